@@ -6,6 +6,10 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
     {
       "AttributeName": "Username",
       "AttributeType": "S"
+    },
+    {
+      "AttributeName": "Email",
+      "AttributeType": "S"
     }
   ],
   "KeySchema": [
@@ -17,6 +21,28 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
   "ProvisionedThroughput": {
     "ReadCapacityUnits": 1,
     "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "Email-index",
+      "KeySchema": [
+        {
+          "AttributeName": "Email",
+          "KeyType": "HASH"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ],
+  "StreamSpecification": {
+    "StreamEnabled": true,
+    "StreamViewType": "NEW_AND_OLD_IMAGES"
   }
 }'
 
@@ -37,6 +63,10 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
   "ProvisionedThroughput": {
     "ReadCapacityUnits": 1,
     "WriteCapacityUnits": 1
+  },
+  "StreamSpecification": {
+    "StreamEnabled": true,
+    "StreamViewType": "NEW_AND_OLD_IMAGES"
   }
 }'
 
@@ -70,6 +100,10 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
     "ReadCapacityUnits": 1,
     "WriteCapacityUnits": 1
   },
+  "StreamSpecification": {
+    "StreamEnabled": true,
+    "StreamViewType": "NEW_AND_OLD_IMAGES"
+  },
   "GlobalSecondaryIndexes": [
     {
       "IndexName": "BoardID-index",
@@ -93,3 +127,315 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
     }
   ]
 }'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "PK",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "SK",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "GSI1PK",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "GSI1SK",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "GSI2PK",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "GSI2SK",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "PK",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "SK",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "GSI1",
+      "KeySchema": [
+        {
+          "AttributeName": "GSI1PK",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "GSI1SK",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    },
+    {
+      "IndexName": "GSI2",
+      "KeySchema": [
+        {
+          "AttributeName": "GSI2PK",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "GSI2SK",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-history",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "TaskID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "At",
+      "AttributeType": "N"
+    },
+    {
+      "AttributeName": "TeamID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "TaskID",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "At",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "TeamID-index",
+      "KeySchema": [
+        {
+          "AttributeName": "TeamID",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "At",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-trash",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "TeamID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "TeamID",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "ID",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-trash \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-audit",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "TeamID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "At",
+      "AttributeType": "N"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "TeamID",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "At",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-avatar",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "Username",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-idempotency",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-idempotency \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-session",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-session \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-lock",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-lock \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-outbox",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
@@ -0,0 +1,365 @@
+// Command admin is an operator CLI for inspecting and managing users and teams
+// directly through the DynamoDB tables.
+//
+// Usage:
+//
+//	admin list-users
+//	admin disable-user <username>
+//	admin enable-user <username>
+//	admin dump-team <teamID>
+//	admin rebalance-tasks <teamID>
+//	admin set-quota <teamID> <maxBoards> <maxMembers> <maxTasksPerBoard>
+//	admin migrate-single-table
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/joho/godotenv"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/profile"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+const (
+	// envDynamoDBEndpoint is the name of the environment variable used for
+	// pointing the DynamoDB client at a local instance such as dynamodb-local
+	// or LocalStack. When it is set, AWS credentials and region default to
+	// dummy values as local instances do not validate them.
+	envDynamoDBEndpoint = "DYNAMODB_ENDPOINT"
+
+	// envAWSEndpoint is the name of the environment variable used for setting
+	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
+	// on local pointing to the local DynamoDB instance. It is superseded by
+	// envDynamoDBEndpoint and kept for existing .env files.
+	envAWSEndpoint = "AWS_ENDPOINT"
+
+	// envPort is the name of the environment variable used for providing AWS
+	// access key to the DynamoDB client.
+	envAWSAccessKey = "AWS_ACCESS_KEY"
+
+	// envPort is the name of the environment variable used for providing AWS
+	// secret key to the DynamoDB client.
+	envAWSSecretKey = "AWS_SECRET_KEY"
+
+	// envAWSRegion is the name of the environment variable used for determining
+	// the AWS region to connect to for DynamoDB.
+	envAWSRegion = "AWS_REGION"
+)
+
+// usage is printed when the command is run with invalid arguments.
+const usage = `usage:
+  admin list-users                list all users
+  admin disable-user <username>   prevent a user from logging in
+  admin enable-user <username>    allow a disabled user to log in again
+  admin dump-team <teamID>        print a team and its boards as JSON
+  admin rebalance-tasks <teamID>  respread the ranks of a team's tasks
+  admin set-quota <teamID> <maxBoards> <maxMembers> <maxTasksPerBoard>
+                                  override a team's quota, 0 to use default
+  admin migrate-single-table      copy the users, teams, and tasks into the
+                                  single table, can be rerun`
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// parse the subcommand and its arguments
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// load environment variables - .env is optional for the CLI since
+	// operators may export them in their shell instead
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	// default the settings that are not set to those of the environment
+	// profile
+	if _, err := profile.Apply(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	// get environment variables
+	var (
+		awsEndpoint  = os.Getenv(envAWSEndpoint)
+		awsAccessKey = os.Getenv(envAWSAccessKey)
+		awsSecretKey = os.Getenv(envAWSSecretKey)
+		awsRegion    = os.Getenv(envAWSRegion)
+	)
+
+	// prefer the DynamoDB endpoint over the AWS endpoint, and fill in dummy
+	// credentials and region if a local endpoint is set without them
+	if endpoint := os.Getenv(envDynamoDBEndpoint); endpoint != "" {
+		awsEndpoint = endpoint
+	}
+	if awsEndpoint != "" {
+		for _, v := range []*string{&awsAccessKey, &awsSecretKey, &awsRegion} {
+			if *v == "" {
+				*v = "local"
+			}
+		}
+	}
+
+	// check all environment variables were set
+	// - except aws endpoint, which is only set on local
+	errPostfix := "was empty"
+	switch "" {
+	case awsAccessKey:
+		log.Fatal(envAWSAccessKey, errPostfix)
+		os.Exit(1)
+	case awsSecretKey:
+		log.Fatal(envAWSSecretKey, errPostfix)
+		os.Exit(1)
+	case awsRegion:
+		log.Fatal(envAWSRegion, errPostfix)
+		os.Exit(1)
+	}
+
+	// define aws config
+	cfg := aws.Config{
+		Region: awsRegion,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			awsAccessKey, awsSecretKey, "",
+		),
+	}
+	if awsEndpoint != "" {
+		cfg.BaseEndpoint = aws.String(awsEndpoint)
+	}
+
+	// create DynamoDB client from config, storing users, teams, and tasks in a
+	// single table if one is configured
+	dynamoClient := dynamodb.NewFromConfig(cfg)
+	var client db.DynamoClient = dynamoClient
+	if os.Getenv(singletbl.EnvTableName) != "" {
+		client = singletbl.NewClient(dynamoClient)
+	}
+
+	// run the subcommand
+	ctx := context.Background()
+	var err error
+	switch args[0] {
+	case "list-users":
+		err = listUsers(ctx, usertbl.NewLister(client))
+	case "disable-user", "enable-user":
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = setUserDisabled(
+			ctx,
+			usertbl.NewRetriever(client),
+			usertbl.NewUpdater(client),
+			args[1],
+			args[0] == "disable-user",
+		)
+	case "dump-team":
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = dumpTeam(ctx, teamtbl.NewRetriever(client), args[1])
+	case "rebalance-tasks":
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = rebalanceTasks(
+			ctx,
+			tasktbl.NewRetrieverByTeam(client),
+			tasktbl.NewTransactionalUpdater(client),
+			args[1],
+		)
+	case "set-quota":
+		if len(args) != 5 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		var limits [3]int
+		for i, arg := range args[2:] {
+			limits[i], err = strconv.Atoi(arg)
+			if err != nil || limits[i] < 0 {
+				flag.Usage()
+				os.Exit(2)
+			}
+		}
+		err = setQuota(
+			ctx,
+			teamtbl.NewRetriever(client),
+			teamtbl.NewUpdater(client),
+			args[1],
+			quota.Quota{
+				MaxBoards:        limits[0],
+				MaxMembers:       limits[1],
+				MaxTasksPerBoard: limits[2],
+			},
+		)
+	case "migrate-single-table":
+		err = migrateSingleTable(ctx, dynamoClient)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+}
+
+// listUsers prints all users in the user table as a table.
+func listUsers(ctx context.Context, lister db.Lister[[]usertbl.User]) error {
+	users, err := lister.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USERNAME\tTEAM ID\tADMIN\tDISABLED")
+	for _, u := range users {
+		fmt.Fprintf(w,
+			"%s\t%s\t%t\t%t\n", u.Username, u.TeamID, u.IsAdmin, u.IsDisabled,
+		)
+	}
+	return w.Flush()
+}
+
+// setUserDisabled sets whether the user with the given username is disabled,
+// revoking all of their sessions if so.
+func setUserDisabled(
+	ctx context.Context,
+	retriever db.Retriever[usertbl.User],
+	updater db.Updater[usertbl.User],
+	username string,
+	isDisabled bool,
+) error {
+	user, err := retriever.Retrieve(ctx, username)
+	if errors.Is(err, db.ErrNoItem) {
+		return fmt.Errorf("user %q not found", username)
+	} else if err != nil {
+		return err
+	}
+
+	user.IsDisabled = isDisabled
+	if isDisabled {
+		user.Sessions = nil
+	}
+	if err = updater.Update(ctx, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("user %q disabled: %t\n", username, isDisabled)
+	return nil
+}
+
+// dumpTeam prints the team with the given ID and its boards as JSON.
+func dumpTeam(
+	ctx context.Context, retriever db.Retriever[teamtbl.Team], teamID string,
+) error {
+	team, err := retriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return fmt.Errorf("team %q not found", teamID)
+	} else if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(team)
+}
+
+// rebalanceTasks respreads the ranks of the tasks in each column of the team
+// with the given ID evenly, keeping their order. It also assigns ranks to tasks
+// created before ranks were introduced.
+func rebalanceTasks(
+	ctx context.Context,
+	retriever db.Retriever[[]tasktbl.Task],
+	updater db.Updater[[]tasktbl.Task],
+	teamID string,
+) error {
+	tasks, err := retriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) || len(tasks) == 0 {
+		return fmt.Errorf("no tasks found for team %q", teamID)
+	} else if err != nil {
+		return err
+	}
+
+	// sort the tasks so that each column's tasks are adjacent and in order,
+	// then rebalance one column at a time
+	tasktbl.SortByRank(tasks)
+	start := 0
+	for i := range tasks {
+		if i == len(tasks)-1 ||
+			tasks[i+1].BoardID != tasks[i].BoardID ||
+			tasks[i+1].ColNo != tasks[i].ColNo {
+			tasktbl.Rebalance(tasks[start : i+1])
+			start = i + 1
+		}
+	}
+
+	if err = updater.Update(ctx, tasks); err != nil {
+		return err
+	}
+
+	fmt.Printf("rebalanced %d tasks of team %q\n", len(tasks), teamID)
+	return nil
+}
+
+// setQuota sets the quota override of the team with the given ID. Limits that
+// are zero fall back to the defaults the services are configured with.
+func setQuota(
+	ctx context.Context,
+	retriever db.Retriever[teamtbl.Team],
+	updater db.Updater[teamtbl.Team],
+	teamID string,
+	q quota.Quota,
+) error {
+	team, err := retriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return fmt.Errorf("team %q not found", teamID)
+	} else if err != nil {
+		return err
+	}
+
+	team.Quota = q
+	if err = updater.Update(ctx, team); err != nil {
+		return err
+	}
+
+	fmt.Printf("team %q quota: %+v\n", teamID, q)
+	return nil
+}
+
+// migrateSingleTable copies the users, teams, and tasks from their tables into
+// the single table. The client must be the one the tables are accessed through
+// directly, so that they are scanned rather than the single table.
+func migrateSingleTable(ctx context.Context, client db.DynamoClient) error {
+	n, err := singletbl.Migrate(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("copied %d items into the single table\n", n)
+	return nil
+}
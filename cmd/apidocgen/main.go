@@ -0,0 +1,31 @@
+// Command apidocgen generates the OpenAPI document for all routes and writes
+// it to the given file. It is run by go generate in internal/apidoc.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/kxplxn/goteam/internal/apidoc"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// parse the output file path
+	out := flag.String("o", "openapi.json", "output file path")
+	flag.Parse()
+
+	// generate the document and write it to the output file
+	spec, err := apidoc.Generate()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatal(err)
+		return
+	}
+}
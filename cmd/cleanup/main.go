@@ -0,0 +1,166 @@
+// Command cleanup purges the data that is kept past its use: trash items past
+// their retention that DynamoDB has not deleted yet, expired invites and
+// sessions, and tasks whose board no longer exists. It is meant to be run
+// periodically, e.g. daily from cron.
+//
+// Usage:
+//
+//	cleanup [-dry-run]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/joho/godotenv"
+
+	"github.com/kxplxn/goteam/internal/cleanup"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/profile"
+)
+
+const (
+	// envDynamoDBEndpoint is the name of the environment variable used for
+	// pointing the DynamoDB client at a local instance such as dynamodb-local
+	// or LocalStack. When it is set, AWS credentials and region default to
+	// dummy values as local instances do not validate them.
+	envDynamoDBEndpoint = "DYNAMODB_ENDPOINT"
+
+	// envAWSEndpoint is the name of the environment variable used for setting
+	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
+	// on local pointing to the local DynamoDB instance. It is superseded by
+	// envDynamoDBEndpoint and kept for existing .env files.
+	envAWSEndpoint = "AWS_ENDPOINT"
+
+	// envAWSAccessKey is the name of the environment variable used for
+	// providing AWS access key to the DynamoDB client.
+	envAWSAccessKey = "AWS_ACCESS_KEY"
+
+	// envAWSSecretKey is the name of the environment variable used for
+	// providing AWS secret key to the DynamoDB client.
+	envAWSSecretKey = "AWS_SECRET_KEY"
+
+	// envAWSRegion is the name of the environment variable used for determining
+	// the AWS region to connect to for DynamoDB.
+	envAWSRegion = "AWS_REGION"
+)
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// parse the flags
+	dryRun := flag.Bool(
+		"dry-run", false, "only log what would be purged without purging it",
+	)
+	flag.Parse()
+
+	// load environment variables - .env is optional for the CLI since
+	// operators may export them in their shell instead
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	// default the settings that are not set to those of the environment
+	// profile
+	if _, err := profile.Apply(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	// get environment variables
+	var (
+		awsEndpoint  = os.Getenv(envAWSEndpoint)
+		awsAccessKey = os.Getenv(envAWSAccessKey)
+		awsSecretKey = os.Getenv(envAWSSecretKey)
+		awsRegion    = os.Getenv(envAWSRegion)
+	)
+
+	// prefer the DynamoDB endpoint over the AWS endpoint, and fill in dummy
+	// credentials and region if a local endpoint is set without them
+	if endpoint := os.Getenv(envDynamoDBEndpoint); endpoint != "" {
+		awsEndpoint = endpoint
+	}
+	if awsEndpoint != "" {
+		for _, v := range []*string{&awsAccessKey, &awsSecretKey, &awsRegion} {
+			if *v == "" {
+				*v = "local"
+			}
+		}
+	}
+
+	// check all environment variables were set
+	// - except aws endpoint, which is only set on local
+	errPostfix := "was empty"
+	switch "" {
+	case awsAccessKey:
+		log.Fatal(envAWSAccessKey, errPostfix)
+		os.Exit(1)
+	case awsSecretKey:
+		log.Fatal(envAWSSecretKey, errPostfix)
+		os.Exit(1)
+	case awsRegion:
+		log.Fatal(envAWSRegion, errPostfix)
+		os.Exit(1)
+	}
+
+	// define aws config
+	cfg := aws.Config{
+		Region: awsRegion,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			awsAccessKey, awsSecretKey, "",
+		),
+	}
+	if awsEndpoint != "" {
+		cfg.BaseEndpoint = aws.String(awsEndpoint)
+	}
+
+	// create DynamoDB client from config, storing users, teams, and tasks in a
+	// single table if one is configured
+	var client db.DynamoClient = dynamodb.NewFromConfig(cfg)
+	if os.Getenv(singletbl.EnvTableName) != "" {
+		client = singletbl.NewClient(client)
+	}
+
+	// purge the items that are kept past their use
+	rep, err := cleanup.NewJob(
+		clock.System{},
+		usertbl.NewLister(client),
+		usertbl.NewUpdater(client),
+		teamtbl.NewRetriever(client),
+		teamtbl.NewUpdater(client),
+		trashtbl.NewRetrieverByTeam(client),
+		trashtbl.NewExpiredRetrieverByTeam(client),
+		trashtbl.NewDeleter(client),
+		tasktbl.NewRetrieverByTeam(client),
+		tasktbl.NewDeleter(client),
+		log,
+	).Run(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	verb := "purged"
+	if *dryRun {
+		verb = "would purge"
+	}
+	fmt.Printf(
+		"%s %d trash items, %d orphaned tasks, %d invites, %d sessions\n",
+		verb, rep.TrashItems, rep.Tasks, rep.Invites, rep.Sessions,
+	)
+}
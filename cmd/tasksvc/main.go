@@ -1,20 +1,42 @@
 package main
 
 import (
+	"flag"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/joho/godotenv"
 
+	"github.com/kxplxn/goteam/internal/apidoc"
+	"github.com/kxplxn/goteam/internal/tasksvc/historyapi"
 	"github.com/kxplxn/goteam/internal/tasksvc/taskapi"
 	"github.com/kxplxn/goteam/internal/tasksvc/tasksapi"
 	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/breaker"
+	"github.com/kxplxn/goteam/pkg/db/cache"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
+	"github.com/kxplxn/goteam/pkg/db/retry"
+	"github.com/kxplxn/goteam/pkg/db/sesstbl"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/openapi"
+	"github.com/kxplxn/goteam/pkg/profile"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/report"
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 const (
@@ -22,9 +44,16 @@ const (
 	// to run the task service on.
 	envPort = "TASK_SERVICE_PORT"
 
+	// envDynamoDBEndpoint is the name of the environment variable used for
+	// pointing the DynamoDB client at a local instance such as dynamodb-local
+	// or LocalStack. When it is set, AWS credentials and region default to
+	// dummy values as local instances do not validate them.
+	envDynamoDBEndpoint = "DYNAMODB_ENDPOINT"
+
 	// envAWSEndpoint is the name of the environment variable used for setting
 	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
-	// on local pointing to the local DynamoDB instance.
+	// on local pointing to the local DynamoDB instance. It is superseded by
+	// envDynamoDBEndpoint and kept for existing .env files.
 	envAWSEndpoint = "AWS_ENDPOINT"
 
 	// envPort is the name of the environment variable used for providing AWS
@@ -39,18 +68,30 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
-	envJWTKey = "JWT_KEY"
-
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
 	envClientOrigin = "CLIENT_ORIGIN"
+
+	// envTeamCacheTTL is the name of the environment variable used for setting
+	// how long retrieved teams are cached in memory for (e.g. "30s"). Teams are
+	// retrieved to validate that the boards tasks are written to belong to the
+	// user's team. They are not cached if it is empty.
+	envTeamCacheTTL = "TEAM_CACHE_TTL"
+
+	// teamCacheSize is the maximum number of teams cached in memory.
+	teamCacheSize = 1000
 )
 
 func main() {
 	// create a logger
 	log := log.New()
 
+	// parse flags
+	demo := flag.Bool(
+		"demo", false, "serve seeded sample data from memory instead of DynamoDB",
+	)
+	flag.Parse()
+
 	// load environment variables
 	err := godotenv.Load()
 	if err != nil {
@@ -58,6 +99,13 @@ func main() {
 		return
 	}
 
+	// default the settings that are not set to those of the environment
+	// profile
+	if _, err := profile.Apply(); err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	// get environment variables
 	var (
 		port         = os.Getenv(envPort)
@@ -65,10 +113,23 @@ func main() {
 		awsAccessKey = os.Getenv(envAWSAccessKey)
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
-		jwtKey       = os.Getenv(envJWTKey)
 		clientOrigin = os.Getenv(envClientOrigin)
+		teamCacheTTL = os.Getenv(envTeamCacheTTL)
 	)
 
+	// prefer the DynamoDB endpoint over the AWS endpoint, and fill in dummy
+	// credentials and region if a local endpoint is set without them
+	if endpoint := os.Getenv(envDynamoDBEndpoint); endpoint != "" {
+		awsEndpoint = endpoint
+	}
+	if awsEndpoint != "" {
+		for _, v := range []*string{&awsAccessKey, &awsSecretKey, &awsRegion} {
+			if *v == "" {
+				*v = "local"
+			}
+		}
+	}
+
 	// check all environment variables were set
 	// - except aws endpoint, which is only set on local
 	errPostfix := "was empty"
@@ -76,84 +137,449 @@ func main() {
 	case port:
 		log.Fatal(envPort, errPostfix)
 		return
-	case awsAccessKey:
-		log.Fatal(envAWSAccessKey, errPostfix)
+	case clientOrigin:
+		log.Fatal(envClientOrigin, errPostfix)
 		return
-	case awsSecretKey:
-		log.Fatal(envAWSSecretKey, errPostfix)
+	}
+
+	// load the keys that JWTs are signed and verified with
+	jwtKeys, err := cookie.KeysFromEnv()
+	if err != nil {
+		log.Fatal(err)
 		return
-	case awsRegion:
-		log.Fatal(envAWSRegion, errPostfix)
+	}
+
+	// load the key that pagination cursors are signed with, which falls back
+	// to the JWT key unless JWTs are signed with an asymmetric key
+	cursorSigner, err := api.CursorSignerFromEnv(jwtKeys.Current.Secret)
+	if err != nil {
+		log.Fatal(err)
 		return
-	case jwtKey:
-		log.Fatal(envJWTKey, errPostfix)
+	}
+
+	// report the errors that are logged to the error tracker if one is
+	// configured - panics are reported by the recovery middleware with the
+	// context of their request, so it logs them through consoleLog instead
+	reporter, err := report.FromEnv(
+		clock.System{}, &http.Client{Timeout: 10 * time.Second},
+	)
+	if err != nil {
+		log.Error(err)
 		return
-	case clientOrigin:
-		log.Fatal(envClientOrigin, errPostfix)
+	}
+	consoleLog := log
+	log = log.ReportTo(reporter)
+
+	// load the limits applied to the server's connections
+	serverConfig, err := api.ServerConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
 		return
 	}
 
-	// define aws config
-	cfg := aws.Config{
-		Region: awsRegion,
-		Credentials: credentials.NewStaticCredentialsProvider(
-			awsAccessKey, awsSecretKey, "",
-		),
+	// load the attributes of the cookies set on clients
+	cookieConfig, err := cookie.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+		return
 	}
-	if awsEndpoint != "" {
-		cfg.BaseEndpoint = aws.String(awsEndpoint)
+	// sessions are stored by the user service, whose in-memory store is not
+	// shared in demo mode
+	if *demo && cookieConfig.AuthMode == cookie.AuthModeSession {
+		log.Fatal(cookie.EnvAuthMode, "cannot be session in demo mode")
+		return
 	}
 
-	// create DynamoDB client from config
-	db := dynamodb.NewFromConfig(cfg)
+	// load the default quota teams are subject to unless overridden
+	defaultQuota, err := quota.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// create the circuit breaker shared by the DynamoDB accessors so that
+	// requests fail fast while the database is down
+	dbBreaker, err := breaker.FromEnv(clock.System{})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// create table accessors - in-memory with seeded sample data in demo mode,
+	// otherwise backed by DynamoDB
+	var (
+		teamRetriever db.Retriever[teamtbl.Team]
+		userRetriever db.Retriever[usertbl.User]
+		taskRetriever db.RetrieverDualKey[tasktbl.Task]
+		taskInserter  db.Inserter[tasktbl.Task]
+		taskUpdater   db.Updater[tasktbl.Task]
+		taskDeleter   db.DeleterDualKey
+		tasksUpdater  db.Updater[[]tasktbl.Task]
+		tasksByBoard  db.Retriever[[]tasktbl.Task]
+		taskPages     db.PageRetriever[[]tasktbl.Task]
+		tasksByTeam   db.Retriever[[]tasktbl.Task]
+		histInserter  db.Inserter[histtbl.Entry]
+		histRetriever db.Retriever[[]histtbl.Entry]
+		trashInserter db.Inserter[trashtbl.Item]
+		trashDeleter  db.DeleterDualKey
+		idemStore     api.IdempotencyStore
+		sessStore     cookie.SessionStore
+	)
+	if *demo {
+		store, err := memdb.NewDemoStore()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		teamRetriever = memdb.NewTeamRetriever(store)
+		userRetriever = memdb.NewUserRetriever(store)
+		taskRetriever = memdb.NewTaskRetriever(store)
+		taskInserter = memdb.NewTaskInserter(store)
+		taskUpdater = memdb.NewTaskUpdater(store)
+		taskDeleter = memdb.NewTaskDeleter(store)
+		tasksUpdater = memdb.NewTaskTransactionalUpdater(store)
+		tasksByBoard = memdb.NewTaskRetrieverByBoard(store)
+		taskPages = memdb.NewTaskRetrieverByBoard(store)
+		tasksByTeam = memdb.NewTaskRetrieverByTeam(store)
+		histInserter = memdb.NewHistoryInserter(store)
+		histRetriever = memdb.NewHistoryRetriever(store)
+		trashInserter = memdb.NewTrashInserter(store)
+		trashDeleter = memdb.NewTrashDeleter(store)
+		idemStore = api.IdempotencyStore{
+			Inserter:  memdb.NewRecordInserter(store),
+			Retriever: memdb.NewRecordRetriever(store),
+			Updater:   memdb.NewRecordUpdater(store),
+			Deleter:   memdb.NewRecordDeleter(store),
+		}
+		log.Info(
+			"running in demo mode - log in as", memdb.DemoUsername,
+			"with password", memdb.DemoPassword,
+		)
+	} else {
+		// check aws environment variables were set
+		// - except aws endpoint, which is only set on local
+		switch "" {
+		case awsAccessKey:
+			log.Fatal(envAWSAccessKey, errPostfix)
+			return
+		case awsSecretKey:
+			log.Fatal(envAWSSecretKey, errPostfix)
+			return
+		case awsRegion:
+			log.Fatal(envAWSRegion, errPostfix)
+			return
+		}
 
-	// create auth decoder to be used by API handlers
-	authDecoder := cookie.NewAuthDecoder([]byte(jwtKey))
+		// define aws config
+		cfg := aws.Config{
+			Region: awsRegion,
+			Credentials: credentials.NewStaticCredentialsProvider(
+				awsAccessKey, awsSecretKey, "",
+			),
+		}
+		if awsEndpoint != "" {
+			cfg.BaseEndpoint = aws.String(awsEndpoint)
+		}
+
+		// create DynamoDB client from config, storing users, teams, and tasks
+		// in a single table if one is configured
+		var client db.DynamoClient = dynamodb.NewFromConfig(cfg)
+		if os.Getenv(singletbl.EnvTableName) != "" {
+			client = singletbl.NewClient(client)
+		}
+		teamRetriever = teamtbl.NewRetriever(client)
+		userRetriever = usertbl.NewRetriever(client)
+		taskRetriever = tasktbl.NewRetriever(client)
+		taskInserter = tasktbl.NewInserter(client)
+		taskUpdater = tasktbl.NewUpdater(client)
+		taskDeleter = tasktbl.NewDeleter(client)
+		tasksUpdater = tasktbl.NewTransactionalUpdater(client)
+		tasksByBoard = tasktbl.NewRetrieverByBoard(client)
+		taskPages = tasktbl.NewRetrieverByBoard(client)
+		tasksByTeam = tasktbl.NewRetrieverByTeam(client)
+		histInserter = histtbl.NewInserter(client)
+		histRetriever = histtbl.NewRetriever(client)
+		trashInserter = trashtbl.NewInserter(client)
+		trashDeleter = trashtbl.NewDeleter(client)
+		idemStore = api.IdempotencyStore{
+			Inserter:  idemtbl.NewInserter(client),
+			Retriever: idemtbl.NewRetriever(client),
+			Updater:   idemtbl.NewUpdater(client),
+			Deleter:   idemtbl.NewDeleter(client),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  sesstbl.NewInserter(client),
+			Retriever: sesstbl.NewRetriever(client),
+			Updater:   sesstbl.NewUpdater(client),
+		}
+
+		// retry the calls to DynamoDB that are throttled instead of failing
+		// the request
+		backoff, err := retry.BackoffFromEnv()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		teamRetriever = retry.NewRetriever(teamRetriever, backoff)
+		userRetriever = retry.NewRetriever(userRetriever, backoff)
+		taskRetriever = retry.NewRetrieverDualKey(taskRetriever, backoff)
+		taskInserter = retry.NewInserter(taskInserter, backoff)
+		taskUpdater = retry.NewUpdater(taskUpdater, backoff)
+		taskDeleter = retry.NewDeleterDualKey(taskDeleter, backoff)
+		tasksUpdater = retry.NewUpdater(tasksUpdater, backoff)
+		tasksByBoard = retry.NewRetriever(tasksByBoard, backoff)
+		taskPages = retry.NewPageRetriever(taskPages, backoff)
+		tasksByTeam = retry.NewRetriever(tasksByTeam, backoff)
+		histInserter = retry.NewInserter(histInserter, backoff)
+		histRetriever = retry.NewRetriever(histRetriever, backoff)
+		trashInserter = retry.NewInserter(trashInserter, backoff)
+		trashDeleter = retry.NewDeleterDualKey(trashDeleter, backoff)
+		idemStore = api.IdempotencyStore{
+			Inserter:  retry.NewInserter(idemStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(idemStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(idemStore.Updater, backoff),
+			Deleter:   retry.NewDeleter(idemStore.Deleter, backoff),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  retry.NewInserter(sessStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(sessStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(sessStore.Updater, backoff),
+		}
+
+		// stop calling DynamoDB while it keeps failing - this wraps the
+		// retries so that a call counts as a single failure however many
+		// times it was attempted
+		teamRetriever = breaker.NewRetriever(teamRetriever, dbBreaker)
+		userRetriever = breaker.NewRetriever(userRetriever, dbBreaker)
+		taskRetriever = breaker.NewRetrieverDualKey(taskRetriever, dbBreaker)
+		taskInserter = breaker.NewInserter(taskInserter, dbBreaker)
+		taskUpdater = breaker.NewUpdater(taskUpdater, dbBreaker)
+		taskDeleter = breaker.NewDeleterDualKey(taskDeleter, dbBreaker)
+		tasksUpdater = breaker.NewUpdater(tasksUpdater, dbBreaker)
+		tasksByBoard = breaker.NewRetriever(tasksByBoard, dbBreaker)
+		taskPages = breaker.NewPageRetriever(taskPages, dbBreaker)
+		tasksByTeam = breaker.NewRetriever(tasksByTeam, dbBreaker)
+		histInserter = breaker.NewInserter(histInserter, dbBreaker)
+		histRetriever = breaker.NewRetriever(histRetriever, dbBreaker)
+		trashInserter = breaker.NewInserter(trashInserter, dbBreaker)
+		trashDeleter = breaker.NewDeleterDualKey(trashDeleter, dbBreaker)
+		idemStore = api.IdempotencyStore{
+			Inserter:  breaker.NewInserter(idemStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(idemStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(idemStore.Updater, dbBreaker),
+			Deleter:   breaker.NewDeleter(idemStore.Deleter, dbBreaker),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  breaker.NewInserter(sessStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(sessStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(sessStore.Updater, dbBreaker),
+		}
+	}
+
+	// cache retrieved teams in memory if a TTL is set - boards never move
+	// between teams, so a cached team can only be stale by the boards created
+	// or deleted since it was retrieved
+	if teamCacheTTL != "" {
+		ttl, err := time.ParseDuration(teamCacheTTL)
+		if err != nil {
+			log.Fatal(envTeamCacheTTL, "was invalid:", err)
+			return
+		}
+		teamRetriever = cache.NewRetriever(
+			teamRetriever, ttl, teamCacheSize, teamtbl.Team.Clone,
+		)
+	}
+
+	// create auth decoder to be used for authenticating users on all routes
+	var authDecoder cookie.Decoder[cookie.Auth] = cookie.NewAuthDecoder(
+		jwtKeys, clock.System{},
+	)
+	// decode the auth tokens that carry the tokens of server-side sessions in
+	// session mode
+	if cookieConfig.AuthMode == cookie.AuthModeSession {
+		authDecoder = cookie.NewStoredAuthDecoder(
+			authDecoder, sessStore, cookieConfig, clock.System{},
+		)
+	}
+	// reject the auth tokens whose session was revoked or whose user is
+	// disabled - users are stored by the user service, whose in-memory store
+	// is not shared in demo mode
+	if !*demo {
+		authDecoder = cookie.NewSessionDecoder(
+			authDecoder, userRetriever, clock.System{},
+		)
+	}
 
 	// register handlers for HTTP routes
-	mux := http.NewServeMux()
+	mux := api.NewRouter()
 
-	taskTitleValidator := taskapi.NewTitleValidator()
-	mux.Handle("/task", api.NewHandler(map[string]api.MethodHandler{
-		http.MethodPost: taskapi.NewPostHandler(
-			authDecoder,
+	var (
+		taskPostHandler = api.Authed(authDecoder, log, taskapi.NewPostHandler(
 			taskapi.ValidatePostReq,
-			tasktbl.NewInserter(db),
+			defaultQuota,
+			teamRetriever,
+			tasksByBoard,
+			taskInserter,
+			histInserter,
 			log,
-		),
-		http.MethodPatch: taskapi.NewPatchHandler(
-			authDecoder,
-			taskTitleValidator,
-			taskTitleValidator,
-			tasktbl.NewUpdater(db),
+		))
+		taskPatchHandler = api.Authed(authDecoder, log, taskapi.NewPatchHandler(
+			validator.TaskTitle,
+			validator.TaskDesc,
+			validator.SubtaskTitle,
+			teamRetriever,
+			taskRetriever,
+			tasksByBoard,
+			taskUpdater,
+			histInserter,
 			log,
-		),
-		http.MethodDelete: taskapi.NewDeleteHandler(
-			authDecoder,
-			tasktbl.NewDeleter(db),
+		))
+		taskDeleteHandler = api.Authed(
+			authDecoder, log, taskapi.NewDeleteHandler(
+				taskRetriever,
+				trashInserter,
+				trashDeleter,
+				taskDeleter,
+				log,
+			),
+		)
+		tasksPatchHandler = api.Authed(
+			authDecoder, log, tasksapi.NewPatchHandler(
+				validator.ColNo,
+				teamRetriever,
+				tasksByBoard,
+				tasksUpdater,
+				histInserter,
+				log,
+			),
+		)
+		taskMoveHandler = api.Authed(authDecoder, log, taskapi.NewMoveHandler(
+			validator.ColNo,
+			taskRetriever,
+			tasksByBoard,
+			teamRetriever,
+			tasksUpdater,
+			histInserter,
 			log,
-		),
-	}))
+		))
+		taskBlockersHandler = api.Authed(
+			authDecoder, log, taskapi.NewBlockersHandler(
+				taskRetriever, tasksByBoard, taskUpdater, log,
+			),
+		)
+		taskChecklistHandler = api.Authed(
+			authDecoder, log, taskapi.NewChecklistHandler(
+				taskRetriever, teamRetriever, taskUpdater, histInserter, log,
+			),
+		)
+		taskDuplicateHandler = api.Authed(
+			authDecoder, log, taskapi.NewDuplicateHandler(
+				validator.ColNo,
+				defaultQuota,
+				taskRetriever,
+				teamRetriever,
+				tasksByBoard,
+				taskInserter,
+				histInserter,
+				log,
+			),
+		)
+		tasksGetHandler = api.Authed(authDecoder, log, tasksapi.NewGetHandler(
+			validator.ID,
+			validator.ColNo,
+			tasksByBoard,
+			taskPages,
+			tasksByTeam,
+			teamRetriever,
+			cursorSigner,
+			log,
+		))
+		historyGetHandler = api.Authed(
+			authDecoder, log, historyapi.NewGetHandler(
+				histRetriever,
+				log,
+			),
+		)
+	)
 
+	// make the unsafe requests sent with an Idempotency-Key header only be
+	// handled once
+	idempotent := api.Idempotent(idemStore, log)
+
+	// PATCH is kept for the clients that compute the order of whole columns
+	// themselves until they move tasks with /tasks/{taskID}/move instead
 	mux.Handle("/tasks", api.NewHandler(map[string]api.MethodHandler{
-		http.MethodPatch: tasksapi.NewPatchHandler(
-			authDecoder,
-			tasksapi.NewColNoValidator(),
-			tasktbl.NewMultiUpdater(db),
-			log,
-		),
-		http.MethodGet: tasksapi.NewGetHandler(
-			tasksapi.NewBoardIDValidator(),
-			tasktbl.NewRetrieverByBoard(db),
-			authDecoder,
-			tasktbl.NewRetrieverByTeam(db),
-			log,
-		),
+		http.MethodPatch: tasksPatchHandler,
+		http.MethodGet:   tasksGetHandler,
+	}).Use(api.Compress, api.ETag))
+
+	mux.Handle("/tasks/{taskID}", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPatch:  taskPatchHandler,
+		http.MethodDelete: taskDeleteHandler,
 	}))
 
-	// serve the registered routes
+	mux.Handle("/tasks/{taskID}/move", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodPost: taskMoveHandler},
+	))
+
+	mux.Handle("/tasks/{taskID}/blockers", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodPut: taskBlockersHandler},
+	))
+
+	mux.Handle("/tasks/{taskID}/checklist", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodPost: taskChecklistHandler},
+	).Use(idempotent))
+
+	mux.Handle("/tasks/{taskID}/duplicate", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodPost: taskDuplicateHandler},
+	).Use(idempotent))
+
+	mux.Handle("/tasks/{taskID}/history", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodGet: historyGetHandler},
+	).Use(api.ETag))
+
+	mux.Handle("/boards/{boardID}/tasks", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet:  tasksGetHandler,
+			http.MethodPost: taskPostHandler,
+		},
+	).Use(api.Compress, api.ETag, idempotent))
+
+	// deprecated - kept as an alias for /tasks/{taskID} and
+	// /boards/{boardID}/tasks for one release to give clients time to migrate
+	mux.Handle("/task", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost:   taskPostHandler,
+		http.MethodPatch:  taskPatchHandler,
+		http.MethodDelete: taskDeleteHandler,
+	}).Use(api.Deprecated, idempotent))
+
+	// deprecated - kept as an alias for /tasks/{taskID}/history alongside the
+	// /task route
+	mux.Handle("/task/history", api.NewHandler(
+		map[string]api.MethodHandler{http.MethodGet: historyGetHandler},
+	).Use(api.Deprecated))
+
+	// translate the error messages of the registered routes into the client's
+	// preferred language, reject cross-site requests to them, and fail fast on
+	// them while the database is down
+	root := http.NewServeMux()
+	root.Handle("/", api.Chain(
+		api.Localize, api.CSRF(clientOrigin), api.FailFast(dbBreaker),
+	)(mux))
+
+	// serve the API documentation
+	root.Handle("/openapi.json", openapi.NewSpecHandler(apidoc.Spec))
+	root.Handle("/docs", openapi.NewDocsHandler("/openapi.json"))
+
+	// serve the registered routes, logging a line for each request and
+	// recovering from the panics in handling them
 	log.Info("running task service on port", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := serverConfig.NewServer(
+		":"+port, api.Chain(
+			api.AccessLog(clock.System{}, log),
+			api.Recover(reporter, consoleLog),
+		)(root),
+	).ListenAndServe(); err != nil {
 		log.Fatal(err)
 		return
 	}
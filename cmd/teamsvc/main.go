@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -8,14 +12,62 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 
+	"github.com/kxplxn/goteam/internal/apidoc"
+	"github.com/kxplxn/goteam/internal/archive"
+	"github.com/kxplxn/goteam/internal/cleanup"
+	"github.com/kxplxn/goteam/internal/jobs"
+	"github.com/kxplxn/goteam/internal/teamsvc/analyticsapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/auditapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/billing"
+	"github.com/kxplxn/goteam/internal/teamsvc/billingapi"
 	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/checklistapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/graphqlapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/inviteapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/membersapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/publicapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/scimtokenapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/searchapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/shareapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/slackapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/sprintapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/ssoapi"
 	"github.com/kxplxn/goteam/internal/teamsvc/teamapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/trashapi"
 	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/breaker"
+	"github.com/kxplxn/goteam/pkg/db/cache"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/db/locktbl"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/db/retry"
+	"github.com/kxplxn/goteam/pkg/db/sesstbl"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/stream"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/featureflag"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+	"github.com/kxplxn/goteam/pkg/notify"
+	"github.com/kxplxn/goteam/pkg/openapi"
+	"github.com/kxplxn/goteam/pkg/outbox"
+	"github.com/kxplxn/goteam/pkg/profile"
+	"github.com/kxplxn/goteam/pkg/queue"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/report"
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 const (
@@ -23,9 +75,22 @@ const (
 	// to run the team service on.
 	envPort = "TEAM_SERVICE_PORT"
 
+	// envDebugAddr is the name of the environment variable used for setting
+	// the address to serve the runtime and cache metrics on, which must not
+	// be reachable from the public network. They are not served if it is
+	// empty.
+	envDebugAddr = "TEAM_SERVICE_DEBUG_ADDR"
+
+	// envDynamoDBEndpoint is the name of the environment variable used for
+	// pointing the DynamoDB client at a local instance such as dynamodb-local
+	// or LocalStack. When it is set, AWS credentials and region default to
+	// dummy values as local instances do not validate them.
+	envDynamoDBEndpoint = "DYNAMODB_ENDPOINT"
+
 	// envAWSEndpoint is the name of the environment variable used for setting
 	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
-	// on local pointing to the local DynamoDB instance.
+	// on local pointing to the local DynamoDB instance. It is superseded by
+	// envDynamoDBEndpoint and kept for existing .env files.
 	envAWSEndpoint = "AWS_ENDPOINT"
 
 	// envPort is the name of the environment variable used for providing AWS
@@ -40,18 +105,65 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
-	envJWTKey = "JWT_KEY"
-
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
 	envClientOrigin = "CLIENT_ORIGIN"
+
+	// envTeamCacheTTL is the name of the environment variable used for setting
+	// how long retrieved teams are cached in memory for (e.g. "30s"). Teams are
+	// not cached if it is empty.
+	envTeamCacheTTL = "TEAM_CACHE_TTL"
+
+	// envSMTPAddr is the name of the environment variable used for setting the
+	// address of the SMTP server that invite emails are sent through (e.g.
+	// "smtp.example.com:587"). Invite emails are logged instead if it is empty.
+	envSMTPAddr = "SMTP_ADDR"
+
+	// envSMTPFrom is the name of the environment variable used for setting the
+	// address invite emails are sent from.
+	envSMTPFrom = "SMTP_FROM"
+
+	// envSMTPUsername is the name of the environment variable used for
+	// authenticating with the SMTP server.
+	envSMTPUsername = "SMTP_USERNAME"
+
+	// envSMTPPassword is the name of the environment variable used for
+	// authenticating with the SMTP server.
+	envSMTPPassword = "SMTP_PASSWORD"
+
+	// envStripeSecretKey is the name of the environment variable used for
+	// authenticating with the Stripe API. The billing routes are not served if
+	// it is empty.
+	envStripeSecretKey = "STRIPE_SECRET_KEY"
+
+	// envStripePriceID is the name of the environment variable used for setting
+	// the ID of the Stripe price that teams subscribe to for the paid plan.
+	envStripePriceID = "STRIPE_PRICE_ID"
+
+	// envStripeWebhookSecret is the name of the environment variable used for
+	// verifying the signatures of the webhook events sent by Stripe.
+	envStripeWebhookSecret = "STRIPE_WEBHOOK_SECRET"
+
+	// envTeamStreamARN is the name of the environment variable used for setting
+	// the ARN of the stream on the team table, or on the single table, that
+	// the cached teams are invalidated on the changes of. Only the writes made
+	// through each instance invalidate its cache if it is empty.
+	envTeamStreamARN = "TEAM_STREAM_ARN"
+
+	// teamCacheSize is the maximum number of teams cached in memory.
+	teamCacheSize = 1000
 )
 
 func main() {
 	// create a logger
 	log := log.New()
 
+	// parse flags
+	demo := flag.Bool(
+		"demo", false, "serve seeded sample data from memory instead of DynamoDB",
+	)
+	flag.Parse()
+
 	// load environment variables
 	err := godotenv.Load()
 	if err != nil {
@@ -59,6 +171,13 @@ func main() {
 		return
 	}
 
+	// default the settings that are not set to those of the environment
+	// profile
+	if _, err := profile.Apply(); err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	// get environment variables
 	var (
 		port         = os.Getenv(envPort)
@@ -66,10 +185,25 @@ func main() {
 		awsAccessKey = os.Getenv(envAWSAccessKey)
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
-		jwtKey       = os.Getenv(envJWTKey)
 		clientOrigin = os.Getenv(envClientOrigin)
+		teamCacheTTL = os.Getenv(envTeamCacheTTL)
+		smtpAddr     = os.Getenv(envSMTPAddr)
+		smtpFrom     = os.Getenv(envSMTPFrom)
 	)
 
+	// prefer the DynamoDB endpoint over the AWS endpoint, and fill in dummy
+	// credentials and region if a local endpoint is set without them
+	if endpoint := os.Getenv(envDynamoDBEndpoint); endpoint != "" {
+		awsEndpoint = endpoint
+	}
+	if awsEndpoint != "" {
+		for _, v := range []*string{&awsAccessKey, &awsSecretKey, &awsRegion} {
+			if *v == "" {
+				*v = "local"
+			}
+		}
+	}
+
 	// check all environment variables were set
 	// - except aws endpoint, which is only set on local
 	errPostfix := "was empty"
@@ -77,78 +211,923 @@ func main() {
 	case port:
 		log.Error(envPort, errPostfix)
 		return
-	case awsAccessKey:
-		log.Fatal(envAWSAccessKey, errPostfix)
+	case clientOrigin:
+		log.Error(envClientOrigin, errPostfix)
+		return
+	}
+
+	// load the keys that JWTs are signed and verified with
+	jwtKeys, err := cookie.KeysFromEnv()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// load the key that pagination cursors are signed with, which falls back
+	// to the JWT key unless JWTs are signed with an asymmetric key
+	cursorSigner, err := api.CursorSignerFromEnv(jwtKeys.Current.Secret)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// report the errors that are logged to the error tracker if one is
+	// configured - panics are reported by the recovery middleware with the
+	// context of their request, so it logs them through consoleLog instead
+	reporter, err := report.FromEnv(
+		clock.System{}, &http.Client{Timeout: 10 * time.Second},
+	)
+	if err != nil {
+		log.Error(err)
 		return
-	case awsSecretKey:
-		log.Fatal(envAWSSecretKey, errPostfix)
+	}
+	consoleLog := log
+	log = log.ReportTo(reporter)
+
+	// load the limits applied to the server's connections
+	serverConfig, err := api.ServerConfigFromEnv()
+	if err != nil {
+		log.Error(err)
 		return
-	case awsRegion:
-		log.Error(envAWSRegion, errPostfix)
+	}
+
+	// load the attributes of the cookies set on clients
+	cookieConfig, err := cookie.ConfigFromEnv()
+	if err != nil {
+		log.Error(err)
 		return
-	case jwtKey:
-		log.Error(envJWTKey, errPostfix)
+	}
+	// sessions are stored by the user service, whose in-memory store is not
+	// shared in demo mode
+	if *demo && cookieConfig.AuthMode == cookie.AuthModeSession {
+		log.Error(cookie.EnvAuthMode, "cannot be session in demo mode")
 		return
-	case clientOrigin:
-		log.Error(envClientOrigin, errPostfix)
+	}
+
+	// load the default quota teams are subject to unless overridden
+	defaultQuota, err := quota.FromEnv()
+	if err != nil {
+		log.Error(err)
 		return
 	}
 
-	// define aws config
-	cfg := aws.Config{
-		Region: awsRegion,
-		Credentials: credentials.NewStaticCredentialsProvider(
-			awsAccessKey, awsSecretKey, "",
-		),
+	// load the rules deciding which teams the flagged features are enabled for
+	flags, err := featureflag.FromEnv()
+	if err != nil {
+		log.Error(err)
+		return
 	}
-	if awsEndpoint != "" {
-		cfg.BaseEndpoint = aws.String(awsEndpoint)
+
+	// create the circuit breaker shared by the DynamoDB accessors so that
+	// requests fail fast while the database is down
+	dbBreaker, err := breaker.FromEnv(clock.System{})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// create table accessors - in-memory with seeded sample data in demo mode,
+	// otherwise backed by DynamoDB
+	var (
+		teamRetriever  db.Retriever[teamtbl.Team]
+		teamInserter   db.Inserter[teamtbl.Team]
+		teamUpdater    db.Updater[teamtbl.Team]
+		userRetriever  db.Retriever[usertbl.User]
+		boardInserter  db.InserterDualKey[teamtbl.Board]
+		boardUpdater   db.UpdaterDualKey[teamtbl.BoardPatch]
+		boardDeleter   db.DeleterDualKey
+		sprintInserter db.InserterDualKey[teamtbl.Sprint]
+		sprintUpdater  db.UpdaterDualKey[teamtbl.Sprint]
+		sprintDeleter  db.DeleterDualKey
+		tasksByBoard   db.Retriever[[]tasktbl.Task]
+		tasksByTeam    db.Retriever[[]tasktbl.Task]
+		taskInserter   db.Inserter[tasktbl.Task]
+		tasksInserter  db.Inserter[[]tasktbl.Task]
+		tasksUpdater   db.Updater[[]tasktbl.Task]
+		trashInserter  db.Inserter[trashtbl.Item]
+		trashRetriever db.RetrieverDualKey[trashtbl.Item]
+		trashByTeam    db.Retriever[[]trashtbl.Item]
+		trashDeleter   db.DeleterDualKey
+		auditInserter  db.Inserter[audittbl.Entry]
+		auditRetriever db.Retriever[[]audittbl.Entry]
+		histByTeam     db.RetrieverDualKey[[]histtbl.Entry]
+		histInserter   db.Inserter[histtbl.Entry]
+		idemStore      api.IdempotencyStore
+		sessStore      cookie.SessionStore
+		outboxLister   db.Lister[[]outboxtbl.Item]
+		outboxUpdater  db.Updater[outboxtbl.Item]
+		outboxDeleter  db.Deleter
+	)
+	if *demo {
+		store, err := memdb.NewDemoStore()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		teamRetriever = memdb.NewTeamRetriever(store)
+		teamInserter = memdb.NewTeamInserter(store)
+		teamUpdater = memdb.NewTeamUpdater(store)
+		userRetriever = memdb.NewUserRetriever(store)
+		boardInserter = memdb.NewBoardInserter(store, defaultQuota)
+		boardUpdater = memdb.NewBoardUpdater(store)
+		boardDeleter = memdb.NewBoardDeleter(store)
+		sprintInserter = memdb.NewSprintInserter(store)
+		sprintUpdater = memdb.NewSprintUpdater(store)
+		sprintDeleter = memdb.NewSprintDeleter(store)
+		tasksByBoard = memdb.NewTaskRetrieverByBoard(store)
+		tasksByTeam = memdb.NewTaskRetrieverByTeam(store)
+		taskInserter = memdb.NewTaskInserter(store)
+		tasksInserter = memdb.NewTaskTransactionalInserter(store)
+		tasksUpdater = memdb.NewTaskTransactionalUpdater(store)
+		trashInserter = memdb.NewTrashInserter(store)
+		trashRetriever = memdb.NewTrashRetriever(store)
+		trashByTeam = memdb.NewTrashRetrieverByTeam(store)
+		trashDeleter = memdb.NewTrashDeleter(store)
+		auditInserter = memdb.NewAuditInserter(store)
+		auditRetriever = memdb.NewAuditRetriever(store)
+		histByTeam = memdb.NewHistoryRetrieverByTeam(store)
+		histInserter = memdb.NewHistoryInserter(store)
+		idemStore = api.IdempotencyStore{
+			Inserter:  memdb.NewRecordInserter(store),
+			Retriever: memdb.NewRecordRetriever(store),
+			Updater:   memdb.NewRecordUpdater(store),
+			Deleter:   memdb.NewRecordDeleter(store),
+		}
+		log.Info(
+			"running in demo mode - log in as", memdb.DemoUsername,
+			"with password", memdb.DemoPassword,
+		)
+	} else {
+		// check aws environment variables were set
+		// - except aws endpoint, which is only set on local
+		switch "" {
+		case awsAccessKey:
+			log.Fatal(envAWSAccessKey, errPostfix)
+			return
+		case awsSecretKey:
+			log.Fatal(envAWSSecretKey, errPostfix)
+			return
+		case awsRegion:
+			log.Fatal(envAWSRegion, errPostfix)
+			return
+		}
+
+		// define aws config
+		cfg := aws.Config{
+			Region: awsRegion,
+			Credentials: credentials.NewStaticCredentialsProvider(
+				awsAccessKey, awsSecretKey, "",
+			),
+		}
+		if awsEndpoint != "" {
+			cfg.BaseEndpoint = aws.String(awsEndpoint)
+		}
+
+		// create DynamoDB client from config, storing users, teams, and tasks
+		// in a single table if one is configured
+		var client db.DynamoClient = dynamodb.NewFromConfig(cfg)
+		if os.Getenv(singletbl.EnvTableName) != "" {
+			client = singletbl.NewClient(client)
+		}
+		teamRetriever = teamtbl.NewRetriever(client)
+		teamInserter = teamtbl.NewInserter(client)
+		teamUpdater = teamtbl.NewUpdater(client)
+		userRetriever = usertbl.NewRetriever(client)
+		boardInserter = teamtbl.NewBoardInserter(
+			client, defaultQuota,
+		).WithOutbox(client, notify.NewBoardCreatedItem)
+		boardUpdater = teamtbl.NewBoardUpdater(client)
+		boardDeleter = teamtbl.NewBoardDeleter(client)
+		sprintInserter = teamtbl.NewSprintInserter(client)
+		sprintUpdater = teamtbl.NewSprintUpdater(client)
+		sprintDeleter = teamtbl.NewSprintDeleter(client)
+		tasksByBoard = tasktbl.NewRetrieverByBoard(client)
+		tasksByTeam = tasktbl.NewRetrieverByTeam(client)
+		taskInserter = tasktbl.NewInserter(client)
+		tasksInserter = tasktbl.NewTransactionalInserter(client)
+		tasksUpdater = tasktbl.NewTransactionalUpdater(client)
+		trashInserter = trashtbl.NewInserter(client)
+		trashRetriever = trashtbl.NewRetriever(client)
+		trashByTeam = trashtbl.NewRetrieverByTeam(client)
+		trashDeleter = trashtbl.NewDeleter(client)
+		auditInserter = audittbl.NewInserter(client)
+		auditRetriever = audittbl.NewRetriever(client)
+		histByTeam = histtbl.NewRetrieverByTeam(client)
+		histInserter = histtbl.NewInserter(client)
+		idemStore = api.IdempotencyStore{
+			Inserter:  idemtbl.NewInserter(client),
+			Retriever: idemtbl.NewRetriever(client),
+			Updater:   idemtbl.NewUpdater(client),
+			Deleter:   idemtbl.NewDeleter(client),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  sesstbl.NewInserter(client),
+			Retriever: sesstbl.NewRetriever(client),
+			Updater:   sesstbl.NewUpdater(client),
+		}
+		outboxLister = outboxtbl.NewLister(client)
+		outboxUpdater = outboxtbl.NewUpdater(client)
+		outboxDeleter = outboxtbl.NewDeleter(client)
+
+		// retry the calls to DynamoDB that are throttled instead of failing
+		// the request
+		backoff, err := retry.BackoffFromEnv()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		teamRetriever = retry.NewRetriever(teamRetriever, backoff)
+		teamInserter = retry.NewInserter(teamInserter, backoff)
+		teamUpdater = retry.NewUpdater(teamUpdater, backoff)
+		userRetriever = retry.NewRetriever(userRetriever, backoff)
+		boardInserter = retry.NewInserterDualKey(boardInserter, backoff)
+		boardUpdater = retry.NewUpdaterDualKey(boardUpdater, backoff)
+		boardDeleter = retry.NewDeleterDualKey(boardDeleter, backoff)
+		sprintInserter = retry.NewInserterDualKey(sprintInserter, backoff)
+		sprintUpdater = retry.NewUpdaterDualKey(sprintUpdater, backoff)
+		sprintDeleter = retry.NewDeleterDualKey(sprintDeleter, backoff)
+		tasksByBoard = retry.NewRetriever(tasksByBoard, backoff)
+		tasksByTeam = retry.NewRetriever(tasksByTeam, backoff)
+		taskInserter = retry.NewInserter(taskInserter, backoff)
+		tasksInserter = retry.NewInserter(tasksInserter, backoff)
+		tasksUpdater = retry.NewUpdater(tasksUpdater, backoff)
+		trashInserter = retry.NewInserter(trashInserter, backoff)
+		trashRetriever = retry.NewRetrieverDualKey(trashRetriever, backoff)
+		trashByTeam = retry.NewRetriever(trashByTeam, backoff)
+		trashDeleter = retry.NewDeleterDualKey(trashDeleter, backoff)
+		auditInserter = retry.NewInserter(auditInserter, backoff)
+		auditRetriever = retry.NewRetriever(auditRetriever, backoff)
+		histByTeam = retry.NewRetrieverDualKey(histByTeam, backoff)
+		histInserter = retry.NewInserter(histInserter, backoff)
+		idemStore = api.IdempotencyStore{
+			Inserter:  retry.NewInserter(idemStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(idemStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(idemStore.Updater, backoff),
+			Deleter:   retry.NewDeleter(idemStore.Deleter, backoff),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  retry.NewInserter(sessStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(sessStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(sessStore.Updater, backoff),
+		}
+		outboxLister = retry.NewLister(outboxLister, backoff)
+		outboxUpdater = retry.NewUpdater(outboxUpdater, backoff)
+		outboxDeleter = retry.NewDeleter(outboxDeleter, backoff)
+
+		// stop calling DynamoDB while it keeps failing - this wraps the
+		// retries so that a call counts as a single failure however many
+		// times it was attempted
+		teamRetriever = breaker.NewRetriever(teamRetriever, dbBreaker)
+		teamInserter = breaker.NewInserter(teamInserter, dbBreaker)
+		teamUpdater = breaker.NewUpdater(teamUpdater, dbBreaker)
+		userRetriever = breaker.NewRetriever(userRetriever, dbBreaker)
+		boardInserter = breaker.NewInserterDualKey(boardInserter, dbBreaker)
+		boardUpdater = breaker.NewUpdaterDualKey(boardUpdater, dbBreaker)
+		boardDeleter = breaker.NewDeleterDualKey(boardDeleter, dbBreaker)
+		sprintInserter = breaker.NewInserterDualKey(sprintInserter, dbBreaker)
+		sprintUpdater = breaker.NewUpdaterDualKey(sprintUpdater, dbBreaker)
+		sprintDeleter = breaker.NewDeleterDualKey(sprintDeleter, dbBreaker)
+		tasksByBoard = breaker.NewRetriever(tasksByBoard, dbBreaker)
+		tasksByTeam = breaker.NewRetriever(tasksByTeam, dbBreaker)
+		taskInserter = breaker.NewInserter(taskInserter, dbBreaker)
+		tasksInserter = breaker.NewInserter(tasksInserter, dbBreaker)
+		tasksUpdater = breaker.NewUpdater(tasksUpdater, dbBreaker)
+		trashInserter = breaker.NewInserter(trashInserter, dbBreaker)
+		trashRetriever = breaker.NewRetrieverDualKey(trashRetriever, dbBreaker)
+		trashByTeam = breaker.NewRetriever(trashByTeam, dbBreaker)
+		trashDeleter = breaker.NewDeleterDualKey(trashDeleter, dbBreaker)
+		auditInserter = breaker.NewInserter(auditInserter, dbBreaker)
+		auditRetriever = breaker.NewRetriever(auditRetriever, dbBreaker)
+		histByTeam = breaker.NewRetrieverDualKey(histByTeam, dbBreaker)
+		histInserter = breaker.NewInserter(histInserter, dbBreaker)
+		idemStore = api.IdempotencyStore{
+			Inserter:  breaker.NewInserter(idemStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(idemStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(idemStore.Updater, dbBreaker),
+			Deleter:   breaker.NewDeleter(idemStore.Deleter, dbBreaker),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  breaker.NewInserter(sessStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(sessStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(sessStore.Updater, dbBreaker),
+		}
+
+		// run the background jobs on their schedules if a lock table is
+		// configured to elect the instance that does each run
+		if os.Getenv(locktbl.EnvTableName) != "" {
+			cleanupSchedule := os.Getenv(cleanup.EnvSchedule)
+			if cleanupSchedule == "" {
+				cleanupSchedule = cleanup.DefaultSchedule
+			}
+			schedule, err := jobs.ParseCron(cleanupSchedule)
+			if err != nil {
+				log.Fatal(cleanup.EnvSchedule, "was invalid:", err)
+				return
+			}
+
+			hostname, _ := os.Hostname()
+			scheduler := jobs.NewScheduler(
+				clock.System{},
+				retry.NewInserter(locktbl.NewInserter(client), backoff),
+				hostname+"/"+uuid.NewString(),
+				log,
+			)
+			scheduler.Add(jobs.Job{
+				Name:     "cleanup",
+				Schedule: schedule,
+				Run: func(ctx context.Context) error {
+					rep, err := cleanup.NewJob(
+						clock.System{},
+						retry.NewLister(usertbl.NewLister(client), backoff),
+						retry.NewUpdater(usertbl.NewUpdater(client), backoff),
+						teamRetriever,
+						teamUpdater,
+						trashByTeam,
+						retry.NewRetriever(
+							trashtbl.NewExpiredRetrieverByTeam(client), backoff,
+						),
+						trashDeleter,
+						tasksByTeam,
+						retry.NewDeleterDualKey(
+							tasktbl.NewDeleter(client), backoff,
+						),
+						log,
+					).Run(ctx, false)
+					if err != nil {
+						return err
+					}
+					log.Info(fmt.Sprintf(
+						"cleanup purged %d trash items, %d orphaned tasks, "+
+							"%d invites, %d sessions",
+						rep.TrashItems, rep.Tasks, rep.Invites, rep.Sessions,
+					))
+					return nil
+				},
+			})
+
+			archiveSchedule := os.Getenv(archive.EnvSchedule)
+			if archiveSchedule == "" {
+				archiveSchedule = archive.DefaultSchedule
+			}
+			schedule, err = jobs.ParseCron(archiveSchedule)
+			if err != nil {
+				log.Fatal(archive.EnvSchedule, "was invalid:", err)
+				return
+			}
+			scheduler.Add(jobs.Job{
+				Name:     "archive",
+				Schedule: schedule,
+				Run: func(ctx context.Context) error {
+					n, err := archive.NewJob(
+						clock.System{},
+						retry.NewLister(usertbl.NewLister(client), backoff),
+						teamRetriever,
+						tasksByTeam,
+						histByTeam,
+						tasksUpdater,
+						log,
+					).Run(ctx)
+					if err != nil {
+						return err
+					}
+					log.Info(fmt.Sprintf("archive archived %d tasks", n))
+					return nil
+				},
+			})
+			expvar.Publish("jobs", scheduler.Metrics())
+			go scheduler.Run(context.Background())
+		}
 	}
 
-	// create DynamoDB client from config
-	db := dynamodb.NewFromConfig(cfg)
+	// cache retrieved teams in memory if a TTL is set, invalidating them on
+	// every write to the team, its boards, or its sprints
+	if teamCacheTTL != "" {
+		ttl, err := time.ParseDuration(teamCacheTTL)
+		if err != nil {
+			log.Fatal(envTeamCacheTTL, "was invalid:", err)
+			return
+		}
+		teamCache := cache.NewRetriever(
+			teamRetriever, ttl, teamCacheSize, teamtbl.Team.Clone,
+		)
+		teamRetriever = teamCache
+		teamUpdater = cache.NewUpdater(
+			teamUpdater, teamCache, func(t teamtbl.Team) string { return t.ID },
+		)
+		boardInserter = cache.NewInserterDualKey(boardInserter, teamCache)
+		boardUpdater = cache.NewUpdaterDualKey(boardUpdater, teamCache)
+		boardDeleter = cache.NewDeleterDualKey(boardDeleter, teamCache)
+		sprintInserter = cache.NewInserterDualKey(sprintInserter, teamCache)
+		sprintUpdater = cache.NewUpdaterDualKey(sprintUpdater, teamCache)
+		sprintDeleter = cache.NewDeleterDualKey(sprintDeleter, teamCache)
+		expvar.Publish("teamCache", expvar.Func(func() any {
+			return teamCache.Stats()
+		}))
 
-	// create auth encoder to be used for authenticating user on all routes
-	authDecoder := cookie.NewAuthDecoder([]byte(jwtKey))
+		// invalidate the teams that are written to by other instances and
+		// the admin CLI too by reading the changes from the table's stream
+		if arn := os.Getenv(envTeamStreamARN); arn != "" && !*demo {
+			go stream.NewPoller(
+				arn,
+				stream.Endpoint(awsRegion, awsEndpoint),
+				awsRegion,
+				credentials.NewStaticCredentialsProvider(
+					awsAccessKey, awsSecretKey, "",
+				),
+				clock.System{},
+				&http.Client{Timeout: 10 * time.Second},
+				stream.NewConsumer(stream.InvalidateTeams(teamCache)),
+				log,
+			).Run(context.Background())
+		}
+	}
+
+	// create auth decoder to be used for authenticating users on all routes
+	var authDecoder cookie.Decoder[cookie.Auth] = cookie.NewAuthDecoder(
+		jwtKeys, clock.System{},
+	)
+	// decode the auth tokens that carry the tokens of server-side sessions in
+	// session mode
+	if cookieConfig.AuthMode == cookie.AuthModeSession {
+		authDecoder = cookie.NewStoredAuthDecoder(
+			authDecoder, sessStore, cookieConfig, clock.System{},
+		)
+	}
+	// reject the auth tokens whose session was revoked or whose user is
+	// disabled - users are stored by the user service, whose in-memory store
+	// is not shared in demo mode
+	if !*demo {
+		authDecoder = cookie.NewSessionDecoder(
+			authDecoder, userRetriever, clock.System{},
+		)
+	}
+
+	// hand invite emails and Slack notifications off to a worker through SQS
+	// if a queue is configured, otherwise through an in-memory queue, so that
+	// requests do not wait for the mail server or Slack
+	var q queue.Queue = queue.NewMemory(clock.System{})
+	if queueURL := os.Getenv(queue.EnvURL); queueURL != "" && !*demo {
+		q, err = queue.NewSQS(
+			queueURL,
+			awsRegion,
+			credentials.NewStaticCredentialsProvider(
+				awsAccessKey, awsSecretKey, "",
+			),
+			clock.System{},
+			&http.Client{Timeout: 30 * time.Second},
+		)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+	mailSender := mail.NewQueued(q)
+
+	// send invite emails through SMTP if it is configured, otherwise log them
+	var smtpSender mail.Sender = mail.NewLog(log)
+	if smtpAddr != "" {
+		if smtpFrom == "" {
+			log.Fatal(envSMTPFrom, errPostfix)
+			return
+		}
+		smtpSender = mail.NewSMTP(
+			smtpAddr,
+			smtpFrom,
+			os.Getenv(envSMTPUsername),
+			os.Getenv(envSMTPPassword),
+		)
+	}
+
+	// send the queued emails and notifications in the background
+	worker := queue.NewWorker(q, log)
+	worker.Handle(mail.QueueKind, mail.NewHandler(smtpSender))
+	worker.Handle(notify.QueueKind, notify.NewHandler(notify.NewSlack(
+		teamRetriever, &http.Client{Timeout: 10 * time.Second},
+	)))
+	go worker.Run(context.Background())
+
+	// send the notifications written to the outbox with the changes they are
+	// of to the queue
+	if outboxLister != nil {
+		dispatcher := outbox.NewDispatcher(
+			clock.System{}, outboxLister, outboxUpdater, outboxDeleter, q, log,
+		)
+		go dispatcher.Run(context.Background())
+	}
 
 	// register handlers for HTTP routes
-	mux := http.NewServeMux()
-
-	mux.Handle("/team", api.NewHandler(map[string]api.MethodHandler{
-		http.MethodGet: teamapi.NewGetHandler(
-			authDecoder,
-			teamtbl.NewRetriever(db),
-			teamtbl.NewInserter(db),
-			teamtbl.NewUpdater(db),
-			cookie.NewInviteEncoder([]byte(jwtKey), 1*time.Hour),
+	mux := api.NewRouter()
+
+	// make the unsafe requests sent with an Idempotency-Key header only be
+	// handled once
+	idempotent := api.Idempotent(idemStore, log)
+
+	mux.Handle("/team", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.Authed(authDecoder, log, teamapi.NewGetHandler(
+				teamRetriever,
+				teamInserter,
+				teamUpdater,
+				userRetriever,
+				cookie.NewInviteEncoder(
+					jwtKeys, 1*time.Hour, cookieConfig, clock.System{},
+				),
+				clock.System{},
+				log,
+			)),
+		},
+	).Use(api.Compress, api.ETag))
+
+	mux.Handle("/team/members", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.Authed(
+				authDecoder, log, membersapi.NewGetHandler(
+					teamRetriever,
+					userRetriever,
+					cursorSigner,
+					log,
+				),
+			),
+		},
+	).Use(api.ETag))
+
+	mux.Handle("/team/audit", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, auditapi.NewGetHandler(
+			auditRetriever,
+			cursorSigner,
 			log,
-		),
+		)),
 	}))
 
-	mux.Handle("/board", api.NewHandler(map[string]api.MethodHandler{
-		http.MethodPost: boardapi.NewPostHandler(
-			authDecoder,
-			boardapi.NewNameValidator(),
-			teamtbl.NewBoardInserter(db),
+	mux.Handle("/team/analytics", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, analyticsapi.NewGetHandler(
+			teamRetriever,
+			tasksByTeam,
+			histByTeam,
+			clock.System{},
+			log,
+		)),
+	}))
+
+	mux.Handle("/search", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, searchapi.NewGetHandler(
+			teamRetriever,
+			tasksByTeam,
+			log,
+		)),
+	}))
+
+	mux.Handle("/team/invite", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: api.Authed(authDecoder, log, inviteapi.NewPostHandler(
+			validator.Email,
+			teamRetriever,
+			teamUpdater,
+			cookie.NewInviteEncoder(
+				jwtKeys, 7*24*time.Hour, cookieConfig, clock.System{},
+			),
+			mailSender,
+			clientOrigin+"/register",
+			auditInserter,
+			clock.System{},
+			log,
+		)),
+	}).Use(idempotent))
+
+	mux.Handle("/team/slack", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, slackapi.NewGetHandler(
+			teamRetriever,
 			log,
+		)),
+		http.MethodPut: api.Authed(authDecoder, log, slackapi.NewPutHandler(
+			slackapi.NewWebhookURLValidator(),
+			teamRetriever,
+			teamUpdater,
+			log,
+		)),
+	}))
+
+	mux.Handle("/team/sso", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, ssoapi.NewGetHandler(
+			teamRetriever,
+			log,
+		)),
+		http.MethodPut: api.Authed(authDecoder, log, ssoapi.NewPutHandler(
+			ssoapi.Metadata{},
+			teamRetriever,
+			teamUpdater,
+			log,
+		)),
+	}))
+
+	mux.Handle("/team/scim-token", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: api.Authed(
+			authDecoder, log, scimtokenapi.NewPostHandler(
+				teamRetriever,
+				teamUpdater,
+				log,
+			),
+		),
+		http.MethodDelete: api.Authed(
+			authDecoder, log,
+			scimtokenapi.NewDeleteHandler(teamRetriever, teamUpdater, log),
 		),
-		http.MethodPatch: boardapi.NewPatchHandler(
-			authDecoder,
-			boardapi.NewIDValidator(),
-			boardapi.NewNameValidator(),
-			teamtbl.NewBoardUpdater(db),
+	}))
+
+	mux.Handle("/team/sprint", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, sprintapi.NewGetHandler(
+			teamRetriever,
+			log,
+		)),
+		http.MethodPost: api.Authed(authDecoder, log, sprintapi.NewPostHandler(
+			sprintapi.ValidateSprint,
+			sprintInserter,
 			log,
+		)),
+		http.MethodPatch: api.Authed(
+			authDecoder, log, sprintapi.NewPatchHandler(
+				sprintapi.ValidateSprint,
+				sprintUpdater,
+				log,
+			),
 		),
-		http.MethodDelete: boardapi.NewDeleteHandler(
-			authDecoder,
-			teamtbl.NewBoardDeleter(db),
+		http.MethodDelete: api.Authed(
+			authDecoder, log, sprintapi.NewDeleteHandler(sprintDeleter, log),
+		),
+	}).Use(idempotent))
+
+	mux.Handle("/team/checklist", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, checklistapi.NewGetHandler(
+			teamRetriever,
 			log,
+		)),
+		http.MethodPost: api.Authed(
+			authDecoder, log, checklistapi.NewPostHandler(
+				checklistapi.ValidateChecklist,
+				teamRetriever,
+				teamUpdater,
+				log,
+			),
 		),
+		http.MethodPatch: api.Authed(
+			authDecoder, log, checklistapi.NewPatchHandler(
+				checklistapi.ValidateChecklist,
+				teamRetriever,
+				teamUpdater,
+				log,
+			),
+		),
+		http.MethodDelete: api.Authed(
+			authDecoder, log,
+			checklistapi.NewDeleteHandler(teamRetriever, teamUpdater, log),
+		),
+	}).Use(idempotent))
+
+	// serve the billing routes if Stripe is configured
+	if stripeSecretKey := os.Getenv(envStripeSecretKey); stripeSecretKey != "" {
+		var (
+			priceID       = os.Getenv(envStripePriceID)
+			webhookSecret = os.Getenv(envStripeWebhookSecret)
+		)
+		switch "" {
+		case priceID:
+			log.Fatal(envStripePriceID, errPostfix)
+			return
+		case webhookSecret:
+			log.Fatal(envStripeWebhookSecret, errPostfix)
+			return
+		}
+		paidQuota, err := quota.PaidFromEnv()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+
+		mux.Handle("/team/billing/checkout", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodPost: api.Authed(
+					authDecoder, log,
+					billingapi.NewCheckoutHandler(
+						teamRetriever,
+						billing.NewStripe(
+							billing.StripeURL,
+							stripeSecretKey,
+							priceID,
+							clientOrigin+"/?billing=success",
+							clientOrigin+"/?billing=cancelled",
+							&http.Client{Timeout: 10 * time.Second},
+						),
+						log,
+					),
+				),
+			},
+		))
+
+		mux.Handle("/team/billing/webhook", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodPost: billingapi.NewWebhookHandler(
+					billing.NewWebhookVerifier(webhookSecret),
+					paidQuota,
+					teamRetriever,
+					teamUpdater,
+					log,
+				),
+			},
+		))
+	}
+
+	mux.Handle("/team/trash", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: api.Authed(authDecoder, log, trashapi.NewGetHandler(
+			trashByTeam,
+			log,
+		)),
 	}))
 
-	// serve the registered routes
+	mux.Handle("/team/trash/restore", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPost: api.Authed(
+				authDecoder, log, trashapi.NewPostHandler(
+					trashRetriever,
+					teamRetriever,
+					boardInserter,
+					taskInserter,
+					trashDeleter,
+					log,
+				),
+			),
+		},
+	).Use(idempotent))
+
+	mux.Handle("/undo", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: api.Authed(authDecoder, log, trashapi.NewUndoHandler(
+			trashByTeam,
+			histByTeam,
+			teamRetriever,
+			boardInserter,
+			taskInserter,
+			trashDeleter,
+			tasksByTeam,
+			tasksUpdater,
+			histInserter,
+			clock.System{},
+			log,
+		)),
+	}).Use(idempotent))
+
+	var (
+		boardPostHandler = api.Authed(authDecoder, log, boardapi.NewPostHandler(
+			validator.BoardName,
+			validator.BoardDesc,
+			boardInserter,
+			log,
+		))
+		boardPatchHandler = api.Authed(
+			authDecoder, log, boardapi.NewPatchHandler(
+				validator.ID,
+				validator.BoardName,
+				validator.BoardDesc,
+				boardapi.ValidateColumns,
+				boardUpdater,
+				log,
+			),
+		)
+		boardDeleteHandler = api.Authed(
+			authDecoder, log, boardapi.NewDeleteHandler(
+				teamRetriever,
+				trashInserter,
+				trashDeleter,
+				boardDeleter,
+				auditInserter,
+				log,
+			),
+		)
+	)
+
+	mux.Handle("/boards", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: boardPostHandler,
+	}).Use(idempotent))
+
+	// registered before /boards/{boardID} so that it is not matched as a board
+	mux.Handle("/boards/import", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: api.Authed(authDecoder, log, boardapi.NewImportHandler(
+			boardapi.ValidateImportReq,
+			boardInserter,
+			boardDeleter,
+			tasksInserter,
+			log,
+		)),
+	}).Use(idempotent))
+
+	mux.Handle("/boards/{boardID}", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPatch:  boardPatchHandler,
+			http.MethodDelete: boardDeleteHandler,
+		},
+	))
+
+	mux.Handle("/boards/{boardID}/export", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.Authed(
+				authDecoder, log, boardapi.NewExportHandler(
+					validator.ID,
+					teamRetriever,
+					tasksByBoard,
+					clock.System{},
+					log,
+				),
+			),
+		},
+	).Use(api.Compress))
+
+	mux.Handle("/boards/{boardID}/share", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPost: api.Authed(
+				authDecoder, log, shareapi.NewPostHandler(
+					validator.ID,
+					teamRetriever,
+					teamUpdater,
+					cookie.NewShareEncoder(jwtKeys),
+					log,
+				),
+			),
+			http.MethodDelete: api.Authed(
+				authDecoder, log,
+				shareapi.NewDeleteHandler(
+					validator.ID, teamRetriever, teamUpdater, log,
+				),
+			),
+		},
+	))
+
+	// serve the boards that were shared to anyone with their share link
+	mux.Handle("/public/board", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: publicapi.NewGetHandler(
+				cookie.NewShareDecoder(jwtKeys, clock.System{}),
+				teamRetriever,
+				tasksByBoard,
+				log,
+			),
+		},
+	).Use(api.Compress))
+
+	// deprecated - kept as an alias for /boards and /boards/{boardID} for one
+	// release to give clients time to migrate
+	mux.Handle("/board", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost:   boardPostHandler,
+		http.MethodPatch:  boardPatchHandler,
+		http.MethodDelete: boardDeleteHandler,
+	}).Use(api.Deprecated, idempotent))
+
+	mux.Handle("/graphql", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPost: api.Authed(authDecoder, log, api.Flagged(
+				flags, featureflag.GraphQL, graphqlapi.NewPostHandler(
+					teamRetriever,
+					tasksByBoard,
+					tasksByTeam,
+					log,
+				),
+			)),
+		},
+	).Use(api.Compress))
+
+	// translate the error messages of the registered routes into the client's
+	// preferred language, reject cross-site requests to them, and fail fast on
+	// them while the database is down
+	root := http.NewServeMux()
+	root.Handle("/", api.Chain(
+		api.Localize, api.CSRF(clientOrigin), api.FailFast(dbBreaker),
+	)(mux))
+
+	// serve the API documentation
+	root.Handle("/openapi.json", openapi.NewSpecHandler(apidoc.Spec))
+	root.Handle("/docs", openapi.NewDocsHandler("/openapi.json"))
+
+	// serve runtime and cache hit/miss metrics on their own listener, if
+	// configured, so that the process details expvar exposes along with them
+	// are kept off the public API
+	if debugAddr := os.Getenv(envDebugAddr); debugAddr != "" {
+		debug := http.NewServeMux()
+		debug.Handle("/debug/vars", expvar.Handler())
+		go func() {
+			log.Info("serving team service metrics on", debugAddr)
+			if err := serverConfig.NewServer(
+				debugAddr, debug,
+			).ListenAndServe(); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
+
+	// serve the registered routes, logging a line for each request and
+	// recovering from the panics in handling them
 	log.Info("running team service on port", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := serverConfig.NewServer(
+		":"+port, api.Chain(
+			api.AccessLog(clock.System{}, log),
+			api.Recover(reporter, consoleLog),
+		)(root),
+	).ListenAndServe(); err != nil {
 		log.Fatal(err)
 		return
 	}
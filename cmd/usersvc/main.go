@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,12 +15,38 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/joho/godotenv"
 
+	"github.com/kxplxn/goteam/internal/apidoc"
+	"github.com/kxplxn/goteam/internal/usersvc/adminapi"
+	"github.com/kxplxn/goteam/internal/usersvc/avatarapi"
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
+	"github.com/kxplxn/goteam/internal/usersvc/favoritesapi"
 	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/internal/usersvc/oidc"
+	"github.com/kxplxn/goteam/internal/usersvc/oidcapi"
 	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/internal/usersvc/samlapi"
+	"github.com/kxplxn/goteam/internal/usersvc/scimapi"
+	"github.com/kxplxn/goteam/internal/usersvc/sessionapi"
 	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/breaker"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
+	"github.com/kxplxn/goteam/pkg/db/retry"
+	"github.com/kxplxn/goteam/pkg/db/sesstbl"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/openapi"
+	"github.com/kxplxn/goteam/pkg/profile"
+	"github.com/kxplxn/goteam/pkg/pwdhash"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/report"
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 const (
@@ -23,9 +54,16 @@ const (
 	// to run the user service on.
 	envPort = "USER_SERVICE_PORT"
 
+	// envDynamoDBEndpoint is the name of the environment variable used for
+	// pointing the DynamoDB client at a local instance such as dynamodb-local
+	// or LocalStack. When it is set, AWS credentials and region default to
+	// dummy values as local instances do not validate them.
+	envDynamoDBEndpoint = "DYNAMODB_ENDPOINT"
+
 	// envAWSEndpoint is the name of the environment variable used for setting
 	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
-	// on local pointing to the local DynamoDB instance.
+	// on local pointing to the local DynamoDB instance. It is superseded by
+	// envDynamoDBEndpoint and kept for existing .env files.
 	envAWSEndpoint = "AWS_ENDPOINT"
 
 	// envPort is the name of the environment variable used for providing AWS
@@ -40,18 +78,82 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
-	envJWTKey = "JWT_KEY"
-
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
 	envClientOrigin = "CLIENT_ORIGIN"
+
+	// envPwdMinScore is the name of the environment variable used for setting
+	// the minimum strength score, between 0 and 4, that passwords must have
+	// on register. It defaults to registerapi.DefaultMinPwdScore.
+	envPwdMinScore = "PASSWORD_MIN_SCORE"
+
+	// envReservedUsernames is the name of the environment variable used for
+	// setting the comma separated list of usernames that cannot be registered,
+	// along with those that can be mistaken for them. It replaces
+	// registerapi.DefaultReservedUsernames when it is set.
+	envReservedUsernames = "RESERVED_USERNAMES"
+
+	// envPwdHashAlgorithm is the name of the environment variable used for
+	// choosing the algorithm new password hashes are made with - bcrypt or
+	// argon2id. Passwords hashed with the other algorithm or with outdated
+	// parameters are rehashed when their users log in.
+	envPwdHashAlgorithm = "PASSWORD_HASH_ALGORITHM"
+
+	// envBcryptCost is the name of the environment variable used for setting
+	// the cost of bcrypt password hashes.
+	envBcryptCost = "BCRYPT_COST"
+
+	// envArgon2Time, envArgon2MemoryKiB and envArgon2Threads are the names of
+	// the environment variables used for setting the iterations, the memory
+	// and the parallelism of Argon2id password hashes.
+	envArgon2Time      = "ARGON2_TIME"
+	envArgon2MemoryKiB = "ARGON2_MEMORY_KIB"
+	envArgon2Threads   = "ARGON2_THREADS"
+
+	// envPwdPeppers is the name of the environment variable used for setting
+	// the secret peppers combined with passwords before hashing, as a comma
+	// separated list of id:secret pairs. New hashes are made with the first
+	// pepper, and the rest are kept for verifying the hashes made before it
+	// until their users log in and their passwords are rehashed.
+	envPwdPeppers = "PASSWORD_PEPPERS"
+
+	// envCaptchaProvider is the name of the environment variable used for
+	// choosing the CAPTCHA provider that register and login requests are
+	// verified with - hcaptcha or turnstile. CAPTCHA verification is disabled
+	// when it is empty.
+	envCaptchaProvider = "CAPTCHA_PROVIDER"
+
+	// envCaptchaSecretKey is the name of the environment variable used for
+	// providing the secret key of the CAPTCHA provider. The matching site key
+	// is set on the client app.
+	envCaptchaSecretKey = "CAPTCHA_SECRET_KEY"
+
+	// envAdminToken is the name of the environment variable used for setting
+	// the static token that operators authenticate to the /admin routes with
+	// as a bearer token. The /admin routes are not served when it is empty.
+	envAdminToken = "ADMIN_TOKEN"
+
+	// minAdminTokenLen is the minimum length of the admin token, so that it
+	// cannot be guessed.
+	minAdminTokenLen = 32
+
+	// envSAMLBaseURL is the name of the environment variable used for setting
+	// the public URL of the user service, which the entity IDs and the ACS
+	// URLs of teams' SAML service providers are made from. The /sso routes are
+	// not served when it is empty.
+	envSAMLBaseURL = "SAML_SP_BASE_URL"
 )
 
 func main() {
 	// create a logger
 	log := log.New()
 
+	// parse flags
+	demo := flag.Bool(
+		"demo", false, "serve seeded sample data from memory instead of DynamoDB",
+	)
+	flag.Parse()
+
 	// load environment variables
 	err := godotenv.Load()
 	if err != nil {
@@ -59,6 +161,13 @@ func main() {
 		return
 	}
 
+	// default the settings that are not set to those of the environment
+	// profile
+	if _, err := profile.Apply(); err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	// get environment variables
 	var (
 		port         = os.Getenv(envPort)
@@ -66,10 +175,23 @@ func main() {
 		awsAccessKey = os.Getenv(envAWSAccessKey)
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
-		jwtKey       = os.Getenv(envJWTKey)
 		clientOrigin = os.Getenv(envClientOrigin)
+		pwdMinScore  = os.Getenv(envPwdMinScore)
 	)
 
+	// prefer the DynamoDB endpoint over the AWS endpoint, and fill in dummy
+	// credentials and region if a local endpoint is set without them
+	if endpoint := os.Getenv(envDynamoDBEndpoint); endpoint != "" {
+		awsEndpoint = endpoint
+	}
+	if awsEndpoint != "" {
+		for _, v := range []*string{&awsAccessKey, &awsSecretKey, &awsRegion} {
+			if *v == "" {
+				*v = "local"
+			}
+		}
+	}
+
 	// check all environment variables were set
 	// - except aws endpoint, which is only set on local
 	errPostfix := "was empty"
@@ -77,76 +199,667 @@ func main() {
 	case port:
 		log.Error(envPort, errPostfix)
 		return
-	case awsAccessKey:
-		log.Fatal(envAWSAccessKey, errPostfix)
+	case clientOrigin:
+		log.Error(envClientOrigin, errPostfix)
 		return
-	case awsSecretKey:
-		log.Fatal(envAWSSecretKey, errPostfix)
+	}
+
+	// load the keys that JWTs are signed and verified with
+	jwtKeys, err := cookie.KeysFromEnv()
+	if err != nil {
+		log.Error(err)
 		return
-	case awsRegion:
-		log.Error(envAWSRegion, errPostfix)
+	}
+
+	// parse the password hashing parameters, defaulting the ones not set
+	hashParams, err := parseHashParams()
+	if err != nil {
+		log.Error(err)
 		return
-	case jwtKey:
-		log.Error(envJWTKey, errPostfix)
+	}
+
+	// parse the password peppers if any were set
+	peppers, err := parsePeppers(os.Getenv(envPwdPeppers))
+	if err != nil {
+		log.Error(err)
 		return
-	case clientOrigin:
-		log.Error(envClientOrigin, errPostfix)
+	}
+
+	// create the CAPTCHA verifier if a provider was set
+	captchaVerifier, err := newCaptchaVerifier(
+		os.Getenv(envCaptchaProvider), os.Getenv(envCaptchaSecretKey),
+	)
+	if err != nil {
+		log.Error(err)
 		return
 	}
 
-	// define aws config
-	cfg := aws.Config{
-		Region: awsRegion,
-		Credentials: credentials.NewStaticCredentialsProvider(
-			awsAccessKey, awsSecretKey, "",
-		),
+	// parse the minimum password strength score if it was set
+	minPwdScore := registerapi.DefaultMinPwdScore
+	if pwdMinScore != "" {
+		minPwdScore, err = strconv.Atoi(pwdMinScore)
+		if err != nil || minPwdScore < 0 || minPwdScore > 4 {
+			log.Error(envPwdMinScore, "must be a number between 0 and 4")
+			return
+		}
 	}
-	if awsEndpoint != "" {
-		cfg.BaseEndpoint = aws.String(awsEndpoint)
+
+	// read the reserved usernames if they were set
+	reservedUsernames := registerapi.DefaultReservedUsernames
+	if raw := os.Getenv(envReservedUsernames); raw != "" {
+		reservedUsernames = nil
+		for _, u := range strings.Split(raw, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				reservedUsernames = append(reservedUsernames, u)
+			}
+		}
+	}
+
+	// report the errors that are logged to the error tracker if one is
+	// configured - panics are reported by the recovery middleware with the
+	// context of their request, so it logs them through consoleLog instead
+	reporter, err := report.FromEnv(
+		clock.System{}, &http.Client{Timeout: 10 * time.Second},
+	)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	consoleLog := log
+	log = log.ReportTo(reporter)
+
+	// load the limits applied to the server's connections
+	serverConfig, err := api.ServerConfigFromEnv()
+	if err != nil {
+		log.Error(err)
+		return
 	}
 
-	// create DynamoDB client from config
-	db := dynamodb.NewFromConfig(cfg)
+	// load the attributes of the cookies set on clients
+	cookieConfig, err := cookie.ConfigFromEnv()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	// sessions are stored by the user service, whose in-memory store is not
+	// shared in demo mode
+	if *demo && cookieConfig.AuthMode == cookie.AuthModeSession {
+		log.Error(cookie.EnvAuthMode, "cannot be session in demo mode")
+		return
+	}
+
+	// load the default quota teams are subject to unless overridden
+	defaultQuota, err := quota.FromEnv()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// create the circuit breaker shared by the DynamoDB accessors so that
+	// requests fail fast while the database is down
+	dbBreaker, err := breaker.FromEnv(clock.System{})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// create table accessors - in-memory with seeded sample data in demo mode,
+	// otherwise backed by DynamoDB
+	var (
+		userRetriever db.Retriever[usertbl.User]
+		userByEmail   db.Retriever[[]usertbl.User]
+		userInserter  db.Inserter[usertbl.User]
+		userUpdater   db.Updater[usertbl.User]
+		userLister    db.Lister[[]usertbl.User]
+		teamRetriever db.Retriever[teamtbl.Team]
+		teamUpdater   db.Updater[teamtbl.Team]
+		idemStore     api.IdempotencyStore
+		sessStore     cookie.SessionStore
+
+		avatarInserter  db.Inserter[avatartbl.Avatar]
+		avatarRetriever db.Retriever[avatartbl.Avatar]
+		avatarDeleter   db.Deleter
+	)
+	if *demo {
+		store, err := memdb.NewDemoStore()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		userRetriever = memdb.NewUserRetriever(store)
+		userByEmail = memdb.NewUserRetrieverByEmail(store)
+		userInserter = memdb.NewUserInserter(store)
+		userUpdater = memdb.NewUserUpdater(store)
+		userLister = memdb.NewUserLister(store)
+		teamRetriever = memdb.NewTeamRetriever(store)
+		teamUpdater = memdb.NewTeamUpdater(store)
+		avatarInserter = memdb.NewAvatarInserter(store)
+		avatarRetriever = memdb.NewAvatarRetriever(store)
+		avatarDeleter = memdb.NewAvatarDeleter(store)
+		idemStore = api.IdempotencyStore{
+			Inserter:  memdb.NewRecordInserter(store),
+			Retriever: memdb.NewRecordRetriever(store),
+			Updater:   memdb.NewRecordUpdater(store),
+			Deleter:   memdb.NewRecordDeleter(store),
+		}
+		log.Info(
+			"running in demo mode - log in as", memdb.DemoUsername,
+			"with password", memdb.DemoPassword,
+		)
+	} else {
+		// check aws environment variables were set
+		// - except aws endpoint, which is only set on local
+		switch "" {
+		case awsAccessKey:
+			log.Fatal(envAWSAccessKey, errPostfix)
+			return
+		case awsSecretKey:
+			log.Fatal(envAWSSecretKey, errPostfix)
+			return
+		case awsRegion:
+			log.Fatal(envAWSRegion, errPostfix)
+			return
+		}
+
+		// define aws config
+		cfg := aws.Config{
+			Region: awsRegion,
+			Credentials: credentials.NewStaticCredentialsProvider(
+				awsAccessKey, awsSecretKey, "",
+			),
+		}
+		if awsEndpoint != "" {
+			cfg.BaseEndpoint = aws.String(awsEndpoint)
+		}
+
+		// create DynamoDB client from config, storing users, teams, and tasks
+		// in a single table if one is configured
+		var client db.DynamoClient = dynamodb.NewFromConfig(cfg)
+		if os.Getenv(singletbl.EnvTableName) != "" {
+			client = singletbl.NewClient(client)
+		}
+		userRetriever = usertbl.NewRetriever(client)
+		userByEmail = usertbl.NewRetrieverByEmail(client)
+		userInserter = usertbl.NewInserter(client)
+		userUpdater = usertbl.NewUpdater(client)
+		userLister = usertbl.NewLister(client)
+		teamRetriever = teamtbl.NewRetriever(client)
+		teamUpdater = teamtbl.NewUpdater(client)
+		avatarInserter = avatartbl.NewInserter(client)
+		avatarRetriever = avatartbl.NewRetriever(client)
+		avatarDeleter = avatartbl.NewDeleter(client)
+		idemStore = api.IdempotencyStore{
+			Inserter:  idemtbl.NewInserter(client),
+			Retriever: idemtbl.NewRetriever(client),
+			Updater:   idemtbl.NewUpdater(client),
+			Deleter:   idemtbl.NewDeleter(client),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  sesstbl.NewInserter(client),
+			Retriever: sesstbl.NewRetriever(client),
+			Updater:   sesstbl.NewUpdater(client),
+		}
+
+		// retry the calls to DynamoDB that are throttled instead of failing
+		// the request
+		backoff, err := retry.BackoffFromEnv()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		userRetriever = retry.NewRetriever(userRetriever, backoff)
+		userByEmail = retry.NewRetriever(userByEmail, backoff)
+		userInserter = retry.NewInserter(userInserter, backoff)
+		userUpdater = retry.NewUpdater(userUpdater, backoff)
+		userLister = retry.NewLister(userLister, backoff)
+		teamRetriever = retry.NewRetriever(teamRetriever, backoff)
+		teamUpdater = retry.NewUpdater(teamUpdater, backoff)
+		avatarInserter = retry.NewInserter(avatarInserter, backoff)
+		avatarRetriever = retry.NewRetriever(avatarRetriever, backoff)
+		avatarDeleter = retry.NewDeleter(avatarDeleter, backoff)
+		idemStore = api.IdempotencyStore{
+			Inserter:  retry.NewInserter(idemStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(idemStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(idemStore.Updater, backoff),
+			Deleter:   retry.NewDeleter(idemStore.Deleter, backoff),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  retry.NewInserter(sessStore.Inserter, backoff),
+			Retriever: retry.NewRetriever(sessStore.Retriever, backoff),
+			Updater:   retry.NewUpdater(sessStore.Updater, backoff),
+		}
+
+		// stop calling DynamoDB while it keeps failing - this wraps the
+		// retries so that a call counts as a single failure however many
+		// times it was attempted
+		userRetriever = breaker.NewRetriever(userRetriever, dbBreaker)
+		userByEmail = breaker.NewRetriever(userByEmail, dbBreaker)
+		userInserter = breaker.NewInserter(userInserter, dbBreaker)
+		userUpdater = breaker.NewUpdater(userUpdater, dbBreaker)
+		userLister = breaker.NewLister(userLister, dbBreaker)
+		teamRetriever = breaker.NewRetriever(teamRetriever, dbBreaker)
+		teamUpdater = breaker.NewUpdater(teamUpdater, dbBreaker)
+		avatarInserter = breaker.NewInserter(avatarInserter, dbBreaker)
+		avatarRetriever = breaker.NewRetriever(avatarRetriever, dbBreaker)
+		avatarDeleter = breaker.NewDeleter(avatarDeleter, dbBreaker)
+		idemStore = api.IdempotencyStore{
+			Inserter:  breaker.NewInserter(idemStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(idemStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(idemStore.Updater, dbBreaker),
+			Deleter:   breaker.NewDeleter(idemStore.Deleter, dbBreaker),
+		}
+		sessStore = cookie.SessionStore{
+			Inserter:  breaker.NewInserter(sessStore.Inserter, dbBreaker),
+			Retriever: breaker.NewRetriever(sessStore.Retriever, dbBreaker),
+			Updater:   breaker.NewUpdater(sessStore.Updater, dbBreaker),
+		}
+	}
 
 	// create JWT encoders and decoders
-	key := []byte(jwtKey)
-	dur := 1 * time.Hour
 	var (
-		inviteDecoder = cookie.NewInviteDecoder(key)
-		authEncoder   = cookie.NewAuthEncoder(key, dur)
+		inviteDecoder = cookie.NewInviteDecoder(jwtKeys, clock.System{})
+
+		authEncoder cookie.Encoder[cookie.Auth] = cookie.NewAuthEncoder(
+			jwtKeys, cookieConfig, clock.System{},
+		)
+		authDecoder cookie.Decoder[cookie.Auth] = cookie.NewAuthDecoder(
+			jwtKeys, clock.System{},
+		)
 	)
 
+	// store the auth of sessions server-side in session mode, so that auth
+	// tokens only carry the tokens of their sessions
+	if cookieConfig.AuthMode == cookie.AuthModeSession {
+		authEncoder = cookie.NewStoredAuthEncoder(
+			sessStore, cookieConfig, clock.System{},
+		)
+		authDecoder = cookie.NewStoredAuthDecoder(
+			authDecoder, sessStore, cookieConfig, clock.System{},
+		)
+	}
+
+	// reject the auth tokens whose session was revoked or whose user is
+	// disabled
+	authDecoder = cookie.NewSessionDecoder(
+		authDecoder, userRetriever, clock.System{},
+	)
+
+	// create password hasher to hash new passwords and verify existing ones
+	pwdHasher := pwdhash.NewHasher(hashParams, peppers...)
+
 	// register handlers for HTTP routes
-	mux := http.NewServeMux()
+	mux := api.NewRouter()
 
 	mux.Handle("/register", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: registerapi.NewPostHandler(
 			registerapi.NewUserValidator(
-				registerapi.NewUsernameValidator(),
-				registerapi.NewPasswordValidator(),
+				registerapi.NewUsernameValidator(reservedUsernames...),
+				registerapi.NewPasswordValidator(minPwdScore),
 			),
+			captchaVerifier,
 			inviteDecoder,
-			registerapi.NewPasswordHasher(),
-			usertbl.NewInserter(db),
+			teamRetriever,
+			teamUpdater,
+			defaultQuota,
+			pwdHasher,
+			userRetriever,
+			userInserter,
 			authEncoder,
+			userUpdater,
+			clock.System{},
 			log,
 		),
-	}))
+	}).Use(api.Idempotent(idemStore, log)))
 
 	mux.Handle("/login", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: loginapi.NewPostHandler(
 			loginapi.NewValidator(),
-			usertbl.NewRetriever(db),
-			loginapi.NewPasswordComparator(),
+			captchaVerifier,
+			userRetriever,
+			userByEmail,
+			pwdHasher,
+			pwdHasher,
 			authEncoder,
+			userUpdater,
+			clock.System{},
 			log,
 		),
 	}))
 
-	// serve the registered routes
+	// log users in with an OpenID Connect provider if one is configured
+	oidcConfig, err := oidc.ConfigFromEnv()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if oidcConfig.Enabled() {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), 30*time.Second,
+		)
+		provider, err := oidc.Discover(
+			ctx, oidcConfig, clock.System{},
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		cancel()
+		if err != nil {
+			log.Error(err)
+			return
+		}
+
+		mux.Handle("/oidc/login", api.NewHandler(map[string]api.MethodHandler{
+			http.MethodGet: oidcapi.NewLoginHandler(
+				provider, cookieConfig, log,
+			),
+		}))
+
+		mux.Handle("/oidc/callback", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodGet: oidcapi.NewCallbackHandler(
+					provider,
+					registerapi.NewUsernameValidator(),
+					oidcConfig.TeamID,
+					teamRetriever,
+					teamUpdater,
+					defaultQuota,
+					userRetriever,
+					userInserter,
+					authEncoder,
+					userUpdater,
+					cookieConfig,
+					clientOrigin,
+					clock.System{},
+					log,
+				),
+			},
+		))
+	}
+
+	mux.Handle("/user/favorites", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPatch: api.Authed(
+				authDecoder, log,
+				favoritesapi.NewPatchHandler(
+					validator.ID, userRetriever, userUpdater, log,
+				),
+			),
+		},
+	))
+
+	mux.Handle("/user/avatar", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPost: api.Authed(
+				authDecoder, log, avatarapi.NewPostHandler(
+					userRetriever, avatarInserter, userUpdater, log,
+				),
+			),
+			http.MethodDelete: api.Authed(
+				authDecoder, log,
+				avatarapi.NewDeleteHandler(
+					userRetriever, avatarDeleter, userUpdater, log,
+				),
+			),
+		},
+	))
+
+	mux.Handle("/users/{username}/avatar", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.Authed(
+				authDecoder, log, avatarapi.NewGetHandler(
+					userRetriever, avatarRetriever, log,
+				),
+			),
+		},
+	))
+
+	mux.Handle("/user/sessions", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.Authed(
+				authDecoder, log, sessionapi.NewGetHandler(
+					userRetriever, clock.System{}, log,
+				),
+			),
+		},
+	))
+
+	mux.Handle("/user/sessions/{sessionID}", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodDelete: api.Authed(
+				authDecoder, log,
+				sessionapi.NewDeleteHandler(
+					userRetriever, userUpdater, clock.System{}, log,
+				),
+			),
+		},
+	))
+
+	// provision and deprovision the members of the teams that set a SCIM
+	// token from their identity provider
+	scimAuthorizer := scimapi.NewTokenAuthorizer(teamRetriever)
+
+	mux.Handle("/scim/{teamID}/v2/Users", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: scimapi.NewListHandler(
+				scimAuthorizer, userRetriever, log,
+			),
+			http.MethodPost: scimapi.NewPostHandler(
+				scimAuthorizer,
+				registerapi.NewUsernameValidator(),
+				teamUpdater,
+				defaultQuota,
+				userRetriever,
+				userInserter,
+				userUpdater,
+				log,
+			),
+		},
+	))
+
+	mux.Handle("/scim/{teamID}/v2/Users/{id}", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: scimapi.NewGetHandler(
+				scimAuthorizer, userRetriever, log,
+			),
+			http.MethodPut: scimapi.NewPutHandler(
+				scimAuthorizer, userRetriever, userUpdater, log,
+			),
+			http.MethodPatch: scimapi.NewPatchHandler(
+				scimAuthorizer, userRetriever, userUpdater, log,
+			),
+			http.MethodDelete: scimapi.NewDeleteHandler(
+				scimAuthorizer, userRetriever, userUpdater, teamUpdater, log,
+			),
+		},
+	))
+
+	// serve the admin routes only if an admin token was set
+	if adminToken := os.Getenv(envAdminToken); adminToken != "" {
+		if len(adminToken) < minAdminTokenLen {
+			log.Error(envAdminToken, "must be at least", minAdminTokenLen,
+				"characters long",
+			)
+			return
+		}
+		authorizer := adminapi.NewTokenAuthorizer(adminToken)
+
+		mux.Handle("/admin/users", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodGet: adminapi.NewGetHandler(
+					authorizer, userLister, clock.System{}, log,
+				),
+			},
+		))
+
+		mux.Handle("/admin/users/{username}", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodPatch: adminapi.NewPatchHandler(
+					authorizer, userRetriever, userUpdater, log,
+				),
+			},
+		))
+
+		mux.Handle("/admin/users/{username}/password-reset", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodPost: adminapi.NewPostHandler(
+					authorizer, userRetriever, pwdHasher, userUpdater, log,
+				),
+			},
+		))
+	}
+
+	// translate the error messages of the registered routes into the client's
+	// preferred language, reject cross-site requests to them, and fail fast on
+	// them while the database is down
+	root := http.NewServeMux()
+	root.Handle("/", api.Chain(
+		api.Localize, api.CSRF(clientOrigin), api.FailFast(dbBreaker),
+	)(mux))
+
+	// log the members of teams in with their team's SAML identity provider if
+	// a base URL is set for the service providers - the identity providers
+	// post their responses from their own sites, so these routes are served
+	// without the CSRF check
+	if baseURL := os.Getenv(envSAMLBaseURL); baseURL != "" {
+		serviceProvider := samlapi.NewServiceProvider(baseURL)
+		sso := api.NewRouter()
+
+		sso.Handle("/sso/{teamID}/metadata", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodGet: samlapi.NewMetadataHandler(
+					serviceProvider, log,
+				),
+			},
+		))
+
+		sso.Handle("/sso/{teamID}/login", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodGet: samlapi.NewLoginHandler(
+					serviceProvider,
+					teamRetriever,
+					clientOrigin,
+					clock.System{},
+					log,
+				),
+			},
+		))
+
+		sso.Handle("/sso/{teamID}/acs", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodPost: samlapi.NewACSHandler(
+					serviceProvider,
+					registerapi.NewUsernameValidator(),
+					teamRetriever,
+					teamUpdater,
+					defaultQuota,
+					userRetriever,
+					userInserter,
+					authEncoder,
+					userUpdater,
+					clientOrigin,
+					clock.System{},
+					log,
+				),
+			},
+		))
+
+		root.Handle("/sso/", api.FailFast(dbBreaker)(sso))
+	}
+
+	// serve the API documentation
+	root.Handle("/openapi.json", openapi.NewSpecHandler(apidoc.Spec))
+	root.Handle("/docs", openapi.NewDocsHandler("/openapi.json"))
+
+	// serve the registered routes, logging a line for each request and
+	// recovering from the panics in handling them
 	log.Info("running user service on port", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := serverConfig.NewServer(
+		":"+port, api.Chain(
+			api.AccessLog(clock.System{}, log),
+			api.Recover(reporter, consoleLog),
+		)(root),
+	).ListenAndServe(); err != nil {
 		log.Fatal(err)
 		return
 	}
 }
+
+// parseHashParams returns the password hashing parameters set in environment
+// variables, defaulting to pwdhash.DefaultParams for the ones not set.
+func parseHashParams() (pwdhash.Params, error) {
+	params := pwdhash.DefaultParams()
+	if alg := os.Getenv(envPwdHashAlgorithm); alg != "" {
+		params.Algorithm = pwdhash.Algorithm(alg)
+	}
+	for _, v := range []struct {
+		env  string
+		bits int
+		set  func(uint64)
+	}{
+		{envBcryptCost, 8, func(n uint64) { params.BcryptCost = int(n) }},
+		{envArgon2Time, 32, func(n uint64) { params.Argon2Time = uint32(n) }},
+		{
+			envArgon2MemoryKiB, 32,
+			func(n uint64) { params.Argon2Memory = uint32(n) },
+		},
+		{
+			envArgon2Threads, 8,
+			func(n uint64) { params.Argon2Threads = uint8(n) },
+		},
+	} {
+		raw := os.Getenv(v.env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(raw, 10, v.bits)
+		if err != nil {
+			return params, fmt.Errorf("%s was invalid: %w", v.env, err)
+		}
+		v.set(n)
+	}
+	return params, params.Validate()
+}
+
+// parsePeppers parses the password peppers from a comma separated list of
+// id:secret pairs.
+func parsePeppers(raw string) ([]pwdhash.Pepper, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var peppers []pwdhash.Pepper
+	for _, pair := range strings.Split(raw, ",") {
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" || strings.Contains(id, "$") {
+			return nil, fmt.Errorf(
+				"%s must be a comma separated list of id:secret pairs with "+
+					"no $ in the ids", envPwdPeppers,
+			)
+		}
+		peppers = append(peppers, pwdhash.Pepper{ID: id, Key: []byte(secret)})
+	}
+	return peppers, nil
+}
+
+// newCaptchaVerifier returns the CAPTCHA verifier for the given provider, or
+// captcha.Disabled if no provider was given.
+func newCaptchaVerifier(provider, secret string) (captcha.Verifier, error) {
+	var url string
+	switch provider {
+	case "":
+		return captcha.Disabled{}, nil
+	case "hcaptcha":
+		url = captcha.HCaptchaURL
+	case "turnstile":
+		url = captcha.TurnstileURL
+	default:
+		return nil, fmt.Errorf(
+			"%s must be hcaptcha or turnstile", envCaptchaProvider,
+		)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf(
+			"%s must be set when %s is", envCaptchaSecretKey, envCaptchaProvider,
+		)
+	}
+	return captcha.NewSiteVerifier(
+		url, secret, &http.Client{Timeout: 5 * time.Second},
+	), nil
+}
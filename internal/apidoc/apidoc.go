@@ -0,0 +1,1317 @@
+// Package apidoc contains the OpenAPI document describing the routes of all
+// services, generated from the request and response types of their handlers.
+package apidoc
+
+//go:generate go run ../../cmd/apidocgen -o openapi.json
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/kxplxn/goteam/internal/tasksvc/historyapi"
+	"github.com/kxplxn/goteam/internal/tasksvc/taskapi"
+	"github.com/kxplxn/goteam/internal/tasksvc/tasksapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/analyticsapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/auditapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/billingapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/checklistapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/graphqlapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/inviteapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/membersapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/publicapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/scimtokenapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/searchapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/shareapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/slackapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/sprintapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/ssoapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/teamapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/trashapi"
+	"github.com/kxplxn/goteam/internal/usersvc/adminapi"
+	"github.com/kxplxn/goteam/internal/usersvc/avatarapi"
+	"github.com/kxplxn/goteam/internal/usersvc/favoritesapi"
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/internal/usersvc/scimapi"
+	"github.com/kxplxn/goteam/internal/usersvc/sessionapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/openapi"
+)
+
+// Spec is the generated OpenAPI document. Run go generate on this package to
+// update it after changing any of the routes or their types.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// authScheme is the name of the cookie auth security scheme.
+const authScheme = "authCookie"
+
+// adminScheme is the name of the admin token security scheme.
+const adminScheme = "adminToken"
+
+// scimScheme is the name of the team SCIM token security scheme.
+const scimScheme = "scimToken"
+
+// Generate generates the OpenAPI document for all routes and returns it as
+// indented JSON.
+func Generate() ([]byte, error) {
+	b, err := json.MarshalIndent(doc(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// doc builds the OpenAPI document for all routes.
+func doc() openapi.Doc {
+	return withCSRF(openapi.Doc{
+		OpenAPI: openapi.Version,
+		Info:    openapi.Info{Title: "GoTeam! API", Version: "1.0.0"},
+		Paths: map[string]openapi.PathItem{
+			"/register": {
+				"post": idempotent(openapi.Operation{
+					Summary: "Register a new user.",
+					Tags:    []string{"user"},
+					Parameters: []openapi.Parameter{
+						query("inviteToken", false),
+					},
+					RequestBody: body(registerapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "User registered. The CSRF token " +
+								"to send with mutating requests is in the " +
+								"X-CSRF-Token header.",
+						},
+						"400": {
+							Description: "Invalid request, the CAPTCHA " +
+								"failed (code captchaFailed), the invite " +
+								"token is invalid, expired, or already used, " +
+								"or the team has reached its member limit.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(registerapi.PostResp{}),
+							),
+						},
+						"502": {
+							Description: "The CAPTCHA could not be verified.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(registerapi.PostResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/login": {
+				"post": {
+					Summary: "Log in with a username or verified email " +
+						"and receive an auth cookie.",
+					Tags:        []string{"user"},
+					RequestBody: body(loginapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "Logged in. The CSRF token to send " +
+								"with mutating requests is in the " +
+								"X-CSRF-Token header.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(loginapi.PostResp{}),
+							),
+						},
+						"400": {
+							Description: "Invalid credentials, an email " +
+								"shared by several accounts, or the " +
+								"CAPTCHA failed (code captchaFailed).",
+							Content: openapi.JSON(
+								openapi.SchemaOf(loginapi.PostResp{}),
+							),
+						},
+						"502": {
+							Description: "The CAPTCHA could not be verified.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(loginapi.PostResp{}),
+							),
+						},
+					}),
+				},
+			},
+			"/oidc/login": {
+				"get": {
+					Summary: "Log in with the OpenID Connect provider, if " +
+						"one is configured.",
+					Tags: []string{"user"},
+					Responses: responses(map[string]openapi.Response{
+						"302": {
+							Description: "Redirect to the provider, which " +
+								"sends the user back to /oidc/callback.",
+						},
+					}),
+				},
+			},
+			"/oidc/callback": {
+				"get": {
+					Summary: "Complete a login with the OpenID Connect " +
+						"provider, registering the user if they log in for " +
+						"the first time.",
+					Tags: []string{"user"},
+					Parameters: []openapi.Parameter{
+						query("state", true), query("code", false),
+						query("error", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"303": {
+							Description: "Logged in and redirected to the " +
+								"client, or redirected to the client's " +
+								"login page with the error query parameter " +
+								"set to ssoFailed, ssoUsername, ssoDisabled, " +
+								"or ssoTeamFull.",
+						},
+					}),
+				},
+			},
+			"/sso/{teamID}/metadata": {
+				"get": {
+					Summary: "Get the SAML metadata of the team's service " +
+						"provider, if SAML is configured, to set up the " +
+						"team's identity provider with.",
+					Tags:       []string{"user"},
+					Parameters: []openapi.Parameter{path("teamID")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The service provider metadata, " +
+								"as application/samlmetadata+xml.",
+						},
+					}),
+				},
+			},
+			"/sso/{teamID}/login": {
+				"get": {
+					Summary: "Log in with the team's SAML identity " +
+						"provider.",
+					Tags:       []string{"user"},
+					Parameters: []openapi.Parameter{path("teamID")},
+					Responses: responses(map[string]openapi.Response{
+						"302": {
+							Description: "Redirect to the identity " +
+								"provider, which posts the user back to " +
+								"/sso/{teamID}/acs.",
+						},
+						"303": {
+							Description: "Redirect to the client's login " +
+								"page with the error query parameter set " +
+								"to ssoNotConfigured or ssoFailed.",
+						},
+					}),
+				},
+			},
+			"/sso/{teamID}/acs": {
+				"post": {
+					Summary: "Complete a login with the team's SAML " +
+						"identity provider, adding the user to the team " +
+						"if they log in for the first time.",
+					Tags:       []string{"user"},
+					Parameters: []openapi.Parameter{path("teamID")},
+					Responses: responses(map[string]openapi.Response{
+						"303": {
+							Description: "Logged in and redirected to the " +
+								"client, or redirected to the client's " +
+								"login page with the error query parameter " +
+								"set to ssoFailed, ssoNotConfigured, " +
+								"ssoUsername, ssoDisabled, or ssoTeamFull.",
+						},
+					}),
+				},
+			},
+			"/scim/{teamID}/v2/Users": {
+				"get": scim(openapi.Operation{
+					Summary: "List the members of the team, optionally " +
+						"filtered with a userName eq filter. For the " +
+						"team's identity provider.",
+					Tags: []string{"scim"},
+					Parameters: []openapi.Parameter{
+						query("filter", false), query("startIndex", false),
+						query("count", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "A page of the members.",
+							Content:     scimContent(scimapi.ListResp{}),
+						},
+					}),
+				}),
+				"post": scim(openapi.Operation{
+					Summary: "Provision a user as a member of the team. " +
+						"For the team's identity provider.",
+					Tags: []string{"scim"},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content:  scimContent(scimapi.PostReq{}),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"201": {
+							Description: "The provisioned user.",
+							Content:     scimContent(scimapi.User{}),
+						},
+						"403": errResp("Team has reached its member limit."),
+						"409": errResp(
+							"Username is taken, or the team was modified " +
+								"concurrently.",
+						),
+					}),
+				}),
+			},
+			"/scim/{teamID}/v2/Users/{id}": {
+				"get": scim(openapi.Operation{
+					Summary:    "Get a member of the team.",
+					Tags:       []string{"scim"},
+					Parameters: []openapi.Parameter{path("id")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The member.",
+							Content:     scimContent(scimapi.User{}),
+						},
+					}),
+				}),
+				"put": scim(openapi.Operation{
+					Summary: "Activate or deactivate a member of the " +
+						"team. Deactivating a member also signs them out.",
+					Tags:       []string{"scim"},
+					Parameters: []openapi.Parameter{path("id")},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content:  scimContent(scimapi.PutReq{}),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The member.",
+							Content:     scimContent(scimapi.User{}),
+						},
+					}),
+				}),
+				"patch": scim(openapi.Operation{
+					Summary: "Activate or deactivate a member of the " +
+						"team with a patch of their active attribute.",
+					Tags:       []string{"scim"},
+					Parameters: []openapi.Parameter{path("id")},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content:  scimContent(scimapi.PatchReq{}),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The member.",
+							Content:     scimContent(scimapi.User{}),
+						},
+					}),
+				}),
+				"delete": scim(openapi.Operation{
+					Summary: "Deprovision a member, deactivating them and " +
+						"removing them from the team and its boards.",
+					Tags:       []string{"scim"},
+					Parameters: []openapi.Parameter{path("id")},
+					Responses: responses(map[string]openapi.Response{
+						"204": {Description: "Deprovisioned."},
+						"409": errResp(
+							"Team was modified concurrently.",
+						),
+					}),
+				}),
+			},
+			"/user/favorites": {
+				"patch": authed(openapi.Operation{
+					Summary:     "Star or unstar a board.",
+					Tags:        []string{"user"},
+					RequestBody: body(favoritesapi.PatchReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The user's starred board IDs.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(favoritesapi.PatchResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/user/avatar": {
+				"post": authed(openapi.Operation{
+					Summary: "Upload a PNG, JPEG, or GIF image of up to 2 " +
+						"MB as the user's avatar, which is cropped to a " +
+						"square and scaled to 128x128 pixels.",
+					Tags: []string{"user"},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content: map[string]openapi.MediaType{
+							"multipart/form-data": {
+								Schema: &openapi.Schema{
+									Type: "object",
+									Properties: map[string]*openapi.Schema{
+										"avatar": {
+											Type:   "string",
+											Format: "binary",
+										},
+									},
+								},
+							},
+						},
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The URL of the uploaded avatar.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(avatarapi.PostResp{}),
+							),
+						},
+					}),
+				}),
+				"delete": authed(openapi.Operation{
+					Summary: "Remove the user's avatar, reverting to " +
+						"their identicon.",
+					Tags: []string{"user"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The URL of the user's identicon.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(avatarapi.DeleteResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/users/{username}/avatar": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the avatar of a member of the user's " +
+						"team, or their identicon if they have not " +
+						"uploaded one. Guests can only get their own.",
+					Tags:       []string{"user"},
+					Parameters: []openapi.Parameter{path("username")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The PNG-encoded avatar.",
+							Content: map[string]openapi.MediaType{
+								"image/png": {Schema: &openapi.Schema{
+									Type: "string", Format: "binary",
+								}},
+							},
+						},
+						"304": {
+							Description: "The avatar matches the " +
+								"If-None-Match header.",
+						},
+					}),
+				}),
+			},
+			"/user/sessions": {
+				"get": authed(openapi.Operation{
+					Summary: "List the sessions the user is logged in with.",
+					Tags:    []string{"user"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The user's unexpired sessions.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(sessionapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/user/sessions/{sessionID}": {
+				"delete": authed(openapi.Operation{
+					Summary: "Revoke a session, signing out the device it " +
+						"was issued to.",
+					Tags:       []string{"user"},
+					Parameters: []openapi.Parameter{path("sessionID")},
+					Responses:  responses(nil),
+				}),
+			},
+			"/team": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the user's team.",
+					Tags:    []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The user's team.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(teamapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/team/invite": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary: "Email an invite link to join the team, or to " +
+						"join only the given boards as a guest.",
+					Tags:        []string{"team"},
+					RequestBody: body(inviteapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						statusKey(http.StatusBadGateway): errResp(
+							"Invite email could not be sent.",
+						),
+					}),
+				})),
+			},
+			"/team/members": {
+				"get": authed(openapi.Operation{
+					Summary: "List the team's members.",
+					Tags:    []string{"team"},
+					Parameters: []openapi.Parameter{
+						query("q", false),
+						query("limit", false),
+						query("cursor", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The team's members ordered by " +
+								"username.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(membersapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/team/analytics": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the tasks completed per day, the cycle " +
+						"time, and the tasks in each column at the end " +
+						"of each day on the boards the user can see, from " +
+						"the from date to the to date inclusive. The range " +
+						"defaults to the last 14 days and cannot be longer " +
+						"than 90 days.",
+					Tags: []string{"team"},
+					Parameters: []openapi.Parameter{
+						query("from", false),
+						query("to", false),
+						query("boardID", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The team's analytics.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(analyticsapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/team/audit": {
+				"get": authed(openapi.Operation{
+					Summary: "List the privileged actions taken in the team. " +
+						"Admins only.",
+					Tags: []string{"team"},
+					Parameters: []openapi.Parameter{
+						query("limit", false),
+						query("cursor", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The team's audit log, newest " +
+								"first.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(auditapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/team/slack": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the team's Slack integration settings.",
+					Tags:    []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The Slack integration settings.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(slackapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"put": authed(openapi.Operation{
+					Summary:     "Set the team's Slack integration settings.",
+					Tags:        []string{"team"},
+					RequestBody: body(slackapi.PutReq{}),
+					Responses:   responses(conflict()),
+				}),
+			},
+			"/team/sso": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the team's SAML single sign-on settings.",
+					Tags:    []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The SAML single sign-on settings.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(ssoapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"put": authed(openapi.Operation{
+					Summary: "Set the team's SAML single sign-on settings " +
+						"from the metadata of its identity provider.",
+					Tags:        []string{"team"},
+					RequestBody: body(ssoapi.PutReq{}),
+					Responses:   responses(conflict()),
+				}),
+			},
+			"/team/scim-token": {
+				"post": authed(openapi.Operation{
+					Summary: "Generate a new SCIM token for the team's " +
+						"identity provider, replacing the previous one.",
+					Tags: []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The token, which is only " +
+								"returned once.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(scimtokenapi.PostResp{}),
+							),
+						},
+						"409": errResp("Resource was modified concurrently."),
+					}),
+				}),
+				"delete": authed(openapi.Operation{
+					Summary: "Revoke the team's SCIM token, disabling " +
+						"SCIM provisioning.",
+					Tags:      []string{"team"},
+					Responses: responses(conflict()),
+				}),
+			},
+			"/team/sprint": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the sprints on the boards the user can " +
+						"see, ordered by start date.",
+					Tags:       []string{"sprint"},
+					Parameters: []openapi.Parameter{query("boardID", false)},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The sprints.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(sprintapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Create a sprint on a board.",
+					Tags:        []string{"sprint"},
+					RequestBody: body(sprintapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"201": {
+							Description: "The ID of the created sprint.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(sprintapi.PostResp{}),
+							),
+						},
+					}),
+				})),
+				"patch": idempotent(authed(openapi.Operation{
+					Summary:     "Update a sprint's board, name and dates.",
+					Tags:        []string{"sprint"},
+					RequestBody: body(sprintapi.PatchReq{}),
+					Responses:   responses(conflict()),
+				})),
+				"delete": idempotent(authed(openapi.Operation{
+					Summary:    "Delete a sprint.",
+					Tags:       []string{"sprint"},
+					Parameters: []openapi.Parameter{query("id", true)},
+					Responses:  responses(conflict()),
+				})),
+			},
+			"/team/checklist": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the team's checklist templates in the " +
+						"order they were created.",
+					Tags: []string{"checklist"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The checklist templates.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(checklistapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Create a checklist template.",
+					Tags:        []string{"checklist"},
+					RequestBody: body(checklistapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"201": {
+							Description: "The ID of the created checklist.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(checklistapi.PostResp{}),
+							),
+						},
+					}),
+				})),
+				"patch": idempotent(authed(openapi.Operation{
+					Summary:     "Replace a checklist's name and items.",
+					Tags:        []string{"checklist"},
+					RequestBody: body(checklistapi.PatchReq{}),
+					Responses:   responses(conflict()),
+				})),
+				"delete": idempotent(authed(openapi.Operation{
+					Summary:    "Delete a checklist template.",
+					Tags:       []string{"checklist"},
+					Parameters: []openapi.Parameter{query("id", true)},
+					Responses:  responses(conflict()),
+				})),
+			},
+			"/team/billing/checkout": {
+				"post": authed(openapi.Operation{
+					Summary: "Start a checkout to subscribe the team to the " +
+						"paid plan. Only served if billing is configured.",
+					Tags: []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The URL of the checkout page.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(billingapi.CheckoutResp{}),
+							),
+						},
+						"400": errResp("Team is already on the paid plan."),
+						"502": errResp("Checkout could not be started."),
+					}),
+				}),
+			},
+			"/team/billing/webhook": {
+				"post": {
+					Summary: "Receive subscription events from Stripe. Only " +
+						"served if billing is configured.",
+					Tags: []string{"team"},
+					Parameters: []openapi.Parameter{{
+						Name:     "Stripe-Signature",
+						In:       "header",
+						Required: true,
+						Schema:   &openapi.Schema{Type: "string"},
+					}},
+					Responses: map[string]openapi.Response{
+						"200": {Description: "Event applied or ignored."},
+						"400": {
+							Description: "Invalid signature or payload.",
+						},
+						"409": {
+							Description: "Team was modified concurrently. " +
+								"Stripe retries the event.",
+						},
+						"500": {Description: "Unexpected error."},
+					},
+				},
+			},
+			"/team/trash": {
+				"get": authed(openapi.Operation{
+					Summary: "List the team's deleted boards and tasks.",
+					Tags:    []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The deleted boards and tasks that " +
+								"can still be restored.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(trashapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/team/trash/restore": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Restore a deleted board or task.",
+					Tags:        []string{"team"},
+					RequestBody: body(trashapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						statusKey(http.StatusConflict): errResp(
+							"Item already exists, or the task's board is " +
+								"deleted.",
+						),
+					}),
+				})),
+			},
+			"/undo": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary: "Undo the caller's most recent board delete, " +
+						"task delete, or move of tasks, made within the " +
+						"last five minutes.",
+					Tags: []string{"team"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "What was restored.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(trashapi.UndoResp{}),
+							),
+						},
+						statusKey(http.StatusConflict): errResp(
+							"Restored item already exists, the task's board " +
+								"is deleted, or the moved tasks have been " +
+								"changed since.",
+						),
+					}),
+				})),
+			},
+			"/search": {
+				"get": authed(openapi.Operation{
+					Summary: "Search the names and descriptions of the " +
+						"boards the user can see, the titles and " +
+						"descriptions of their tasks, and the usernames of " +
+						"the team's members, returning up to 20 of each.",
+					Tags:       []string{"team"},
+					Parameters: []openapi.Parameter{query("q", true)},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The matching boards, tasks, and " +
+								"members.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(searchapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/admin/users": {
+				"get": admin(openapi.Operation{
+					Summary: "List all users. Operators only.",
+					Tags:    []string{"admin"},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "All users.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(adminapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/admin/users/{username}": {
+				"patch": admin(openapi.Operation{
+					Summary: "Disable or enable a user. Disabling a user " +
+						"also signs them out. Operators only.",
+					Tags:        []string{"admin"},
+					Parameters:  []openapi.Parameter{path("username")},
+					RequestBody: body(adminapi.PatchReq{}),
+					Responses:   responses(nil),
+				}),
+			},
+			"/admin/users/{username}/password-reset": {
+				"post": admin(openapi.Operation{
+					Summary: "Replace a user's password with a temporary " +
+						"one and sign them out. Operators only.",
+					Tags:       []string{"admin"},
+					Parameters: []openapi.Parameter{path("username")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The temporary password.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(adminapi.PostResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/graphql": {
+				"post": authed(openapi.Operation{
+					Summary:     "Query the team, boards, members, and tasks.",
+					Tags:        []string{"team"},
+					RequestBody: body(graphqlapi.PostReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The query result.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(graphqlapi.PostResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/boards": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Create a board.",
+					Tags:        []string{"board"},
+					RequestBody: body(boardapi.PostReq{}),
+					Responses:   responses(nil),
+				})),
+			},
+			"/boards/{boardID}": {
+				"patch": authed(openapi.Operation{
+					Summary:     "Update a board's name, members and columns.",
+					Tags:        []string{"board"},
+					Parameters:  []openapi.Parameter{path("boardID")},
+					RequestBody: body(boardapi.PatchReq{}),
+					Responses:   responses(conflict()),
+				}),
+				"delete": authed(openapi.Operation{
+					Summary:    "Delete a board, moving it to the trash.",
+					Tags:       []string{"board"},
+					Parameters: []openapi.Parameter{path("boardID")},
+					Responses:  responses(conflict()),
+				}),
+			},
+			"/boards/import": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Import a board from an export document.",
+					Tags:        []string{"board"},
+					RequestBody: body(boardapi.ImportReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "Board imported. Maps the IDs in " +
+								"the document to the IDs created for them.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(boardapi.ImportResp{}),
+							),
+						},
+					}),
+				})),
+			},
+			"/boards/{boardID}/export": {
+				"get": authed(openapi.Operation{
+					Summary:    "Export a board with its tasks as JSON.",
+					Tags:       []string{"board"},
+					Parameters: []openapi.Parameter{path("boardID")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The board export document.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(boardapi.ExportResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/boards/{boardID}/share": {
+				"post": authed(openapi.Operation{
+					Summary: "Share a board through a read-only link, or " +
+						"get the share token of a board already shared.",
+					Tags:       []string{"board"},
+					Parameters: []openapi.Parameter{path("boardID")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The share token, to be passed to " +
+								"/public/board.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(shareapi.PostResp{}),
+							),
+						},
+						"409": errResp("Resource was modified concurrently."),
+					}),
+				}),
+				"delete": authed(openapi.Operation{
+					Summary:    "Unshare a board, revoking its share token.",
+					Tags:       []string{"board"},
+					Parameters: []openapi.Parameter{path("boardID")},
+					Responses:  responses(conflict()),
+				}),
+			},
+			"/public/board": {
+				"get": {
+					Summary: "Get a shared board with its tasks, without " +
+						"its members.",
+					Tags:       []string{"board"},
+					Parameters: []openapi.Parameter{query("token", true)},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The shared board.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(publicapi.GetResp{}),
+							),
+						},
+						"404": errResp(
+							"Share link is invalid or was revoked.",
+						),
+					}),
+				},
+			},
+			"/board": {
+				"post": deprecated("Create a board.", "board",
+					body(boardapi.PostReq{}),
+				),
+				"patch": deprecated("Update a board.", "board",
+					body(boardapi.PatchReq{}), query("id", true),
+				),
+				"delete": deprecated("Delete a board.", "board",
+					nil, query("id", true),
+				),
+			},
+			"/tasks": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the tasks of the team's first board.",
+					Tags:    []string{"task"},
+					Parameters: []openapi.Parameter{
+						query("boardID", false),
+						query("column", false),
+						query("sort", false),
+						query("order", false),
+						query("limit", false),
+						query("cursor", false),
+						query("render", false),
+						query("archived", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The tasks.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(tasksapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"patch": authed(openapi.Operation{
+					Summary: "Update the order and columns of tasks. " +
+						"Use /tasks/{taskID}/move to move a task instead.",
+					Tags:        []string{"task"},
+					Deprecated:  true,
+					RequestBody: body(tasksapi.PatchReq{}),
+					Responses:   responses(nil),
+				}),
+			},
+			"/tasks/{taskID}": {
+				"patch": authed(openapi.Operation{
+					Summary:     "Update a task.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("taskID")},
+					RequestBody: body(taskapi.PatchReq{}),
+					Responses:   responses(blockedResps()),
+				}),
+				"delete": authed(openapi.Operation{
+					Summary:    "Delete a task, moving it to the trash.",
+					Tags:       []string{"task"},
+					Parameters: []openapi.Parameter{path("taskID")},
+					Responses:  responses(nil),
+				}),
+			},
+			"/tasks/{taskID}/move": {
+				"post": authed(openapi.Operation{
+					Summary: "Move a task to a position in a column of its " +
+						"board, updating the order of the column atomically.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("taskID")},
+					RequestBody: body(taskapi.MoveReq{}),
+					Responses:   responses(blockedResps()),
+				}),
+			},
+			"/tasks/{taskID}/blockers": {
+				"put": authed(openapi.Operation{
+					Summary: "Set the tasks on the same board that block a " +
+						"task, which cannot block it in a cycle.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("taskID")},
+					RequestBody: body(taskapi.BlockersReq{}),
+					Responses:   responses(nil),
+				}),
+			},
+			"/tasks/{taskID}/checklist": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary: "Add the items of a checklist template to the " +
+						"subtasks of a task, skipping the ones it has.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("taskID")},
+					RequestBody: body(taskapi.ChecklistReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The task with its new subtasks.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(taskapi.ChecklistResp{}),
+							),
+						},
+					}),
+				})),
+			},
+			"/tasks/{taskID}/duplicate": {
+				"post": idempotent(authed(openapi.Operation{
+					Summary: "Copy a task with its subtasks not done to the " +
+						"end of its column or of the requested one.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("taskID")},
+					RequestBody: body(taskapi.DuplicateReq{}),
+					Responses: responses(map[string]openapi.Response{
+						"201": {
+							Description: "The created copy.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(taskapi.DuplicateResp{}),
+							),
+						},
+					}),
+				})),
+			},
+			"/tasks/{taskID}/history": {
+				"get": authed(openapi.Operation{
+					Summary:    "Get the edit history of a task, newest first.",
+					Tags:       []string{"task"},
+					Parameters: []openapi.Parameter{path("taskID")},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The edits made to the task.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(historyapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+			},
+			"/boards/{boardID}/tasks": {
+				"get": authed(openapi.Operation{
+					Summary: "Get the tasks of a board.",
+					Tags:    []string{"task"},
+					Parameters: []openapi.Parameter{
+						path("boardID"),
+						query("column", false),
+						query("sort", false),
+						query("order", false),
+						query("limit", false),
+						query("cursor", false),
+						query("render", false),
+						query("archived", false),
+					},
+					Responses: responses(map[string]openapi.Response{
+						"200": {
+							Description: "The tasks.",
+							Content: openapi.JSON(
+								openapi.SchemaOf(tasksapi.GetResp{}),
+							),
+						},
+					}),
+				}),
+				"post": idempotent(authed(openapi.Operation{
+					Summary:     "Create a task on a board.",
+					Tags:        []string{"task"},
+					Parameters:  []openapi.Parameter{path("boardID")},
+					RequestBody: body(taskapi.PostReq{}),
+					Responses:   responses(nil),
+				})),
+			},
+			"/task": {
+				"post": deprecated("Create a task.", "task",
+					body(taskapi.PostReq{}),
+				),
+				"patch": deprecated("Update a task.", "task",
+					body(taskapi.PatchReq{}),
+				),
+				"delete": deprecated("Delete a task.", "task",
+					nil, query("id", true),
+				),
+			},
+			"/task/history": {
+				"get": deprecated("Get the edit history of a task.", "task",
+					nil, query("id", true),
+				),
+			},
+		},
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Error": openapi.SchemaOf(struct {
+					Error string `json:"error"`
+				}{}),
+			},
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				authScheme: {
+					Type: "apiKey", In: "cookie", Name: cookie.AuthName,
+				},
+				adminScheme: {Type: "http", Scheme: "bearer"},
+				scimScheme:  {Type: "http", Scheme: "bearer"},
+			},
+		},
+	})
+}
+
+// withCSRF returns the given document with the CSRF token header added to the
+// mutating operations that require the auth cookie.
+func withCSRF(d openapi.Doc) openapi.Doc {
+	for _, item := range d.Paths {
+		for method, op := range item {
+			if method == "get" || len(op.Security) == 0 {
+				continue
+			}
+			if _, ok := op.Security[0][authScheme]; !ok {
+				continue
+			}
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:     api.CSRFHeader,
+				In:       "header",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+			op.Responses[statusKey(http.StatusForbidden)] = errResp(
+				"User is not allowed to perform this action, or the CSRF " +
+					"token is missing or invalid.",
+			)
+			item[method] = op
+		}
+	}
+	return d
+}
+
+// authed returns the given operation with the cookie auth scheme required and
+// the responses for failed authentication added.
+func authed(op openapi.Operation) openapi.Operation {
+	op.Security = []map[string][]string{{authScheme: {}}}
+	for code, desc := range map[int]string{
+		http.StatusUnauthorized: "Auth token not found or invalid.",
+		http.StatusForbidden:    "User is not allowed to perform this action.",
+	} {
+		op.Responses[statusKey(code)] = errResp(desc)
+	}
+	return op
+}
+
+// admin returns the given operation with the admin token scheme required and
+// the response for requests without it added.
+func admin(op openapi.Operation) openapi.Operation {
+	op.Security = []map[string][]string{{adminScheme: {}}}
+	op.Responses[statusKey(http.StatusUnauthorized)] = openapi.Response{
+		Description: "Admin token not found or invalid.",
+	}
+	return op
+}
+
+// scim returns the given operation with the team ID path parameter and the
+// SCIM token scheme required, the response for requests without the token
+// added, and the error responses of the handlers described as SCIM errors.
+// The service unavailable response is written before the handlers are
+// reached, so it keeps the error envelope.
+func scim(op openapi.Operation) openapi.Operation {
+	op.Security = []map[string][]string{{scimScheme: {}}}
+	op.Parameters = append(
+		[]openapi.Parameter{path("teamID")}, op.Parameters...,
+	)
+	op.Responses[statusKey(http.StatusUnauthorized)] = errResp(
+		"SCIM token not found or invalid.",
+	)
+	unavailable := statusKey(http.StatusServiceUnavailable)
+	for code, r := range op.Responses {
+		if _, ok := r.Content["application/json"]; ok && code != unavailable {
+			r.Content = scimContent(scimapi.Error{})
+			op.Responses[code] = r
+		}
+	}
+	return op
+}
+
+// scimContent returns the SCIM JSON content with the schema of the given
+// value.
+func scimContent(v any) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{
+		"application/scim+json": {Schema: openapi.SchemaOf(v)},
+	}
+}
+
+// idempotent returns the given operation with the optional Idempotency-Key
+// header and the responses for reused keys added.
+func idempotent(op openapi.Operation) openapi.Operation {
+	op.Parameters = append(op.Parameters, openapi.Parameter{
+		Name:   "Idempotency-Key",
+		In:     "header",
+		Schema: &openapi.Schema{Type: "string"},
+	})
+	for code, desc := range map[int]string{
+		http.StatusConflict: "A request with the same key is in progress.",
+		http.StatusUnprocessableEntity: "Key was already used for a " +
+			"different request.",
+	} {
+		op.Responses[statusKey(code)] = errResp(desc)
+	}
+	return op
+}
+
+// deprecated returns an authenticated operation for a deprecated alias route.
+func deprecated(
+	summary, tag string, req *openapi.RequestBody, params ...openapi.Parameter,
+) openapi.Operation {
+	return authed(openapi.Operation{
+		Summary:     summary,
+		Tags:        []string{tag},
+		Deprecated:  true,
+		Parameters:  params,
+		RequestBody: req,
+		Responses:   responses(nil),
+	})
+}
+
+// responses returns the given responses with the default success, not found,
+// internal server error, service unavailable and gateway timeout responses
+// added where missing.
+func responses(rs map[string]openapi.Response) map[string]openapi.Response {
+	if rs == nil {
+		rs = map[string]openapi.Response{}
+	}
+	defaults := map[int]openapi.Response{
+		http.StatusOK:                  {Description: "Success."},
+		http.StatusBadRequest:          errResp("Invalid request."),
+		http.StatusNotFound:            errResp("Resource not found."),
+		http.StatusInternalServerError: {Description: "Unexpected error."},
+		http.StatusServiceUnavailable: errResp(
+			"Database is down, retry after the Retry-After header.",
+		),
+		http.StatusGatewayTimeout: {
+			Description: "Database did not respond in time.",
+		},
+	}
+	for code, r := range defaults {
+		if _, ok := rs[statusKey(code)]; !ok {
+			rs[statusKey(code)] = r
+		}
+	}
+	return rs
+}
+
+// conflict returns the response for a write that lost to a concurrent write.
+func conflict() map[string]openapi.Response {
+	return map[string]openapi.Response{
+		statusKey(http.StatusConflict): errResp(
+			"Resource was modified concurrently.",
+		),
+	}
+}
+
+// blockedResps returns the responses for a move of a task into the last
+// column of its board while it is blocked.
+func blockedResps() map[string]openapi.Response {
+	return map[string]openapi.Response{
+		"200": {
+			Description: "Success, with a warning if the task was moved " +
+				"into the last column while it is blocked.",
+			Content: openapi.JSON(openapi.SchemaOf(taskapi.MoveResp{})),
+		},
+		statusKey(http.StatusConflict): errResp(
+			"Task is blocked and its board refuses to move blocked tasks " +
+				"into its last column.",
+		),
+	}
+}
+
+// errResp returns a response with the error envelope as its body.
+func errResp(desc string) openapi.Response {
+	return openapi.Response{
+		Description: desc, Content: openapi.JSON(openapi.Ref("Error")),
+	}
+}
+
+// body returns a required JSON request body with the schema of the given value.
+func body(v any) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: true, Content: openapi.JSON(openapi.SchemaOf(v)),
+	}
+}
+
+// path returns a required string path parameter with the given name.
+func path(name string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:     name,
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string"},
+	}
+}
+
+// query returns a string query parameter with the given name.
+func query(name string, required bool) openapi.Parameter {
+	return openapi.Parameter{
+		Name:     name,
+		In:       "query",
+		Required: required,
+		Schema:   &openapi.Schema{Type: "string"},
+	}
+}
+
+// statusKey returns the key used for a status code in the responses map.
+func statusKey(code int) string { return strconv.Itoa(code) }
@@ -0,0 +1,20 @@
+//go:build utest
+
+package apidoc
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSpec asserts that the embedded OpenAPI document is in sync with the
+// routes and their types.
+func TestSpec(t *testing.T) {
+	spec, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(spec, Spec) {
+		t.Error("openapi.json is out of date - run go generate ./internal/apidoc")
+	}
+}
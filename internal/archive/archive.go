@@ -0,0 +1,176 @@
+// Package archive contains the job that archives the tasks that have sat in
+// the last column of their board for longer than the board allows.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+const (
+	// EnvSchedule is the name of the environment variable used for setting the
+	// cron expression that the services schedule the job on.
+	EnvSchedule = "ARCHIVE_SCHEDULE"
+
+	// DefaultSchedule is the schedule of the job if EnvSchedule is empty - at
+	// 03:30 UTC every day.
+	DefaultSchedule = "30 3 * * *"
+)
+
+// Job can be used to archive the tasks that have sat in the last column of
+// their board for longer than the board's ArchiveAfterDays.
+type Job struct {
+	clock         clock.Clock
+	userLister    db.Lister[[]usertbl.User]
+	teamRetriever db.Retriever[teamtbl.Team]
+	tasksByTeam   db.Retriever[[]tasktbl.Task]
+	histByTeam    db.RetrieverDualKey[[]histtbl.Entry]
+	tasksUpdater  db.Updater[[]tasktbl.Task]
+	log           log.Infoer
+}
+
+// NewJob creates and returns a new Job.
+func NewJob(
+	clock clock.Clock,
+	userLister db.Lister[[]usertbl.User],
+	teamRetriever db.Retriever[teamtbl.Team],
+	tasksByTeam db.Retriever[[]tasktbl.Task],
+	histByTeam db.RetrieverDualKey[[]histtbl.Entry],
+	tasksUpdater db.Updater[[]tasktbl.Task],
+	log log.Infoer,
+) Job {
+	return Job{
+		clock:         clock,
+		userLister:    userLister,
+		teamRetriever: teamRetriever,
+		tasksByTeam:   tasksByTeam,
+		histByTeam:    histByTeam,
+		tasksUpdater:  tasksUpdater,
+		log:           log,
+	}
+}
+
+// Run archives the tasks that have sat in the last column of their board for
+// longer than the board allows and returns how many it archived. The time a
+// task moved into the last column is read from its history, so a task with no
+// record of moving there within the allowed days has sat there for longer.
+func (j Job) Run(ctx context.Context) (int, error) {
+	users, err := j.userLister.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var teamIDs []string
+	for _, user := range users {
+		if user.TeamID != "" && !slices.Contains(teamIDs, user.TeamID) {
+			teamIDs = append(teamIDs, user.TeamID)
+		}
+	}
+	slices.Sort(teamIDs)
+
+	var n int
+	for _, teamID := range teamIDs {
+		archived, err := j.runTeam(ctx, teamID)
+		if err != nil {
+			return n, fmt.Errorf("archive tasks of team %s: %w", teamID, err)
+		}
+		n += archived
+	}
+	return n, nil
+}
+
+// runTeam archives the tasks of the team with the given ID that have sat in
+// the last column of their board for longer than the board allows, and returns
+// how many it archived.
+func (j Job) runTeam(ctx context.Context, teamID string) (int, error) {
+	team, err := j.teamRetriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	// find the time before which a task must have moved into the last column
+	// of each board that archives tasks to be archived
+	now := j.clock.Now()
+	cutoffs := map[string]int64{}
+	var earliest time.Time
+	for _, b := range team.Boards {
+		if b.ArchiveAfterDays <= 0 {
+			continue
+		}
+		cutoff := now.AddDate(0, 0, -b.ArchiveAfterDays)
+		cutoffs[b.ID] = cutoff.UnixNano()
+		if earliest.IsZero() || cutoff.Before(earliest) {
+			earliest = cutoff
+		}
+	}
+	if len(cutoffs) == 0 {
+		return 0, nil
+	}
+
+	// find when each task last moved into the last column since the earliest
+	// cutoff
+	entries, err := j.histByTeam.Retrieve(
+		ctx, teamID, earliest.UTC().Format(time.DateOnly),
+	)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		return 0, err
+	}
+	lastCol := strconv.Itoa(validator.MaxColNo)
+	entered := map[string]int64{}
+	for _, e := range entries {
+		for _, c := range e.Changes {
+			if c.Field == histtbl.FieldColumn && c.New == lastCol &&
+				e.At > entered[e.TaskID] {
+				entered[e.TaskID] = e.At
+			}
+		}
+	}
+
+	// archive the tasks in the last column that did not move there since
+	// their board's cutoff
+	tasks, err := j.tasksByTeam.Retrieve(ctx, teamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		return 0, err
+	}
+	var archived []tasktbl.Task
+	for _, t := range tasks {
+		cutoff, ok := cutoffs[t.BoardID]
+		if !ok || t.ColNo != validator.MaxColNo || t.ArchivedAt != 0 ||
+			entered[t.ID] >= cutoff {
+			continue
+		}
+		t.ArchivedAt = now.Unix()
+		archived = append(archived, t)
+	}
+	if len(archived) == 0 {
+		return 0, nil
+	}
+	if err := j.tasksUpdater.Update(
+		ctx, archived,
+	); errors.Is(err, db.ErrNoItem) {
+		// a task was deleted since it was read, so leave the team's tasks to
+		// the next run
+		j.log.Info("skipped tasks of team", teamID, "as one was deleted")
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	for _, t := range archived {
+		j.log.Info("archived task", teamID+"/"+t.ID)
+	}
+	return len(archived), nil
+}
@@ -0,0 +1,151 @@
+//go:build utest
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestJob tests the Run method of Job to assert that it archives the tasks
+// that have sat in the last column of their board for longer than the board
+// allows, and only those.
+func TestJob(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock.Fake{Time: time.Date(2024, 3, 10, 3, 30, 0, 0, time.UTC)}
+	daysAgo := func(days int) int64 {
+		return clk.Now().AddDate(0, 0, -days).UnixNano()
+	}
+
+	// seed returns a store holding tasks to archive and to leave be, with
+	// the history of the tasks moving into the last column
+	seed := func(t *testing.T) *memdb.Store {
+		s := memdb.NewStore()
+		for _, u := range []usertbl.User{
+			{Username: "bob", TeamID: "t1"},
+			{Username: "carol", TeamID: "t2"},
+		} {
+			err := memdb.NewUserInserter(s).Insert(ctx, u)
+			assert.Nil(t.Fatal, err)
+		}
+		err := memdb.NewTeamInserter(s).Insert(ctx, teamtbl.Team{
+			ID: "t1",
+			Boards: []teamtbl.Board{
+				{ID: "b1", ArchiveAfterDays: 7},
+				{ID: "b2"},
+			},
+		})
+		assert.Nil(t.Fatal, err)
+
+		for _, task := range []tasktbl.Task{
+			{TeamID: "t1", BoardID: "b1", ColNo: 3, ID: "k1"}, // no history
+			{TeamID: "t1", BoardID: "b1", ColNo: 3, ID: "k2"}, // moved 8d ago
+			{TeamID: "t1", BoardID: "b1", ColNo: 3, ID: "k3"}, // moved 2d ago
+			{TeamID: "t1", BoardID: "b1", ColNo: 2, ID: "k4"}, // not done
+			{TeamID: "t1", BoardID: "b2", ColNo: 3, ID: "k5"}, // not archiving
+			{TeamID: "t2", BoardID: "b3", ColNo: 3, ID: "k6"}, // team gone
+			{
+				TeamID: "t1", BoardID: "b1", ColNo: 3, ID: "k7",
+				ArchivedAt: 1, // archived already
+			},
+		} {
+			err := memdb.NewTaskInserter(s).Insert(ctx, task)
+			assert.Nil(t.Fatal, err)
+		}
+
+		for _, e := range []histtbl.Entry{
+			{TaskID: "k2", At: daysAgo(8)},
+			{TaskID: "k3", At: daysAgo(9)},
+			{TaskID: "k3", At: daysAgo(2)},
+		} {
+			e.TeamID = "t1"
+			e.Changes = []histtbl.Change{
+				{Field: histtbl.FieldColumn, Old: "2", New: "3"},
+			}
+			err := memdb.NewHistoryInserter(s).Insert(ctx, e)
+			assert.Nil(t.Fatal, err)
+		}
+		return s
+	}
+
+	// newSUT returns a Job that archives the tasks in the given store
+	newSUT := func(s *memdb.Store) Job {
+		return NewJob(
+			clk,
+			memdb.NewUserLister(s),
+			memdb.NewTeamRetriever(s),
+			memdb.NewTaskRetrieverByTeam(s),
+			memdb.NewHistoryRetrieverByTeam(s),
+			memdb.NewTaskTransactionalUpdater(s),
+			&log.FakeInfoer{},
+		)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		s := seed(t)
+
+		n, err := newSUT(s).Run(ctx)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, n, 2)
+		for id, want := range map[string]int64{
+			"k1": clk.Now().Unix(),
+			"k2": clk.Now().Unix(),
+			"k3": 0,
+			"k4": 0,
+			"k5": 0,
+			"k6": 0,
+			"k7": 1,
+		} {
+			teamID := "t1"
+			if id == "k6" {
+				teamID = "t2"
+			}
+			task, err := memdb.NewTaskRetriever(s).Retrieve(ctx, teamID, id)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, task.ArchivedAt, want)
+		}
+
+		// a second run has nothing left to archive
+		n, err = newSUT(s).Run(ctx)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, n, 0)
+	})
+
+	t.Run("TaskDeleted", func(t *testing.T) {
+		s := seed(t)
+		sut := newSUT(s)
+		sut.tasksUpdater = &db.FakeUpdater[[]tasktbl.Task]{Err: db.ErrNoItem}
+
+		n, err := sut.Run(ctx)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, n, 0)
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		s := seed(t)
+		sut := newSUT(s)
+		errA := errors.New("failed")
+		sut.tasksUpdater = &db.FakeUpdater[[]tasktbl.Task]{Err: errA}
+
+		_, err := sut.Run(ctx)
+
+		assert.ErrIs(t.Error, err, errA)
+		assert.Equal(
+			t.Error, err.Error(), "archive tasks of team t1: failed",
+		)
+	})
+}
@@ -0,0 +1,242 @@
+// Package cleanup contains the job that purges the data that is kept past its
+// use: expired trash items, invites, and sessions, and tasks that are left
+// behind by boards that no longer exist.
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+const (
+	// EnvSchedule is the name of the environment variable used for setting the
+	// cron expression that the services schedule the job on.
+	EnvSchedule = "CLEANUP_SCHEDULE"
+
+	// DefaultSchedule is the schedule of the job if EnvSchedule is empty - at
+	// 03:00 UTC every day.
+	DefaultSchedule = "0 3 * * *"
+)
+
+// Report defines the number of items of each kind that a run purged, or would
+// have purged if it was a dry run.
+type Report struct {
+	TrashItems int // past trashtbl.Retention, which DynamoDB has not deleted
+	Tasks      int // whose board is neither on the team nor in the trash
+	Invites    int
+	Sessions   int
+}
+
+// Job can be used to purge the items that are kept past their use.
+type Job struct {
+	clock          clock.Clock
+	userLister     db.Lister[[]usertbl.User]
+	userUpdater    db.Updater[usertbl.User]
+	teamRetriever  db.Retriever[teamtbl.Team]
+	teamUpdater    db.Updater[teamtbl.Team]
+	trashRetriever db.Retriever[[]trashtbl.Item]
+	expiredTrash   db.Retriever[[]trashtbl.Item]
+	trashDeleter   db.DeleterDualKey
+	taskRetriever  db.Retriever[[]tasktbl.Task]
+	taskDeleter    db.DeleterDualKey
+	log            log.Infoer
+}
+
+// NewJob creates and returns a new Job. trashRetriever must retrieve the
+// unexpired items of a team from the trash, and expiredTrash the expired ones.
+func NewJob(
+	clock clock.Clock,
+	userLister db.Lister[[]usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	trashRetriever db.Retriever[[]trashtbl.Item],
+	expiredTrash db.Retriever[[]trashtbl.Item],
+	trashDeleter db.DeleterDualKey,
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	taskDeleter db.DeleterDualKey,
+	log log.Infoer,
+) Job {
+	return Job{
+		clock:          clock,
+		userLister:     userLister,
+		userUpdater:    userUpdater,
+		teamRetriever:  teamRetriever,
+		teamUpdater:    teamUpdater,
+		trashRetriever: trashRetriever,
+		expiredTrash:   expiredTrash,
+		trashDeleter:   trashDeleter,
+		taskRetriever:  taskRetriever,
+		taskDeleter:    taskDeleter,
+		log:            log,
+	}
+}
+
+// Run purges the items that are kept past their use and returns how many of
+// each kind it purged. If dryRun is true, the items are only logged and
+// counted. Each item is logged as it is purged so that a failed run shows how
+// far it got.
+func (j Job) Run(ctx context.Context, dryRun bool) (Report, error) {
+	var rep Report
+	purge := func(kind, id string, del func() error) error {
+		if dryRun {
+			j.log.Info("would purge", kind, id)
+			return nil
+		}
+		if err := del(); err != nil && !errors.Is(err, db.ErrNoItem) {
+			return fmt.Errorf("purge %s %s: %w", kind, id, err)
+		}
+		j.log.Info("purged", kind, id)
+		return nil
+	}
+
+	users, err := j.userLister.List(ctx)
+	if err != nil {
+		return rep, err
+	}
+
+	// purge each user's expired sessions - the user is overwritten, so a
+	// session started since it was listed is lost and has to log in again
+	now := j.clock.Now().Unix()
+	var teamIDs []string
+	for _, user := range users {
+		if user.TeamID != "" && !slices.Contains(teamIDs, user.TeamID) {
+			teamIDs = append(teamIDs, user.TeamID)
+		}
+
+		var sessions []usertbl.Session
+		for _, s := range user.Sessions {
+			if s.ExpiresAt > now {
+				sessions = append(sessions, s)
+			}
+		}
+		n := len(user.Sessions) - len(sessions)
+		if n == 0 {
+			continue
+		}
+		if err := purge(
+			fmt.Sprintf("%d sessions of user", n), user.Username,
+			func() error {
+				user.Sessions = sessions
+				return j.userUpdater.Update(ctx, user)
+			},
+		); err != nil {
+			return rep, err
+		}
+		rep.Sessions += n
+	}
+
+	// purge the trash items, tasks, and invites of each team
+	slices.Sort(teamIDs)
+	for _, teamID := range teamIDs {
+		if err := j.runTeam(ctx, teamID, now, purge, &rep); err != nil {
+			return rep, err
+		}
+	}
+	return rep, nil
+}
+
+// runTeam purges the expired trash items and invites of the team with the given
+// ID, and the tasks that are left behind by its boards that no longer exist.
+func (j Job) runTeam(
+	ctx context.Context,
+	teamID string,
+	now int64,
+	purge func(kind, id string, del func() error) error,
+	rep *Report,
+) error {
+	team, err := j.teamRetriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		// the tasks of a team that no longer exists are all left behind
+		team = teamtbl.Team{ID: teamID}
+	} else if err != nil {
+		return err
+	}
+
+	// purge the expired trash items
+	expired, err := j.expiredTrash.Retrieve(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for _, item := range expired {
+		if err := purge(
+			"trash item", teamID+"/"+item.ID,
+			func() error {
+				return j.trashDeleter.Delete(ctx, teamID, item.ID)
+			},
+		); err != nil {
+			return err
+		}
+		rep.TrashItems++
+	}
+
+	// purge the tasks whose board is neither on the team nor in the trash,
+	// which includes those of the boards whose trash items were just purged
+	boardIDs := map[string]bool{}
+	for _, b := range team.Boards {
+		boardIDs[b.ID] = true
+	}
+	trash, err := j.trashRetriever.Retrieve(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for _, item := range trash {
+		if item.Kind == trashtbl.KindBoard {
+			boardIDs[item.ID] = true
+		}
+	}
+	tasks, err := j.taskRetriever.Retrieve(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if boardIDs[task.BoardID] {
+			continue
+		}
+		if err := purge(
+			"orphaned task", teamID+"/"+task.ID,
+			func() error {
+				return j.taskDeleter.Delete(ctx, teamID, task.ID)
+			},
+		); err != nil {
+			return err
+		}
+		rep.Tasks++
+	}
+
+	// purge the expired invites
+	invites := slices.DeleteFunc(
+		slices.Clone(team.Invites),
+		func(inv teamtbl.Invite) bool { return inv.ExpiresAt <= now },
+	)
+	n := len(team.Invites) - len(invites)
+	if n == 0 {
+		return nil
+	}
+	if err := purge(
+		fmt.Sprintf("%d invites of team", n), teamID,
+		func() error {
+			team.Invites = invites
+			return j.teamUpdater.Update(ctx, team)
+		},
+	); errors.Is(err, db.ErrConflict) {
+		// the team was written to since it was read, so leave its invites
+		// to the next run rather than overwrite the write
+		j.log.Info("skipped invites of team", teamID, "as it was modified")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	rep.Invites += n
+	return nil
+}
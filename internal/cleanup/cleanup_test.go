@@ -0,0 +1,171 @@
+//go:build utest
+
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestJob tests the Run method of Job to assert that it purges the expired
+// trash items, invites, and sessions, and the orphaned tasks, and only counts
+// them on a dry run.
+func TestJob(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock.Fake{Time: time.Now()}
+	now := clk.Now().Unix()
+
+	// seed returns a store holding one item of each kind to keep and purge
+	seed := func(t *testing.T) *memdb.Store {
+		s := memdb.NewStore()
+		for _, u := range []usertbl.User{
+			{Username: "bob", TeamID: "t1", Sessions: []usertbl.Session{
+				{ID: "s1", ExpiresAt: now - 1},
+				{ID: "s2", ExpiresAt: now + 60},
+			}},
+			{Username: "alice", TeamID: "t1"},
+			{Username: "carol", TeamID: "t2"},
+		} {
+			err := memdb.NewUserInserter(s).Insert(ctx, u)
+			assert.Nil(t.Fatal, err)
+		}
+		err := memdb.NewTeamInserter(s).Insert(ctx, teamtbl.Team{
+			ID:     "t1",
+			Boards: []teamtbl.Board{{ID: "b1"}},
+			Invites: []teamtbl.Invite{
+				{Nonce: "n1", ExpiresAt: now - 1},
+				{Nonce: "n2", ExpiresAt: now + 60},
+			},
+		})
+		assert.Nil(t.Fatal, err)
+
+		trashed := trashtbl.NewBoardItem("t1", "bob", teamtbl.Board{ID: "b2"})
+		expired := trashtbl.NewBoardItem("t1", "bob", teamtbl.Board{ID: "b3"})
+		expired.ExpiresAt = now - 1
+		for _, item := range []trashtbl.Item{trashed, expired} {
+			err := memdb.NewTrashInserter(s).Insert(ctx, item)
+			assert.Nil(t.Fatal, err)
+		}
+
+		for _, task := range []tasktbl.Task{
+			{TeamID: "t1", BoardID: "b1", ID: "k1"}, // on the team
+			{TeamID: "t1", BoardID: "b2", ID: "k2"}, // in the trash
+			{TeamID: "t1", BoardID: "b3", ID: "k3"}, // trash expired
+			{TeamID: "t1", BoardID: "b4", ID: "k4"}, // board gone
+			{TeamID: "t2", BoardID: "b5", ID: "k5"}, // team gone
+		} {
+			err := memdb.NewTaskInserter(s).Insert(ctx, task)
+			assert.Nil(t.Fatal, err)
+		}
+		return s
+	}
+
+	// newSUT returns a Job that purges from the given store
+	newSUT := func(s *memdb.Store) Job {
+		return NewJob(
+			clk,
+			memdb.NewUserLister(s),
+			memdb.NewUserUpdater(s),
+			memdb.NewTeamRetriever(s),
+			memdb.NewTeamUpdater(s),
+			memdb.NewTrashRetrieverByTeam(s),
+			memdb.NewTrashExpiredRetrieverByTeam(s),
+			memdb.NewTrashDeleter(s),
+			memdb.NewTaskRetrieverByTeam(s),
+			memdb.NewTaskDeleter(s),
+			&log.FakeInfoer{},
+		)
+	}
+	wantRep := Report{TrashItems: 1, Tasks: 3, Invites: 1, Sessions: 1}
+
+	t.Run("DryRun", func(t *testing.T) {
+		s := seed(t)
+
+		rep, err := newSUT(s).Run(ctx, true)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, rep, wantRep)
+		tasks, _ := memdb.NewTaskRetrieverByTeam(s).Retrieve(ctx, "t1")
+		assert.Equal(t.Error, len(tasks), 4)
+		user, _ := memdb.NewUserRetriever(s).Retrieve(ctx, "bob")
+		assert.Equal(t.Error, len(user.Sessions), 2)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		s := seed(t)
+
+		rep, err := newSUT(s).Run(ctx, false)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, rep, wantRep)
+
+		user, err := memdb.NewUserRetriever(s).Retrieve(ctx, "bob")
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(user.Sessions), 1)
+		assert.Equal(t.Error, user.Sessions[0].ID, "s2")
+
+		team, err := memdb.NewTeamRetriever(s).Retrieve(ctx, "t1")
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(team.Invites), 1)
+		assert.Equal(t.Error, team.Invites[0].Nonce, "n2")
+
+		expired, _ := memdb.NewTrashExpiredRetrieverByTeam(s).Retrieve(
+			ctx, "t1",
+		)
+		assert.Equal(t.Error, len(expired), 0)
+
+		for teamID, wantIDs := range map[string][]string{
+			"t1": {"k1", "k2"},
+			"t2": nil,
+		} {
+			tasks, _ := memdb.NewTaskRetrieverByTeam(s).Retrieve(ctx, teamID)
+			assert.Equal(t.Fatal, len(tasks), len(wantIDs))
+			for i, id := range wantIDs {
+				assert.Equal(t.Error, tasks[i].ID, id)
+			}
+		}
+
+		// a second run has nothing left to purge
+		rep, err = newSUT(s).Run(ctx, false)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, rep, Report{})
+	})
+
+	t.Run("TeamConflict", func(t *testing.T) {
+		s := seed(t)
+		sut := newSUT(s)
+		sut.teamUpdater = &db.FakeUpdater[teamtbl.Team]{Err: db.ErrConflict}
+
+		rep, err := sut.Run(ctx, false)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, rep.Invites, 0)
+		assert.Equal(t.Error, rep.Tasks, 3)
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		s := seed(t)
+		sut := newSUT(s)
+		errA := errors.New("failed")
+		sut.userUpdater = &db.FakeUpdater[usertbl.User]{Err: errA}
+
+		_, err := sut.Run(ctx, false)
+
+		assert.ErrIs(t.Error, err, errA)
+		assert.Equal(
+			t.Error, err.Error(), "purge 1 sessions of user bob: failed",
+		)
+	})
+}
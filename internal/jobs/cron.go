@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes a type that can be used to tell when a job is next due.
+type Schedule interface {
+	// Next returns the first time the job is due after the given time, or the
+	// zero time if it is never due again.
+	Next(time.Time) time.Time
+}
+
+// Cron is a Schedule parsed from a cron expression. Its times are in UTC.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day of month and day of week
+	// fields started with *, as a day is due if it matches either of them
+	// unless one of them does, in which case it must match the other.
+	domAny, dowAny bool
+}
+
+// cronDescriptors are the shorthands ParseCron accepts for common schedules.
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron parses the given cron expression into a Cron. The expression has
+// five fields - minute, hour, day of month, month, and day of week - each of
+// which is *, a number, a range like 1-5, or a comma-separated list of those,
+// optionally followed by a step like */15. Sunday is 0 or 7 in the day of week
+// field. @hourly, @daily, @weekly, and @monthly are also accepted.
+func ParseCron(expr string) (Cron, error) {
+	if d, ok := cronDescriptors[expr]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf(
+			"cron expression must have 5 fields, got %q", expr,
+		)
+	}
+
+	var c Cron
+	for i, f := range []struct {
+		bits        *uint64
+		first, last int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 7},
+	} {
+		bits, err := parseCronField(fields[i], f.first, f.last)
+		if err != nil {
+			return Cron{}, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		*f.bits = bits
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField returns the set of values the given cron field matches as a
+// bit set, given the first and last values of the field.
+func parseCronField(field string, first, last int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		lo, hi := first, last
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				hi = last
+			}
+		}
+		if lo < first || hi > last || lo > hi {
+			return 0, fmt.Errorf(
+				"%q is out of the range %d-%d", part, first, last,
+			)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// cronSearchLimit is how far ahead Next looks for a due time before deciding
+// that the schedule is never due, e.g. for February 30.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Next returns the first minute after the given time that matches the cron
+// expression.
+func (c Cron) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<m) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches returns whether the day of the given time matches the day of
+// month and day of week fields of the cron expression.
+func (c Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<t.Weekday()) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
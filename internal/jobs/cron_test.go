@@ -0,0 +1,60 @@
+//go:build utest
+
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestCron tests the Next method of Cron to assert that it returns the first
+// minute after the given time that matches the parsed expression.
+func TestCron(t *testing.T) {
+	// Friday
+	from := time.Date(2024, 3, 15, 12, 30, 45, 0, time.UTC)
+
+	for _, c := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 15, 12, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 3, 15, 12, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 3, 16, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 3, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"30 8 1,20 * *", time.Date(2024, 3, 20, 8, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+		// day of month or day of week when both are restricted
+		{"0 0 1 * 6", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(c.expr, func(t *testing.T) {
+			sut, err := ParseCron(c.expr)
+			assert.Nil(t.Fatal, err)
+
+			got := sut.Next(from)
+
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+// TestParseCron tests the ParseCron function to assert that it rejects invalid
+// expressions.
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *",
+		"* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *",
+		"a * * * *", "@yearly",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+
+			assert.True(t.Error, err != nil)
+		})
+	}
+}
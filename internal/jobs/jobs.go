@@ -0,0 +1,171 @@
+// Package jobs contains the scheduler that runs the background jobs of the
+// services, such as the cleanup job, on cron-like schedules. Each run of a job
+// is leader-elected through a lock in DynamoDB so that it is done by only one
+// of the instances that host the scheduler.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/locktbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DefaultTimeout is how long a run of a job may take unless the job sets its
+// own timeout.
+const DefaultTimeout = 30 * time.Minute
+
+// Job defines a job that is run on a schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(context.Context) error
+
+	// Timeout is how long a run may take before its context is cancelled,
+	// and how long the instance that runs it holds its lock for. It defaults
+	// to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Scheduler runs jobs on their schedules.
+type Scheduler struct {
+	clock   clock.Clock
+	locker  db.Inserter[locktbl.Lock]
+	owner   string
+	log     log.Errorer
+	sleep   func(context.Context, time.Duration) error
+	jobs    []Job
+	metrics *expvar.Map
+}
+
+// NewScheduler creates and returns a new Scheduler that acquires the lock on
+// each run of a job through the given locker as the given owner, which must
+// identify the instance among those that host the scheduler.
+func NewScheduler(
+	clk clock.Clock,
+	locker db.Inserter[locktbl.Lock],
+	owner string,
+	log log.Errorer,
+) *Scheduler {
+	return &Scheduler{
+		clock:   clk,
+		locker:  locker,
+		owner:   owner,
+		log:     log,
+		sleep:   clock.Sleep,
+		metrics: new(expvar.Map).Init(),
+	}
+}
+
+// Add adds the given job to the scheduler. It must be called before Run.
+func (s *Scheduler) Add(job Job) {
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
+	}
+	s.metrics.Set(job.Name, new(expvar.Map).Init())
+	s.jobs = append(s.jobs, job)
+}
+
+// Metrics returns the metrics of the jobs for publishing with expvar. They are
+// the number of runs, failures, panics, and runs skipped as another instance
+// held their lock, and the time and duration of the last run, by job name.
+func (s *Scheduler) Metrics() expvar.Var { return s.metrics }
+
+// Run runs each job on its schedule until the given context is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			for {
+				at := job.Schedule.Next(s.clock.Now())
+				if at.IsZero() {
+					return
+				}
+				if err := s.sleep(ctx, at.Sub(s.clock.Now())); err != nil {
+					return
+				}
+				s.runAt(ctx, job, at)
+			}
+		}(job)
+	}
+	wg.Wait()
+}
+
+// runAt runs the given job for the given due time if no other instance has
+// acquired the lock on that run, recording the outcome in the metrics.
+func (s *Scheduler) runAt(ctx context.Context, job Job, at time.Time) {
+	metrics := s.metrics.Get(job.Name).(*expvar.Map)
+
+	lockID := "job/" + job.Name + "/" + at.UTC().Format(time.RFC3339)
+	if err := s.locker.Insert(ctx, locktbl.NewLock(
+		lockID, s.owner, at, job.Timeout,
+	)); errors.Is(err, db.ErrDupKey) {
+		metrics.Add("skipped", 1)
+		return
+	} else if err != nil {
+		metrics.Add("failures", 1)
+		s.log.Error("job", job.Name, "failed to acquire lock:", err)
+		return
+	}
+
+	start := s.clock.Now()
+	err := s.run(ctx, job)
+	metrics.Add("runs", 1)
+	metrics.Set("lastRunAt", stringVar(start.UTC().Format(time.RFC3339)))
+	metrics.Set("lastDurationSeconds", floatVar(
+		s.clock.Now().Sub(start).Seconds(),
+	))
+
+	var pe panicErr
+	if errors.As(err, &pe) {
+		metrics.Add("panics", 1)
+		s.log.Error("job", job.Name, "panicked:", pe.v, "\n", pe.stack)
+	} else if err != nil {
+		metrics.Add("failures", 1)
+		s.log.Error("job", job.Name, "failed:", err)
+	}
+}
+
+// run runs the given job within its timeout, returning a panicErr if it panics
+// so that a panic in one job does not take down the others or the service.
+func (s *Scheduler) run(ctx context.Context, job Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+	defer func() {
+		if v := recover(); v != nil {
+			err = panicErr{v: v, stack: string(debug.Stack())}
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// panicErr is returned by Scheduler.run for jobs that panic.
+type panicErr struct {
+	v     any
+	stack string
+}
+
+// Error returns the value the job panicked with.
+func (e panicErr) Error() string { return fmt.Sprint("panic: ", e.v) }
+
+// stringVar is an expvar.Var that holds a string that is set once.
+type stringVar string
+
+// String returns the JSON encoding of the string.
+func (v stringVar) String() string { return fmt.Sprintf("%q", string(v)) }
+
+// floatVar is an expvar.Var that holds a float that is set once.
+type floatVar float64
+
+// String returns the JSON encoding of the float.
+func (v floatVar) String() string { return fmt.Sprint(float64(v)) }
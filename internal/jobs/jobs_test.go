@@ -0,0 +1,153 @@
+//go:build utest
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/locktbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// metric returns the value of the given metric of the given job as a string.
+func metric(s *Scheduler, job, name string) string {
+	v := s.metrics.Get(job).(*expvar.Map).Get(name)
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// TestScheduler tests the Run method of Scheduler to assert that it runs each
+// job at its due times with the lock on the run acquired, until its context
+// is done.
+func TestScheduler(t *testing.T) {
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 30, 0, time.UTC)}
+	locker := &db.FakeInserter[locktbl.Lock]{}
+	sut := NewScheduler(clk, locker, "instance1", &log.FakeErrorer{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ranAt []time.Time
+	sut.Add(Job{
+		Name:     "job",
+		Schedule: mustParseCron(t, "*/10 * * * *"),
+		Run: func(context.Context) error {
+			ranAt = append(ranAt, clk.Now())
+			if len(ranAt) == 3 {
+				cancel()
+			}
+			return nil
+		},
+	})
+	sut.sleep = func(ctx context.Context, d time.Duration) error {
+		clk.Advance(d)
+		return ctx.Err()
+	}
+
+	sut.Run(ctx)
+
+	assert.DeepEqual(t.Error, ranAt, []time.Time{
+		time.Date(2024, 3, 15, 12, 10, 0, 0, time.UTC),
+		time.Date(2024, 3, 15, 12, 20, 0, 0, time.UTC),
+		time.Date(2024, 3, 15, 12, 30, 0, 0, time.UTC),
+	})
+	assert.Equal(t.Error, locker.Inserted, locktbl.Lock{
+		ID:        "job/job/2024-03-15T12:30:00Z",
+		Owner:     "instance1",
+		ExpiresAt: ranAt[2].Add(DefaultTimeout).Unix(),
+	})
+	assert.Equal(t.Error, metric(sut, "job", "runs"), "3")
+	assert.Equal(
+		t.Error, metric(sut, "job", "lastRunAt"), `"2024-03-15T12:30:00Z"`,
+	)
+}
+
+// TestSchedulerRunAt tests the runAt method of Scheduler to assert that it
+// isolates failing and panicking jobs, and skips the runs another instance
+// acquired the lock on.
+func TestSchedulerRunAt(t *testing.T) {
+	errA := errors.New("failed")
+
+	for _, c := range []struct {
+		name       string
+		errLock    error
+		run        func(context.Context) error
+		wantRan    bool
+		wantMetric string
+		wantLogged bool
+	}{
+		{
+			name:       "LockHeld",
+			errLock:    db.ErrDupKey,
+			wantMetric: "skipped",
+		},
+		{
+			name:       "LockErr",
+			errLock:    errA,
+			wantMetric: "failures",
+			wantLogged: true,
+		},
+		{
+			name:       "Err",
+			run:        func(context.Context) error { return errA },
+			wantRan:    true,
+			wantMetric: "failures",
+			wantLogged: true,
+		},
+		{
+			name:       "Panic",
+			run:        func(context.Context) error { panic("boom") },
+			wantRan:    true,
+			wantMetric: "panics",
+			wantLogged: true,
+		},
+		{
+			name:       "OK",
+			run:        func(context.Context) error { return nil },
+			wantRan:    true,
+			wantMetric: "runs",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			log := &log.FakeErrorer{}
+			sut := NewScheduler(
+				&clock.Fake{},
+				&db.FakeInserter[locktbl.Lock]{Err: c.errLock},
+				"instance1",
+				log,
+			)
+			ran := false
+			sut.Add(Job{
+				Name: "job",
+				Run: func(ctx context.Context) error {
+					ran = true
+					_, hasDeadline := ctx.Deadline()
+					assert.True(t.Error, hasDeadline)
+					return c.run(ctx)
+				},
+			})
+
+			sut.runAt(context.Background(), sut.jobs[0], time.Time{})
+
+			assert.Equal(t.Error, ran, c.wantRan)
+			assert.Equal(t.Error, metric(sut, "job", c.wantMetric), "1")
+			assert.Equal(t.Error, len(log.Args) > 0, c.wantLogged)
+		})
+	}
+}
+
+// mustParseCron parses the given cron expression, failing the test if it is
+// invalid.
+func mustParseCron(t *testing.T, expr string) Cron {
+	c, err := ParseCron(expr)
+	assert.Nil(t.Fatal, err)
+	return c
+}
@@ -0,0 +1,91 @@
+package historyapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET task history responses. Entries are ordered
+// newest first, and are omitted for tasks that have not been edited.
+type GetResp struct {
+	Error   string     `json:"error,omitempty"`
+	Entries []GetEntry `json:"entries,omitempty"`
+}
+
+// GetEntry defines an edit of the task in a GetResp.
+type GetEntry struct {
+	At       time.Time        `json:"at"`
+	Username string           `json:"username"`
+	Changes  []histtbl.Change `json:"changes"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// task history route.
+type GetHandler struct {
+	histRetriever db.Retriever[[]histtbl.Entry]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	histRetriever db.Retriever[[]histtbl.Entry],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		histRetriever: histRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the task history route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// get task ID from the path, falling back to the query for /task/history
+	id := api.PathParam(r, "taskID")
+	if id == "" {
+		id = r.URL.Query().Get("id")
+	}
+	if id == "" {
+		h.writeResp(w, http.StatusBadRequest, GetResp{
+			Error: "Task ID cannot be empty.",
+		})
+		return
+	}
+
+	// retrieve the task's history, keeping only the entries recorded for the
+	// user's team so that other teams' edits are never exposed
+	entries, err := h.histRetriever.Retrieve(r.Context(), id)
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var resp GetResp
+	for _, e := range entries {
+		if e.TeamID != auth.TeamID {
+			continue
+		}
+		resp.Entries = append(resp.Entries, GetEntry{
+			At:       time.Unix(0, e.At).UTC(),
+			Username: e.Username,
+			Changes:  e.Changes,
+		})
+	}
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,121 @@
+//go:build utest
+
+package historyapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the GET handler.
+func TestGetHandler(t *testing.T) {
+	histRetriever := &db.FakeRetriever[[]histtbl.Entry]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(histRetriever, log)
+
+	someEntries := []histtbl.Entry{
+		{
+			TaskID:   "qwerty",
+			At:       1700000000000000002,
+			TeamID:   "21",
+			Username: "bob123",
+			Changes: []histtbl.Change{
+				{Field: "title", Old: "Do it", New: "Do it now"},
+			},
+		},
+		{
+			TaskID:   "qwerty",
+			At:       1700000000000000001,
+			TeamID:   "22",
+			Username: "alice456",
+			Changes: []histtbl.Change{
+				{Field: "description", Old: "", New: "Secret"},
+			},
+		},
+	}
+
+	for _, c := range []struct {
+		name           string
+		path           string
+		entries        []histtbl.Entry
+		errRetrieve    error
+		wantStatusCode int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:           "NoTaskID",
+			path:           "/",
+			entries:        nil,
+			errRetrieve:    nil,
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     assert.OnRespErr("Task ID cannot be empty."),
+		},
+		{
+			name:           "ErrRetrieve",
+			path:           "/?id=qwerty",
+			entries:        nil,
+			errRetrieve:    errors.New("retrieve history failed"),
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve history failed"),
+		},
+		{
+			name:           "NoEntries",
+			path:           "/?id=qwerty",
+			entries:        nil,
+			errRetrieve:    nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp GetResp
+				if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, len(resp.Entries), 0)
+			},
+		},
+		{
+			name:           "OK",
+			path:           "/?id=qwerty",
+			entries:        someEntries,
+			errRetrieve:    nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp GetResp
+				if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+
+				// only the entries of the user's team are returned
+				assert.Equal(t.Fatal, len(resp.Entries), 1)
+				e := resp.Entries[0]
+				assert.True(t.Error, e.At.Equal(
+					time.Unix(0, someEntries[0].At),
+				))
+				assert.Equal(t.Error, e.Username, someEntries[0].Username)
+				assert.AllEqual(t.Error, e.Changes, someEntries[0].Changes)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			histRetriever.Res = c.entries
+			histRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+
+			sut.Handle(w, r, cookie.Auth{TeamID: "21"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package historyapi contains code for responding to HTTP requests made to the
+// task history API route, which is used for viewing the edits made to a task.
+package historyapi
@@ -0,0 +1,198 @@
+package taskapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// blockedMsg is the message that the moves of blocked tasks into the last
+// column of their board are refused or warned about with.
+const blockedMsg = "Task is blocked by tasks that are not done."
+
+// BlockersReq defines the body of PUT task blockers requests. BlockedBy holds
+// the IDs of the tasks on the same board that block the task, and replaces the
+// ones that blocked it before.
+type BlockersReq struct {
+	BlockedBy []string `json:"blockedBy"`
+}
+
+// BlockersResp defines the body of PUT task blockers responses.
+type BlockersResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// BlockersHandler is an api.MethodHandler that can handle PUT requests sent to
+// the task blockers route.
+type BlockersHandler struct {
+	taskRetriever    db.RetrieverDualKey[tasktbl.Task]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	taskUpdater      db.Updater[tasktbl.Task]
+	log              log.Errorer
+}
+
+// NewBlockersHandler creates and returns a new BlockersHandler.
+func NewBlockersHandler(
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
+	taskUpdater db.Updater[tasktbl.Task],
+	log log.Errorer,
+) BlockersHandler {
+	return BlockersHandler{
+		taskRetriever:    taskRetriever,
+		retrieverByBoard: retrieverByBoard,
+		taskUpdater:      taskUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles PUT requests sent to the task blockers route. It sets the
+// tasks that block the task, which must be on the same board as it and must
+// not be blocked by it themselves, directly or through other tasks.
+func (h BlockersHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeErr(w, http.StatusForbidden, "Only team admins can edit tasks.")
+		return
+	}
+
+	// read and validate request body
+	var req BlockersReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var blockedBy []string
+	for _, id := range req.BlockedBy {
+		if !slices.Contains(blockedBy, id) {
+			blockedBy = append(blockedBy, id)
+		}
+	}
+	if len(blockedBy) > validator.MaxBlockers {
+		h.writeErr(w, http.StatusBadRequest,
+			"A task cannot be blocked by more than 20 tasks.",
+		)
+		return
+	}
+
+	// retrieve the task
+	task, err := h.taskRetriever.Retrieve(
+		r.Context(), auth.TeamID, api.PathParam(r, "taskID"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "Task not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if slices.Contains(blockedBy, task.ID) {
+		h.writeErr(w, http.StatusBadRequest, "A task cannot block itself.")
+		return
+	}
+
+	// validate the blocking tasks are on the task's board and that they do
+	// not form a cycle with the links between the board's tasks
+	boardTasks, err := h.retrieverByBoard.Retrieve(r.Context(), task.BoardID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	links := map[string][]string{}
+	for _, t := range boardTasks {
+		links[t.ID] = t.BlockedBy
+	}
+	for _, id := range blockedBy {
+		if _, ok := links[id]; !ok {
+			h.writeErr(w, http.StatusNotFound, "Blocking task not found.")
+			return
+		}
+	}
+	links[task.ID] = blockedBy
+	if blocksItself(task.ID, links) {
+		h.writeErr(w, http.StatusBadRequest,
+			"Tasks cannot block each other in a cycle.",
+		)
+		return
+	}
+
+	// update the task
+	task.BlockedBy = blockedBy
+	if err = h.taskUpdater.Update(
+		r.Context(), task,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "Task not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeErr writes the given status and error message to the response.
+func (h BlockersHandler) writeErr(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(BlockersResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
+
+// blocksItself returns whether the task with the given ID blocks itself
+// through the given links, which map the IDs of tasks to those of the tasks
+// that block them.
+func blocksItself(id string, links map[string][]string) bool {
+	seen := map[string]bool{}
+	next := append([]string(nil), links[id]...)
+	for len(next) > 0 {
+		cur := next[len(next)-1]
+		next = next[:len(next)-1]
+		if cur == id {
+			return true
+		}
+		if !seen[cur] {
+			seen[cur] = true
+			next = append(next, links[cur]...)
+		}
+	}
+	return false
+}
+
+// blockedTasks returns the setting of the board with the given ID for the
+// tasks that are moved into its last column while they are blocked.
+func blockedTasks(
+	ctx context.Context,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamID string,
+	boardID string,
+) (string, error) {
+	team, err := teamRetriever.Retrieve(ctx, teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	for _, b := range team.Boards {
+		if b.ID == boardID {
+			return b.BlockedTasks, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,176 @@
+//go:build utest
+
+package taskapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestBlockersHandler tests the Handle method of BlockersHandler to assert
+// that it behaves correctly in all possible scenarios.
+func TestBlockersHandler(t *testing.T) {
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
+	taskUpdater := &db.FakeUpdater[tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewBlockersHandler(
+		taskRetriever, retrieverByBoard, taskUpdater, log,
+	)
+
+	task := tasktbl.Task{TeamID: "team1", BoardID: "board1", ID: "task1"}
+
+	// task3 is blocked by task2, which is blocked by task1
+	boardTasks := []tasktbl.Task{
+		task,
+		{BoardID: "board1", ID: "task2", BlockedBy: []string{"task1"}},
+		{BoardID: "board1", ID: "task3", BlockedBy: []string{"task2"}},
+		{BoardID: "board1", ID: "task4"},
+	}
+
+	for _, c := range []struct {
+		name        string
+		auth        cookie.Auth
+		reqBody     string
+		errRetrieve error
+		errBoard    error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NotAdmin",
+			auth:       cookie.Auth{IsAdmin: false},
+			reqBody:    `{"blockedBy": ["task4"]}`,
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr("Only team admins can edit tasks."),
+		},
+		{
+			name: "TooMany",
+			auth: cookie.Auth{IsAdmin: true},
+			reqBody: `{"blockedBy": ["a", "b", "c", "d", "e", "f", "g", ` +
+				`"h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", ` +
+				`"s", "t", "u"]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"A task cannot be blocked by more than 20 tasks.",
+			),
+		},
+		{
+			name:        "TaskNotFound",
+			auth:        cookie.Auth{IsAdmin: true},
+			reqBody:     `{"blockedBy": ["task4"]}`,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Task not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			auth:        cookie.Auth{IsAdmin: true},
+			reqBody:     `{"blockedBy": ["task4"]}`,
+			errRetrieve: errors.New("retrieve task failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:       "Self",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4", "task1"]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("A task cannot block itself."),
+		},
+		{
+			name:       "ErrRetrieveBoard",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4"]}`,
+			errBoard:   errors.New("retrieve board failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve board failed"),
+		},
+		{
+			name:       "BlockerNotFound",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task5"]}`,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Blocking task not found."),
+		},
+		{
+			name:       "Cycle",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4", "task3"]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot block each other in a cycle.",
+			),
+		},
+		{
+			name:       "UpdateNotFound",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4"]}`,
+			errUpdate:  db.ErrNoItem,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Task not found."),
+		},
+		{
+			name:       "ErrUpdate",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4"]}`,
+			errUpdate:  errors.New("update failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:       "OK",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": ["task4", "task4"]}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.AllEqual(t.Error,
+					taskUpdater.Updated.BlockedBy, []string{"task4"},
+				)
+			},
+		},
+		{
+			name:       "OKClear",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    `{"blockedBy": []}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, taskUpdater.Updated.ID, "task1")
+				assert.Equal(t.Error, len(taskUpdater.Updated.BlockedBy), 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			taskRetriever.Res = task
+			taskRetriever.Err = c.errRetrieve
+			retrieverByBoard.Res = boardTasks
+			retrieverByBoard.Err = c.errBoard
+			taskUpdater.Err = c.errUpdate
+			taskUpdater.Updated = tasktbl.Task{}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPut, "/", strings.NewReader(c.reqBody),
+				),
+				map[string]string{"taskID": "task1"},
+			)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// ChecklistReq defines the body of POST task checklist requests.
+type ChecklistReq struct {
+	ChecklistID string `json:"checklistID"`
+}
+
+// ChecklistResp defines the body of POST task checklist responses.
+type ChecklistResp struct {
+	Error string        `json:"error,omitempty"`
+	Task  *tasktbl.Task `json:"task,omitempty"`
+}
+
+// ChecklistHandler is an api.MethodHandler that can handle POST requests sent
+// to the task checklist route.
+type ChecklistHandler struct {
+	taskRetriever db.RetrieverDualKey[tasktbl.Task]
+	teamRetriever db.Retriever[teamtbl.Team]
+	taskUpdater   db.Updater[tasktbl.Task]
+	histInserter  db.Inserter[histtbl.Entry]
+	log           log.Errorer
+}
+
+// NewChecklistHandler creates and returns a new ChecklistHandler.
+func NewChecklistHandler(
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskUpdater db.Updater[tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
+	log log.Errorer,
+) ChecklistHandler {
+	return ChecklistHandler{
+		taskRetriever: taskRetriever,
+		teamRetriever: teamRetriever,
+		taskUpdater:   taskUpdater,
+		histInserter:  histInserter,
+		log:           log,
+	}
+}
+
+// Handle handles POST requests sent to the task checklist route. It applies
+// one of the team's checklist templates to the task by adding its items to the
+// end of the task's subtasks, not done, and responds with the task. The items
+// that the task already has a subtask with the title of are skipped so that
+// applying a checklist twice does not duplicate them.
+func (h ChecklistHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, ChecklistResp{
+			Error: "Only team admins can edit tasks.",
+		})
+		return
+	}
+
+	// read request body
+	var req ChecklistReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the task and the checklist to apply to it
+	task, err := h.taskRetriever.Retrieve(
+		r.Context(), auth.TeamID, api.PathParam(r, "taskID"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, ChecklistResp{
+			Error: "Task not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	checklist, ok := team.Checklist(req.ChecklistID)
+	if !ok {
+		h.writeResp(w, http.StatusNotFound, ChecklistResp{
+			Error: "Checklist not found.",
+		})
+		return
+	}
+
+	// add the checklist's items that the task does not have to its subtasks
+	old := task
+	task.Subtasks = append([]tasktbl.Subtask(nil), old.Subtasks...)
+	has := map[string]bool{}
+	for _, st := range task.Subtasks {
+		has[st.Title] = true
+	}
+	for _, item := range checklist.Items {
+		if !has[item] {
+			task.Subtasks = append(
+				task.Subtasks, tasktbl.NewSubtask(item, false),
+			)
+			has[item] = true
+		}
+	}
+
+	// update the task if it got new subtasks
+	changes := histtbl.Diff(old, task)
+	if len(changes) == 0 {
+		h.writeResp(w, http.StatusOK, ChecklistResp{Task: &task})
+		return
+	}
+	if err = h.taskUpdater.Update(
+		r.Context(), task,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, ChecklistResp{
+			Error: "Task not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the new subtasks in the task's history - the task has already
+	// been updated at this point, so a failure here is only logged
+	if err = h.histInserter.Insert(r.Context(), histtbl.NewEntry(
+		task.ID, auth.TeamID, auth.Username, changes,
+	)); err != nil {
+		h.log.Error(err)
+	}
+
+	h.writeResp(w, http.StatusOK, ChecklistResp{Task: &task})
+}
+
+// writeResp writes the given status and response.
+func (h ChecklistHandler) writeResp(
+	w http.ResponseWriter, status int, resp ChecklistResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
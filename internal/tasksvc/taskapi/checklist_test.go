@@ -0,0 +1,161 @@
+//go:build utest
+
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestChecklistHandler tests the Handle method of ChecklistHandler to assert
+// that it behaves correctly in all possible scenarios.
+func TestChecklistHandler(t *testing.T) {
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskUpdater := &db.FakeUpdater[tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
+	log := &log.FakeErrorer{}
+	sut := NewChecklistHandler(
+		taskRetriever, teamRetriever, taskUpdater, histInserter, log,
+	)
+
+	task := tasktbl.Task{
+		TeamID:   "team1",
+		BoardID:  "board1",
+		ID:       "task1",
+		Subtasks: []tasktbl.Subtask{{Title: "Tag it", IsDone: true}},
+	}
+	teamRetriever.Res = teamtbl.Team{Checklists: []teamtbl.Checklist{
+		{ID: "release", Items: []string{"Tag it", "Announce it"}},
+		{ID: "tagging", Items: []string{"Tag it"}},
+	}}
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1", Username: "bob"}
+
+	for _, c := range []struct {
+		name            string
+		auth            cookie.Auth
+		reqBody         string
+		errRetrieve     error
+		errRetrieveTeam error
+		errUpdate       error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NotAdmin",
+			auth:       cookie.Auth{IsAdmin: false},
+			reqBody:    `{"checklistID": "release"}`,
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr("Only team admins can edit tasks."),
+		},
+		{
+			name:        "TaskNotFound",
+			auth:        admin,
+			reqBody:     `{"checklistID": "release"}`,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Task not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			auth:        admin,
+			reqBody:     `{"checklistID": "release"}`,
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			auth:            admin,
+			reqBody:         `{"checklistID": "release"}`,
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:       "ChecklistNotFound",
+			auth:       admin,
+			reqBody:    `{"checklistID": "onboarding"}`,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Checklist not found."),
+		},
+		{
+			name:       "ErrUpdate",
+			auth:       admin,
+			reqBody:    `{"checklistID": "release"}`,
+			errUpdate:  errors.New("update failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:       "NothingNew",
+			auth:       admin,
+			reqBody:    `{"checklistID": "tagging"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, taskUpdater.Updated.ID, "")
+				assert.Equal(t.Error, histInserter.Inserted.TaskID, "")
+			},
+		},
+		{
+			name:       "OK",
+			auth:       admin,
+			reqBody:    `{"checklistID": "release"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				// the item the task has already is skipped, and the new one
+				// is added not done
+				want := []tasktbl.Subtask{
+					{Title: "Tag it", IsDone: true},
+					{Title: "Announce it", IsDone: false},
+				}
+				assert.AllEqual(t.Error, taskUpdater.Updated.Subtasks, want)
+
+				var resp ChecklistResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+				assert.AllEqual(t.Error, resp.Task.Subtasks, want)
+
+				entry := histInserter.Inserted
+				assert.Equal(t.Error, entry.TaskID, "task1")
+				assert.Equal(t.Fatal, len(entry.Changes), 1)
+				assert.Equal(t.Error, entry.Changes[0].Field, "subtasks")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			taskRetriever.Res = task
+			taskRetriever.Err = c.errRetrieve
+			teamRetriever.Err = c.errRetrieveTeam
+			taskUpdater.Err = c.errUpdate
+			taskUpdater.Updated = tasktbl.Task{}
+			histInserter.Inserted = histtbl.Entry{}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				),
+				map[string]string{"taskID": "task1"},
+			)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 )
 
@@ -16,87 +19,99 @@ type DeleteResp struct {
 }
 
 // DeleteHandler is an api.MethodHandler that can be used to handle DELETE
-// requests made to the task route.
+// requests made to the task route. Deleted tasks are moved to the trash, from
+// which they can be restored until they expire.
 type DeleteHandler struct {
-	authDecoder cookie.Decoder[cookie.Auth]
-	taskDeleter db.DeleterDualKey
-	log         log.Errorer
+	taskRetriever db.RetrieverDualKey[tasktbl.Task]
+	trashInserter db.Inserter[trashtbl.Item]
+	trashDeleter  db.DeleterDualKey
+	taskDeleter   db.DeleterDualKey
+	log           log.Errorer
 }
 
 // NewDeleteHandler creates and returns a new DELETEHandler.
 func NewDeleteHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	trashInserter db.Inserter[trashtbl.Item],
+	trashDeleter db.DeleterDualKey,
 	taskDeleter db.DeleterDualKey,
 	log log.Errorer,
 ) DeleteHandler {
 	return DeleteHandler{
-		authDecoder: authDecoder,
-		taskDeleter: taskDeleter,
-		log:         log,
+		taskRetriever: taskRetriever,
+		trashInserter: trashInserter,
+		trashDeleter:  trashDeleter,
+		taskDeleter:   taskDeleter,
+		log:           log,
 	}
 }
 
 // Handle handles the DELETE requests sent to the task route.
 func (h DeleteHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if encodeErr := json.NewEncoder(w).Encode(DeleteResp{
-			Error: "Auth token not found.",
-		}); encodeErr != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+	// validate user is admin
+	if !auth.IsAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Only team admins can delete tasks.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
+	}
+
+	// read task ID from the path if present, otherwise fall back to the
+	// deprecated query parameter
+	id := api.PathParam(r, "taskID")
+	if id == "" {
+		id = r.URL.Query().Get("id")
+	}
+
+	// move the task to the trash
+	task, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID, id)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeNotFound(w)
+		return
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(DeleteResp{
-			Error: "Invalid auth token.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-			return
-		}
+	if err = h.trashInserter.Insert(
+		r.Context(), trashtbl.NewTaskItem(auth.TeamID, auth.Username, task),
+	); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
-		w.WriteHeader(http.StatusForbidden)
-		if err = json.NewEncoder(w).Encode(DeleteResp{
-			Error: "Only team admins can delete tasks.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+	// delete task from the task table, taking it back out of the trash if that
+	// fails
+	if err = h.taskDeleter.Delete(r.Context(), auth.TeamID, id); err != nil {
+		if err := h.trashDeleter.Delete(
+			r.Context(), auth.TeamID, id,
+		); err != nil {
 			h.log.Error(err)
-			return
 		}
 	}
-
-	// delete task from the task table
-	if err = h.taskDeleter.Delete(
-		r.Context(), auth.TeamID, r.URL.Query().Get("id"),
-	); errors.Is(err, db.ErrNoItem) {
-		w.WriteHeader(http.StatusNotFound)
-		if err := json.NewEncoder(w).Encode(DeleteResp{
-			Error: "Task not found.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-			return
-		}
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeNotFound(w)
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 }
+
+// writeNotFound writes the response for a task that does not exist.
+func (h DeleteHandler) writeNotFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	if err := json.NewEncoder(w).Encode(DeleteResp{
+		Error: "Task not found.",
+	}); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
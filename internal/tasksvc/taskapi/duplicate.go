@@ -0,0 +1,204 @@
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// DuplicateReq defines the body of POST task duplicate requests, which can be
+// empty. Column is the column the copy is put in, and defaults to that of the
+// task.
+type DuplicateReq struct {
+	Column *int `json:"column,omitempty"`
+}
+
+// DuplicateResp defines the body of POST task duplicate responses.
+type DuplicateResp struct {
+	Error string        `json:"error,omitempty"`
+	Task  *tasktbl.Task `json:"task,omitempty"`
+}
+
+// DuplicateHandler is an api.MethodHandler that can handle POST requests sent
+// to the task duplicate route.
+type DuplicateHandler struct {
+	colNoValidator   validator.Int
+	quota            quota.Quota
+	taskRetriever    db.RetrieverDualKey[tasktbl.Task]
+	teamRetriever    db.Retriever[teamtbl.Team]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	taskInserter     db.Inserter[tasktbl.Task]
+	histInserter     db.Inserter[histtbl.Entry]
+	log              log.Errorer
+}
+
+// NewDuplicateHandler creates and returns a new DuplicateHandler.
+func NewDuplicateHandler(
+	colNoValidator validator.Int,
+	quota quota.Quota,
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
+	taskInserter db.Inserter[tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
+	log log.Errorer,
+) DuplicateHandler {
+	return DuplicateHandler{
+		colNoValidator:   colNoValidator,
+		quota:            quota,
+		taskRetriever:    taskRetriever,
+		teamRetriever:    teamRetriever,
+		retrieverByBoard: retrieverByBoard,
+		taskInserter:     taskInserter,
+		histInserter:     histInserter,
+		log:              log,
+	}
+}
+
+// Handle handles POST requests sent to the task duplicate route. It creates a
+// copy of the task at the end of the requested column of its board, with its
+// subtasks not done, and responds with the copy. The copy is not in a sprint
+// even if the task is, so that recurring work starts in the backlog.
+func (h DuplicateHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, DuplicateResp{
+			Error: "Only team admins can create tasks.",
+		})
+		return
+	}
+
+	// read and validate request body
+	var req DuplicateReq
+	if err := json.NewDecoder(
+		r.Body,
+	).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if req.Column != nil {
+		if err := h.colNoValidator.Validate(*req.Column); err != nil {
+			h.writeResp(w, http.StatusBadRequest, DuplicateResp{
+				Error: "Invalid column number.",
+			})
+			return
+		}
+	}
+
+	// retrieve the task to duplicate
+	task, err := h.taskRetriever.Retrieve(
+		r.Context(), auth.TeamID, api.PathParam(r, "taskID"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, DuplicateResp{
+			Error: "Task not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// check the board has room for another task
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	boardTasks, err := h.retrieverByBoard.Retrieve(r.Context(), task.BoardID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if !h.quota.Override(team.Quota).AllowsTask(len(boardTasks)) {
+		h.writeResp(w, http.StatusBadRequest, DuplicateResp{
+			Error: "You have already created the maximum amount of tasks " +
+				"allowed per board. Please delete one of the board's " +
+				"tasks to create a new one.",
+		})
+		return
+	}
+
+	// rank the copy at the end of its column
+	colNo := task.ColNo
+	if req.Column != nil {
+		colNo = *req.Column
+	}
+	var col []tasktbl.Task
+	for _, t := range boardTasks {
+		if t.ColNo == colNo {
+			col = append(col, t)
+		}
+	}
+	tasktbl.SortByRank(col)
+	subtasks := make([]tasktbl.Subtask, len(task.Subtasks))
+	for i, st := range task.Subtasks {
+		subtasks[i] = tasktbl.NewSubtask(st.Title, false)
+	}
+
+	// insert the copy into the task table - retry up to 3 times for the
+	// unlikely event that the generated UUID is a duplicate
+	var dup tasktbl.Task
+	for i := 0; i < 3; i++ {
+		dup = tasktbl.NewTask(
+			auth.TeamID,
+			task.BoardID,
+			colNo,
+			uuid.NewString(),
+			task.Title,
+			task.Description,
+			len(col),
+			subtasks,
+		)
+		dup.Rank = tasktbl.RankAt(col, len(col))
+		if err = h.taskInserter.Insert(
+			r.Context(), dup,
+		); !errors.Is(err, db.ErrDupKey) {
+			break
+		}
+	}
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the creation in the copy's history - the copy has already been
+	// inserted at this point, so a failure here is only logged
+	if err = h.histInserter.Insert(r.Context(), histtbl.NewEntry(
+		dup.ID, auth.TeamID, auth.Username, histtbl.Created(dup),
+	)); err != nil {
+		h.log.Error(err)
+	}
+
+	h.writeResp(w, http.StatusCreated, DuplicateResp{Task: &dup})
+}
+
+// writeResp writes the given status and response.
+func (h DuplicateHandler) writeResp(
+	w http.ResponseWriter, status int, resp DuplicateResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
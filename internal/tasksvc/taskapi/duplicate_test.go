@@ -0,0 +1,226 @@
+//go:build utest
+
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// TestDuplicateHandler tests the Handle method of DuplicateHandler to assert
+// that it behaves correctly in all possible scenarios.
+func TestDuplicateHandler(t *testing.T) {
+	colNoValidator := &api.FakeIntValidator{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
+	taskInserter := &db.FakeInserter[tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
+	log := &log.FakeErrorer{}
+	sut := NewDuplicateHandler(
+		colNoValidator,
+		quota.Quota{MaxTasksPerBoard: 4},
+		taskRetriever,
+		teamRetriever,
+		retrieverByBoard,
+		taskInserter,
+		histInserter,
+		log,
+	)
+
+	task := tasktbl.Task{
+		TeamID:      "team1",
+		BoardID:     "board1",
+		ColNo:       1,
+		ID:          "task1",
+		Title:       "Weekly report",
+		Description: "Send it to *everyone*.",
+		Subtasks: []tasktbl.Subtask{
+			{Title: "Write", IsDone: true},
+			{Title: "Send", IsDone: false},
+		},
+		SprintID: "sprint1",
+		Rank:     "i",
+	}
+	boardTasks := []tasktbl.Task{
+		task,
+		{BoardID: "board1", ColNo: 1, ID: "task2", Rank: "m"},
+		{BoardID: "board1", ColNo: 2, ID: "task3", Rank: "c"},
+	}
+
+	// wantCopy returns an assert func that checks the copy in the response
+	// and the one inserted are in the given column after the given rank
+	wantCopy := func(
+		colNo int, after string,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, r *http.Response, _ []any) {
+			var resp DuplicateResp
+			err := json.NewDecoder(r.Body).Decode(&resp)
+			assert.Nil(t.Fatal, err)
+			assert.True(t.Fatal, resp.Task != nil)
+			assert.Equal(t.Error, resp.Task.ID, taskInserter.Inserted.ID)
+
+			got := taskInserter.Inserted
+			assert.True(t.Error, got.ID != "" && got.ID != task.ID)
+			assert.Equal(t.Error, got.TeamID, "team1")
+			assert.Equal(t.Error, got.BoardID, "board1")
+			assert.Equal(t.Error, got.ColNo, colNo)
+			assert.Equal(t.Error, got.Title, task.Title)
+			assert.Equal(t.Error, got.Description, task.Description)
+			assert.Equal(t.Error, got.SprintID, "")
+			assert.True(t.Error, got.Rank > after)
+			assert.Equal(t.Fatal, len(got.Subtasks), 2)
+			for i, st := range got.Subtasks {
+				assert.Equal(t.Error, st.Title, task.Subtasks[i].Title)
+				assert.Equal(t.Error, st.IsDone, false)
+			}
+
+			// the original's subtasks are left as they were
+			assert.Equal(t.Error, task.Subtasks[0].IsDone, true)
+			assert.Equal(t.Error, histInserter.Inserted.TaskID, got.ID)
+		}
+	}
+
+	for _, c := range []struct {
+		name             string
+		auth             cookie.Auth
+		reqBody          string
+		errValidateColNo error
+		errRetrieve      error
+		errRetrieveTeam  error
+		boardTasks       []tasktbl.Task
+		errRetrieveBoard error
+		errInsert        error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NotAdmin",
+			auth:       cookie.Auth{IsAdmin: false},
+			reqBody:    "",
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can create tasks.",
+			),
+		},
+		{
+			name:             "InvalidColumn",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 9}`,
+			errValidateColNo: errors.New("invalid column"),
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Invalid column number."),
+		},
+		{
+			name:        "TaskNotFound",
+			auth:        cookie.Auth{IsAdmin: true},
+			reqBody:     "",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Task not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			auth:        cookie.Auth{IsAdmin: true},
+			reqBody:     "",
+			errRetrieve: errors.New("retrieve task failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			auth:            cookie.Auth{IsAdmin: true},
+			reqBody:         "",
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:             "ErrRetrieveBoard",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          "",
+			errRetrieveBoard: errors.New("retrieve board failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve board failed"),
+		},
+		{
+			name:    "QuotaReached",
+			auth:    cookie.Auth{IsAdmin: true},
+			reqBody: "",
+			boardTasks: append(
+				[]tasktbl.Task{{BoardID: "board1", ID: "task4"}},
+				boardTasks...,
+			),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"You have already created the maximum amount of tasks " +
+					"allowed per board. Please delete one of the board's " +
+					"tasks to create a new one.",
+			),
+		},
+		{
+			name:       "ErrInsert",
+			auth:       cookie.Auth{IsAdmin: true},
+			reqBody:    "",
+			boardTasks: boardTasks,
+			errInsert:  errors.New("insert failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:       "OKSameColumn",
+			auth:       cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			reqBody:    "",
+			boardTasks: boardTasks,
+			wantStatus: http.StatusCreated,
+			assertFunc: wantCopy(1, "m"),
+		},
+		{
+			name:       "OKOtherColumn",
+			auth:       cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			reqBody:    `{"column": 2}`,
+			boardTasks: boardTasks,
+			wantStatus: http.StatusCreated,
+			assertFunc: wantCopy(2, "c"),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			colNoValidator.Err = c.errValidateColNo
+			taskRetriever.Res = task
+			taskRetriever.Err = c.errRetrieve
+			teamRetriever.Err = c.errRetrieveTeam
+			retrieverByBoard.Res = c.boardTasks
+			retrieverByBoard.Err = c.errRetrieveBoard
+			taskInserter.Err = c.errInsert
+			taskInserter.Inserted = tasktbl.Task{}
+			histInserter.Inserted = histtbl.Entry{}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				),
+				map[string]string{"taskID": "task1"},
+			)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
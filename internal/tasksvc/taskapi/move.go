@@ -0,0 +1,216 @@
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// MoveReq defines the body of POST task move requests. Position is the index
+// the task is to be at within the column it is moved to, and is clamped to the
+// end of the column.
+type MoveReq struct {
+	Column   int `json:"column"`
+	Position int `json:"position"`
+}
+
+// MoveResp defines the body of POST task move responses. Warning is set if
+// the task was moved into the last column while it is blocked.
+type MoveResp struct {
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// MoveHandler is an api.MethodHandler that can handle POST requests sent to
+// the task move route.
+type MoveHandler struct {
+	colNoValidator   validator.Int
+	taskRetriever    db.RetrieverDualKey[tasktbl.Task]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	teamRetriever    db.Retriever[teamtbl.Team]
+	tasksUpdater     db.Updater[[]tasktbl.Task]
+	histInserter     db.Inserter[histtbl.Entry]
+	log              log.Errorer
+}
+
+// NewMoveHandler creates and returns a new MoveHandler.
+func NewMoveHandler(
+	colNoValidator validator.Int,
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	tasksUpdater db.Updater[[]tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
+	log log.Errorer,
+) MoveHandler {
+	return MoveHandler{
+		colNoValidator:   colNoValidator,
+		taskRetriever:    taskRetriever,
+		retrieverByBoard: retrieverByBoard,
+		teamRetriever:    teamRetriever,
+		tasksUpdater:     tasksUpdater,
+		histInserter:     histInserter,
+		log:              log,
+	}
+}
+
+// Handle handles POST requests sent to the task move route. It moves the task
+// to the requested position in the requested column of its board, ranking it
+// against the stored tasks of the column rather than an order computed by the
+// client so that moves made by different users at the same time do not undo
+// each other. Only the tasks whose ranks change are written, in a single
+// transaction, which is the moved task alone unless the column has to be
+// rebalanced. The move of a blocked task into the last column is refused or
+// warned about if its board is set to.
+func (h MoveHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeErr(w, http.StatusForbidden, "Only team admins can edit tasks.")
+		return
+	}
+
+	// read and validate request body
+	var req MoveReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if err := h.colNoValidator.Validate(req.Column); err != nil {
+		h.writeErr(w, http.StatusBadRequest, "Invalid column number.")
+		return
+	}
+	if req.Position < 0 {
+		h.writeErr(w, http.StatusBadRequest, "Position cannot be negative.")
+		return
+	}
+
+	// retrieve the task to move
+	old, err := h.taskRetriever.Retrieve(
+		r.Context(), auth.TeamID, api.PathParam(r, "taskID"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "Task not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the stored tasks of the board to rank the task against the
+	// ones in the column it is moved to
+	boardTasks, err := h.retrieverByBoard.Retrieve(r.Context(), old.BoardID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	stored := map[string]tasktbl.Task{}
+	var col []tasktbl.Task
+	for _, t := range boardTasks {
+		stored[t.ID] = t
+		if t.ColNo == req.Column && t.ID != old.ID {
+			col = append(col, t)
+		}
+	}
+	sort.SliceStable(col, func(i, j int) bool {
+		return tasktbl.Less(col[i], col[j])
+	})
+
+	// refuse or warn about the move of a blocked task into the last column
+	// if its board is set to
+	var warning string
+	if req.Column == validator.MaxColNo && old.ColNo != validator.MaxColNo &&
+		old.IsBlocked(boardTasks, validator.MaxColNo) {
+		setting, err := blockedTasks(
+			r.Context(), h.teamRetriever, auth.TeamID, old.BoardID,
+		)
+		if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		switch setting {
+		case teamtbl.BlockedTasksRefuse:
+			h.writeErr(w, http.StatusConflict, blockedMsg)
+			return
+		case teamtbl.BlockedTasksWarn:
+			warning = blockedMsg
+		}
+	}
+
+	// insert the task at the requested position and rank the column, keeping
+	// the stored ranks of the tasks that did not move
+	task := old
+	task.ColNo = req.Column
+	if task.ColNo != old.ColNo {
+		// a task that is moved out of the last column is in use again, so it
+		// is no longer archived
+		task.ArchivedAt = 0
+	}
+	pos := min(req.Position, len(col))
+	col = append(col[:pos], append([]tasktbl.Task{task}, col[pos:]...)...)
+	tasktbl.Rerank(col, stored)
+
+	// only write the tasks whose ranks or columns changed
+	var changed []tasktbl.Task
+	for _, t := range col {
+		s, ok := stored[t.ID]
+		if !ok || s.Rank != t.Rank || s.ColNo != t.ColNo {
+			changed = append(changed, t)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if err = h.tasksUpdater.Update(
+		r.Context(), changed,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "Task not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the move in the task's history if it changed columns - the task
+	// has already been moved at this point, so a failure here is only logged
+	if changes := histtbl.Diff(old, task); len(changes) > 0 {
+		if err := h.histInserter.Insert(r.Context(), histtbl.NewEntry(
+			task.ID, auth.TeamID, auth.Username, changes,
+		)); err != nil {
+			h.log.Error(err)
+		}
+	}
+
+	if warning != "" {
+		if err := json.NewEncoder(w).Encode(
+			MoveResp{Warning: warning},
+		); err != nil {
+			h.log.Error(err)
+		}
+	}
+}
+
+// writeErr writes the given status and error message to the response.
+func (h MoveHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(MoveResp{Error: msg}); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
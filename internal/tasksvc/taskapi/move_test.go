@@ -0,0 +1,341 @@
+//go:build utest
+
+package taskapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestMoveHandler tests the Handle method of MoveHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestMoveHandler(t *testing.T) {
+	colNoValidator := &api.FakeIntValidator{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	tasksUpdater := &db.FakeUpdater[[]tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
+	log := &log.FakeErrorer{}
+	sut := NewMoveHandler(
+		colNoValidator,
+		taskRetriever,
+		retrieverByBoard,
+		teamRetriever,
+		tasksUpdater,
+		histInserter,
+		log,
+	)
+
+	task := tasktbl.Task{
+		TeamID: "team1", BoardID: "board1", ColNo: 0, ID: "task1", Rank: "i",
+	}
+	ranked := []tasktbl.Task{
+		task,
+		{TeamID: "team1", BoardID: "board1", ColNo: 1, ID: "task3", Rank: "m"},
+		{TeamID: "team1", BoardID: "board1", ColNo: 1, ID: "task2", Rank: "c"},
+	}
+	unranked := []tasktbl.Task{
+		task,
+		{TeamID: "team1", BoardID: "board1", ColNo: 1, ID: "task2", Order: 0},
+		{TeamID: "team1", BoardID: "board1", ColNo: 1, ID: "task3", Order: 1},
+	}
+
+	for _, c := range []struct {
+		name             string
+		auth             cookie.Auth
+		reqBody          string
+		errValidateColNo error
+		errRetrieve      error
+		boardTasks       []tasktbl.Task
+		errRetrieveBoard error
+		errUpdate        error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "NotAdmin",
+			auth:             cookie.Auth{IsAdmin: false},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       nil,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit tasks.",
+			),
+		},
+		{
+			name:             "InvalidColumn",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 9, "position": 1}`,
+			errValidateColNo: errors.New("invalid column"),
+			errRetrieve:      nil,
+			boardTasks:       nil,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Invalid column number."),
+		},
+		{
+			name:             "NegativePosition",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": -1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       nil,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Position cannot be negative.",
+			),
+		},
+		{
+			name:             "TaskNotFound",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      db.ErrNoItem,
+			boardTasks:       nil,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Task not found."),
+		},
+		{
+			name:             "ErrRetrieve",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      errors.New("retrieve task failed"),
+			boardTasks:       nil,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:             "ErrRetrieveBoard",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       nil,
+			errRetrieveBoard: errors.New("retrieve board failed"),
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve board failed"),
+		},
+		{
+			name:             "UpdateNotFound",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       ranked,
+			errRetrieveBoard: nil,
+			errUpdate:        db.ErrNoItem,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Task not found."),
+		},
+		{
+			name:             "ErrUpdate",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       ranked,
+			errRetrieveBoard: nil,
+			errUpdate:        errors.New("update failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:             "Unmoved",
+			auth:             cookie.Auth{IsAdmin: true},
+			reqBody:          `{"column": 0, "position": 5}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       ranked,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, len(tasksUpdater.Updated), 0)
+				assert.Equal(t.Error, histInserter.Inserted.TaskID, "")
+			},
+		},
+		{
+			name:             "OK",
+			auth:             cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       ranked,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// only the moved task is written, ranked between the tasks
+				// around its new position
+				assert.Equal(t.Fatal, len(tasksUpdater.Updated), 1)
+				got := tasksUpdater.Updated[0]
+				assert.Equal(t.Error, got.ID, "task1")
+				assert.Equal(t.Error, got.ColNo, 1)
+				assert.True(t.Error, got.Rank > "c" && got.Rank < "m")
+
+				entry := histInserter.Inserted
+				assert.Equal(t.Error, entry.TaskID, "task1")
+				assert.Equal(t.Fatal, len(entry.Changes), 1)
+				assert.Equal(t.Error,
+					entry.Changes[0].Field, histtbl.FieldColumn,
+				)
+			},
+		},
+		{
+			name:             "OKRebalance",
+			auth:             cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			reqBody:          `{"column": 1, "position": 1}`,
+			errValidateColNo: nil,
+			errRetrieve:      nil,
+			boardTasks:       unranked,
+			errRetrieveBoard: nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the column has unranked tasks, so all of its tasks are
+				// written with new ranks in their new order
+				got := tasksUpdater.Updated
+				assert.Equal(t.Fatal, len(got), 3)
+				var ids []string
+				for _, task := range got {
+					ids = append(ids, task.ID)
+				}
+				assert.AllEqual(t.Error,
+					ids, []string{"task2", "task1", "task3"},
+				)
+				assert.True(t.Error,
+					got[0].Rank < got[1].Rank && got[1].Rank < got[2].Rank,
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			colNoValidator.Err = c.errValidateColNo
+			taskRetriever.Res = task
+			taskRetriever.Err = c.errRetrieve
+			retrieverByBoard.Res = c.boardTasks
+			retrieverByBoard.Err = c.errRetrieveBoard
+			tasksUpdater.Err = c.errUpdate
+			tasksUpdater.Updated = nil
+			histInserter.Inserted = histtbl.Entry{}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				),
+				map[string]string{"taskID": "task1"},
+			)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+
+	t.Run("Blocked", func(t *testing.T) {
+		// task1 is blocked by task2, which is not done, and by task9, which
+		// is deleted
+		blocked := task
+		blocked.BlockedBy = []string{"task2", "task9"}
+		boardTasks := []tasktbl.Task{
+			blocked,
+			{TeamID: "team1", BoardID: "board1", ColNo: 2, ID: "task2"},
+		}
+
+		for _, c := range []struct {
+			name        string
+			setting     string
+			errTeam     error
+			wantStatus  int
+			wantWarning string
+			wantMoved   bool
+		}{
+			{
+				name:       "Allow",
+				setting:    "",
+				wantStatus: http.StatusOK,
+				wantMoved:  true,
+			},
+			{
+				name:       "ErrRetrieveTeam",
+				setting:    teamtbl.BlockedTasksRefuse,
+				errTeam:    errors.New("retrieve team failed"),
+				wantStatus: http.StatusInternalServerError,
+			},
+			{
+				name:       "Refuse",
+				setting:    teamtbl.BlockedTasksRefuse,
+				wantStatus: http.StatusConflict,
+			},
+			{
+				name:        "Warn",
+				setting:     teamtbl.BlockedTasksWarn,
+				wantStatus:  http.StatusOK,
+				wantWarning: "Task is blocked by tasks that are not done.",
+				wantMoved:   true,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				colNoValidator.Err = nil
+				taskRetriever.Res = blocked
+				taskRetriever.Err = nil
+				retrieverByBoard.Res = boardTasks
+				retrieverByBoard.Err = nil
+				teamRetriever.Res = teamtbl.Team{Boards: []teamtbl.Board{
+					{ID: "board1", BlockedTasks: c.setting},
+				}}
+				teamRetriever.Err = c.errTeam
+				tasksUpdater.Err = nil
+				tasksUpdater.Updated = nil
+				w := httptest.NewRecorder()
+				r := api.WithPathParams(
+					httptest.NewRequest(
+						http.MethodPost, "/",
+						strings.NewReader(`{"column": 3, "position": 0}`),
+					),
+					map[string]string{"taskID": "task1"},
+				)
+
+				sut.Handle(w, r, cookie.Auth{IsAdmin: true, TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				assert.Equal(t.Error,
+					len(tasksUpdater.Updated) > 0, c.wantMoved,
+				)
+				var body MoveResp
+				_ = json.NewDecoder(resp.Body).Decode(&body)
+				assert.Equal(t.Error, body.Warning, c.wantWarning)
+			})
+		}
+	})
+}
@@ -5,89 +5,80 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // PatchReq defines the body of PATCH task requests.
 type PatchReq tasktbl.Task
 
-// PatchResp defines the body of PATCH task responses.
+// PatchResp defines the body of PATCH task responses. Warning is set if the
+// task was moved into the last column while it is blocked.
 type PatchResp struct {
-	Error string `json:"error"`
+	Error   string `json:"error"`
+	Warning string `json:"warning,omitempty"`
 }
 
 // PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
 // the task route.
 type PatchHandler struct {
-	authDecoder        cookie.Decoder[cookie.Auth]
 	titleValidator     validator.String
+	descValidator      validator.String
 	subtTitleValidator validator.String
+	teamRetriever      db.Retriever[teamtbl.Team]
+	taskRetriever      db.RetrieverDualKey[tasktbl.Task]
+	retrieverByBoard   db.Retriever[[]tasktbl.Task]
 	taskUpdater        db.Updater[tasktbl.Task]
+	histInserter       db.Inserter[histtbl.Entry]
 	log                log.Errorer
 }
 
 // NewPatchHandler returns a new PatchHandler.
 func NewPatchHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	taskTitleValidator validator.String,
+	taskDescValidator validator.String,
 	subtaskTitleValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
 	taskUpdater db.Updater[tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
 	log log.Errorer,
 ) *PatchHandler {
 	return &PatchHandler{
-		authDecoder:        authDecoder,
 		titleValidator:     taskTitleValidator,
+		descValidator:      taskDescValidator,
 		subtTitleValidator: subtaskTitleValidator,
+		teamRetriever:      teamRetriever,
+		taskRetriever:      taskRetriever,
+		retrieverByBoard:   retrieverByBoard,
 		taskUpdater:        taskUpdater,
+		histInserter:       histInserter,
 		log:                log,
 	}
 }
 
-// Handle handles PATCH requests sent to the task route.
+// Handle handles PATCH requests sent to the task route. The tasks that block
+// the task are set through the task blockers route rather than this one, and
+// the move of a blocked task into the last column is refused or warned about
+// if its board is set to.
 func (h *PatchHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if encodeErr := json.NewEncoder(w).Encode(PatchResp{
-			Error: "Auth token not found.",
-		}); encodeErr != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(PatchResp{
-			Error: "Invalid auth token.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	}
-
 	// validate user is admin
 	if !auth.IsAdmin {
 		w.WriteHeader(http.StatusForbidden)
 		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit tasks.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
@@ -96,11 +87,16 @@ func (h *PatchHandler) Handle(
 	// read request body
 	var req PatchReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 
+	// task ID in the path takes precedence over the one in the body
+	if id := api.PathParam(r, "taskID"); id != "" {
+		req.ID = id
+	}
+
 	// validate task title
 	if err := h.titleValidator.Validate(req.Title); err != nil {
 		var errMsg string
@@ -109,7 +105,7 @@ func (h *PatchHandler) Handle(
 		} else if errors.Is(err, validator.ErrTooLong) {
 			errMsg = "Task title cannot be longer than 50 characters."
 		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 			return
 		}
@@ -118,7 +114,27 @@ func (h *PatchHandler) Handle(
 		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: errMsg,
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// strip the parts of the description that are unsafe to render and
+	// validate what is left
+	req.Description = markdown.Sanitize(req.Description)
+	if err := h.descValidator.Validate(req.Description); err != nil {
+		if !errors.Is(err, validator.ErrTooLong) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Task description cannot be longer than 500 characters.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
@@ -133,7 +149,7 @@ func (h *PatchHandler) Handle(
 			} else if errors.Is(err, validator.ErrTooLong) {
 				errMsg = "Subtask title cannot be longer than 50 characters."
 			} else {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(api.ErrStatus(err))
 				h.log.Error(err)
 				return
 			}
@@ -142,32 +158,155 @@ func (h *PatchHandler) Handle(
 			if err := json.NewEncoder(w).Encode(PatchResp{
 				Error: errMsg,
 			}); err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(api.ErrStatus(err))
 				h.log.Error(err)
 			}
 			return
 		}
 	}
 
-	// update task in task table
+	// retrieve the task as it is before the edit to record what changed
+	old, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID, req.ID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Task not found.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// keep the task on its board unless it is being moved
+	if req.BoardID == "" {
+		req.BoardID = old.BoardID
+	}
+
+	// validate the board the task is moved to and the sprint it is assigned
+	// to belong to the user's team, and that the sprint is on the task's board
+	// - a sprint that was deleted after the task was assigned to it is let
+	// through unless the task is moved
+	moved := req.BoardID != old.BoardID
+	if moved || (req.SprintID != "" && req.SprintID != old.SprintID) {
+		team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		if moved && !team.HasBoard(req.BoardID) {
+			w.WriteHeader(http.StatusNotFound)
+			if err := json.NewEncoder(w).Encode(PatchResp{
+				Error: "Board not found.",
+			}); err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+			}
+			return
+		}
+		if req.SprintID != "" {
+			if s, ok := team.Sprint(req.SprintID); !ok ||
+				s.BoardID != req.BoardID {
+				w.WriteHeader(http.StatusNotFound)
+				if err := json.NewEncoder(w).Encode(PatchResp{
+					Error: "Sprint not found.",
+				}); err != nil {
+					w.WriteHeader(api.ErrStatus(err))
+					h.log.Error(err)
+				}
+				return
+			}
+		}
+	}
+
+	// refuse or warn about the move of a blocked task into the last column
+	// if its board is set to
+	var warning string
+	if req.ColNo == validator.MaxColNo && len(old.BlockedBy) > 0 &&
+		(old.ColNo != validator.MaxColNo || moved) {
+		boardTasks, err := h.retrieverByBoard.Retrieve(
+			r.Context(), req.BoardID,
+		)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		if old.IsBlocked(boardTasks, validator.MaxColNo) {
+			setting, err := blockedTasks(
+				r.Context(), h.teamRetriever, auth.TeamID, req.BoardID,
+			)
+			if err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+				return
+			}
+			switch setting {
+			case teamtbl.BlockedTasksRefuse:
+				w.WriteHeader(http.StatusConflict)
+				if err := json.NewEncoder(w).Encode(PatchResp{
+					Error: blockedMsg,
+				}); err != nil {
+					w.WriteHeader(api.ErrStatus(err))
+					h.log.Error(err)
+				}
+				return
+			case teamtbl.BlockedTasksWarn:
+				warning = blockedMsg
+			}
+		}
+	}
+
+	// update task in task table, keeping the tasks that block it and whether
+	// it is archived unless it is moved out of its column
 	task := tasktbl.Task(req)
 	task.TeamID = auth.TeamID
+	task.BlockedBy = old.BlockedBy
+	if task.ColNo == old.ColNo {
+		task.ArchivedAt = old.ArchivedAt
+	} else {
+		task.ArchivedAt = 0
+	}
 	err = h.taskUpdater.Update(r.Context(), task)
 	if errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Task not found.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 
+	// record the edit in the task's history - the task has already been
+	// updated at this point, so a failure here is only logged
+	if changes := histtbl.Diff(old, task); len(changes) > 0 {
+		if err := h.histInserter.Insert(r.Context(), histtbl.NewEntry(
+			task.ID, auth.TeamID, auth.Username, changes,
+		)); err != nil {
+			h.log.Error(err)
+		}
+	}
+
+	if warning != "" {
+		if err := json.NewEncoder(w).Encode(
+			PatchResp{Warning: warning},
+		); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+	}
+
 	// no need to update state token as it does not store any of the updated
 	// fields and the frontend will have updated its own state already
 }
@@ -3,6 +3,7 @@
 package taskapi
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -13,67 +14,70 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // TestPatchHandler tests the PATCH handler.
 func TestPatchHandler(t *testing.T) {
-	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
 	titleValidator := &api.FakeStringValidator{}
+	descValidator := &api.FakeStringValidator{}
 	subtTitleValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
 	taskUpdater := &db.FakeUpdater[tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
-		decodeAuth,
 		titleValidator,
+		descValidator,
 		subtTitleValidator,
+		teamRetriever,
+		taskRetriever,
+		retrieverByBoard,
 		taskUpdater,
+		histInserter,
 		log,
 	)
 
+	team := teamtbl.Team{
+		ID:     "team1",
+		Boards: []teamtbl.Board{{ID: "board1"}, {ID: "board2"}},
+	}
+
 	for _, c := range []struct {
 		name                 string
-		authToken            string
 		authDecoded          cookie.Auth
-		errDecodeAuth        error
 		errValidateTitle     error
+		errValidateDesc      error
 		errValidateSubtTitle error
+		reqSprintID          string
+		taskRetrieved        tasktbl.Task
+		errRetrieveTask      error
+		team                 teamtbl.Team
+		errRetrieveTeam      error
 		taskUpdaterErr       error
+		errInsertHist        error
 		wantStatusCode       int
 		assertFunc           func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:                 "NoAuth",
-			authToken:            "",
-			authDecoded:          cookie.Auth{},
-			errDecodeAuth:        nil,
-			errValidateTitle:     nil,
-			errValidateSubtTitle: nil,
-			taskUpdaterErr:       nil,
-			wantStatusCode:       http.StatusUnauthorized,
-			assertFunc:           assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:                 "ErrDecodeAuth",
-			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{},
-			errDecodeAuth:        cookie.ErrInvalid,
-			errValidateTitle:     nil,
-			errValidateSubtTitle: nil,
-			taskUpdaterErr:       nil,
-			wantStatusCode:       http.StatusUnauthorized,
-			assertFunc:           assert.OnRespErr("Invalid auth token."),
-		},
 		{
 			name:                 "NotAdmin",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: false},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
 				"Only team admins can edit tasks.",
@@ -81,12 +85,17 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "TaskTitleEmpty",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrEmpty,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task title cannot be empty.",
@@ -94,12 +103,17 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "TaskTitleTooLong",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrTooLong,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task title cannot be longer than 50 characters.",
@@ -107,12 +121,53 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "TaskTitleErr",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrWrongFormat,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr(
+				validator.ErrWrongFormat.Error(),
+			),
+		},
+		{
+			name:                 "TaskDescTooLong",
+			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			errValidateTitle:     nil,
+			errValidateDesc:      validator.ErrTooLong,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Task description cannot be longer than 500 characters.",
+			),
+		},
+		{
+			name:                 "TaskDescErr",
+			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			errValidateTitle:     nil,
+			errValidateDesc:      validator.ErrWrongFormat,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc: assert.OnLoggedErr(
 				validator.ErrWrongFormat.Error(),
@@ -120,12 +175,17 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "SubtaskTitleEmpty",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: validator.ErrEmpty,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Subtask title cannot be empty.",
@@ -133,12 +193,17 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "SubtaskTitleTooLong",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: validator.ErrTooLong,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Subtask title cannot be longer than 50 characters.",
@@ -146,12 +211,17 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "SubtaskTitleErr",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: validator.ErrWrongFormat,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc: assert.OnLoggedErr(
 				validator.ErrWrongFormat.Error(),
@@ -159,63 +229,355 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:                 "TaskNotFound",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      db.ErrNoItem,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusNotFound,
+			assertFunc:           assert.OnRespErr("Task not found."),
+		},
+		{
+			name:                 "TaskRetrieverErr",
+			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      errors.New("retrieve task failed"),
+			team:                 team,
+			errRetrieveTeam:      nil,
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:                 "TeamRetrieverErr",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{BoardID: "board2"},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      errors.New("retrieve team failed"),
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:                 "BoardNotInTeam",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{BoardID: "board2"},
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				ID:     "team1",
+				Boards: []teamtbl.Board{{ID: "board2"}},
+			},
+			errRetrieveTeam: nil,
+			taskUpdaterErr:  nil,
+			errInsertHist:   nil,
+			wantStatusCode:  http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Board not found."),
+		},
+		{
+			name:                 "SameBoard",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{BoardID: "board1"},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      errors.New("retrieve team failed"),
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusOK,
+			assertFunc: func(*testing.T, *http.Response, []any) {
+				// the team is only retrieved when the task is moved to
+				// another board
+			},
+		},
+		{
+			name:                 "SprintTeamRetrieverErr",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "sprint1",
+			taskRetrieved:        tasktbl.Task{BoardID: "board1"},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      errors.New("retrieve team failed"),
+			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:                 "SprintNotInTeam",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "sprint3",
+			taskRetrieved:        tasktbl.Task{BoardID: "board1"},
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				ID:     "team1",
+				Boards: []teamtbl.Board{{ID: "board1"}, {ID: "board2"}},
+				Sprints: []teamtbl.Sprint{
+					{ID: "sprint1", BoardID: "board1"},
+					{ID: "sprint2", BoardID: "board2"},
+				},
+			},
+			errRetrieveTeam: nil,
+			taskUpdaterErr:  nil,
+			errInsertHist:   nil,
+			wantStatusCode:  http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Sprint not found."),
+		},
+		{
+			name:                 "SprintOnOtherBoard",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "sprint2",
+			taskRetrieved:        tasktbl.Task{BoardID: "board1"},
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				ID:     "team1",
+				Boards: []teamtbl.Board{{ID: "board1"}, {ID: "board2"}},
+				Sprints: []teamtbl.Sprint{
+					{ID: "sprint1", BoardID: "board1"},
+					{ID: "sprint2", BoardID: "board2"},
+				},
+			},
+			errRetrieveTeam: nil,
+			taskUpdaterErr:  nil,
+			errInsertHist:   nil,
+			wantStatusCode:  http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Sprint not found."),
+		},
+		{
+			name:                 "SameSprint",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "sprint1",
+			taskRetrieved: tasktbl.Task{
+				BoardID: "board1", SprintID: "sprint1",
+			},
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			taskUpdaterErr:  nil,
+			errInsertHist:   nil,
+			wantStatusCode:  http.StatusOK,
+			assertFunc:      func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                 "SprintAssigned",
+			authDecoded:          cookie.Auth{IsAdmin: true},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "sprint1",
+			taskRetrieved:        tasktbl.Task{BoardID: "board1"},
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				ID:     "team1",
+				Boards: []teamtbl.Board{{ID: "board1"}, {ID: "board2"}},
+				Sprints: []teamtbl.Sprint{
+					{ID: "sprint1", BoardID: "board1"},
+					{ID: "sprint2", BoardID: "board2"},
+				},
+			},
+			errRetrieveTeam: nil,
+			taskUpdaterErr:  nil,
+			errInsertHist:   nil,
+			wantStatusCode:  http.StatusOK,
+			assertFunc:      func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                 "TaskUpdaterNotFound",
+			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       db.ErrNoItem,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusNotFound,
 			assertFunc:           assert.OnRespErr("Task not found."),
 		},
 		{
 			name:                 "TaskUpdaterErr",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       errors.New("update task failed"),
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc:           assert.OnLoggedErr("update task failed"),
 		},
+		{
+			name:                 "HistInserterErr",
+			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			errValidateTitle:     nil,
+			errValidateDesc:      nil,
+			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{Title: "Do something!"},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			taskUpdaterErr:       nil,
+			errInsertHist:        errors.New("insert history failed"),
+			wantStatusCode:       http.StatusOK,
+			assertFunc:           assert.OnLoggedErr("insert history failed"),
+		},
 		{
 			name:                 "Success",
-			authToken:            "nonempty",
 			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
-			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
+			errValidateDesc:      nil,
 			errValidateSubtTitle: nil,
+			reqSprintID:          "",
+			taskRetrieved:        tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
 			taskUpdaterErr:       nil,
+			errInsertHist:        nil,
 			wantStatusCode:       http.StatusOK,
-			assertFunc:           func(*testing.T, *http.Response, []any) {},
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the script should be stripped from the description
+				assert.Equal(t.Error,
+					taskUpdater.Updated.Description, "**a**",
+				)
+			},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			decodeAuth.Res = c.authDecoded
-			decodeAuth.Err = c.errDecodeAuth
 			titleValidator.Err = c.errValidateTitle
+			descValidator.Err = c.errValidateDesc
 			subtTitleValidator.Err = c.errValidateSubtTitle
+			taskRetriever.Res = c.taskRetrieved
+			taskRetriever.Err = c.errRetrieveTask
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
 			taskUpdater.Err = c.taskUpdaterErr
+			histInserter.Err = c.errInsertHist
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/?id=qwerty", strings.NewReader(`{
+				"boardID":     "board1",
 				"column":      0,
 				"title":       "",
-				"description": "",
-				"subtasks":    [{"title": ""}]
+				"description": "**a**<script>x</script>",
+				"subtasks":    [{"title": ""}],
+				"sprintID":    "`+c.reqSprintID+`"
 			}`))
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name:  "auth-token",
-					Value: c.authToken,
-				})
-			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
 			c.assertFunc(t, resp, log.Args)
 		})
 	}
+
+	t.Run("Blocked", func(t *testing.T) {
+		// task1 is blocked by task2, which is not done
+		blocked := tasktbl.Task{
+			TeamID: "team1", BoardID: "board1", ColNo: 1, ID: "task1",
+			BlockedBy: []string{"task2"},
+		}
+		retrieverByBoard.Res = []tasktbl.Task{
+			blocked,
+			{TeamID: "team1", BoardID: "board1", ColNo: 2, ID: "task2"},
+		}
+
+		for _, c := range []struct {
+			name        string
+			setting     string
+			wantStatus  int
+			wantWarning string
+		}{
+			{name: "Allow", setting: "", wantStatus: http.StatusOK},
+			{
+				name:       "Refuse",
+				setting:    teamtbl.BlockedTasksRefuse,
+				wantStatus: http.StatusConflict,
+			},
+			{
+				name:        "Warn",
+				setting:     teamtbl.BlockedTasksWarn,
+				wantStatus:  http.StatusOK,
+				wantWarning: "Task is blocked by tasks that are not done.",
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				titleValidator.Err = nil
+				descValidator.Err = nil
+				subtTitleValidator.Err = nil
+				taskRetriever.Res = blocked
+				taskRetriever.Err = nil
+				teamRetriever.Res = teamtbl.Team{Boards: []teamtbl.Board{
+					{ID: "board1", BlockedTasks: c.setting},
+				}}
+				teamRetriever.Err = nil
+				taskUpdater.Err = nil
+				taskUpdater.Updated = tasktbl.Task{}
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest("", "/?id=task1", strings.NewReader(
+					`{"boardID": "board1", "colNo": 3, "title": "a", `+
+						`"blockedBy": ["task3"]}`,
+				))
+
+				sut.Handle(w, r, cookie.Auth{IsAdmin: true, TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				var body PatchResp
+				_ = json.NewDecoder(resp.Body).Decode(&body)
+				assert.Equal(t.Error, body.Warning, c.wantWarning)
+				if c.wantStatus != http.StatusOK {
+					return
+				}
+
+				// the tasks that block the task are not edited by PATCH
+				assert.AllEqual(t.Error,
+					taskUpdater.Updated.BlockedBy, []string{"task2"},
+				)
+			})
+		}
+	})
 }
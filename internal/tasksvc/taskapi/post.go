@@ -4,13 +4,19 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
+	"github.com/kxplxn/goteam/pkg/quota"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -32,68 +38,47 @@ type PostResp struct {
 // PostHandler is an api.MethodHandler that can be used to handle POST requests
 // sent to the task route.
 type PostHandler struct {
-	authDecoder  cookie.Decoder[cookie.Auth]
-	validateReq  validator.Func[PostReq]
-	taskInserter db.Inserter[tasktbl.Task]
-	log          log.Errorer
+	validateReq      validator.Func[PostReq]
+	quota            quota.Quota
+	teamRetriever    db.Retriever[teamtbl.Team]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	taskInserter     db.Inserter[tasktbl.Task]
+	histInserter     db.Inserter[histtbl.Entry]
+	log              log.Errorer
 }
 
 // NewPostHandler creates and returns a new POSTHandler.
 func NewPostHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	validateReq validator.Func[PostReq],
+	quota quota.Quota,
+	teamRetriever db.Retriever[teamtbl.Team],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
 	taskInserter db.Inserter[tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
 	log log.Errorer,
 ) *PostHandler {
 	return &PostHandler{
-		authDecoder:  authDecoder,
-		validateReq:  validateReq,
-		taskInserter: taskInserter,
-		log:          log,
+		validateReq:      validateReq,
+		quota:            quota,
+		teamRetriever:    teamRetriever,
+		retrieverByBoard: retrieverByBoard,
+		taskInserter:     taskInserter,
+		histInserter:     histInserter,
+		log:              log,
 	}
 }
 
 // Handle handles the POST requests sent to the task route.
 func (h *PostHandler) Handle(
-	w http.ResponseWriter, r *http.Request, _ string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(PostResp{
-			Error: "Auth token not found.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(PostResp{
-			Error: "Invalid auth token.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	}
-
 	// validate user is admin
 	if !auth.IsAdmin {
 		w.WriteHeader(http.StatusForbidden)
 		if err := json.NewEncoder(w).Encode(PostResp{
 			Error: "Only team admins can create tasks.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
@@ -102,67 +87,150 @@ func (h *PostHandler) Handle(
 	// decode request
 	var req PostReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 
+	// board ID in the path takes precedence over the one in the body
+	if boardID := api.PathParam(r, "boardID"); boardID != "" {
+		req.BoardID = boardID
+	}
+
+	// strip the parts of the description that are unsafe to render before
+	// validating it, so that its length is that of what is stored
+	req.Description = markdown.Sanitize(req.Description)
+
 	// validate request
 	if err := h.validateReq(req); err != nil {
-		var msg string
-		switch {
-		case errors.Is(err, errBoardIDEmpty):
-			msg = "Board ID cannot be empty."
-		case errors.Is(err, errParseBoardID):
-			msg = "Board ID is must be a valid UUID."
-		case errors.Is(err, errColNoOutOfBounds):
-			msg = "Column number must be between 0 and 3."
-		case errors.Is(err, errTitleEmpty):
-			msg = "Task title cannot be empty."
-		case errors.Is(err, errTitleTooLong):
-			msg = "Task title cannot be longer than 50 characters."
-		case errors.Is(err, errDescTooLong):
-			msg = "Task description cannot be longer than 500 characters."
-		case errors.Is(err, errSubtaskTitleEmpty):
-			msg = "Subtask title cannot be empty."
-		case errors.Is(err, errSubtaskTitleTooLong):
-			msg = "Subtask title cannot be longer than 50 characters."
-		case errors.Is(err, errOrderNegative):
-			msg = "Order cannot be negative."
-		default:
-			w.WriteHeader(http.StatusInternalServerError)
+		msg, ok := postErrMsg(err)
+		if !ok {
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 			return
 		}
 
 		w.WriteHeader(http.StatusBadRequest)
 		if err = json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	}
 
+	// validate the board belongs to the user's team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if !team.HasBoard(req.BoardID) {
+		w.WriteHeader(http.StatusNotFound)
+		if err = json.NewEncoder(w).Encode(PostResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// check the board has room for another task
+	boardTasks, err := h.retrieverByBoard.Retrieve(r.Context(), req.BoardID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if !h.quota.Override(team.Quota).AllowsTask(len(boardTasks)) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err = json.NewEncoder(w).Encode(PostResp{
+			Error: "You have already created the maximum amount of tasks " +
+				"allowed per board. Please delete one of the board's " +
+				"tasks to create a new one.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// rank the task at the requested order within its column
+	var col []tasktbl.Task
+	for _, t := range boardTasks {
+		if t.ColNo == req.ColNo {
+			col = append(col, t)
+		}
+	}
+	tasktbl.SortByRank(col)
+	rank := tasktbl.RankAt(col, req.Order)
+
 	// insert a new task into the task table - retry up to 3 times for the
 	// unlikely event that the generated UUID is a duplicate
+	var task tasktbl.Task
 	for i := 0; i < 3; i++ {
-		id := uuid.NewString()
-		if err = h.taskInserter.Insert(r.Context(), tasktbl.NewTask(
+		task = tasktbl.NewTask(
 			auth.TeamID,
 			req.BoardID,
 			req.ColNo,
-			id,
+			uuid.NewString(),
 			req.Title,
 			req.Description,
 			req.Order,
 			req.Subtasks,
-		)); !errors.Is(err, db.ErrDupKey) {
+		)
+		task.Rank = rank
+		if err = h.taskInserter.Insert(
+			r.Context(), task,
+		); !errors.Is(err, db.ErrDupKey) {
 			break
 		}
 	}
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
+
+	// record the creation in the task's history so that the column it starts
+	// in is known to team analytics - the task has already been inserted at
+	// this point, so a failure here is only logged
+	if err = h.histInserter.Insert(r.Context(), histtbl.NewEntry(
+		task.ID, auth.TeamID, auth.Username, histtbl.Created(task),
+	)); err != nil {
+		h.log.Error(err)
+	}
+}
+
+// postErrMsg returns the message to respond with for the first field of a
+// PostReq that failed validation, and false if err is not a validation error.
+func postErrMsg(err error) (string, bool) {
+	fe, ok := validator.First(err)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case fe.Path == "boardID" && errors.Is(fe, validator.ErrEmpty):
+		return "Board ID cannot be empty.", true
+	case fe.Path == "boardID":
+		return "Board ID is must be a valid UUID.", true
+	case fe.Path == "colNo":
+		return "Column number must be between 0 and 3.", true
+	case fe.Path == "title" && errors.Is(fe, validator.ErrEmpty):
+		return "Task title cannot be empty.", true
+	case fe.Path == "title":
+		return "Task title cannot be longer than 50 characters.", true
+	case fe.Path == "description":
+		return "Task description cannot be longer than 500 characters.", true
+	case strings.HasPrefix(fe.Path, "subtasks") &&
+		errors.Is(fe, validator.ErrEmpty):
+		return "Subtask title cannot be empty.", true
+	case strings.HasPrefix(fe.Path, "subtasks"):
+		return "Subtask title cannot be longer than 50 characters.", true
+	case fe.Path == "order":
+		return "Order cannot be negative.", true
+	default:
+		return "", false
+	}
 }
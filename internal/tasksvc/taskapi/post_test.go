@@ -12,212 +12,333 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // TestPostHandler tests the Handle method of PostHandler to assert that it
 // behaves correctly in all possible scenarios.
 func TestPostHandler(t *testing.T) {
-	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
 	validate := &validator.FakeFunc[PostReq]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
 	taskInserter := &db.FakeInserter[tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
 	log := &log.FakeErrorer{}
 	sut := NewPostHandler(
-		authDecoder,
 		validate.Func,
+		quota.Quota{MaxTasksPerBoard: 2},
+		teamRetriever,
+		retrieverByBoard,
 		taskInserter,
+		histInserter,
 		log,
 	)
 
+	team := teamtbl.Team{
+		ID:     "team1",
+		Boards: []teamtbl.Board{{ID: "board1"}},
+	}
+
 	for _, c := range []struct {
-		name          string
-		authToken     string
-		authDecoded   cookie.Auth
-		errDecodeAuth error
-		errValidate   error
-		errInsertTask error
-		wantStatus    int
-		assertFunc    func(*testing.T, *http.Response, []any)
+		name            string
+		authDecoded     cookie.Auth
+		errValidate     error
+		team            teamtbl.Team
+		errRetrieveTeam error
+		errRetrieve     error
+		boardTasks      []tasktbl.Task
+		errInsertTask   error
+		errInsertHist   error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:          "NoAuth",
-			authToken:     "",
-			errDecodeAuth: cookie.ErrInvalid,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:          "InvalidAuth",
-			authToken:     "nonempty",
-			errDecodeAuth: cookie.ErrInvalid,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Invalid auth token."),
-		},
-		{
-			name:          "NotAdmin",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{},
-			errDecodeAuth: nil,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusForbidden,
+			name:            "NotAdmin",
+			authDecoded:     cookie.Auth{},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
 				"Only team admins can create tasks.",
 			),
 		},
 		{
-			name:          "ErrBoardIDEmpty",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errBoardIDEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Board ID cannot be empty."),
+			name:        "ErrBoardIDEmpty",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "boardID", Err: validator.ErrEmpty,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Board ID cannot be empty."),
 		},
 		{
-			name:          "ErrParseBoardID",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errParseBoardID,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:        "ErrParseBoardID",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "boardID", Err: validator.ErrWrongFormat,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Board ID is must be a valid UUID.",
 			),
 		},
 		{
-			name:          "ErrColNoOutOfBounds",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errColNoOutOfBounds,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:        "ErrColNoOutOfBounds",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "colNo", Err: validator.ErrOutOfBounds,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Column number must be between 0 and 3.",
 			),
 		},
 		{
-			name:          "ErrTitleEmpty",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errTitleEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Task title cannot be empty."),
+			name:        "ErrTitleEmpty",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "title", Err: validator.ErrEmpty,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Task title cannot be empty."),
 		},
 		{
-			name:          "ErrTitleTooLong",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errTitleTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:        "ErrTitleTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "title", Err: validator.ErrTooLong,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task title cannot be longer than 50 characters.",
 			),
 		},
 		{
-			name:          "ErrDescTooLong",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errDescTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:        "ErrDescTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "description", Err: validator.ErrTooLong,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task description cannot be longer than 500 characters.",
 			),
 		},
 		{
-			name:          "ErrSubtaskTitleEmpty",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errSubtaskTitleEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Subtask title cannot be empty."),
+			name:        "ErrSubtaskTitleEmpty",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.Errs{{
+				Path: "subtasks[1].title", Err: validator.ErrEmpty,
+			}},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Subtask title cannot be empty."),
 		},
 		{
-			name:          "ErrSubtaskTitleTooLong",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errSubtaskTitleTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:        "ErrSubtaskTitleTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.Errs{{
+				Path: "subtasks[0].title", Err: validator.ErrTooLong,
+			}},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Subtask title cannot be longer than 50 characters.",
 			),
 		},
 		{
-			name:          "ErrOrderNegative",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errOrderNegative,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Order cannot be negative."),
-		},
-		{
-			name:          "ErrValidate",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errors.New("validate failed"),
-			errInsertTask: nil,
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("validate failed"),
-		},
-		{
-			name:          "ErrPutTask",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   nil,
-			errInsertTask: errors.New("put task failed"),
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("put task failed"),
-		},
-		{
-			name:          "OK",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusOK,
-			assertFunc:    func(*testing.T, *http.Response, []any) {},
+			name:        "ErrOrderNegative",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.FieldErr{
+				Path: "order", Err: validator.ErrOutOfBounds,
+			},
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Order cannot be negative."),
+		},
+		{
+			name:            "ErrValidate",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     errors.New("validate failed"),
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("validate failed"),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:            "TeamNotFound",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: db.ErrNoItem,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "BoardNotInTeam",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			team: teamtbl.Team{
+				ID:     "team1",
+				Boards: []teamtbl.Board{{ID: "board2"}},
+			},
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Board not found."),
+		},
+		{
+			name:            "ErrRetrieveTasks",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     errors.New("retrieve tasks failed"),
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:            "TaskLimitReached",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			boardTasks:      []tasktbl.Task{{ID: "task1"}, {ID: "task2"}},
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"You have already created the maximum amount of tasks " +
+					"allowed per board. Please delete one of the board's " +
+					"tasks to create a new one.",
+			),
+		},
+		{
+			name:            "ErrPutTask",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   errors.New("put task failed"),
+			errInsertHist:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("put task failed"),
+		},
+		{
+			name:            "HistInserterErr",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   errors.New("insert history failed"),
+			wantStatus:      http.StatusOK,
+			assertFunc:      assert.OnLoggedErr("insert history failed"),
+		},
+		{
+			name:            "OK",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidate:     nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errRetrieve:     nil,
+			errInsertTask:   nil,
+			errInsertHist:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			authDecoder.Res = c.authDecoded
-			authDecoder.Err = c.errDecodeAuth
 			validate.Err = c.errValidate
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			retrieverByBoard.Res = c.boardTasks
+			retrieverByBoard.Err = c.errRetrieve
 			taskInserter.Err = c.errInsertTask
+			histInserter.Err = c.errInsertHist
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
-				http.MethodPost, "/", strings.NewReader("{}"),
+				http.MethodPost, "/",
+				strings.NewReader(`{"boardID": "board1"}`),
 			)
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name: "auth-token", Value: c.authToken,
-				})
-			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -3,6 +3,8 @@
 package taskapi
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/kxplxn/goteam/pkg/assert"
@@ -11,24 +13,28 @@ import (
 )
 
 // TestValidatePostReq tests the ValidatePostReq function to assert that it
-// returns the correct error based on the PostReq input.
+// returns the error of the first field that fails validation based on the
+// PostReq input.
 func TestValidatePostReq(t *testing.T) {
 	sut := ValidatePostReq
 
 	for _, c := range []struct {
-		name    string
-		req     PostReq
-		wantErr error
+		name     string
+		req      PostReq
+		wantPath string
+		wantErr  error
 	}{
 		{
-			name:    "BoardIDEmpty",
-			req:     PostReq{BoardID: ""},
-			wantErr: errBoardIDEmpty,
+			name:     "BoardIDEmpty",
+			req:      PostReq{BoardID: ""},
+			wantPath: "boardID",
+			wantErr:  validator.ErrEmpty,
 		},
 		{
-			name:    "BoardIDInvalid",
-			req:     PostReq{BoardID: "invalid"},
-			wantErr: errParseBoardID,
+			name:     "BoardIDInvalid",
+			req:      PostReq{BoardID: "invalid"},
+			wantPath: "boardID",
+			wantErr:  validator.ErrWrongFormat,
 		},
 		{
 			name: "ColNoTooSmall",
@@ -36,7 +42,8 @@ func TestValidatePostReq(t *testing.T) {
 				BoardID: "00000000-0000-0000-0000-000000000000",
 				ColNo:   -1,
 			},
-			wantErr: errColNoOutOfBounds,
+			wantPath: "colNo",
+			wantErr:  validator.ErrOutOfBounds,
 		},
 		{
 			name: "ColNoTooBig",
@@ -44,7 +51,8 @@ func TestValidatePostReq(t *testing.T) {
 				BoardID: "00000000-0000-0000-0000-000000000000",
 				ColNo:   4,
 			},
-			wantErr: errColNoOutOfBounds,
+			wantPath: "colNo",
+			wantErr:  validator.ErrOutOfBounds,
 		},
 		{
 			name: "TitleEmpty",
@@ -53,7 +61,8 @@ func TestValidatePostReq(t *testing.T) {
 				ColNo:   2,
 				Title:   "",
 			},
-			wantErr: errTitleEmpty,
+			wantPath: "title",
+			wantErr:  validator.ErrEmpty,
 		},
 		{
 			name: "TitleTooLong",
@@ -62,7 +71,8 @@ func TestValidatePostReq(t *testing.T) {
 				ColNo:   2,
 				Title:   "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasd",
 			},
-			wantErr: errTitleTooLong,
+			wantPath: "title",
+			wantErr:  validator.ErrTooLong,
 		},
 		{
 			name: "DescriptionTooLong",
@@ -81,7 +91,8 @@ func TestValidatePostReq(t *testing.T) {
 					"sdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqwe" +
 					"asdqwe",
 			},
-			wantErr: errDescTooLong,
+			wantPath: "description",
+			wantErr:  validator.ErrTooLong,
 		},
 		{
 			name: "SubtaskTitleEmpty",
@@ -92,7 +103,8 @@ func TestValidatePostReq(t *testing.T) {
 				Description: "Some Description",
 				Subtasks:    []tasktbl.Subtask{{Title: ""}},
 			},
-			wantErr: errSubtaskTitleEmpty,
+			wantPath: "subtasks[0].title",
+			wantErr:  validator.ErrEmpty,
 		},
 		{
 			name: "SubtaskTitleTooLong",
@@ -108,7 +120,8 @@ func TestValidatePostReq(t *testing.T) {
 					},
 				},
 			},
-			wantErr: errSubtaskTitleTooLong,
+			wantPath: "subtasks[0].title",
+			wantErr:  validator.ErrTooLong,
 		},
 		{
 			name: "OrderNegative",
@@ -120,7 +133,8 @@ func TestValidatePostReq(t *testing.T) {
 				Subtasks:    []tasktbl.Subtask{{Title: "Some Subtask"}},
 				Order:       -1,
 			},
-			wantErr: errOrderNegative,
+			wantPath: "order",
+			wantErr:  validator.ErrOutOfBounds,
 		},
 		{
 			name: "OK",
@@ -132,44 +146,76 @@ func TestValidatePostReq(t *testing.T) {
 				Subtasks:    []tasktbl.Subtask{{Title: "Some Subtask"}},
 				Order:       0,
 			},
-			wantErr: nil,
+			wantPath: "",
+			wantErr:  nil,
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			err := sut(c.req)
+
+			fe, _ := validator.First(err)
+			assert.Equal(t.Error, fe.Path, c.wantPath)
 			assert.ErrIs(t.Error, err, c.wantErr)
 		})
 	}
 }
 
-// TestTitleValidator tests the TitleValidator.Validate method.
-func TestTitleValidator(t *testing.T) {
-	sut := NewTitleValidator()
+// TestValidatePostReqErrs tests the ValidatePostReq function to assert that
+// it returns the errors of all the fields that fail validation.
+func TestValidatePostReqErrs(t *testing.T) {
+	err := ValidatePostReq(PostReq{
+		BoardID:  "00000000-0000-0000-0000-000000000000",
+		ColNo:    4,
+		Title:    "",
+		Subtasks: []tasktbl.Subtask{{Title: "Some Subtask"}, {Title: ""}},
+	})
 
-	for _, c := range []struct {
-		name    string
-		title   string
-		wantErr error
-	}{
-		{
-			name:    "TitleEmpty",
-			title:   "",
-			wantErr: validator.ErrEmpty,
-		},
-		{
-			name:    "TitleTooLong",
-			title:   "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasd",
-			wantErr: validator.ErrTooLong,
-		},
-		{
-			name:    "Success",
-			title:   "Some Task",
-			wantErr: nil,
-		},
+	var errs validator.Errs
+	assert.True(t.Fatal, errors.As(err, &errs))
+	assert.Equal(t.Fatal, len(errs), 3)
+	assert.Equal(t.Error, errs[0].Path, "colNo")
+	assert.Equal(t.Error, errs[1].Path, "title")
+	assert.Equal(t.Error, errs[2].Path, "subtasks[1].title")
+}
+
+// FuzzValidatePostReq fuzzes the decoding of POST task request bodies and their
+// validation by ValidatePostReq to assert that neither panics, and that a
+// request is rejected with a field error exactly when one of its fields fails
+// its rule.
+func FuzzValidatePostReq(f *testing.F) {
+	for _, body := range []string{
+		`{}`,
+		`{"boardID":"0a2b3c4d-0000-4000-8000-000000000000","colNo":1,` +
+			`"title":"Task","order":0,"subtasks":[{"title":"Sub"}]}`,
+		`{"colNo":-1,"title":"","order":-5}`,
+		`{"colNo":1e3,"subtasks":[null,{"title":null}]}`,
+		`{"title":"\ud800"}`,
+		`[]`,
+		`null`,
 	} {
-		t.Run(c.name, func(t *testing.T) {
-			err := sut.Validate(c.title)
-			assert.ErrIs(t.Error, err, c.wantErr)
-		})
+		f.Add([]byte(body))
 	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req PostReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+
+		err := ValidatePostReq(req)
+
+		wantOK := validator.ID(req.BoardID) == nil &&
+			validator.ColNo(req.ColNo) == nil &&
+			validator.TaskTitle(req.Title) == nil &&
+			validator.TaskDesc(req.Description) == nil &&
+			validator.Order(req.Order) == nil
+		for _, st := range req.Subtasks {
+			wantOK = wantOK && validator.SubtaskTitle(st.Title) == nil
+		}
+		assert.Equal(t.Error, err == nil, wantOK)
+		if err != nil {
+			fe, ok := validator.First(err)
+			assert.True(t.Error, ok && fe.Path != "")
+		}
+	})
 }
@@ -5,81 +5,207 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sort"
+	"strconv"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
+// sort keys and orders that can be requested from the GET tasks route.
+const (
+	sortTitle = "title"
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// renderHTML is the value of the render query parameter that requests the
+// descriptions of the tasks to be rendered into HTML.
+const renderHTML = "html"
+
+// maxLimit is the maximum number of tasks that can be requested in a page.
+const maxLimit = 100
+
 // GetResp defines the body of GET tasks responses.
-type GetResp []tasktbl.Task
+type GetResp []GetTask
+
+// GetTask defines a task in a GetResp. DescriptionHTML is the description
+// rendered into HTML that is safe to display, and is only set if it was
+// requested.
+type GetTask struct {
+	tasktbl.Task
+	DescriptionHTML string `json:"descriptionHTML,omitempty"`
+}
 
 // GetHandler is an api.MethodHandler that can handle GET requests sent to the
 // tasks route.
 type GetHandler struct {
 	boardIDValidator validator.String
+	colNoValidator   validator.Int
 	retrieverByBoard db.Retriever[[]tasktbl.Task]
-	authDecoder      cookie.Decoder[cookie.Auth]
+	pagesByBoard     db.PageRetriever[[]tasktbl.Task]
 	retrieverByTeam  db.Retriever[[]tasktbl.Task]
+	teamRetriever    db.Retriever[teamtbl.Team]
+	cursorSigner     api.CursorSigner
 	log              log.Errorer
 }
 
 // NewGetHandler creates and returns a new GetHandler.
 func NewGetHandler(
 	boardIDValidator validator.String,
+	colNoValidator validator.Int,
 	retrieverByBoard db.Retriever[[]tasktbl.Task],
-	authDecoder cookie.Decoder[cookie.Auth],
+	pagesByBoard db.PageRetriever[[]tasktbl.Task],
 	retrieverByTeam db.Retriever[[]tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	cursorSigner api.CursorSigner,
 	log log.Errorer,
 ) GetHandler {
 	return GetHandler{
 		boardIDValidator: boardIDValidator,
+		colNoValidator:   colNoValidator,
 		retrieverByBoard: retrieverByBoard,
-		authDecoder:      authDecoder,
+		pagesByBoard:     pagesByBoard,
 		retrieverByTeam:  retrieverByTeam,
+		teamRetriever:    teamRetriever,
+		cursorSigner:     cursorSigner,
 		log:              log,
 	}
 }
 
-// Handle handles GET requests sent to the tasks route.
-func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
+// Handle handles GET requests sent to the tasks route. The tasks can be
+// filtered by column number with the column query parameter and sorted with the
+// sort and order query parameters. The tasks of a board can be paginated with
+// the limit and cursor query parameters, in which case the cursor for the next
+// page is sent in the Next-Cursor header. Archived tasks are only sent, in
+// place of the others, if the archived query parameter is true. The
+// descriptions of the tasks are also sent rendered into HTML if the render
+// query parameter is html.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// read the column filter if present
+	colNo := -1
+	if col := r.URL.Query().Get("column"); col != "" {
+		var err error
+		if colNo, err = strconv.Atoi(col); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err = h.colNoValidator.Validate(colNo); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	// read the sort key and order if present
+	sortKey, order := r.URL.Query().Get("sort"), r.URL.Query().Get("order")
+	if sortKey != "" && sortKey != sortTitle {
+		w.WriteHeader(http.StatusBadRequest)
 		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
+	}
+	if order != "" && order != orderAsc && order != orderDesc {
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+	// read whether to get the archived tasks instead of the others
+	archived := false
+	if a := r.URL.Query().Get("archived"); a != "" {
+		var err error
+		if archived, err = strconv.ParseBool(a); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	// read whether to render descriptions
+	render := r.URL.Query().Get("render")
+	if render != "" && render != renderHTML {
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
+	// read the page size and cursor if present
+	limit, cursor := 0, r.URL.Query().Get("cursor")
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 ||
+			limit > maxLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	} else if cursor != "" {
+		limit = maxLimit
+	}
+
 	// get tasks by board ID if present, otherwise get tasks by team ID of the
-	// auth cookie
+	// auth cookie - only the tasks of a board can be paginated
 	var (
 		tasks  []tasktbl.Task
 		status int
 	)
-	if boardID := r.URL.Query().Get("boardID"); boardID != "" {
+	boardID := api.PathParam(r, "boardID")
+	if boardID == "" {
+		boardID = r.URL.Query().Get("boardID")
+	}
+	if limit > 0 && boardID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// non-admin users can only see the tasks of the boards they can access
+	var visible map[string]bool
+	if !auth.IsAdmin {
+		if visible, status = h.getVisibleBoards(
+			r.Context(), auth,
+		); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if boardID != "" && !visible[boardID] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	if limit > 0 {
+		tasks, status = h.getPageByBoardID(
+			r.Context(), auth, w, boardID, limit, cursor,
+		)
+	} else if boardID != "" {
 		tasks, status = h.getByBoardID(r.Context(), auth, w, boardID)
 	} else {
-		tasks, status = h.getByTeamID(r.Context(), auth, w)
+		tasks, status = h.getByTeamID(r.Context(), auth, w, visible)
 	}
 
-	// write status and if OK, write tasks to response
+	// write status and if OK, write tasks to response in their order within
+	// their columns
 	w.WriteHeader(status)
 	if status == http.StatusOK {
-		if err := json.NewEncoder(w).Encode(tasks); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		tasks = filterByArchived(tasks, archived)
+		tasktbl.SortByRank(tasks)
+		if colNo != -1 {
+			tasks = filterByColNo(tasks, colNo)
+		}
+		if sortKey == sortTitle {
+			sortByTitle(tasks, order == orderDesc)
+		}
+		resp := make(GetResp, len(tasks))
+		for i, t := range tasks {
+			resp[i] = GetTask{Task: t}
+			if render == renderHTML {
+				resp[i].DescriptionHTML = markdown.Render(t.Description)
+			}
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 			return
 		}
@@ -102,7 +228,7 @@ func (h GetHandler) getByBoardID(
 		tasks = []tasktbl.Task{}
 	} else if err != nil {
 		h.log.Error(err)
-		return nil, http.StatusInternalServerError
+		return nil, api.ErrStatus(err)
 	}
 
 	// validate that all tasks belong to user's team
@@ -116,10 +242,87 @@ func (h GetHandler) getByBoardID(
 	return tasks, http.StatusOK
 }
 
+// getPageByBoardID validates the board ID and the cursor and retrieves a page
+// of tasks for the board, setting the cursor for the next page on the response.
+// The tasks within the page are sorted by rank, but since pages are not split
+// by column, their orders are only relative to the page.
+func (h GetHandler) getPageByBoardID(
+	ctx context.Context,
+	auth cookie.Auth,
+	w http.ResponseWriter,
+	boardID string,
+	limit int,
+	cursor string,
+) ([]tasktbl.Task, int) {
+	if err := h.boardIDValidator.Validate(boardID); err != nil {
+		return nil, http.StatusBadRequest
+	}
+	if cursor != "" {
+		var err error
+		if cursor, err = h.cursorSigner.Verify(boardID, cursor); err != nil {
+			return nil, http.StatusBadRequest
+		}
+	}
+
+	// retrieve tasks
+	tasks, next, err := h.pagesByBoard.RetrievePage(
+		ctx, boardID, limit, cursor,
+	)
+	if errors.Is(err, db.ErrInvalidCursor) {
+		return nil, http.StatusBadRequest
+	} else if err != nil {
+		h.log.Error(err)
+		return nil, api.ErrStatus(err)
+	}
+	if tasks == nil {
+		tasks = []tasktbl.Task{}
+	}
+
+	// validate that all tasks belong to user's team
+	for _, t := range tasks {
+		if t.TeamID != auth.TeamID {
+			return nil, http.StatusForbidden
+		}
+	}
+
+	// set the cursor for the next page if there is one
+	if next != "" {
+		w.Header().Set(api.NextCursorHeader, h.cursorSigner.Sign(boardID, next))
+	}
+
+	return tasks, http.StatusOK
+}
+
+// getVisibleBoards retrieves the team of the user and returns the set of IDs of
+// the boards they can access.
+func (h GetHandler) getVisibleBoards(
+	ctx context.Context, auth cookie.Auth,
+) (map[string]bool, int) {
+	team, err := h.teamRetriever.Retrieve(ctx, auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return nil, http.StatusForbidden
+	} else if err != nil {
+		h.log.Error(err)
+		return nil, api.ErrStatus(err)
+	}
+
+	boards := make(map[string]bool)
+	for _, b := range team.Boards {
+		if b.IsVisibleTo(auth.Username, auth.IsAdmin, auth.IsGuest) {
+			boards[b.ID] = true
+		}
+	}
+	return boards, http.StatusOK
+}
+
 // getByTeamID gets the team ID from the auth token, retrieves all tasks for
 // the team, and writes the ones with the first task's board ID to the response.
+// If boards is not nil, only the tasks of the boards in it are considered.
 func (h GetHandler) getByTeamID(
-	ctx context.Context, auth cookie.Auth, w http.ResponseWriter,
+	ctx context.Context,
+	auth cookie.Auth,
+	w http.ResponseWriter,
+	boards map[string]bool,
 ) ([]tasktbl.Task, int) {
 	// retrieve tasks
 	tasks, err := h.retrieverByTeam.Retrieve(ctx, auth.TeamID)
@@ -128,7 +331,18 @@ func (h GetHandler) getByTeamID(
 		tasks = []tasktbl.Task{}
 	} else if err != nil {
 		h.log.Error(err)
-		return nil, http.StatusInternalServerError
+		return nil, api.ErrStatus(err)
+	}
+
+	// drop the tasks of the boards that are not visible
+	if boards != nil {
+		visible := []tasktbl.Task{}
+		for _, t := range tasks {
+			if boards[t.BoardID] {
+				visible = append(visible, t)
+			}
+		}
+		tasks = visible
 	}
 
 	// if more than one task, only return the ones with the first task's board
@@ -150,3 +364,37 @@ func (h GetHandler) getByTeamID(
 
 	return tasks, http.StatusOK
 }
+
+// filterByColNo returns the tasks that are in the column with the given number.
+func filterByColNo(tasks []tasktbl.Task, colNo int) []tasktbl.Task {
+	filtered := []tasktbl.Task{}
+	for _, t := range tasks {
+		if t.ColNo == colNo {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// filterByArchived returns the tasks that are archived if archived is true,
+// and the ones that are not otherwise.
+func filterByArchived(tasks []tasktbl.Task, archived bool) []tasktbl.Task {
+	filtered := []tasktbl.Task{}
+	for _, t := range tasks {
+		if (t.ArchivedAt != 0) == archived {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// sortByTitle sorts the tasks by title, keeping tasks with the same title in
+// their column order.
+func sortByTitle(tasks []tasktbl.Task, desc bool) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if desc {
+			return tasks[i].Title > tasks[j].Title
+		}
+		return tasks[i].Title < tasks[j].Title
+	})
+}
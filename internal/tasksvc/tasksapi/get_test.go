@@ -9,25 +9,33 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 func TestGetHandler(t *testing.T) {
 	boardIDValidator := &validator.FakeString{}
+	colNoValidator := &validator.FakeInt{}
 	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
-	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	pagesByBoard := &db.FakePageRetriever[[]tasktbl.Task]{}
 	retrieverByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	cursorSigner := api.NewCursorSigner([]byte("key"))
 	log := &log.FakeErrorer{}
 	sut := NewGetHandler(
 		boardIDValidator,
+		colNoValidator,
 		retrieverByBoard,
-		authDecoder,
+		pagesByBoard,
 		retrieverByTeam,
+		teamRetriever,
+		cursorSigner,
 		log,
 	)
 
@@ -39,7 +47,7 @@ func TestGetHandler(t *testing.T) {
 			ID:          "task1",
 			Title:       "taskone",
 			Description: "task one description",
-			Order:       1,
+			Order:       0,
 			Subtasks: []tasktbl.Subtask{
 				{Title: "subtaskone", IsDone: false},
 				{Title: "subtasktwo", IsDone: false},
@@ -52,7 +60,7 @@ func TestGetHandler(t *testing.T) {
 			ID:          "task2",
 			Title:       "tasktwo",
 			Description: "task two description",
-			Order:       2,
+			Order:       0,
 			Subtasks: []tasktbl.Subtask{
 				{Title: "subtaskthree", IsDone: true},
 				{Title: "subtaskfour", IsDone: false},
@@ -65,7 +73,7 @@ func TestGetHandler(t *testing.T) {
 			ID:          "task3",
 			Title:       "taskthree",
 			Description: "task three description",
-			Order:       3,
+			Order:       0,
 			Subtasks: []tasktbl.Subtask{
 				{Title: "subtaskfive", IsDone: true},
 				{Title: "subtasksix", IsDone: true},
@@ -73,45 +81,30 @@ func TestGetHandler(t *testing.T) {
 		},
 	}
 
+	// the boards of tasksA are accessible to all members of the team, which
+	// is overridden in the cases that test access to boards
+	teamRetriever.Res = teamtbl.Team{
+		ID: "team1",
+		Boards: []teamtbl.Board{
+			{ID: "nonempty", Visibility: teamtbl.VisibilityTeam},
+			{ID: "board1", Visibility: teamtbl.VisibilityTeam},
+			{ID: "board2", Visibility: teamtbl.VisibilityTeam},
+		},
+	}
+
 	t.Run("WithBoardID", func(t *testing.T) {
 		for _, c := range []struct {
 			name               string
 			errValidateBoardID error
-			authToken          string
-			errDecodeAuth      error
 			auth               cookie.Auth
 			errRetrieve        error
 			tasks              []tasktbl.Task
 			wantStatus         int
 			assertFunc         func(*testing.T, *http.Response, []any)
 		}{
-			{
-				name:               "NoAuth",
-				errValidateBoardID: nil,
-				authToken:          "",
-				errDecodeAuth:      nil,
-				auth:               cookie.Auth{},
-				errRetrieve:        nil,
-				tasks:              []tasktbl.Task{},
-				wantStatus:         http.StatusUnauthorized,
-				assertFunc:         func(*testing.T, *http.Response, []any) {},
-			},
-			{
-				name:               "InvalidAuth",
-				errValidateBoardID: nil,
-				authToken:          "nonempty",
-				errDecodeAuth:      errors.New("decode auth failed"),
-				auth:               cookie.Auth{},
-				errRetrieve:        nil,
-				tasks:              []tasktbl.Task{},
-				wantStatus:         http.StatusUnauthorized,
-				assertFunc:         func(*testing.T, *http.Response, []any) {},
-			},
 			{
 				name:               "InvalidBoardID",
 				errValidateBoardID: errors.New("validate board ID failed"),
-				authToken:          "nonempty",
-				errDecodeAuth:      nil,
 				auth:               cookie.Auth{},
 				errRetrieve:        nil,
 				tasks:              []tasktbl.Task{},
@@ -121,8 +114,6 @@ func TestGetHandler(t *testing.T) {
 			{
 				name:               "ErrRetrieve",
 				errValidateBoardID: nil,
-				authToken:          "nonempty",
-				errDecodeAuth:      nil,
 				auth:               cookie.Auth{},
 				errRetrieve:        errors.New("retrieve failed"),
 				tasks:              []tasktbl.Task{},
@@ -132,8 +123,6 @@ func TestGetHandler(t *testing.T) {
 			{
 				name:               "TaskWrongTeam",
 				errValidateBoardID: nil,
-				authToken:          "nonempty",
-				errDecodeAuth:      nil,
 				auth:               cookie.Auth{TeamID: "team2"},
 				errRetrieve:        nil,
 				tasks:              tasksA,
@@ -143,8 +132,6 @@ func TestGetHandler(t *testing.T) {
 			{
 				name:               "OKNone",
 				errValidateBoardID: nil,
-				authToken:          "nonempty",
-				errDecodeAuth:      nil,
 				auth:               cookie.Auth{},
 				errRetrieve:        nil,
 				tasks:              []tasktbl.Task{},
@@ -160,8 +147,6 @@ func TestGetHandler(t *testing.T) {
 			{
 				name:               "OKSome",
 				errValidateBoardID: nil,
-				authToken:          "nonempty",
-				errDecodeAuth:      nil,
 				auth:               cookie.Auth{TeamID: "team1"},
 				errRetrieve:        nil,
 				tasks:              tasksA,
@@ -207,8 +192,6 @@ func TestGetHandler(t *testing.T) {
 			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
-				authDecoder.Err = c.errDecodeAuth
-				authDecoder.Res = c.auth
 				boardIDValidator.Err = c.errValidateBoardID
 				retrieverByBoard.Err = c.errRetrieve
 				retrieverByBoard.Res = c.tasks
@@ -216,13 +199,8 @@ func TestGetHandler(t *testing.T) {
 				r := httptest.NewRequest(
 					http.MethodGet, "/?boardID=nonempty", nil,
 				)
-				if c.authToken != "" {
-					r.AddCookie(&http.Cookie{
-						Name: "auth-token", Value: c.authToken,
-					})
-				}
 
-				sut.Handle(w, r, "")
+				sut.Handle(w, r, c.auth)
 
 				resp := w.Result()
 				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -233,53 +211,27 @@ func TestGetHandler(t *testing.T) {
 
 	t.Run("WithoutBoardID", func(t *testing.T) {
 		for _, c := range []struct {
-			name          string
-			authToken     string
-			errDecodeAuth error
-			auth          cookie.Auth
-			errRetrieve   error
-			tasks         []tasktbl.Task
-			wantStatus    int
-			assertFunc    func(*testing.T, *http.Response, []any)
+			name        string
+			auth        cookie.Auth
+			errRetrieve error
+			tasks       []tasktbl.Task
+			wantStatus  int
+			assertFunc  func(*testing.T, *http.Response, []any)
 		}{
 			{
-				name:          "NoAuth",
-				authToken:     "",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusUnauthorized,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
-			},
-			{
-				name:          "InvalidAuth",
-				authToken:     "nonempty",
-				errDecodeAuth: errors.New("decode auth failed"),
-				auth:          cookie.Auth{},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusUnauthorized,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
-			},
-			{
-				name:          "ErrRetrieve",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   errors.New("retrieve failed"),
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusInternalServerError,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
-			},
-			{
-				name:          "OKNone",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusOK,
+				name:        "ErrRetrieve",
+				auth:        cookie.Auth{TeamID: "team1"},
+				errRetrieve: errors.New("retrieve failed"),
+				tasks:       []tasktbl.Task{},
+				wantStatus:  http.StatusInternalServerError,
+				assertFunc:  func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:        "OKNone",
+				auth:        cookie.Auth{TeamID: "team1"},
+				errRetrieve: nil,
+				tasks:       []tasktbl.Task{},
+				wantStatus:  http.StatusOK,
 				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 					var tasks []tasktbl.Task
 					err := json.NewDecoder(resp.Body).Decode(&tasks)
@@ -288,13 +240,11 @@ func TestGetHandler(t *testing.T) {
 				},
 			},
 			{
-				name:          "OKSome",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   nil,
-				tasks:         tasksA,
-				wantStatus:    http.StatusOK,
+				name:        "OKSome",
+				auth:        cookie.Auth{TeamID: "team1"},
+				errRetrieve: nil,
+				tasks:       tasksA,
+				wantStatus:  http.StatusOK,
 				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 					var tasks []tasktbl.Task
 					err := json.NewDecoder(resp.Body).Decode(&tasks)
@@ -340,19 +290,387 @@ func TestGetHandler(t *testing.T) {
 			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
-				authDecoder.Res = c.auth
-				authDecoder.Err = c.errDecodeAuth
 				retrieverByTeam.Err = c.errRetrieve
 				retrieverByTeam.Res = c.tasks
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(http.MethodGet, "/", nil)
-				if c.authToken != "" {
-					r.AddCookie(&http.Cookie{
-						Name: "auth-token", Value: c.authToken,
-					})
+
+				sut.Handle(w, r, c.auth)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, log.Args)
+			})
+		}
+	})
+
+	t.Run("WithColumn", func(t *testing.T) {
+		for _, c := range []struct {
+			name             string
+			column           string
+			errValidateColNo error
+			wantStatus       int
+			assertFunc       func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:             "NotNumber",
+				column:           "one",
+				errValidateColNo: nil,
+				wantStatus:       http.StatusBadRequest,
+				assertFunc:       func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:             "OutOfBounds",
+				column:           "4",
+				errValidateColNo: validator.ErrOutOfBounds,
+				wantStatus:       http.StatusBadRequest,
+				assertFunc:       func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:             "OK",
+				column:           "2",
+				errValidateColNo: nil,
+				wantStatus:       http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var tasks []tasktbl.Task
+					err := json.NewDecoder(resp.Body).Decode(&tasks)
+					assert.Nil(t.Fatal, err)
+
+					// only the second task is in the third column
+					assert.Equal(t.Fatal, len(tasks), 1)
+					assert.Equal(t.Error, tasks[0].ID, "task2")
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = c.errValidateColNo
+				retrieverByBoard.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByBoard.Err = nil
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodGet, "/?boardID=nonempty&column="+c.column, nil,
+				)
+
+				sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, log.Args)
+			})
+		}
+	})
+
+	t.Run("WithSort", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			query      string
+			wantStatus int
+			wantIDs    []string
+		}{
+			{
+				name:       "UnknownKey",
+				query:      "sort=dueDate",
+				wantStatus: http.StatusBadRequest,
+				wantIDs:    nil,
+			},
+			{
+				name:       "UnknownOrder",
+				query:      "sort=title&order=up",
+				wantStatus: http.StatusBadRequest,
+				wantIDs:    nil,
+			},
+			{
+				name:       "Default",
+				query:      "",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task2", "task3"},
+			},
+			{
+				name:       "TitleAsc",
+				query:      "sort=title",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task3", "task2"},
+			},
+			{
+				name:       "TitleDesc",
+				query:      "sort=title&order=desc",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task2", "task3", "task1"},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = nil
+				retrieverByBoard.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByBoard.Err = nil
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodGet, "/?boardID=nonempty&"+c.query, nil,
+				)
+
+				sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				if c.wantStatus != http.StatusOK {
+					return
 				}
+				var tasks []tasktbl.Task
+				err := json.NewDecoder(resp.Body).Decode(&tasks)
+				assert.Nil(t.Fatal, err)
+				var ids []string
+				for _, task := range tasks {
+					ids = append(ids, task.ID)
+				}
+				assert.AllEqual(t.Error, ids, c.wantIDs)
+			})
+		}
+	})
 
-				sut.Handle(w, r, "")
+	t.Run("WithArchived", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			query      string
+			wantStatus int
+			wantIDs    []string
+		}{
+			{
+				name:       "Invalid",
+				query:      "archived=maybe",
+				wantStatus: http.StatusBadRequest,
+				wantIDs:    nil,
+			},
+			{
+				name:       "Default",
+				query:      "",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task3"},
+			},
+			{
+				name:       "False",
+				query:      "archived=false",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task3"},
+			},
+			{
+				name:       "True",
+				query:      "archived=true",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task2"},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = nil
+				retrieverByBoard.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByBoard.Res[1].ArchivedAt = 1700000000
+				retrieverByBoard.Err = nil
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodGet, "/?boardID=nonempty&"+c.query, nil,
+				)
+
+				sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				if c.wantStatus != http.StatusOK {
+					return
+				}
+				var tasks []tasktbl.Task
+				err := json.NewDecoder(resp.Body).Decode(&tasks)
+				assert.Nil(t.Fatal, err)
+				var ids []string
+				for _, task := range tasks {
+					ids = append(ids, task.ID)
+				}
+				assert.AllEqual(t.Error, ids, c.wantIDs)
+			})
+		}
+	})
+
+	t.Run("WithRender", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			query      string
+			wantStatus int
+			wantHTML   string
+		}{
+			{
+				name:       "Unknown",
+				query:      "render=markdown",
+				wantStatus: http.StatusBadRequest,
+				wantHTML:   "",
+			},
+			{
+				name:       "None",
+				query:      "",
+				wantStatus: http.StatusOK,
+				wantHTML:   "",
+			},
+			{
+				name:       "HTML",
+				query:      "render=html",
+				wantStatus: http.StatusOK,
+				wantHTML:   "<p>task one description</p>\n",
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = nil
+				retrieverByBoard.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByBoard.Err = nil
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodGet, "/?boardID=nonempty&"+c.query, nil,
+				)
+
+				sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				if c.wantStatus != http.StatusOK {
+					return
+				}
+				var tasks GetResp
+				err := json.NewDecoder(resp.Body).Decode(&tasks)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Fatal, len(tasks), len(tasksA))
+				assert.Equal(t.Error, tasks[0].ID, "task1")
+				assert.Equal(t.Error, tasks[0].DescriptionHTML, c.wantHTML)
+			})
+		}
+	})
+
+	t.Run("WithPage", func(t *testing.T) {
+		const boardQuery = "/?boardID=nonempty&limit=2"
+		for _, c := range []struct {
+			name       string
+			target     string
+			tasks      []tasktbl.Task
+			next       string
+			errPage    error
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "LimitNotNumber",
+				target:     "/?boardID=nonempty&limit=two",
+				tasks:      nil,
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:       "LimitTooLarge",
+				target:     "/?boardID=nonempty&limit=101",
+				tasks:      nil,
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:       "NoBoardID",
+				target:     "/?limit=2",
+				tasks:      nil,
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:       "CursorNotSigned",
+				target:     boardQuery + "&cursor=abc",
+				tasks:      nil,
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name: "CursorInvalid",
+				target: boardQuery + "&cursor=" +
+					cursorSigner.Sign("nonempty", "abc"),
+				tasks:      nil,
+				next:       "",
+				errPage:    db.ErrInvalidCursor,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:       "ErrRetrieve",
+				target:     boardQuery,
+				tasks:      nil,
+				next:       "",
+				errPage:    errors.New("retrieve page failed"),
+				wantStatus: http.StatusInternalServerError,
+				assertFunc: assert.OnLoggedErr("retrieve page failed"),
+			},
+			{
+				name:       "TaskWrongTeam",
+				target:     boardQuery,
+				tasks:      []tasktbl.Task{{TeamID: "team2"}},
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusForbidden,
+				assertFunc: func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:       "OKLastPage",
+				target:     boardQuery,
+				tasks:      tasksA[:1],
+				next:       "",
+				errPage:    nil,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var tasks []tasktbl.Task
+					err := json.NewDecoder(resp.Body).Decode(&tasks)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Fatal, len(tasks), 1)
+					assert.Equal(t.Error, tasks[0].ID, "task1")
+
+					// no cursor should be sent after the last page
+					assert.Equal(t.Error,
+						resp.Header.Get(api.NextCursorHeader), "",
+					)
+				},
+			},
+			{
+				name:       "OKNextPage",
+				target:     boardQuery,
+				tasks:      tasksA[:2],
+				next:       "next",
+				errPage:    nil,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var tasks []tasktbl.Task
+					err := json.NewDecoder(resp.Body).Decode(&tasks)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(tasks), 2)
+
+					// the cursor for the next page should be signed for the
+					// board
+					next, err := cursorSigner.Verify(
+						"nonempty", resp.Header.Get(api.NextCursorHeader),
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, next, "next")
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = nil
+				pagesByBoard.Res = c.tasks
+				pagesByBoard.Next = c.next
+				pagesByBoard.Err = c.errPage
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, c.target, nil)
+
+				sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
 
 				resp := w.Result()
 				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -360,4 +678,114 @@ func TestGetHandler(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("WithBoardAccess", func(t *testing.T) {
+		member := cookie.Auth{TeamID: "team1", Username: "bob"}
+		guest := cookie.Auth{TeamID: "team1", Username: "bob", IsGuest: true}
+		team := teamtbl.Team{
+			ID: "team1",
+			Boards: []teamtbl.Board{
+				{
+					ID:         "board1",
+					Members:    []string{"alice"},
+					Visibility: teamtbl.VisibilityTeam,
+				},
+				{ID: "board2", Members: []string{"alice", "bob"}},
+				{ID: "board3", Members: []string{"alice"}},
+			},
+		}
+
+		for _, c := range []struct {
+			name        string
+			auth        cookie.Auth
+			target      string
+			errRetrieve error
+			wantStatus  int
+			wantIDs     []string
+		}{
+			{
+				name:        "TeamNotFound",
+				auth:        member,
+				target:      "/",
+				errRetrieve: db.ErrNoItem,
+				wantStatus:  http.StatusForbidden,
+			},
+			{
+				name:        "ErrRetrieve",
+				auth:        member,
+				target:      "/",
+				errRetrieve: errors.New("retrieve failed"),
+				wantStatus:  http.StatusInternalServerError,
+			},
+			{
+				name:       "Restricted",
+				auth:       member,
+				target:     "/?boardID=board3",
+				wantStatus: http.StatusForbidden,
+			},
+			{
+				name:       "OKTeamVisible",
+				auth:       member,
+				target:     "/?boardID=board1",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task2", "task3"},
+			},
+			{
+				name:       "OKAdmin",
+				auth:       cookie.Auth{TeamID: "team1", IsAdmin: true},
+				target:     "/?boardID=board3",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task2", "task3"},
+			},
+			{
+				name:       "GuestTeamVisible",
+				auth:       guest,
+				target:     "/?boardID=board1",
+				wantStatus: http.StatusForbidden,
+			},
+			{
+				name:       "OKGuestBoard",
+				auth:       guest,
+				target:     "/?boardID=board2",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task1", "task2", "task3"},
+			},
+			{
+				name:       "OKGuestTeam",
+				auth:       guest,
+				target:     "/",
+				wantStatus: http.StatusOK,
+				wantIDs:    []string{"task3"},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				boardIDValidator.Err = nil
+				colNoValidator.Err = nil
+				teamRetriever.Res = team
+				teamRetriever.Err = c.errRetrieve
+				retrieverByBoard.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByBoard.Err = nil
+				retrieverByTeam.Res = append([]tasktbl.Task(nil), tasksA...)
+				retrieverByTeam.Err = nil
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, c.target, nil)
+
+				sut.Handle(w, r, c.auth)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				if c.wantStatus != http.StatusOK {
+					return
+				}
+				var tasks []tasktbl.Task
+				err := json.NewDecoder(resp.Body).Decode(&tasks)
+				assert.Nil(t.Fatal, err)
+				var ids []string
+				for _, task := range tasks {
+					ids = append(ids, task.ID)
+				}
+				assert.AllEqual(t.Error, ids, c.wantIDs)
+			})
+		}
+	})
 }
@@ -4,11 +4,17 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sort"
+	"time"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -21,76 +27,58 @@ type Subtask struct {
 	IsDone bool   `json:"done"`
 }
 
-// PatchResp defines the body for PATCH column responses.
+// blockedMsg is the message that the moves of blocked tasks into the last
+// column of their board are refused or warned about with.
+const blockedMsg = "Task is blocked by tasks that are not done."
+
+// PatchResp defines the body for PATCH column responses. Warning is set if a
+// task was moved into the last column while it is blocked.
 type PatchResp struct {
-	Error string `json:"error"`
+	Error   string `json:"error"`
+	Warning string `json:"warning,omitempty"`
 }
 
 // PatchHandler is an api.MethodHandler that can be used to handle PATCH
 // requests sent to the tasks route.
 type PatchHandler struct {
-	authDecoder    cookie.Decoder[cookie.Auth]
-	colNoValidator validator.Int
-	tasksUpdater   db.Updater[[]tasktbl.Task]
-	log            log.Errorer
+	colNoValidator   validator.Int
+	teamRetriever    db.Retriever[teamtbl.Team]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	tasksUpdater     db.Updater[[]tasktbl.Task]
+	histInserter     db.Inserter[histtbl.Entry]
+	log              log.Errorer
 }
 
 // NewPatchHandler creates and returns a new PATCHHandler.
 func NewPatchHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	colNoValidator validator.Int,
+	teamRetriever db.Retriever[teamtbl.Team],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
 	tasksUpdater db.Updater[[]tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
 	log log.Errorer,
 ) PatchHandler {
 	return PatchHandler{
-		authDecoder:    authDecoder,
-		colNoValidator: colNoValidator,
-		tasksUpdater:   tasksUpdater,
-		log:            log,
+		colNoValidator:   colNoValidator,
+		teamRetriever:    teamRetriever,
+		retrieverByBoard: retrieverByBoard,
+		tasksUpdater:     tasksUpdater,
+		histInserter:     histInserter,
+		log:              log,
 	}
 }
 
 // Handle handles the PATCH requests sent to the tasks route.
 func (h PatchHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(PatchResp{
-			Error: "Auth token not found.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err = json.NewEncoder(w).Encode(PatchResp{
-			Error: "Invalid auth token.",
-		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-			return
-		}
-	}
-
 	// validate user is admin
 	if !auth.IsAdmin {
 		w.WriteHeader(http.StatusForbidden)
-		if err = json.NewEncoder(w).Encode(PatchResp{
+		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit tasks.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 			return
 		}
@@ -98,18 +86,18 @@ func (h PatchHandler) Handle(
 
 	// decode request body
 	var req PatchReq
-	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 
 	if len(req) == 0 {
 		w.WriteHeader(http.StatusBadRequest)
-		if err = json.NewEncoder(w).Encode(PatchResp{
+		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "No tasks provided.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
@@ -121,10 +109,10 @@ func (h PatchHandler) Handle(
 		// TODO: validate other fields, too
 		if err := h.colNoValidator.Validate(t.ColNo); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			if err = json.NewEncoder(w).Encode(PatchResp{
+			if err := json.NewEncoder(w).Encode(PatchResp{
 				Error: "Invalid column number.",
 			}); err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(api.ErrStatus(err))
 				h.log.Error(err)
 			}
 			return
@@ -136,7 +124,7 @@ func (h PatchHandler) Handle(
 			ColNo:       t.ColNo,
 			ID:          t.ID,
 			Title:       t.Title,
-			Description: t.Description,
+			Description: markdown.Sanitize(t.Description),
 			Order:       t.Order,
 			Subtasks:    t.Subtasks,
 		}
@@ -144,21 +132,195 @@ func (h PatchHandler) Handle(
 		tasks = append(tasks, task)
 	}
 
+	// validate the boards the tasks are on belong to the user's team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	for _, t := range tasks {
+		if !team.HasBoard(t.BoardID) {
+			w.WriteHeader(http.StatusNotFound)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "Board not found.",
+			}); err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+			}
+			return
+		}
+	}
+
+	// retrieve the stored tasks of the boards the tasks are on
+	stored, retrieved := map[string]tasktbl.Task{}, map[string]bool{}
+	for _, t := range tasks {
+		if retrieved[t.BoardID] {
+			continue
+		}
+		retrieved[t.BoardID] = true
+		boardTasks, err := h.retrieverByBoard.Retrieve(r.Context(), t.BoardID)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		for _, bt := range boardTasks {
+			stored[bt.ID] = bt
+		}
+	}
+
+	// keep the tasks in the sprints they are assigned to and the links to
+	// the tasks that block them - a task that is moved to another board is
+	// not found among the stored tasks of the boards the tasks are on and
+	// leaves its sprint and links, which are on the old board
+	for i, t := range tasks {
+		tasks[i].SprintID = stored[t.ID].SprintID
+		tasks[i].BlockedBy = stored[t.ID].BlockedBy
+		if t.ColNo == stored[t.ID].ColNo {
+			tasks[i].ArchivedAt = stored[t.ID].ArchivedAt
+		}
+	}
+
+	// refuse or warn about the moves of blocked tasks into the last column
+	// if their boards are set to, counting the blockers moved into it along
+	// with them as done
+	var warning string
+	moved := map[string]tasktbl.Task{}
+	for _, t := range stored {
+		moved[t.ID] = t
+	}
+	for _, t := range tasks {
+		moved[t.ID] = t
+	}
+	for _, t := range tasks {
+		s, ok := stored[t.ID]
+		if !ok || t.ColNo != validator.MaxColNo ||
+			s.ColNo == validator.MaxColNo {
+			continue
+		}
+		var boardTasks []tasktbl.Task
+		for _, mt := range moved {
+			if mt.BoardID == t.BoardID {
+				boardTasks = append(boardTasks, mt)
+			}
+		}
+		if !t.IsBlocked(boardTasks, validator.MaxColNo) {
+			continue
+		}
+		board, _ := team.Board(t.BoardID)
+		switch board.BlockedTasks {
+		case teamtbl.BlockedTasksRefuse:
+			w.WriteHeader(http.StatusConflict)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: blockedMsg,
+			}); err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+			}
+			return
+		case teamtbl.BlockedTasksWarn:
+			warning = blockedMsg
+		}
+	}
+
+	// rank the tasks of each column in their requested order, keeping the
+	// stored ranks of the tasks that did not move
+	type column struct {
+		boardID string
+		colNo   int
+	}
+	cols := map[column][]tasktbl.Task{}
+	var colKeys []column
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Order < tasks[j].Order
+	})
+	for _, t := range tasks {
+		key := column{boardID: t.BoardID, colNo: t.ColNo}
+		if _, ok := cols[key]; !ok {
+			colKeys = append(colKeys, key)
+		}
+		cols[key] = append(cols[key], t)
+	}
+
+	// only write the tasks that changed so that moving a single task is a
+	// single-item update
+	var changed []tasktbl.Task
+	for _, key := range colKeys {
+		col := cols[key]
+		tasktbl.Rerank(col, stored)
+		for _, t := range col {
+			if s, ok := stored[t.ID]; !ok || !unchanged(t, s) {
+				changed = append(changed, t)
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
 	// update tasks in the task table
 	if err = h.tasksUpdater.Update(
-		r.Context(), tasks,
+		r.Context(), changed,
 	); errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		if err = json.NewEncoder(w).Encode(
 			PatchResp{Error: "Task not found."},
 		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
+
+	// record the edits in the tasks' history so that moves between columns
+	// are known to team analytics and can be undone together - the tasks have
+	// already been updated at this point, so a failure here is only logged
+	at := time.Now().UnixNano()
+	for _, t := range changed {
+		s, ok := stored[t.ID]
+		if !ok {
+			continue
+		}
+		if changes := histtbl.Diff(s, t); len(changes) > 0 {
+			entry := histtbl.NewEntry(t.ID, auth.TeamID, auth.Username, changes)
+			entry.At = at
+			if err = h.histInserter.Insert(r.Context(), entry); err != nil {
+				h.log.Error(err)
+			}
+		}
+	}
+
+	if warning != "" {
+		if err = json.NewEncoder(w).Encode(
+			PatchResp{Warning: warning},
+		); err != nil {
+			h.log.Error(err)
+		}
+	}
+}
+
+// unchanged returns whether the given task is the same as the stored task in
+// all the fields that are persisted and not derived from its rank.
+func unchanged(task, stored tasktbl.Task) bool {
+	if task.TeamID != stored.TeamID ||
+		task.BoardID != stored.BoardID ||
+		task.ColNo != stored.ColNo ||
+		task.Title != stored.Title ||
+		task.Description != stored.Description ||
+		task.SprintID != stored.SprintID ||
+		task.Rank != stored.Rank ||
+		len(task.Subtasks) != len(stored.Subtasks) {
+		return false
+	}
+	for i, st := range task.Subtasks {
+		if st != stored.Subtasks[i] {
+			return false
+		}
+	}
+	return true
 }
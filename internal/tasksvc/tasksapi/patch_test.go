@@ -13,71 +13,81 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 )
 
 func TestPatchHandler(t *testing.T) {
-	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
 	colNoVdtor := &api.FakeIntValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
 	tasksUpdater := &db.FakeUpdater[[]tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
-		authDecoder,
 		colNoVdtor,
+		teamRetriever,
+		retrieverByBoard,
 		tasksUpdater,
+		histInserter,
 		log,
 	)
 
+	taskBody := `[{"boardID": "board1", "id": "taskid", "order": 3, ` +
+		`"column": 0}]`
+	team := teamtbl.Team{
+		ID:     "1",
+		Boards: []teamtbl.Board{{ID: "board1"}},
+	}
+
+	// withBlockedTasks returns team with the given setting on its board for
+	// the tasks moved into its last column while they are blocked
+	withBlockedTasks := func(setting string) teamtbl.Team {
+		return teamtbl.Team{
+			ID: "1",
+			Boards: []teamtbl.Board{
+				{ID: "board1", BlockedTasks: setting},
+			},
+		}
+	}
+	blockedBody := `[
+		{"boardID": "board1", "id": "taskid", "order": 0, "colNo": 3}
+	]`
+	blockedTasks := []tasktbl.Task{
+		{
+			TeamID: "1", BoardID: "board1", ID: "taskid", ColNo: 1,
+			Rank: "i", BlockedBy: []string{"task2"},
+		},
+		{TeamID: "1", BoardID: "board1", ID: "task2", ColNo: 1, Rank: "j"},
+	}
+
 	for _, c := range []struct {
 		name             string
 		rBody            string
-		authToken        string
-		errDecodeAuth    error
 		authDecoded      cookie.Auth
 		errValidateColNo error
+		team             teamtbl.Team
+		errRetrieveTeam  error
+		storedTasks      []tasktbl.Task
+		errRetrieve      error
 		errUpdateTasks   error
-		errEncodeState   error
-		outState         http.Cookie
+		errInsertHist    error
 		wantStatus       int
 		assertFunc       func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:             "NoAuth",
-			rBody:            "[]",
-			authToken:        "",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusUnauthorized,
-			assertFunc:       assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:             "ErrDecodeAuth",
-			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    errors.New("decode auth failed"),
-			authDecoded:      cookie.Auth{},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusUnauthorized,
-			assertFunc:       assert.OnRespErr("Invalid auth token."),
-		},
 		{
 			name:             "NotAdmin",
 			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
 			authDecoded:      cookie.Auth{IsAdmin: false},
 			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
 			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
 				"Only team admins can edit tasks.",
@@ -86,83 +96,246 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:             "NoTasks",
 			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
 			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
 			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
 			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusBadRequest,
 			assertFunc:       assert.OnRespErr("No tasks provided."),
 		},
 		{
 			name:             "ColNoInvalid",
 			rBody:            "[{}]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
 			authDecoded:      cookie.Auth{IsAdmin: true},
 			errValidateColNo: errors.New("err validate column number"),
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
 			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusBadRequest,
 			assertFunc:       assert.OnRespErr("Invalid column number."),
 		},
+		{
+			name:             "ErrRetrieveTeam",
+			rBody:            taskBody,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  errors.New("retrieve team failed"),
+			storedTasks:      nil,
+			errRetrieve:      nil,
+			errUpdateTasks:   nil,
+			errInsertHist:    nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name: "BoardNotInTeam",
+			rBody: `[
+				{"boardID": "board1", "id": "task1", "order": 0, "column": 0},
+				{"boardID": "board2", "id": "task2", "order": 1, "column": 0}
+			]`,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
+			errUpdateTasks:   nil,
+			errInsertHist:    nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Board not found."),
+		},
+		{
+			name:             "ErrRetrieve",
+			rBody:            taskBody,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      errors.New("retrieve tasks failed"),
+			errUpdateTasks:   nil,
+			errInsertHist:    nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve tasks failed"),
+		},
 		{
 			name:             "TaskNotFound",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
+			rBody:            taskBody,
 			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
 			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
 			errUpdateTasks:   db.ErrNoItem,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusNotFound,
 			assertFunc:       assert.OnRespErr("Task not found."),
 		},
 		{
 			name:             "ErrUpdateTasks",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
+			rBody:            taskBody,
 			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
 			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
 			errUpdateTasks:   errors.New("update tasks failed"),
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusInternalServerError,
 			assertFunc:       assert.OnLoggedErr("update tasks failed"),
 		},
 		{
 			name:             "OK",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
+			rBody:            taskBody,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks:      nil,
+			errRetrieve:      nil,
+			errUpdateTasks:   nil,
+			errInsertHist:    nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name: "HistInserterErr",
+			rBody: `[
+				{"boardID": "board1", "id": "taskid", "order": 3, "colNo": 2}
+			]`,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks: []tasktbl.Task{
+				{
+					TeamID: "1", BoardID: "board1", ID: "taskid", ColNo: 1,
+					Rank: "i",
+				},
+			},
+			errRetrieve:    nil,
+			errUpdateTasks: nil,
+			errInsertHist:  errors.New("insert history failed"),
+			wantStatus:     http.StatusOK,
+			assertFunc:     assert.OnLoggedErr("insert history failed"),
+		},
+		{
+			name: "OKUnchanged",
+			rBody: `[
+				{"boardID": "board1", "id": "taskid", "order": 3, "colNo": 1}
+			]`,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks: []tasktbl.Task{
+				{
+					TeamID: "1", BoardID: "board1", ID: "taskid", ColNo: 1,
+					Rank: "i",
+				},
+			},
+			errRetrieve: nil,
+			// the update must be skipped since nothing changed
+			errUpdateTasks: errors.New("update tasks failed"),
+			errInsertHist:  nil,
+			wantStatus:     http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name: "OKKeepsSprint",
+			rBody: `[
+				{"boardID": "board1", "id": "taskid", "order": 3, "colNo": 1}
+			]`,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             team,
+			errRetrieveTeam:  nil,
+			storedTasks: []tasktbl.Task{
+				{
+					TeamID: "1", BoardID: "board1", ID: "taskid", ColNo: 1,
+					Rank: "i", SprintID: "sprint1",
+				},
+			},
+			errRetrieve: nil,
+			// the update must be skipped since the task stays in its sprint
+			errUpdateTasks: errors.New("update tasks failed"),
+			errInsertHist:  nil,
+			wantStatus:     http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:             "BlockedRefused",
+			rBody:            blockedBody,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             withBlockedTasks(teamtbl.BlockedTasksRefuse),
+			errRetrieveTeam:  nil,
+			storedTasks:      blockedTasks,
+			errRetrieve:      nil,
+			// the update must be skipped since the move is refused
+			errUpdateTasks: errors.New("update tasks failed"),
+			errInsertHist:  nil,
+			wantStatus:     http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Task is blocked by tasks that are not done.",
+			),
+		},
+		{
+			name:             "BlockedWarned",
+			rBody:            blockedBody,
+			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
+			errValidateColNo: nil,
+			team:             withBlockedTasks(teamtbl.BlockedTasksWarn),
+			errRetrieveTeam:  nil,
+			storedTasks:      blockedTasks,
+			errRetrieve:      nil,
+			errUpdateTasks:   nil,
+			errInsertHist:    nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: assert.OnRespBody(PatchResp{
+				Warning: "Task is blocked by tasks that are not done.",
+			}),
+		},
+		{
+			name: "OKBlockerMovedAlong",
+			rBody: `[
+				{"boardID": "board1", "id": "task2", "order": 0, "colNo": 3},
+				{"boardID": "board1", "id": "taskid", "order": 1, "colNo": 3}
+			]`,
 			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
 			errValidateColNo: nil,
+			team:             withBlockedTasks(teamtbl.BlockedTasksRefuse),
+			errRetrieveTeam:  nil,
+			storedTasks:      blockedTasks,
+			errRetrieve:      nil,
 			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{Name: "foo", Value: "bar"},
+			errInsertHist:    nil,
 			wantStatus:       http.StatusOK,
 			assertFunc:       func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			authDecoder.Res = c.authDecoded
-			authDecoder.Err = c.errDecodeAuth
 			colNoVdtor.Err = c.errValidateColNo
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			retrieverByBoard.Res = c.storedTasks
+			retrieverByBoard.Err = c.errRetrieve
 			tasksUpdater.Err = c.errUpdateTasks
+			histInserter.Err = c.errInsertHist
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/", strings.NewReader(c.rBody))
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name: "auth-token", Value: c.authToken,
-				})
-			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
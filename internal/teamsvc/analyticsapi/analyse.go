@@ -0,0 +1,152 @@
+package analyticsapi
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// day is the length of a day in nanoseconds.
+const day = int64(24 * time.Hour)
+
+// move is a move of a task between columns recorded in its history. old is -1
+// for the creation of the task.
+type move struct {
+	taskID string
+	at     int64
+	old    int
+	new    int
+}
+
+// analyse computes the analytics of the given tasks for the days from from to
+// to inclusive, from and to being the starts of the days in UTC. The given
+// history entries must be the ones recorded for the tasks since before from,
+// oldest first, and the entries of other tasks are ignored.
+//
+// The columns the tasks were in at the end of each day are worked out by
+// undoing the moves made after it, starting from the columns they are in now.
+// Tasks completed in the range count towards the cycle time from the first
+// time they left the first column, if that is among the given entries.
+func analyse(
+	tasks []tasktbl.Task, entries []histtbl.Entry, from, to time.Time,
+) GetResp {
+	cols := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		cols[t.ID] = t.ColNo
+	}
+
+	// collect the moves of the tasks in the order they were made
+	var moves []move
+	for _, e := range entries {
+		if _, ok := cols[e.TaskID]; !ok {
+			continue
+		}
+		for _, c := range e.Changes {
+			if c.Field != histtbl.FieldColumn {
+				continue
+			}
+			m := move{taskID: e.TaskID, at: e.At, old: -1}
+			var err error
+			if m.new, err = strconv.Atoi(c.New); err != nil {
+				continue
+			}
+			if c.Old != "" {
+				if m.old, err = strconv.Atoi(c.Old); err != nil {
+					continue
+				}
+			}
+			moves = append(moves, m)
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].at < moves[j].at
+	})
+
+	// count the tasks in each column at the end of each day, going backwards
+	// from now and undoing the moves made after the end of the day
+	resp := GetResp{
+		From: from.Format(validator.DateLayout),
+		To:   to.Format(validator.DateLayout),
+		Days: make([]Day, (to.UnixNano()-from.UnixNano())/day+1),
+	}
+	i := len(moves) - 1
+	for d := len(resp.Days) - 1; d >= 0; d-- {
+		start := from.AddDate(0, 0, d)
+		end := start.UnixNano() + day
+		for ; i >= 0 && moves[i].at >= end; i-- {
+			if moves[i].old < 0 {
+				delete(cols, moves[i].taskID)
+			} else {
+				cols[moves[i].taskID] = moves[i].old
+			}
+		}
+
+		resp.Days[d] = Day{
+			Date:    start.Format(validator.DateLayout),
+			Columns: make([]int, validator.MaxColNo+1),
+		}
+		for _, col := range cols {
+			if col >= 0 && col <= validator.MaxColNo {
+				resp.Days[d].Columns[col]++
+			}
+		}
+	}
+
+	// count the tasks completed each day and their cycle times - a task that
+	// is moved back out of the done column and completed again is counted
+	// again, with its cycle time measured from when it was first started
+	started := map[string]int64{}
+	var cycleTimes []float64
+	for _, m := range moves {
+		if m.old <= 0 && m.new > 0 {
+			if _, ok := started[m.taskID]; !ok {
+				started[m.taskID] = m.at
+			}
+		}
+		if m.new != validator.MaxColNo || m.old == validator.MaxColNo ||
+			m.at < from.UnixNano() ||
+			m.at >= to.UnixNano()+day {
+			continue
+		}
+		resp.Days[(m.at-from.UnixNano())/day].Completed++
+		if at, ok := started[m.taskID]; ok {
+			cycleTimes = append(
+				cycleTimes, time.Duration(m.at-at).Hours(),
+			)
+		}
+	}
+	resp.CycleTime = cycleTime(cycleTimes)
+
+	return resp
+}
+
+// cycleTime returns the CycleTime summarising the given cycle times in hours.
+func cycleTime(hours []float64) CycleTime {
+	if len(hours) == 0 {
+		return CycleTime{}
+	}
+	sort.Float64s(hours)
+
+	var sum float64
+	for _, h := range hours {
+		sum += h
+	}
+	median := hours[len(hours)/2]
+	if len(hours)%2 == 0 {
+		median = (hours[len(hours)/2-1] + median) / 2
+	}
+
+	return CycleTime{
+		Count:       len(hours),
+		AvgHours:    round(sum / float64(len(hours))),
+		MedianHours: round(median),
+	}
+}
+
+// round rounds the given number of hours to one decimal place.
+func round(hours float64) float64 { return math.Round(hours*10) / 10 }
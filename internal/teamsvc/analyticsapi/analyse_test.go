@@ -0,0 +1,73 @@
+//go:build utest
+
+package analyticsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+)
+
+// TestAnalyse tests the analyse function to assert that it works out the
+// completed tasks, column counts, and cycle time of each day correctly.
+func TestAnalyse(t *testing.T) {
+	at := func(date string, hour int) int64 {
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.Add(time.Duration(hour) * time.Hour).UnixNano()
+	}
+	moved := func(
+		taskID string, at int64, changes ...histtbl.Change,
+	) histtbl.Entry {
+		return histtbl.Entry{TaskID: taskID, At: at, Changes: changes}
+	}
+	col := func(old, new string) histtbl.Change {
+		return histtbl.Change{Field: "column", Old: old, New: new}
+	}
+
+	tasks := []tasktbl.Task{
+		{ID: "taskA", ColNo: 3},
+		{ID: "taskB", ColNo: 1},
+		{ID: "taskC", ColNo: 0},
+	}
+	entries := []histtbl.Entry{
+		moved("taskA", at("2023-12-20", 10), col("", "0")),
+		moved("taskA", at("2023-12-31", 12), col("0", "1")),
+		moved("taskB", at("2024-01-01", 6), col("", "1")),
+		moved(
+			"taskA", at("2024-01-02", 12),
+			histtbl.Change{Field: "title", Old: "Do", New: "Do it"},
+			col("1", "3"),
+		),
+		// tasks that no longer exist are left out
+		moved("taskD", at("2024-01-02", 14), col("0", "3")),
+		moved("taskB", at("2024-01-02", 18), col("1", "3")),
+		moved("taskC", at("2024-01-03", 8), col("", "0")),
+		moved("taskB", at("2024-01-04", 9), col("3", "1")),
+	}
+	from, _ := time.Parse(time.DateOnly, "2024-01-01")
+	to, _ := time.Parse(time.DateOnly, "2024-01-03")
+
+	resp := analyse(tasks, entries, from, to)
+
+	assert.Equal(t.Error, resp.From, "2024-01-01")
+	assert.Equal(t.Error, resp.To, "2024-01-03")
+	assert.Equal(t.Fatal, len(resp.Days), 3)
+	for i, want := range []Day{
+		{Date: "2024-01-01", Completed: 0, Columns: []int{0, 2, 0, 0}},
+		{Date: "2024-01-02", Completed: 2, Columns: []int{0, 0, 0, 2}},
+		{Date: "2024-01-03", Completed: 0, Columns: []int{1, 0, 0, 2}},
+	} {
+		assert.Equal(t.Error, resp.Days[i].Date, want.Date)
+		assert.Equal(t.Error, resp.Days[i].Completed, want.Completed)
+		assert.AllEqual(t.Error, resp.Days[i].Columns, want.Columns)
+	}
+	assert.Equal(t.Error, resp.CycleTime, CycleTime{
+		Count: 2, AvgHours: 42, MedianHours: 42,
+	})
+}
@@ -0,0 +1,4 @@
+// Package analyticsapi contains code for responding to HTTP requests made to
+// the team analytics API route, which is used for charting the throughput of a
+// team's boards over time.
+package analyticsapi
@@ -0,0 +1,197 @@
+package analyticsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+const (
+	// defaultDays is the number of days analysed when no start date is given.
+	defaultDays = 14
+
+	// lookbackDays is the number of days before the start date that the
+	// history is retrieved from to find when the tasks completed in the range
+	// were started.
+	lookbackDays = 90
+)
+
+// GetResp defines the body of GET team analytics responses.
+type GetResp struct {
+	Error     string    `json:"error,omitempty"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	Days      []Day     `json:"days,omitempty"`
+	CycleTime CycleTime `json:"cycleTime"`
+}
+
+// Day defines the analytics of a single day in a GetResp. Columns holds the
+// number of tasks in each column at the end of the day.
+type Day struct {
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+	Columns   []int  `json:"columns"`
+}
+
+// CycleTime defines the cycle time of the tasks completed in the range of a
+// GetResp, which is the time it took them to go from leaving the first column
+// to reaching the done column.
+type CycleTime struct {
+	Count       int     `json:"count"`
+	AvgHours    float64 `json:"avgHours"`
+	MedianHours float64 `json:"medianHours"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// team analytics route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	tasksByTeam   db.Retriever[[]tasktbl.Task]
+	histByTeam    db.RetrieverDualKey[[]histtbl.Entry]
+	clock         clock.Clock
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	tasksByTeam db.Retriever[[]tasktbl.Task],
+	histByTeam db.RetrieverDualKey[[]histtbl.Entry],
+	clock clock.Clock,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		tasksByTeam:   tasksByTeam,
+		histByTeam:    histByTeam,
+		clock:         clock,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the team analytics route. It responds
+// with the analytics of the boards the user can see for the days from the
+// from query parameter to the to query parameter inclusive, which default to
+// the last 14 days. The analytics can be filtered by board with the boardID
+// query parameter.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// parse and validate the date range
+	from, to, errMsg := dateRange(r, h.clock.Now())
+	if errMsg != "" {
+		h.writeResp(w, http.StatusBadRequest, GetResp{Error: errMsg})
+		return
+	}
+
+	// retrieve team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// determine the boards to analyse - the ones the user can access
+	boardID := r.URL.Query().Get("boardID")
+	boards := map[string]bool{}
+	for _, b := range team.Boards {
+		if boardID != "" && b.ID != boardID {
+			continue
+		}
+		if b.IsVisibleTo(auth.Username, auth.IsAdmin, auth.IsGuest) {
+			boards[b.ID] = true
+		}
+	}
+	if boardID != "" && !boards[boardID] {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "Board not found.",
+		})
+		return
+	}
+
+	// retrieve the tasks on those boards
+	teamTasks, err := h.tasksByTeam.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var tasks []tasktbl.Task
+	for _, t := range teamTasks {
+		if boards[t.BoardID] {
+			tasks = append(tasks, t)
+		}
+	}
+
+	// retrieve the history of the team's tasks since before the range so
+	// that the cycle times of the tasks completed early in it can be found
+	entries, err := h.histByTeam.Retrieve(
+		r.Context(),
+		auth.TeamID,
+		from.AddDate(0, 0, -lookbackDays).Format(validator.DateLayout),
+	)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, analyse(tasks, entries, from, to))
+}
+
+// dateRange returns the start and end dates of the range to analyse from the
+// from and to query parameters of the given request, or an error message if
+// they are invalid. The range ends on the day of the given time by default.
+func dateRange(
+	r *http.Request, now time.Time,
+) (from, to time.Time, errMsg string) {
+	const errFormat = "Dates must be in the YYYY-MM-DD format."
+	var err error
+
+	to = now.UTC().Truncate(24 * time.Hour)
+	if s := r.URL.Query().Get("to"); s != "" {
+		if to, err = time.Parse(validator.DateLayout, s); err != nil {
+			return from, to, errFormat
+		}
+	}
+
+	from = to.AddDate(0, 0, 1-defaultDays)
+	if s := r.URL.Query().Get("from"); s != "" {
+		if from, err = time.Parse(validator.DateLayout, s); err != nil {
+			return from, to, errFormat
+		}
+	}
+
+	if to.Before(from) {
+		return from, to, "Start date cannot be after end date."
+	}
+	if to.Sub(from) >= validator.MaxAnalyticsDays*24*time.Hour {
+		return from, to, "Date range cannot be longer than 90 days."
+	}
+	return from, to, ""
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
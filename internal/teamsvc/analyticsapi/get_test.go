@@ -0,0 +1,215 @@
+//go:build utest
+
+package analyticsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	tasksByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	histByTeam := &db.FakeRetrieverDualKey[[]histtbl.Entry]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewGetHandler(
+		teamRetriever, tasksByTeam, histByTeam, clk, log,
+	)
+
+	team := teamtbl.Team{
+		Boards: []teamtbl.Board{
+			{ID: "board1", Members: []string{"bob"}},
+			{ID: "board2", Members: []string{"alice"}},
+		},
+	}
+	tasksByTeam.Res = []tasktbl.Task{
+		{ID: "task1", BoardID: "board1", ColNo: 2},
+		{ID: "task2", BoardID: "board2", ColNo: 1},
+	}
+	wantColumns := func(cols ...int) func(
+		*testing.T, *http.Response, []any,
+	) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var got GetResp
+			err := json.NewDecoder(resp.Body).Decode(&got)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, got.From, "2024-01-01")
+			assert.Equal(t.Error, got.To, "2024-01-02")
+			assert.Equal(t.Fatal, len(got.Days), 2)
+			for _, d := range got.Days {
+				assert.AllEqual(t.Error, d.Columns, cols)
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		name             string
+		query            string
+		authDecoded      cookie.Auth
+		errRetrieveTeam  error
+		errRetrieveTasks error
+		errRetrieveHist  error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "InvalidFrom",
+			query:            "?from=01/01/2024",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Dates must be in the YYYY-MM-DD format.",
+			),
+		},
+		{
+			name:             "InvalidTo",
+			query:            "?to=tomorrow",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Dates must be in the YYYY-MM-DD format.",
+			),
+		},
+		{
+			name:             "FromAfterTo",
+			query:            "?from=2024-01-02&to=2024-01-01",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Start date cannot be after end date.",
+			),
+		},
+		{
+			name:             "RangeTooLong",
+			query:            "?from=2024-01-01&to=2024-03-31",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Date range cannot be longer than 90 days.",
+			),
+		},
+		{
+			name:             "TeamNotFound",
+			query:            "",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  db.ErrNoItem,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Team not found."),
+		},
+		{
+			name:             "ErrRetrieveTeam",
+			query:            "",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  errors.New("retrieve team failed"),
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:             "BoardNotFound",
+			query:            "?boardID=board2",
+			authDecoded:      cookie.Auth{Username: "bob"},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Board not found."),
+		},
+		{
+			name:             "ErrRetrieveTasks",
+			query:            "",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: errors.New("retrieve tasks failed"),
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:             "ErrRetrieveHist",
+			query:            "",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  errors.New("retrieve history failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve history failed"),
+		},
+		{
+			name:             "OKMember",
+			query:            "?from=2024-01-01&to=2024-01-02",
+			authDecoded:      cookie.Auth{Username: "bob"},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       wantColumns(0, 0, 1, 0),
+		},
+		{
+			name:             "OKAdmin",
+			query:            "?from=2024-01-01&to=2024-01-02",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       wantColumns(0, 1, 1, 0),
+		},
+		{
+			name:             "OKBoardID",
+			query:            "?from=2024-01-01&to=2024-01-02&boardID=board2",
+			authDecoded:      cookie.Auth{IsAdmin: true},
+			errRetrieveTeam:  nil,
+			errRetrieveTasks: nil,
+			errRetrieveHist:  nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       wantColumns(0, 1, 0, 0),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = team
+			teamRetriever.Err = c.errRetrieveTeam
+			tasksByTeam.Err = c.errRetrieveTasks
+			histByTeam.Err = c.errRetrieveHist
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+c.query, nil)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package auditapi contains code for responding to HTTP requests made to the
+// team audit API route, which is used by team admins for viewing the
+// privileged actions taken within their team.
+package auditapi
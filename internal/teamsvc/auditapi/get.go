@@ -0,0 +1,140 @@
+package auditapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// maxLimit is the maximum and default number of entries returned in a page.
+const maxLimit = 100
+
+// GetResp defines the body of GET audit responses. Entries are ordered newest
+// first.
+type GetResp struct {
+	Error   string     `json:"error,omitempty"`
+	Entries []GetEntry `json:"entries,omitempty"`
+}
+
+// GetEntry defines a privileged action in a GetResp.
+type GetEntry struct {
+	At     time.Time       `json:"at"`
+	Action audittbl.Action `json:"action"`
+	Actor  string          `json:"actor"`
+	Target string          `json:"target"`
+	IP     string          `json:"ip"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// audit route.
+type GetHandler struct {
+	auditRetriever db.Retriever[[]audittbl.Entry]
+	cursorSigner   api.CursorSigner
+	log            log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	auditRetriever db.Retriever[[]audittbl.Entry],
+	cursorSigner api.CursorSigner,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		auditRetriever: auditRetriever,
+		cursorSigner:   cursorSigner,
+		log:            log,
+	}
+}
+
+// Handle handles GET requests sent to the audit route. The entries can be
+// paginated with the limit and cursor query parameters, in which case the
+// cursor for the next page is sent in the Next-Cursor header.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, GetResp{
+			Error: "Only team admins can view the audit log.",
+		})
+		return
+	}
+
+	// read the page size and cursor - the cursor is the time of the last
+	// entry on the previous page
+	limit := maxLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 ||
+			limit > maxLimit {
+			h.writeResp(w, http.StatusBadRequest, GetResp{
+				Error: "Limit must be between 1 and 100.",
+			})
+			return
+		}
+	}
+	before := int64(-1)
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		after, err := h.cursorSigner.Verify(auth.TeamID, cursor)
+		if err == nil {
+			before, err = strconv.ParseInt(after, 10, 64)
+		}
+		if err != nil {
+			h.writeResp(w, http.StatusBadRequest, GetResp{
+				Error: "Invalid cursor.",
+			})
+			return
+		}
+	}
+
+	// retrieve the team's audit entries and select the requested page
+	entries, err := h.auditRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var resp GetResp
+	hasNext := false
+	for _, e := range entries {
+		if before >= 0 && e.At >= before {
+			continue
+		}
+		if len(resp.Entries) == limit {
+			hasNext = true
+			break
+		}
+		resp.Entries = append(resp.Entries, GetEntry{
+			At:     time.Unix(0, e.At).UTC(),
+			Action: e.Action,
+			Actor:  e.Actor,
+			Target: e.Target,
+			IP:     e.IP,
+		})
+	}
+
+	// set the cursor for the next page if there is one
+	if hasNext {
+		last := resp.Entries[limit-1].At.UnixNano()
+		w.Header().Set(api.NextCursorHeader, h.cursorSigner.Sign(
+			auth.TeamID, strconv.FormatInt(last, 10),
+		))
+	}
+
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,169 @@
+//go:build utest
+
+package auditapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	auditRetriever := &db.FakeRetriever[[]audittbl.Entry]{}
+	cursorSigner := api.NewCursorSigner([]byte("key"))
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(auditRetriever, cursorSigner, log)
+
+	entries := []audittbl.Entry{
+		{
+			TeamID: "team1",
+			At:     1700000000000000003,
+			Action: audittbl.ActionDeleteBoard,
+			Actor:  "alice",
+			Target: "board1",
+			IP:     "203.0.113.7",
+		},
+		{TeamID: "team1", At: 1700000000000000002},
+		{TeamID: "team1", At: 1700000000000000001},
+	}
+
+	// assertEntries returns a function that asserts on the times of the
+	// entries in the response and on the cursor for the next page.
+	assertEntries := func(
+		wantNext int64, wantAts ...int64,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body GetResp
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t.Fatal, len(body.Entries), len(wantAts))
+			for i, e := range body.Entries {
+				assert.Equal(t.Error, e.At.UnixNano(), wantAts[i])
+			}
+
+			next := resp.Header.Get(api.NextCursorHeader)
+			if wantNext == 0 {
+				assert.Equal(t.Error, next, "")
+				return
+			}
+			before, err := cursorSigner.Verify("team1", next)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, before, strconv.FormatInt(wantNext, 10))
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		isAdmin     bool
+		query       string
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			isAdmin:     false,
+			query:       "",
+			errRetrieve: nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can view the audit log.",
+			),
+		},
+		{
+			name:        "InvalidLimit",
+			isAdmin:     true,
+			query:       "?limit=101",
+			errRetrieve: nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Limit must be between 1 and 100.",
+			),
+		},
+		{
+			name:        "InvalidCursor",
+			isAdmin:     true,
+			query:       "?cursor=" + cursorSigner.Sign("team2", "1"),
+			errRetrieve: nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid cursor."),
+		},
+		{
+			name:        "ErrRetrieve",
+			isAdmin:     true,
+			query:       "",
+			errRetrieve: errors.New("retrieve audit failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve audit failed"),
+		},
+		{
+			name:        "OK",
+			isAdmin:     true,
+			query:       "",
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Fatal, len(body.Entries), 3)
+				e := body.Entries[0]
+				assert.Equal(t.Error, e.At.UnixNano(), entries[0].At)
+				assert.Equal(t.Error, e.Action, entries[0].Action)
+				assert.Equal(t.Error, e.Actor, entries[0].Actor)
+				assert.Equal(t.Error, e.Target, entries[0].Target)
+				assert.Equal(t.Error, e.IP, entries[0].IP)
+				assert.Equal(t.Error,
+					resp.Header.Get(api.NextCursorHeader), "",
+				)
+			},
+		},
+		{
+			name:        "FirstPage",
+			isAdmin:     true,
+			query:       "?limit=2",
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: assertEntries(
+				1700000000000000002,
+				1700000000000000003, 1700000000000000002,
+			),
+		},
+		{
+			name:    "NextPage",
+			isAdmin: true,
+			query: "?limit=2&cursor=" +
+				cursorSigner.Sign("team1", "1700000000000000002"),
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  assertEntries(0, 1700000000000000001),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			auditRetriever.Res = entries
+			auditRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+c.query, nil)
+
+			sut.Handle(w, r, cookie.Auth{IsAdmin: c.isAdmin, TeamID: "team1"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package billing contains code for subscribing teams to the paid plan with
+// Stripe Checkout and for verifying the webhook events Stripe sends about their
+// subscriptions.
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeURL is the base URL of the Stripe API.
+const StripeURL = "https://api.stripe.com"
+
+// MetadataTeamID is the key of the subscription metadata entry that holds the
+// ID of the team the subscription is for.
+const MetadataTeamID = "teamID"
+
+// signatureTolerance is how old a webhook event's signature can be before it is
+// rejected to prevent replay attacks. It matches Stripe's own libraries.
+const signatureTolerance = 5 * time.Minute
+
+// ErrInvalidSignature means that a webhook event's signature was missing,
+// malformed, expired, or did not match its payload.
+var ErrInvalidSignature = errors.New("invalid stripe signature")
+
+// CheckoutCreator describes a type that can be used to start a checkout for
+// the team with the given ID to subscribe to the paid plan. It returns the URL
+// of the checkout page to redirect the user to.
+type CheckoutCreator interface {
+	CreateCheckout(ctx context.Context, teamID, customerID string) (
+		string, error,
+	)
+}
+
+// Stripe is a CheckoutCreator that creates Stripe Checkout sessions.
+type Stripe struct {
+	url        string
+	secretKey  string
+	priceID    string
+	successURL string
+	cancelURL  string
+	client     *http.Client
+}
+
+// NewStripe creates and returns a new Stripe that creates checkout sessions for
+// the given price with the Stripe API at the given URL, redirecting to the
+// success or cancel URL once the user is done.
+func NewStripe(
+	url, secretKey, priceID, successURL, cancelURL string, client *http.Client,
+) Stripe {
+	return Stripe{
+		url:        url,
+		secretKey:  secretKey,
+		priceID:    priceID,
+		successURL: successURL,
+		cancelURL:  cancelURL,
+		client:     client,
+	}
+}
+
+// checkoutResp defines the body of Stripe's create checkout session responses.
+type checkoutResp struct {
+	URL   string `json:"url"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateCheckout creates a subscription checkout session for the team with the
+// given ID. The team ID is set on the subscription's metadata so that webhook
+// events about it can be matched to the team. The customer ID is reused if the
+// team subscribed before so that Stripe keeps a single customer per team.
+func (s Stripe) CreateCheckout(
+	ctx context.Context, teamID, customerID string,
+) (string, error) {
+	form := url.Values{
+		"mode":                    {"subscription"},
+		"line_items[0][price]":    {s.priceID},
+		"line_items[0][quantity]": {"1"},
+		"success_url":             {s.successURL},
+		"cancel_url":              {s.cancelURL},
+		"client_reference_id":     {teamID},
+		"subscription_data[metadata][" + MetadataTeamID + "]": {teamID},
+	}
+	if customerID != "" {
+		form.Set("customer", customerID)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		s.url+"/v1/checkout/sessions",
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body checkoutResp
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"stripe responded %d: %s", resp.StatusCode, body.Error.Message,
+		)
+	}
+	return body.URL, nil
+}
+
+// Verifier describes a type that can be used to verify that a webhook event's
+// payload was sent by Stripe using the value of its Stripe-Signature header.
+type Verifier interface {
+	Verify(payload []byte, sigHeader string) error
+}
+
+// WebhookVerifier is a Verifier that checks the HMAC-SHA256 signatures Stripe
+// signs webhook events with using the endpoint's signing secret.
+type WebhookVerifier struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewWebhookVerifier creates and returns a new WebhookVerifier with the given
+// signing secret.
+func NewWebhookVerifier(secret string) WebhookVerifier {
+	return WebhookVerifier{secret: []byte(secret), now: time.Now}
+}
+
+// Verify returns ErrInvalidSignature unless the header holds a timestamp within
+// the tolerance and a v1 signature of the timestamp and payload.
+func (v WebhookVerifier) Verify(payload []byte, sigHeader string) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		k, val, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = val
+		case "v1":
+			sigs = append(sigs, val)
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := v.now().Sub(time.Unix(unix, 0)); age > signatureTolerance ||
+		age < -signatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	want := mac.Sum(nil)
+	for _, sig := range sigs {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, want) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Event defines the webhook events Stripe sends.
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// Subscription defines the subscription object sent in the customer
+// subscription webhook events.
+type Subscription struct {
+	ID       string            `json:"id"`
+	Customer string            `json:"customer"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+}
@@ -0,0 +1,160 @@
+//go:build utest
+
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestStripe tests the CreateCheckout method of Stripe to assert that it
+// creates a subscription checkout session for the team and returns its URL.
+func TestStripe(t *testing.T) {
+	var (
+		auth       string
+		form       map[string]string
+		respStatus int
+		respBody   string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_ = r.ParseForm()
+			auth = r.Header.Get("Authorization")
+			form = map[string]string{}
+			for k := range r.PostForm {
+				form[k] = r.PostForm.Get(k)
+			}
+			w.WriteHeader(respStatus)
+			_, _ = w.Write([]byte(respBody))
+		},
+	))
+	defer srv.Close()
+
+	sut := NewStripe(
+		srv.URL, "sk_test", "price_1", "/success", "/cancel", srv.Client(),
+	)
+
+	for _, c := range []struct {
+		name         string
+		customerID   string
+		respStatus   int
+		respBody     string
+		wantURL      string
+		wantErrIsNil bool
+	}{
+		{
+			name:         "ErrStatus",
+			customerID:   "",
+			respStatus:   http.StatusBadRequest,
+			respBody:     `{"error": {"message": "No such price"}}`,
+			wantURL:      "",
+			wantErrIsNil: false,
+		},
+		{
+			name:         "NewCustomer",
+			customerID:   "",
+			respStatus:   http.StatusOK,
+			respBody:     `{"url": "https://checkout.stripe.com/c/1"}`,
+			wantURL:      "https://checkout.stripe.com/c/1",
+			wantErrIsNil: true,
+		},
+		{
+			name:         "ExistingCustomer",
+			customerID:   "cus_1",
+			respStatus:   http.StatusOK,
+			respBody:     `{"url": "https://checkout.stripe.com/c/2"}`,
+			wantURL:      "https://checkout.stripe.com/c/2",
+			wantErrIsNil: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			respStatus = c.respStatus
+			respBody = c.respBody
+
+			url, err := sut.CreateCheckout(
+				context.Background(), "team1", c.customerID,
+			)
+
+			assert.Equal(t.Error, url, c.wantURL)
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, auth, "Bearer sk_test")
+			assert.Equal(t.Error, form["mode"], "subscription")
+			assert.Equal(t.Error, form["line_items[0][price]"], "price_1")
+			assert.Equal(t.Error, form["client_reference_id"], "team1")
+			assert.Equal(t.Error,
+				form["subscription_data[metadata][teamID]"], "team1",
+			)
+			assert.Equal(t.Error, form["customer"], c.customerID)
+		})
+	}
+}
+
+// TestWebhookVerifier tests the Verify method of WebhookVerifier to assert
+// that it only accepts recent signatures of the payload made with the secret.
+func TestWebhookVerifier(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	sut := NewWebhookVerifier("whsec_test")
+	sut.now = func() time.Time { return now }
+
+	payload := []byte(`{"id": "evt_1"}`)
+	sign := func(secret string, at time.Time) string {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(payload)
+		return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for _, c := range []struct {
+		name      string
+		sigHeader string
+		wantErr   error
+	}{
+		{
+			name:      "NoHeader",
+			sigHeader: "",
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "WrongSecret",
+			sigHeader: sign("whsec_other", now),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "Expired",
+			sigHeader: sign("whsec_test", now.Add(-10*time.Minute)),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "NotHex",
+			sigHeader: "t=1700000000,v1=zz",
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "OK",
+			sigHeader: sign("whsec_test", now),
+			wantErr:   nil,
+		},
+		{
+			name:      "OKWithExtraSignature",
+			sigHeader: sign("whsec_test", now) + ",v1=00ff",
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut.Verify(payload, c.sigHeader)
+
+			assert.True(t.Error, errors.Is(err, c.wantErr))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+//go:build utest
+
+package billing
+
+import "context"
+
+// FakeCheckoutCreator is a test fake for CheckoutCreator.
+type FakeCheckoutCreator struct {
+	URL        string
+	Err        error
+	TeamID     string
+	CustomerID string
+}
+
+// CreateCheckout records the team and customer IDs and returns
+// FakeCheckoutCreator.URL and FakeCheckoutCreator.Err.
+func (f *FakeCheckoutCreator) CreateCheckout(
+	_ context.Context, teamID, customerID string,
+) (string, error) {
+	f.TeamID, f.CustomerID = teamID, customerID
+	return f.URL, f.Err
+}
+
+// FakeVerifier is a test fake for Verifier.
+type FakeVerifier struct{ Err error }
+
+// Verify discards the input parameters and returns FakeVerifier.Err.
+func (f *FakeVerifier) Verify([]byte, string) error { return f.Err }
@@ -0,0 +1,4 @@
+// Package billingapi contains code for responding to HTTP requests made to the
+// team billing API routes, which are used by team admins for subscribing to the
+// paid plan and by Stripe for reporting changes to their subscriptions.
+package billingapi
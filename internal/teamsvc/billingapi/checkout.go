@@ -0,0 +1,100 @@
+package billingapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/billing"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// CheckoutResp defines the body of POST checkout responses.
+type CheckoutResp struct {
+	Error string `json:"error,omitempty"`
+
+	// URL is the URL of the checkout page to redirect the user to.
+	URL string `json:"url,omitempty"`
+}
+
+// CheckoutHandler is an api.MethodHandler that can handle POST requests sent
+// to the billing checkout route.
+type CheckoutHandler struct {
+	teamRetriever   db.Retriever[teamtbl.Team]
+	checkoutCreator billing.CheckoutCreator
+	log             log.Errorer
+}
+
+// NewCheckoutHandler creates and returns a new CheckoutHandler.
+func NewCheckoutHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	checkoutCreator billing.CheckoutCreator,
+	log log.Errorer,
+) CheckoutHandler {
+	return CheckoutHandler{
+		teamRetriever:   teamRetriever,
+		checkoutCreator: checkoutCreator,
+		log:             log,
+	}
+}
+
+// Handle handles POST requests sent to the billing checkout route.
+func (h CheckoutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			CheckoutResp{Error: "Only team admins can manage billing."},
+		)
+		return
+	}
+
+	// retrieve the team to check it isn't already subscribed and to reuse its
+	// Stripe customer if it subscribed before
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound,
+			CheckoutResp{Error: "Team not found."},
+		)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if team.Billing.IsPaid() {
+		h.writeResp(w, http.StatusBadRequest,
+			CheckoutResp{Error: "Team is already on the paid plan."},
+		)
+		return
+	}
+
+	// start the checkout
+	url, err := h.checkoutCreator.CreateCheckout(
+		r.Context(), team.ID, team.Billing.CustomerID,
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.writeResp(w, http.StatusBadGateway, CheckoutResp{
+			Error: "Checkout could not be started. Please try again later.",
+		})
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, CheckoutResp{URL: url})
+}
+
+// writeResp writes the given status and response body.
+func (h CheckoutHandler) writeResp(
+	w http.ResponseWriter, status int, resp CheckoutResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
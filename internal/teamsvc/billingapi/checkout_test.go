@@ -0,0 +1,141 @@
+//go:build utest
+
+package billingapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/billing"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestCheckoutHandler tests the Handle method of CheckoutHandler to assert
+// that it behaves correctly in all possible scenarios.
+func TestCheckoutHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	checkoutCreator := &billing.FakeCheckoutCreator{}
+	log := &log.FakeErrorer{}
+	sut := NewCheckoutHandler(teamRetriever, checkoutCreator, log)
+
+	for _, c := range []struct {
+		name            string
+		isAdmin         bool
+		team            teamtbl.Team
+		errRetrieveTeam error
+		checkoutURL     string
+		errCheckout     error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:            "NotAdmin",
+			isAdmin:         false,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			checkoutURL:     "",
+			errCheckout:     nil,
+			wantStatus:      http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can manage billing.",
+			),
+		},
+		{
+			name:            "TeamNotFound",
+			isAdmin:         true,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: db.ErrNoItem,
+			checkoutURL:     "",
+			errCheckout:     nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Team not found."),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			isAdmin:         true,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			checkoutURL:     "",
+			errCheckout:     nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:    "AlreadyPaid",
+			isAdmin: true,
+			team: teamtbl.Team{
+				ID:      "team1",
+				Billing: teamtbl.Billing{Status: "active"},
+			},
+			errRetrieveTeam: nil,
+			checkoutURL:     "",
+			errCheckout:     nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Team is already on the paid plan.",
+			),
+		},
+		{
+			name:            "ErrCheckout",
+			isAdmin:         true,
+			team:            teamtbl.Team{ID: "team1"},
+			errRetrieveTeam: nil,
+			checkoutURL:     "",
+			errCheckout:     errors.New("create checkout failed"),
+			wantStatus:      http.StatusBadGateway,
+			assertFunc: func(t *testing.T, r *http.Response, args []any) {
+				assert.OnLoggedErr("create checkout failed")(t, r, args)
+				assert.OnRespErr(
+					"Checkout could not be started. Please try again later.",
+				)(t, r, args)
+			},
+		},
+		{
+			name:    "OK",
+			isAdmin: true,
+			team: teamtbl.Team{
+				ID: "team1",
+				Billing: teamtbl.Billing{
+					CustomerID: "cus_1", Status: "canceled",
+				},
+			},
+			errRetrieveTeam: nil,
+			checkoutURL:     "https://checkout.stripe.com/c/1",
+			errCheckout:     nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp CheckoutResp
+				if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error,
+					resp.URL, "https://checkout.stripe.com/c/1",
+				)
+				assert.Equal(t.Error, checkoutCreator.TeamID, "team1")
+				assert.Equal(t.Error, checkoutCreator.CustomerID, "cus_1")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			checkoutCreator.URL = c.checkoutURL
+			checkoutCreator.Err = c.errCheckout
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			sut.Handle(w, r, cookie.Auth{IsAdmin: c.isAdmin, TeamID: "team1"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
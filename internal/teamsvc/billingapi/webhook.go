@@ -0,0 +1,137 @@
+package billingapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/billing"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// maxEventSize is the maximum size of webhook event payloads in bytes. Stripe
+// events are a few kilobytes at most.
+const maxEventSize = 64 << 10
+
+// WebhookHandler is an api.MethodHandler that can handle the POST requests
+// Stripe sends to the billing webhook route. It keeps each team's billing
+// state in sync with its subscription and applies the paid plan's quota while
+// the subscription is active.
+//
+// Stripe retries events that are not acknowledged with a 2xx status, so events
+// that cannot be applied yet are responded to with an error status, while
+// events that can never be applied are acknowledged and ignored.
+type WebhookHandler struct {
+	verifier      billing.Verifier
+	paidQuota     quota.Quota
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewWebhookHandler creates and returns a new WebhookHandler.
+func NewWebhookHandler(
+	verifier billing.Verifier,
+	paidQuota quota.Quota,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) WebhookHandler {
+	return WebhookHandler{
+		verifier:      verifier,
+		paidQuota:     paidQuota,
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles the POST requests sent to the billing webhook route.
+func (h WebhookHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	// read the payload as-is since the signature is computed over its bytes
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEventSize))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// verify the event was sent by Stripe
+	if err = h.verifier.Verify(
+		payload, r.Header.Get("Stripe-Signature"),
+	); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// only subscription events affect billing state
+	var event billing.Event
+	if err = json.Unmarshal(payload, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !strings.HasPrefix(event.Type, "customer.subscription.") {
+		return
+	}
+	var sub billing.Subscription
+	if err = json.Unmarshal(event.Data.Object, &sub); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// retrieve the team the subscription is for - subscriptions that were not
+	// created through checkout have no team ID and are ignored
+	teamID := sub.Metadata[billing.MetadataTeamID]
+	if teamID == "" {
+		return
+	}
+	team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// ignore events about a previous subscription of a team that has since
+	// subscribed again
+	if team.Billing.IsPaid() && team.Billing.SubscriptionID != sub.ID {
+		return
+	}
+
+	// update the billing state and apply or lift the paid plan's quota when
+	// the team moves between plans, keeping quota overrides set by operators
+	// otherwise
+	wasPaid := team.Billing.IsPaid()
+	team.Billing = teamtbl.Billing{
+		CustomerID:     sub.Customer,
+		SubscriptionID: sub.ID,
+		Status:         sub.Status,
+	}
+	if isPaid := team.Billing.IsPaid(); isPaid && !wasPaid {
+		team.Quota = h.paidQuota
+	} else if !isPaid && wasPaid {
+		team.Quota = quota.Quota{}
+	}
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,247 @@
+//go:build utest
+
+package billingapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/billing"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// TestWebhookHandler tests the Handle method of WebhookHandler to assert that
+// it behaves correctly in all possible scenarios.
+func TestWebhookHandler(t *testing.T) {
+	verifier := &billing.FakeVerifier{}
+	paidQuota := quota.Quota{MaxBoards: 20}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewWebhookHandler(
+		verifier, paidQuota, teamRetriever, teamUpdater, log,
+	)
+
+	// event returns the body of a subscription event of the given type.
+	event := func(typ, id, status, teamID string) string {
+		return `{"type": "` + typ + `", "data": {"object": {"id": "` + id +
+			`", "customer": "cus_1", "status": "` + status +
+			`", "metadata": {"teamID": "` + teamID + `"}}}}`
+	}
+	updated := event("customer.subscription.updated", "sub_1", "active", "t1")
+
+	// assertUpdated returns a function that asserts on the team that was
+	// updated.
+	assertUpdated := func(
+		wantBilling teamtbl.Billing, wantQuota quota.Quota,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, _ *http.Response, _ []any) {
+			assert.Equal(t.Error, teamUpdater.Updated.ID, "t1")
+			assert.Equal(t.Error, teamUpdater.Updated.Billing, wantBilling)
+			assert.Equal(t.Error, teamUpdater.Updated.Quota, wantQuota)
+		}
+	}
+	assertNotUpdated := func(t *testing.T, _ *http.Response, _ []any) {
+		assert.Equal(t.Error, teamUpdater.Updated.ID, "")
+	}
+
+	for _, c := range []struct {
+		name            string
+		body            string
+		errVerify       error
+		team            teamtbl.Team
+		errRetrieveTeam error
+		errUpdateTeam   error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:            "ErrVerify",
+			body:            updated,
+			errVerify:       billing.ErrInvalidSignature,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name:            "InvalidBody",
+			body:            "{",
+			errVerify:       nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name:            "OtherEvent",
+			body:            `{"type": "invoice.paid", "data": {"object": 1}}`,
+			errVerify:       nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name: "NoTeamID",
+			body: event(
+				"customer.subscription.created", "sub_1", "active", "",
+			),
+			errVerify:       nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name:            "TeamNotFound",
+			body:            updated,
+			errVerify:       nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: db.ErrNoItem,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			body:            updated,
+			errVerify:       nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:      "PreviousSubscription",
+			body:      updated,
+			errVerify: nil,
+			team: teamtbl.Team{
+				ID: "t1",
+				Billing: teamtbl.Billing{
+					SubscriptionID: "sub_2", Status: "active",
+				},
+			},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertNotUpdated,
+		},
+		{
+			name:            "ErrConflict",
+			body:            updated,
+			errVerify:       nil,
+			team:            teamtbl.Team{ID: "t1"},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   db.ErrConflict,
+			wantStatus:      http.StatusConflict,
+			assertFunc:      func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:            "ErrUpdateTeam",
+			body:            updated,
+			errVerify:       nil,
+			team:            teamtbl.Team{ID: "t1"},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   errors.New("update team failed"),
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:            "Subscribed",
+			body:            updated,
+			errVerify:       nil,
+			team:            teamtbl.Team{ID: "t1"},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertUpdated(
+				teamtbl.Billing{
+					CustomerID:     "cus_1",
+					SubscriptionID: "sub_1",
+					Status:         "active",
+				},
+				paidQuota,
+			),
+		},
+		{
+			name:      "StillSubscribed",
+			body:      updated,
+			errVerify: nil,
+			team: teamtbl.Team{
+				ID: "t1",
+				Billing: teamtbl.Billing{
+					SubscriptionID: "sub_1", Status: "trialing",
+				},
+				Quota: quota.Quota{MaxMembers: 100},
+			},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertUpdated(
+				teamtbl.Billing{
+					CustomerID:     "cus_1",
+					SubscriptionID: "sub_1",
+					Status:         "active",
+				},
+				quota.Quota{MaxMembers: 100},
+			),
+		},
+		{
+			name: "Unsubscribed",
+			body: event(
+				"customer.subscription.deleted", "sub_1", "canceled", "t1",
+			),
+			errVerify: nil,
+			team: teamtbl.Team{
+				ID: "t1",
+				Billing: teamtbl.Billing{
+					SubscriptionID: "sub_1", Status: "active",
+				},
+				Quota: paidQuota,
+			},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertUpdated(
+				teamtbl.Billing{
+					CustomerID:     "cus_1",
+					SubscriptionID: "sub_1",
+					Status:         "canceled",
+				},
+				quota.Quota{},
+			),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			verifier.Err = c.errVerify
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			teamUpdater.Err = c.errUpdateTeam
+			teamUpdater.Updated = teamtbl.Team{}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
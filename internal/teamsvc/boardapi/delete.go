@@ -4,78 +4,132 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/google/uuid"
-
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // DeleteHandler is an api.MethodHandler that can be used to handle DELETE board
-// requests.
+// requests. Deleted boards are moved to the trash, from which they can be
+// restored until they expire.
 type DeleteHandler struct {
-	authDecoder  cookie.Decoder[cookie.Auth]
-	boardDeleter db.DeleterDualKey
-	log          log.Errorer
+	teamRetriever db.Retriever[teamtbl.Team]
+	trashInserter db.Inserter[trashtbl.Item]
+	trashDeleter  db.DeleterDualKey
+	boardDeleter  db.DeleterDualKey
+	auditInserter db.Inserter[audittbl.Entry]
+	log           log.Errorer
 }
 
 // NewDeleteHandler creates and returns a new DeleteHandler.
 func NewDeleteHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	trashInserter db.Inserter[trashtbl.Item],
+	trashDeleter db.DeleterDualKey,
 	boardDeleter db.DeleterDualKey,
+	auditInserter db.Inserter[audittbl.Entry],
 	log log.Errorer,
 ) DeleteHandler {
 	return DeleteHandler{
-		authDecoder:  authDecoder,
-		boardDeleter: boardDeleter,
-		log:          log,
+		teamRetriever: teamRetriever,
+		trashInserter: trashInserter,
+		trashDeleter:  trashDeleter,
+		boardDeleter:  boardDeleter,
+		auditInserter: auditInserter,
+		log:           log,
 	}
 }
 
 // Handle handles DELETE board requests.
 func (h DeleteHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
+	// validate user is admin
+	if !auth.IsAdmin {
+		w.WriteHeader(http.StatusForbidden)
 		return
 	}
 
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+	// validate ID - read from the path if present, otherwise fall back to the
+	// deprecated query parameter
+	id := api.PathParam(r, "boardID")
+	if id == "" {
+		id = r.URL.Query().Get("id")
+	}
+	if err := validator.ID(id); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
-		w.WriteHeader(http.StatusForbidden)
+	// find the board in the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(api.ErrStatus(err))
+		return
+	}
+	var (
+		board teamtbl.Board
+		found bool
+	)
+	for _, b := range team.Boards {
+		if b.ID == id {
+			board, found = b, true
+			break
+		}
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
 		return
 	}
 
-	// validate ID
-	id := r.URL.Query().Get("id")
-	if _, err := uuid.Parse(id); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	// move the board to the trash - its tasks are left in the task table so
+	// that they are back on the board if it is restored
+	if err = h.trashInserter.Insert(
+		r.Context(), trashtbl.NewBoardItem(auth.TeamID, auth.Username, board),
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
-	// delete the board
-	if err = h.boardDeleter.Delete(
-		r.Context(), auth.TeamID, id,
-	); errors.Is(err, db.ErrNoItem) {
+	// delete the board, taking it back out of the trash if that fails
+	if err = h.boardDeleter.Delete(r.Context(), auth.TeamID, id); err != nil {
+		if err := h.trashDeleter.Delete(
+			r.Context(), auth.TeamID, id,
+		); err != nil {
+			h.log.Error(err)
+		}
+	}
+	if errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		return
+	} else if errors.Is(err, db.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		return
 	} else if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
+
+	// record the deletion in the team's audit log - the board has already
+	// been deleted at this point, so a failure here is only logged
+	if err = h.auditInserter.Insert(r.Context(), audittbl.NewEntry(
+		auth.TeamID,
+		audittbl.ActionDeleteBoard,
+		auth.Username,
+		id,
+		api.RemoteIP(r),
+	)); err != nil {
+		h.log.Error(err)
+	}
 }
@@ -8,56 +8,58 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 )
 
 // TestDeleteHandler tests the Handle method of DELETEHandler to assert that it
 // behaves correctly in all possible scenarios.
 func TestDeleteHandler(t *testing.T) {
-	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	trashInserter := &db.FakeInserter[trashtbl.Item]{}
+	trashDeleter := &db.FakeDeleterDualKey{}
 	deleter := &db.FakeDeleterDualKey{}
+	auditInserter := &db.FakeInserter[audittbl.Entry]{}
 	log := &log.FakeErrorer{}
-	sut := NewDeleteHandler(authDecoder, deleter, log)
+	sut := NewDeleteHandler(
+		teamRetriever,
+		trashInserter,
+		trashDeleter,
+		deleter,
+		auditInserter,
+		log,
+	)
+
+	boardID := "66c16e54-c14f-4481-ada6-404bca897fb0"
+	team := teamtbl.Team{Boards: []teamtbl.Board{{ID: boardID}}}
 
 	for _, c := range []struct {
 		name           string
 		boardID        string
-		authToken      string
-		errDecodeAuth  error
+		inPath         bool
 		authDecoded    cookie.Auth
+		team           teamtbl.Team
+		errRetrieve    error
+		errInsertTrash error
 		deleteBoardErr error
+		errInsertAudit error
 		wantStatusCode int
 		assertFunc     func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:           "NoAuth",
-			boardID:        "",
-			authToken:      "",
-			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{},
-			deleteBoardErr: nil,
-			wantStatusCode: http.StatusUnauthorized,
-			assertFunc:     func(*testing.T, *http.Response, []any) {},
-		},
-		{
-			name:           "InvalidAuth",
-			boardID:        "",
-			authToken:      "nonempty",
-			errDecodeAuth:  cookie.ErrInvalid,
-			authDecoded:    cookie.Auth{},
-			deleteBoardErr: nil,
-			wantStatusCode: http.StatusUnauthorized,
-			assertFunc:     func(*testing.T, *http.Response, []any) {},
-		},
 		{
 			name:           "NotAdmin",
 			boardID:        "",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			inPath:         false,
 			authDecoded:    cookie.Auth{IsAdmin: false},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusForbidden,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -65,9 +67,11 @@ func TestDeleteHandler(t *testing.T) {
 		{
 			name:           "EmptyID",
 			boardID:        "",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			inPath:         false,
 			authDecoded:    cookie.Auth{IsAdmin: true},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -75,58 +79,179 @@ func TestDeleteHandler(t *testing.T) {
 		{
 			name:           "InvalidID",
 			boardID:        "adksfjahsd",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			inPath:         false,
 			authDecoded:    cookie.Auth{IsAdmin: true},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
 		},
+		{
+			name:           "TeamNotFound",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           teamtbl.Team{},
+			errRetrieve:    db.ErrNoItem,
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusNotFound,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:           "RetrieveErr",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           teamtbl.Team{},
+			errRetrieve:    errors.New("retrieve team failed"),
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:           "BoardNotFound",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusNotFound,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:           "InsertTrashErr",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: errors.New("insert trash failed"),
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("insert trash failed"),
+		},
 		{
 			name:           "ErrNoItem",
-			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			boardID:        boardID,
+			inPath:         false,
 			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: db.ErrNoItem,
 			wantStatusCode: http.StatusNotFound,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
 		},
+		{
+			name:           "ErrConflict",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
+			deleteBoardErr: db.ErrConflict,
+			wantStatusCode: http.StatusConflict,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
 		{
 			name:           "DeleteErr",
-			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			boardID:        boardID,
+			inPath:         false,
 			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: errors.New("delete board failed"),
 			wantStatusCode: http.StatusInternalServerError,
 			assertFunc:     assert.OnLoggedErr("delete board failed"),
 		},
 		{
-			name:           "Success",
-			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
-			authToken:      "nonempty",
-			errDecodeAuth:  nil,
+			name:    "Success",
+			boardID: boardID,
+			inPath:  false,
+			authDecoded: cookie.Auth{
+				IsAdmin: true, TeamID: "1", Username: "bob123",
+			},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the deletion should be recorded in the audit log
+				e := auditInserter.Inserted
+				assert.Equal(t.Error, e.TeamID, "1")
+				assert.Equal(t.Error, e.Action, audittbl.ActionDeleteBoard)
+				assert.Equal(t.Error, e.Actor, "bob123")
+				assert.Equal(t.Error, e.Target, boardID)
+				assert.Equal(t.Error, e.IP, "192.0.2.1")
+			},
+		},
+		{
+			name:           "ErrInsertAudit",
+			boardID:        boardID,
+			inPath:         false,
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			errInsertAudit: errors.New("insert audit failed"),
+			wantStatusCode: http.StatusOK,
+			assertFunc:     assert.OnLoggedErr("insert audit failed"),
+		},
+		{
+			name:           "InvalidIDInPath",
+			boardID:        "adksfjahsd",
+			inPath:         true,
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:           "SuccessIDInPath",
+			boardID:        boardID,
+			inPath:         true,
 			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			team:           team,
+			errRetrieve:    nil,
+			errInsertTrash: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusOK,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			authDecoder.Err = c.errDecodeAuth
-			authDecoder.Res = c.authDecoded
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			trashInserter.Err = c.errInsertTrash
 			deleter.Err = c.deleteBoardErr
+			auditInserter.Err = c.errInsertAudit
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodPost, "/?id="+c.boardID, nil)
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name:  "auth-token",
-					Value: c.authToken,
-				})
+			var r *http.Request
+			if c.inPath {
+				r = api.WithPathParams(
+					httptest.NewRequest(http.MethodDelete, "/", nil),
+					map[string]string{"boardID": c.boardID},
+				)
+			} else {
+				r = httptest.NewRequest(
+					http.MethodPost, "/?id="+c.boardID, nil,
+				)
 			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
@@ -0,0 +1,242 @@
+package boardapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+const (
+	// exportFormat is the version of the export document's format. It must be
+	// incremented on breaking changes to ExportResp.
+	exportFormat = 1
+
+	// exportColumns is the number of columns a board has.
+	exportColumns = validator.MaxColNo + 1
+)
+
+// ExportResp defines the document written by ExportHandler. It is
+// self-contained so that it can be kept as a backup or transferred to another
+// team.
+type ExportResp struct {
+	Format     int            `json:"format"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	TeamID     string         `json:"teamID"`
+	Board      ExportBoard    `json:"board"`
+	Columns    []ExportColumn `json:"columns"`
+}
+
+// ExportBoard defines the board in an ExportResp.
+type ExportBoard struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// ExportColumn defines a column of the board in an ExportResp.
+type ExportColumn struct {
+	No    int          `json:"no"`
+	Tasks []ExportTask `json:"tasks"`
+}
+
+// ExportTask defines a task in an ExportColumn.
+type ExportTask struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Order       int               `json:"order"`
+	Subtasks    []tasktbl.Subtask `json:"subtasks"`
+}
+
+// ExportErrResp defines the body of error responses written by ExportHandler.
+type ExportErrResp struct {
+	Error string `json:"error"`
+}
+
+// ExportHandler is an api.MethodHandler that can be used to handle GET board
+// export requests.
+type ExportHandler struct {
+	idValidator   validator.String
+	teamRetriever db.Retriever[teamtbl.Team]
+	taskRetriever db.Retriever[[]tasktbl.Task]
+	clock         clock.Clock
+	log           log.Errorer
+}
+
+// NewExportHandler creates and returns a new ExportHandler.
+func NewExportHandler(
+	idValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	clock clock.Clock,
+	log log.Errorer,
+) ExportHandler {
+	return ExportHandler{
+		idValidator:   idValidator,
+		teamRetriever: teamRetriever,
+		taskRetriever: taskRetriever,
+		clock:         clock,
+		log:           log,
+	}
+}
+
+// Handle handles GET board export requests.
+func (h ExportHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate board ID
+	id := api.PathParam(r, "boardID")
+	if err := h.idValidator.Validate(id); err != nil {
+		h.writeErr(w, http.StatusBadRequest, "Board ID must be a UUID.")
+		return
+	}
+
+	// find the board in the user's team - non-admins can only export the
+	// boards they are a member of
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "Board not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var (
+		board teamtbl.Board
+		found bool
+	)
+	for _, b := range team.Boards {
+		if b.ID == id {
+			board, found = b, b.IsVisibleTo(
+				auth.Username, auth.IsAdmin, auth.IsGuest,
+			)
+			break
+		}
+	}
+	if !found {
+		h.writeErr(w, http.StatusNotFound, "Board not found.")
+		return
+	}
+
+	// retrieve the board's tasks and group them into columns in their order
+	tasks, err := h.taskRetriever.Retrieve(r.Context(), id)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	tasktbl.SortByRank(tasks)
+	cols := make([][]ExportTask, exportColumns)
+	for _, t := range tasks {
+		if t.TeamID != auth.TeamID || t.ColNo < 0 || t.ColNo >= len(cols) {
+			continue
+		}
+		cols[t.ColNo] = append(cols[t.ColNo], ExportTask{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			Order:       t.Order,
+			Subtasks:    t.Subtasks,
+		})
+	}
+
+	// write the document, streaming the tasks one at a time so that large
+	// boards are not encoded into memory all at once
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(
+		"Content-Disposition", `attachment; filename="board-`+id+`.json"`,
+	)
+	bw := bufio.NewWriter(w)
+	if err := writeExport(bw, ExportResp{
+		Format:     exportFormat,
+		ExportedAt: h.clock.Now().UTC(),
+		TeamID:     team.ID,
+		Board: ExportBoard{
+			ID: board.ID, Name: board.Name, Members: board.Members,
+		},
+	}, cols); err != nil {
+		h.log.Error(err)
+		return
+	}
+	if err := bw.Flush(); err != nil {
+		h.log.Error(err)
+	}
+}
+
+// writeExport writes the given export document to w with the given columns in
+// place of its Columns, encoding one task at a time.
+func writeExport(
+	w *bufio.Writer, doc ExportResp, cols [][]ExportTask,
+) error {
+	// raw parts are written as-is, and all others are encoded as JSON
+	type raw string
+	enc := json.NewEncoder(w)
+	write := func(parts ...any) error {
+		for _, p := range parts {
+			var err error
+			if s, ok := p.(raw); ok {
+				_, err = w.WriteString(string(s))
+			} else {
+				err = enc.Encode(p)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := write(
+		raw(`{"format":`), doc.Format,
+		raw(`,"exportedAt":`), doc.ExportedAt,
+		raw(`,"teamID":`), doc.TeamID,
+		raw(`,"board":`), doc.Board,
+		raw(`,"columns":[`),
+	); err != nil {
+		return err
+	}
+	for no, tasks := range cols {
+		if no > 0 {
+			if err := write(raw(",")); err != nil {
+				return err
+			}
+		}
+		if err := write(raw(`{"no":`), no, raw(`,"tasks":[`)); err != nil {
+			return err
+		}
+		for i, t := range tasks {
+			if i > 0 {
+				if err := write(raw(",")); err != nil {
+					return err
+				}
+			}
+			if err := write(t); err != nil {
+				return err
+			}
+		}
+		if err := write(raw("]}")); err != nil {
+			return err
+		}
+	}
+	return write(raw("]}\n"))
+}
+
+// writeErr writes the given status and error message.
+func (h ExportHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(ExportErrResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
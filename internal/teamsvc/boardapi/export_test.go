@@ -0,0 +1,181 @@
+//go:build utest
+
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestExportHandler tests the Handle method of ExportHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestExportHandler(t *testing.T) {
+	idValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewExportHandler(
+		idValidator, teamRetriever, taskRetriever, clk, log,
+	)
+
+	const boardID = "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+	team := teamtbl.Team{
+		ID: "team1",
+		Boards: []teamtbl.Board{
+			{ID: boardID, Name: "Board 1", Members: []string{"bob"}},
+		},
+	}
+
+	for _, c := range []struct {
+		name          string
+		authDecoded   cookie.Auth
+		errValidateID error
+		team          teamtbl.Team
+		errRetrieve   error
+		tasks         []tasktbl.Task
+		errRetrieveTs error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "InvalidID",
+			authDecoded:   cookie.Auth{},
+			errValidateID: validator.ErrWrongFormat,
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			tasks:         nil,
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:          "TeamNotFound",
+			authDecoded:   cookie.Auth{},
+			errValidateID: nil,
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			tasks:         nil,
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "ErrRetrieveTeam",
+			authDecoded:   cookie.Auth{},
+			errValidateID: nil,
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("failed to retrieve team"),
+			tasks:         nil,
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("failed to retrieve team"),
+		},
+		{
+			name:          "BoardNotFound",
+			authDecoded:   cookie.Auth{IsAdmin: true},
+			errValidateID: nil,
+			team:          teamtbl.Team{ID: "team1"},
+			errRetrieve:   nil,
+			tasks:         nil,
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "NotBoardMember",
+			authDecoded:   cookie.Auth{Username: "alice"},
+			errValidateID: nil,
+			team:          team,
+			errRetrieve:   nil,
+			tasks:         nil,
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "ErrRetrieveTasks",
+			authDecoded:   cookie.Auth{Username: "bob"},
+			errValidateID: nil,
+			team:          team,
+			errRetrieve:   nil,
+			tasks:         nil,
+			errRetrieveTs: errors.New("failed to retrieve tasks"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("failed to retrieve tasks"),
+		},
+		{
+			name:          "OK",
+			authDecoded:   cookie.Auth{Username: "bob", TeamID: "team1"},
+			errValidateID: nil,
+			team:          team,
+			errRetrieve:   nil,
+			tasks: []tasktbl.Task{
+				{TeamID: "team1", ColNo: 2, ID: "t2", Rank: "b"},
+				{TeamID: "team1", ColNo: 2, ID: "t1", Rank: "a",
+					Subtasks: []tasktbl.Subtask{{Title: "s", IsDone: true}}},
+				{TeamID: "team1", ColNo: 0, ID: "t0", Title: "Task 0"},
+				{TeamID: "team2", ColNo: 0, ID: "other"},
+			},
+			errRetrieveTs: nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var doc ExportResp
+				err := json.NewDecoder(resp.Body).Decode(&doc)
+				assert.Nil(t.Fatal, err)
+
+				assert.Equal(t.Error, doc.Format, exportFormat)
+				assert.Equal(t.Error, doc.TeamID, "team1")
+				assert.True(t.Error, doc.ExportedAt.Equal(clk.Time))
+				assert.Equal(t.Error, doc.Board.Name, "Board 1")
+				assert.Equal(t.Fatal, len(doc.Columns), exportColumns)
+				for i, want := range [][]string{{"t0"}, {}, {"t1", "t2"}, {}} {
+					col := doc.Columns[i]
+					assert.Equal(t.Error, col.No, i)
+					assert.Equal(t.Fatal, len(col.Tasks), len(want))
+					for j, id := range want {
+						assert.Equal(t.Error, col.Tasks[j].ID, id)
+						assert.Equal(t.Error, col.Tasks[j].Order, j)
+					}
+				}
+				assert.Equal(t.Error, doc.Columns[0].Tasks[0].Title, "Task 0")
+				assert.Equal(t.Error,
+					doc.Columns[2].Tasks[0].Subtasks[0].IsDone, true,
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idValidator.Err = c.errValidateID
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			taskRetriever.Res = c.tasks
+			taskRetriever.Err = c.errRetrieveTs
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodGet, "/", nil),
+				map[string]string{"boardID": boardID},
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// maxImportSize is the maximum size in bytes of an import request's body.
+const maxImportSize = 10 << 20
+
+// ImportReq defines the body of POST board import requests, which is a
+// document written by ExportHandler.
+type ImportReq ExportResp
+
+// ImportResp defines the body of POST board import responses.
+type ImportResp struct {
+	Error string `json:"error,omitempty"`
+
+	// IDs maps the board and task IDs in the imported document to the IDs
+	// generated for them.
+	IDs map[string]string `json:"ids,omitempty"`
+}
+
+// ImportHandler is an api.MethodHandler that can be used to handle POST board
+// import requests.
+type ImportHandler struct {
+	validateReq   validator.Func[ImportReq]
+	boardInserter db.InserterDualKey[teamtbl.Board]
+	boardDeleter  db.DeleterDualKey
+	taskInserter  db.Inserter[[]tasktbl.Task]
+	log           log.Errorer
+}
+
+// NewImportHandler creates and returns a new ImportHandler.
+func NewImportHandler(
+	validateReq validator.Func[ImportReq],
+	boardInserter db.InserterDualKey[teamtbl.Board],
+	boardDeleter db.DeleterDualKey,
+	taskInserter db.Inserter[[]tasktbl.Task],
+	log log.Errorer,
+) ImportHandler {
+	return ImportHandler{
+		validateReq:   validateReq,
+		boardInserter: boardInserter,
+		boardDeleter:  boardDeleter,
+		taskInserter:  taskInserter,
+		log:           log,
+	}
+}
+
+// Handle handles POST board import requests.
+func (h ImportHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, ImportResp{
+			Error: "Only team admins can import boards.",
+		})
+		return
+	}
+
+	// decode and validate the document
+	var req ImportReq
+	if err := json.NewDecoder(
+		http.MaxBytesReader(w, r.Body, maxImportSize),
+	).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, ImportResp{
+			Error: "Import file is not a valid board export.",
+		})
+		return
+	}
+	for i, col := range req.Columns {
+		for j, t := range col.Tasks {
+			req.Columns[i].Tasks[j].Description = markdown.Sanitize(
+				t.Description,
+			)
+		}
+	}
+	if err := h.validateReq(req); err != nil {
+		var msg string
+		switch {
+		case errors.Is(err, errImportFormat):
+			msg = "Import file format is not supported."
+		case errors.Is(err, validator.ErrEmpty):
+			msg = "Board name cannot be empty."
+		case errors.Is(err, validator.ErrTooLong):
+			msg = "Board name cannot be longer than 35 characters."
+		case errors.Is(err, errImportColumn):
+			msg = "Import file has an invalid column."
+		case errors.Is(err, errImportTaskID):
+			msg = "Import file has tasks with missing or duplicate IDs."
+		case errors.Is(err, errImportTask):
+			msg = "Import file has a task with an empty or too long " +
+				"title, description, or subtask."
+		default:
+			msg = "Import file is not a valid board export."
+		}
+		h.writeResp(w, http.StatusBadRequest, ImportResp{Error: msg})
+		return
+	}
+
+	// insert the board into the team's boards in the team table - retry up to
+	// 3 times for the unlikely event that the generated UUID is a duplicate
+	var (
+		boardID string
+		err     error
+	)
+	for i := 0; i < 3; i++ {
+		boardID = uuid.NewString()
+		if err = h.boardInserter.Insert(r.Context(), auth.TeamID, teamtbl.Board{
+			ID: boardID, Name: req.Board.Name,
+		}); !errors.Is(err, db.ErrDupKey) {
+			break
+		}
+	}
+	if errors.Is(err, db.ErrLimitReached) {
+		h.writeResp(w, http.StatusBadRequest, ImportResp{
+			Error: "You have already created the maximum amount of boards " +
+				"allowed per team. Please delete one of your boards to " +
+				"import a new one.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	ids := map[string]string{req.Board.ID: boardID}
+
+	// create the tasks with new IDs, keeping their order within each column
+	var tasks []tasktbl.Task
+	for _, col := range req.Columns {
+		tasksCol := make([]tasktbl.Task, 0, len(col.Tasks))
+		for i, t := range col.Tasks {
+			ids[t.ID] = uuid.NewString()
+			tasksCol = append(tasksCol, tasktbl.NewTask(
+				auth.TeamID,
+				boardID,
+				col.No,
+				ids[t.ID],
+				t.Title,
+				t.Description,
+				i,
+				t.Subtasks,
+			))
+		}
+		tasktbl.Rebalance(tasksCol)
+		tasks = append(tasks, tasksCol...)
+	}
+
+	// insert the tasks, removing the board if that fails so that the import
+	// can be retried without leaving a partial board behind
+	if err = h.taskInserter.Insert(r.Context(), tasks); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		if err := h.boardDeleter.Delete(
+			r.Context(), auth.TeamID, boardID,
+		); err != nil {
+			h.log.Error(err)
+		}
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, ImportResp{IDs: ids})
+}
+
+// writeResp writes the given status and response.
+func (h ImportHandler) writeResp(
+	w http.ResponseWriter, status int, resp ImportResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,190 @@
+//go:build utest
+
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestImportHandler tests the Handle method of ImportHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestImportHandler(t *testing.T) {
+	validateReq := &validator.FakeFunc[ImportReq]{}
+	boardInserter := &db.FakeInserterDualKey[teamtbl.Board]{}
+	boardDeleter := &db.FakeDeleterDualKey{}
+	taskInserter := &db.FakeInserter[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewImportHandler(
+		validateReq.Func,
+		boardInserter,
+		boardDeleter,
+		taskInserter,
+		log,
+	)
+
+	const body = `{
+		"format": 1,
+		"board": {"id": "oldBoard", "name": "Board"},
+		"columns": [{"no": 0, "tasks": [
+			{"id": "oldTask0", "title": "Task 0"},
+			{"id": "oldTask1", "title": "Task 1"}
+		]}]
+	}`
+
+	for _, c := range []struct {
+		name           string
+		authDecoded    cookie.Auth
+		body           string
+		errValidate    error
+		errInsertBoard error
+		errInsertTasks error
+		wantStatus     int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:           "NotAdmin",
+			authDecoded:    cookie.Auth{IsAdmin: false},
+			body:           body,
+			errValidate:    nil,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can import boards.",
+			),
+		},
+		{
+			name:           "InvalidJSON",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           "{",
+			errValidate:    nil,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Import file is not a valid board export.",
+			),
+		},
+		{
+			name:           "InvalidFormat",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    errImportFormat,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Import file format is not supported.",
+			),
+		},
+		{
+			name:           "NameTooLong",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    validator.ErrTooLong,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Board name cannot be longer than 35 characters.",
+			),
+		},
+		{
+			name:           "InvalidTask",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    errImportTask,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Import file has a task with an empty or too long title, " +
+					"description, or subtask.",
+			),
+		},
+		{
+			name:           "LimitReached",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    nil,
+			errInsertBoard: db.ErrLimitReached,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"You have already created the maximum amount of boards " +
+					"allowed per team. Please delete one of your boards to " +
+					"import a new one.",
+			),
+		},
+		{
+			name:           "ErrInsertBoard",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    nil,
+			errInsertBoard: errors.New("failed to insert board"),
+			errInsertTasks: nil,
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("failed to insert board"),
+		},
+		{
+			name:           "ErrInsertTasks",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    nil,
+			errInsertBoard: nil,
+			errInsertTasks: errors.New("failed to insert tasks"),
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("failed to insert tasks"),
+		},
+		{
+			name:           "OK",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			body:           body,
+			errValidate:    nil,
+			errInsertBoard: nil,
+			errInsertTasks: nil,
+			wantStatus:     http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var got ImportResp
+				err := json.NewDecoder(resp.Body).Decode(&got)
+				assert.Nil(t.Fatal, err)
+
+				assert.Equal(t.Error, len(got.IDs), 3)
+				for _, old := range []string{
+					"oldBoard", "oldTask0", "oldTask1",
+				} {
+					assert.True(t.Error, got.IDs[old] != "")
+				}
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			validateReq.Err = c.errValidate
+			boardInserter.Err = c.errInsertBoard
+			taskInserter.Err = c.errInsertTasks
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
-// PatchReq defines the body of PATCH board requests.
-type PatchReq teamtbl.Board
+// PatchReq defines the body of PATCH board requests. Only the fields that are
+// set are changed on the board.
+type PatchReq teamtbl.BoardPatch
 
 // PatchResp defines the body of PATCH board responses.
 type PatchResp struct {
@@ -22,26 +26,29 @@ type PatchResp struct {
 
 // PatchHandler can be used to handle PATCH board requests.
 type PatchHandler struct {
-	authDecoder   cookie.Decoder[cookie.Auth]
 	idValidator   validator.String
 	nameValidator validator.String
-	boardUpdater  db.UpdaterDualKey[teamtbl.Board]
+	descValidator validator.String
+	validateCols  validator.Func[[]teamtbl.Column]
+	boardUpdater  db.UpdaterDualKey[teamtbl.BoardPatch]
 	log           log.Errorer
 }
 
 // DeleteHandler is an api.MethodHandler that can be used to handle DELETE board
 // requests.
 func NewPatchHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	idValidator validator.String,
 	nameValidator validator.String,
-	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	descValidator validator.String,
+	validateCols validator.Func[[]teamtbl.Column],
+	boardUpdater db.UpdaterDualKey[teamtbl.BoardPatch],
 	log log.Errorer,
 ) *PatchHandler {
 	return &PatchHandler{
-		authDecoder:   authDecoder,
 		idValidator:   idValidator,
 		nameValidator: nameValidator,
+		descValidator: descValidator,
+		validateCols:  validateCols,
 		boardUpdater:  boardUpdater,
 		log:           log,
 	}
@@ -49,45 +56,15 @@ func NewPatchHandler(
 
 // Handle handles PATCH board requests.
 func (h *PatchHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err := json.NewEncoder(w).Encode(
-			PatchResp{Error: "Auth token not found."},
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err := json.NewEncoder(w).Encode(
-			PatchResp{Error: "Invalid auth token."},
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	}
-
 	// validate user is admin
 	if !auth.IsAdmin {
 		w.WriteHeader(http.StatusForbidden)
-		if err = json.NewEncoder(w).Encode(PatchResp{
+		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit boards.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
@@ -96,11 +73,16 @@ func (h *PatchHandler) Handle(
 	// decode board
 	var req PatchReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 
+	// board ID in the path takes precedence over the one in the body
+	if id := api.PathParam(r, "boardID"); id != "" {
+		req.ID = id
+	}
+
 	// validate board ID
 	if err := h.idValidator.Validate(req.ID); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -112,44 +94,127 @@ func (h *PatchHandler) Handle(
 		}
 
 		if err = json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	}
-	if err := h.nameValidator.Validate(req.Name); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		var msg string
-		if errors.Is(err, validator.ErrEmpty) {
-			msg = "Board name cannot be empty."
-		} else if errors.Is(err, validator.ErrTooLong) {
-			msg = "Board name cannot be longer than 35 characters."
+	if req.Name != nil {
+		if err := h.nameValidator.Validate(*req.Name); err != nil {
+			var msg string
+			if errors.Is(err, validator.ErrEmpty) {
+				msg = "Board name cannot be empty."
+			} else if errors.Is(err, validator.ErrTooLong) {
+				msg = "Board name cannot be longer than 35 characters."
+			}
+			h.writeErr(w, http.StatusBadRequest, msg)
+			return
 		}
+	}
+	if req.Description != nil {
+		desc := markdown.Sanitize(*req.Description)
+		if err := h.descValidator.Validate(desc); err != nil {
+			h.writeErr(w, http.StatusBadRequest,
+				"Board description cannot be longer than 1000 characters.",
+			)
+			return
+		}
+		req.Description = &desc
+	}
 
-		if err := json.NewEncoder(w).Encode(
-			PatchResp{Error: msg},
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
+	if req.Columns != nil {
+		if err := h.validateCols(*req.Columns); err != nil {
+			msg, ok := colsErrMsg(err)
+			if !ok {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+				return
+			}
+			h.writeErr(w, http.StatusBadRequest, msg)
+			return
 		}
+	}
+
+	if v := req.Visibility; v != nil && *v != "" &&
+		*v != teamtbl.VisibilityTeam && *v != teamtbl.VisibilityRestricted {
+		h.writeErr(w, http.StatusBadRequest,
+			"Board visibility must be team or restricted.",
+		)
+		return
+	}
+
+	if v := req.BlockedTasks; v != nil && *v != "" &&
+		*v != teamtbl.BlockedTasksWarn && *v != teamtbl.BlockedTasksRefuse {
+		h.writeErr(w, http.StatusBadRequest,
+			"Blocked tasks setting must be warn or refuse.",
+		)
+		return
+	}
+
+	if v := req.ArchiveAfterDays; v != nil &&
+		(*v < 0 || *v > validator.MaxArchiveDays) {
+		h.writeErr(w, http.StatusBadRequest,
+			"Archive days must be between 0 and 365.",
+		)
 		return
 	}
 
 	// update the board for the team
 	if err := h.boardUpdater.Update(
-		r.Context(), auth.TeamID, teamtbl.Board(req),
+		r.Context(), auth.TeamID, teamtbl.BoardPatch(req),
 	); errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		if err := json.NewEncoder(w).Encode(
 			PatchResp{Error: "Board not found."},
 		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	} else if errors.Is(err, db.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board was modified by someone else. Please refresh the " +
+				"page and try again.",
+		}); err != nil {
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
 }
+
+// writeErr writes the given status and error message to the response.
+func (h *PatchHandler) writeErr(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
+
+// colsErrMsg returns the message to respond with for the first column setting
+// that failed validation, and false if err is not a validation error.
+func colsErrMsg(err error) (string, bool) {
+	fe, ok := validator.First(err)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case errors.Is(fe, errColumnsTooMany):
+		return "Board cannot have more than 4 columns.", true
+	case strings.HasSuffix(fe.Path, ".color"):
+		return "Column color must be a hex color (e.g. #1a2b3c).", true
+	case strings.HasSuffix(fe.Path, ".description"):
+		return "Column description cannot be longer than 200 characters.",
+			true
+	default:
+		return "", false
+	}
+}
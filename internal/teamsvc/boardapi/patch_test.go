@@ -19,59 +19,40 @@ import (
 )
 
 func TestPatchHandler(t *testing.T) {
-	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
 	idValidator := &api.FakeStringValidator{}
 	nameValidator := &api.FakeStringValidator{}
-	updater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
+	descValidator := &api.FakeStringValidator{}
+	validateCols := &validator.FakeFunc[[]teamtbl.Column]{}
+	updater := &db.FakeUpdaterDualKey[teamtbl.BoardPatch]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
-		decodeAuth,
 		idValidator,
 		nameValidator,
+		descValidator,
+		validateCols.Func,
 		updater,
 		log,
 	)
 
 	for _, c := range []struct {
 		name            string
-		authToken       string
-		errDecodeAuth   error
 		authDecoded     cookie.Auth
+		body            string
 		errValidateID   error
 		errValidateName error
+		errValidateDesc error
+		errValidateCols error
 		errUpdateBoard  error
 		wantStatus      int
 		assertFunc      func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:            "NoAuth",
-			authToken:       "",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{},
-			errValidateID:   nil,
-			errValidateName: nil,
-			errUpdateBoard:  nil,
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:            "InvalidAuth",
-			authToken:       "nonempty",
-			errDecodeAuth:   cookie.ErrInvalid,
-			authDecoded:     cookie.Auth{},
-			errValidateID:   nil,
-			errValidateName: nil,
-			errUpdateBoard:  nil,
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      assert.OnRespErr("Invalid auth token."),
-		},
 		{
 			name:            "NotAdmin",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false},
 			errValidateID:   nil,
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
@@ -80,99 +61,261 @@ func TestPatchHandler(t *testing.T) {
 		},
 		{
 			name:            "IDEmpty",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateID:   validator.ErrEmpty,
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board ID cannot be empty."),
 		},
 		{
 			name:            "IDNotUUID",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateID:   validator.ErrWrongFormat,
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board ID must be a UUID."),
 		},
 		{
 			name:            "NameEmpty",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateID:   nil,
 			errValidateName: validator.ErrEmpty,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board name cannot be empty."),
 		},
 		{
 			name:            "NameTooLong",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateID:   nil,
 			errValidateName: validator.ErrTooLong,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Board name cannot be longer than 35 characters.",
 			),
 		},
+		{
+			name:            "DescTooLong",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateID:   nil,
+			errValidateName: nil,
+			errValidateDesc: validator.ErrTooLong,
+			errValidateCols: nil,
+			errUpdateBoard:  nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Board description cannot be longer than 1000 " +
+					"characters.",
+			),
+		},
+		{
+			name:            "ColumnsTooMany",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateID:   nil,
+			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: validator.FieldErr{
+				Path: "columns", Err: errColumnsTooMany,
+			},
+			errUpdateBoard: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Board cannot have more than 4 columns.",
+			),
+		},
+		{
+			name:            "ColumnColorInvalid",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateID:   nil,
+			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: validator.Errs{{
+				Path: "columns[1].color", Err: validator.ErrWrongFormat,
+			}},
+			errUpdateBoard: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column color must be a hex color (e.g. #1a2b3c).",
+			),
+		},
+		{
+			name:            "ColumnDescriptionTooLong",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateID:   nil,
+			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: validator.Errs{{
+				Path: "columns[0].description", Err: validator.ErrTooLong,
+			}},
+			errUpdateBoard: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column description cannot be longer than 200 characters.",
+			),
+		},
+		{
+			name:            "ColumnsValidateErr",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateID:   nil,
+			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: errors.New("validate columns failed"),
+			errUpdateBoard:  nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("validate columns failed"),
+		},
+		{
+			name:        "VisibilityInvalid",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"visibility": "public"}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Board visibility must be team or restricted.",
+			),
+		},
+		{
+			name:        "ArchiveDaysInvalid",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"archiveAfterDays": 366}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Archive days must be between 0 and 365.",
+			),
+		},
+		{
+			name:        "BlockedTasksInvalid",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"blockedTasks": "ignore"}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Blocked tasks setting must be warn or refuse.",
+			),
+		},
 		{
 			name:            "BoardNotFound",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  db.ErrNoItem,
 			wantStatus:      http.StatusNotFound,
 			assertFunc:      assert.OnRespErr("Board not found."),
 		},
+		{
+			name:            "BoardConflict",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
+			errUpdateBoard:  db.ErrConflict,
+			wantStatus:      http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Board was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
 		{
 			name:            "BoardUpdaterErr",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  errors.New("update board failed"),
 			wantStatus:      http.StatusInternalServerError,
 			assertFunc:      assert.OnLoggedErr("update board failed"),
 		},
 		{
 			name:            "Success",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: nil,
+			errValidateDesc: nil,
+			errValidateCols: nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusOK,
 			assertFunc:      func(*testing.T, *http.Response, []any) {},
 		},
+		{
+			name:        "SuccessNameOnly",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"name": "Renamed"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// only the name is to be changed on the stored board
+				name := "Renamed"
+				assert.DeepEqual(t.Error, updater.Updated, teamtbl.BoardPatch{
+					ID: "c193d6ba-ebfe-45fe-80d9-00b545690b4b", Name: &name,
+				})
+			},
+		},
+		{
+			name:        "SuccessTeamVisible",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"members": ["bob123"], "visibility": "team"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					*updater.Updated.Visibility, teamtbl.VisibilityTeam,
+				)
+				assert.AllEqual(t.Error,
+					*updater.Updated.Members, []string{"bob123"},
+				)
+			},
+		},
+		{
+			name:        "SuccessArchive",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"archiveAfterDays": 14}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, *updater.Updated.ArchiveAfterDays, 14)
+			},
+		},
+		{
+			name:        "SuccessBlockedTasks",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+				`"blockedTasks": "refuse"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					*updater.Updated.BlockedTasks, teamtbl.BlockedTasksRefuse,
+				)
+			},
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			decodeAuth.Err = c.errDecodeAuth
-			decodeAuth.Res = c.authDecoded
 			idValidator.Err = c.errValidateID
 			nameValidator.Err = c.errValidateName
+			descValidator.Err = c.errValidateDesc
+			validateCols.Err = c.errValidateCols
 			updater.Err = c.errUpdateBoard
-			w := httptest.NewRecorder()
-			r := httptest.NewRequest("", "/", strings.NewReader(`{
-                "id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
-            }`))
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name:  cookie.AuthName,
-					Value: c.authToken,
-				})
+			updater.Updated = teamtbl.BoardPatch{}
+			body := c.body
+			if body == "" {
+				body = `{"id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b", ` +
+					`"name": "Board", "description": "", "columns": []}`
 			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("", "/", strings.NewReader(body))
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
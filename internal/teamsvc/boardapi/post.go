@@ -7,16 +7,19 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/markdown"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // PostReq defines the body of POST board requests.
 type PostReq struct {
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // PostResp defines the body of POST board responses.
@@ -27,22 +30,22 @@ type PostResp struct {
 // DeleteHandler is an api.MethodHandler that can be used to handle POST board
 // requests.
 type PostHandler struct {
-	authDecoder   cookie.Decoder[cookie.Auth]
 	nameValidator validator.String
+	descValidator validator.String
 	inserter      db.InserterDualKey[teamtbl.Board]
 	log           log.Errorer
 }
 
 // NewPostHandler creates and returns a new PostHandler.
 func NewPostHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	nameValidator validator.String,
+	descValidator validator.String,
 	inserter db.InserterDualKey[teamtbl.Board],
 	log log.Errorer,
 ) *PostHandler {
 	return &PostHandler{
-		authDecoder:   authDecoder,
 		nameValidator: nameValidator,
+		descValidator: descValidator,
 		inserter:      inserter,
 		log:           log,
 	}
@@ -50,58 +53,28 @@ func NewPostHandler(
 
 // Handle handles DELETE board requests.
 func (h PostHandler) Handle(
-	w http.ResponseWriter, r *http.Request, username string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err := json.NewEncoder(w).Encode(
-			PatchResp{Error: "Auth token not found."},
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		h.log.Error(err)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		if err := json.NewEncoder(w).Encode(
-			PatchResp{Error: "Invalid auth token."},
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.log.Error(err)
-		}
-		return
-	}
-
 	// validate user is admin
 	if !auth.IsAdmin {
 		w.WriteHeader(http.StatusForbidden)
-		if err = json.NewEncoder(w).Encode(PatchResp{
+		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit boards.",
 		}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	}
 
-	// get and validate board name
+	// get and validate board name and description
 	var req PostReq
-	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
-	if err = h.nameValidator.Validate(req.Name); err != nil {
+	if err := h.nameValidator.Validate(req.Name); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		var msg string
 		if errors.Is(err, validator.ErrEmpty) {
@@ -110,19 +83,31 @@ func (h PostHandler) Handle(
 			msg = "Board name cannot be longer than 35 characters."
 		}
 
-		if err = json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+		if err := json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+			h.log.Error(err)
+			w.WriteHeader(api.ErrStatus(err))
+		}
+		return
+	}
+	req.Description = markdown.Sanitize(req.Description)
+	if err := h.descValidator.Validate(req.Description); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Board description cannot be longer than 1000 characters.",
+		}); err != nil {
 			h.log.Error(err)
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 		}
 		return
 	}
 
 	// insert the board into the team's boards in the team table - retry up to 3
 	// times for the unlikely event that the generated UUID is a duplicate
+	var err error
 	for i := 0; i < 3; i++ {
 		id := uuid.NewString()
 		if err = h.inserter.Insert(r.Context(), auth.TeamID, teamtbl.Board{
-			ID: id, Name: req.Name,
+			ID: id, Name: req.Name, Description: req.Description,
 		}); !errors.Is(err, db.ErrDupKey) {
 			break
 		}
@@ -137,12 +122,13 @@ func (h PostHandler) Handle(
 			},
 		); err != nil {
 			h.log.Error(err)
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 		}
 		return
 	} else if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
+
 }
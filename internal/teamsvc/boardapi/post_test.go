@@ -19,48 +19,26 @@ import (
 )
 
 func TestPostHandler(t *testing.T) {
-	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
 	nameValidator := &api.FakeStringValidator{}
+	descValidator := &api.FakeStringValidator{}
 	inserter := &db.FakeInserterDualKey[teamtbl.Board]{}
 	log := &log.FakeErrorer{}
-	sut := NewPostHandler(decodeAuth, nameValidator, inserter, log)
+	sut := NewPostHandler(nameValidator, descValidator, inserter, log)
 
 	for _, c := range []struct {
 		name            string
-		authToken       string
-		errDecodeAuth   error
 		authDecoded     cookie.Auth
 		errValidateName error
+		errValidateDesc error
 		boardUpdaterErr error
 		wantStatusCode  int
 		assertFunc      func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:            "NoAuth",
-			authToken:       "",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{},
-			errValidateName: nil,
-			boardUpdaterErr: nil,
-			wantStatusCode:  http.StatusUnauthorized,
-			assertFunc:      assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:            "InvalidAuth",
-			authToken:       "nonempty",
-			errDecodeAuth:   cookie.ErrInvalid,
-			authDecoded:     cookie.Auth{},
-			errValidateName: nil,
-			boardUpdaterErr: nil,
-			wantStatusCode:  http.StatusUnauthorized,
-			assertFunc:      assert.OnRespErr("Invalid auth token."),
-		},
 		{
 			name:            "NotAdmin",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false},
 			errValidateName: nil,
+			errValidateDesc: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
@@ -69,32 +47,41 @@ func TestPostHandler(t *testing.T) {
 		},
 		{
 			name:            "NameEmpty",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: validator.ErrEmpty,
+			errValidateDesc: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board name cannot be empty."),
 		},
 		{
 			name:            "NameTooLong",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: validator.ErrTooLong,
+			errValidateDesc: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Board name cannot be longer than 35 characters.",
 			),
 		},
+		{
+			name:            "DescTooLong",
+			authDecoded:     cookie.Auth{IsAdmin: true},
+			errValidateName: nil,
+			errValidateDesc: validator.ErrTooLong,
+			boardUpdaterErr: nil,
+			wantStatusCode:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Board description cannot be longer than 1000 " +
+					"characters.",
+			),
+		},
 		{
 			name:            "ErrLimitReached",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: nil,
+			errValidateDesc: nil,
 			boardUpdaterErr: db.ErrLimitReached,
 			wantStatusCode:  http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
@@ -105,42 +92,33 @@ func TestPostHandler(t *testing.T) {
 		},
 		{
 			name:            "BoardUpdaterErr",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errValidateName: nil,
+			errValidateDesc: nil,
 			boardUpdaterErr: errors.New("update board failed"),
 			wantStatusCode:  http.StatusInternalServerError,
 			assertFunc:      assert.OnLoggedErr("update board failed"),
 		},
 		{
 			name:            "Success",
-			authToken:       "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{IsAdmin: true, TeamID: "team1"},
 			errValidateName: nil,
+			errValidateDesc: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusOK,
 			assertFunc:      func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			decodeAuth.Err = c.errDecodeAuth
-			decodeAuth.Res = c.authDecoded
 			nameValidator.Err = c.errValidateName
+			descValidator.Err = c.errValidateDesc
 			inserter.Err = c.boardUpdaterErr
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/", strings.NewReader(`{
-                "id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+                "name": "My Board"
             }`))
-			if c.authToken != "" {
-				r.AddCookie(&http.Cookie{
-					Name:  "auth-token",
-					Value: c.authToken,
-				})
-			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
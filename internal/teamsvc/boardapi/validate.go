@@ -1,41 +1,104 @@
 package boardapi
 
 import (
-	"github.com/google/uuid"
+	"errors"
 
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
-// NameValidator can be used to validate a board name.
-type NameValidator struct{}
+// ValidateImportReq validates a given ImportReq against the limits that apply
+// to boards and tasks created through the API. The error it returns is a
+// validator.FieldErr with the path to the field that failed validation.
+func ValidateImportReq(req ImportReq) error {
+	if req.Format != exportFormat {
+		return validator.FieldErr{Path: "format", Err: errImportFormat}
+	}
+	if err := validator.Field(
+		"board.name", req.Board.Name, validator.BoardName,
+	); err != nil {
+		return err
+	}
 
-// NewNameValidator creates and returns a new NameValidator.
-func NewNameValidator() NameValidator { return NameValidator{} }
+	cols, ids := map[int]bool{}, map[string]bool{}
+	for i, col := range req.Columns {
+		if validator.ColNo(col.No) != nil || cols[col.No] {
+			return validator.FieldErr{
+				Path: validator.Path("columns", i, "no"),
+				Err:  errImportColumn,
+			}
+		}
+		cols[col.No] = true
 
-// Validate validates a given board name.
-func (b NameValidator) Validate(boardName string) error {
-	if boardName == "" {
-		return validator.ErrEmpty
-	}
-	if len(boardName) > 35 {
-		return validator.ErrTooLong
+		for j, t := range col.Tasks {
+			path := validator.Path("columns", i, "tasks", j)
+			if t.ID == "" || ids[t.ID] {
+				return validator.FieldErr{
+					Path: validator.Path(path, "id"), Err: errImportTaskID,
+				}
+			}
+			ids[t.ID] = true
+
+			if validator.TaskTitle(t.Title) != nil ||
+				validator.TaskDesc(t.Description) != nil {
+				return validator.FieldErr{Path: path, Err: errImportTask}
+			}
+			for k, st := range t.Subtasks {
+				if validator.SubtaskTitle(st.Title) != nil {
+					return validator.FieldErr{
+						Path: validator.Path(path, "subtasks", k, "title"),
+						Err:  errImportTask,
+					}
+				}
+			}
+		}
 	}
 	return nil
 }
 
-// IDValidator can be used to validate a board ID.
-type IDValidator struct{}
-
-// NewIDValidator creates and returns a new IDValidator.
-func NewIDValidator() IDValidator { return IDValidator{} }
-
-// Validate validates a given board ID.
-func (i IDValidator) Validate(boardID string) error {
-	if boardID == "" {
-		return validator.ErrEmpty
+// ValidateColumns validates the settings of a board's columns. The error it
+// returns is validator.Errs with the paths to the fields that failed
+// validation.
+func ValidateColumns(cols []teamtbl.Column) error {
+	if len(cols) > exportColumns {
+		return validator.FieldErr{Path: "columns", Err: errColumnsTooMany}
 	}
-	if _, err := uuid.Parse(boardID); err != nil {
-		return validator.ErrWrongFormat
+	var errs []error
+	for i, col := range cols {
+		errs = append(errs,
+			validator.Field(
+				validator.Path("columns", i, "color"),
+				col.Color,
+				validator.ColColor,
+			),
+			validator.Field(
+				validator.Path("columns", i, "description"),
+				col.Description,
+				validator.ColDesc,
+			),
+		)
 	}
-	return nil
+	return validator.Collect(errs...)
 }
+
+var (
+	// errColumnsTooMany is returned when a board is given settings for more
+	// columns than it has.
+	errColumnsTooMany = errors.New("too many columns")
+
+	// errImportFormat is returned when an import document's format is not
+	// supported.
+	errImportFormat = errors.New("unsupported import format")
+
+	// errImportColumn is returned when an import document's column number is
+	// out of bounds or duplicate.
+	errImportColumn = errors.New("invalid import column")
+
+	// errImportTaskID is returned when an import document's task ID is empty
+	// or duplicate.
+	errImportTaskID = errors.New("invalid import task id")
+
+	// errImportTask is returned when an import document's task has an empty or
+	// too long title, description, or subtask.
+	errImportTask = errors.New("invalid import task")
+)
@@ -3,70 +3,224 @@
 package boardapi
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
-func TestNameValidator(t *testing.T) {
-	sut := NewNameValidator()
+func TestValidateImportReq(t *testing.T) {
+	newReq := func(cols ...ExportColumn) ImportReq {
+		return ImportReq{
+			Format:  exportFormat,
+			Board:   ExportBoard{ID: "b", Name: "Board"},
+			Columns: cols,
+		}
+	}
+	task := func(id, title string) ExportTask {
+		return ExportTask{ID: id, Title: title}
+	}
 
 	for _, c := range []struct {
-		name      string
-		boardName string
-		wantErr   error
+		name    string
+		req     ImportReq
+		wantErr error
 	}{
 		{
-			name:      "Empty",
-			boardName: "",
-			wantErr:   validator.ErrEmpty,
+			name:    "Format",
+			req:     ImportReq{Format: exportFormat + 1},
+			wantErr: errImportFormat,
 		},
 		{
-			name:      "TooLong",
-			boardName: "boardyboardsyboardkyboardishboardxyz",
-			wantErr:   validator.ErrTooLong,
+			name:    "NameEmpty",
+			req:     ImportReq{Format: exportFormat},
+			wantErr: validator.ErrEmpty,
 		},
 		{
-			name:      "OK",
-			boardName: "My Board",
-			wantErr:   nil,
+			name:    "ColumnOutOfBounds",
+			req:     newReq(ExportColumn{No: exportColumns}),
+			wantErr: errImportColumn,
+		},
+		{
+			name:    "ColumnDuplicate",
+			req:     newReq(ExportColumn{No: 1}, ExportColumn{No: 1}),
+			wantErr: errImportColumn,
+		},
+		{
+			name: "TaskIDEmpty",
+			req: newReq(ExportColumn{
+				Tasks: []ExportTask{task("", "Task")},
+			}),
+			wantErr: errImportTaskID,
+		},
+		{
+			name: "TaskIDDuplicate",
+			req: newReq(
+				ExportColumn{No: 0, Tasks: []ExportTask{task("t", "Task")}},
+				ExportColumn{No: 1, Tasks: []ExportTask{task("t", "Task")}},
+			),
+			wantErr: errImportTaskID,
+		},
+		{
+			name: "TaskTitleTooLong",
+			req: newReq(ExportColumn{
+				Tasks: []ExportTask{task("t", strings.Repeat("a", 51))},
+			}),
+			wantErr: errImportTask,
+		},
+		{
+			name: "SubtaskTitleEmpty",
+			req: newReq(ExportColumn{Tasks: []ExportTask{{
+				ID: "t", Title: "Task", Subtasks: []tasktbl.Subtask{{}},
+			}}}),
+			wantErr: errImportTask,
+		},
+		{
+			name: "OK",
+			req: newReq(
+				ExportColumn{No: 0, Tasks: []ExportTask{task("t0", "Task")}},
+				ExportColumn{No: 3, Tasks: []ExportTask{task("t1", "Task")}},
+			),
+			wantErr: nil,
 		},
 	} {
-		err := sut.Validate(c.boardName)
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateImportReq(c.req)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
 
-		assert.ErrIs(t.Error, err, c.wantErr)
+// TestValidateImportReqPath tests the ValidateImportReq function to assert
+// that its errors carry the path to the field that failed validation.
+func TestValidateImportReqPath(t *testing.T) {
+	req := ImportReq{
+		Format: exportFormat,
+		Board:  ExportBoard{ID: "b", Name: "Board"},
+		Columns: []ExportColumn{
+			{No: 0, Tasks: []ExportTask{{ID: "t0", Title: "Task"}}},
+			{No: 1, Tasks: []ExportTask{{
+				ID: "t1", Title: "Task", Subtasks: []tasktbl.Subtask{{}},
+			}}},
+		},
 	}
+
+	err := ValidateImportReq(req)
+
+	fe, ok := validator.First(err)
+	assert.True(t.Fatal, ok)
+	assert.Equal(t.Error, fe.Path, "columns[1].tasks[0].subtasks[0].title")
+	assert.ErrIs(t.Error, err, errImportTask)
 }
 
-func TestIDValidator(t *testing.T) {
-	sut := NewIDValidator()
+// FuzzValidateImportReq fuzzes the decoding of board import request bodies and
+// their validation by ValidateImportReq to assert that neither panics, and that
+// an accepted document can be imported as-is: its columns are unique and in
+// range, and its tasks have unique IDs and valid fields.
+func FuzzValidateImportReq(f *testing.F) {
+	for _, body := range []string{
+		`{}`,
+		`{"format":1,"board":{"name":"Board"},"columns":[{"no":0,"tasks":` +
+			`[{"id":"t1","title":"Task","subtasks":[{"title":"Sub"}]}]}]}`,
+		`{"format":1,"board":{"name":"Board"},"columns":[{"no":0},{"no":0}]}`,
+		`{"format":1,"board":{"name":"Board"},"columns":[{"no":0,"tasks":` +
+			`[{"id":"t1","title":"A"},{"id":"t1","title":"B"}]}]}`,
+		`{"format":1,"board":{"name":""},"columns":null}`,
+		`{"format":1,"exportedAt":"not a time"}`,
+		`null`,
+	} {
+		f.Add([]byte(body))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req ImportReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+
+		if err := ValidateImportReq(req); err != nil {
+			fe, ok := validator.First(err)
+			assert.True(t.Error, ok && fe.Path != "")
+			return
+		}
 
+		assert.Equal(t.Error, req.Format, exportFormat)
+		assert.Nil(t.Error, validator.BoardName(req.Board.Name))
+		cols, ids := map[int]bool{}, map[string]bool{}
+		for _, col := range req.Columns {
+			assert.Nil(t.Error, validator.ColNo(col.No))
+			assert.True(t.Error, !cols[col.No])
+			cols[col.No] = true
+			for _, task := range col.Tasks {
+				assert.True(t.Error, task.ID != "" && !ids[task.ID])
+				ids[task.ID] = true
+				assert.Nil(t.Error, validator.TaskTitle(task.Title))
+				assert.Nil(t.Error, validator.TaskDesc(task.Description))
+				for _, st := range task.Subtasks {
+					assert.Nil(t.Error, validator.SubtaskTitle(st.Title))
+				}
+			}
+		}
+	})
+}
+
+// TestValidateColumns tests the ValidateColumns function to assert that it
+// returns the errors of all the column settings that fail validation.
+func TestValidateColumns(t *testing.T) {
 	for _, c := range []struct {
-		name    string
-		boardID string
-		wantErr error
+		name      string
+		cols      []teamtbl.Column
+		wantPaths []string
+		wantErr   error
 	}{
 		{
-			name:    "Empty",
-			boardID: "",
-			wantErr: validator.ErrEmpty,
+			name:      "None",
+			cols:      nil,
+			wantPaths: nil,
+			wantErr:   nil,
 		},
 		{
-			name:    "NotUUID",
-			boardID: "21",
-			wantErr: validator.ErrWrongFormat,
+			name:      "TooMany",
+			cols:      make([]teamtbl.Column, exportColumns+1),
+			wantPaths: nil,
+			wantErr:   errColumnsTooMany,
 		},
 		{
-			name:    "Success",
-			boardID: "97377e55-5a2a-4172-bf5d-354b40aa2735",
-			wantErr: nil,
+			name: "Invalid",
+			cols: []teamtbl.Column{
+				{Color: "#1a2b3c", Description: "To do"},
+				{Color: "blue", Description: strings.Repeat("a", 201)},
+			},
+			wantPaths: []string{"columns[1].color", "columns[1].description"},
+			wantErr:   validator.ErrWrongFormat,
+		},
+		{
+			name: "OK",
+			cols: []teamtbl.Column{
+				{Color: "#1a2b3c", Description: "To do"}, {}, {Color: "#FFFFFF"},
+			},
+			wantPaths: nil,
+			wantErr:   nil,
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			err := sut.Validate(c.boardID)
+			err := ValidateColumns(c.cols)
 
 			assert.ErrIs(t.Error, err, c.wantErr)
+			var errs validator.Errs
+			if errors.As(err, &errs) {
+				paths := make([]string, len(errs))
+				for i, fe := range errs {
+					paths[i] = fe.Path
+				}
+				assert.AllEqual(t.Error, paths, c.wantPaths)
+			}
 		})
 	}
 }
@@ -0,0 +1,3 @@
+// Package checklistapi contains code for responding to HTTP requests made to
+// the team checklist API route.
+package checklistapi
@@ -0,0 +1,90 @@
+package checklistapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the checklist route.
+type DeleteHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the checklist route. The tasks that
+// the checklist was applied to keep the subtasks it added to them.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// validate ID
+	id := r.URL.Query().Get("id")
+	if err := validator.ID(id); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// retrieve the team and remove the checklist from its checklists
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	checklists := make([]teamtbl.Checklist, 0, len(team.Checklists))
+	for _, c := range team.Checklists {
+		if c.ID != id {
+			checklists = append(checklists, c)
+		}
+	}
+	if len(checklists) == len(team.Checklists) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	team.Checklists = checklists
+
+	// update the team
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if errors.Is(err, db.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,127 @@
+//go:build utest
+
+package checklistapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(teamRetriever, teamUpdater, log)
+
+	const id = "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21"
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1"}
+	team := teamtbl.Team{Checklists: []teamtbl.Checklist{
+		{ID: "checklist1"}, {ID: id},
+	}}
+
+	for _, c := range []struct {
+		name        string
+		checklistID string
+		authDecoded cookie.Auth
+		team        teamtbl.Team
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			checklistID: id,
+			authDecoded: cookie.Auth{IsAdmin: false},
+			wantStatus:  http.StatusForbidden,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "InvalidID",
+			checklistID: "checklist1",
+			authDecoded: admin,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "TeamNotFound",
+			checklistID: id,
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrRetrieve",
+			checklistID: id,
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "NotFound",
+			checklistID: id,
+			authDecoded: admin,
+			team:        teamtbl.Team{},
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "Conflict",
+			checklistID: id,
+			authDecoded: admin,
+			team:        team,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrUpdate",
+			checklistID: id,
+			authDecoded: admin,
+			team:        team,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "OK",
+			checklistID: id,
+			authDecoded: admin,
+			team:        team,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				got := teamUpdater.Updated.Checklists
+				assert.Equal(t.Fatal, len(got), 1)
+				assert.Equal(t.Error, got[0].ID, "checklist1")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete, "/?id="+c.checklistID, nil,
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package checklistapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET checklist responses.
+type GetResp []teamtbl.Checklist
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// checklist route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the checklist route. It responds with
+// the team's checklist templates in the order they were created.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// retrieve team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	resp := append(GetResp{}, team.Checklists...)
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build utest
+
+package checklistapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(teamRetriever, log)
+
+	wantIDs := func(ids ...string) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var got GetResp
+			err := json.NewDecoder(resp.Body).Decode(&got)
+			assert.Nil(t.Fatal, err)
+			gotIDs := make([]string, len(got))
+			for i, c := range got {
+				gotIDs[i] = c.ID
+			}
+			assert.AllEqual(t.Error, gotIDs, ids)
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		team        teamtbl.Team
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "TeamNotFound",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:       "None",
+			wantStatus: http.StatusOK,
+			assertFunc: wantIDs(),
+		},
+		{
+			name: "OK",
+			team: teamtbl.Team{Checklists: []teamtbl.Checklist{
+				{ID: "checklist1"}, {ID: "checklist2"},
+			}},
+			wantStatus: http.StatusOK,
+			assertFunc: wantIDs("checklist1", "checklist2"),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			sut.Handle(w, r, cookie.Auth{TeamID: "team1"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package checklistapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PatchReq defines the body of PATCH checklist requests.
+type PatchReq teamtbl.Checklist
+
+// PatchResp defines the body of PATCH checklist responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
+// the checklist route.
+type PatchHandler struct {
+	validateChecklist validator.Func[teamtbl.Checklist]
+	teamRetriever     db.Retriever[teamtbl.Team]
+	teamUpdater       db.Updater[teamtbl.Team]
+	log               log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	validateChecklist validator.Func[teamtbl.Checklist],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		validateChecklist: validateChecklist,
+		teamRetriever:     teamRetriever,
+		teamUpdater:       teamUpdater,
+		log:               log,
+	}
+}
+
+// Handle handles PATCH requests sent to the checklist route. The checklist is
+// replaced as a whole. The tasks that it was applied to keep the subtasks it
+// added to them.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can edit checklists.",
+		)
+		return
+	}
+
+	// decode and validate the checklist
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	if err := validator.Collect(
+		validator.Field("id", req.ID, validator.ID),
+		h.validateChecklist(teamtbl.Checklist(req)),
+	); err != nil {
+		msg, ok := errMsg(err)
+		if !ok {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		h.writeResp(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// retrieve the team and replace the checklist in its checklists
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	found := false
+	for i, c := range team.Checklists {
+		if c.ID == req.ID {
+			team.Checklists[i] = teamtbl.Checklist(req)
+			found = true
+			break
+		}
+	}
+	if !found {
+		h.writeResp(w, http.StatusNotFound, "Checklist not found.")
+		return
+	}
+
+	// update the team
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict, conflictMsg)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h PatchHandler) writeResp(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,151 @@
+//go:build utest
+
+package checklistapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	validateChecklist := &validator.FakeFunc[teamtbl.Checklist]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		validateChecklist.Func, teamRetriever, teamUpdater, log,
+	)
+
+	const id = "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21"
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1"}
+	body := `{"id": "` + id + `", "name": "Release", "items": ["Tag it"]}`
+	team := teamtbl.Team{Checklists: []teamtbl.Checklist{
+		{ID: "checklist1"}, {ID: id, Name: "Old"},
+	}}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		body        string
+		errValidate error
+		team        teamtbl.Team
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			body:        body,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit checklists.",
+			),
+		},
+		{
+			name:        "InvalidBody",
+			authDecoded: admin,
+			body:        "{",
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:        "InvalidID",
+			authDecoded: admin,
+			body:        `{"id": "checklist1"}`,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Checklist ID must be a UUID."),
+		},
+		{
+			name:        "InvalidChecklist",
+			authDecoded: admin,
+			body:        body,
+			errValidate: validator.Errs{{
+				Path: "name", Err: validator.ErrEmpty,
+			}},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Checklist name cannot be empty."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			body:        body,
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "NotFound",
+			authDecoded: admin,
+			body:        body,
+			team:        teamtbl.Team{},
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Checklist not found."),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			body:        body,
+			team:        team,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			body:        body,
+			team:        team,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "OK",
+			authDecoded: admin,
+			body:        body,
+			team:        team,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				got := teamUpdater.Updated.Checklists
+				assert.Equal(t.Fatal, len(got), 2)
+				assert.Equal(t.Error, got[0].ID, "checklist1")
+				assert.Equal(t.Error, got[1].Name, "Release")
+				assert.AllEqual(t.Error, got[1].Items, []string{"Tag it"})
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			validateChecklist.Err = c.errValidate
+			teamRetriever.Res = c.team.Clone()
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
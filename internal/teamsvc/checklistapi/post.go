@@ -0,0 +1,141 @@
+package checklistapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST checklist requests.
+type PostReq struct {
+	Name  string   `json:"name"`
+	Items []string `json:"items"`
+}
+
+// PostResp defines the body of POST checklist responses.
+type PostResp struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the checklist route.
+type PostHandler struct {
+	validateChecklist validator.Func[teamtbl.Checklist]
+	teamRetriever     db.Retriever[teamtbl.Team]
+	teamUpdater       db.Updater[teamtbl.Team]
+	log               log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	validateChecklist validator.Func[teamtbl.Checklist],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		validateChecklist: validateChecklist,
+		teamRetriever:     teamRetriever,
+		teamUpdater:       teamUpdater,
+		log:               log,
+	}
+}
+
+// Handle handles POST requests sent to the checklist route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, PostResp{
+			Error: "Only team admins can create checklists.",
+		})
+		return
+	}
+
+	// decode and validate the checklist
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "Invalid request body.",
+		})
+		return
+	}
+	checklist := teamtbl.Checklist{Name: req.Name, Items: req.Items}
+	if err := h.validateChecklist(checklist); err != nil {
+		msg, ok := errMsg(err)
+		if !ok {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		h.writeResp(w, http.StatusBadRequest, PostResp{Error: msg})
+		return
+	}
+
+	// retrieve the team and check it has room for another checklist
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if len(team.Checklists) >= validator.MaxChecklists {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "A team cannot have more than 20 checklists.",
+		})
+		return
+	}
+
+	// add the checklist to the team's checklists with an ID that is not
+	// taken by another
+	for {
+		checklist.ID = uuid.NewString()
+		if _, ok := team.Checklist(checklist.ID); !ok {
+			break
+		}
+	}
+	team.Checklists = append(team.Checklists, checklist)
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict, PostResp{Error: conflictMsg})
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusCreated, PostResp{ID: checklist.ID})
+}
+
+// writeResp writes the given status and response.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
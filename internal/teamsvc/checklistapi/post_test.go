@@ -0,0 +1,143 @@
+//go:build utest
+
+package checklistapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	validateChecklist := &validator.FakeFunc[teamtbl.Checklist]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		validateChecklist.Func, teamRetriever, teamUpdater, log,
+	)
+
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1"}
+	full := teamtbl.Team{
+		Checklists: make([]teamtbl.Checklist, validator.MaxChecklists),
+	}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		errValidate error
+		team        teamtbl.Team
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can create checklists.",
+			),
+		},
+		{
+			name:        "InvalidChecklist",
+			authDecoded: admin,
+			errValidate: validator.Errs{{
+				Path: "items", Err: errTooManyItems,
+			}},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Checklist cannot have more than 30 items.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "TooMany",
+			authDecoded: admin,
+			team:        full,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"A team cannot have more than 20 checklists.",
+			),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "OK",
+			authDecoded: admin,
+			team: teamtbl.Team{
+				Checklists: []teamtbl.Checklist{{ID: "checklist1"}},
+			},
+			wantStatus: http.StatusCreated,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp PostResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+
+				got := teamUpdater.Updated.Checklists
+				assert.Equal(t.Fatal, len(got), 2)
+				assert.Equal(t.Error, got[1].ID, resp.ID)
+				assert.Equal(t.Error, got[1].Name, "Release")
+				assert.AllEqual(t.Error, got[1].Items, []string{"Tag it"})
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			validateChecklist.Err = c.errValidate
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(
+				`{"name": "Release", "items": ["Tag it"]}`,
+			))
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
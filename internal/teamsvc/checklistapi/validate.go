@@ -0,0 +1,72 @@
+package checklistapi
+
+import (
+	"errors"
+
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// ValidateChecklist validates the name and items of a given checklist. The
+// error it returns is validator.Errs with the paths to the fields that failed
+// validation.
+func ValidateChecklist(c teamtbl.Checklist) error {
+	errs := []error{
+		validator.Field("name", c.Name, validator.ChecklistName),
+	}
+	switch {
+	case len(c.Items) == 0:
+		errs = append(errs, validator.FieldErr{
+			Path: "items", Err: validator.ErrEmpty,
+		})
+	case len(c.Items) > validator.MaxChecklistItems:
+		errs = append(errs, validator.FieldErr{
+			Path: "items", Err: errTooManyItems,
+		})
+	}
+	for i, item := range c.Items {
+		errs = append(errs, validator.Field(
+			validator.Path("items", i), item, validator.SubtaskTitle,
+		))
+	}
+	return validator.Collect(errs...)
+}
+
+// errTooManyItems is returned when a checklist has more items than
+// validator.MaxChecklistItems.
+var errTooManyItems = errors.New("too many items")
+
+// errMsg returns the message to respond with for the first field of a
+// checklist that failed validation, and false if err is not a validation
+// error.
+func errMsg(err error) (string, bool) {
+	fe, ok := validator.First(err)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case fe.Path == "id" && errors.Is(fe, validator.ErrEmpty):
+		return "Checklist ID cannot be empty.", true
+	case fe.Path == "id":
+		return "Checklist ID must be a UUID.", true
+	case fe.Path == "name" && errors.Is(fe, validator.ErrEmpty):
+		return "Checklist name cannot be empty.", true
+	case fe.Path == "name":
+		return "Checklist name cannot be longer than 35 characters.", true
+	case fe.Path == "items" && errors.Is(fe, validator.ErrEmpty):
+		return "Checklist must have at least one item.", true
+	case fe.Path == "items":
+		return "Checklist cannot have more than 30 items.", true
+	case errors.Is(fe, validator.ErrEmpty):
+		return "Checklist items cannot be empty.", true
+	case errors.Is(fe, validator.ErrTooLong):
+		return "Checklist items cannot be longer than 50 characters.", true
+	default:
+		return "", false
+	}
+}
+
+// conflictMsg is the message that the writes that would overwrite the changes
+// made to the team since it was read are refused with.
+const conflictMsg = "Team was modified by someone else. Please refresh the " +
+	"page and try again."
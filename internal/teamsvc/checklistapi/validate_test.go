@@ -0,0 +1,99 @@
+//go:build utest
+
+package checklistapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestValidateChecklist tests the ValidateChecklist func to assert that it
+// returns the error of the first field that failed validation.
+func TestValidateChecklist(t *testing.T) {
+	valid := teamtbl.Checklist{
+		Name:  "Release",
+		Items: []string{"Bump version", "Tag release"},
+	}
+
+	for _, c := range []struct {
+		name     string
+		edit     func(*teamtbl.Checklist)
+		wantPath string
+		wantErr  error
+	}{
+		{
+			name:     "NameEmpty",
+			edit:     func(c *teamtbl.Checklist) { c.Name = "" },
+			wantPath: "name",
+			wantErr:  validator.ErrEmpty,
+		},
+		{
+			name: "NameTooLong",
+			edit: func(c *teamtbl.Checklist) {
+				c.Name = strings.Repeat("a", validator.MaxChecklistNameLen+1)
+			},
+			wantPath: "name",
+			wantErr:  validator.ErrTooLong,
+		},
+		{
+			name:     "NoItems",
+			edit:     func(c *teamtbl.Checklist) { c.Items = nil },
+			wantPath: "items",
+			wantErr:  validator.ErrEmpty,
+		},
+		{
+			name: "TooManyItems",
+			edit: func(c *teamtbl.Checklist) {
+				c.Items = make([]string, validator.MaxChecklistItems+1)
+				for i := range c.Items {
+					c.Items[i] = "Item"
+				}
+			},
+			wantPath: "items",
+			wantErr:  errTooManyItems,
+		},
+		{
+			name:     "ItemEmpty",
+			edit:     func(c *teamtbl.Checklist) { c.Items[1] = "" },
+			wantPath: "items[1]",
+			wantErr:  validator.ErrEmpty,
+		},
+		{
+			name: "ItemTooLong",
+			edit: func(c *teamtbl.Checklist) {
+				c.Items[0] = strings.Repeat(
+					"a", validator.MaxSubtaskTitleLen+1,
+				)
+			},
+			wantPath: "items[0]",
+			wantErr:  validator.ErrTooLong,
+		},
+		{
+			name:     "OK",
+			edit:     func(*teamtbl.Checklist) {},
+			wantPath: "",
+			wantErr:  nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cl := valid
+			cl.Items = append([]string(nil), valid.Items...)
+			c.edit(&cl)
+
+			err := ValidateChecklist(cl)
+
+			if c.wantErr == nil {
+				assert.Nil(t.Fatal, err)
+				return
+			}
+			fe, ok := validator.First(err)
+			assert.True(t.Fatal, ok)
+			assert.Equal(t.Error, fe.Path, c.wantPath)
+			assert.ErrIs(t.Error, fe, c.wantErr)
+		})
+	}
+}
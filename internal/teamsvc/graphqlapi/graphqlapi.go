@@ -0,0 +1,4 @@
+// Package graphqlapi contains code for responding to HTTP requests made to the
+// GraphQL API route, which can be used to fetch the team, its boards and
+// members, and the tasks of a board in a single request.
+package graphqlapi
@@ -0,0 +1,291 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/graphql"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PostReq defines the body of POST graphql requests.
+type PostReq struct {
+	Query string `json:"query"`
+}
+
+// PostResp defines the body of POST graphql responses.
+type PostResp struct {
+	Data   map[string]any `json:"data,omitempty"`
+	Errors []RespErr      `json:"errors,omitempty"`
+}
+
+// RespErr defines an error in the body of POST graphql responses.
+type RespErr struct {
+	Message string `json:"message"`
+}
+
+var (
+	// errForbidden means that the user is not allowed to access the requested
+	// data.
+	errForbidden = errors.New("forbidden")
+
+	// errGuest means that the data requested cannot be seen by guests.
+	errGuest = errors.New("guest")
+
+	// errUnknownField means that a root field in the query is not supported.
+	errUnknownField = errors.New("unknown field")
+
+	// errInvalidArg means that an argument in the query has an invalid value.
+	errInvalidArg = errors.New("invalid argument")
+)
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the graphql route.
+type PostHandler struct {
+	teamRetriever    db.Retriever[teamtbl.Team]
+	retrieverByBoard db.Retriever[[]tasktbl.Task]
+	retrieverByTeam  db.Retriever[[]tasktbl.Task]
+	log              log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	retrieverByBoard db.Retriever[[]tasktbl.Task],
+	retrieverByTeam db.Retriever[[]tasktbl.Task],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		teamRetriever:    teamRetriever,
+		retrieverByBoard: retrieverByBoard,
+		retrieverByTeam:  retrieverByTeam,
+		log:              log,
+	}
+}
+
+// Handle handles POST requests sent to the graphql route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// decode and parse query
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErr(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	fields, err := graphql.Parse(req.Query)
+	if err != nil {
+		h.writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// resolve each root field - the team is retrieved at most once and shared
+	// between the fields that need it
+	res := resolver{PostHandler: h, ctx: r.Context(), auth: auth}
+	data := map[string]any{}
+	for _, f := range fields {
+		v, err := res.resolve(f)
+		if errors.Is(err, db.ErrNoItem) {
+			h.writeErr(w, http.StatusNotFound, "Team not found.")
+			return
+		} else if errors.Is(err, errForbidden) {
+			h.writeErr(w, http.StatusForbidden,
+				"You do not have access to this board.",
+			)
+			return
+		} else if errors.Is(err, errGuest) {
+			h.writeErr(w, http.StatusForbidden,
+				"Guests cannot see the team's members.",
+			)
+			return
+		} else if errors.Is(err, errUnknownField) ||
+			errors.Is(err, errInvalidArg) {
+			h.writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		} else if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+
+		// shape the resolved value according to the selection set
+		if data[f.Name], err = graphql.Select(v, f.Selections); err != nil {
+			h.writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// write response
+	if err := json.NewEncoder(w).Encode(PostResp{Data: data}); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
+
+// writeErr writes the given status code and a response with the given error
+// message.
+func (h PostHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(
+		PostResp{Errors: []RespErr{{Message: msg}}},
+	); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
+
+// resolver resolves the root fields of a single query.
+type resolver struct {
+	PostHandler
+	ctx  context.Context
+	auth cookie.Auth
+	team *teamtbl.Team
+}
+
+// resolve resolves the value of the given root field.
+func (r *resolver) resolve(f graphql.Field) (any, error) {
+	switch f.Name {
+	case "team":
+		return r.getTeam()
+	case "boards":
+		team, err := r.getTeam()
+		if err != nil {
+			return nil, err
+		}
+		return team.Boards, nil
+	case "members":
+		if r.auth.IsGuest {
+			return nil, errGuest
+		}
+		team, err := r.getTeam()
+		if err != nil {
+			return nil, err
+		}
+		return team.Members, nil
+	case "tasks":
+		// the archived tasks are only returned, in place of the others, if
+		// the archived argument is true
+		archived := false
+		if a, ok := f.Args["archived"]; ok {
+			var err error
+			if archived, err = strconv.ParseBool(a); err != nil {
+				return nil, fmt.Errorf("%w %q", errInvalidArg, "archived")
+			}
+		}
+		tasks, err := r.getTasks(f.Args["boardID"])
+		if err != nil {
+			return nil, err
+		}
+		filtered := []tasktbl.Task{}
+		for _, t := range tasks {
+			if (t.ArchivedAt != 0) == archived {
+				filtered = append(filtered, t)
+			}
+		}
+		return filtered, nil
+	default:
+		return nil, fmt.Errorf("%w %q", errUnknownField, f.Name)
+	}
+}
+
+// getTeam retrieves the user's team on the first call and returns the same
+// team on subsequent calls. Non-admin users only see the boards they can
+// access, and guests are only told of themselves as the team's members.
+func (r *resolver) getTeam() (teamtbl.Team, error) {
+	if r.team != nil {
+		return *r.team, nil
+	}
+
+	team, err := r.teamRetriever.Retrieve(r.ctx, r.auth.TeamID)
+	if err != nil {
+		return teamtbl.Team{}, err
+	}
+
+	if !r.auth.IsAdmin {
+		var boards []teamtbl.Board
+		for _, b := range team.Boards {
+			if b.IsVisibleTo(r.auth.Username, false, r.auth.IsGuest) {
+				boards = append(boards, b)
+			}
+		}
+		team.Boards = boards
+	}
+	if r.auth.IsGuest {
+		team.Members = []string{r.auth.Username}
+		for i := range team.Boards {
+			team.Boards[i].Members = []string{r.auth.Username}
+		}
+	}
+
+	r.team = &team
+	return team, nil
+}
+
+// getTasks retrieves the tasks of the given board, or the tasks of the team's
+// first board if the board ID is empty. Non-admin users only see the tasks of
+// the boards they can access.
+func (r *resolver) getTasks(boardID string) ([]tasktbl.Task, error) {
+	var team teamtbl.Team
+	if !r.auth.IsAdmin {
+		var err error
+		if team, err = r.getTeam(); err != nil {
+			return nil, err
+		}
+		if boardID != "" && !team.HasBoard(boardID) {
+			return nil, errForbidden
+		}
+	}
+
+	if boardID == "" {
+		tasks, err := r.retrieverByTeam.Retrieve(r.ctx, r.auth.TeamID)
+		if errors.Is(err, db.ErrNoItem) {
+			return []tasktbl.Task{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if !r.auth.IsAdmin {
+			var visible []tasktbl.Task
+			for _, t := range tasks {
+				if team.HasBoard(t.BoardID) {
+					visible = append(visible, t)
+				}
+			}
+			if tasks = visible; len(tasks) == 0 {
+				return []tasktbl.Task{}, nil
+			}
+		}
+
+		// only return the tasks with the first task's board ID
+		var singleBoardTasks []tasktbl.Task
+		for _, t := range tasks {
+			if t.BoardID == tasks[0].BoardID {
+				singleBoardTasks = append(singleBoardTasks, t)
+			}
+		}
+		return singleBoardTasks, nil
+	}
+
+	tasks, err := r.retrieverByBoard.Retrieve(r.ctx, boardID)
+	if errors.Is(err, db.ErrNoItem) {
+		return []tasktbl.Task{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// validate that all tasks belong to user's team
+	for _, t := range tasks {
+		if t.TeamID != r.auth.TeamID {
+			return nil, errForbidden
+		}
+	}
+	return tasks, nil
+}
@@ -0,0 +1,344 @@
+//go:build utest
+
+package graphqlapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
+	retrieverByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		teamRetriever, retrieverByBoard, retrieverByTeam, log,
+	)
+
+	team := teamtbl.Team{
+		ID:      "team1",
+		Members: []string{"bob", "sally"},
+		Boards: []teamtbl.Board{
+			{ID: "board1", Name: "Board 1", Members: []string{"sally"}},
+			{ID: "board2", Name: "Board 2", Members: []string{"bob"}},
+		},
+	}
+	tasks := []tasktbl.Task{
+		{TeamID: "team1", BoardID: "board1", ID: "task1", Title: "Task 1"},
+		{TeamID: "team1", BoardID: "board1", ID: "task2", Title: "Task 2"},
+		{TeamID: "team1", BoardID: "board2", ID: "task3", Title: "Task 3"},
+	}
+	archived := append(tasks[:2:2], tasktbl.Task{
+		TeamID: "team1", BoardID: "board1", ID: "task4", Title: "Task 4",
+		ArchivedAt: 1700000000,
+	})
+
+	// onRespErr asserts that the given message was written to the response
+	// body's errors field
+	onRespErr := func(wantMsg string) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body PostResp
+			err := json.NewDecoder(resp.Body).Decode(&body)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Fatal, len(body.Errors), 1)
+			assert.Equal(t.Error, body.Errors[0].Message, wantMsg)
+		}
+	}
+
+	// onRespData asserts that the response body's data field equals the
+	// given JSON
+	onRespData := func(wantData string) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body struct{ Data json.RawMessage }
+			err := json.NewDecoder(resp.Body).Decode(&body)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, string(body.Data), wantData)
+		}
+	}
+
+	for _, c := range []struct {
+		name           string
+		authDecoded    cookie.Auth
+		reqBody        string
+		team           teamtbl.Team
+		errRetrieve    error
+		tasks          []tasktbl.Task
+		errRetrieveTsk error
+		wantStatus     int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:           "InvalidBody",
+			authDecoded:    cookie.Auth{},
+			reqBody:        "{",
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc:     onRespErr("Invalid request body."),
+		},
+		{
+			name:           "InvalidQuery",
+			authDecoded:    cookie.Auth{},
+			reqBody:        `{"query": "{ team {"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc: onRespErr(
+				"syntax error at position 8: expected field name",
+			),
+		},
+		{
+			name:           "UnknownRootField",
+			authDecoded:    cookie.Auth{},
+			reqBody:        `{"query": "{ users }"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc:     onRespErr(`unknown field "users"`),
+		},
+		{
+			name:           "TeamNotFound",
+			authDecoded:    cookie.Auth{},
+			reqBody:        `{"query": "{ team { id } }"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    db.ErrNoItem,
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusNotFound,
+			assertFunc:     onRespErr("Team not found."),
+		},
+		{
+			name:           "ErrRetrieveTeam",
+			authDecoded:    cookie.Auth{},
+			reqBody:        `{"query": "{ members }"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    errors.New("retrieve failed"),
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:           "UnknownNestedField",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			reqBody:        `{"query": "{ team { foo } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          nil,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc:     onRespErr(`unknown field "foo"`),
+		},
+		{
+			name:           "ErrRetrieveTasks",
+			authDecoded:    cookie.Auth{IsAdmin: true},
+			reqBody:        `{"query": "{ tasks(boardID: \"board1\") { id } }"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			tasks:          nil,
+			errRetrieveTsk: errors.New("retrieve tasks failed"),
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:           "TasksWrongTeam",
+			authDecoded:    cookie.Auth{TeamID: "team2", IsAdmin: true},
+			reqBody:        `{"query": "{ tasks(boardID: \"board1\") { id } }"}`,
+			team:           teamtbl.Team{},
+			errRetrieve:    nil,
+			tasks:          tasks,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusForbidden,
+			assertFunc: onRespErr(
+				"You do not have access to this board.",
+			),
+		},
+		{
+			name:        "OKAdmin",
+			authDecoded: cookie.Auth{TeamID: "team1", IsAdmin: true},
+			reqBody: `{"query": "{ team { id } boards { name } members ` +
+				`tasks { id } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          tasks,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusOK,
+			assertFunc: onRespData(`{` +
+				`"boards":[{"name":"Board 1"},{"name":"Board 2"}],` +
+				`"members":["bob","sally"],` +
+				`"tasks":[{"id":"task1"},{"id":"task2"}],` +
+				`"team":{"id":"team1"}}`,
+			),
+		},
+		{
+			name:           "ArchivedInvalid",
+			authDecoded:    cookie.Auth{TeamID: "team1", IsAdmin: true},
+			reqBody:        `{"query": "{ tasks(archived: yes) { id } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          archived,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusBadRequest,
+			assertFunc:     onRespErr(`invalid argument "archived"`),
+		},
+		{
+			name:        "OKNotArchived",
+			authDecoded: cookie.Auth{TeamID: "team1", IsAdmin: true},
+			reqBody: `{"query": "{ ` +
+				`tasks(boardID: \"board1\") { id } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          archived,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusOK,
+			assertFunc: onRespData(
+				`{"tasks":[{"id":"task1"},{"id":"task2"}]}`,
+			),
+		},
+		{
+			name:        "OKArchived",
+			authDecoded: cookie.Auth{TeamID: "team1", IsAdmin: true},
+			reqBody: `{"query": "{ ` +
+				`tasks(boardID: \"board1\", archived: true) { id } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          archived,
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusOK,
+			assertFunc:     onRespData(`{"tasks":[{"id":"task4"}]}`),
+		},
+		{
+			name: "OKMember",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsAdmin: false,
+			},
+			reqBody: `{"query": "{ boards { id } ` +
+				`tasks(boardID: \"board2\") { title } }"}`,
+			team:           team,
+			errRetrieve:    nil,
+			tasks:          tasks[2:],
+			errRetrieveTsk: nil,
+			wantStatus:     http.StatusOK,
+			assertFunc: onRespData(`{` +
+				`"boards":[{"id":"board2"}],` +
+				`"tasks":[{"title":"Task 3"}]}`,
+			),
+		},
+		{
+			name: "MemberTasksRestricted",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsAdmin: false,
+			},
+			reqBody:    `{"query": "{ tasks(boardID: \"board1\") { id } }"}`,
+			team:       team,
+			tasks:      tasks,
+			wantStatus: http.StatusForbidden,
+			assertFunc: onRespErr(
+				"You do not have access to this board.",
+			),
+		},
+		{
+			name: "OKMemberTeamVisible",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsAdmin: false,
+			},
+			reqBody: `{"query": "{ boards { id } ` +
+				`tasks(boardID: \"board1\") { id } }"}`,
+			team: teamtbl.Team{
+				ID: "team1",
+				Boards: []teamtbl.Board{
+					{
+						ID:         "board1",
+						Members:    []string{"sally"},
+						Visibility: teamtbl.VisibilityTeam,
+					},
+					{ID: "board2", Members: []string{"bob"}},
+				},
+			},
+			tasks:      tasks[:2],
+			wantStatus: http.StatusOK,
+			assertFunc: onRespData(`{` +
+				`"boards":[{"id":"board1"},{"id":"board2"}],` +
+				`"tasks":[{"id":"task1"},{"id":"task2"}]}`,
+			),
+		},
+		{
+			name: "GuestMembers",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsGuest: true,
+			},
+			reqBody:    `{"query": "{ members }"}`,
+			team:       team,
+			wantStatus: http.StatusForbidden,
+			assertFunc: onRespErr("Guests cannot see the team's members."),
+		},
+		{
+			name: "GuestTasksNotBoardMember",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsGuest: true,
+			},
+			reqBody:    `{"query": "{ tasks(boardID: \"board1\") { id } }"}`,
+			team:       team,
+			tasks:      tasks,
+			wantStatus: http.StatusForbidden,
+			assertFunc: onRespErr(
+				"You do not have access to this board.",
+			),
+		},
+		{
+			name: "OKGuest",
+			authDecoded: cookie.Auth{
+				Username: "bob", TeamID: "team1", IsGuest: true,
+			},
+			reqBody: `{"query": "{ team { members boards { id members } } ` +
+				`tasks { id } }"}`,
+			team:       team,
+			tasks:      tasks,
+			wantStatus: http.StatusOK,
+			assertFunc: onRespData(`{` +
+				`"tasks":[{"id":"task3"}],` +
+				`"team":{"boards":[{"id":"board2","members":["bob"]}],` +
+				`"members":["bob"]}}`,
+			),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			retrieverByBoard.Res = c.tasks
+			retrieverByBoard.Err = c.errRetrieveTsk
+			retrieverByTeam.Res = c.tasks
+			retrieverByTeam.Err = c.errRetrieveTsk
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.reqBody),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package inviteapi contains code for responding to HTTP requests made to the
+// team invite API route, which is used for emailing invites to join a team.
+package inviteapi
@@ -0,0 +1,209 @@
+package inviteapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST invite requests.
+type PostReq struct {
+	Email string `json:"email"`
+
+	// BoardIDs holds the boards to invite the person to as a guest. The
+	// person is invited as a member of the team if it is empty.
+	BoardIDs []string `json:"boardIDs,omitempty"`
+}
+
+// PostResp defines the body of POST invite responses.
+type PostResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to the
+// invite route.
+type PostHandler struct {
+	emailValidator validator.String
+	teamRetriever  db.Retriever[teamtbl.Team]
+	teamUpdater    db.Updater[teamtbl.Team]
+	inviteEncoder  cookie.Encoder[cookie.Invite]
+	mailSender     mail.Sender
+	registerURL    string
+	auditInserter  db.Inserter[audittbl.Entry]
+	clock          clock.Clock
+	log            log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler. The invite links
+// emailed to people point to the given register page URL.
+func NewPostHandler(
+	emailValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	inviteEncoder cookie.Encoder[cookie.Invite],
+	mailSender mail.Sender,
+	registerURL string,
+	auditInserter db.Inserter[audittbl.Entry],
+	clock clock.Clock,
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		emailValidator: emailValidator,
+		teamRetriever:  teamRetriever,
+		teamUpdater:    teamUpdater,
+		inviteEncoder:  inviteEncoder,
+		mailSender:     mailSender,
+		registerURL:    registerURL,
+		auditInserter:  auditInserter,
+		clock:          clock,
+		log:            log,
+	}
+}
+
+// Handle handles POST requests sent to the invite route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can invite members.",
+		)
+		return
+	}
+
+	// decode and validate the email address
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	if err := h.emailValidator.Validate(req.Email); err != nil {
+		var msg string
+		switch {
+		case errors.Is(err, validator.ErrEmpty):
+			msg = "Email cannot be empty."
+		case errors.Is(err, validator.ErrTooLong):
+			msg = "Email cannot be longer than 254 characters."
+		default:
+			msg = "Email is invalid."
+		}
+		h.writeResp(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// retrieve the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// guests can only be invited to the team's boards
+	for _, id := range req.BoardIDs {
+		if !team.HasBoard(id) {
+			h.writeResp(w, http.StatusBadRequest, "Board not found.")
+			return
+		}
+	}
+
+	// encode a single-use invite bound to the email address
+	nonce := uuid.NewString()
+	inv := cookie.NewEmailInvite(team.ID, req.Email, nonce)
+	inv.IsGuest = len(req.BoardIDs) > 0
+	ckInv, err := h.inviteEncoder.Encode(r.Context(), inv)
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the invite as pending on the team, replacing any earlier invite
+	// to the same address and dropping the expired ones
+	now := h.clock.Now().Unix()
+	var invites []teamtbl.Invite
+	for _, inv := range team.Invites {
+		if inv.Email != req.Email && inv.ExpiresAt > now {
+			invites = append(invites, inv)
+		}
+	}
+	team.Invites = append(invites, teamtbl.Invite{
+		Nonce:     nonce,
+		Email:     req.Email,
+		ExpiresAt: ckInv.Expires.Unix(),
+		BoardIDs:  req.BoardIDs,
+	})
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict,
+			"Team was modified by someone else. Please refresh the page "+
+				"and try again.",
+		)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the invite in the team's audit log - the invite has already been
+	// issued at this point, so a failure here is only logged
+	if err = h.auditInserter.Insert(r.Context(), audittbl.NewEntry(
+		team.ID,
+		audittbl.ActionInvite,
+		auth.Username,
+		req.Email,
+		api.RemoteIP(r),
+	)); err != nil {
+		h.log.Error(err)
+	}
+
+	// email the invite link
+	link := h.registerURL + "?inviteToken=" + url.QueryEscape(ckInv.Value)
+	if err = h.mailSender.Send(r.Context(), mail.Message{
+		To:      req.Email,
+		Subject: "You're invited to join a team on GoTeam!",
+		Body: fmt.Sprintf(
+			"%s invited you to join their team on GoTeam!\n\n"+
+				"Sign up with the link below before %s to join:\n%s\n",
+			auth.Username, ckInv.Expires.Format(time.RFC1123), link,
+		),
+	}); err != nil {
+		h.log.Error(err)
+		h.writeResp(w, http.StatusBadGateway,
+			"Invite email could not be sent. Please try again later.",
+		)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h PostHandler) writeResp(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
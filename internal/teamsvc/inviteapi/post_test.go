@@ -0,0 +1,313 @@
+//go:build utest
+
+package inviteapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	emailValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	inviteEncoder := &cookie.FakeEncoder[cookie.Invite]{}
+	mailSender := &mail.FakeSender{}
+	auditInserter := &db.FakeInserter[audittbl.Entry]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewPostHandler(
+		emailValidator,
+		teamRetriever,
+		teamUpdater,
+		inviteEncoder,
+		mailSender,
+		"https://goteam.test/register",
+		auditInserter,
+		clk,
+		log,
+	)
+
+	const body = `{"email": "bob@example.com"}`
+	expires := clk.Time.Add(time.Hour)
+	team := teamtbl.Team{
+		ID:     "team1",
+		Boards: []teamtbl.Board{{ID: "board1"}},
+		Invites: []teamtbl.Invite{
+			{Email: "old@example.com", ExpiresAt: 1},
+			{Email: "bob@example.com", ExpiresAt: expires.Unix()},
+			{Email: "eve@example.com", ExpiresAt: expires.Unix()},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		body        string
+		errValidate error
+		errRetrieve error
+		errEncode   error
+		errUpdate   error
+		errAudit    error
+		errSend     error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can invite members.",
+			),
+		},
+		{
+			name:        "InvalidBody",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        "{",
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:        "EmailEmpty",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: validator.ErrEmpty,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Email cannot be empty."),
+		},
+		{
+			name:        "EmailTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: validator.ErrTooLong,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Email cannot be longer than 254 characters.",
+			),
+		},
+		{
+			name:        "EmailInvalid",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: validator.ErrWrongFormat,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Email is invalid."),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: db.ErrNoItem,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: errors.New("retrieve failed"),
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "GuestBoardNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"email": "bob@example.com", ` +
+				`"boardIDs": ["board1", "board2"]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "ErrEncode",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   errors.New("encode failed"),
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("encode failed"),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   db.ErrConflict,
+			errSend:     nil,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   errors.New("update failed"),
+			errSend:     nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "ErrAudit",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errAudit:    errors.New("audit failed"),
+			errSend:     nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  assert.OnLoggedErr("audit failed"),
+		},
+		{
+			name:        "ErrSend",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     errors.New("send failed"),
+			wantStatus:  http.StatusBadGateway,
+			assertFunc: func(t *testing.T, resp *http.Response, args []any) {
+				assert.OnRespErr(
+					"Invite email could not be sent. Please try again later.",
+				)(t, resp, args)
+				assert.OnLoggedErr("send failed")(t, resp, args)
+			},
+		},
+		{
+			name:        "OK",
+			authDecoded: cookie.Auth{IsAdmin: true, Username: "alice"},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errEncode:   nil,
+			errUpdate:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the expired invite and the earlier invite to the same
+				// address should be replaced with the new one
+				invites := teamUpdater.Updated.Invites
+				assert.Equal(t.Fatal, len(invites), 2)
+				assert.Equal(t.Error, invites[0].Email, "eve@example.com")
+				assert.Equal(t.Error, invites[1].Email, "bob@example.com")
+				assert.Equal(t.Error, invites[1].ExpiresAt, expires.Unix())
+				assert.True(t.Error, invites[1].Nonce != "")
+				assert.Equal(t.Error, len(invites[1].BoardIDs), 0)
+
+				// the invite should be recorded in the audit log
+				e := auditInserter.Inserted
+				assert.Equal(t.Error, e.TeamID, "team1")
+				assert.Equal(t.Error, e.Action, audittbl.ActionInvite)
+				assert.Equal(t.Error, e.Actor, "alice")
+				assert.Equal(t.Error, e.Target, "bob@example.com")
+				assert.Equal(t.Error, e.IP, "192.0.2.1")
+
+				// the invite link should be emailed to the address
+				msg := mailSender.Messages[len(mailSender.Messages)-1]
+				assert.Equal(t.Error, msg.To, "bob@example.com")
+				assert.True(t.Error, strings.Contains(msg.Body, "alice"))
+				assert.True(t.Error, strings.Contains(msg.Body,
+					"https://goteam.test/register?inviteToken=tk%2B1",
+				))
+			},
+		},
+		{
+			name:        "OKGuest",
+			authDecoded: cookie.Auth{IsAdmin: true, Username: "alice"},
+			body:        `{"email": "bob@example.com", "boardIDs": ["board1"]}`,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the boards the guest is invited to should be recorded on
+				// the pending invite
+				invites := teamUpdater.Updated.Invites
+				assert.Equal(t.Fatal, len(invites), 2)
+				assert.AllEqual(t.Error,
+					invites[1].BoardIDs, []string{"board1"},
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			emailValidator.Err = c.errValidate
+			teamRetriever.Res = team
+			teamRetriever.Err = c.errRetrieve
+			inviteEncoder.Res = http.Cookie{Value: "tk+1", Expires: expires}
+			inviteEncoder.Err = c.errEncode
+			teamUpdater.Err = c.errUpdate
+			auditInserter.Err = c.errAudit
+			mailSender.Err = c.errSend
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package membersapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// roles a team member can have.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleGuest  = "guest"
+)
+
+// maxLimit is the maximum and default number of members returned in a page.
+const maxLimit = 100
+
+// GetResp defines the body of GET members responses.
+type GetResp struct {
+	Error   string      `json:"error,omitempty"`
+	Members []GetMember `json:"members,omitempty"`
+}
+
+// GetMember defines a team member in a GetResp.
+type GetMember struct {
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	IsDisabled bool   `json:"isDisabled"`
+
+	// AvatarURL is the path of the member's avatar on the user service.
+	AvatarURL string `json:"avatarURL"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// members route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	userRetriever db.Retriever[usertbl.User]
+	cursorSigner  api.CursorSigner
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	userRetriever db.Retriever[usertbl.User],
+	cursorSigner api.CursorSigner,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		userRetriever: userRetriever,
+		cursorSigner:  cursorSigner,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the members route. The members are
+// ordered by username, can be searched by username with the q query parameter,
+// and can be paginated with the limit and cursor query parameters, in which
+// case the cursor for the next page is sent in the Next-Cursor header.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate the user is not a guest
+	if auth.IsGuest {
+		h.writeResp(w, http.StatusForbidden, GetResp{
+			Error: "Guests cannot see the team's members.",
+		})
+		return
+	}
+
+	// read the search term, page size and cursor
+	q := strings.ToLower(r.URL.Query().Get("q"))
+	limit := maxLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 ||
+			limit > maxLimit {
+			h.writeResp(w, http.StatusBadRequest, GetResp{
+				Error: "Limit must be between 1 and 100.",
+			})
+			return
+		}
+	}
+	var after string
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		var err error
+		if after, err = h.cursorSigner.Verify(
+			auth.TeamID, cursor,
+		); err != nil {
+			h.writeResp(w, http.StatusBadRequest, GetResp{
+				Error: "Invalid cursor.",
+			})
+			return
+		}
+	}
+
+	// retrieve the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// select the matching members on the requested page
+	var usernames []string
+	for _, m := range team.Members {
+		if m > after && strings.Contains(strings.ToLower(m), q) {
+			usernames = append(usernames, m)
+		}
+	}
+	sort.Strings(usernames)
+	hasNext := len(usernames) > limit
+	if hasNext {
+		usernames = usernames[:limit]
+	}
+
+	// retrieve each member's role - members without a user record yet are
+	// listed as regular members
+	var resp GetResp
+	for _, username := range usernames {
+		member := GetMember{Username: username, Role: RoleMember}
+		user, err := h.userRetriever.Retrieve(r.Context(), username)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		if user.IsAdmin {
+			member.Role = RoleAdmin
+		} else if user.IsGuest {
+			member.Role = RoleGuest
+		}
+		member.IsDisabled = user.IsDisabled
+		member.AvatarURL = avatar.URL(username, user.AvatarHash)
+		resp.Members = append(resp.Members, member)
+	}
+
+	// set the cursor for the next page if there is one
+	if hasNext {
+		w.Header().Set(api.NextCursorHeader, h.cursorSigner.Sign(
+			auth.TeamID, usernames[limit-1],
+		))
+	}
+
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,238 @@
+//go:build utest
+
+package membersapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	cursorSigner := api.NewCursorSigner([]byte("key"))
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(
+		teamRetriever, userRetriever, cursorSigner, log,
+	)
+
+	team := teamtbl.Team{
+		ID:      "team1",
+		Members: []string{"carol", "alice", "bob", "Alicia"},
+	}
+
+	// assertMembers returns a function that asserts on the usernames, roles,
+	// and avatar URLs of the members in the response and on the cursor for
+	// the next page.
+	assertMembers := func(
+		wantNext string, wantRole string, wantUsernames ...string,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body GetResp
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t.Fatal, len(body.Members), len(wantUsernames))
+			for i, m := range body.Members {
+				assert.Equal(t.Error, m.Username, wantUsernames[i])
+				assert.Equal(t.Error, m.Role, wantRole)
+				assert.Equal(t.Error, m.AvatarURL, avatar.URL(
+					wantUsernames[i], userRetriever.Res.AvatarHash,
+				))
+			}
+
+			next := resp.Header.Get(api.NextCursorHeader)
+			if wantNext == "" {
+				assert.Equal(t.Error, next, "")
+				return
+			}
+			after, err := cursorSigner.Verify("team1", next)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, after, wantNext)
+		}
+	}
+
+	for _, c := range []struct {
+		name            string
+		isGuest         bool
+		query           string
+		errRetrieveTeam error
+		user            usertbl.User
+		errRetrieveUser error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "Guest",
+			isGuest:    true,
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Guests cannot see the team's members.",
+			),
+		},
+		{
+			name:            "LimitNotNumber",
+			query:           "limit=one",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Limit must be between 1 and 100.",
+			),
+		},
+		{
+			name:            "LimitTooLarge",
+			query:           "limit=101",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Limit must be between 1 and 100.",
+			),
+		},
+		{
+			name: "InvalidCursor",
+			query: "cursor=" +
+				cursorSigner.Sign("team2", "bob"),
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Invalid cursor."),
+		},
+		{
+			name:            "TeamNotFound",
+			query:           "",
+			errRetrieveTeam: db.ErrNoItem,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Team not found."),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			query:           "",
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:            "ErrRetrieveUser",
+			query:           "",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: errors.New("retrieve user failed"),
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:            "OKNoUser",
+			query:           "",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: db.ErrNoItem,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertMembers(
+				"", RoleMember, "Alicia", "alice", "bob", "carol",
+			),
+		},
+		{
+			name:            "OKAdmin",
+			query:           "",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{IsAdmin: true},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertMembers(
+				"", RoleAdmin, "Alicia", "alice", "bob", "carol",
+			),
+		},
+		{
+			name:            "OKGuestMembers",
+			query:           "",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{IsGuest: true},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertMembers(
+				"", RoleGuest, "Alicia", "alice", "bob", "carol",
+			),
+		},
+		{
+			name:            "OKAvatar",
+			query:           "",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{AvatarHash: "abc"},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertMembers(
+				"", RoleMember, "Alicia", "alice", "bob", "carol",
+			),
+		},
+		{
+			name:            "OKSearch",
+			query:           "q=ALI",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertMembers("", RoleMember, "Alicia", "alice"),
+		},
+		{
+			name:            "OKFirstPage",
+			query:           "limit=2",
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc: assertMembers(
+				"alice", RoleMember, "Alicia", "alice",
+			),
+		},
+		{
+			name: "OKLastPage",
+			query: "limit=2&cursor=" +
+				cursorSigner.Sign("team1", "alice"),
+			errRetrieveTeam: nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      assertMembers("", RoleMember, "bob", "carol"),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = team
+			teamRetriever.Err = c.errRetrieveTeam
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/?"+c.query, nil)
+
+			sut.Handle(w, r, cookie.Auth{
+				TeamID: "team1", IsGuest: c.isGuest,
+			})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package membersapi contains code for responding to HTTP requests made to the
+// team members API route, which is used for listing the members of a team.
+package membersapi
@@ -0,0 +1,170 @@
+package publicapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// GetResp defines the body of GET public board responses. It only holds what
+// is shown on the board, leaving out its members and the IDs of its team and
+// sprints.
+type GetResp struct {
+	Board   Board    `json:"board"`
+	Columns []Column `json:"columns"`
+}
+
+// GetErrResp defines the body of error responses written by GetHandler.
+type GetErrResp struct {
+	Error string `json:"error"`
+}
+
+// Board defines the board in a GetResp.
+type Board struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Columns     []teamtbl.Column `json:"columns,omitempty"`
+}
+
+// Column defines a column of the board in a GetResp.
+type Column struct {
+	No    int    `json:"no"`
+	Tasks []Task `json:"tasks"`
+}
+
+// Task defines a task in a Column.
+type Task struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Order       int               `json:"order"`
+	Subtasks    []tasktbl.Subtask `json:"subtasks"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// public board route. It serves the board that the share token in the query
+// grants access to, as long as the board was not unshared since.
+type GetHandler struct {
+	shareDecoder  cookie.StringDecoder[cookie.Share]
+	teamRetriever db.Retriever[teamtbl.Team]
+	taskRetriever db.Retriever[[]tasktbl.Task]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	shareDecoder cookie.StringDecoder[cookie.Share],
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		shareDecoder:  shareDecoder,
+		teamRetriever: teamRetriever,
+		taskRetriever: taskRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the public board route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	// decode the share token - invalid and revoked tokens are reported the
+	// same so as not to tell which boards exist
+	const notFound = "Share link is invalid or was revoked."
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		h.writeResp(w, http.StatusBadRequest, GetErrResp{
+			Error: "Share token cannot be empty.",
+		})
+		return
+	}
+	share, err := h.shareDecoder.Decode(token)
+	if err != nil {
+		h.writeResp(w, http.StatusNotFound, GetErrResp{Error: notFound})
+		return
+	}
+
+	// find the board, which must still be shared with the token's nonce
+	team, err := h.teamRetriever.Retrieve(r.Context(), share.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetErrResp{Error: notFound})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var (
+		board teamtbl.Board
+		found bool
+	)
+	for _, b := range team.Boards {
+		if b.ID == share.BoardID {
+			board, found = b, b.ShareNonce != "" && subtle.ConstantTimeCompare(
+				[]byte(b.ShareNonce), []byte(share.Nonce),
+			) == 1
+			break
+		}
+	}
+	if !found {
+		h.writeResp(w, http.StatusNotFound, GetErrResp{Error: notFound})
+		return
+	}
+
+	// retrieve the board's tasks and group them into columns in their order,
+	// leaving out the archived ones as the board does
+	tasks, err := h.taskRetriever.Retrieve(r.Context(), board.ID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	tasktbl.SortByRank(tasks)
+	cols := make([]Column, validator.MaxColNo+1)
+	for no := range cols {
+		cols[no] = Column{No: no, Tasks: []Task{}}
+	}
+	for _, t := range tasks {
+		if t.TeamID != team.ID || t.ArchivedAt != 0 ||
+			t.ColNo < 0 || t.ColNo >= len(cols) {
+			continue
+		}
+		cols[t.ColNo].Tasks = append(cols[t.ColNo].Tasks, Task{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			Order:       t.Order,
+			Subtasks:    t.Subtasks,
+		})
+	}
+
+	h.writeResp(w, http.StatusOK, GetResp{
+		Board: Board{
+			ID:          board.ID,
+			Name:        board.Name,
+			Description: board.Description,
+			Columns:     board.Columns,
+		},
+		Columns: cols,
+	})
+}
+
+// writeResp writes the given status and response body.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp any) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
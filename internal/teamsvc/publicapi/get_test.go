@@ -0,0 +1,180 @@
+//go:build utest
+
+package publicapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	shareDecoder := &cookie.FakeStringDecoder[cookie.Share]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(shareDecoder, teamRetriever, taskRetriever, log)
+
+	const notFound = "Share link is invalid or was revoked."
+	share := cookie.NewShare("team1", "board1", "nonce1")
+	team := teamtbl.Team{
+		ID:      "team1",
+		Members: []string{"team1", "bob123"},
+		Boards: []teamtbl.Board{{
+			ID:          "board1",
+			Name:        "Roadmap",
+			Members:     []string{"team1", "bob123"},
+			Description: "What we are building next.",
+			Columns:     []teamtbl.Column{{Color: "#1a2b3c"}},
+			ShareNonce:  "nonce1",
+		}},
+	}
+	unshared := team.Clone()
+	unshared.Boards[0].ShareNonce = ""
+
+	for _, c := range []struct {
+		name          string
+		token         string
+		errDecode     error
+		team          teamtbl.Team
+		errRetrieve   error
+		tasks         []tasktbl.Task
+		errRetrieveTs error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoToken",
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Share token cannot be empty."),
+		},
+		{
+			name:       "InvalidToken",
+			token:      "token1",
+			errDecode:  cookie.ErrInvalid,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr(notFound),
+		},
+		{
+			name:        "TeamNotFound",
+			token:       "token1",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr(notFound),
+		},
+		{
+			name:        "ErrRetrieve",
+			token:       "token1",
+			errRetrieve: errors.New("retrieve team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:       "BoardNotFound",
+			token:      "token1",
+			team:       teamtbl.Team{ID: "team1"},
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr(notFound),
+		},
+		{
+			name:       "Revoked",
+			token:      "token1",
+			team:       unshared,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr(notFound),
+		},
+		{
+			name:          "ErrRetrieveTasks",
+			token:         "token1",
+			team:          team,
+			errRetrieveTs: errors.New("retrieve tasks failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:  "OK",
+			token: "token1",
+			team:  team,
+			tasks: []tasktbl.Task{
+				{
+					TeamID: "team1", BoardID: "board1", ColNo: 1,
+					ID: "task2", Title: "Second", Order: 1, Rank: "b",
+					SprintID: "sprint1",
+				},
+				{
+					TeamID: "team1", BoardID: "board1", ColNo: 1,
+					ID: "task1", Title: "First", Rank: "a",
+					Subtasks: []tasktbl.Subtask{{Title: "Sub"}},
+				},
+				{
+					TeamID: "team2", BoardID: "board1", ColNo: 0,
+					ID: "task3", Title: "Other team's", Rank: "a",
+				},
+				{
+					TeamID: "team1", BoardID: "board1", ColNo: 3,
+					ID: "task4", Title: "Archived", Rank: "a",
+					ArchivedAt: 1700000000,
+				},
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var raw map[string]json.RawMessage
+				if err := json.NewDecoder(resp.Body).Decode(
+					&raw,
+				); err != nil {
+					t.Fatal(err)
+				}
+				var board map[string]any
+				if err := json.Unmarshal(raw["board"], &board); err != nil {
+					t.Fatal(err)
+				}
+				_, ok := board["members"]
+				assert.True(t.Error, !ok)
+
+				var cols []Column
+				if err := json.Unmarshal(raw["columns"], &cols); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Fatal, len(cols), 4)
+				assert.Equal(t.Error, len(cols[0].Tasks), 0)
+				assert.Equal(t.Error, len(cols[3].Tasks), 0)
+				assert.Equal(t.Fatal, len(cols[1].Tasks), 2)
+				assert.Equal(t.Error, cols[1].Tasks[0].ID, "task1")
+				assert.Equal(t.Error, cols[1].Tasks[1].ID, "task2")
+				assert.Equal(t.Error,
+					cols[1].Tasks[0].Subtasks[0].Title, "Sub",
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			shareDecoder.Res = share
+			shareDecoder.Err = c.errDecode
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			taskRetriever.Res = c.tasks
+			taskRetriever.Err = c.errRetrieveTs
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodGet, "/public/board?token="+c.token, nil,
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
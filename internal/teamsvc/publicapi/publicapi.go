@@ -0,0 +1,4 @@
+// Package publicapi contains code for responding to HTTP requests made to the
+// public API routes, which serve the boards that admins shared to anyone with
+// their share link, without logging in.
+package publicapi
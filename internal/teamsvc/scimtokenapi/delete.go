@@ -0,0 +1,87 @@
+package scimtokenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DeleteResp defines the body of DELETE SCIM token responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the SCIM token route. It revokes the team's token, which disables SCIM
+// provisioning for the team.
+type DeleteHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the SCIM token route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can manage the SCIM token.",
+		)
+		return
+	}
+
+	// retrieve the team and clear the hash of its token
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if len(team.SCIMTokenHash) == 0 {
+		return
+	}
+	team.SCIMTokenHash = nil
+	if err = h.teamUpdater.Update(r.Context(), team); err != nil {
+		if msg, status := updateErr(err); msg != "" {
+			h.writeResp(w, status, msg)
+			return
+		}
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h DeleteHandler) writeResp(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(DeleteResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
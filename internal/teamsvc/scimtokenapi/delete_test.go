@@ -0,0 +1,114 @@
+//go:build utest
+
+package scimtokenapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that
+// it behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(teamRetriever, teamUpdater, log)
+	admin := cookie.Auth{IsAdmin: true, TeamID: "acme"}
+	enabled := teamtbl.Team{ID: "acme", SCIMTokenHash: []byte("hash")}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		team        teamtbl.Team
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false, TeamID: "acme"},
+			team:        enabled,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can manage the SCIM token.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:        "NoToken",
+			authDecoded: admin,
+			team:        teamtbl.Team{ID: "acme"},
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			team:        enabled,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			team:        enabled,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:        "OK",
+			authDecoded: admin,
+			team:        enabled,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, teamUpdater.Updated.ID, "acme")
+				assert.Equal(t.Error,
+					len(teamUpdater.Updated.SCIMTokenHash), 0,
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
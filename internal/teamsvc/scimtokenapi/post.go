@@ -0,0 +1,121 @@
+package scimtokenapi
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// tokenLen is the number of random bytes in a SCIM token.
+const tokenLen = 32
+
+// PostResp defines the body of POST SCIM token responses. The token is only
+// ever returned here, as only its hash is stored.
+type PostResp struct {
+	Token string `json:"token,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the SCIM token route. It generates a new token for the team, which replaces
+// the one it had if any.
+type PostHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles POST requests sent to the SCIM token route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, PostResp{
+			Error: "Only team admins can manage the SCIM token.",
+		})
+		return
+	}
+
+	// generate the token
+	b := make([]byte, tokenLen)
+	if _, err := rand.Read(b); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := sha256.Sum256([]byte(token))
+
+	// retrieve the team and store the hash of the token
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	team.SCIMTokenHash = hash[:]
+	if err = h.teamUpdater.Update(r.Context(), team); err != nil {
+		if msg, status := updateErr(err); msg != "" {
+			h.writeResp(w, status, PostResp{Error: msg})
+			return
+		}
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, PostResp{Token: token})
+}
+
+// writeResp writes the given status and response body.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
+
+// updateErr returns the error message and status to respond with for the
+// given error from updating the team, or an empty message if it was
+// unexpected.
+func updateErr(err error) (string, int) {
+	switch {
+	case errors.Is(err, db.ErrConflict):
+		return "Team was modified by someone else. Please refresh the " +
+			"page and try again.", http.StatusConflict
+	case errors.Is(err, db.ErrNoItem):
+		return "Team not found.", http.StatusNotFound
+	default:
+		return "", 0
+	}
+}
@@ -0,0 +1,119 @@
+//go:build utest
+
+package scimtokenapi
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(teamRetriever, teamUpdater, log)
+	admin := cookie.Auth{IsAdmin: true, TeamID: "acme"}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false, TeamID: "acme"},
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can manage the SCIM token.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "UpdateNotFound",
+			authDecoded: admin,
+			errUpdate:   db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:        "OK",
+			authDecoded: admin,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var got PostResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&got,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, len(got.Token), 43)
+				hash := sha256.Sum256([]byte(got.Token))
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.SCIMTokenHash, hash[:],
+				)
+				assert.Equal(t.Error, teamUpdater.Updated.ID, "acme")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = teamtbl.Team{
+				ID: "acme", SCIMTokenHash: []byte("old"),
+			}
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
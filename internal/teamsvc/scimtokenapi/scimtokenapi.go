@@ -0,0 +1,4 @@
+// Package scimtokenapi contains code for responding to HTTP requests made to
+// the team SCIM token API route. The token authorizes the team's identity
+// provider to provision and deprovision its members over SCIM.
+package scimtokenapi
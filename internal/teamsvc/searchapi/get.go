@@ -0,0 +1,172 @@
+package searchapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// maxResults is the maximum number of results returned in each group.
+const maxResults = 20
+
+// GetResp defines the body of GET search responses. Each group holds up to 20
+// results.
+type GetResp struct {
+	Error   string   `json:"error,omitempty"`
+	Boards  []Board  `json:"boards"`
+	Tasks   []Task   `json:"tasks"`
+	Members []Member `json:"members"`
+}
+
+// Board defines a board that matched the search in a GetResp.
+type Board struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Task defines a task that matched the search in a GetResp, along with the
+// board and the column it is in.
+type Task struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	BoardID   string `json:"boardID"`
+	BoardName string `json:"boardName"`
+	ColNo     int    `json:"colNo"`
+}
+
+// Member defines a member of the team that matched the search in a GetResp.
+type Member struct {
+	Username string `json:"username"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// search route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	tasksByTeam   db.Retriever[[]tasktbl.Task]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	tasksByTeam db.Retriever[[]tasktbl.Task],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		tasksByTeam:   tasksByTeam,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the search route. It responds with the
+// boards whose names or descriptions, the tasks whose titles or descriptions,
+// and the members whose usernames contain the q query parameter, ignoring
+// case. Only the boards the user can access and their tasks are searched, and
+// guests do not get members as they cannot see the team's members. Archived
+// tasks are not searched.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// read the search term
+	q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if q == "" {
+		h.writeResp(w, http.StatusBadRequest, GetResp{
+			Error: "Search term cannot be empty.",
+		})
+		return
+	}
+	matches := func(fields ...string) bool {
+		for _, f := range fields {
+			if strings.Contains(strings.ToLower(f), q) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// retrieve the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// search the boards the user can access
+	resp := GetResp{Boards: []Board{}, Tasks: []Task{}, Members: []Member{}}
+	boardNames := map[string]string{}
+	for _, b := range team.Boards {
+		if !b.IsVisibleTo(auth.Username, auth.IsAdmin, auth.IsGuest) {
+			continue
+		}
+		boardNames[b.ID] = b.Name
+		if matches(b.Name, b.Description) && len(resp.Boards) < maxResults {
+			resp.Boards = append(resp.Boards, Board{ID: b.ID, Name: b.Name})
+		}
+	}
+
+	// search the tasks of those boards, in the order of their columns
+	tasks, err := h.tasksByTeam.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	tasktbl.SortByRank(tasks)
+	for _, t := range tasks {
+		if len(resp.Tasks) == maxResults {
+			break
+		}
+		boardName, ok := boardNames[t.BoardID]
+		if !ok || t.ArchivedAt != 0 || !matches(t.Title, t.Description) {
+			continue
+		}
+		resp.Tasks = append(resp.Tasks, Task{
+			ID:        t.ID,
+			Title:     t.Title,
+			BoardID:   t.BoardID,
+			BoardName: boardName,
+			ColNo:     t.ColNo,
+		})
+	}
+
+	// search the members
+	if !auth.IsGuest {
+		members := append([]string(nil), team.Members...)
+		sort.Strings(members)
+		for _, m := range members {
+			if len(resp.Members) == maxResults {
+				break
+			}
+			if matches(m) {
+				resp.Members = append(resp.Members, Member{Username: m})
+			}
+		}
+	}
+
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
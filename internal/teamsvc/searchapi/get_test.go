@@ -0,0 +1,175 @@
+//go:build utest
+
+package searchapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	tasksByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(teamRetriever, tasksByTeam, log)
+
+	team := teamtbl.Team{
+		Members: []string{"rocket", "bob", "alice"},
+		Boards: []teamtbl.Board{
+			{ID: "board1", Name: "Rocket", Members: []string{"bob"}},
+			{
+				ID:          "board2",
+				Name:        "Launch",
+				Description: "Get the rocket off the ground.",
+				Members:     []string{"alice"},
+			},
+			{ID: "board3", Name: "Misc", Members: []string{"bob"}},
+		},
+	}
+	tasks := []tasktbl.Task{
+		{ID: "task1", BoardID: "board1", ColNo: 2, Title: "Paint rocket"},
+		{ID: "task2", BoardID: "board2", ColNo: 0, Title: "Fuel ROCKET"},
+		{
+			ID: "task3", BoardID: "board3", ColNo: 1, Title: "Tidy up",
+			Description: "Sweep the rocket hangar.",
+		},
+		{ID: "task4", BoardID: "board3", ColNo: 0, Title: "Other"},
+		{
+			ID: "task5", BoardID: "board1", ColNo: 3, Title: "Old rocket",
+			ArchivedAt: 1,
+		},
+	}
+
+	// wantResult returns an assert func that checks the IDs of the boards,
+	// tasks, and members in the response
+	wantResult := func(
+		boardIDs, taskIDs, usernames []string,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, r *http.Response, _ []any) {
+			var resp GetResp
+			err := json.NewDecoder(r.Body).Decode(&resp)
+			assert.Nil(t.Fatal, err)
+
+			gotBoards := []string{}
+			for _, b := range resp.Boards {
+				gotBoards = append(gotBoards, b.ID)
+			}
+			assert.AllEqual(t.Error, gotBoards, boardIDs)
+
+			gotTasks := []string{}
+			for _, task := range resp.Tasks {
+				gotTasks = append(gotTasks, task.ID)
+			}
+			assert.AllEqual(t.Error, gotTasks, taskIDs)
+
+			gotMembers := []string{}
+			for _, m := range resp.Members {
+				gotMembers = append(gotMembers, m.Username)
+			}
+			assert.AllEqual(t.Error, gotMembers, usernames)
+		}
+	}
+
+	for _, c := range []struct {
+		name       string
+		auth       cookie.Auth
+		q          string
+		errTeam    error
+		errTasks   error
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "EmptyTerm",
+			auth:       cookie.Auth{Username: "bob"},
+			q:          "  ",
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Search term cannot be empty."),
+		},
+		{
+			name:       "TeamNotFound",
+			auth:       cookie.Auth{Username: "bob"},
+			q:          "rocket",
+			errTeam:    db.ErrNoItem,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Team not found."),
+		},
+		{
+			name:       "ErrRetrieveTeam",
+			auth:       cookie.Auth{Username: "bob"},
+			q:          "rocket",
+			errTeam:    errors.New("retrieve team failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:       "ErrRetrieveTasks",
+			auth:       cookie.Auth{Username: "bob"},
+			q:          "rocket",
+			errTasks:   errors.New("retrieve tasks failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:       "OKMember",
+			auth:       cookie.Auth{Username: "bob"},
+			q:          "Rocket",
+			wantStatus: http.StatusOK,
+			assertFunc: wantResult(
+				[]string{"board1"},
+				[]string{"task1", "task3"},
+				[]string{"rocket"},
+			),
+		},
+		{
+			name:       "OKAdmin",
+			auth:       cookie.Auth{Username: "alice", IsAdmin: true},
+			q:          "rocket",
+			wantStatus: http.StatusOK,
+			assertFunc: wantResult(
+				[]string{"board1", "board2"},
+				[]string{"task1", "task2", "task3"},
+				[]string{"rocket"},
+			),
+		},
+		{
+			name:       "OKGuest",
+			auth:       cookie.Auth{Username: "alice", IsGuest: true},
+			q:          "rocket",
+			wantStatus: http.StatusOK,
+			assertFunc: wantResult(
+				[]string{"board2"}, []string{"task2"}, []string{},
+			),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = team
+			teamRetriever.Err = c.errTeam
+			tasksByTeam.Res = append([]tasktbl.Task(nil), tasks...)
+			tasksByTeam.Err = c.errTasks
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodGet, "/?q="+url.QueryEscape(c.q), nil,
+			)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package searchapi contains code for responding to HTTP requests made to the
+// search API route, which is used for finding the boards, tasks, and members
+// of a team in one call.
+package searchapi
@@ -0,0 +1,113 @@
+package shareapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// DeleteResp defines the body of DELETE board share responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the board share route. It unshares the board, which revokes its share
+// token.
+type DeleteHandler struct {
+	idValidator   validator.String
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	idValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		idValidator:   idValidator,
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the board share route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can share boards.",
+		)
+		return
+	}
+
+	// validate board ID
+	id := api.PathParam(r, "boardID")
+	if err := h.idValidator.Validate(id); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Board ID must be a UUID.")
+		return
+	}
+
+	// find the board in the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Board not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	i := findBoard(team, id)
+	if i == -1 {
+		h.writeResp(w, http.StatusNotFound, "Board not found.")
+		return
+	}
+
+	// unshare the board if it is shared
+	if team.Boards[i].ShareNonce == "" {
+		return
+	}
+	team = team.Clone()
+	team.Boards[i].ShareNonce = ""
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict,
+			"Board was modified by someone else. Please refresh the page "+
+				"and try again.",
+		)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Board not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h DeleteHandler) writeResp(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(DeleteResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
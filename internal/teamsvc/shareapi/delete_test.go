@@ -0,0 +1,141 @@
+//go:build utest
+
+package shareapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that
+// it behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	idValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(idValidator, teamRetriever, teamUpdater, log)
+
+	const boardID = "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1"}
+	team := teamtbl.Team{
+		ID: "team1", Boards: []teamtbl.Board{{ID: boardID, Name: "Board 1"}},
+	}
+	shared := team.Clone()
+	shared.Boards[0].ShareNonce = "nonce1"
+
+	for _, c := range []struct {
+		name          string
+		authDecoded   cookie.Auth
+		errValidateID error
+		team          teamtbl.Team
+		errRetrieve   error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{TeamID: "team1"},
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can share boards.",
+			),
+		},
+		{
+			name:          "InvalidID",
+			authDecoded:   admin,
+			errValidateID: validator.ErrWrongFormat,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:        "BoardNotFound",
+			authDecoded: admin,
+			team:        teamtbl.Team{ID: "team1"},
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "NotShared",
+			authDecoded: admin,
+			team:        team,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			team:        shared,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Board was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			team:        shared,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:        "Unshared",
+			authDecoded: admin,
+			team:        shared,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					teamUpdater.Updated.Boards[0].ShareNonce, "",
+				)
+				assert.Equal(t.Error, shared.Boards[0].ShareNonce, "nonce1")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idValidator.Err = c.errValidateID
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodDelete, "/", nil),
+				map[string]string{"boardID": boardID},
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package shareapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostResp defines the body of POST board share responses.
+type PostResp struct {
+	// Token is the share token that grants read-only access to the board at
+	// GET /public/board?token=...
+	Token string `json:"token,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the board share route. It shares the board if it is not already shared, and
+// returns its share token. The token stays the same until the board is
+// unshared so that the links already handed out keep working.
+type PostHandler struct {
+	idValidator   validator.String
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	shareEncoder  cookie.StringEncoder[cookie.Share]
+	log           log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	idValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	shareEncoder cookie.StringEncoder[cookie.Share],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		idValidator:   idValidator,
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		shareEncoder:  shareEncoder,
+		log:           log,
+	}
+}
+
+// Handle handles POST requests sent to the board share route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, PostResp{
+			Error: "Only team admins can share boards.",
+		})
+		return
+	}
+
+	// validate board ID
+	id := api.PathParam(r, "boardID")
+	if err := h.idValidator.Validate(id); err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "Board ID must be a UUID.",
+		})
+		return
+	}
+
+	// find the board in the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Board not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	i := findBoard(team, id)
+	if i == -1 {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Board not found.",
+		})
+		return
+	}
+
+	// share the board unless it already is
+	nonce := team.Boards[i].ShareNonce
+	if nonce == "" {
+		nonce = uuid.NewString()
+		team = team.Clone()
+		team.Boards[i].ShareNonce = nonce
+		if err = h.teamUpdater.Update(
+			r.Context(), team,
+		); errors.Is(err, db.ErrConflict) {
+			h.writeResp(w, http.StatusConflict, PostResp{
+				Error: "Board was modified by someone else. Please refresh " +
+					"the page and try again.",
+			})
+			return
+		} else if errors.Is(err, db.ErrNoItem) {
+			h.writeResp(w, http.StatusNotFound, PostResp{
+				Error: "Board not found.",
+			})
+			return
+		} else if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+	}
+
+	// write the share token
+	token, err := h.shareEncoder.Encode(cookie.NewShare(team.ID, id, nonce))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	h.writeResp(w, http.StatusOK, PostResp{Token: token})
+}
+
+// writeResp writes the given status and response body.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
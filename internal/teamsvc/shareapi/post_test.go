@@ -0,0 +1,155 @@
+//go:build utest
+
+package shareapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	idValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	shareEncoder := &cookie.FakeStringEncoder[cookie.Share]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		idValidator, teamRetriever, teamUpdater, shareEncoder, log,
+	)
+
+	const boardID = "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+	admin := cookie.Auth{IsAdmin: true, TeamID: "team1"}
+	team := teamtbl.Team{
+		ID: "team1", Boards: []teamtbl.Board{{ID: boardID, Name: "Board 1"}},
+	}
+	shared := team.Clone()
+	shared.Boards[0].ShareNonce = "nonce1"
+
+	for _, c := range []struct {
+		name          string
+		authDecoded   cookie.Auth
+		errValidateID error
+		team          teamtbl.Team
+		errRetrieve   error
+		errUpdate     error
+		errEncode     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{TeamID: "team1"},
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can share boards.",
+			),
+		},
+		{
+			name:          "InvalidID",
+			authDecoded:   admin,
+			errValidateID: validator.ErrWrongFormat,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: admin,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: admin,
+			errRetrieve: errors.New("retrieve team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:        "BoardNotFound",
+			authDecoded: admin,
+			team:        teamtbl.Team{ID: "team1"},
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: admin,
+			team:        team,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Board was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: admin,
+			team:        team,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:        "ErrEncode",
+			authDecoded: admin,
+			team:        team,
+			errEncode:   errors.New("encode share failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("encode share failed"),
+		},
+		{
+			name:        "Shared",
+			authDecoded: admin,
+			team:        team,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.OnRespBody(PostResp{Token: "token1"})(t, resp, nil)
+				nonce := teamUpdater.Updated.Boards[0].ShareNonce
+				assert.True(t.Error, nonce != "")
+				assert.Equal(t.Error, team.Boards[0].ShareNonce, "")
+			},
+		},
+		{
+			name:        "AlreadyShared",
+			authDecoded: admin,
+			team:        shared,
+			errUpdate:   errors.New("update team failed"),
+			wantStatus:  http.StatusOK,
+			assertFunc:  assert.OnRespBody(PostResp{Token: "token1"}),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idValidator.Err = c.errValidateID
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			shareEncoder.Res = "token1"
+			shareEncoder.Err = c.errEncode
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodPost, "/", nil),
+				map[string]string{"boardID": boardID},
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Package shareapi contains code for responding to HTTP requests made to the
+// board share API route, which admins use to share a board with people
+// outside the team through a read-only link.
+package shareapi
+
+import "github.com/kxplxn/goteam/pkg/db/teamtbl"
+
+// findBoard returns the index of the board with the given ID in the team's
+// boards, or -1 if the team does not have it.
+func findBoard(team teamtbl.Team, id string) int {
+	for i, b := range team.Boards {
+		if b.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,73 @@
+package slackapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET Slack integration responses.
+type GetResp struct {
+	Error string `json:"error,omitempty"`
+	teamtbl.Slack
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// Slack integration route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the Slack integration route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, GetResp{
+			Error: "Only team admins can view integrations.",
+		})
+		return
+	}
+
+	// retrieve team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "Team not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, GetResp{Slack: team.Slack})
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,115 @@
+package slackapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PutReq defines the body of PUT Slack integration requests. An empty webhook
+// URL disables the integration.
+type PutReq teamtbl.Slack
+
+// PutResp defines the body of PUT Slack integration responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can handle PUT requests sent to the
+// Slack integration route.
+type PutHandler struct {
+	urlValidator  validator.String
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	urlValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		urlValidator:  urlValidator,
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PUT requests sent to the Slack integration route.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can edit integrations.",
+		)
+		return
+	}
+
+	// decode and validate the settings
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	if req.WebhookURL != "" {
+		if err := h.urlValidator.Validate(req.WebhookURL); err != nil {
+			var msg string
+			if errors.Is(err, validator.ErrTooLong) {
+				msg = "Webhook URL cannot be longer than 500 characters."
+			} else {
+				msg = "Webhook URL must be a Slack incoming webhook URL."
+			}
+			h.writeResp(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	// retrieve the team and update its settings
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	team.Slack = teamtbl.Slack(req)
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict,
+			"Team was modified by someone else. Please refresh the page "+
+				"and try again.",
+		)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h PutHandler) writeResp(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PutResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
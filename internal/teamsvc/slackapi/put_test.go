@@ -0,0 +1,158 @@
+//go:build utest
+
+package slackapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	urlValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(
+		urlValidator, teamRetriever, teamUpdater, log,
+	)
+
+	const body = `{"webhookURL": "https://hooks.slack.com/x"}`
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		body        string
+		errValidate error
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit integrations.",
+			),
+		},
+		{
+			name:        "InvalidBody",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        "{",
+			errValidate: nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:        "InvalidURL",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: validator.ErrWrongFormat,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Webhook URL must be a Slack incoming webhook URL.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: db.ErrNoItem,
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: errors.New("retrieve failed"),
+			errUpdate:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "Disable",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        `{"webhookURL": ""}`,
+			errValidate: validator.ErrEmpty,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "OK",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errValidate: nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			urlValidator.Err = c.errValidate
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
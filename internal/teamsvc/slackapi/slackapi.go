@@ -0,0 +1,3 @@
+// Package slackapi contains code for responding to HTTP requests made to the
+// team Slack integration API route.
+package slackapi
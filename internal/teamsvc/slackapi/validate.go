@@ -0,0 +1,34 @@
+package slackapi
+
+import (
+	"net/url"
+
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// maxWebhookURLLen is the maximum length of a Slack incoming webhook URL.
+const maxWebhookURLLen = 500
+
+// WebhookURLValidator can be used to validate a Slack incoming webhook URL.
+type WebhookURLValidator struct{ validator.Func[string] }
+
+// NewWebhookURLValidator creates and returns a new WebhookURLValidator.
+func NewWebhookURLValidator() WebhookURLValidator {
+	return WebhookURLValidator{validator.All(
+		validator.NotEmpty(),
+		validator.MaxLen(maxWebhookURLLen),
+		slackHost,
+	)}
+}
+
+// slackHost is the rule that only allows HTTPS URLs on Slack's webhook host so
+// that the integration cannot be used to make the server send requests
+// elsewhere.
+func slackHost(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host != "hooks.slack.com" ||
+		u.User != nil {
+		return validator.ErrWrongFormat
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+//go:build utest
+
+package slackapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+func TestWebhookURLValidator(t *testing.T) {
+	sut := NewWebhookURLValidator()
+
+	for _, c := range []struct {
+		name       string
+		webhookURL string
+		wantErr    error
+	}{
+		{
+			name:       "Empty",
+			webhookURL: "",
+			wantErr:    validator.ErrEmpty,
+		},
+		{
+			name: "TooLong",
+			webhookURL: "https://hooks.slack.com/services/" +
+				strings.Repeat("a", 500),
+			wantErr: validator.ErrTooLong,
+		},
+		{
+			name:       "NotHTTPS",
+			webhookURL: "http://hooks.slack.com/services/T0/B0/X",
+			wantErr:    validator.ErrWrongFormat,
+		},
+		{
+			name:       "NotSlack",
+			webhookURL: "https://example.com/services/T0/B0/X",
+			wantErr:    validator.ErrWrongFormat,
+		},
+		{
+			name:       "UserInfo",
+			webhookURL: "https://u@hooks.slack.com/services/T0/B0/X",
+			wantErr:    validator.ErrWrongFormat,
+		},
+		{
+			name:       "OK",
+			webhookURL: "https://hooks.slack.com/services/T0/B0/X",
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut.Validate(c.webhookURL)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package sprintapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the sprint route.
+type DeleteHandler struct {
+	sprintDeleter db.DeleterDualKey
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	sprintDeleter db.DeleterDualKey,
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		sprintDeleter: sprintDeleter,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the sprint route. The tasks assigned
+// to the sprint keep its ID and are shown as unassigned by the clients, which
+// only know of the sprints that exist.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// validate ID
+	id := r.URL.Query().Get("id")
+	if err := validator.ID(id); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// delete the sprint from the team's sprints
+	if err := h.sprintDeleter.Delete(
+		r.Context(), auth.TeamID, id,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if errors.Is(err, db.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
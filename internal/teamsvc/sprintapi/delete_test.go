@@ -0,0 +1,95 @@
+//go:build utest
+
+package sprintapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	sprintDeleter := &db.FakeDeleterDualKey{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(sprintDeleter, log)
+
+	for _, c := range []struct {
+		name        string
+		sprintID    string
+		authDecoded cookie.Auth
+		errDelete   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			sprintID:    "",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			errDelete:   nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "InvalidID",
+			sprintID:    "sprint1",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errDelete:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "NotFound",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errDelete:   db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "Conflict",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errDelete:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrDelete",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errDelete:   errors.New("delete failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("delete failed"),
+		},
+		{
+			name:        "OK",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errDelete:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sprintDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete, "/?id="+c.sprintID, nil,
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package sprintapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET sprint responses.
+type GetResp []teamtbl.Sprint
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// sprint route.
+type GetHandler struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	teamRetriever db.Retriever[teamtbl.Team],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		teamRetriever: teamRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the sprint route. It responds with the
+// sprints on the boards the user can see, ordered by start date. The sprints
+// can be filtered by board with the boardID query parameter.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// retrieve team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// determine the boards the user can see
+	visible := map[string]bool{}
+	for _, b := range team.Boards {
+		if b.IsVisibleTo(auth.Username, auth.IsAdmin, auth.IsGuest) {
+			visible[b.ID] = true
+		}
+	}
+
+	// list the sprints on those boards, leaving out the ones on deleted
+	// boards and on boards other than the one filtered by if present
+	boardID := r.URL.Query().Get("boardID")
+	resp := GetResp{}
+	for _, s := range team.Sprints {
+		if visible[s.BoardID] && (boardID == "" || s.BoardID == boardID) {
+			resp = append(resp, s)
+		}
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].StartDate < resp[j].StartDate
+	})
+
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,125 @@
+//go:build utest
+
+package sprintapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(teamRetriever, log)
+
+	team := teamtbl.Team{
+		Boards: []teamtbl.Board{
+			{ID: "board1", Members: []string{"bob"}},
+			{ID: "board2", Members: []string{"alice"}},
+		},
+		Sprints: []teamtbl.Sprint{
+			{ID: "sprint1", BoardID: "board1", StartDate: "2024-01-15"},
+			{ID: "sprint2", BoardID: "board2", StartDate: "2024-01-01"},
+			{ID: "sprint3", BoardID: "board1", StartDate: "2024-01-01"},
+			{ID: "sprint4", BoardID: "deleted", StartDate: "2024-01-01"},
+		},
+	}
+	wantSprints := func(ids ...string) func(
+		*testing.T, *http.Response, []any,
+	) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var got GetResp
+			err := json.NewDecoder(resp.Body).Decode(&got)
+			assert.Nil(t.Fatal, err)
+			gotIDs := make([]string, len(got))
+			for i, s := range got {
+				gotIDs[i] = s.ID
+			}
+			assert.AllEqual(t.Error, gotIDs, ids)
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		boardID     string
+		authDecoded cookie.Auth
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "TeamNotFound",
+			boardID:     "",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrRetrieve",
+			boardID:     "",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "Admin",
+			boardID:     "",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  wantSprints("sprint2", "sprint3", "sprint1"),
+		},
+		{
+			name:        "AdminByBoard",
+			boardID:     "board1",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  wantSprints("sprint3", "sprint1"),
+		},
+		{
+			name:        "Member",
+			boardID:     "",
+			authDecoded: cookie.Auth{Username: "alice"},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  wantSprints("sprint2"),
+		},
+		{
+			name:        "MemberOtherBoard",
+			boardID:     "board1",
+			authDecoded: cookie.Auth{Username: "alice"},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  wantSprints(),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = team
+			teamRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodGet, "/?boardID="+c.boardID, nil,
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sprintapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PatchReq defines the body of PATCH sprint requests.
+type PatchReq teamtbl.Sprint
+
+// PatchResp defines the body of PATCH sprint responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
+// the sprint route.
+type PatchHandler struct {
+	validateSprint validator.Func[teamtbl.Sprint]
+	sprintUpdater  db.UpdaterDualKey[teamtbl.Sprint]
+	log            log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	validateSprint validator.Func[teamtbl.Sprint],
+	sprintUpdater db.UpdaterDualKey[teamtbl.Sprint],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		validateSprint: validateSprint,
+		sprintUpdater:  sprintUpdater,
+		log:            log,
+	}
+}
+
+// Handle handles PATCH requests sent to the sprint route. The sprint is
+// replaced as a whole, so a sprint can be moved to another board by sending
+// that board's ID.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can edit sprints.",
+		)
+		return
+	}
+
+	// decode and validate the sprint
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	if err := validator.Collect(
+		validator.Field("id", req.ID, validator.ID),
+		h.validateSprint(teamtbl.Sprint(req)),
+	); err != nil {
+		msg, ok := errMsg(err)
+		if !ok {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		h.writeResp(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// update the sprint in the team's sprints
+	if err := h.sprintUpdater.Update(
+		r.Context(), auth.TeamID, teamtbl.Sprint(req),
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Sprint or board not found.")
+		return
+	} else if errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict,
+			"Team was modified by someone else. Please refresh the page "+
+				"and try again.",
+		)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h PatchHandler) writeResp(
+	w http.ResponseWriter, status int, msg string,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,136 @@
+//go:build utest
+
+package sprintapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	validateSprint := &validator.FakeFunc[teamtbl.Sprint]{}
+	sprintUpdater := &db.FakeUpdaterDualKey[teamtbl.Sprint]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		validateSprint.Func, sprintUpdater, log,
+	)
+
+	for _, c := range []struct {
+		name        string
+		sprintID    string
+		authDecoded cookie.Auth
+		errValidate error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			sprintID:    "",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			errValidate: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit sprints.",
+			),
+		},
+		{
+			name:        "IDEmpty",
+			sprintID:    "",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Sprint ID cannot be empty."),
+		},
+		{
+			name:        "IDNotUUID",
+			sprintID:    "sprint1",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Sprint ID must be a UUID."),
+		},
+		{
+			name:        "DateWrongFormat",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.Errs{
+				{Path: "startDate", Err: validator.ErrWrongFormat},
+			},
+			errUpdate:  nil,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Sprint dates must be in the YYYY-MM-DD format.",
+			),
+		},
+		{
+			name:        "NotFound",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Sprint or board not found."),
+		},
+		{
+			name:        "Conflict",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "OK",
+			sprintID:    "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			validateSprint.Err = c.errValidate
+			sprintUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(
+				`{"id": "`+c.sprintID+`", "name": "Sprint 1"}`,
+			))
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sprintapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST sprint requests.
+type PostReq struct {
+	BoardID   string `json:"boardID"`
+	Name      string `json:"name"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// PostResp defines the body of POST sprint responses.
+type PostResp struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the sprint route.
+type PostHandler struct {
+	validateSprint validator.Func[teamtbl.Sprint]
+	sprintInserter db.InserterDualKey[teamtbl.Sprint]
+	log            log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	validateSprint validator.Func[teamtbl.Sprint],
+	sprintInserter db.InserterDualKey[teamtbl.Sprint],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		validateSprint: validateSprint,
+		sprintInserter: sprintInserter,
+		log:            log,
+	}
+}
+
+// Handle handles POST requests sent to the sprint route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, PostResp{
+			Error: "Only team admins can create sprints.",
+		})
+		return
+	}
+
+	// decode and validate the sprint
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "Invalid request body.",
+		})
+		return
+	}
+	sprint := teamtbl.Sprint{
+		BoardID:   req.BoardID,
+		Name:      req.Name,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+	if err := h.validateSprint(sprint); err != nil {
+		msg, ok := errMsg(err)
+		if !ok {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		h.writeResp(w, http.StatusBadRequest, PostResp{Error: msg})
+		return
+	}
+
+	// insert the sprint into the team's sprints - retry up to 3 times for the
+	// unlikely event that the generated UUID is a duplicate
+	var err error
+	for i := 0; i < 3; i++ {
+		sprint.ID = uuid.NewString()
+		if err = h.sprintInserter.Insert(
+			r.Context(), auth.TeamID, sprint,
+		); !errors.Is(err, db.ErrDupKey) {
+			break
+		}
+	}
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Board not found.",
+		})
+		return
+	} else if errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict, PostResp{
+			Error: "Team was modified by someone else. Please refresh the " +
+				"page and try again.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusCreated, PostResp{ID: sprint.ID})
+}
+
+// writeResp writes the given status and response.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
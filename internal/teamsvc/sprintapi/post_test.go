@@ -0,0 +1,127 @@
+//go:build utest
+
+package sprintapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	validateSprint := &validator.FakeFunc[teamtbl.Sprint]{}
+	sprintInserter := &db.FakeInserterDualKey[teamtbl.Sprint]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		validateSprint.Func, sprintInserter, log,
+	)
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		errValidate error
+		errInsert   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			errValidate: nil,
+			errInsert:   nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can create sprints.",
+			),
+		},
+		{
+			name:        "NameEmpty",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.Errs{
+				{Path: "name", Err: validator.ErrEmpty},
+			},
+			errInsert:  nil,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Sprint name cannot be empty."),
+		},
+		{
+			name:        "EndsBeforeStart",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: validator.Errs{
+				{Path: "endDate", Err: errEndsBeforeStart},
+			},
+			errInsert:  nil,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Sprint cannot end before it starts.",
+			),
+		},
+		{
+			name:        "BoardNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errInsert:   db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errInsert:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrInsert",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errInsert:   errors.New("insert failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:        "Created",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			errValidate: nil,
+			errInsert:   nil,
+			wantStatus:  http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var got PostResp
+				err := json.NewDecoder(resp.Body).Decode(&got)
+				assert.Nil(t.Fatal, err)
+				assert.Nil(t.Error, validator.ID(got.ID))
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			validateSprint.Err = c.errValidate
+			sprintInserter.Err = c.errInsert
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(
+				`{"boardID": "board1", "name": "Sprint 1"}`,
+			))
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package sprintapi contains code for responding to HTTP requests made to the
+// team sprint API route.
+package sprintapi
@@ -0,0 +1,61 @@
+package sprintapi
+
+import (
+	"errors"
+
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// ValidateSprint validates the board, name and dates of a given sprint. The
+// error it returns is validator.Errs with the paths to the fields that failed
+// validation.
+func ValidateSprint(s teamtbl.Sprint) error {
+	if err := validator.Collect(
+		validator.Field("boardID", s.BoardID, validator.ID),
+		validator.Field("name", s.Name, validator.SprintName),
+		validator.Field("startDate", s.StartDate, validator.SprintDate),
+		validator.Field("endDate", s.EndDate, validator.SprintDate),
+	); err != nil {
+		return err
+	}
+
+	// dates in the YYYY-MM-DD format sort the same as the days they are on
+	if s.EndDate < s.StartDate {
+		return validator.Errs{{Path: "endDate", Err: errEndsBeforeStart}}
+	}
+	return nil
+}
+
+// errEndsBeforeStart is returned when a sprint's end date is before its start
+// date.
+var errEndsBeforeStart = errors.New("ends before start")
+
+// errMsg returns the message to respond with for the first field of a sprint
+// that failed validation, and false if err is not a validation error.
+func errMsg(err error) (string, bool) {
+	fe, ok := validator.First(err)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case fe.Path == "id" && errors.Is(fe, validator.ErrEmpty):
+		return "Sprint ID cannot be empty.", true
+	case fe.Path == "id":
+		return "Sprint ID must be a UUID.", true
+	case fe.Path == "boardID" && errors.Is(fe, validator.ErrEmpty):
+		return "Board ID cannot be empty.", true
+	case fe.Path == "boardID":
+		return "Board ID must be a UUID.", true
+	case fe.Path == "name" && errors.Is(fe, validator.ErrEmpty):
+		return "Sprint name cannot be empty.", true
+	case fe.Path == "name":
+		return "Sprint name cannot be longer than 35 characters.", true
+	case errors.Is(fe, errEndsBeforeStart):
+		return "Sprint cannot end before it starts.", true
+	case fe.Path == "startDate" || fe.Path == "endDate":
+		return "Sprint dates must be in the YYYY-MM-DD format.", true
+	default:
+		return "", false
+	}
+}
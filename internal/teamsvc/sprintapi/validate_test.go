@@ -0,0 +1,85 @@
+//go:build utest
+
+package sprintapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestValidateSprint tests the ValidateSprint func to assert that it returns
+// the error of the first field that failed validation.
+func TestValidateSprint(t *testing.T) {
+	valid := teamtbl.Sprint{
+		BoardID:   "7a7c5e06-8b1c-4a4f-9e33-6e1f4c3e0a21",
+		Name:      "Sprint 1",
+		StartDate: "2024-01-01",
+		EndDate:   "2024-01-14",
+	}
+
+	for _, c := range []struct {
+		name     string
+		edit     func(*teamtbl.Sprint)
+		wantPath string
+		wantErr  error
+	}{
+		{
+			name:     "BoardIDEmpty",
+			edit:     func(s *teamtbl.Sprint) { s.BoardID = "" },
+			wantPath: "boardID",
+			wantErr:  validator.ErrEmpty,
+		},
+		{
+			name: "NameTooLong",
+			edit: func(s *teamtbl.Sprint) {
+				s.Name = strings.Repeat("a", validator.MaxSprintNameLen+1)
+			},
+			wantPath: "name",
+			wantErr:  validator.ErrTooLong,
+		},
+		{
+			name:     "StartDateWrongFormat",
+			edit:     func(s *teamtbl.Sprint) { s.StartDate = "2024-1-1" },
+			wantPath: "startDate",
+			wantErr:  validator.ErrWrongFormat,
+		},
+		{
+			name:     "EndsBeforeStart",
+			edit:     func(s *teamtbl.Sprint) { s.EndDate = "2023-12-31" },
+			wantPath: "endDate",
+			wantErr:  errEndsBeforeStart,
+		},
+		{
+			name:     "OneDay",
+			edit:     func(s *teamtbl.Sprint) { s.EndDate = s.StartDate },
+			wantPath: "",
+			wantErr:  nil,
+		},
+		{
+			name:     "OK",
+			edit:     func(*teamtbl.Sprint) {},
+			wantPath: "",
+			wantErr:  nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := valid
+			c.edit(&s)
+
+			err := ValidateSprint(s)
+
+			if c.wantErr == nil {
+				assert.Nil(t.Fatal, err)
+				return
+			}
+			fe, ok := validator.First(err)
+			assert.True(t.Fatal, ok)
+			assert.Equal(t.Error, fe.Path, c.wantPath)
+			assert.ErrIs(t.Error, fe, c.wantErr)
+		})
+	}
+}
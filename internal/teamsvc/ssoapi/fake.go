@@ -0,0 +1,16 @@
+//go:build utest
+
+package ssoapi
+
+import "github.com/kxplxn/goteam/pkg/saml"
+
+// fakeMetadataParser is a test fake for MetadataParser.
+type fakeMetadataParser struct {
+	idp saml.IdP
+	err error
+}
+
+// ParseMetadata returns fakeMetadataParser.idp and fakeMetadataParser.err.
+func (f *fakeMetadataParser) ParseMetadata([]byte) (saml.IdP, error) {
+	return f.idp, f.err
+}
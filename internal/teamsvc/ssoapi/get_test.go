@@ -0,0 +1,88 @@
+//go:build utest
+
+package ssoapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(teamRetriever, log)
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		team        teamtbl.Team
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			team:        teamtbl.Team{},
+			errRetrieve: nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can view single sign-on settings.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			team:        teamtbl.Team{},
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			team:        teamtbl.Team{},
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "OK",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			team: teamtbl.Team{SAML: teamtbl.SAML{
+				IdPEntityID: "https://idp.example.com",
+				SSOURL:      "https://idp.example.com/sso",
+				Certs:       [][]byte{[]byte("cert")},
+			}},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: assert.OnRespBody(GetResp{SAML: teamtbl.SAML{
+				IdPEntityID: "https://idp.example.com",
+				SSOURL:      "https://idp.example.com/sso",
+			}}),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
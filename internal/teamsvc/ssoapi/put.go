@@ -0,0 +1,144 @@
+package ssoapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// The maximum lengths of the settings.
+const (
+	maxMetadataLen          = 100000
+	maxUsernameAttributeLen = 200
+)
+
+// PutReq defines the body of PUT SAML single sign-on requests. Empty metadata
+// disables single sign-on.
+type PutReq struct {
+	// Metadata is the XML metadata of the team's identity provider.
+	Metadata string `json:"metadata"`
+
+	// UsernameAttribute is the name of the attribute that holds the username
+	// of the user. The name ID of the user is used if it is empty.
+	UsernameAttribute string `json:"usernameAttribute"`
+}
+
+// PutResp defines the body of PUT SAML single sign-on responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can handle PUT requests sent to the
+// SAML single sign-on route.
+type PutHandler struct {
+	metadataParser MetadataParser
+	teamRetriever  db.Retriever[teamtbl.Team]
+	teamUpdater    db.Updater[teamtbl.Team]
+	log            log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	metadataParser MetadataParser,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		metadataParser: metadataParser,
+		teamRetriever:  teamRetriever,
+		teamUpdater:    teamUpdater,
+		log:            log,
+	}
+}
+
+// Handle handles PUT requests sent to the SAML single sign-on route.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden,
+			"Only team admins can edit single sign-on settings.",
+		)
+		return
+	}
+
+	// decode and validate the settings, reading the identity provider from
+	// its metadata
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	var saml teamtbl.SAML
+	if req.Metadata != "" {
+		if len(req.Metadata) > maxMetadataLen {
+			h.writeResp(w, http.StatusBadRequest,
+				"Metadata cannot be longer than 100000 characters.",
+			)
+			return
+		}
+		if len(req.UsernameAttribute) > maxUsernameAttributeLen {
+			h.writeResp(w, http.StatusBadRequest,
+				"Username attribute cannot be longer than 200 characters.",
+			)
+			return
+		}
+		idp, err := h.metadataParser.ParseMetadata([]byte(req.Metadata))
+		if err != nil {
+			h.writeResp(w, http.StatusBadRequest,
+				"Metadata must be the SAML metadata of an identity provider.",
+			)
+			return
+		}
+		saml = teamtbl.SAML{
+			IdPEntityID:       idp.EntityID,
+			SSOURL:            idp.SSOURL,
+			Certs:             idp.Certs,
+			UsernameAttribute: req.UsernameAttribute,
+		}
+	}
+
+	// retrieve the team and update its settings
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	team.SAML = saml
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		h.writeResp(w, http.StatusConflict,
+			"Team was modified by someone else. Please refresh the page "+
+				"and try again.",
+		)
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, "Team not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeResp writes the given status and error message.
+func (h PutHandler) writeResp(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PutResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
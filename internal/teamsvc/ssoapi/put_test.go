@@ -0,0 +1,200 @@
+//go:build utest
+
+package ssoapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/saml"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	metadataParser := &fakeMetadataParser{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(
+		metadataParser, teamRetriever, teamUpdater, log,
+	)
+
+	const body = `{"metadata": "<md/>", "usernameAttribute": "uid"}`
+	idp := saml.IdP{
+		EntityID: "https://idp.example.com",
+		SSOURL:   "https://idp.example.com/sso",
+		Certs:    [][]byte{[]byte("cert")},
+	}
+
+	for _, c := range []struct {
+		name        string
+		authDecoded cookie.Auth
+		body        string
+		errParse    error
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotAdmin",
+			authDecoded: cookie.Auth{IsAdmin: false},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit single sign-on settings.",
+			),
+		},
+		{
+			name:        "InvalidBody",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        "{",
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:        "MetadataTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"metadata": "` + strings.Repeat("a", 100001) +
+				`"}`,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Metadata cannot be longer than 100000 characters.",
+			),
+		},
+		{
+			name:        "UsernameAttributeTooLong",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body: `{"metadata": "<md/>", "usernameAttribute": "` +
+				strings.Repeat("a", 201) + `"}`,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Username attribute cannot be longer than 200 characters.",
+			),
+		},
+		{
+			name:        "InvalidMetadata",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    errors.New("saml: metadata has no identity provider"),
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Metadata must be the SAML metadata of an identity provider.",
+			),
+		},
+		{
+			name:        "TeamNotFound",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: db.ErrNoItem,
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Team not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: errors.New("retrieve failed"),
+			errUpdate:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "Conflict",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   db.ErrConflict,
+			wantStatus:  http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please refresh the " +
+					"page and try again.",
+			),
+		},
+		{
+			name:        "ErrUpdate",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "Disable",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        `{"metadata": ""}`,
+			errParse:    errors.New("saml: EOF"),
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.True(t.Error, !teamUpdater.Updated.SAML.IsEnabled())
+			},
+		},
+		{
+			name:        "OK",
+			authDecoded: cookie.Auth{IsAdmin: true},
+			body:        body,
+			errParse:    nil,
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				got := teamUpdater.Updated.SAML
+				assert.Equal(t.Error, got.IdPEntityID, idp.EntityID)
+				assert.Equal(t.Error, got.SSOURL, idp.SSOURL)
+				assert.Equal(t.Error, len(got.Certs), 1)
+				assert.Equal(t.Error, got.UsernameAttribute, "uid")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			metadataParser.idp = idp
+			metadataParser.err = c.errParse
+			teamRetriever.Res = teamtbl.Team{
+				SAML: teamtbl.SAML{IdPEntityID: "https://old.example.com"},
+			}
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Package ssoapi contains code for responding to HTTP requests made to the
+// team SAML single sign-on settings API route.
+package ssoapi
+
+import "github.com/kxplxn/goteam/pkg/saml"
+
+// MetadataParser describes a type that can be used to parse the metadata of a
+// team's identity provider.
+type MetadataParser interface {
+	ParseMetadata([]byte) (saml.IdP, error)
+}
+
+// Metadata is a MetadataParser that parses SAML 2.0 metadata.
+type Metadata struct{}
+
+// ParseMetadata parses the given identity provider metadata.
+func (Metadata) ParseMetadata(data []byte) (saml.IdP, error) {
+	return saml.ParseMetadata(data)
+}
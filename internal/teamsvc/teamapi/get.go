@@ -4,64 +4,84 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sort"
 
 	"github.com/google/uuid"
 
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
 )
 
 // GetResp defines the body of GET team responses.
-type GetResp teamtbl.Team
+type GetResp struct {
+	ID      string     `json:"id"`
+	Members []string   `json:"members"`
+	Boards  []GetBoard `json:"boards"`
+	Version int        `json:"version"`
+}
+
+// GetBoard defines a board in the body of GET team responses. Boards the user
+// has starred are listed first.
+type GetBoard struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Members    []string `json:"members"`
+	IsFavorite bool     `json:"isFavorite"`
+
+	// Description is the markdown description of the board.
+	Description string `json:"description,omitempty"`
+
+	// Columns holds the settings of the board's columns, indexed by column
+	// number. Columns past the end of the list use the default settings.
+	Columns []teamtbl.Column `json:"columns,omitempty"`
+
+	// Visibility is either team or restricted. Boards without one are
+	// restricted to their members.
+	Visibility string `json:"visibility,omitempty"`
+}
 
 // GetHandler is an api.MethodHandler that can handle GET requests sent to the
 // team route.
 type GetHandler struct {
-	authDecoder   cookie.Decoder[cookie.Auth]
 	teamRetriever db.Retriever[teamtbl.Team]
 	teamInserter  db.Inserter[teamtbl.Team]
 	teamUpdater   db.Updater[teamtbl.Team]
+	userRetriever db.Retriever[usertbl.User]
 	inviteEncoder cookie.Encoder[cookie.Invite]
+	clock         clock.Clock
 	log           log.Errorer
 }
 
 // NewGetHandler creates and returns a new GetHandler.
 func NewGetHandler(
-	authDecoder cookie.Decoder[cookie.Auth],
 	teamRetriever db.Retriever[teamtbl.Team],
 	teamInserter db.Inserter[teamtbl.Team],
 	teamUpdater db.Updater[teamtbl.Team],
+	userRetriever db.Retriever[usertbl.User],
 	inviteEncoder cookie.Encoder[cookie.Invite],
+	clock clock.Clock,
 	log log.Errorer,
 ) GetHandler {
 	return GetHandler{
-		authDecoder:   authDecoder,
 		teamRetriever: teamRetriever,
 		teamInserter:  teamInserter,
 		teamUpdater:   teamUpdater,
+		userRetriever: userRetriever,
 		inviteEncoder: inviteEncoder,
+		clock:         clock,
 		log:           log,
 	}
 }
 
 // Handle handles GET requests sent to the team route.
-func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
-	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
-	if err == http.ErrNoCookie {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
-	// decode auth token
-	auth, err := h.authDecoder.Decode(*ckAuth)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
 	// retrieve team
 	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
 	var status int
@@ -94,7 +114,7 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 			); errors.Is(err, db.ErrDupKey) {
 				team.Boards[0].ID = uuid.NewString()
 			} else if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(api.ErrStatus(err))
 				h.log.Error(err)
 				return
 			} else {
@@ -105,7 +125,7 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		// write 201 to indicate creation of the new team
 		status = http.StatusCreated
 	} else if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	} else {
@@ -125,41 +145,117 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 			if !isTeamMember {
 				team.Members = append(team.Members, auth.Username)
 				if err = h.teamUpdater.Update(r.Context(), team); err != nil {
-					w.WriteHeader(http.StatusInternalServerError)
+					w.WriteHeader(api.ErrStatus(err))
 					h.log.Error(err)
 					return
 				}
 			}
 
-			// return only the boards the user is a member of
+			// return only the boards the user can access
 			var boards []teamtbl.Board
 			for _, b := range team.Boards {
-				for _, m := range b.Members {
-					if m == auth.Username {
-						boards = append(boards, b)
-						break
-					}
+				if b.IsVisibleTo(auth.Username, auth.IsAdmin, auth.IsGuest) {
+					boards = append(boards, b)
 				}
 			}
 			team.Boards = boards
 		}
 	}
 
-	// encode invite token if the user is admin
+	// encode invite token if the user is admin - invites can only be used
+	// once, so the team's pending shared invite is reused until it is used or
+	// it expires, after which a new one is recorded on the team
 	if auth.IsAdmin {
-		ckInv, err := h.inviteEncoder.Encode(cookie.NewInvite(team.ID))
+		now := h.clock.Now().Unix()
+		var pending teamtbl.Invite
+		for _, inv := range team.Invites {
+			if inv.Email == "" && inv.ExpiresAt > now {
+				pending = inv
+				break
+			}
+		}
+		nonce := pending.Nonce
+		if nonce == "" {
+			nonce = uuid.NewString()
+		}
+
+		ckInv, err := h.inviteEncoder.Encode(
+			r.Context(), cookie.NewInvite(team.ID, nonce),
+		)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 			return
 		}
+
+		if pending.Nonce == "" {
+			var invites []teamtbl.Invite
+			for _, inv := range team.Invites {
+				if inv.ExpiresAt > now {
+					invites = append(invites, inv)
+				}
+			}
+			team.Invites = append(invites, teamtbl.Invite{
+				Nonce: nonce, ExpiresAt: ckInv.Expires.Unix(),
+			})
+			if err = h.teamUpdater.Update(r.Context(), team); err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+				return
+			}
+		}
+
 		http.SetCookie(w, &ckInv)
 	}
 
+	// retrieve the user's favorite boards - a user that is not found yet has
+	// no favorites
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	isFavorite := make(map[string]bool, len(user.Favorites))
+	for _, id := range user.Favorites {
+		isFavorite[id] = true
+	}
+
+	// list favorite boards first, keeping the team's order otherwise
+	resp := GetResp{
+		ID:      team.ID,
+		Members: team.Members,
+		Boards:  make([]GetBoard, 0, len(team.Boards)),
+		Version: team.Version,
+	}
+	for _, b := range team.Boards {
+		resp.Boards = append(resp.Boards, GetBoard{
+			ID:          b.ID,
+			Name:        b.Name,
+			Members:     b.Members,
+			IsFavorite:  isFavorite[b.ID],
+			Description: b.Description,
+			Columns:     b.Columns,
+			Visibility:  b.Visibility,
+		})
+	}
+	sort.SliceStable(resp.Boards, func(i, j int) bool {
+		return resp.Boards[i].IsFavorite && !resp.Boards[j].IsFavorite
+	})
+
+	// guests cannot see the team's members, so they are only told of
+	// themselves
+	if auth.IsGuest {
+		resp.Members = []string{auth.Username}
+		for i := range resp.Boards {
+			resp.Boards[i].Members = []string{auth.Username}
+		}
+	}
+
 	// encode team
 	w.WriteHeader(status)
-	if err = json.NewEncoder(w).Encode(GetResp(team)); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
 		h.log.Error(err)
 		return
 	}
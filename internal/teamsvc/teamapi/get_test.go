@@ -8,27 +8,32 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
 )
 
 func TestGetHandler(t *testing.T) {
-	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
 	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
 	teamInserter := &db.FakeInserter[teamtbl.Team]{}
 	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
 	inviteEncoder := &cookie.FakeEncoder[cookie.Invite]{}
 	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
 	sut := NewGetHandler(
-		authDecoder,
 		teamRetriever,
 		teamInserter,
 		teamUpdater,
+		userRetriever,
 		inviteEncoder,
+		clk,
 		log,
 	)
 
@@ -36,62 +41,42 @@ func TestGetHandler(t *testing.T) {
 		ID:      "teamid",
 		Members: []string{"memberone", "membertwo"},
 		Boards: []teamtbl.Board{
-			{ID: "board1", Name: "boardone", Members: []string{"memberone"}},
+			{
+				ID:          "board1",
+				Name:        "boardone",
+				Members:     []string{"memberone"},
+				Description: "Sprint work for the **core** team.",
+				Columns: []teamtbl.Column{
+					{Color: "#1a2b3c", Description: "Ready to pick up"},
+				},
+			},
 			{ID: "board2", Name: "boardtwo", Members: []string{"membertwo"}},
 		},
 	}
 
 	for _, c := range []struct {
 		name            string
-		auth            string
-		errDecodeAuth   error
 		authDecoded     cookie.Auth
 		errRetrieve     error
 		team            teamtbl.Team
 		errInsert       error
 		errUpdate       error
+		user            usertbl.User
+		errRetrieveUser error
 		errEncodeInvite error
 		inviteEncoded   http.Cookie
 		wantStatus      int
 		assertFunc      func(*testing.T, *http.Response, []any)
 	}{
-		{
-			name:            "NoAuth",
-			auth:            "",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      func(*testing.T, *http.Response, []any) {},
-		},
-		{
-			name:            "InvalidAuth",
-			auth:            "nonempty",
-			errDecodeAuth:   errors.New("decode auth failed"),
-			authDecoded:     cookie.Auth{},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      func(*testing.T, *http.Response, []any) {},
-		},
 		{
 			name:            "ErrRetrieve",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{},
 			errRetrieve:     errors.New("retrieve failed"),
 			team:            teamtbl.Team{},
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusInternalServerError,
@@ -100,13 +85,13 @@ func TestGetHandler(t *testing.T) {
 		// check comments in implementation for explanation
 		{
 			name:            "NotAdmin",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false},
 			errRetrieve:     db.ErrNoItem,
 			team:            teamtbl.Team{},
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusUnauthorized,
@@ -114,13 +99,13 @@ func TestGetHandler(t *testing.T) {
 		},
 		{
 			name:            "ErrInsert",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true},
 			errRetrieve:     db.ErrNoItem,
 			team:            teamtbl.Team{},
 			errInsert:       errors.New("insert failed"),
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusInternalServerError,
@@ -128,13 +113,13 @@ func TestGetHandler(t *testing.T) {
 		},
 		{
 			name:            "ErrUpdate",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false},
 			errRetrieve:     nil,
 			team:            teamtbl.Team{},
 			errInsert:       nil,
 			errUpdate:       errors.New("update failed"),
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusInternalServerError,
@@ -142,27 +127,41 @@ func TestGetHandler(t *testing.T) {
 		},
 		{
 			name:            "ErrEncodeInvite",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
 			errRetrieve:     nil,
 			team:            teamtbl.Team{Members: []string{"memberone"}},
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: errors.New("encode invite failed"),
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusInternalServerError,
 			assertFunc:      assert.OnLoggedErr("encode invite failed"),
 		},
+		{
+			name:            "ErrRetrieveUser",
+			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
+			errRetrieve:     nil,
+			team:            wantTeam,
+			errInsert:       nil,
+			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: errors.New("retrieve user failed"),
+			errEncodeInvite: nil,
+			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve user failed"),
+		},
 		{
 			name:            "OKAdmin",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
 			errRetrieve:     nil,
 			team:            wantTeam,
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
 			wantStatus:      http.StatusOK,
@@ -180,23 +179,74 @@ func TestGetHandler(t *testing.T) {
 					assert.Equal(t.Error, b.ID, wantB.ID)
 					assert.Equal(t.Error, b.Name, wantB.Name)
 					assert.AllEqual(t.Error, b.Members, wantB.Members)
+					assert.Equal(
+						t.Error, b.Description, wantB.Description,
+					)
+					assert.AllEqual(t.Error, b.Columns, wantB.Columns)
 				}
 
 				// invite cookie should be set for admin
 				ckInv := resp.Cookies()[0]
 				assert.Equal(t.Error, ckInv.Name, "invite-token")
 				assert.Equal(t.Error, ckInv.Value, "aksdfj")
+
+				// the invite should be recorded as pending on the team
+				invites := teamUpdater.Updated.Invites
+				assert.Equal(t.Fatal, len(invites), 1)
+				assert.True(t.Error, invites[0].Nonce != "")
+				assert.Equal(t.Error, invites[0].Email, "")
+			},
+		},
+		{
+			name:            "ErrUpdateInvite",
+			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
+			errRetrieve:     nil,
+			team:            wantTeam,
+			errInsert:       nil,
+			errUpdate:       errors.New("update invite failed"),
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			errEncodeInvite: nil,
+			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("update invite failed"),
+		},
+		{
+			name:        "OKAdminPendingInvite",
+			authDecoded: cookie.Auth{IsAdmin: true, Username: "memberone"},
+			errRetrieve: nil,
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"memberone"},
+				Invites: []teamtbl.Invite{{
+					Nonce:     "nonce1",
+					ExpiresAt: clk.Time.Add(time.Hour).Unix(),
+				}},
+			},
+			errInsert:       nil,
+			errUpdate:       errors.New("update invite failed"),
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
+			errEncodeInvite: nil,
+			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
+			wantStatus:      http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				// the pending invite should be reused without updating the
+				// team
+				assert.Equal(t.Error, teamUpdater.Updated.ID, "")
+				ckInv := resp.Cookies()[0]
+				assert.Equal(t.Error, ckInv.Value, "aksdfj")
 			},
 		},
 		{
 			name:            "OKAdminNewTeam",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: true, Username: "newuser"},
 			errRetrieve:     db.ErrNoItem,
 			team:            teamtbl.Team{},
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
 			wantStatus:      http.StatusCreated,
@@ -220,13 +270,13 @@ func TestGetHandler(t *testing.T) {
 		},
 		{
 			name:            "OKMember",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false, Username: "memberone"},
 			errRetrieve:     nil,
 			team:            wantTeam,
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
 			wantStatus:      http.StatusOK,
@@ -254,13 +304,13 @@ func TestGetHandler(t *testing.T) {
 		},
 		{
 			name:            "OKInvitee",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
 			authDecoded:     cookie.Auth{IsAdmin: false, Username: "newuser"},
 			errRetrieve:     nil,
 			team:            wantTeam,
 			errInsert:       nil,
 			errUpdate:       nil,
+			user:            usertbl.User{},
+			errRetrieveUser: nil,
 			errEncodeInvite: nil,
 			inviteEncoded:   http.Cookie{},
 			wantStatus:      http.StatusOK,
@@ -282,23 +332,111 @@ func TestGetHandler(t *testing.T) {
 				assert.Equal(t.Error, len(resp.Cookies()), 0)
 			},
 		},
+		{
+			name:        "OKMemberTeamVisible",
+			authDecoded: cookie.Auth{IsAdmin: false, Username: "memberone"},
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"memberone", "membertwo"},
+				Boards: []teamtbl.Board{
+					{ID: "board1", Members: []string{"membertwo"}},
+					{
+						ID:         "board2",
+						Members:    []string{"membertwo"},
+						Visibility: teamtbl.VisibilityTeam,
+					},
+				},
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var team GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+					t.Fatal(err)
+				}
+
+				// the team-visible board should be returned even though the
+				// user is not a member of it
+				assert.Equal(t.Fatal, len(team.Boards), 1)
+				assert.Equal(t.Error, team.Boards[0].ID, "board2")
+				assert.Equal(t.Error,
+					team.Boards[0].Visibility, teamtbl.VisibilityTeam,
+				)
+			},
+		},
+		{
+			name: "OKGuest",
+			authDecoded: cookie.Auth{
+				IsAdmin: false, IsGuest: true, Username: "guestone",
+			},
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"memberone", "guestone"},
+				Boards: []teamtbl.Board{
+					{ID: "board1", Members: []string{"memberone"}},
+					{
+						ID:      "board2",
+						Members: []string{"memberone", "guestone"},
+					},
+				},
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var team teamtbl.Team
+				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+					t.Fatal(err)
+				}
+
+				// only the boards the guest was invited to should be
+				// returned, and the members should be hidden from them
+				assert.AllEqual(t.Error, team.Members, []string{"guestone"})
+				assert.Equal(t.Fatal, len(team.Boards), 1)
+				assert.Equal(t.Error, team.Boards[0].ID, "board2")
+				assert.AllEqual(t.Error,
+					team.Boards[0].Members, []string{"guestone"},
+				)
+			},
+		},
+		{
+			name:            "OKFavorites",
+			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
+			errRetrieve:     nil,
+			team:            wantTeam,
+			errInsert:       nil,
+			errUpdate:       nil,
+			user:            usertbl.User{Favorites: []string{"board2"}},
+			errRetrieveUser: nil,
+			errEncodeInvite: nil,
+			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
+			wantStatus:      http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var team GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+					t.Fatal(err)
+				}
+
+				// the starred board should be listed first and flagged
+				assert.Equal(t.Fatal, len(team.Boards), 2)
+				assert.Equal(t.Error, team.Boards[0].ID, "board2")
+				assert.True(t.Error, team.Boards[0].IsFavorite)
+				assert.Equal(t.Error, team.Boards[1].ID, "board1")
+				assert.Equal(t.Error, team.Boards[1].IsFavorite, false)
+			},
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			authDecoder.Err = c.errDecodeAuth
-			authDecoder.Res = c.authDecoded
 			teamRetriever.Err = c.errRetrieve
 			teamRetriever.Res = c.team
 			teamInserter.Err = c.errInsert
 			teamUpdater.Err = c.errUpdate
+			teamUpdater.Updated = teamtbl.Team{}
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
 			inviteEncoder.Err = c.errEncodeInvite
 			inviteEncoder.Res = c.inviteEncoded
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodGet, "/", nil)
-			if c.auth != "" {
-				r.AddCookie(&http.Cookie{Name: "auth-token", Value: c.auth})
-			}
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, c.authDecoded)
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -0,0 +1,96 @@
+package trashapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET trash responses.
+type GetResp struct {
+	Error string    `json:"error,omitempty"`
+	Items []GetItem `json:"items,omitempty"`
+}
+
+// GetItem defines a deleted board or task in a GetResp.
+type GetItem struct {
+	ID        string        `json:"id"`
+	Kind      trashtbl.Kind `json:"kind"`
+	Name      string        `json:"name"`              // board name or task title
+	BoardID   string        `json:"boardID,omitempty"` // only set for tasks
+	DeletedBy string        `json:"deletedBy"`
+	DeletedAt time.Time     `json:"deletedAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// trash route.
+type GetHandler struct {
+	trashRetriever db.Retriever[[]trashtbl.Item]
+	log            log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	trashRetriever db.Retriever[[]trashtbl.Item],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		trashRetriever: trashRetriever,
+		log:            log,
+	}
+}
+
+// Handle handles GET requests sent to the trash route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, GetResp{
+			Error: "Only team admins can view the trash.",
+		})
+		return
+	}
+
+	// retrieve the team's deleted items
+	items, err := h.trashRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var resp GetResp
+	for _, item := range items {
+		ri := GetItem{
+			ID:        item.ID,
+			Kind:      item.Kind,
+			DeletedBy: item.DeletedBy,
+			DeletedAt: time.Unix(item.DeletedAt, 0).UTC(),
+			ExpiresAt: time.Unix(item.ExpiresAt, 0).UTC(),
+		}
+		switch item.Kind {
+		case trashtbl.KindBoard:
+			ri.Name = item.Board.Name
+		case trashtbl.KindTask:
+			ri.Name, ri.BoardID = item.Task.Title, item.Task.BoardID
+		}
+		resp.Items = append(resp.Items, ri)
+	}
+
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// writeResp writes the given status and response.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
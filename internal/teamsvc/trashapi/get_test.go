@@ -0,0 +1,106 @@
+//go:build utest
+
+package trashapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the GET handler.
+func TestGetHandler(t *testing.T) {
+	trashRetriever := &db.FakeRetriever[[]trashtbl.Item]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(trashRetriever, log)
+
+	someItems := []trashtbl.Item{
+		trashtbl.NewBoardItem("team1", "bob123", teamtbl.Board{
+			ID: "board1", Name: "Board 1",
+		}),
+		trashtbl.NewTaskItem("team1", "bob123", tasktbl.Task{
+			ID: "task1", BoardID: "board2", Title: "Task 1",
+		}),
+	}
+
+	for _, c := range []struct {
+		name           string
+		authDecoded    cookie.Auth
+		items          []trashtbl.Item
+		errRetrieve    error
+		wantStatusCode int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:           "NotAdmin",
+			authDecoded:    cookie.Auth{IsAdmin: false},
+			items:          nil,
+			errRetrieve:    nil,
+			wantStatusCode: http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can view the trash.",
+			),
+		},
+		{
+			name:           "ErrRetrieve",
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			items:          nil,
+			errRetrieve:    errors.New("retrieve trash failed"),
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve trash failed"),
+		},
+		{
+			name:           "OK",
+			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "team1"},
+			items:          someItems,
+			errRetrieve:    nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp GetResp
+				if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Fatal, len(resp.Items), 2)
+
+				board := resp.Items[0]
+				assert.Equal(t.Error, board.ID, "board1")
+				assert.Equal(t.Error, board.Kind, trashtbl.KindBoard)
+				assert.Equal(t.Error, board.Name, "Board 1")
+				assert.Equal(t.Error, board.BoardID, "")
+				assert.Equal(t.Error, board.DeletedBy, "bob123")
+				assert.Equal(t.Error,
+					board.ExpiresAt.Sub(board.DeletedAt), trashtbl.Retention,
+				)
+
+				task := resp.Items[1]
+				assert.Equal(t.Error, task.ID, "task1")
+				assert.Equal(t.Error, task.Kind, trashtbl.KindTask)
+				assert.Equal(t.Error, task.Name, "Task 1")
+				assert.Equal(t.Error, task.BoardID, "board2")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			trashRetriever.Res = c.items
+			trashRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package trashapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PostReq defines the body of POST trash restore requests.
+type PostReq struct {
+	ID string `json:"id"`
+}
+
+// PostResp defines the body of POST trash restore responses.
+type PostResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the trash restore route.
+type PostHandler struct {
+	trashRetriever db.RetrieverDualKey[trashtbl.Item]
+	teamRetriever  db.Retriever[teamtbl.Team]
+	boardInserter  db.InserterDualKey[teamtbl.Board]
+	taskInserter   db.Inserter[tasktbl.Task]
+	trashDeleter   db.DeleterDualKey
+	log            log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	trashRetriever db.RetrieverDualKey[trashtbl.Item],
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardInserter db.InserterDualKey[teamtbl.Board],
+	taskInserter db.Inserter[tasktbl.Task],
+	trashDeleter db.DeleterDualKey,
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		trashRetriever: trashRetriever,
+		teamRetriever:  teamRetriever,
+		boardInserter:  boardInserter,
+		taskInserter:   taskInserter,
+		trashDeleter:   trashDeleter,
+		log:            log,
+	}
+}
+
+// Handle handles POST requests sent to the trash restore route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, PostResp{
+			Error: "Only team admins can restore boards and tasks.",
+		})
+		return
+	}
+
+	// decode request
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "Invalid request body.",
+		})
+		return
+	}
+
+	// retrieve the deleted item
+	item, err := h.trashRetriever.Retrieve(r.Context(), auth.TeamID, req.ID)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "Item not found in trash.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// restore the item
+	var status int
+	switch item.Kind {
+	case trashtbl.KindBoard:
+		status = h.restoreBoard(w, r, auth.TeamID, item.Board)
+	case trashtbl.KindTask:
+		status = h.restoreTask(w, r, auth.TeamID, item.Task)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error("unknown trash item kind:", item.Kind)
+		return
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	// take the item out of the trash - it is restored already, so a failure
+	// here is only logged
+	if err := h.trashDeleter.Delete(
+		r.Context(), auth.TeamID, item.ID,
+	); err != nil {
+		h.log.Error(err)
+	}
+
+	h.writeResp(w, http.StatusOK, PostResp{})
+}
+
+// restoreBoard inserts the given board back into the team's boards, writing an
+// error response and returning its status if it fails.
+func (h PostHandler) restoreBoard(
+	w http.ResponseWriter, r *http.Request, teamID string, board teamtbl.Board,
+) int {
+	err := h.boardInserter.Insert(r.Context(), teamID, board)
+	if errors.Is(err, db.ErrDupKey) {
+		h.writeResp(w, http.StatusConflict, PostResp{
+			Error: "Board already exists.",
+		})
+		return http.StatusConflict
+	} else if errors.Is(err, db.ErrLimitReached) {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "You have already created the maximum amount of boards " +
+				"allowed per team. Please delete one of your boards to " +
+				"restore this one.",
+		})
+		return http.StatusBadRequest
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return api.ErrStatus(err)
+	}
+	return http.StatusOK
+}
+
+// restoreTask inserts the given task back into the task table, writing an
+// error response and returning its status if it fails. The task's board must
+// exist for it to be restored.
+func (h PostHandler) restoreTask(
+	w http.ResponseWriter, r *http.Request, teamID string, task tasktbl.Task,
+) int {
+	team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return api.ErrStatus(err)
+	}
+	var found bool
+	for _, b := range team.Boards {
+		if b.ID == task.BoardID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		h.writeResp(w, http.StatusConflict, PostResp{
+			Error: "The task's board is deleted. Restore the board first.",
+		})
+		return http.StatusConflict
+	}
+
+	err = h.taskInserter.Insert(r.Context(), task)
+	if errors.Is(err, db.ErrDupKey) {
+		h.writeResp(w, http.StatusConflict, PostResp{
+			Error: "Task already exists.",
+		})
+		return http.StatusConflict
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return api.ErrStatus(err)
+	}
+	return http.StatusOK
+}
+
+// writeResp writes the given status and response.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,259 @@
+//go:build utest
+
+package trashapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the POST handler.
+func TestPostHandler(t *testing.T) {
+	trashRetriever := &db.FakeRetrieverDualKey[trashtbl.Item]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardInserter := &db.FakeInserterDualKey[teamtbl.Board]{}
+	taskInserter := &db.FakeInserter[tasktbl.Task]{}
+	trashDeleter := &db.FakeDeleterDualKey{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		trashRetriever,
+		teamRetriever,
+		boardInserter,
+		taskInserter,
+		trashDeleter,
+		log,
+	)
+
+	var (
+		admin     = cookie.Auth{IsAdmin: true, TeamID: "team1"}
+		boardItem = trashtbl.NewBoardItem("team1", "bob123", teamtbl.Board{
+			ID: "board1",
+		})
+		taskItem = trashtbl.NewTaskItem("team1", "bob123", tasktbl.Task{
+			ID: "task1", BoardID: "board2",
+		})
+		team = teamtbl.Team{Boards: []teamtbl.Board{{ID: "board2"}}}
+	)
+
+	for _, c := range []struct {
+		name           string
+		authDecoded    cookie.Auth
+		reqBody        string
+		item           trashtbl.Item
+		errRetrieve    error
+		team           teamtbl.Team
+		errInsertBoard error
+		errInsertTask  error
+		errDelete      error
+		wantStatusCode int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:           "NotAdmin",
+			authDecoded:    cookie.Auth{IsAdmin: false},
+			reqBody:        `{"id": "board1"}`,
+			item:           trashtbl.Item{},
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can restore boards and tasks.",
+			),
+		},
+		{
+			name:           "InvalidBody",
+			authDecoded:    admin,
+			reqBody:        `{`,
+			item:           trashtbl.Item{},
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:           "NotInTrash",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           trashtbl.Item{},
+			errRetrieve:    db.ErrNoItem,
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusNotFound,
+			assertFunc:     assert.OnRespErr("Item not found in trash."),
+		},
+		{
+			name:           "ErrRetrieve",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           trashtbl.Item{},
+			errRetrieve:    errors.New("retrieve trash failed"),
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve trash failed"),
+		},
+		{
+			name:           "BoardExists",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           boardItem,
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: db.ErrDupKey,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusConflict,
+			assertFunc:     assert.OnRespErr("Board already exists."),
+		},
+		{
+			name:           "BoardLimitReached",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           boardItem,
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: db.ErrLimitReached,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"You have already created the maximum amount of boards " +
+					"allowed per team. Please delete one of your boards to " +
+					"restore this one.",
+			),
+		},
+		{
+			name:           "ErrInsertBoard",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           boardItem,
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: errors.New("insert board failed"),
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("insert board failed"),
+		},
+		{
+			name:           "BoardRestored",
+			authDecoded:    admin,
+			reqBody:        `{"id": "board1"}`,
+			item:           boardItem,
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:           "TaskBoardDeleted",
+			authDecoded:    admin,
+			reqBody:        `{"id": "task1"}`,
+			item:           taskItem,
+			errRetrieve:    nil,
+			team:           teamtbl.Team{},
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"The task's board is deleted. Restore the board first.",
+			),
+		},
+		{
+			name:           "TaskExists",
+			authDecoded:    admin,
+			reqBody:        `{"id": "task1"}`,
+			item:           taskItem,
+			errRetrieve:    nil,
+			team:           team,
+			errInsertBoard: nil,
+			errInsertTask:  db.ErrDupKey,
+			errDelete:      nil,
+			wantStatusCode: http.StatusConflict,
+			assertFunc:     assert.OnRespErr("Task already exists."),
+		},
+		{
+			name:           "ErrInsertTask",
+			authDecoded:    admin,
+			reqBody:        `{"id": "task1"}`,
+			item:           taskItem,
+			errRetrieve:    nil,
+			team:           team,
+			errInsertBoard: nil,
+			errInsertTask:  errors.New("insert task failed"),
+			errDelete:      nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("insert task failed"),
+		},
+		{
+			name:           "ErrDelete",
+			authDecoded:    admin,
+			reqBody:        `{"id": "task1"}`,
+			item:           taskItem,
+			errRetrieve:    nil,
+			team:           team,
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      errors.New("delete trash failed"),
+			wantStatusCode: http.StatusOK,
+			assertFunc:     assert.OnLoggedErr("delete trash failed"),
+		},
+		{
+			name:           "TaskRestored",
+			authDecoded:    admin,
+			reqBody:        `{"id": "task1"}`,
+			item:           taskItem,
+			errRetrieve:    nil,
+			team:           team,
+			errInsertBoard: nil,
+			errInsertTask:  nil,
+			errDelete:      nil,
+			wantStatusCode: http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			trashRetriever.Res = c.item
+			trashRetriever.Err = c.errRetrieve
+			teamRetriever.Res = c.team
+			boardInserter.Err = c.errInsertBoard
+			taskInserter.Err = c.errInsertTask
+			trashDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.reqBody),
+			)
+
+			sut.Handle(w, r, c.authDecoded)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package trashapi contains code for responding to HTTP requests made to the
+// team trash API routes, which are used for listing and restoring deleted
+// boards and tasks, and to the undo route, which is used for undoing deletes
+// and moves of tasks shortly after they are made.
+package trashapi
@@ -0,0 +1,316 @@
+package trashapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// UndoWindow is how long after an action it can be undone.
+const UndoWindow = 5 * time.Minute
+
+// actions that can be undone, as reported in UndoResp.
+const (
+	actionDeleteBoard = "board.delete"
+	actionDeleteTask  = "task.delete"
+	actionMoveTasks   = "tasks.move"
+)
+
+// UndoResp defines the body of POST undo responses. Boards and Tasks hold what
+// was restored by undoing Action.
+type UndoResp struct {
+	Error  string          `json:"error,omitempty"`
+	Action string          `json:"action,omitempty"`
+	Boards []teamtbl.Board `json:"boards,omitempty"`
+	Tasks  []tasktbl.Task  `json:"tasks,omitempty"`
+}
+
+// UndoHandler is an api.MethodHandler that can handle POST requests sent to
+// the undo route. Deleted boards and tasks are restored from the trash, and
+// moved tasks are moved back to the columns they were moved from, as recorded
+// in their history.
+type UndoHandler struct {
+	// restorer is used to restore deleted boards and tasks from the trash
+	// rather than to handle requests, so it has no trash retriever
+	restorer PostHandler
+
+	trashByTeam  db.Retriever[[]trashtbl.Item]
+	histByTeam   db.RetrieverDualKey[[]histtbl.Entry]
+	tasksByTeam  db.Retriever[[]tasktbl.Task]
+	tasksUpdater db.Updater[[]tasktbl.Task]
+	histInserter db.Inserter[histtbl.Entry]
+	clock        clock.Clock
+	log          log.Errorer
+}
+
+// NewUndoHandler creates and returns a new UndoHandler.
+func NewUndoHandler(
+	trashByTeam db.Retriever[[]trashtbl.Item],
+	histByTeam db.RetrieverDualKey[[]histtbl.Entry],
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardInserter db.InserterDualKey[teamtbl.Board],
+	taskInserter db.Inserter[tasktbl.Task],
+	trashDeleter db.DeleterDualKey,
+	tasksByTeam db.Retriever[[]tasktbl.Task],
+	tasksUpdater db.Updater[[]tasktbl.Task],
+	histInserter db.Inserter[histtbl.Entry],
+	clock clock.Clock,
+	log log.Errorer,
+) UndoHandler {
+	return UndoHandler{
+		restorer: NewPostHandler(
+			nil, teamRetriever, boardInserter, taskInserter, trashDeleter, log,
+		),
+		trashByTeam:  trashByTeam,
+		histByTeam:   histByTeam,
+		tasksByTeam:  tasksByTeam,
+		tasksUpdater: tasksUpdater,
+		histInserter: histInserter,
+		clock:        clock,
+		log:          log,
+	}
+}
+
+// Handle handles POST requests sent to the undo route. It undoes the most
+// recent board delete, task delete, or move of tasks that the user made within
+// UndoWindow and has not undone yet.
+func (h UndoHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate user is admin
+	if !auth.IsAdmin {
+		h.writeResp(w, http.StatusForbidden, UndoResp{
+			Error: "Only team admins can undo actions.",
+		})
+		return
+	}
+	since := h.clock.Now().Add(-UndoWindow)
+
+	// find the user's latest deletion within the window
+	items, err := h.trashByTeam.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	var deleted *trashtbl.Item
+	for i, item := range items {
+		if item.DeletedBy == auth.Username &&
+			item.DeletedAt >= since.Unix() &&
+			(deleted == nil || item.DeletedAt > deleted.DeletedAt) {
+			deleted = &items[i]
+		}
+	}
+
+	// find the user's latest move of tasks within the window that has not
+	// been undone yet
+	entries, err := h.histByTeam.Retrieve(
+		r.Context(), auth.TeamID, since.UTC().Format(time.DateOnly),
+	)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	moved := latestMove(entries, auth.Username, since)
+
+	// undo whichever of the two is more recent
+	switch {
+	case len(moved) > 0 &&
+		(deleted == nil || moved[0].At >= deleted.DeletedAt*int64(time.Second)):
+		h.undoMove(w, r, auth, moved)
+	case deleted != nil:
+		h.undoDelete(w, r, auth, *deleted)
+	default:
+		h.writeResp(w, http.StatusNotFound, UndoResp{
+			Error: "Nothing to undo.",
+		})
+	}
+}
+
+// undoDelete restores the given deleted board or task from the trash.
+func (h UndoHandler) undoDelete(
+	w http.ResponseWriter,
+	r *http.Request,
+	auth cookie.Auth,
+	item trashtbl.Item,
+) {
+	var (
+		status int
+		resp   UndoResp
+	)
+	switch item.Kind {
+	case trashtbl.KindBoard:
+		status = h.restorer.restoreBoard(w, r, auth.TeamID, item.Board)
+		resp = UndoResp{
+			Action: actionDeleteBoard, Boards: []teamtbl.Board{item.Board},
+		}
+	case trashtbl.KindTask:
+		status = h.restorer.restoreTask(w, r, auth.TeamID, item.Task)
+		resp = UndoResp{
+			Action: actionDeleteTask, Tasks: []tasktbl.Task{item.Task},
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error("unknown trash item kind:", item.Kind)
+		return
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	// take the item out of the trash - it is restored already, so a failure
+	// here is only logged
+	if err := h.restorer.trashDeleter.Delete(
+		r.Context(), auth.TeamID, item.ID,
+	); err != nil {
+		h.log.Error(err)
+	}
+
+	h.writeResp(w, http.StatusOK, resp)
+}
+
+// undoMove moves the tasks whose moves the given entries record back to the
+// columns they were moved from, at the end of them. The tasks that were
+// deleted or moved again since are left where they are.
+func (h UndoHandler) undoMove(
+	w http.ResponseWriter,
+	r *http.Request,
+	auth cookie.Auth,
+	moves []histtbl.Entry,
+) {
+	tasks, err := h.tasksByTeam.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// index the tasks and group them into their columns in their order
+	type column struct {
+		boardID string
+		colNo   int
+	}
+	byID, cols := map[string]tasktbl.Task{}, map[column][]tasktbl.Task{}
+	tasktbl.SortByRank(tasks)
+	for _, t := range tasks {
+		byID[t.ID] = t
+		key := column{boardID: t.BoardID, colNo: t.ColNo}
+		cols[key] = append(cols[key], t)
+	}
+
+	// move each task back to the end of its old column
+	var restored []tasktbl.Task
+	for _, e := range moves {
+		t, ok := byID[e.TaskID]
+		c := e.Changes[0]
+		if !ok || strconv.Itoa(t.ColNo) != c.New {
+			continue
+		}
+		oldColNo, err := strconv.Atoi(c.Old)
+		if err != nil {
+			continue
+		}
+		key := column{boardID: t.BoardID, colNo: oldColNo}
+		t.ColNo, t.Order = oldColNo, len(cols[key])
+		t.Rank = tasktbl.RankAt(cols[key], len(cols[key]))
+		cols[key] = append(cols[key], t)
+		restored = append(restored, t)
+	}
+	if len(restored) == 0 {
+		h.writeResp(w, http.StatusConflict, UndoResp{
+			Error: "The moved tasks have been changed since. Nothing to undo.",
+		})
+		return
+	}
+
+	if err = h.tasksUpdater.Update(
+		r.Context(), restored,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, UndoResp{
+			Error: "Task not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// record the moves back in the tasks' history, marked as undoing the
+	// moves so that they are not undone again - the tasks have already been
+	// moved back at this point, so a failure here is only logged
+	at := h.clock.Now().UnixNano()
+	for _, t := range restored {
+		old := byID[t.ID]
+		entry := histtbl.NewEntry(
+			t.ID, auth.TeamID, auth.Username, histtbl.Diff(old, t),
+		)
+		entry.At, entry.Undoes = at, moves[0].At
+		if err := h.histInserter.Insert(r.Context(), entry); err != nil {
+			h.log.Error(err)
+		}
+	}
+
+	h.writeResp(w, http.StatusOK, UndoResp{
+		Action: actionMoveTasks, Tasks: restored,
+	})
+}
+
+// latestMove returns the entries that record the latest move of tasks between
+// columns that the given user made since the given time and has not undone.
+// The entries of a move share their At.
+func latestMove(
+	entries []histtbl.Entry, username string, since time.Time,
+) []histtbl.Entry {
+	undone := map[int64]bool{}
+	for _, e := range entries {
+		if e.Undoes != 0 {
+			undone[e.Undoes] = true
+		}
+	}
+
+	var moves []histtbl.Entry
+	for _, e := range entries {
+		if e.Username != username || e.At < since.UnixNano() ||
+			e.Undoes != 0 || undone[e.At] || !isMove(e) {
+			continue
+		}
+		if len(moves) > 0 && e.At > moves[0].At {
+			moves = nil
+		}
+		if len(moves) == 0 || e.At == moves[0].At {
+			moves = append(moves, e)
+		}
+	}
+	return moves
+}
+
+// isMove returns whether the given entry records only the move of a task from
+// one column to another.
+func isMove(e histtbl.Entry) bool {
+	return len(e.Changes) == 1 && e.Changes[0].Field == histtbl.FieldColumn &&
+		e.Changes[0].Old != ""
+}
+
+// writeResp writes the given status and response.
+func (h UndoHandler) writeResp(
+	w http.ResponseWriter, status int, resp UndoResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
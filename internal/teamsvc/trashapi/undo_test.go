@@ -0,0 +1,294 @@
+//go:build utest
+
+package trashapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestUndoHandler tests the Handle method of UndoHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestUndoHandler(t *testing.T) {
+	trashByTeam := &db.FakeRetriever[[]trashtbl.Item]{}
+	histByTeam := &db.FakeRetrieverDualKey[[]histtbl.Entry]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardInserter := &db.FakeInserterDualKey[teamtbl.Board]{}
+	taskInserter := &db.FakeInserter[tasktbl.Task]{}
+	trashDeleter := &db.FakeDeleterDualKey{}
+	tasksByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	tasksUpdater := &db.FakeUpdater[[]tasktbl.Task]{}
+	histInserter := &db.FakeInserter[histtbl.Entry]{}
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	log := &log.FakeErrorer{}
+	sut := NewUndoHandler(
+		trashByTeam,
+		histByTeam,
+		teamRetriever,
+		boardInserter,
+		taskInserter,
+		trashDeleter,
+		tasksByTeam,
+		tasksUpdater,
+		histInserter,
+		&clock.Fake{Time: now},
+		log,
+	)
+	teamRetriever.Res = teamtbl.Team{Boards: []teamtbl.Board{{ID: "board2"}}}
+
+	var (
+		admin = cookie.Auth{IsAdmin: true, TeamID: "team1", Username: "bob"}
+
+		// deleted returns a trash item deleted by bob the given time ago
+		deleted = func(item trashtbl.Item, ago time.Duration) trashtbl.Item {
+			item.DeletedAt = now.Add(-ago).Unix()
+			return item
+		}
+		boardItem = trashtbl.NewBoardItem("team1", "bob", teamtbl.Board{
+			ID: "board1",
+		})
+		taskItem = trashtbl.NewTaskItem("team1", "bob", tasktbl.Task{
+			ID: "task9", BoardID: "board2",
+		})
+
+		// moved returns the entry recording bob's move of the given task
+		// between the given columns at the given time ago
+		moved = func(taskID, from, to string, ago time.Duration) histtbl.Entry {
+			return histtbl.Entry{
+				TaskID:   taskID,
+				At:       now.Add(-ago).UnixNano(),
+				TeamID:   "team1",
+				Username: "bob",
+				Changes: []histtbl.Change{
+					{Field: histtbl.FieldColumn, Old: from, New: to},
+				},
+			}
+		}
+		move = []histtbl.Entry{
+			moved("task1", "0", "1", time.Minute),
+			moved("task2", "0", "2", time.Minute),
+		}
+		tasks = []tasktbl.Task{
+			{BoardID: "board2", ColNo: 0, ID: "task0", Rank: "i"},
+			{BoardID: "board2", ColNo: 1, ID: "task1", Rank: "i"},
+			{BoardID: "board2", ColNo: 2, ID: "task2", Rank: "i"},
+		}
+	)
+
+	for _, c := range []struct {
+		name           string
+		auth           cookie.Auth
+		items          []trashtbl.Item
+		errTrash       error
+		entries        []histtbl.Entry
+		errHist        error
+		tasks          []tasktbl.Task
+		errInsertBoard error
+		errUpdate      error
+		wantStatus     int
+		assertFunc     func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NotAdmin",
+			auth:       cookie.Auth{IsAdmin: false},
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr("Only team admins can undo actions."),
+		},
+		{
+			name:       "ErrRetrieveTrash",
+			auth:       admin,
+			errTrash:   errors.New("retrieve trash failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve trash failed"),
+		},
+		{
+			name:       "ErrRetrieveHistory",
+			auth:       admin,
+			errHist:    errors.New("retrieve history failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve history failed"),
+		},
+		{
+			name: "NothingToUndo",
+			auth: admin,
+			items: []trashtbl.Item{
+				deleted(boardItem, UndoWindow+time.Second),
+				deleted(trashtbl.NewTaskItem(
+					"team1", "jo", tasktbl.Task{ID: "task8"},
+				), time.Minute),
+			},
+			entries: []histtbl.Entry{
+				moved("task1", "0", "1", UndoWindow+time.Second),
+				moved("task2", "", "0", time.Minute),
+			},
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Nothing to undo."),
+		},
+		{
+			name: "BoardConflict",
+			auth: admin,
+			items: []trashtbl.Item{
+				deleted(boardItem, time.Minute),
+			},
+			errInsertBoard: db.ErrDupKey,
+			wantStatus:     http.StatusConflict,
+			assertFunc:     assert.OnRespErr("Board already exists."),
+		},
+		{
+			name: "OKDeleteBoard",
+			auth: admin,
+			items: []trashtbl.Item{
+				deleted(taskItem, 2*time.Minute),
+				deleted(boardItem, time.Minute),
+			},
+			entries: []histtbl.Entry{
+				moved("task1", "0", "1", 3*time.Minute),
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp UndoResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, resp.Action, "board.delete")
+				assert.Equal(t.Fatal, len(resp.Boards), 1)
+				assert.Equal(t.Error, resp.Boards[0].ID, "board1")
+			},
+		},
+		{
+			name: "OKDeleteTask",
+			auth: admin,
+			items: []trashtbl.Item{
+				deleted(boardItem, 2*time.Minute),
+				deleted(taskItem, time.Minute),
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp UndoResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, resp.Action, "task.delete")
+				assert.Equal(t.Fatal, len(resp.Tasks), 1)
+				assert.Equal(t.Error, resp.Tasks[0].ID, "task9")
+				assert.Equal(t.Error, taskInserter.Inserted.ID, "task9")
+			},
+		},
+		{
+			name: "MoveChanged",
+			auth: admin,
+			entries: []histtbl.Entry{
+				moved("task1", "0", "2", time.Minute),
+				moved("task3", "0", "1", time.Minute),
+			},
+			tasks:      tasks,
+			wantStatus: http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"The moved tasks have been changed since. Nothing to undo.",
+			),
+		},
+		{
+			name:       "ErrUpdate",
+			auth:       admin,
+			entries:    move,
+			tasks:      tasks,
+			errUpdate:  errors.New("update failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:       "OKMove",
+			auth:       admin,
+			items:      []trashtbl.Item{deleted(boardItem, 2*time.Minute)},
+			entries:    move,
+			tasks:      tasks,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				var resp UndoResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, resp.Action, "tasks.move")
+
+				// both tasks are moved back after the task in their old
+				// column
+				got := tasksUpdater.Updated
+				assert.Equal(t.Fatal, len(got), 2)
+				for i, id := range []string{"task1", "task2"} {
+					assert.Equal(t.Error, got[i].ID, id)
+					assert.Equal(t.Error, got[i].ColNo, 0)
+					assert.Equal(t.Error, got[i].Order, i+1)
+				}
+				assert.True(t.Error,
+					"i" < got[0].Rank && got[0].Rank < got[1].Rank,
+				)
+
+				// the moves back are recorded as undoing the move
+				entry := histInserter.Inserted
+				assert.Equal(t.Error, entry.Undoes, move[0].At)
+				assert.Equal(t.Fatal, len(entry.Changes), 1)
+				assert.Equal(t.Error, entry.Changes[0].Old, "2")
+				assert.Equal(t.Error, entry.Changes[0].New, "0")
+			},
+		},
+		{
+			name:  "OKMoveUndone",
+			auth:  admin,
+			items: []trashtbl.Item{deleted(boardItem, 2*time.Minute)},
+			entries: append([]histtbl.Entry{
+				{
+					TaskID:   "task1",
+					At:       now.Add(-30 * time.Second).UnixNano(),
+					Username: "bob",
+					Changes: []histtbl.Change{
+						{Field: histtbl.FieldColumn, Old: "1", New: "0"},
+					},
+					Undoes: move[0].At,
+				},
+			}, move...),
+			tasks:      tasks,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, r *http.Response, _ []any) {
+				// the move is undone already, so the deletion before it is
+				// undone instead
+				var resp UndoResp
+				err := json.NewDecoder(r.Body).Decode(&resp)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, resp.Action, "board.delete")
+				assert.Equal(t.Error, len(tasksUpdater.Updated), 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			trashByTeam.Res = c.items
+			trashByTeam.Err = c.errTrash
+			histByTeam.Res = c.entries
+			histByTeam.Err = c.errHist
+			tasksByTeam.Res = append([]tasktbl.Task(nil), c.tasks...)
+			boardInserter.Err = c.errInsertBoard
+			taskInserter.Inserted = tasktbl.Task{}
+			tasksUpdater.Err = c.errUpdate
+			tasksUpdater.Updated = nil
+			histInserter.Inserted = histtbl.Entry{}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Package adminapi contains code for responding to HTTP requests made to the
+// admin API routes, which are used by the operators of a deployment for
+// managing users, such as when handling abuse reports. They are authenticated
+// with a static admin token rather than with auth tokens so that no user of
+// the app can ever reach them.
+package adminapi
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Authorizer describes a type that can be used to check whether a request was
+// made by an operator.
+type Authorizer interface {
+	Authorize(r *http.Request) bool
+}
+
+// TokenAuthorizer is an Authorizer that authorizes the requests that carry the
+// admin token as a bearer token in their Authorization header.
+type TokenAuthorizer struct{ tokenHash [sha256.Size]byte }
+
+// NewTokenAuthorizer creates and returns a new TokenAuthorizer for the given
+// admin token, which must not be empty.
+func NewTokenAuthorizer(token string) TokenAuthorizer {
+	return TokenAuthorizer{tokenHash: sha256.Sum256([]byte(token))}
+}
+
+// Authorize returns whether the request carries the admin token. The tokens
+// are compared by their hashes in constant time so that neither their contents
+// nor their lengths leak through timing.
+func (a TokenAuthorizer) Authorize(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	hash := sha256.Sum256([]byte(token))
+	return subtle.ConstantTimeCompare(hash[:], a.tokenHash[:]) == 1
+}
+
+// writeUnauthorized writes the response sent to requests that were not made
+// by an operator.
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+}
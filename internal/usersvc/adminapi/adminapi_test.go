@@ -0,0 +1,37 @@
+//go:build utest
+
+package adminapi
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestTokenAuthorizer tests the Authorize method of TokenAuthorizer to assert
+// that it only authorizes the requests carrying the admin token.
+func TestTokenAuthorizer(t *testing.T) {
+	sut := NewTokenAuthorizer("s3cret")
+
+	for _, c := range []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "NoHeader", header: "", want: false},
+		{name: "NotBearer", header: "Basic s3cret", want: false},
+		{name: "EmptyToken", header: "Bearer ", want: false},
+		{name: "WrongToken", header: "Bearer s3cre", want: false},
+		{name: "OK", header: "Bearer s3cret", want: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("", "/", nil)
+			if c.header != "" {
+				r.Header.Set("Authorization", c.header)
+			}
+
+			assert.Equal(t.Error, sut.Authorize(r), c.want)
+		})
+	}
+}
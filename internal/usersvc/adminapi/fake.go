@@ -0,0 +1,26 @@
+package adminapi
+
+import "net/http"
+
+// fakeAuthorizer is a test fake for Authorizer.
+type fakeAuthorizer struct{ isAuthorized bool }
+
+// Authorize implements the Authorizer interface on fakeAuthorizer.
+func (f *fakeAuthorizer) Authorize(_ *http.Request) bool {
+	return f.isAuthorized
+}
+
+// fakeHasher is a test fake for Hasher.
+type fakeHasher struct {
+	hash []byte
+	err  error
+
+	// plaintext is set to the password passed to Hash.
+	plaintext string
+}
+
+// Hash implements the Hasher interface on fakeHasher.
+func (f *fakeHasher) Hash(plaintext string) ([]byte, error) {
+	f.plaintext = plaintext
+	return f.hash, f.err
+}
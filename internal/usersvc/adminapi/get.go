@@ -0,0 +1,91 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET users responses.
+type GetResp struct {
+	Users []GetUser `json:"users"`
+}
+
+// GetUser defines a user in a GetResp.
+type GetUser struct {
+	Username   string `json:"username"`
+	TeamID     string `json:"teamID"`
+	IsAdmin    bool   `json:"isAdmin"`
+	IsDisabled bool   `json:"isDisabled"`
+
+	// Sessions is the number of sessions the user is logged in with.
+	Sessions int `json:"sessions"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// admin users route.
+type GetHandler struct {
+	authorizer Authorizer
+	userLister db.Lister[[]usertbl.User]
+	clock      clock.Clock
+	log        log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authorizer Authorizer,
+	userLister db.Lister[[]usertbl.User],
+	clock clock.Clock,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authorizer: authorizer,
+		userLister: userLister,
+		clock:      clock,
+		log:        log,
+	}
+}
+
+// Handle handles GET requests sent to the admin users route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	if !h.authorizer.Authorize(r) {
+		writeUnauthorized(w)
+		return
+	}
+
+	users, err := h.userLister.List(r.Context())
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	resp := GetResp{Users: make([]GetUser, 0, len(users))}
+	now := h.clock.Now().Unix()
+	for _, u := range users {
+		sessions := 0
+		for _, s := range u.Sessions {
+			if s.ExpiresAt > now {
+				sessions++
+			}
+		}
+		resp.Users = append(resp.Users, GetUser{
+			Username:   u.Username,
+			TeamID:     u.TeamID,
+			IsAdmin:    u.IsAdmin,
+			IsDisabled: u.IsDisabled,
+			Sessions:   sessions,
+		})
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build utest
+
+package adminapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userLister := &db.FakeLister[[]usertbl.User]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewGetHandler(authorizer, userLister, clk, log)
+
+	now := clk.Time.Unix()
+	users := []usertbl.User{
+		{
+			Username: "alice",
+			TeamID:   "team1",
+			IsAdmin:  true,
+			Sessions: []usertbl.Session{
+				{ID: "s1", ExpiresAt: now + 60},
+				{ID: "s2", ExpiresAt: now - 60},
+			},
+		},
+		{Username: "bob", TeamID: "team1", IsDisabled: true},
+	}
+
+	for _, c := range []struct {
+		name         string
+		isAuthorized bool
+		errList      error
+		wantStatus   int
+		assertFunc   func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:         "Unauthorized",
+			isAuthorized: false,
+			errList:      nil,
+			wantStatus:   http.StatusUnauthorized,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					resp.Header.Get("WWW-Authenticate"), "Bearer",
+				)
+			},
+		},
+		{
+			name:         "ErrList",
+			isAuthorized: true,
+			errList:      errors.New("list users failed"),
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("list users failed"),
+		},
+		{
+			name:         "OK",
+			isAuthorized: true,
+			errList:      nil,
+			wantStatus:   http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				assert.AllEqual(t.Error, body.Users, []GetUser{
+					{
+						Username: "alice",
+						TeamID:   "team1",
+						IsAdmin:  true,
+						Sessions: 1,
+					},
+					{Username: "bob", TeamID: "team1", IsDisabled: true},
+				})
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.isAuthorized = c.isAuthorized
+			userLister.Res = users
+			userLister.Err = c.errList
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PatchReq defines the body of PATCH user requests.
+type PatchReq struct {
+	IsDisabled *bool `json:"isDisabled"`
+}
+
+// PatchResp defines the body of PATCH user responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
+// the admin user route. Disabling a user also revokes all of their sessions so
+// that they are signed out right away.
+type PatchHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PATCH requests sent to the admin user route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	if !h.authorizer.Authorize(r) {
+		writeUnauthorized(w)
+		return
+	}
+
+	// decode and validate the request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErr(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	if req.IsDisabled == nil {
+		h.writeErr(w, http.StatusBadRequest, "isDisabled must be set.")
+		return
+	}
+
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(
+		r.Context(), api.PathParam(r, "username"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// update the user
+	user.IsDisabled = *req.IsDisabled
+	if user.IsDisabled {
+		user.Sessions = nil
+	}
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeErr writes the given status and error message.
+func (h PatchHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
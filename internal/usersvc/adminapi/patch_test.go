@@ -0,0 +1,143 @@
+//go:build utest
+
+package adminapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(authorizer, userRetriever, userUpdater, log)
+
+	user := usertbl.User{
+		Username: "bob",
+		Sessions: []usertbl.Session{{ID: "s1"}},
+	}
+
+	for _, c := range []struct {
+		name         string
+		isAuthorized bool
+		body         string
+		errRetrieve  error
+		errUpdate    error
+		wantStatus   int
+		assertFunc   func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:         "Unauthorized",
+			isAuthorized: false,
+			body:         `{"isDisabled": true}`,
+			errRetrieve:  nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusUnauthorized,
+			assertFunc:   func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:         "InvalidBody",
+			isAuthorized: true,
+			body:         `{"isDisabled": "yes"}`,
+			errRetrieve:  nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusBadRequest,
+			assertFunc:   assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:         "NoIsDisabled",
+			isAuthorized: true,
+			body:         `{}`,
+			errRetrieve:  nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusBadRequest,
+			assertFunc:   assert.OnRespErr("isDisabled must be set."),
+		},
+		{
+			name:         "UserNotFound",
+			isAuthorized: true,
+			body:         `{"isDisabled": true}`,
+			errRetrieve:  db.ErrNoItem,
+			errUpdate:    nil,
+			wantStatus:   http.StatusNotFound,
+			assertFunc:   assert.OnRespErr("User not found."),
+		},
+		{
+			name:         "ErrRetrieve",
+			isAuthorized: true,
+			body:         `{"isDisabled": true}`,
+			errRetrieve:  errors.New("retrieve user failed"),
+			errUpdate:    nil,
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:         "ErrUpdate",
+			isAuthorized: true,
+			body:         `{"isDisabled": true}`,
+			errRetrieve:  nil,
+			errUpdate:    errors.New("update user failed"),
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:         "Disable",
+			isAuthorized: true,
+			body:         `{"isDisabled": true}`,
+			errRetrieve:  nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the user should be signed out of all sessions
+				assert.True(t.Error, userUpdater.Updated.IsDisabled)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 0)
+			},
+		},
+		{
+			name:         "Enable",
+			isAuthorized: true,
+			body:         `{"isDisabled": false}`,
+			errRetrieve:  nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.True(t.Error, !userUpdater.Updated.IsDisabled)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 1)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.isAuthorized = c.isAuthorized
+			userRetriever.Res = user
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPatch, "/", strings.NewReader(c.body),
+				),
+				map[string]string{"username": "bob"},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package adminapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// tmpPwdLen is the number of random bytes in a temporary password.
+const tmpPwdLen = 18
+
+// Hasher describes a type that can be used to hash a plaintext password.
+type Hasher interface{ Hash(string) ([]byte, error) }
+
+// PostResp defines the body of POST password reset responses.
+type PostResp struct {
+	Error string `json:"error,omitempty"`
+
+	// TemporaryPassword is the password the user can log in with from now
+	// on. It is only ever returned once, for the operator to hand over to the
+	// user.
+	TemporaryPassword string `json:"temporaryPassword,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to the
+// admin password reset route. It replaces the user's password with a random
+// temporary one and revokes all of their sessions, so that whoever was using
+// the account is signed out and can no longer log in.
+type PostHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	hasher        Hasher
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	hasher Hasher,
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		hasher:        hasher,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles POST requests sent to the admin password reset route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	if !h.authorizer.Authorize(r) {
+		writeUnauthorized(w)
+		return
+	}
+
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(
+		r.Context(), api.PathParam(r, "username"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// generate and hash a temporary password
+	b := make([]byte, tmpPwdLen)
+	if _, err = rand.Read(b); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	tmpPwd := base64.RawURLEncoding.EncodeToString(b)
+	if user.Password, err = h.hasher.Hash(tmpPwd); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// replace the password and revoke the sessions
+	user.Sessions = nil
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, PostResp{TemporaryPassword: tmpPwd})
+}
+
+// writeResp writes the given status and response.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
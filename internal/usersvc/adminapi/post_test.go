@@ -0,0 +1,136 @@
+//go:build utest
+
+package adminapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	hasher := &fakeHasher{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authorizer, userRetriever, hasher, userUpdater, log,
+	)
+
+	user := usertbl.User{
+		Username: "bob",
+		Password: []byte("oldhash"),
+		Sessions: []usertbl.Session{{ID: "s1"}},
+	}
+
+	for _, c := range []struct {
+		name         string
+		isAuthorized bool
+		errRetrieve  error
+		errHash      error
+		errUpdate    error
+		wantStatus   int
+		assertFunc   func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:         "Unauthorized",
+			isAuthorized: false,
+			errRetrieve:  nil,
+			errHash:      nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusUnauthorized,
+			assertFunc:   func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:         "UserNotFound",
+			isAuthorized: true,
+			errRetrieve:  db.ErrNoItem,
+			errHash:      nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusNotFound,
+			assertFunc:   assert.OnRespErr("User not found."),
+		},
+		{
+			name:         "ErrRetrieve",
+			isAuthorized: true,
+			errRetrieve:  errors.New("retrieve user failed"),
+			errHash:      nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:         "ErrHash",
+			isAuthorized: true,
+			errRetrieve:  nil,
+			errHash:      errors.New("hash failed"),
+			errUpdate:    nil,
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("hash failed"),
+		},
+		{
+			name:         "ErrUpdate",
+			isAuthorized: true,
+			errRetrieve:  nil,
+			errHash:      nil,
+			errUpdate:    errors.New("update user failed"),
+			wantStatus:   http.StatusInternalServerError,
+			assertFunc:   assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:         "OK",
+			isAuthorized: true,
+			errRetrieve:  nil,
+			errHash:      nil,
+			errUpdate:    nil,
+			wantStatus:   http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body PostResp
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+
+				// the temporary password should be hashed and the user
+				// signed out of all sessions
+				assert.Equal(t.Error, len(body.TemporaryPassword), 24)
+				assert.Equal(t.Error, hasher.plaintext, body.TemporaryPassword)
+				assert.Equal(t.Error,
+					string(userUpdater.Updated.Password), "newhash",
+				)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.isAuthorized = c.isAuthorized
+			userRetriever.Res = user
+			userRetriever.Err = c.errRetrieve
+			hasher.hash = []byte("newhash")
+			hasher.err = c.errHash
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodPost, "/", nil),
+				map[string]string{"username": "bob"},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package avatarapi contains code for responding to HTTP requests made to the
+// avatar API routes, which are used for uploading and removing the user's own
+// avatar and for serving the avatars of the members of their team.
+package avatarapi
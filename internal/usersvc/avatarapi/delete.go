@@ -0,0 +1,102 @@
+package avatarapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DeleteResp defines the body of DELETE avatar responses.
+type DeleteResp struct {
+	Error     string `json:"error,omitempty"`
+	AvatarURL string `json:"avatarURL,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the user avatar route. Deleting the avatar reverts the user to their
+// identicon.
+type DeleteHandler struct {
+	userRetriever db.Retriever[usertbl.User]
+	avatarDeleter db.Deleter
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	userRetriever db.Retriever[usertbl.User],
+	avatarDeleter db.Deleter,
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		userRetriever: userRetriever,
+		avatarDeleter: avatarDeleter,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the user avatar route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, DeleteResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// unversion the avatar's URL on the user first so that the identicon is
+	// served even if deleting the avatar fails
+	if user.AvatarHash != "" {
+		user.AvatarHash = ""
+		if err = h.userUpdater.Update(
+			r.Context(), user,
+		); errors.Is(err, db.ErrNoItem) {
+			h.writeResp(w, http.StatusNotFound, DeleteResp{
+				Error: "User not found.",
+			})
+			return
+		} else if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+	}
+
+	// delete the avatar
+	if err = h.avatarDeleter.Delete(r.Context(), user.Username); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, DeleteResp{
+		AvatarURL: avatar.URL(user.Username, ""),
+	})
+}
+
+// writeResp writes the given status and response.
+func (h DeleteHandler) writeResp(
+	w http.ResponseWriter, status int, resp DeleteResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build utest
+
+package avatarapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	avatarDeleter := &db.FakeDeleter{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(userRetriever, avatarDeleter, userUpdater, log)
+
+	for _, c := range []struct {
+		name        string
+		hash        string
+		errRetrieve error
+		errUpdate   error
+		errDelete   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "UserNotFound",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "UpdateNotFound",
+			hash:       "abc",
+			errUpdate:  db.ErrNoItem,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("User not found."),
+		},
+		{
+			name:       "ErrUpdate",
+			hash:       "abc",
+			errUpdate:  errors.New("update user failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:       "ErrDelete",
+			hash:       "abc",
+			errDelete:  errors.New("delete avatar failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("delete avatar failed"),
+		},
+		{
+			name:       "NoAvatar",
+			errUpdate:  errors.New("update user failed"),
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(DeleteResp{
+				AvatarURL: avatar.URL("bob123", ""),
+			}),
+		},
+		{
+			name:       "OK",
+			hash:       "abc",
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Error, userUpdater.Updated.Username, "bob123")
+				assert.Equal(t.Error, userUpdater.Updated.AvatarHash, "")
+				assert.OnRespBody(DeleteResp{
+					AvatarURL: avatar.URL("bob123", ""),
+				})(t, resp, nil)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = usertbl.User{
+				Username: "bob123", AvatarHash: c.hash,
+			}
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			userUpdater.Updated = usertbl.User{}
+			avatarDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+			sut.Handle(w, r, cookie.Auth{Username: "bob123"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package avatarapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// cacheControl is the Cache-Control header set on the avatars served. Avatar
+// URLs change with the avatars they point to, so they can be cached for long.
+const cacheControl = "private, max-age=86400"
+
+// GetResp defines the body of GET avatar responses that fail. Successful ones
+// carry the PNG-encoded avatar instead.
+type GetResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// avatar route of a user. Users can get the avatars of the members of their
+// own team, except for guests, who can only get their own.
+type GetHandler struct {
+	userRetriever   db.Retriever[usertbl.User]
+	avatarRetriever db.Retriever[avatartbl.Avatar]
+	log             log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	userRetriever db.Retriever[usertbl.User],
+	avatarRetriever db.Retriever[avatartbl.Avatar],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		userRetriever:   userRetriever,
+		avatarRetriever: avatarRetriever,
+		log:             log,
+	}
+}
+
+// Handle handles GET requests sent to the avatar route of a user. The users
+// who have not uploaded an avatar get their identicon.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// validate the user can see the requested user
+	username := api.PathParam(r, "username")
+	if auth.IsGuest && username != auth.Username {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	}
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if user.TeamID != auth.TeamID {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	}
+
+	// retrieve the avatar, falling back to the identicon if the user has not
+	// uploaded one
+	var img []byte
+	if user.AvatarHash != "" {
+		a, err := h.avatarRetriever.Retrieve(r.Context(), user.Username)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		img = a.Image
+	}
+	if len(img) == 0 {
+		img = avatar.Identicon(user.Username)
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	api.WriteConditional(w, r, `"`+avatar.Hash(img)+`"`, img)
+}
+
+// writeErr writes the given status and error message.
+func (h GetHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(GetResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,178 @@
+//go:build utest
+
+package avatarapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	avatarRetriever := &db.FakeRetriever[avatartbl.Avatar]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(userRetriever, avatarRetriever, log)
+
+	member := cookie.Auth{Username: "jo1234", TeamID: "acme"}
+	guest := cookie.Auth{Username: "jo1234", TeamID: "acme", IsGuest: true}
+	bob := usertbl.User{Username: "bob123", TeamID: "acme"}
+	bobWithAvatar := usertbl.User{
+		Username: "bob123", TeamID: "acme", AvatarHash: "abc",
+	}
+	uploaded := []byte("uploaded")
+	wantImage := func(want []byte) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			assert.Equal(t.Error,
+				resp.Header.Get("Content-Type"), "image/png",
+			)
+			assert.Equal(t.Error,
+				resp.Header.Get("Cache-Control"), cacheControl,
+			)
+			assert.Equal(t.Error,
+				resp.Header.Get("ETag"), `"`+avatar.Hash(want)+`"`,
+			)
+			got, err := io.ReadAll(resp.Body)
+			assert.Nil(t.Fatal, err)
+			assert.True(t.Error, bytes.Equal(got, want))
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		auth        cookie.Auth
+		username    string
+		user        usertbl.User
+		errRetrieve error
+		errAvatar   error
+		ifNoneMatch string
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "GuestOtherUser",
+			auth:       guest,
+			username:   "bob123",
+			user:       bob,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "UserNotFound",
+			auth:        member,
+			username:    "bob123",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieveUser",
+			auth:        member,
+			username:    "bob123",
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "OtherTeam",
+			auth:       member,
+			username:   "bob123",
+			user:       usertbl.User{Username: "bob123", TeamID: "other"},
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("User not found."),
+		},
+		{
+			name:       "ErrRetrieveAvatar",
+			auth:       member,
+			username:   "bob123",
+			user:       bobWithAvatar,
+			errAvatar:  errors.New("retrieve avatar failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve avatar failed"),
+		},
+		{
+			name:       "Identicon",
+			auth:       member,
+			username:   "bob123",
+			user:       bob,
+			wantStatus: http.StatusOK,
+			assertFunc: wantImage(avatar.Identicon("bob123")),
+		},
+		{
+			name:       "AvatarNotFound",
+			auth:       member,
+			username:   "bob123",
+			user:       bobWithAvatar,
+			errAvatar:  db.ErrNoItem,
+			wantStatus: http.StatusOK,
+			assertFunc: wantImage(avatar.Identicon("bob123")),
+		},
+		{
+			name:       "Uploaded",
+			auth:       member,
+			username:   "bob123",
+			user:       bobWithAvatar,
+			wantStatus: http.StatusOK,
+			assertFunc: wantImage(uploaded),
+		},
+		{
+			name:     "GuestSelf",
+			auth:     guest,
+			username: "jo1234",
+			user: usertbl.User{
+				Username: "jo1234", TeamID: "acme", IsGuest: true,
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: wantImage(avatar.Identicon("jo1234")),
+		},
+		{
+			name:        "NotModified",
+			auth:        member,
+			username:    "bob123",
+			user:        bobWithAvatar,
+			ifNoneMatch: `"` + avatar.Hash(uploaded) + `"`,
+			wantStatus:  http.StatusNotModified,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			avatarRetriever.Res = avatartbl.Avatar{
+				Username: "bob123", Image: uploaded,
+			}
+			avatarRetriever.Err = c.errAvatar
+			if c.errAvatar != nil {
+				avatarRetriever.Res = avatartbl.Avatar{}
+			}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodGet, "/", nil),
+				map[string]string{"username": c.username},
+			)
+			if c.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", c.ifNoneMatch)
+			}
+
+			sut.Handle(w, r, c.auth)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package avatarapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// maxUploadSize is the maximum size in bytes of an avatar upload request's
+// body.
+const maxUploadSize = 2 << 20
+
+// formField is the name of the multipart form field to upload the avatar in.
+const formField = "avatar"
+
+// errMsgFormat is the error message returned when the uploaded avatar cannot
+// be read.
+const errMsgFormat = "Avatar must be a PNG, JPEG, or GIF image of up to 2 MB."
+
+// PostResp defines the body of POST avatar responses.
+type PostResp struct {
+	Error     string `json:"error,omitempty"`
+	AvatarURL string `json:"avatarURL,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the user avatar route. The avatar is uploaded as a multipart form, and is
+// cropped and scaled to avatar.Size before it is stored.
+type PostHandler struct {
+	userRetriever  db.Retriever[usertbl.User]
+	avatarInserter db.Inserter[avatartbl.Avatar]
+	userUpdater    db.Updater[usertbl.User]
+	log            log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	userRetriever db.Retriever[usertbl.User],
+	avatarInserter db.Inserter[avatartbl.Avatar],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		userRetriever:  userRetriever,
+		avatarInserter: avatarInserter,
+		userUpdater:    userUpdater,
+		log:            log,
+	}
+}
+
+// Handle handles POST requests sent to the user avatar route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// read and process the uploaded image
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, _, err := r.FormFile(formField)
+	if err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{Error: errMsgFormat})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.writeResp(w, http.StatusBadRequest, PostResp{Error: errMsgFormat})
+		return
+	}
+	img, err := avatar.Process(data)
+	if errors.Is(err, avatar.ErrFormat) {
+		h.writeResp(w, http.StatusBadRequest, PostResp{Error: errMsgFormat})
+		return
+	} else if errors.Is(err, avatar.ErrDimensions) {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Error: "Avatar cannot be larger than 4096x4096 pixels.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// store the avatar, replacing the existing one if there is one
+	if err = h.avatarInserter.Insert(r.Context(), avatartbl.Avatar{
+		Username: user.Username, Image: img,
+	}); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// version the avatar's URL on the user
+	user.AvatarHash = avatar.Hash(img)
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PostResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, PostResp{
+		AvatarURL: avatar.URL(user.Username, user.AvatarHash),
+	})
+}
+
+// writeResp writes the given status and response.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
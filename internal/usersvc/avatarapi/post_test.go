@@ -0,0 +1,166 @@
+//go:build utest
+
+package avatarapi
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	avatarInserter := &db.FakeInserter[avatartbl.Avatar]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(userRetriever, avatarInserter, userUpdater, log)
+
+	var validPNG bytes.Buffer
+	_ = png.Encode(&validPNG, image.NewGray(image.Rect(0, 0, 200, 100)))
+	var widePNG bytes.Buffer
+	_ = png.Encode(&widePNG, image.NewGray(image.Rect(
+		0, 0, avatar.MaxDimension+1, 1,
+	)))
+
+	for _, c := range []struct {
+		name        string
+		field       string
+		file        []byte
+		errRetrieve error
+		errInsert   error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoFile",
+			field:      "file",
+			file:       validPNG.Bytes(),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(errMsgFormat),
+		},
+		{
+			name:       "TooBig",
+			field:      formField,
+			file:       make([]byte, maxUploadSize+1),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(errMsgFormat),
+		},
+		{
+			name:       "NotImage",
+			field:      formField,
+			file:       []byte("<svg/>"),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(errMsgFormat),
+		},
+		{
+			name:       "TooWide",
+			field:      formField,
+			file:       widePNG.Bytes(),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Avatar cannot be larger than 4096x4096 pixels.",
+			),
+		},
+		{
+			name:        "UserNotFound",
+			field:       formField,
+			file:        validPNG.Bytes(),
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			field:       formField,
+			file:        validPNG.Bytes(),
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "ErrInsert",
+			field:      formField,
+			file:       validPNG.Bytes(),
+			errInsert:  errors.New("insert avatar failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("insert avatar failed"),
+		},
+		{
+			name:       "UpdateNotFound",
+			field:      formField,
+			file:       validPNG.Bytes(),
+			errUpdate:  db.ErrNoItem,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("User not found."),
+		},
+		{
+			name:       "ErrUpdate",
+			field:      formField,
+			file:       validPNG.Bytes(),
+			errUpdate:  errors.New("update user failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:       "OK",
+			field:      formField,
+			file:       validPNG.Bytes(),
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				stored := avatarInserter.Inserted
+				assert.Equal(t.Error, stored.Username, "bob123")
+				img, err := png.Decode(bytes.NewReader(stored.Image))
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, img.Bounds().Dx(), avatar.Size)
+
+				hash := avatar.Hash(stored.Image)
+				assert.Equal(t.Error, userUpdater.Updated.AvatarHash, hash)
+				assert.OnRespBody(PostResp{
+					AvatarURL: avatar.URL("bob123", hash),
+				})(t, resp, nil)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = usertbl.User{Username: "bob123"}
+			userRetriever.Err = c.errRetrieve
+			avatarInserter.Err = c.errInsert
+			avatarInserter.Inserted = avatartbl.Avatar{}
+			userUpdater.Err = c.errUpdate
+			userUpdater.Updated = usertbl.User{}
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, err := mw.CreateFormFile(c.field, "avatar.png")
+			assert.Nil(t.Fatal, err)
+			_, err = io.Copy(fw, bytes.NewReader(c.file))
+			assert.Nil(t.Fatal, err)
+			assert.Nil(t.Fatal, mw.Close())
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", &body)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+
+			sut.Handle(w, r, cookie.Auth{Username: "bob123"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
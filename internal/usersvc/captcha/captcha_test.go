@@ -0,0 +1,109 @@
+//go:build utest
+
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSiteVerifier tests the Verify method of SiteVerifier to assert that it
+// sends the token to the siteverify endpoint and reports its verdict.
+func TestSiteVerifier(t *testing.T) {
+	var (
+		form       map[string]string
+		respStatus int
+		respBody   string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_ = r.ParseForm()
+			form = map[string]string{
+				"secret":   r.PostForm.Get("secret"),
+				"response": r.PostForm.Get("response"),
+				"remoteip": r.PostForm.Get("remoteip"),
+			}
+			w.WriteHeader(respStatus)
+			_, _ = w.Write([]byte(respBody))
+		},
+	))
+	defer srv.Close()
+
+	sut := NewSiteVerifier(srv.URL, "secretkey", srv.Client())
+
+	for _, c := range []struct {
+		name       string
+		token      string
+		respStatus int
+		respBody   string
+		wantSent   bool
+		wantErr    error
+	}{
+		{
+			name:       "NoToken",
+			token:      "",
+			respStatus: http.StatusOK,
+			respBody:   `{"success": true}`,
+			wantSent:   false,
+			wantErr:    ErrFailed,
+		},
+		{
+			name:       "ErrStatus",
+			token:      "token",
+			respStatus: http.StatusInternalServerError,
+			respBody:   "",
+			wantSent:   true,
+			wantErr:    errors.New("captcha siteverify responded 500"),
+		},
+		{
+			name:       "ErrBody",
+			token:      "token",
+			respStatus: http.StatusOK,
+			respBody:   "{",
+			wantSent:   true,
+			wantErr:    errors.New("unexpected EOF"),
+		},
+		{
+			name:       "Rejected",
+			token:      "token",
+			respStatus: http.StatusOK,
+			respBody: `{"success": false, ` +
+				`"error-codes": ["invalid-input-response"]}`,
+			wantSent: true,
+			wantErr:  ErrFailed,
+		},
+		{
+			name:       "OK",
+			token:      "token",
+			respStatus: http.StatusOK,
+			respBody:   `{"success": true}`,
+			wantSent:   true,
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			form = nil
+			respStatus = c.respStatus
+			respBody = c.respBody
+
+			err := sut.Verify(context.Background(), c.token, "1.2.3.4")
+
+			if c.wantErr == nil {
+				assert.Nil(t.Error, err)
+			} else {
+				assert.Equal(t.Error, err.Error(), c.wantErr.Error())
+			}
+			assert.Equal(t.Error, form != nil, c.wantSent)
+			if c.wantSent {
+				assert.Equal(t.Error, form["secret"], "secretkey")
+				assert.Equal(t.Error, form["response"], c.token)
+				assert.Equal(t.Error, form["remoteip"], "1.2.3.4")
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//go:build utest
+
+package captcha
+
+import "context"
+
+// FakeVerifier is a test fake for Verifier.
+type FakeVerifier struct{ Err error }
+
+// Verify discards the input parameters and returns FakeVerifier.Err.
+func (f *FakeVerifier) Verify(context.Context, string, string) error {
+	return f.Err
+}
@@ -0,0 +1,3 @@
+// Package favoritesapi contains code for responding to HTTP requests made to the
+// user favorites API route, which is used for starring and unstarring boards.
+package favoritesapi
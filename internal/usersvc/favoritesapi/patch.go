@@ -0,0 +1,127 @@
+package favoritesapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PatchReq defines the body of PATCH favorites requests.
+type PatchReq struct {
+	BoardID    string `json:"boardID"`
+	IsFavorite bool   `json:"isFavorite"`
+}
+
+// PatchResp defines the body of PATCH favorites responses.
+type PatchResp struct {
+	Error     string   `json:"error,omitempty"`
+	Favorites []string `json:"favorites,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
+// the user favorites route.
+type PatchHandler struct {
+	boardIDValidator validator.String
+	userRetriever    db.Retriever[usertbl.User]
+	userUpdater      db.Updater[usertbl.User]
+	log              log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	boardIDValidator validator.String,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		boardIDValidator: boardIDValidator,
+		userRetriever:    userRetriever,
+		userUpdater:      userUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles PATCH requests sent to the user favorites route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// decode and validate the request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeResp(w, http.StatusBadRequest, PatchResp{
+			Error: "Invalid request body.",
+		})
+		return
+	}
+	if err := h.boardIDValidator.Validate(req.BoardID); err != nil {
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		} else {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		h.writeResp(w, http.StatusBadRequest, PatchResp{Error: msg})
+		return
+	}
+
+	// retrieve the user and star or unstar the board
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PatchResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	favorites := make([]string, 0, len(user.Favorites)+1)
+	for _, id := range user.Favorites {
+		if id != req.BoardID {
+			favorites = append(favorites, id)
+		}
+	}
+	if req.IsFavorite {
+		favorites = append(favorites, req.BoardID)
+	}
+	user.Favorites = favorites
+
+	// update the user
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, PatchResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	h.writeResp(w, http.StatusOK, PatchResp{Favorites: user.Favorites})
+}
+
+// writeResp writes the given status and response body.
+func (h PatchHandler) writeResp(
+	w http.ResponseWriter, status int, resp PatchResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
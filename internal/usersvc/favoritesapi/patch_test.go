@@ -0,0 +1,172 @@
+//go:build utest
+
+package favoritesapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	boardIDValidator := &api.FakeStringValidator{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		boardIDValidator, userRetriever, userUpdater, log,
+	)
+
+	const (
+		star   = `{"boardID": "board2", "isFavorite": true}`
+		unstar = `{"boardID": "board1", "isFavorite": false}`
+	)
+
+	assertFavorites := func(
+		want ...string,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body PatchResp
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			assert.AllEqual(t.Error, body.Favorites, want)
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		body        string
+		errValidate error
+		user        usertbl.User
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "InvalidBody",
+			body:        "{",
+			errValidate: nil,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid request body."),
+		},
+		{
+			name:        "BoardIDEmpty",
+			body:        star,
+			errValidate: validator.ErrEmpty,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Board ID cannot be empty."),
+		},
+		{
+			name:        "BoardIDNotUUID",
+			body:        star,
+			errValidate: validator.ErrWrongFormat,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:        "UserNotFound",
+			body:        star,
+			errValidate: nil,
+			user:        usertbl.User{},
+			errRetrieve: db.ErrNoItem,
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			body:        star,
+			errValidate: nil,
+			user:        usertbl.User{},
+			errRetrieve: errors.New("retrieve failed"),
+			errUpdate:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "ErrUpdate",
+			body:        star,
+			errValidate: nil,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "Star",
+			body:        star,
+			errValidate: nil,
+			user:        usertbl.User{Favorites: []string{"board1"}},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  assertFavorites("board1", "board2"),
+		},
+		{
+			name:        "StarAgain",
+			body:        star,
+			errValidate: nil,
+			user: usertbl.User{
+				Favorites: []string{"board2", "board1"},
+			},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  assertFavorites("board1", "board2"),
+		},
+		{
+			name:        "Unstar",
+			body:        unstar,
+			errValidate: nil,
+			user: usertbl.User{
+				Favorites: []string{"board1", "board3"},
+			},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  assertFavorites("board3"),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			boardIDValidator.Err = c.errValidate
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
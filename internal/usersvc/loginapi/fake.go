@@ -11,3 +11,16 @@ type fakeHashComparer struct{ err error }
 
 // Compare implements the Comparator interface on fakeHashComparer.
 func (f *fakeHashComparer) Compare(_ []byte, _ string) error { return f.err }
+
+// fakeRehasher is a test fake for Rehasher.
+type fakeRehasher struct {
+	needsRehash bool
+	hash        []byte
+	err         error
+}
+
+// NeedsRehash implements the Rehasher interface on fakeRehasher.
+func (f *fakeRehasher) NeedsRehash(_ []byte) bool { return f.needsRehash }
+
+// Hash implements the Rehasher interface on fakeRehasher.
+func (f *fakeRehasher) Hash(_ string) ([]byte, error) { return f.hash, f.err }
@@ -1,57 +1,93 @@
 package loginapi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/avatar"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pwdhash"
 )
 
 // PostReq defines the body of POST login requests.
 type PostReq struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	// Username is the user's username or verified email address.
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	CaptchaToken string `json:"captchaToken"`
+}
+
+// PostResp defines the body of POST login responses.
+type PostResp struct {
+	Err  string `json:"error,omitempty"`
+	Code string `json:"code,omitempty"`
+
+	// AvatarURL is the path of the logged in user's avatar on the user
+	// service.
+	AvatarURL string `json:"avatarURL,omitempty"`
 }
 
 // PostHandler is a http.PostHandler that can be used to handle login requests.
 type PostHandler struct {
-	validator     ReqValidator
-	userRetriever db.Retriever[usertbl.User]
-	pwdComparator Comparator
-	authEncoder   cookie.Encoder[cookie.Auth]
-	log           log.Errorer
+	validator       ReqValidator
+	captchaVerifier captcha.Verifier
+	userRetriever   db.Retriever[usertbl.User]
+	emailRetriever  db.Retriever[[]usertbl.User]
+	pwdComparator   Comparator
+	pwdRehasher     Rehasher
+	authEncoder     cookie.Encoder[cookie.Auth]
+	userUpdater     db.Updater[usertbl.User]
+	clock           clock.Clock
+	log             log.Errorer
 }
 
 // NewPostHandler creates and returns a new Handler.
 func NewPostHandler(
 	validator ReqValidator,
+	captchaVerifier captcha.Verifier,
 	userRetriever db.Retriever[usertbl.User],
+	emailRetriever db.Retriever[[]usertbl.User],
 	pwdComparator Comparator,
+	pwdRehasher Rehasher,
 	encodeAuth cookie.Encoder[cookie.Auth],
+	userUpdater db.Updater[usertbl.User],
+	clock clock.Clock,
 	log log.Errorer,
 ) PostHandler {
 	return PostHandler{
-		validator:     validator,
-		userRetriever: userRetriever,
-		pwdComparator: pwdComparator,
-		authEncoder:   encodeAuth,
-		log:           log,
+		validator:       validator,
+		captchaVerifier: captchaVerifier,
+		userRetriever:   userRetriever,
+		emailRetriever:  emailRetriever,
+		pwdComparator:   pwdComparator,
+		pwdRehasher:     pwdRehasher,
+		authEncoder:     encodeAuth,
+		userUpdater:     userUpdater,
+		clock:           clock,
+		log:             log,
 	}
 }
 
 // ServeHTTP responds to requests made to the login route.
-func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
 	// Read and validate request body.
 	var req PostReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 	if ok := h.validator.Validate(req); !ok {
@@ -59,15 +95,39 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
-	// Read the user in the database who owns the username that came in the
-	// request.
-	user, err := h.userRetriever.Retrieve(r.Context(), req.Username)
-	if errors.Is(err, db.ErrNoItem) {
+	// Verify the CAPTCHA solved by the client to keep bots from guessing
+	// passwords.
+	if err := h.captchaVerifier.Verify(
+		r.Context(), req.CaptchaToken, api.RemoteIP(r),
+	); errors.Is(err, captcha.ErrFailed) {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Err:  "CAPTCHA verification failed. Please try again.",
+			Code: captcha.ErrCode,
+		})
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		h.writeResp(w, http.StatusBadGateway, PostResp{
+			Err: "CAPTCHA could not be verified. Please try again later.",
+		})
+		return
+	}
+
+	// Read the user in the database who owns the username or the verified
+	// email address that came in the request.
+	user, err := h.retrieveUser(r.Context(), req.Username)
+	if errors.Is(err, errEmailNotUnique) {
+		h.writeResp(w, http.StatusBadRequest, PostResp{
+			Err: "This email address belongs to more than one account. " +
+				"Please log in with your username.",
+		})
+		return
+	} else if errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	} else if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
@@ -75,25 +135,102 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	// of the user from the database.
 	if err = h.pwdComparator.Compare(
 		user.Password, req.Password,
-	); errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+	); errors.Is(err, pwdhash.ErrMismatch) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	} else if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
-	// encode a new auth token
-	ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
-		user.Username, user.IsAdmin, user.TeamID,
-	))
+	// disabled users are not allowed to log in
+	if user.IsDisabled {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// encode a new auth token for a new session
+	auth := cookie.NewAuth(user.Username, user.IsAdmin, user.TeamID)
+	auth.SessionID = uuid.NewString()
+	auth.IsGuest = user.IsGuest
+	ckAuth, err := h.authEncoder.Encode(r.Context(), auth)
 	if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
+		return
+	}
+
+	// upgrade the password hash if it was made with outdated parameters - the
+	// user can still log in with the old hash if this fails
+	if h.pwdRehasher.NeedsRehash(user.Password) {
+		if hash, err := h.pwdRehasher.Hash(req.Password); err != nil {
+			h.log.Error(err)
+		} else {
+			user.Password = hash
+		}
+	}
+
+	// record the session on the user so that it can be listed and revoked
+	user.StartSession(usertbl.NewSession(
+		auth.SessionID,
+		r.UserAgent(),
+		h.clock.Now().Unix(),
+		ckAuth.Expires.Unix(),
+	))
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
 	// set auth token in cookie
 	http.SetCookie(w, &ckAuth)
+
+	// issue a CSRF token for the session - requests made without one fall
+	// back to the origin check, so a failure here is only logged
+	if err = api.SetCSRF(w, ckAuth); err != nil {
+		h.log.Error(err)
+	}
+
+	h.writeResp(w, http.StatusOK, PostResp{
+		AvatarURL: avatar.URL(user.Username, user.AvatarHash),
+	})
+}
+
+// errEmailNotUnique means that the email address a user tried to log in with
+// is the verified email address of more than one user.
+var errEmailNotUnique = errors.New("email belongs to more than one user")
+
+// retrieveUser retrieves the user who owns the given username or, if it
+// contains an @, which usernames cannot, the given verified email address.
+func (h PostHandler) retrieveUser(
+	ctx context.Context, identifier string,
+) (usertbl.User, error) {
+	if !strings.Contains(identifier, "@") {
+		return h.userRetriever.Retrieve(ctx, identifier)
+	}
+
+	users, err := h.emailRetriever.Retrieve(ctx, strings.ToLower(identifier))
+	if err != nil {
+		return usertbl.User{}, err
+	}
+	switch len(users) {
+	case 0:
+		return usertbl.User{}, db.ErrNoItem
+	case 1:
+		return users[0], nil
+	default:
+		return usertbl.User{}, errEmailNotUnique
+	}
+}
+
+// writeResp writes the given status and response body.
+func (h PostHandler) writeResp(
+	w http.ResponseWriter, status int, resp PostResp,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
 }
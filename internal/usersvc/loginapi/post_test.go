@@ -3,15 +3,19 @@
 package loginapi
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
@@ -23,127 +27,431 @@ import (
 func TestPOSTHandler(t *testing.T) {
 	var (
 		validator        = &fakeReqValidator{}
+		captchaVerifier  = &captcha.FakeVerifier{}
 		userRetriever    = &db.FakeRetriever[usertbl.User]{}
+		emailRetriever   = &db.FakeRetriever[[]usertbl.User]{}
 		passwordComparer = &fakeHashComparer{}
+		passwordRehasher = &fakeRehasher{}
 		authEncoder      = &cookie.FakeEncoder[cookie.Auth]{}
+		userUpdater      = &db.FakeUpdater[usertbl.User]{}
 		log              = &log.FakeErrorer{}
 	)
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
 	sut := NewPostHandler(
-		validator, userRetriever, passwordComparer, authEncoder, log,
+		validator,
+		captchaVerifier,
+		userRetriever,
+		emailRetriever,
+		passwordComparer,
+		passwordRehasher,
+		authEncoder,
+		userUpdater,
+		clk,
+		log,
 	)
+	expires := clk.Time.Add(time.Hour).UTC()
 
 	for _, c := range []struct {
 		name             string
 		reqIsValid       bool
+		errVerifyCaptcha error
 		user             usertbl.User
 		errRetrieveUser  error
 		errCompareHash   error
+		needsRehash      bool
+		errRehash        error
 		authToken        http.Cookie
 		errGenerateToken error
+		errUpdateUser    error
 		wantStatus       int
 		assertFunc       func(*testing.T, *http.Response, []any)
 	}{
 		{
 			name:             "InvalidRequest",
 			reqIsValid:       false,
+			errVerifyCaptcha: nil,
 			user:             usertbl.User{},
 			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusBadRequest,
 			assertFunc:       func(*testing.T, *http.Response, []any) {},
 		},
+		{
+			name:             "CaptchaFailed",
+			reqIsValid:       true,
+			errVerifyCaptcha: captcha.ErrFailed,
+			user:             usertbl.User{},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
+			authToken:        http.Cookie{},
+			errGenerateToken: nil,
+			errUpdateUser:    nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&respBody,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, respBody.Code, captcha.ErrCode)
+			},
+		},
+		{
+			name:             "ErrVerifyCaptcha",
+			reqIsValid:       true,
+			errVerifyCaptcha: errors.New("siteverify unreachable"),
+			user:             usertbl.User{},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
+			authToken:        http.Cookie{},
+			errGenerateToken: nil,
+			errUpdateUser:    nil,
+			wantStatus:       http.StatusBadGateway,
+			assertFunc:       assert.OnLoggedErr("siteverify unreachable"),
+		},
 		{
 			name:             "UserNotFound",
 			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user:             usertbl.User{},
 			errRetrieveUser:  db.ErrNoItem,
 			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusBadRequest,
 			assertFunc:       func(*testing.T, *http.Response, []any) {},
 		},
 		{
 			name:             "UserSelectorError",
 			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user:             usertbl.User{},
 			errRetrieveUser:  errors.New("user selector error"),
 			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusInternalServerError,
 			assertFunc:       assert.OnLoggedErr("user selector error"),
 		},
 		{
-			name:       "WrongPassword",
-			reqIsValid: true,
+			name:             "WrongPassword",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   bcrypt.ErrMismatchedHashAndPassword,
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusBadRequest,
 			assertFunc:       func(*testing.T, *http.Response, []any) {},
 		},
 		{
-			name:       "HashComparerError",
-			reqIsValid: true,
+			name:             "HashComparerError",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   errors.New("hash comparer error"),
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusInternalServerError,
 			assertFunc:       assert.OnLoggedErr("hash comparer error"),
 		},
 		{
-			name:       "TokenGeneratorError",
-			reqIsValid: true,
+			name:             "UserDisabled",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
+			user: usertbl.User{
+				Username:   "bob123",
+				Password:   []byte("$2a$ASasdflak$kajdsfh"),
+				IsDisabled: true,
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
+			authToken:        http.Cookie{},
+			errGenerateToken: nil,
+			errUpdateUser:    nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc:       func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:             "TokenGeneratorError",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: errors.New("token generator error"),
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusInternalServerError,
 			assertFunc:       assert.OnLoggedErr("token generator error"),
 		},
 		{
-			name:       "Success",
-			reqIsValid: true,
+			name:             "ErrUpdateUser",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			errUpdateUser:    errors.New("update user failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:             "ErrRehash",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			needsRehash:      true,
+			errRehash:        errors.New("rehash failed"),
 			authToken:        http.Cookie{Name: "foo", Value: "bar"},
 			errGenerateToken: nil,
+			errUpdateUser:    nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, args []any) {
+				// the user should still be logged in with the old hash
+				assert.OnLoggedErr("rehash failed")(t, resp, args)
+				assert.Equal(t.Error,
+					string(userUpdater.Updated.Password),
+					"$2a$ASasdflak$kajdsfh",
+				)
+			},
+		},
+		{
+			name:             "Rehash",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      true,
+			errRehash:        nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			errUpdateUser:    nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					string(userUpdater.Updated.Password), "$argon2id$newhash",
+				)
+			},
+		},
+		{
+			name:             "Success",
+			reqIsValid:       true,
+			errVerifyCaptcha: nil,
+			user: usertbl.User{
+				Username:   "bob123",
+				Password:   []byte("$2a$ASasdflak$kajdsfh"),
+				AvatarHash: "abc",
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			needsRehash:      false,
+			errRehash:        nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			errUpdateUser:    nil,
 			wantStatus:       http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 				ck := resp.Cookies()[0]
 				assert.Equal(t.Error, ck.Name, "foo")
 				assert.Equal(t.Error, ck.Value, "bar")
+
+				// a CSRF token should be issued with the auth token
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[1].Name, "csrf-token")
+				assert.Equal(t.Error,
+					resp.Header.Get("X-CSRF-Token"), cks[1].Value,
+				)
+
+				// the session of the token should be recorded on the user
+				sessions := userUpdater.Updated.Sessions
+				assert.Equal(t.Fatal, len(sessions), 1)
+				assert.True(t.Error, sessions[0].ID != "")
+				assert.Equal(t.Error, sessions[0].UserAgent, "goteam-test")
+				assert.Equal(t.Error, sessions[0].ExpiresAt, expires.Unix())
+
+				// the user's avatar URL should be sent in the response
+				assert.OnRespBody(PostResp{
+					AvatarURL: "/users/bob123/avatar?v=abc",
+				})(t, resp, nil)
 			},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			validator.isValid = c.reqIsValid
+			captchaVerifier.Err = c.errVerifyCaptcha
 			userRetriever.Res = c.user
 			userRetriever.Err = c.errRetrieveUser
 			passwordComparer.err = c.errCompareHash
+			passwordRehasher.needsRehash = c.needsRehash
+			passwordRehasher.hash = []byte("$argon2id$newhash")
+			passwordRehasher.err = c.errRehash
 			authEncoder.Res = c.authToken
+			authEncoder.Res.Expires = expires
 			authEncoder.Err = c.errGenerateToken
+			userUpdater.Err = c.errUpdateUser
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/", strings.NewReader("{}"))
+			r.Header.Set("User-Agent", "goteam-test")
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
+
+// TestPOSTHandlerByEmail tests the ServeHTTP method of Handler to assert that
+// it looks users up by their verified email address when one is sent instead
+// of a username.
+func TestPOSTHandlerByEmail(t *testing.T) {
+	var (
+		userRetriever  = &db.FakeRetriever[usertbl.User]{}
+		emailRetriever = &db.FakeRetriever[[]usertbl.User]{}
+		userUpdater    = &db.FakeUpdater[usertbl.User]{}
+		log            = &log.FakeErrorer{}
+	)
+	sut := NewPostHandler(
+		&fakeReqValidator{isValid: true},
+		&captcha.FakeVerifier{},
+		userRetriever,
+		emailRetriever,
+		&fakeHashComparer{},
+		&fakeRehasher{},
+		&cookie.FakeEncoder[cookie.Auth]{},
+		userUpdater,
+		&clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		log,
+	)
+	bob := usertbl.User{
+		Username: "bob123",
+		Password: []byte("$2a$ASasdflak$kajdsfh"),
+		Email:    "bob@acme.com",
+	}
+
+	for _, c := range []struct {
+		name        string
+		identifier  string
+		users       []usertbl.User
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "ErrRetrieve",
+			identifier:  "bob@acme.com",
+			errRetrieve: errors.New("query failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("query failed"),
+		},
+		{
+			name:       "NotFound",
+			identifier: "bob@acme.com",
+			users:      []usertbl.User{},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:       "NotUnique",
+			identifier: "bob@acme.com",
+			users: []usertbl.User{
+				bob, {Username: "bob456", Email: "bob@acme.com"},
+			},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"This email address belongs to more than one account. " +
+					"Please log in with your username.",
+			),
+		},
+		{
+			name:       "Success",
+			identifier: "Bob@Acme.com",
+			users:      []usertbl.User{bob},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the email should be looked up in lower case
+				assert.Equal(t.Error, emailRetriever.Key, "bob@acme.com")
+				assert.Equal(t.Error, userUpdater.Updated.Username, "bob123")
+			},
+		},
+		{
+			name:       "Username",
+			identifier: "bob123",
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// usernames should not be looked up by email
+				assert.Equal(t.Error, userRetriever.Key, "bob123")
+				assert.Equal(t.Error, emailRetriever.Key, "")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = bob
+			userRetriever.Key = ""
+			emailRetriever.Res = c.users
+			emailRetriever.Err = c.errRetrieve
+			emailRetriever.Key = ""
+			userUpdater.Updated = usertbl.User{}
+			body, err := json.Marshal(PostReq{
+				Username: c.identifier, Password: "asdqwe123",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("", "/", strings.NewReader(string(body)))
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, cookie.Auth{})
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -10,10 +10,14 @@ type Validator struct{}
 // NewValidator creates and returns a new Validator.
 func NewValidator() Validator { return Validator{} }
 
+// maxIdentifierLen is the maximum length of the username or email address a
+// user can log in with, which is the maximum length of an email address.
+const maxIdentifierLen = 254
+
 // Validate validates the request body sent to the login route.
 func (v Validator) Validate(reqBody PostReq) bool {
 	if reqBody.Username == "" || reqBody.Password == "" {
 		return false
 	}
-	return true
+	return len(reqBody.Username) <= maxIdentifierLen
 }
@@ -3,6 +3,7 @@
 package loginapi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kxplxn/goteam/pkg/assert"
@@ -33,6 +34,22 @@ func TestValidator(t *testing.T) {
 			reqBody: PostReq{Username: "", Password: ""},
 			wantOK:  false,
 		},
+		{
+			name: "UsernameTooLong",
+			reqBody: PostReq{
+				Username: strings.Repeat("a", 243) + "@example.com",
+				Password: "asdqwe123",
+			},
+			wantOK: false,
+		},
+		{
+			name: "IsValidEmail",
+			reqBody: PostReq{
+				Username: strings.Repeat("a", 242) + "@example.com",
+				Password: "asdqwe123",
+			},
+			wantOK: true,
+		},
 		{
 			name:    "IsValid",
 			reqBody: PostReq{Username: "bob123", Password: "asdqwe123"},
@@ -42,7 +59,7 @@ func TestValidator(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			ok := sut.Validate(c.reqBody)
 
-			assert.Equal(t.Error, c.wantOK, ok)
+			assert.Equal(t.Error, ok, c.wantOK)
 		})
 	}
 }
@@ -0,0 +1,34 @@
+//go:build utest
+
+package oidc
+
+import (
+	"context"
+	"net/url"
+)
+
+// FakeAuthenticator is a test fake for Authenticator.
+type FakeAuthenticator struct {
+	Username string
+	Err      error
+
+	// Code and Nonce are the code and nonce Exchange was last called with.
+	Code  string
+	Nonce string
+}
+
+// AuthCodeURL returns a URL that holds the given state and nonce.
+func (f *FakeAuthenticator) AuthCodeURL(state, nonce string) string {
+	return "https://idp.example.com/auth?" + url.Values{
+		"state": {state}, "nonce": {nonce},
+	}.Encode()
+}
+
+// Exchange records the code and nonce and returns FakeAuthenticator.Username
+// and FakeAuthenticator.Err.
+func (f *FakeAuthenticator) Exchange(
+	_ context.Context, code, nonce string,
+) (string, error) {
+	f.Code, f.Nonce = code, nonce
+	return f.Username, f.Err
+}
@@ -0,0 +1,175 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// minRefreshInterval is how long a keySet waits before fetching the keys again
+// for a key ID it does not have, so that tokens with made up key IDs cannot be
+// used to make it hammer the provider.
+const minRefreshInterval = time.Minute
+
+// keySet holds the public keys that a provider signs its ID tokens with,
+// fetching them again when a token is signed with a key it does not have as
+// providers rotate their keys.
+type keySet struct {
+	url    string
+	clock  clock.Clock
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// newKeySet creates and returns a new keySet that fetches the keys from the
+// JWKS at the given URL.
+func newKeySet(url string, clock clock.Clock, client *http.Client) *keySet {
+	return &keySet{url: url, clock: clock, client: client}
+}
+
+// keyfunc returns the jwt.Keyfunc that looks up the key of a token by its key
+// ID, or returns the only key if the token has none.
+func (s *keySet) keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(tk *jwt.Token) (any, error) {
+		kid, _ := tk.Header["kid"].(string)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if key, ok := s.lookup(kid); ok {
+			return key, nil
+		}
+		if s.clock.Now().Sub(s.fetchedAt) < minRefreshInterval {
+			return nil, fmt.Errorf("no key with id %q", kid)
+		}
+		if err := s.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := s.lookup(kid); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("no key with id %q", kid)
+	}
+}
+
+// lookup returns the key with the given ID, or the only key if the ID is
+// empty.
+func (s *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// jwk defines the fields used from the keys in a JWKS.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch replaces the keys with the signing keys in the JWKS. The keys of types
+// that are not supported are skipped.
+func (s *keySet) fetch(ctx context.Context) error {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.url, &jwks); err != nil {
+		return err
+	}
+
+	keys := map[string]any{}
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if errors.Is(err, errKeyType) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	s.keys, s.fetchedAt = keys, s.clock.Now()
+	return nil
+}
+
+// errKeyType means that a key was of a type that is not supported.
+var errKeyType = errors.New("unsupported key type")
+
+// publicKey returns the RSA or ECDSA public key defined by the JWK.
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errKeyType
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errKeyType
+	}
+}
+
+// decodeInt decodes a base64url encoded big-endian integer.
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
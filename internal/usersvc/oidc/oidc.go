@@ -0,0 +1,281 @@
+// Package oidc contains code for logging users in with any OpenID Connect
+// provider, such as Keycloak or Auth0, that is discovered from its issuer URL.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// Names of the environment variables to load the OIDC settings from. Login
+// with OIDC is disabled when the issuer URL is unset.
+const (
+	EnvIssuerURL     = "OIDC_ISSUER_URL"
+	EnvClientID      = "OIDC_CLIENT_ID"
+	EnvClientSecret  = "OIDC_CLIENT_SECRET"
+	EnvRedirectURL   = "OIDC_REDIRECT_URL"
+	EnvUsernameClaim = "OIDC_USERNAME_CLAIM"
+	EnvTeamID        = "OIDC_TEAM_ID"
+)
+
+// DefaultUsernameClaim is the claim of the ID token that the username is read
+// from unless configured otherwise.
+const DefaultUsernameClaim = "preferred_username"
+
+// ErrInvalid means that the provider did not return a valid ID token, or that
+// the ID token had no username.
+var ErrInvalid = errors.New("invalid id token")
+
+// Config defines the settings of the OIDC provider that users log in with.
+type Config struct {
+	// IssuerURL is the URL that the provider is discovered from. It must be
+	// exactly the issuer of the provider's ID tokens.
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the URL of the callback route that the provider sends
+	// the users back to, which must be registered with the provider.
+	RedirectURL string
+
+	// UsernameClaim is the claim of the ID token that the username is read
+	// from.
+	UsernameClaim string
+
+	// TeamID is the ID of the team that the users who log in for the first
+	// time join. Each of them gets a team of their own when it is empty.
+	TeamID string
+}
+
+// ConfigFromEnv returns the Config set in the environment, or the zero Config
+// if the issuer URL is unset.
+func ConfigFromEnv() (Config, error) {
+	c := Config{
+		IssuerURL:     os.Getenv(EnvIssuerURL),
+		ClientID:      os.Getenv(EnvClientID),
+		ClientSecret:  os.Getenv(EnvClientSecret),
+		RedirectURL:   os.Getenv(EnvRedirectURL),
+		UsernameClaim: os.Getenv(EnvUsernameClaim),
+		TeamID:        os.Getenv(EnvTeamID),
+	}
+	if c.IssuerURL == "" {
+		return Config{}, nil
+	}
+	for env, v := range map[string]string{
+		EnvClientID:     c.ClientID,
+		EnvClientSecret: c.ClientSecret,
+		EnvRedirectURL:  c.RedirectURL,
+	} {
+		if v == "" {
+			return Config{}, fmt.Errorf(
+				"%s must be set when %s is", env, EnvIssuerURL,
+			)
+		}
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = DefaultUsernameClaim
+	}
+	return c, nil
+}
+
+// Enabled returns whether login with OIDC is configured.
+func (c Config) Enabled() bool { return c.IssuerURL != "" }
+
+// Authenticator describes a type that can be used to log users in with an
+// OIDC provider.
+type Authenticator interface {
+	// AuthCodeURL returns the URL of the provider to send the user to, which
+	// sends them back to the callback route with the given state and an
+	// authorization code.
+	AuthCodeURL(state, nonce string) string
+
+	// Exchange exchanges the given authorization code for an ID token that
+	// must have the given nonce, and returns the username it holds.
+	Exchange(ctx context.Context, code, nonce string) (string, error)
+}
+
+// validMethods are the algorithms that ID tokens are accepted signed with.
+// Symmetric algorithms are left out as the keys are published.
+var validMethods = []string{
+	"RS256", "RS384", "RS512", "PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// Provider is an Authenticator that uses the authorization code flow with the
+// endpoints it discovered from the issuer.
+type Provider struct {
+	cfg      Config
+	issuer   string
+	authURL  string
+	tokenURL string
+	keys     *keySet
+	clock    clock.Clock
+	client   *http.Client
+}
+
+// discovery defines the fields used from the discovery document of an issuer.
+type discovery struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+}
+
+// Discover creates and returns a new Provider, fetching its endpoints from the
+// discovery document of the issuer in the given config. The expiry of ID
+// tokens is checked against the time told by the given clock.
+func Discover(
+	ctx context.Context, cfg Config, clock clock.Clock, client *http.Client,
+) (*Provider, error) {
+	var doc discovery
+	if err := getJSON(ctx, client, strings.TrimSuffix(
+		cfg.IssuerURL, "/",
+	)+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if doc.Issuer != cfg.IssuerURL {
+		return nil, fmt.Errorf(
+			"oidc discovery returned issuer %q for %q", doc.Issuer,
+			cfg.IssuerURL,
+		)
+	}
+	if doc.AuthURL == "" || doc.TokenURL == "" || doc.JWKSURL == "" {
+		return nil, errors.New("oidc discovery returned no endpoints")
+	}
+	return &Provider{
+		cfg:      cfg,
+		issuer:   doc.Issuer,
+		authURL:  doc.AuthURL,
+		tokenURL: doc.TokenURL,
+		keys:     newKeySet(doc.JWKSURL, clock, client),
+		clock:    clock,
+		client:   client,
+	}, nil
+}
+
+// AuthCodeURL returns the authorization URL of the provider with the given
+// state and nonce.
+func (p *Provider) AuthCodeURL(state, nonce string) string {
+	sep := "?"
+	if strings.Contains(p.authURL, "?") {
+		sep = "&"
+	}
+	return p.authURL + sep + url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.cfg.ClientID},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"scope":         {"openid profile email"},
+		"state":         {state},
+		"nonce":         {nonce},
+	}.Encode()
+}
+
+// tokenResp defines the fields used from the body of token responses.
+type tokenResp struct {
+	IDToken string `json:"id_token"`
+}
+
+// Exchange exchanges the given authorization code at the token endpoint and
+// returns the username held by the ID token it responds with. It returns
+// ErrInvalid if the ID token was not valid or had no username.
+func (p *Provider) Exchange(
+	ctx context.Context, code, nonce string,
+) (string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, p.tokenURL, strings.NewReader(url.Values{
+			"grant_type":   {"authorization_code"},
+			"code":         {code},
+			"redirect_uri": {p.cfg.RedirectURL},
+		}.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(
+		url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret),
+	)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc token endpoint responded %d",
+			resp.StatusCode,
+		)
+	}
+	var body tokenResp
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	claims, err := p.verify(ctx, body.IDToken, nonce)
+	if err != nil {
+		return "", err
+	}
+	username, _ := claims[p.cfg.UsernameClaim].(string)
+	if username == "" {
+		return "", fmt.Errorf(
+			"%w: no %s claim", ErrInvalid, p.cfg.UsernameClaim,
+		)
+	}
+	return username, nil
+}
+
+// verify validates the signature of the given ID token with the provider's
+// keys and checks that it was issued by the provider to this client with the
+// given nonce, returning its claims.
+func (p *Provider) verify(
+	ctx context.Context, idToken, nonce string,
+) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(
+		idToken, &claims, p.keys.keyfunc(ctx),
+		jwt.WithValidMethods(validMethods), jwt.WithoutClaimsValidation(),
+	); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	switch {
+	case !claims.VerifyIssuer(p.issuer, true):
+		return nil, fmt.Errorf("%w: wrong issuer", ErrInvalid)
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return nil, fmt.Errorf("%w: wrong audience", ErrInvalid)
+	case !claims.VerifyExpiresAt(p.clock.Now().Unix(), true):
+		return nil, fmt.Errorf("%w: expired", ErrInvalid)
+	case claims["nonce"] != nonce:
+		return nil, fmt.Errorf("%w: wrong nonce", ErrInvalid)
+	}
+	return claims, nil
+}
+
+// getJSON decodes the JSON body of a GET request to the given URL into v.
+func getJSON(
+	ctx context.Context, client *http.Client, url string, v any,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
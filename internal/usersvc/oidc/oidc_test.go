@@ -0,0 +1,324 @@
+//go:build utest
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// b64 base64url encodes the big-endian bytes of the given integer.
+func b64(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// TestProvider tests the Discover function and the Exchange method of
+// Provider to assert that it exchanges codes for the usernames in valid ID
+// tokens and rejects the ones that are not valid.
+func TestProvider(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t.Fatal, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+
+	var (
+		srv      *httptest.Server
+		idToken  string
+		form     url.Values
+		user     string
+		pwd      string
+		jwksHits int
+	)
+	srv = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/.well-known/openid-configuration":
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"issuer":                 srv.URL,
+					"authorization_endpoint": srv.URL + "/auth",
+					"token_endpoint":         srv.URL + "/token",
+					"jwks_uri":               srv.URL + "/jwks",
+				})
+			case "/jwks":
+				jwksHits++
+				_ = json.NewEncoder(w).Encode(map[string]any{"keys": []jwk{
+					{
+						Kty: "RSA", Kid: "rsa", Use: "sig",
+						N: b64(rsaKey.N), E: b64(big.NewInt(int64(rsaKey.E))),
+					},
+					{
+						Kty: "EC", Kid: "ec", Crv: "P-256",
+						X: b64(ecKey.X), Y: b64(ecKey.Y),
+					},
+					{Kty: "oct", Kid: "hmac"},
+				}})
+			case "/token":
+				_ = r.ParseForm()
+				form = r.PostForm
+				user, pwd, _ = r.BasicAuth()
+				if form.Get("code") == "bad" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(tokenResp{IDToken: idToken})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		},
+	))
+	defer srv.Close()
+
+	clk := &clock.Fake{Time: time.Now()}
+	cfg := Config{
+		IssuerURL:     srv.URL,
+		ClientID:      "goteam",
+		ClientSecret:  "s3cret",
+		RedirectURL:   "https://api.goteam.io/oidc/callback",
+		UsernameClaim: "preferred_username",
+	}
+	sut, err := Discover(context.Background(), cfg, clk, srv.Client())
+	assert.Nil(t.Fatal, err)
+
+	sign := func(
+		method jwt.SigningMethod, kid string, key any,
+		edit func(jwt.MapClaims),
+	) string {
+		claims := jwt.MapClaims{
+			"iss":                srv.URL,
+			"aud":                []string{"other", "goteam"},
+			"exp":                clk.Now().Add(time.Minute).Unix(),
+			"nonce":              "n0nce",
+			"preferred_username": "bob123",
+		}
+		if edit != nil {
+			edit(claims)
+		}
+		tk := jwt.NewWithClaims(method, claims)
+		tk.Header["kid"] = kid
+		s, err := tk.SignedString(key)
+		assert.Nil(t.Fatal, err)
+		return s
+	}
+
+	for _, c := range []struct {
+		name    string
+		code    string
+		idToken string
+		wantErr bool
+	}{
+		{
+			name:    "RSA",
+			code:    "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey, nil),
+		},
+		{
+			name:    "EC",
+			code:    "c0de",
+			idToken: sign(jwt.SigningMethodES256, "ec", ecKey, nil),
+		},
+		{
+			name:    "TokenEndpointErr",
+			code:    "bad",
+			wantErr: true,
+		},
+		{
+			name:    "UnknownKey",
+			code:    "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "other", otherKey, nil),
+			wantErr: true,
+		},
+		{
+			name:    "WrongKey",
+			code:    "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", otherKey, nil),
+			wantErr: true,
+		},
+		{
+			name: "HMAC",
+			code: "c0de",
+			idToken: sign(
+				jwt.SigningMethodHS256, "hmac", []byte("s3cret"), nil,
+			),
+			wantErr: true,
+		},
+		{
+			name: "WrongIssuer",
+			code: "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey,
+				func(c jwt.MapClaims) { c["iss"] = "https://evil.com" },
+			),
+			wantErr: true,
+		},
+		{
+			name: "WrongAudience",
+			code: "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey,
+				func(c jwt.MapClaims) { c["aud"] = "other" },
+			),
+			wantErr: true,
+		},
+		{
+			name: "Expired",
+			code: "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey,
+				func(c jwt.MapClaims) {
+					c["exp"] = clk.Now().Add(-time.Second).Unix()
+				},
+			),
+			wantErr: true,
+		},
+		{
+			name: "WrongNonce",
+			code: "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey,
+				func(c jwt.MapClaims) { c["nonce"] = "other" },
+			),
+			wantErr: true,
+		},
+		{
+			name: "NoUsername",
+			code: "c0de",
+			idToken: sign(jwt.SigningMethodRS256, "rsa", rsaKey,
+				func(c jwt.MapClaims) { delete(c, "preferred_username") },
+			),
+			wantErr: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idToken = c.idToken
+
+			username, err := sut.Exchange(
+				context.Background(), c.code, "n0nce",
+			)
+
+			assert.Equal(t.Error, form.Get("code"), c.code)
+			assert.Equal(t.Error, form.Get("redirect_uri"), cfg.RedirectURL)
+			assert.Equal(t.Error, user, "goteam")
+			assert.Equal(t.Error, pwd, "s3cret")
+			if c.wantErr {
+				assert.True(t.Error, err != nil)
+				return
+			}
+			assert.Nil(t.Error, err)
+			assert.Equal(t.Error, username, "bob123")
+		})
+	}
+
+	// the keys are fetched once, and again for unknown keys only after a
+	// minute has passed
+	assert.Equal(t.Error, jwksHits, 1)
+	clk.Advance(minRefreshInterval)
+	idToken = sign(jwt.SigningMethodRS256, "other", otherKey, nil)
+	_, err = sut.Exchange(context.Background(), "c0de", "n0nce")
+	assert.True(t.Error, err != nil)
+	assert.Equal(t.Error, jwksHits, 2)
+}
+
+// TestDiscover tests the Discover function to assert that it rejects issuers
+// whose discovery documents are for another issuer.
+func TestDiscover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"issuer":"https://evil.com",` +
+				`"authorization_endpoint":"a","token_endpoint":"t",` +
+				`"jwks_uri":"j"}`))
+		},
+	))
+	defer srv.Close()
+
+	_, err := Discover(
+		context.Background(), Config{IssuerURL: srv.URL}, clock.System{},
+		srv.Client(),
+	)
+
+	assert.True(t.Error, err != nil)
+}
+
+// TestAuthCodeURL tests the AuthCodeURL method of Provider to assert that it
+// returns the authorization URL with the parameters of the code flow.
+func TestAuthCodeURL(t *testing.T) {
+	sut := &Provider{
+		cfg: Config{
+			ClientID:    "goteam",
+			RedirectURL: "https://api.goteam.io/oidc/callback",
+		},
+		authURL: "https://idp.example.com/auth?tenant=acme",
+	}
+
+	u, err := url.Parse(sut.AuthCodeURL("st4te", "n0nce"))
+
+	assert.Nil(t.Fatal, err)
+	q := u.Query()
+	assert.Equal(t.Error, u.Host, "idp.example.com")
+	assert.Equal(t.Error, q.Get("tenant"), "acme")
+	assert.Equal(t.Error, q.Get("response_type"), "code")
+	assert.Equal(t.Error, q.Get("client_id"), "goteam")
+	assert.Equal(t.Error,
+		q.Get("redirect_uri"), "https://api.goteam.io/oidc/callback",
+	)
+	assert.Equal(t.Error, q.Get("scope"), "openid profile email")
+	assert.Equal(t.Error, q.Get("state"), "st4te")
+	assert.Equal(t.Error, q.Get("nonce"), "n0nce")
+}
+
+// TestConfigFromEnv tests the ConfigFromEnv function to assert that it
+// disables OIDC when no issuer is set, requires the client settings when one
+// is, and defaults the username claim.
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv(EnvIssuerURL, "")
+		t.Setenv(EnvClientID, "goteam")
+
+		cfg, err := ConfigFromEnv()
+
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, !cfg.Enabled())
+	})
+
+	t.Run("NoSecret", func(t *testing.T) {
+		t.Setenv(EnvIssuerURL, "https://idp.example.com")
+		t.Setenv(EnvClientID, "goteam")
+		t.Setenv(EnvClientSecret, "")
+		t.Setenv(EnvRedirectURL, "https://api.goteam.io/oidc/callback")
+
+		_, err := ConfigFromEnv()
+
+		assert.Equal(t.Error, err.Error(),
+			"OIDC_CLIENT_SECRET must be set when OIDC_ISSUER_URL is",
+		)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Setenv(EnvIssuerURL, "https://idp.example.com")
+		t.Setenv(EnvClientID, "goteam")
+		t.Setenv(EnvClientSecret, "s3cret")
+		t.Setenv(EnvRedirectURL, "https://api.goteam.io/oidc/callback")
+		t.Setenv(EnvUsernameClaim, "")
+		t.Setenv(EnvTeamID, "acme")
+
+		cfg, err := ConfigFromEnv()
+
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, cfg.Enabled())
+		assert.Equal(t.Error, cfg.UsernameClaim, DefaultUsernameClaim)
+		assert.Equal(t.Error, cfg.TeamID, "acme")
+	})
+}
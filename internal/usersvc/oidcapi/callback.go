@@ -0,0 +1,230 @@
+package oidcapi
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/internal/usersvc/oidc"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// UsernameValidator describes a type that can be used to validate the
+// usernames that users are registered with.
+type UsernameValidator interface{ Validate(string) []string }
+
+// CallbackHandler is an api.MethodHandler that can be used to handle GET OIDC
+// callback requests, which the provider sends the user back with. It logs the
+// user in, registering them first if they log in for the first time.
+type CallbackHandler struct {
+	authenticator     oidc.Authenticator
+	usernameValidator UsernameValidator
+	teamID            string
+	teamRetriever     db.Retriever[teamtbl.Team]
+	teamUpdater       db.Updater[teamtbl.Team]
+	quota             quota.Quota
+	userRetriever     db.Retriever[usertbl.User]
+	userInserter      db.Inserter[usertbl.User]
+	authEncoder       cookie.Encoder[cookie.Auth]
+	userUpdater       db.Updater[usertbl.User]
+	cookieCfg         cookie.Config
+	clientOrigin      string
+	clock             clock.Clock
+	log               log.Errorer
+}
+
+// NewCallbackHandler creates and returns a new CallbackHandler. The users who
+// log in for the first time join the team with the given ID, or get a team of
+// their own if it is empty. Users are redirected to the given client origin
+// once they are logged in.
+func NewCallbackHandler(
+	authenticator oidc.Authenticator,
+	usernameValidator UsernameValidator,
+	teamID string,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	quota quota.Quota,
+	userRetriever db.Retriever[usertbl.User],
+	userInserter db.Inserter[usertbl.User],
+	authEncoder cookie.Encoder[cookie.Auth],
+	userUpdater db.Updater[usertbl.User],
+	cookieCfg cookie.Config,
+	clientOrigin string,
+	clock clock.Clock,
+	log log.Errorer,
+) CallbackHandler {
+	return CallbackHandler{
+		authenticator:     authenticator,
+		usernameValidator: usernameValidator,
+		teamID:            teamID,
+		teamRetriever:     teamRetriever,
+		teamUpdater:       teamUpdater,
+		quota:             quota,
+		userRetriever:     userRetriever,
+		userInserter:      userInserter,
+		authEncoder:       authEncoder,
+		userUpdater:       userUpdater,
+		cookieCfg:         cookieCfg,
+		clientOrigin:      clientOrigin,
+		clock:             clock,
+		log:               log,
+	}
+}
+
+// Handle handles GET OIDC callback requests.
+func (h CallbackHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	// the state cookie is only good for one callback
+	expired := newStateCookie(h.cookieCfg, "", "")
+	expired.MaxAge = -1
+	http.SetCookie(w, &expired)
+
+	// check the callback is for the login started by this browser, and that
+	// the user did not cancel it at the provider
+	ck, err := r.Cookie(stateName)
+	if err != nil {
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	state, nonce, _ := strings.Cut(ck.Value, ".")
+	query := r.URL.Query()
+	if state == "" || subtle.ConstantTimeCompare(
+		[]byte(state), []byte(query.Get("state")),
+	) != 1 || query.Get("error") != "" {
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// exchange the code for the username held by the user's ID token
+	username, err := h.authenticator.Exchange(
+		r.Context(), query.Get("code"), nonce,
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	if errs := h.usernameValidator.Validate(username); len(errs) > 0 {
+		h.log.Error("oidc username", username, "was invalid:", errs)
+		h.fail(w, r, ErrCodeUsername)
+		return
+	}
+
+	// register the user if they are logging in for the first time
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		var errCode string
+		if user, errCode = h.register(r, username); errCode != "" {
+			h.fail(w, r, errCode)
+			return
+		}
+	} else if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// disabled users are not allowed to log in
+	if user.IsDisabled {
+		h.fail(w, r, ErrCodeDisabled)
+		return
+	}
+
+	// encode a new auth token for a new session and record the session on
+	// the user so that it can be listed and revoked
+	auth := cookie.NewAuth(user.Username, user.IsAdmin, user.TeamID)
+	auth.SessionID = uuid.NewString()
+	auth.IsGuest = user.IsGuest
+	ckAuth, err := h.authEncoder.Encode(r.Context(), auth)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	user.StartSession(usertbl.NewSession(
+		auth.SessionID,
+		r.UserAgent(),
+		h.clock.Now().Unix(),
+		ckAuth.Expires.Unix(),
+	))
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// set auth token in cookie
+	http.SetCookie(w, &ckAuth)
+
+	// issue a CSRF token for the session - requests made without one fall
+	// back to the origin check, so a failure here is only logged
+	if err = api.SetCSRF(w, ckAuth); err != nil {
+		h.log.Error(err)
+	}
+
+	http.Redirect(w, r, h.clientOrigin, http.StatusSeeOther)
+}
+
+// register adds the user with the given username to the configured team, or
+// to a team of their own if none is configured, and inserts them into the
+// user table. The user has no password, so they can only log in with the
+// provider unless one is set for them. It returns the error code to fail the
+// login with if the user could not be registered.
+func (h CallbackHandler) register(
+	r *http.Request, username string,
+) (usertbl.User, string) {
+	teamID, isAdmin := username, true
+	if h.teamID != "" {
+		teamID, isAdmin = h.teamID, false
+
+		team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+		if err != nil {
+			h.log.Error(err)
+			return usertbl.User{}, ErrCodeFailed
+		}
+
+		// a user who is already a member is one whose registration failed
+		// after they joined the team
+		if !slices.Contains(team.Members, username) {
+			if !h.quota.Override(team.Quota).AllowsMember(
+				len(team.Members),
+			) {
+				return usertbl.User{}, ErrCodeTeamFull
+			}
+			team.Members = append(team.Members, username)
+			if err = h.teamUpdater.Update(r.Context(), team); err != nil {
+				h.log.Error(err)
+				return usertbl.User{}, ErrCodeFailed
+			}
+		}
+	}
+
+	user := usertbl.NewUser(username, nil, isAdmin, teamID)
+	if err := h.userInserter.Insert(r.Context(), user); err != nil {
+		h.log.Error(err)
+		return usertbl.User{}, ErrCodeFailed
+	}
+	return user, ""
+}
+
+// fail redirects the user to the client's login page with the given error
+// code.
+func (h CallbackHandler) fail(
+	w http.ResponseWriter, r *http.Request, errCode string,
+) {
+	http.Redirect(
+		w, r, loginPageURL(h.clientOrigin, errCode), http.StatusSeeOther,
+	)
+}
@@ -0,0 +1,286 @@
+//go:build utest
+
+package oidcapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/oidc"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// TestCallbackHandler tests the Handle method of CallbackHandler to assert
+// that it logs in the user whose username the provider returns, registering
+// them first if they log in for the first time, and sends them back to the
+// client's login page with an error code when it fails.
+func TestCallbackHandler(t *testing.T) {
+	var (
+		authenticator     = &oidc.FakeAuthenticator{}
+		usernameValidator = &fakeUsernameValidator{}
+		teamRetriever     = &db.FakeRetriever[teamtbl.Team]{}
+		teamUpdater       = &db.FakeUpdater[teamtbl.Team]{}
+		userRetriever     = &db.FakeRetriever[usertbl.User]{}
+		userInserter      = &db.FakeInserter[usertbl.User]{}
+		authEncoder       = &cookie.FakeEncoder[cookie.Auth]{}
+		userUpdater       = &db.FakeUpdater[usertbl.User]{}
+		log               = &log.FakeErrorer{}
+	)
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	authToken := http.Cookie{
+		Name: cookie.AuthName, Value: "t0ken", Expires: clk.Time.Add(time.Hour),
+	}
+	const origin = "https://goteam.io"
+	fail := func(code string) string { return loginPageURL(origin, code) }
+
+	for _, c := range []struct {
+		name            string
+		stateCookie     string
+		query           string
+		teamID          string
+		errExchange     error
+		errsValidate    []string
+		user            usertbl.User
+		errRetrieveUser error
+		team            teamtbl.Team
+		errRetrieveTeam error
+		errUpdateTeam   error
+		errInsertUser   error
+		errEncodeAuth   error
+		errUpdateUser   error
+		wantLocation    string
+		assertFunc      func(*testing.T, *http.Response)
+	}{
+		{
+			name:         "NoStateCookie",
+			query:        "?state=st4te&code=c0de",
+			wantLocation: fail(ErrCodeFailed),
+		},
+		{
+			name:         "WrongState",
+			stateCookie:  "st4te.n0nce",
+			query:        "?state=other&code=c0de",
+			wantLocation: fail(ErrCodeFailed),
+		},
+		{
+			name:         "Cancelled",
+			stateCookie:  "st4te.n0nce",
+			query:        "?state=st4te&error=access_denied",
+			wantLocation: fail(ErrCodeFailed),
+		},
+		{
+			name:         "ErrExchange",
+			stateCookie:  "st4te.n0nce",
+			query:        "?state=st4te&code=c0de",
+			errExchange:  oidc.ErrInvalid,
+			wantLocation: fail(ErrCodeFailed),
+			assertFunc: func(t *testing.T, _ *http.Response) {
+				assert.ErrIs(t.Error, log.Args[0].(error), oidc.ErrInvalid)
+			},
+		},
+		{
+			name:         "UsernameInvalid",
+			stateCookie:  "st4te.n0nce",
+			query:        "?state=st4te&code=c0de",
+			errsValidate: []string{"Username cannot be empty."},
+			wantLocation: fail(ErrCodeUsername),
+		},
+		{
+			name:            "ErrRetrieveUser",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			errRetrieveUser: errors.New("retrieve user failed"),
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:         "Disabled",
+			stateCookie:  "st4te.n0nce",
+			query:        "?state=st4te&code=c0de",
+			user:         usertbl.User{Username: "bob123", IsDisabled: true},
+			wantLocation: fail(ErrCodeDisabled),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			teamID:          "acme",
+			errRetrieveUser: db.ErrNoItem,
+			errRetrieveTeam: db.ErrNoItem,
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:            "TeamFull",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			teamID:          "acme",
+			errRetrieveUser: db.ErrNoItem,
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"a", "b"},
+			},
+			wantLocation: fail(ErrCodeTeamFull),
+		},
+		{
+			name:            "ErrUpdateTeam",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			teamID:          "acme",
+			errRetrieveUser: db.ErrNoItem,
+			team:            teamtbl.Team{ID: "acme", Members: []string{"a"}},
+			errUpdateTeam:   db.ErrConflict,
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:            "ErrInsertUser",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			errRetrieveUser: db.ErrNoItem,
+			errInsertUser:   db.ErrDupKey,
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:          "ErrEncodeAuth",
+			stateCookie:   "st4te.n0nce",
+			query:         "?state=st4te&code=c0de",
+			user:          usertbl.User{Username: "bob123"},
+			errEncodeAuth: errors.New("encode failed"),
+			wantLocation:  fail(ErrCodeFailed),
+		},
+		{
+			name:          "ErrUpdateUser",
+			stateCookie:   "st4te.n0nce",
+			query:         "?state=st4te&code=c0de",
+			user:          usertbl.User{Username: "bob123"},
+			errUpdateUser: errors.New("update user failed"),
+			wantLocation:  fail(ErrCodeFailed),
+		},
+		{
+			name:        "LoggedIn",
+			stateCookie: "st4te.n0nce",
+			query:       "?state=st4te&code=c0de",
+			user: usertbl.User{
+				Username: "bob123", TeamID: "acme", Password: []byte("hash"),
+			},
+			wantLocation: origin,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t.Error, authenticator.Code, "c0de")
+				assert.Equal(t.Error, authenticator.Nonce, "n0nce")
+				assert.Equal(t.Error, userInserter.Inserted.Username, "")
+				assert.Equal(t.Error,
+					string(userUpdater.Updated.Password), "hash",
+				)
+				assert.Equal(t.Fatal, len(userUpdater.Updated.Sessions), 1)
+				assert.Equal(t.Error,
+					userUpdater.Updated.Sessions[0].ExpiresAt,
+					authToken.Expires.Unix(),
+				)
+			},
+		},
+		{
+			name:            "RegisteredOwnTeam",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			errRetrieveUser: db.ErrNoItem,
+			wantLocation:    origin,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t.Error, userInserter.Inserted.Username, "bob123")
+				assert.Equal(t.Error, userInserter.Inserted.TeamID, "bob123")
+				assert.True(t.Error, userInserter.Inserted.IsAdmin)
+				assert.Equal(t.Error,
+					len(userInserter.Inserted.Password), 0,
+				)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 1)
+			},
+		},
+		{
+			name:            "RegisteredJoinedTeam",
+			stateCookie:     "st4te.n0nce",
+			query:           "?state=st4te&code=c0de",
+			teamID:          "acme",
+			errRetrieveUser: db.ErrNoItem,
+			team:            teamtbl.Team{ID: "acme", Members: []string{"a"}},
+			wantLocation:    origin,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.Members, []string{"a", "bob123"},
+				)
+				assert.Equal(t.Error, userInserter.Inserted.TeamID, "acme")
+				assert.True(t.Error, !userInserter.Inserted.IsAdmin)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authenticator.Username = "bob123"
+			authenticator.Err = c.errExchange
+			usernameValidator.errs = c.errsValidate
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			teamUpdater.Err = c.errUpdateTeam
+			teamUpdater.Updated = teamtbl.Team{}
+			userInserter.Err = c.errInsertUser
+			userInserter.Inserted = usertbl.User{}
+			authEncoder.Res = authToken
+			authEncoder.Err = c.errEncodeAuth
+			userUpdater.Err = c.errUpdateUser
+			sut := NewCallbackHandler(
+				authenticator,
+				usernameValidator,
+				c.teamID,
+				teamRetriever,
+				teamUpdater,
+				quota.Quota{MaxMembers: 2},
+				userRetriever,
+				userInserter,
+				authEncoder,
+				userUpdater,
+				cookie.DefaultConfig(),
+				origin,
+				clk,
+				log,
+			)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodGet, "/oidc/callback"+c.query, nil,
+			)
+			if c.stateCookie != "" {
+				r.AddCookie(&http.Cookie{
+					Name: stateName, Value: c.stateCookie,
+				})
+			}
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, http.StatusSeeOther)
+			assert.Equal(t.Error, resp.Header.Get("Location"), c.wantLocation)
+
+			// the state cookie is always cleared, and the auth cookie is
+			// only set once the user is logged in
+			var gotAuth bool
+			for _, ck := range resp.Cookies() {
+				if ck.Name == stateName {
+					assert.True(t.Error, ck.MaxAge < 0)
+				}
+				if ck.Name == cookie.AuthName {
+					gotAuth = true
+				}
+			}
+			assert.Equal(t.Error, gotAuth, c.wantLocation == origin)
+
+			if c.assertFunc != nil {
+				c.assertFunc(t, resp)
+			}
+		})
+	}
+}
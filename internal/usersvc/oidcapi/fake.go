@@ -0,0 +1,10 @@
+//go:build utest
+
+package oidcapi
+
+// fakeUsernameValidator is a test fake for UsernameValidator.
+type fakeUsernameValidator struct{ errs []string }
+
+// Validate discards the input parameters and returns
+// fakeUsernameValidator.errs.
+func (f *fakeUsernameValidator) Validate(string) []string { return f.errs }
@@ -0,0 +1,53 @@
+package oidcapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/internal/usersvc/oidc"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// LoginHandler is an api.MethodHandler that can be used to handle GET OIDC
+// login requests, which send the user to the provider to log in.
+type LoginHandler struct {
+	authenticator oidc.Authenticator
+	cookieCfg     cookie.Config
+	log           log.Errorer
+}
+
+// NewLoginHandler creates and returns a new LoginHandler.
+func NewLoginHandler(
+	authenticator oidc.Authenticator, cookieCfg cookie.Config, log log.Errorer,
+) LoginHandler {
+	return LoginHandler{
+		authenticator: authenticator, cookieCfg: cookieCfg, log: log,
+	}
+}
+
+// Handle handles GET OIDC login requests.
+func (h LoginHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	// generate the state that ties the callback to this browser and the nonce
+	// that ties the ID token to this login
+	state, err := randomString()
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(api.ErrStatus(err))
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(api.ErrStatus(err))
+		return
+	}
+
+	ck := newStateCookie(h.cookieCfg, state, nonce)
+	http.SetCookie(w, &ck)
+	http.Redirect(
+		w, r, h.authenticator.AuthCodeURL(state, nonce), http.StatusFound,
+	)
+}
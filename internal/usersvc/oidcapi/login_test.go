@@ -0,0 +1,45 @@
+//go:build utest
+
+package oidcapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/usersvc/oidc"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestLoginHandler tests the Handle method of LoginHandler to assert that it
+// sends the user to the provider with the state and nonce that it keeps in the
+// state cookie.
+func TestLoginHandler(t *testing.T) {
+	sut := NewLoginHandler(
+		&oidc.FakeAuthenticator{}, cookie.DefaultConfig(), &log.FakeErrorer{},
+	)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/oidc/login", nil)
+
+	sut.Handle(w, r, cookie.Auth{})
+
+	resp := w.Result()
+	assert.Equal(t.Fatal, resp.StatusCode, http.StatusFound)
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, loc.Host, "idp.example.com")
+
+	cks := resp.Cookies()
+	assert.Equal(t.Fatal, len(cks), 1)
+	assert.Equal(t.Error, cks[0].Name, stateName)
+	assert.True(t.Error, cks[0].HttpOnly)
+	assert.Equal(t.Error, cks[0].SameSite, http.SameSiteLaxMode)
+	state, nonce, _ := strings.Cut(cks[0].Value, ".")
+	assert.True(t.Error, state != "" && nonce != "" && state != nonce)
+	assert.Equal(t.Error, loc.Query().Get("state"), state)
+	assert.Equal(t.Error, loc.Query().Get("nonce"), nonce)
+}
@@ -0,0 +1,64 @@
+// Package oidcapi contains code for responding to HTTP requests made to the
+// OIDC API routes, which are used for logging in a user with an OpenID Connect
+// provider.
+package oidcapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+// stateName is the name of the cookie that holds the state and nonce of a
+// login between the login and callback routes.
+const stateName = "oidc-state"
+
+// stateMaxAge is how long a user has to log in with the provider.
+const stateMaxAge = 10 * time.Minute
+
+// The codes set on the error query parameter of the client's login page when
+// a login fails, so that the client can tell the user why.
+const (
+	ErrCodeFailed   = "ssoFailed"
+	ErrCodeUsername = "ssoUsername"
+	ErrCodeDisabled = "ssoDisabled"
+	ErrCodeTeamFull = "ssoTeamFull"
+)
+
+// newStateCookie returns the cookie that holds the given state and nonce.
+// It is sent on the provider's redirect to the callback route as that is a
+// top-level navigation, even though the provider is on another site.
+func newStateCookie(cfg cookie.Config, state, nonce string) http.Cookie {
+	return http.Cookie{
+		Name:     stateName,
+		Value:    state + "." + nonce,
+		Domain:   cfg.Domain,
+		Path:     "/",
+		MaxAge:   int(stateMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   cfg.Secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// randomString returns a random URL-safe string.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// loginPageURL returns the URL of the client's login page with the given
+// error code.
+func loginPageURL(clientOrigin, errCode string) string {
+	return strings.TrimSuffix(clientOrigin, "/") + "/login?" + url.Values{
+		"error": {errCode},
+	}.Encode()
+}
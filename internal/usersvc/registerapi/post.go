@@ -2,23 +2,35 @@ package registerapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 // PostReq defines the body of POST register requests.
 type PostReq struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	CaptchaToken string `json:"captchaToken"`
 }
 
 // PostResp defines the body of POST register responses.
 type PostResp struct {
 	Err            string         `json:"error,omitempty"`
+	Code           string         `json:"code,omitempty"`
 	ValidationErrs ValidationErrs `json:"validationErrors,omitempty"`
 }
 
@@ -36,43 +48,70 @@ func (e ValidationErrs) Any() bool {
 // PostHandler is a api.MethodHandler that can be used to handle POST register
 // requests.
 type PostHandler struct {
-	reqValidator  ReqValidator
-	hasher        Hasher
-	inviteDecoder cookie.StringDecoder[cookie.Invite]
-	userInserter  db.Inserter[usertbl.User]
-	authEncoder   cookie.Encoder[cookie.Auth]
-	log           log.Errorer
+	reqValidator    ReqValidator
+	captchaVerifier captcha.Verifier
+	hasher          Hasher
+	inviteDecoder   cookie.StringDecoder[cookie.Invite]
+	teamRetriever   db.Retriever[teamtbl.Team]
+	teamUpdater     db.Updater[teamtbl.Team]
+	quota           quota.Quota
+	userRetriever   db.Retriever[usertbl.User]
+	userInserter    db.Inserter[usertbl.User]
+	authEncoder     cookie.Encoder[cookie.Auth]
+	userUpdater     db.Updater[usertbl.User]
+	clock           clock.Clock
+	log             log.Errorer
 }
 
 // NewPostHandler creates and returns a new HandlerPost.
 func NewPostHandler(
 	userValidator ReqValidator,
+	captchaVerifier captcha.Verifier,
 	inviteDecoder cookie.StringDecoder[cookie.Invite],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	quota quota.Quota,
 	hasher Hasher,
+	userRetriever db.Retriever[usertbl.User],
 	userInserter db.Inserter[usertbl.User],
 	authEncoder cookie.Encoder[cookie.Auth],
+	userUpdater db.Updater[usertbl.User],
+	clock clock.Clock,
 	log log.Errorer,
 ) PostHandler {
 	return PostHandler{
-		reqValidator:  userValidator,
-		hasher:        hasher,
-		inviteDecoder: inviteDecoder,
-		userInserter:  userInserter,
-		authEncoder:   authEncoder,
-		log:           log,
+		reqValidator:    userValidator,
+		captchaVerifier: captchaVerifier,
+		hasher:          hasher,
+		inviteDecoder:   inviteDecoder,
+		teamRetriever:   teamRetriever,
+		teamUpdater:     teamUpdater,
+		quota:           quota,
+		userRetriever:   userRetriever,
+		userInserter:    userInserter,
+		authEncoder:     authEncoder,
+		userUpdater:     userUpdater,
+		clock:           clock,
+		log:             log,
 	}
 }
 
 // ServeHTTP responds to requests made to the register route.
-func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
 	// decode request
 	var req PostReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
+	// fold the compatibility forms of letters and digits into the plain ones
+	// before validating so that they cannot be used to register lookalikes
+	req.Username = normalizeUsername(req.Username)
+
 	// validate request
 	vdtErrs := h.reqValidator.Validate(req)
 	if vdtErrs.Any() {
@@ -80,27 +119,51 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		if err := json.NewEncoder(w).Encode(
 			PostResp{ValidationErrs: vdtErrs},
 		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// verify the CAPTCHA solved by the client to keep bots from signing up
+	if err := h.captchaVerifier.Verify(
+		r.Context(), req.CaptchaToken, api.RemoteIP(r),
+	); errors.Is(err, captcha.ErrFailed) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Err:  "CAPTCHA verification failed. Please try again.",
+			Code: captcha.ErrCode,
+		}); err != nil {
 			h.log.Error(err)
 		}
 		return
+	} else if err != nil {
+		h.log.Error(err)
+		h.writeErr(w, http.StatusBadGateway,
+			"CAPTCHA could not be verified. Please try again later.",
+		)
+		return
 	}
 
 	// determine teamID and isAdmin based on invite token.
 	invCode := r.URL.Query().Get("inviteToken")
+	var invite cookie.Invite
 	var teamID string
-	var isAdmin bool
+	var isAdmin, isGuest bool
 	if invCode == "" {
 		teamID = req.Username
 		isAdmin = true
 	} else {
-		invite, err := h.inviteDecoder.Decode(invCode)
-		if err != nil {
+		// invites without a nonce were issued before invites became
+		// single-use and cannot be checked against the team's pending invites
+		var err error
+		invite, err = h.inviteDecoder.Decode(invCode)
+		if err != nil || invite.Nonce == "" {
 			w.WriteHeader(http.StatusBadRequest)
 			if err := json.NewEncoder(w).Encode(
 				PostResp{Err: "Invalid invite token."},
 			); err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(api.ErrStatus(err))
 				h.log.Error(err)
 			}
 			return
@@ -113,48 +176,195 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	pwdHash, err := h.hasher.Hash(req.Password)
 	if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
+	// use up the invite and add the user to the team's members before
+	// inserting the user so that the invite cannot be used to register more
+	// than once and the team's member quota cannot be exceeded
+	if invCode != "" {
+		// check the username is available first so that the invite is not
+		// used up by a registration that is bound to fail
+		if _, err = h.userRetriever.Retrieve(
+			r.Context(), req.Username,
+		); err == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			if err := json.NewEncoder(w).Encode(
+				PostResp{ValidationErrs: ValidationErrs{
+					Username: []string{"Username is already taken."},
+				}},
+			); err != nil {
+				w.WriteHeader(api.ErrStatus(err))
+				h.log.Error(err)
+			}
+			return
+		} else if !errors.Is(err, db.ErrNoItem) {
+			h.log.Error(err)
+			w.WriteHeader(api.ErrStatus(err))
+			return
+		}
+
+		team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+		if errors.Is(err, db.ErrNoItem) {
+			h.writeErr(w, http.StatusBadRequest, "Invalid invite token.")
+			return
+		} else if err != nil {
+			h.log.Error(err)
+			w.WriteHeader(api.ErrStatus(err))
+			return
+		}
+
+		// reject usernames that can be mistaken for those of the team's
+		// members, who they could otherwise be impersonated by
+		skeleton := usernameSkeleton(req.Username)
+		for _, m := range team.Members {
+			if usernameSkeleton(m) == skeleton {
+				w.WriteHeader(http.StatusBadRequest)
+				if err := json.NewEncoder(w).Encode(
+					PostResp{ValidationErrs: ValidationErrs{
+						Username: []string{
+							"Username is too similar to that of a team " +
+								"member.",
+						},
+					}},
+				); err != nil {
+					w.WriteHeader(api.ErrStatus(err))
+					h.log.Error(err)
+				}
+				return
+			}
+		}
+
+		// remove the invite from the team's pending invites, which also
+		// rejects the invite if it expired or was already used
+		now := h.clock.Now().Unix()
+		var isPending bool
+		var guestBoards []string
+		var invites []teamtbl.Invite
+		for _, inv := range team.Invites {
+			if inv.Nonce == invite.Nonce && inv.ExpiresAt > now {
+				isPending = true
+				guestBoards = inv.BoardIDs
+				continue
+			}
+			invites = append(invites, inv)
+		}
+		if !isPending {
+			h.writeErr(w, http.StatusBadRequest,
+				"Invite token has expired or has already been used.",
+			)
+			return
+		}
+		team.Invites = invites
+
+		// reject the registration if the team has no room for another member
+		if !h.quota.Override(team.Quota).AllowsMember(len(team.Members)) {
+			h.writeErr(w, http.StatusBadRequest,
+				"This team has reached its member limit. Please ask the "+
+					"team admin to raise it.",
+			)
+			return
+		}
+		team.Members = append(team.Members, req.Username)
+
+		// guests join only the boards they were invited to - the boards are
+		// taken from the pending invite rather than the token so that they
+		// cannot be tampered with
+		if invite.IsGuest || len(guestBoards) > 0 {
+			isGuest = true
+			team = team.Clone()
+			for i, b := range team.Boards {
+				if slices.Contains(guestBoards, b.ID) {
+					team.Boards[i].Members = append(b.Members, req.Username)
+				}
+			}
+		}
+
+		if err = h.teamUpdater.Update(
+			r.Context(), team,
+		); errors.Is(err, db.ErrConflict) {
+			h.writeErr(w, http.StatusConflict,
+				"Team was modified by someone else. Please try again.",
+			)
+			return
+		} else if errors.Is(err, db.ErrNoItem) {
+			h.writeErr(w, http.StatusBadRequest, "Invalid invite token.")
+			return
+		} else if err != nil {
+			h.log.Error(err)
+			w.WriteHeader(api.ErrStatus(err))
+			return
+		}
+	}
+
 	// insert a new user into the user table
-	if err = h.userInserter.Insert(r.Context(), usertbl.NewUser(
-		req.Username, pwdHash, isAdmin, teamID,
-	)); err == db.ErrDupKey {
+	user := usertbl.NewUser(req.Username, pwdHash, isAdmin, teamID)
+	user.IsGuest = isGuest
+
+	// the user proved they own the address the invite was emailed to by
+	// following its link, so they can log in with it
+	user.Email = strings.ToLower(invite.Email)
+	if err = h.userInserter.Insert(r.Context(), user); err == db.ErrDupKey {
 		w.WriteHeader(http.StatusBadRequest)
 		if err := json.NewEncoder(w).Encode(
 			PostResp{ValidationErrs: ValidationErrs{
 				Username: []string{"Username is already taken."},
 			}},
 		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(api.ErrStatus(err))
 			h.log.Error(err)
 		}
 		return
 	} else if err != nil {
 		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(api.ErrStatus(err))
 		return
 	}
 
-	// generate an auth token
-	ckAuth, err := h.authEncoder.Encode(
-		cookie.NewAuth(req.Username, isAdmin, teamID),
-	)
+	// generate an auth token for a new session
+	auth := cookie.NewAuth(req.Username, isAdmin, teamID)
+	auth.SessionID = uuid.NewString()
+	auth.IsGuest = isGuest
+	ckAuth, err := h.authEncoder.Encode(r.Context(), auth)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		if err := json.NewEncoder(w).Encode(
-			PostResp{
-				Err: "You have been registered successfully but something " +
-					"went wrong. Please log in using the credentials you " +
-					"registered with.",
-			},
-		); err != nil {
-			h.log.Error(err)
-		}
+		h.writeErr(w, http.StatusInternalServerError, errMsgRegistered)
+		return
+	}
+
+	// record the session on the user so that it can be listed and revoked
+	user.StartSession(usertbl.NewSession(
+		auth.SessionID,
+		r.UserAgent(),
+		h.clock.Now().Unix(),
+		ckAuth.Expires.Unix(),
+	))
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		h.log.Error(err)
+		h.writeErr(w, api.ErrStatus(err), errMsgRegistered)
 		return
 	}
 
 	// set auth cookie
 	http.SetCookie(w, &ckAuth)
+
+	// issue a CSRF token for the session - requests made without one fall
+	// back to the origin check, so a failure here is only logged
+	if err = api.SetCSRF(w, ckAuth); err != nil {
+		h.log.Error(err)
+	}
+}
+
+// errMsgRegistered is the error message returned when the user was registered
+// but could not be logged in.
+const errMsgRegistered = "You have been registered successfully but " +
+	"something went wrong. Please log in using the credentials you " +
+	"registered with."
+
+// writeErr writes the given status and error message.
+func (h PostHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(PostResp{Err: msg}); err != nil {
+		h.log.Error(err)
+	}
 }
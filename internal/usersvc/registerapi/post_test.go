@@ -9,27 +9,61 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 func TestHandler(t *testing.T) {
 	var (
-		userValidator = &fakeReqValidator{}
-		hasher        = &fakeHasher{}
-		inviteDecoder = &cookie.FakeStringDecoder[cookie.Invite]{}
-		userInserter  = &db.FakeInserter[usertbl.User]{}
-		authEncoder   = &cookie.FakeEncoder[cookie.Auth]{}
-		log           = &log.FakeErrorer{}
+		userValidator   = &fakeReqValidator{}
+		captchaVerifier = &captcha.FakeVerifier{}
+		hasher          = &fakeHasher{}
+		inviteDecoder   = &cookie.FakeStringDecoder[cookie.Invite]{}
+		teamRetriever   = &db.FakeRetriever[teamtbl.Team]{}
+		teamUpdater     = &db.FakeUpdater[teamtbl.Team]{}
+		userRetriever   = &db.FakeRetriever[usertbl.User]{}
+		userInserter    = &db.FakeInserter[usertbl.User]{}
+		authEncoder     = &cookie.FakeEncoder[cookie.Auth]{}
+		userUpdater     = &db.FakeUpdater[usertbl.User]{}
+		log             = &log.FakeErrorer{}
 	)
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
 	sut := NewPostHandler(
-		userValidator, inviteDecoder, hasher, userInserter, authEncoder, log,
+		userValidator,
+		captchaVerifier,
+		inviteDecoder,
+		teamRetriever,
+		teamUpdater,
+		quota.Quota{MaxMembers: 2},
+		hasher,
+		userRetriever,
+		userInserter,
+		authEncoder,
+		userUpdater,
+		clk,
+		log,
 	)
 
+	// invite is a single-use invite that is pending on team
+	invite := cookie.NewInvite("teamid", "nonce1")
+	team := teamtbl.Team{
+		ID:      "teamid",
+		Members: []string{"teamid"},
+		Invites: []teamtbl.Invite{
+			{Nonce: "nonce1", ExpiresAt: clk.Time.Add(time.Hour).Unix()},
+			{Nonce: "nonce2", ExpiresAt: clk.Time.Add(time.Hour).Unix()},
+		},
+	}
+
 	// Used in status 400 cases to assert on validation errors.
 	assertOnErrsValidate := func(
 		wantValidationErrs ValidationErrs,
@@ -52,19 +86,25 @@ func TestHandler(t *testing.T) {
 
 	validRBody := `{"username": "bob123", "password": "Myp4ssword!"}`
 	for _, c := range []struct {
-		name            string
-		req             string
-		errValidate     ValidationErrs
-		tkInvite        string
-		inviteDecoded   cookie.Invite
-		errDecodeInvite error
-		pwdHash         []byte
-		errHash         error
-		errInsertUser   error
-		authToken       http.Cookie
-		errEncodeAuth   error
-		wantStatus      int
-		assertFunc      func(*testing.T, *http.Response, []any)
+		name             string
+		req              string
+		errValidate      ValidationErrs
+		errVerifyCaptcha error
+		tkInvite         string
+		inviteDecoded    cookie.Invite
+		errDecodeInvite  error
+		errRetrieveUser  error
+		team             teamtbl.Team
+		errRetrieveTeam  error
+		errUpdateTeam    error
+		pwdHash          []byte
+		errHash          error
+		errInsertUser    error
+		authToken        http.Cookie
+		errEncodeAuth    error
+		errUpdateUser    error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
 	}{
 		{
 			name: "ErrsValidate",
@@ -88,6 +128,30 @@ func TestHandler(t *testing.T) {
 				},
 			),
 		},
+		{
+			name:             "CaptchaFailed",
+			req:              "{}",
+			errValidate:      ValidationErrs{},
+			errVerifyCaptcha: captcha.ErrFailed,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&respBody,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, respBody.Code, captcha.ErrCode)
+			},
+		},
+		{
+			name:             "ErrVerifyCaptcha",
+			req:              "{}",
+			errValidate:      ValidationErrs{},
+			errVerifyCaptcha: errors.New("siteverify unreachable"),
+			wantStatus:       http.StatusBadGateway,
+			assertFunc:       assert.OnLoggedErr("siteverify unreachable"),
+		},
 		{
 			name:            "ErrDecodeInvite",
 			req:             "{}",
@@ -127,7 +191,7 @@ func TestHandler(t *testing.T) {
 			req:           validRBody,
 			errValidate:   ValidationErrs{},
 			tkInvite:      "{}",
-			inviteDecoded: cookie.Invite{},
+			inviteDecoded: invite,
 			pwdHash:       nil,
 			errHash:       errors.New("hasher error"),
 			errInsertUser: nil,
@@ -154,6 +218,237 @@ func TestHandler(t *testing.T) {
 				},
 			),
 		},
+		{
+			name:            "InviteNoNonce",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   cookie.NewInvite("teamid", ""),
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Invalid invite token."),
+		},
+		{
+			name:            "ErrUsnTakenInvite",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: nil,
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assertOnErrsValidate(
+				ValidationErrs{
+					Username: []string{"Username is already taken."},
+				},
+			),
+		},
+		{
+			name:            "ErrRetrieveUser",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: errors.New("retrieve user failed"),
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:            "InviteTeamNotFound",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: db.ErrNoItem,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc:      assert.OnRespErr("Invalid invite token."),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:            "InviteUsed",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            teamtbl.Team{ID: "teamid"},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invite token has expired or has already been used.",
+			),
+		},
+		{
+			name:            "InviteExpired",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Invites: []teamtbl.Invite{{Nonce: "nonce1", ExpiresAt: 1}},
+			},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invite token has expired or has already been used.",
+			),
+		},
+		{
+			name:            "MemberLimitReached",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"teamid", "alice"},
+				Invites: team.Invites,
+			},
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"This team has reached its member limit. Please ask the " +
+					"team admin to raise it.",
+			),
+		},
+		{
+			name:            "UsnConfusable",
+			req:             validRBody,
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errRetrieveUser: db.ErrNoItem,
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"teamid", "B0b123"},
+				Invites: team.Invites,
+			},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assertOnErrsValidate(ValidationErrs{Username: []string{
+					"Username is too similar to that of a team member.",
+				}})(t, resp, nil)
+
+				// the invite should not be used up
+				assert.Equal(t.Error, len(teamUpdater.Updated.Invites), 0)
+			},
+		},
+		{
+			name:            "InviteConflict",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   db.ErrConflict,
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Team was modified by someone else. Please try again.",
+			),
+		},
+		{
+			name:            "ErrUpdateTeam",
+			req:             validRBody,
+			errValidate:     ValidationErrs{},
+			tkInvite:        "someinvitetoken",
+			inviteDecoded:   invite,
+			errDecodeInvite: nil,
+			errRetrieveUser: db.ErrNoItem,
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   errors.New("update team failed"),
+			pwdHash:         nil,
+			errHash:         nil,
+			errInsertUser:   nil,
+			authToken:       http.Cookie{},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("update team failed"),
+		},
 		{
 			name:          "ErrPutUser",
 			req:           validRBody,
@@ -187,33 +482,149 @@ func TestHandler(t *testing.T) {
 			),
 		},
 		{
-			name: "Success",
-			req:  validRBody,
-			tkInvite: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ0ZWFtSUQiOi" +
-				"J0ZWFtaWQifQ.1h_fmLJ1ip-Z6kJq9JXYDgGuWDPOcOf8abwCgKtHHcY",
+			name:          "ErrUpdateUser",
+			req:           validRBody,
 			errValidate:   ValidationErrs{},
-			errInsertUser: nil,
+			tkInvite:      "",
+			inviteDecoded: cookie.Invite{},
 			pwdHash:       nil,
 			errHash:       nil,
+			errInsertUser: nil,
 			authToken:     http.Cookie{Name: "foo", Value: "bar"},
 			errEncodeAuth: nil,
-			wantStatus:    http.StatusOK,
+			errUpdateUser: errors.New("update user failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc: func(t *testing.T, resp *http.Response, args []any) {
+				assert.OnRespErr(
+					"You have been registered successfully but something "+
+						"went wrong. Please log in using the credentials "+
+						"you registered with.",
+				)(t, resp, args)
+				assert.OnLoggedErr("update user failed")(t, resp, args)
+			},
+		},
+		{
+			name: "Success",
+			req:  validRBody,
+			tkInvite: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ0ZWFtSUQiOi" +
+				"J0ZWFtaWQifQ.1h_fmLJ1ip-Z6kJq9JXYDgGuWDPOcOf8abwCgKtHHcY",
+			errValidate:     ValidationErrs{},
+			inviteDecoded:   invite,
+			errRetrieveUser: db.ErrNoItem,
+			team:            team,
+			errRetrieveTeam: nil,
+			errUpdateTeam:   nil,
+			errInsertUser:   nil,
+			pwdHash:         nil,
+			errHash:         nil,
+			authToken:       http.Cookie{Name: "foo", Value: "bar"},
+			errEncodeAuth:   nil,
+			wantStatus:      http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 				ck := resp.Cookies()[0]
 				assert.Equal(t.Error, ck.Name, "foo")
 				assert.Equal(t.Error, ck.Value, "bar")
+
+				// a CSRF token should be issued with the auth token
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[1].Name, "csrf-token")
+				assert.Equal(t.Error,
+					resp.Header.Get("X-CSRF-Token"), cks[1].Value,
+				)
+
+				// the invite should be used up
+				invites := teamUpdater.Updated.Invites
+				assert.Equal(t.Fatal, len(invites), 1)
+				assert.Equal(t.Error, invites[0].Nonce, "nonce2")
+
+				// the user should be added to the team's members
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.Members, []string{"teamid", "bob123"},
+				)
+
+				// the session of the token should be recorded on the user
+				user := userUpdater.Updated
+				assert.Equal(t.Error, user.Username, "bob123")
+				assert.Equal(t.Fatal, len(user.Sessions), 1)
+				assert.True(t.Error, user.Sessions[0].ID != "")
+				assert.True(t.Error, !user.IsGuest)
+				assert.Equal(t.Error, user.Email, "")
+			},
+		},
+		{
+			name:       "SuccessNormalized",
+			req:        `{"username": "ｂｏｂ１２３", "password": "Myp4ssword!"}`,
+			authToken:  http.Cookie{Name: "foo", Value: "bar"},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the fullwidth username should be folded into the plain one
+				assert.Equal(t.Error, userInserter.Inserted.Username, "bob123")
+				assert.Equal(t.Error, userInserter.Inserted.TeamID, "bob123")
+			},
+		},
+		{
+			name:        "SuccessGuest",
+			req:         validRBody,
+			tkInvite:    "someinvitetoken",
+			errValidate: ValidationErrs{},
+			inviteDecoded: cookie.Invite{
+				Nonce: "nonce1", Email: "Bob@Acme.com", IsGuest: true,
+			},
+			errRetrieveUser: db.ErrNoItem,
+			team: teamtbl.Team{
+				ID:      "teamid",
+				Members: []string{"teamid"},
+				Boards: []teamtbl.Board{
+					{ID: "b1", Members: []string{"teamid"}},
+					{ID: "b2", Members: []string{"teamid"}},
+				},
+				Invites: []teamtbl.Invite{{
+					Nonce:     "nonce1",
+					ExpiresAt: clk.Time.Add(time.Hour).Unix(),
+					BoardIDs:  []string{"b2"},
+				}},
+			},
+			authToken:  http.Cookie{Name: "foo", Value: "bar"},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the guest should only be added to the boards of the invite
+				got := teamUpdater.Updated
+				assert.AllEqual(t.Error,
+					got.Members, []string{"teamid", "bob123"},
+				)
+				assert.AllEqual(t.Error,
+					got.Boards[0].Members, []string{"teamid"},
+				)
+				assert.AllEqual(t.Error,
+					got.Boards[1].Members, []string{"teamid", "bob123"},
+				)
+
+				assert.True(t.Error, userUpdater.Updated.IsGuest)
+
+				// the user should be able to log in with the invite's email
+				assert.Equal(t.Error,
+					userUpdater.Updated.Email, "bob@acme.com",
+				)
 			},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			userValidator.validationErrs = c.errValidate
+			captchaVerifier.Err = c.errVerifyCaptcha
 			inviteDecoder.Res = c.inviteDecoded
 			inviteDecoder.Err = c.errDecodeInvite
+			userRetriever.Err = c.errRetrieveUser
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			teamUpdater.Err = c.errUpdateTeam
 			hasher.hash = c.pwdHash
 			hasher.err = c.errHash
 			userInserter.Err = c.errInsertUser
 			authEncoder.Res = c.authToken
 			authEncoder.Err = c.errEncodeAuth
+			userUpdater.Err = c.errUpdateUser
+			teamUpdater.Updated = teamtbl.Team{}
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
 				http.MethodPost,
@@ -221,7 +632,7 @@ func TestHandler(t *testing.T) {
 				strings.NewReader(c.req),
 			)
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, cookie.Auth{})
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -0,0 +1,270 @@
+package registerapi
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultMinPwdScore is the minimum password strength score accepted when none
+// is configured.
+const DefaultMinPwdScore = 3
+
+// pwdPattern is a kind of predictable pattern that a password can contain.
+type pwdPattern int
+
+// The patterns that the strength estimator looks for in a password.
+const (
+	patternNone pwdPattern = iota
+	patternCommon
+	patternRepeat
+	patternSequence
+	patternKeyboard
+	patternYear
+)
+
+// pwdMatch is a part of a password that is guessable with the given number of
+// guesses, stored as log10 so that the guesses can be multiplied by adding.
+type pwdMatch struct {
+	i, j    int // the match is pwd[i:j]
+	guesses float64
+	pattern pwdPattern
+}
+
+// estimateStrength estimates the number of guesses it would take to crack the
+// password, in the style of zxcvbn: the password is split into the sequence of
+// common passwords, repeats, sequences, keyboard patterns, years and single
+// characters that is the cheapest to guess, and the guesses for each part are
+// multiplied. It returns a score between 0 (too guessable) and 4 (very
+// unguessable) as well as the patterns found in the cheapest split.
+func estimateStrength(pwd string) (score int, patterns []pwdPattern) {
+	lower := strings.ToLower(pwd)
+	matches := findMatches(pwd, lower)
+
+	// find the cheapest split by dynamic programming over the end positions
+	best := make([]float64, len(pwd)+1)
+	prev := make([]pwdMatch, len(pwd)+1)
+	for j := 1; j <= len(pwd); j++ {
+		best[j] = math.Inf(1)
+		for _, m := range matches {
+			if m.j == j && best[m.i]+m.guesses < best[j] {
+				best[j], prev[j] = best[m.i]+m.guesses, m
+			}
+		}
+	}
+
+	// collect the patterns found, ignoring the single characters
+	seen := map[pwdPattern]bool{}
+	for j := len(pwd); j > 0; j = prev[j].i {
+		if p := prev[j].pattern; p != patternNone && !seen[p] {
+			seen[p] = true
+			patterns = append([]pwdPattern{p}, patterns...)
+		}
+	}
+
+	// score as zxcvbn does, by orders of magnitude of guesses
+	for _, threshold := range []float64{3, 6, 8, 10} {
+		if best[len(pwd)] < threshold {
+			break
+		}
+		score++
+	}
+	return score, patterns
+}
+
+// findMatches returns all the parts of the password that match a pattern, as
+// well as a match for each single character.
+func findMatches(pwd, lower string) []pwdMatch {
+	var matches []pwdMatch
+	for i := 0; i < len(pwd); i++ {
+		matches = append(matches, pwdMatch{
+			i: i, j: i + 1, guesses: math.Log10(cardinality(pwd[i])),
+		})
+	}
+
+	// common passwords, with capitals and common character substitutions
+	for rank, word := range commonPwds {
+		for i := 0; i+len(word) <= len(lower); i++ {
+			j := i + len(word)
+			if !matchesWord(lower[i:j], word) {
+				continue
+			}
+			guesses := math.Log10(float64(rank+1)) +
+				math.Log10(capsVariations(pwd[i:j])) +
+				math.Log10(leetVariations(lower[i:j], word))
+			matches = append(matches, pwdMatch{i, j, guesses, patternCommon})
+		}
+	}
+
+	// runs of repeated characters, sequences and keyboard patterns
+	for _, run := range []struct {
+		pattern pwdPattern
+		minLen  int
+		next    func(a, b byte) bool
+		base    func(byte) float64
+	}{
+		{
+			pattern: patternRepeat,
+			minLen:  3,
+			next:    func(a, b byte) bool { return a == b },
+			base:    cardinality,
+		},
+		{
+			pattern: patternSequence,
+			minLen:  3,
+			next:    isSequential,
+			base:    cardinality,
+		},
+		{
+			pattern: patternSequence,
+			minLen:  3,
+			next:    func(a, b byte) bool { return isSequential(b, a) },
+			base:    cardinality,
+		},
+		{
+			pattern: patternKeyboard,
+			minLen:  4,
+			next:    isAdjacentKey,
+			base:    func(byte) float64 { return 50 },
+		},
+	} {
+		for i := 0; i < len(lower); {
+			j := i + 1
+			for j < len(lower) && run.next(lower[j-1], lower[j]) {
+				j++
+			}
+			if j-i >= run.minLen {
+				guesses := math.Log10(run.base(lower[i]) * float64(j-i))
+				matches = append(matches, pwdMatch{i, j, guesses, run.pattern})
+			}
+			i = j
+		}
+	}
+
+	// years between 1900 and 2099
+	for i := 0; i+4 <= len(lower); i++ {
+		if y := lower[i : i+4]; (strings.HasPrefix(y, "19") ||
+			strings.HasPrefix(y, "20")) && isDigits(y) {
+			matches = append(matches, pwdMatch{
+				i, i + 4, math.Log10(200), patternYear,
+			})
+		}
+	}
+
+	return matches
+}
+
+// cardinality returns the number of characters of the class of the given
+// character, which is the number of guesses it takes to brute force it.
+func cardinality(c byte) float64 {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return 26
+	case c >= '0' && c <= '9':
+		return 10
+	default:
+		return 33
+	}
+}
+
+// capsVariations returns the number of ways a word could have been capitalised
+// to get the given part of a password, counting capitalising the first or all
+// letters as a single extra guess.
+func capsVariations(s string) float64 {
+	upper := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			upper++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case upper == len(s) || (upper == 1 && s[0] >= 'A' && s[0] <= 'Z'):
+		return 2
+	default:
+		return math.Pow(2, float64(upper))
+	}
+}
+
+// matchesWord returns whether the given lowercased part of a password is the
+// word, with any of the characters substituted by their leetSubs.
+func matchesWord(s, word string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != word[i] && leetSubs[s[i]] != word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// leetVariations returns the number of ways the substitutions in the given part
+// of a password could have been made to the word it matched.
+func leetVariations(s, word string) float64 {
+	subs := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != word[i] {
+			subs++
+		}
+	}
+	return math.Pow(2, float64(subs))
+}
+
+// isSequential returns whether b comes right after a in the alphabet or among
+// digits.
+func isSequential(a, b byte) bool {
+	return cardinality(a) != 33 && cardinality(a) == cardinality(b) && b == a+1
+}
+
+// isAdjacentKey returns whether a and b are next to each other on the same row
+// of a QWERTY keyboard.
+func isAdjacentKey(a, b byte) bool {
+	for _, row := range keyboardRows {
+		if i := strings.IndexByte(row, a); i >= 0 {
+			return (i+1 < len(row) && row[i+1] == b) ||
+				(i > 0 && row[i-1] == b)
+		}
+	}
+	return false
+}
+
+// isDigits returns whether the string only contains digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// keyboardRows are the rows of a QWERTY keyboard.
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+}
+
+// leetSubs maps the characters commonly substituted for letters back to them.
+var leetSubs = map[byte]byte{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a',
+	'$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// commonPwds are the most common passwords found in breaches, lowercased and
+// ordered by how common they are. Passwords shorter than four characters are
+// left out as they are cheap to brute force anyway.
+var commonPwds = []string{
+	"password", "123456", "qwerty", "letmein", "welcome", "admin",
+	"iloveyou", "monkey", "dragon", "football", "baseball", "sunshine",
+	"princess", "master", "shadow", "superman", "trustno1", "abc123",
+	"michael", "login", "starwars", "whatever", "freedom", "qazwsx",
+	"mustang", "charlie", "jennifer", "hunter", "jordan", "harley",
+	"ranger", "buster", "thomas", "tigger", "robert", "soccer", "batman",
+	"hockey", "killer", "george", "andrew", "summer", "winter", "spring",
+	"autumn", "flower", "pepper", "ginger", "cookie", "cheese", "chelsea",
+	"liverpool", "arsenal", "secret", "hello", "love", "pass", "test",
+	"guest", "user", "root", "access", "changeme", "default", "computer",
+	"internet", "google", "apple", "orange", "banana", "purple", "yellow",
+	"silver", "golden", "diamond", "angel", "family", "friend", "lovely",
+	"happy", "money", "matrix", "maggie", "daniel", "jessica", "ashley",
+	"nicole", "hannah", "thunder", "phoenix", "samsung", "pokemon",
+	"naruto", "goteam", "team", "board", "demo",
+}
@@ -0,0 +1,93 @@
+package registerapi
+
+import "strings"
+
+// normalizeUsername applies NFKC normalization to the compatibility forms of
+// the letters and digits that usernames can contain (e.g. fullwidth "ｂ",
+// mathematical "𝐛", circled "ⓑ"), so that they are folded into the plain
+// letters and digits they are variants of instead of being rejected. Since
+// usernames can contain only ASCII letters and digits, the compatibility forms
+// of any other character are left intact for the validator to reject.
+func normalizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if lig, ok := ligatures[r]; ok {
+			b.WriteString(lig)
+		} else if n, ok := foldCompat(r); ok {
+			b.WriteRune(n)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// foldCompat returns the ASCII letter or digit that r is the compatibility
+// form of, and false if it is not one.
+func foldCompat(r rune) (rune, bool) {
+	switch {
+	// fullwidth forms
+	case r >= 'Ａ' && r <= 'Ｚ':
+		return 'A' + r - 'Ａ', true
+	case r >= 'ａ' && r <= 'ｚ':
+		return 'a' + r - 'ａ', true
+	case r >= '０' && r <= '９':
+		return '0' + r - '０', true
+	// mathematical alphanumeric symbols - 13 styles of A-Z and a-z followed
+	// by 5 styles of 0-9
+	case r >= 0x1D400 && r <= 0x1D6A3:
+		i := (r - 0x1D400) % 52
+		if i < 26 {
+			return 'A' + i, true
+		}
+		return 'a' + i - 26, true
+	case r >= 0x1D7CE && r <= 0x1D7FF:
+		return '0' + (r-0x1D7CE)%10, true
+	// circled letters and digits
+	case r >= 'Ⓐ' && r <= 'Ⓩ':
+		return 'A' + r - 'Ⓐ', true
+	case r >= 'ⓐ' && r <= 'ⓩ':
+		return 'a' + r - 'ⓐ', true
+	case r >= '①' && r <= '⑨':
+		return '1' + r - '①', true
+	case r == '⓪':
+		return '0', true
+	// superscript and subscript digits
+	case r >= '⁴' && r <= '⁹':
+		return '4' + r - '⁴', true
+	case r >= '₀' && r <= '₉':
+		return '0' + r - '₀', true
+	}
+	n, ok := letterlike[r]
+	return n, ok
+}
+
+// letterlike maps the compatibility forms of letters and digits that are not
+// in a contiguous block to their ASCII equivalents.
+var letterlike = map[rune]rune{
+	'⁰': '0', '¹': '1', '²': '2', '³': '3', 'ⁱ': 'i', 'ⁿ': 'n',
+	'ℂ': 'C', 'ℊ': 'g', 'ℋ': 'H', 'ℌ': 'H', 'ℍ': 'H', 'ℎ': 'h', 'ℐ': 'I',
+	'ℑ': 'I', 'ℒ': 'L', 'ℓ': 'l', 'ℕ': 'N', 'ℙ': 'P', 'ℚ': 'Q', 'ℛ': 'R',
+	'ℜ': 'R', 'ℝ': 'R', 'ℤ': 'Z', 'ℬ': 'B', 'ℭ': 'C', 'ℯ': 'e', 'ℰ': 'E',
+	'ℱ': 'F', 'ℳ': 'M', 'ℴ': 'o', 'ℹ': 'i', '\u212A': 'K', // Kelvin sign
+}
+
+// ligatures maps the Latin ligatures to the letters they are made of.
+var ligatures = map[rune]string{
+	'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st",
+	'ﬆ': "st",
+}
+
+// usernameSkeleton returns the form of username that usernames which can be
+// mistaken for it share, so that a username can be checked against those of
+// the members of a team. Case is ignored and the letters and digits that look
+// alike are replaced with a single one of them.
+func usernameSkeleton(username string) string {
+	return skeletonReplacer.Replace(strings.ToLower(username))
+}
+
+// skeletonReplacer replaces the lower case ASCII letters and digits, and the
+// sequences of them, that look alike.
+var skeletonReplacer = strings.NewReplacer(
+	"rn", "m", "vv", "w", "0", "o", "1", "l", "i", "l",
+)
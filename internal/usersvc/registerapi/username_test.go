@@ -0,0 +1,62 @@
+//go:build utest
+
+package registerapi
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestNormalizeUsername tests the normalizeUsername function to assert that it
+// folds the compatibility forms of letters and digits into the plain ones and
+// leaves other characters intact.
+func TestNormalizeUsername(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		username string
+		want     string
+	}{
+		{name: "Plain", username: "bob123", want: "bob123"},
+		{name: "Fullwidth", username: "Ｂｏｂ１２３", want: "Bob123"},
+		{name: "Mathematical", username: "𝐁𝐨𝐛𝟏𝟐𝟑", want: "Bob123"},
+		{name: "MathematicalItalic", username: "𝑩𝒐𝒃𝟷𝟸𝟹", want: "Bob123"},
+		{name: "Circled", username: "Ⓑⓞⓑ①②③", want: "Bob123"},
+		{name: "Scripts", username: "bob¹²³₄", want: "bob1234"},
+		{name: "Letterlike", username: "ℍℯℓℓℴ", want: "Hello"},
+		{name: "Kelvin", username: "Kate1", want: "Kate1"},
+		{name: "Ligature", username: "ﬁﬂuﬀy", want: "fifluffy"},
+		{name: "Cyrillic", username: "bоb123", want: "bоb123"},
+		{name: "Symbol", username: "bob!", want: "bob!"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, normalizeUsername(c.username), c.want)
+		})
+	}
+}
+
+// TestUsernameSkeleton tests the usernameSkeleton function to assert that the
+// usernames that can be mistaken for each other share a skeleton.
+func TestUsernameSkeleton(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "Same", a: "bob123", b: "bob123", want: true},
+		{name: "Case", a: "bob123", b: "Bob123", want: true},
+		{name: "ZeroO", a: "b0b123", b: "bob123", want: true},
+		{name: "OneL", a: "alice1", b: "alicel", want: true},
+		{name: "UpperIL", a: "Iris12", b: "lris12", want: true},
+		{name: "RNM", a: "marnie", b: "mamie", want: true},
+		{name: "VVW", a: "vvendy", b: "wendy", want: true},
+		{name: "Different", a: "bob123", b: "bob124", want: false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error,
+				usernameSkeleton(c.a) == usernameSkeleton(c.b), c.want,
+			)
+		})
+	}
+}
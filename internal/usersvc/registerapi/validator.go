@@ -1,7 +1,10 @@
 package registerapi
 
 import (
+	"fmt"
 	"regexp"
+
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // ReqValidator describes a type that validates a request body and returns
@@ -40,11 +43,30 @@ func (v UserValidator) Validate(req PostReq) ValidationErrs {
 // string slice containing validation error messages.
 type StrValidator interface{ Validate(string) (errs []string) }
 
+// DefaultReservedUsernames are the usernames that cannot be registered unless
+// others are configured, as they could be mistaken for the staff or the parts
+// of the app.
+var DefaultReservedUsernames = []string{
+	"admin", "root", "support", "api", "system",
+}
+
 // IDValidator is the ID field validator for POST register requests.
-type IDValidator struct{}
+type IDValidator struct {
+	// reserved holds the skeletons of the reserved usernames so that their
+	// lookalikes are reserved too.
+	reserved map[string]struct{}
+}
 
-// NewUsernameValidator creates and returns a new username validator.
-func NewUsernameValidator() IDValidator { return IDValidator{} }
+// NewUsernameValidator creates and returns a new username validator that
+// rejects the given reserved usernames, as well as those that can be mistaken
+// for them.
+func NewUsernameValidator(reserved ...string) IDValidator {
+	v := IDValidator{reserved: make(map[string]struct{}, len(reserved))}
+	for _, r := range reserved {
+		v.reserved[usernameSkeleton(r)] = struct{}{}
+	}
+	return v
+}
 
 // Validate applies user ID validation rules to the ID string and returns the
 // error message if any fails.
@@ -53,10 +75,16 @@ func (v IDValidator) Validate(id string) (errs []string) {
 		errs = append(errs, "Username cannot be empty.")
 		// if password empty, further validation is pointless – return errors
 		return
-	} else if len([]rune(id)) < 5 {
-		errs = append(errs, "Username cannot be shorter than 5 characters.")
-	} else if len([]rune(id)) > 15 {
-		errs = append(errs, "Username cannot be longer than 15 characters.")
+	} else if len([]rune(id)) < validator.MinUsernameLen {
+		errs = append(errs, fmt.Sprintf(
+			"Username cannot be shorter than %d characters.",
+			validator.MinUsernameLen,
+		))
+	} else if len([]rune(id)) > validator.MaxUsernameLen {
+		errs = append(errs, fmt.Sprintf(
+			"Username cannot be longer than %d characters.",
+			validator.MaxUsernameLen,
+		))
 	}
 
 	if match, _ := regexp.MatchString("[^A-Za-z0-9]+", id); match {
@@ -68,15 +96,22 @@ func (v IDValidator) Validate(id string) (errs []string) {
 	if match, _ := regexp.MatchString("(^\\d)", id); match {
 		errs = append(errs, "Username can start only with a letter (a-z/A-Z).")
 	}
+	if _, ok := v.reserved[usernameSkeleton(id)]; ok {
+		errs = append(errs, "Username is reserved.")
+	}
 
 	return
 }
 
 // PwdValidator is the password field validator for the register route.
-type PwdValidator struct{}
+type PwdValidator struct{ minScore int }
 
-// NewPasswordValidator creates and returns a new PasswordValidator.
-func NewPasswordValidator() PwdValidator { return PwdValidator{} }
+// NewPasswordValidator creates and returns a new PasswordValidator that rejects
+// passwords whose estimated strength is below minScore, between 0 (too
+// guessable) and 4 (very unguessable).
+func NewPasswordValidator(minScore int) PwdValidator {
+	return PwdValidator{minScore: minScore}
+}
 
 // Validate applies password validation rules to the Password string and returns
 // the error message if any fails.
@@ -85,10 +120,16 @@ func (v PwdValidator) Validate(pwd string) (errs []string) {
 		errs = append(errs, "Password cannot be empty.")
 		// if password empty, further validation is pointless
 		return
-	} else if len([]rune(pwd)) < 8 {
-		errs = append(errs, "Password cannot be shorter than 8 characters.")
-	} else if len([]rune(pwd)) > 64 {
-		errs = append(errs, "Password cannot be longer than 64 characters.")
+	} else if len([]rune(pwd)) < validator.MinPasswordLen {
+		errs = append(errs, fmt.Sprintf(
+			"Password cannot be shorter than %d characters.",
+			validator.MinPasswordLen,
+		))
+	} else if len([]rune(pwd)) > validator.MaxPasswordLen {
+		errs = append(errs, fmt.Sprintf(
+			"Password cannot be longer than %d characters.",
+			validator.MaxPasswordLen,
+		))
 	}
 
 	if match, _ := regexp.MatchString("[a-z]", pwd); !match {
@@ -113,7 +154,8 @@ func (v PwdValidator) Validate(pwd string) (errs []string) {
 	if match, _ := regexp.MatchString("\\s", pwd); match {
 		errs = append(errs, "Password cannot contain spaces.")
 	}
-	if match, _ := regexp.MatchString("[^\\x00-\\x7F]", pwd); match {
+	// control characters are rejected here too, and whitespace above
+	if match, _ := regexp.MatchString("[^\\x20-\\x7E\\s]", pwd); match {
 		errs = append(
 			errs,
 			"Password can contain only letters (a-z/A-Z), digits (0-9), and "+
@@ -122,5 +164,43 @@ func (v PwdValidator) Validate(pwd string) (errs []string) {
 		)
 	}
 
+	// only estimate the strength of passwords that follow the rules above, as
+	// the errors would otherwise pile up
+	if len(errs) > 0 {
+		return
+	}
+	score, patterns := estimateStrength(pwd)
+	if score >= v.minScore {
+		return
+	}
+	for _, p := range patterns {
+		switch p {
+		case patternCommon:
+			errs = append(errs,
+				"Password cannot be based on a commonly used password.",
+			)
+		case patternRepeat:
+			errs = append(errs,
+				"Password cannot contain repeated characters (e.g. aaa).",
+			)
+		case patternSequence:
+			errs = append(errs,
+				"Password cannot contain sequences (e.g. abc, 123).",
+			)
+		case patternKeyboard:
+			errs = append(errs,
+				"Password cannot contain keyboard patterns (e.g. qwerty).",
+			)
+		case patternYear:
+			errs = append(errs, "Password cannot contain years (e.g. 1990).")
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs,
+			"Password is too easy to guess. Make it longer or less "+
+				"predictable.",
+		)
+	}
+
 	return
 }
@@ -3,9 +3,12 @@
 package registerapi
 
 import (
+	"regexp"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/validator"
 )
 
 // These constants are declared separately from the strings that are actually
@@ -17,6 +20,7 @@ const (
 	idTooLong      = "Username cannot be longer than 15 characters."
 	usnInvalidChar = "Username can contain only letters (a-z/A-Z) and digits (0-9)."
 	usnDigitStart  = "Username can start only with a letter (a-z/A-Z)."
+	usnReserved    = "Username is reserved."
 
 	pwdEmpty     = "Password cannot be empty."
 	pwdTooShort  = "Password cannot be shorter than 8 characters."
@@ -30,6 +34,14 @@ const (
 	pwdNonASCII = "Password can contain only letters (a-z/A-Z), digits (0-9), " +
 		"and the following special characters: " +
 		"! \" # $ % & ' ( ) * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~."
+
+	pwdCommon    = "Password cannot be based on a commonly used password."
+	pwdRepeat    = "Password cannot contain repeated characters (e.g. aaa)."
+	pwdSequence  = "Password cannot contain sequences (e.g. abc, 123)."
+	pwdKeyboard  = "Password cannot contain keyboard patterns (e.g. qwerty)."
+	pwdYear      = "Password cannot contain years (e.g. 1990)."
+	pwdGuessable = "Password is too easy to guess. Make it longer or less " +
+		"predictable."
 )
 
 // TestUserValidator tests the UserValidator's Validate method to ensure that it returns
@@ -132,6 +144,16 @@ func TestUsernameValidator(t *testing.T) {
 			username: "1bob!",
 			wantErrs: []string{usnInvalidChar, usnDigitStart},
 		},
+		{
+			name:     "InvalidCharacter,Lookalike",
+			username: "bоb123",
+			wantErrs: []string{usnInvalidChar},
+		},
+		{
+			name:     "InvalidCharacter,NotLatin",
+			username: "боб123",
+			wantErrs: []string{usnInvalidChar},
+		},
 
 		// 3-error cases
 		{
@@ -152,10 +174,37 @@ func TestUsernameValidator(t *testing.T) {
 	}
 }
 
+// TestUsernameValidatorReserved tests the UsernameValidator to assert that it
+// rejects the reserved usernames it was created with and their lookalikes.
+func TestUsernameValidatorReserved(t *testing.T) {
+	sut := NewUsernameValidator(DefaultReservedUsernames...)
+
+	for _, c := range []struct {
+		name     string
+		username string
+		wantErrs []string
+	}{
+		{name: "Reserved", username: "admin", wantErrs: []string{usnReserved}},
+		{name: "Case", username: "System", wantErrs: []string{usnReserved}},
+		{name: "Lookalike", username: "Adm1n", wantErrs: []string{usnReserved}},
+		{name: "NotReserved", username: "admin1", wantErrs: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.username)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+
+	t.Run("NoneReserved", func(t *testing.T) {
+		errs := NewUsernameValidator().Validate("admin")
+		assert.Equal(t.Error, len(errs), 0)
+	})
+}
+
 // TestPasswordValidator tests the PasswordValidator to assert that it returns
 // the correct error strings based on the password passed to it.
 func TestValidatorPassword(t *testing.T) {
-	sut := NewPasswordValidator()
+	sut := NewPasswordValidator(DefaultMinPwdScore)
 
 	for _, c := range []struct {
 		name     string
@@ -1353,7 +1402,122 @@ func TestValidatorPassword(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			gotErrs := sut.Validate(c.password)
 
-			assert.AllEqual(t.Error, c.wantErrs, gotErrs)
+			assert.AllEqual(t.Error, gotErrs, c.wantErrs)
 		})
 	}
 }
+
+// TestPasswordValidatorStrength tests the PasswordValidator to assert that it
+// rejects the passwords that follow the rules but are too easy to guess, with
+// errors pointing out what makes them so.
+func TestPasswordValidatorStrength(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		minScore int
+		password string
+		wantErrs []string
+	}{
+		{
+			name:     "Common",
+			minScore: DefaultMinPwdScore,
+			password: "P4ssw0rd!",
+			wantErrs: []string{pwdCommon},
+		},
+		{
+			name:     "Repeat",
+			minScore: DefaultMinPwdScore,
+			password: "Aaaaaaa1!",
+			wantErrs: []string{pwdRepeat},
+		},
+		{
+			name:     "Sequence",
+			minScore: DefaultMinPwdScore,
+			password: "Abcdefg1!",
+			wantErrs: []string{pwdSequence},
+		},
+		{
+			name:     "Keyboard",
+			minScore: DefaultMinPwdScore,
+			password: "Asdfghj1!",
+			wantErrs: []string{pwdKeyboard},
+		},
+		{
+			name:     "Common,Year",
+			minScore: DefaultMinPwdScore,
+			password: "Summer2023!",
+			wantErrs: []string{pwdCommon, pwdYear},
+		},
+		{
+			name:     "Guessable",
+			minScore: 4,
+			password: "Ab1!9274",
+			wantErrs: []string{pwdGuessable},
+		},
+		{
+			name:     "LowMinScore",
+			minScore: 0,
+			password: "P4ssw0rd!",
+			wantErrs: nil,
+		},
+		{
+			name:     "Strong",
+			minScore: DefaultMinPwdScore,
+			password: "Gr8!Koala-Mug",
+			wantErrs: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := NewPasswordValidator(c.minScore).Validate(c.password)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
+
+// FuzzUsernameValidator fuzzes IDValidator to assert that it accepts exactly
+// the usernames of the allowed length that start with a letter and contain only
+// letters and digits.
+func FuzzUsernameValidator(f *testing.F) {
+	sut := NewUsernameValidator()
+	for _, s := range []string{
+		"", "bob", "bob123", "1bob23", "bob_123", "bobobobobobobobo", "böb123",
+		"\xffbob123",
+	} {
+		f.Add(s)
+	}
+	valid := regexp.MustCompile("^[A-Za-z][A-Za-z0-9]*$")
+
+	f.Fuzz(func(t *testing.T, username string) {
+		n := utf8.RuneCountInString(username)
+		wantOK := n >= validator.MinUsernameLen &&
+			n <= validator.MaxUsernameLen &&
+			valid.MatchString(username)
+
+		assert.Equal(t.Error, len(sut.Validate(username)) == 0, wantOK)
+	})
+}
+
+// FuzzPasswordValidator fuzzes PwdValidator to assert that it does not panic
+// on any input, including the strength estimation, and that it never accepts
+// a password of the wrong length or with characters outside printable ASCII.
+func FuzzPasswordValidator(f *testing.F) {
+	sut := NewPasswordValidator(DefaultMinPwdScore)
+	for _, s := range []string{
+		"", "Myp4ssw0rd!", "P4ssw@rd123", "Password1!", "qwerty123ASD!",
+		"aaaaaaaaA1!", "Mý-p4ssw0rd", "My p4ssw0rd!", "\xff\xfeA1!aaaaa",
+	} {
+		f.Add(s)
+	}
+	printableASCII := regexp.MustCompile("^[!-~]*$")
+
+	f.Fuzz(func(t *testing.T, pwd string) {
+		if len(sut.Validate(pwd)) > 0 {
+			return
+		}
+
+		n := utf8.RuneCountInString(pwd)
+		assert.True(t.Error,
+			n >= validator.MinPasswordLen && n <= validator.MaxPasswordLen,
+		)
+		assert.True(t.Error, printableASCII.MatchString(pwd))
+	})
+}
@@ -0,0 +1,214 @@
+package samlapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// UsernameValidator describes a type that can be used to validate the
+// usernames that users are registered with.
+type UsernameValidator interface{ Validate(string) []string }
+
+// ACSHandler is an api.MethodHandler that can be used to handle POST SAML ACS
+// requests, which the identity provider of a team sends the user back with. It
+// logs the user in, adding them to the team first if they log in for the
+// first time.
+type ACSHandler struct {
+	responseParser    ResponseParser
+	usernameValidator UsernameValidator
+	teamRetriever     db.Retriever[teamtbl.Team]
+	teamUpdater       db.Updater[teamtbl.Team]
+	quota             quota.Quota
+	userRetriever     db.Retriever[usertbl.User]
+	userInserter      db.Inserter[usertbl.User]
+	authEncoder       cookie.Encoder[cookie.Auth]
+	userUpdater       db.Updater[usertbl.User]
+	clientOrigin      string
+	clock             clock.Clock
+	log               log.Errorer
+}
+
+// NewACSHandler creates and returns a new ACSHandler. Users are redirected to
+// the given client origin once they are logged in.
+func NewACSHandler(
+	responseParser ResponseParser,
+	usernameValidator UsernameValidator,
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	quota quota.Quota,
+	userRetriever db.Retriever[usertbl.User],
+	userInserter db.Inserter[usertbl.User],
+	authEncoder cookie.Encoder[cookie.Auth],
+	userUpdater db.Updater[usertbl.User],
+	clientOrigin string,
+	clock clock.Clock,
+	log log.Errorer,
+) ACSHandler {
+	return ACSHandler{
+		responseParser:    responseParser,
+		usernameValidator: usernameValidator,
+		teamRetriever:     teamRetriever,
+		teamUpdater:       teamUpdater,
+		quota:             quota,
+		userRetriever:     userRetriever,
+		userInserter:      userInserter,
+		authEncoder:       authEncoder,
+		userUpdater:       userUpdater,
+		clientOrigin:      clientOrigin,
+		clock:             clock,
+		log:               log,
+	}
+}
+
+// Handle handles POST SAML ACS requests.
+func (h ACSHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	teamID := api.PathParam(r, "teamID")
+
+	// retrieve the team's identity provider
+	team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+	if errors.Is(err, db.ErrNoItem) || err == nil && !team.SAML.IsEnabled() {
+		h.fail(w, r, ErrCodeNotConfigured)
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// verify the response and read the username from its assertion
+	assertion, err := h.responseParser.ParseResponse(
+		teamID, r.PostFormValue("SAMLResponse"), idpOf(team.SAML),
+		h.clock.Now(),
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	username := assertion.NameID
+	if attr := team.SAML.UsernameAttribute; attr != "" {
+		username = ""
+		if vals := assertion.Attributes[attr]; len(vals) > 0 {
+			username = vals[0]
+		}
+	}
+	if errs := h.usernameValidator.Validate(username); len(errs) > 0 {
+		h.log.Error("saml username", username, "was invalid:", errs)
+		h.fail(w, r, ErrCodeUsername)
+		return
+	}
+
+	// add the user to the team if they are logging in for the first time
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		var errCode string
+		if user, errCode = h.register(r, team, username); errCode != "" {
+			h.fail(w, r, errCode)
+			return
+		}
+	} else if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// the identity provider of a team can only log in the team's members
+	if user.TeamID != teamID {
+		h.log.Error("saml user", username, "is not a member of", teamID)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// disabled users are not allowed to log in
+	if user.IsDisabled {
+		h.fail(w, r, ErrCodeDisabled)
+		return
+	}
+
+	// encode a new auth token for a new session and record the session on
+	// the user so that it can be listed and revoked
+	auth := cookie.NewAuth(user.Username, user.IsAdmin, user.TeamID)
+	auth.SessionID = uuid.NewString()
+	auth.IsGuest = user.IsGuest
+	ckAuth, err := h.authEncoder.Encode(r.Context(), auth)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	user.StartSession(usertbl.NewSession(
+		auth.SessionID,
+		r.UserAgent(),
+		h.clock.Now().Unix(),
+		ckAuth.Expires.Unix(),
+	))
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// set auth token in cookie
+	http.SetCookie(w, &ckAuth)
+
+	// issue a CSRF token for the session - requests made without one fall
+	// back to the origin check, so a failure here is only logged
+	if err = api.SetCSRF(w, ckAuth); err != nil {
+		h.log.Error(err)
+	}
+
+	http.Redirect(w, r, h.clientOrigin, http.StatusSeeOther)
+}
+
+// register adds the user with the given username to the given team and
+// inserts them into the user table. The user has no password, so they can
+// only log in with the identity provider unless one is set for them. It
+// returns the error code to fail the login with if the user could not be
+// registered.
+func (h ACSHandler) register(
+	r *http.Request, team teamtbl.Team, username string,
+) (usertbl.User, string) {
+	// a user who is already a member is one whose registration failed after
+	// they joined the team
+	if !slices.Contains(team.Members, username) {
+		if !h.quota.Override(team.Quota).AllowsMember(len(team.Members)) {
+			return usertbl.User{}, ErrCodeTeamFull
+		}
+		team.Members = append(team.Members, username)
+		if err := h.teamUpdater.Update(r.Context(), team); err != nil {
+			h.log.Error(err)
+			return usertbl.User{}, ErrCodeFailed
+		}
+	}
+
+	user := usertbl.NewUser(username, nil, false, team.ID)
+	if err := h.userInserter.Insert(r.Context(), user); err != nil {
+		h.log.Error(err)
+		return usertbl.User{}, ErrCodeFailed
+	}
+	return user, ""
+}
+
+// fail redirects the user to the client's login page with the given error
+// code.
+func (h ACSHandler) fail(
+	w http.ResponseWriter, r *http.Request, errCode string,
+) {
+	http.Redirect(
+		w, r, loginPageURL(h.clientOrigin, errCode), http.StatusSeeOther,
+	)
+}
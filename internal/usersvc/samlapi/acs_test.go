@@ -0,0 +1,263 @@
+//go:build utest
+
+package samlapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/saml"
+)
+
+// TestACSHandler tests the Handle method of ACSHandler to assert that it logs
+// in the member of the team that the identity provider vouches for, adding
+// them to the team first if they log in for the first time, and sends them
+// back to the client's login page with an error code when it fails.
+func TestACSHandler(t *testing.T) {
+	var (
+		responseParser    = &fakeResponseParser{}
+		usernameValidator = &fakeUsernameValidator{}
+		teamRetriever     = &db.FakeRetriever[teamtbl.Team]{}
+		teamUpdater       = &db.FakeUpdater[teamtbl.Team]{}
+		userRetriever     = &db.FakeRetriever[usertbl.User]{}
+		userInserter      = &db.FakeInserter[usertbl.User]{}
+		authEncoder       = &cookie.FakeEncoder[cookie.Auth]{}
+		userUpdater       = &db.FakeUpdater[usertbl.User]{}
+		log               = &log.FakeErrorer{}
+	)
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	authToken := http.Cookie{
+		Name: cookie.AuthName, Value: "t0ken", Expires: clk.Time.Add(time.Hour),
+	}
+	const origin = "https://goteam.io"
+	fail := func(code string) string { return loginPageURL(origin, code) }
+	sut := NewACSHandler(
+		responseParser,
+		usernameValidator,
+		teamRetriever,
+		teamUpdater,
+		quota.Quota{MaxMembers: 2},
+		userRetriever,
+		userInserter,
+		authEncoder,
+		userUpdater,
+		origin,
+		clk,
+		log,
+	)
+	enabled := teamtbl.SAML{IdPEntityID: "https://idp.example.com"}
+
+	for _, c := range []struct {
+		name            string
+		team            teamtbl.Team
+		errRetrieveTeam error
+		errParse        error
+		attributes      map[string][]string
+		errsValidate    []string
+		user            usertbl.User
+		errRetrieveUser error
+		errUpdateTeam   error
+		errInsertUser   error
+		errEncodeAuth   error
+		errUpdateUser   error
+		wantLocation    string
+		assertFunc      func(*testing.T)
+	}{
+		{
+			name:            "TeamNotFound",
+			errRetrieveTeam: db.ErrNoItem,
+			wantLocation:    fail(ErrCodeNotConfigured),
+		},
+		{
+			name:         "NotConfigured",
+			team:         teamtbl.Team{ID: "acme"},
+			wantLocation: fail(ErrCodeNotConfigured),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:         "ErrParse",
+			team:         teamtbl.Team{ID: "acme", SAML: enabled},
+			errParse:     saml.ErrSignature,
+			wantLocation: fail(ErrCodeFailed),
+			assertFunc: func(t *testing.T) {
+				assert.ErrIs(t.Error, log.Args[0].(error), saml.ErrSignature)
+			},
+		},
+		{
+			name: "NoUsernameAttribute",
+			team: teamtbl.Team{ID: "acme", SAML: teamtbl.SAML{
+				IdPEntityID:       "https://idp.example.com",
+				UsernameAttribute: "uid",
+			}},
+			errsValidate: []string{"Username cannot be empty."},
+			wantLocation: fail(ErrCodeUsername),
+		},
+		{
+			name:            "ErrRetrieveUser",
+			team:            teamtbl.Team{ID: "acme", SAML: enabled},
+			errRetrieveUser: errors.New("retrieve user failed"),
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:         "OtherTeam",
+			team:         teamtbl.Team{ID: "acme", SAML: enabled},
+			user:         usertbl.User{Username: "bob123", TeamID: "other"},
+			wantLocation: fail(ErrCodeFailed),
+		},
+		{
+			name: "Disabled",
+			team: teamtbl.Team{ID: "acme", SAML: enabled},
+			user: usertbl.User{
+				Username: "bob123", TeamID: "acme", IsDisabled: true,
+			},
+			wantLocation: fail(ErrCodeDisabled),
+		},
+		{
+			name: "TeamFull",
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"a", "b"}, SAML: enabled,
+			},
+			errRetrieveUser: db.ErrNoItem,
+			wantLocation:    fail(ErrCodeTeamFull),
+		},
+		{
+			name: "ErrUpdateTeam",
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"a"}, SAML: enabled,
+			},
+			errRetrieveUser: db.ErrNoItem,
+			errUpdateTeam:   db.ErrConflict,
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name: "ErrInsertUser",
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"a"}, SAML: enabled,
+			},
+			errRetrieveUser: db.ErrNoItem,
+			errInsertUser:   db.ErrDupKey,
+			wantLocation:    fail(ErrCodeFailed),
+		},
+		{
+			name:          "ErrEncodeAuth",
+			team:          teamtbl.Team{ID: "acme", SAML: enabled},
+			user:          usertbl.User{Username: "bob123", TeamID: "acme"},
+			errEncodeAuth: errors.New("encode failed"),
+			wantLocation:  fail(ErrCodeFailed),
+		},
+		{
+			name:          "ErrUpdateUser",
+			team:          teamtbl.Team{ID: "acme", SAML: enabled},
+			user:          usertbl.User{Username: "bob123", TeamID: "acme"},
+			errUpdateUser: errors.New("update user failed"),
+			wantLocation:  fail(ErrCodeFailed),
+		},
+		{
+			name:         "LoggedIn",
+			team:         teamtbl.Team{ID: "acme", SAML: enabled},
+			user:         usertbl.User{Username: "bob123", TeamID: "acme"},
+			wantLocation: origin,
+			assertFunc: func(t *testing.T) {
+				assert.Equal(t.Error, responseParser.teamID, "acme")
+				assert.Equal(t.Error, responseParser.samlResponse, "r3sp")
+				assert.Equal(t.Error, userInserter.Inserted.Username, "")
+				assert.Equal(t.Fatal, len(userUpdater.Updated.Sessions), 1)
+				assert.Equal(t.Error,
+					userUpdater.Updated.Sessions[0].ExpiresAt,
+					authToken.Expires.Unix(),
+				)
+			},
+		},
+		{
+			name: "Registered",
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"a"}, SAML: teamtbl.SAML{
+					IdPEntityID:       "https://idp.example.com",
+					UsernameAttribute: "uid",
+				},
+			},
+			attributes:      map[string][]string{"uid": {"bob123"}},
+			errRetrieveUser: db.ErrNoItem,
+			wantLocation:    origin,
+			assertFunc: func(t *testing.T) {
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.Members, []string{"a", "bob123"},
+				)
+				assert.Equal(t.Error, userInserter.Inserted.Username, "bob123")
+				assert.Equal(t.Error, userInserter.Inserted.TeamID, "acme")
+				assert.True(t.Error, !userInserter.Inserted.IsAdmin)
+				assert.Equal(t.Error,
+					len(userInserter.Inserted.Password), 0,
+				)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 1)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			responseParser.assertion = saml.Assertion{
+				ID: "_a1", NameID: "bob123", Attributes: c.attributes,
+			}
+			responseParser.err = c.errParse
+			usernameValidator.errs = c.errsValidate
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			teamUpdater.Err = c.errUpdateTeam
+			teamUpdater.Updated = teamtbl.Team{}
+			userInserter.Err = c.errInsertUser
+			userInserter.Inserted = usertbl.User{}
+			authEncoder.Res = authToken
+			authEncoder.Err = c.errEncodeAuth
+			userUpdater.Err = c.errUpdateUser
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/sso/acme/acs",
+				strings.NewReader(url.Values{
+					"SAMLResponse": {"r3sp"},
+				}.Encode()),
+			)
+			r.Header.Set(
+				"Content-Type", "application/x-www-form-urlencoded",
+			)
+			r = api.WithPathParams(r, map[string]string{"teamID": "acme"})
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, http.StatusSeeOther)
+			assert.Equal(t.Error, resp.Header.Get("Location"), c.wantLocation)
+
+			// the auth cookie is only set once the user is logged in
+			var gotAuth bool
+			for _, ck := range resp.Cookies() {
+				if ck.Name == cookie.AuthName {
+					gotAuth = true
+				}
+			}
+			assert.Equal(t.Error, gotAuth, c.wantLocation == origin)
+
+			if c.assertFunc != nil {
+				c.assertFunc(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+//go:build utest
+
+package samlapi
+
+import (
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/saml"
+)
+
+// fakeResponseParser is a test fake for ResponseParser.
+type fakeResponseParser struct {
+	assertion saml.Assertion
+	err       error
+
+	// teamID and samlResponse are what ParseResponse was last called with.
+	teamID       string
+	samlResponse string
+}
+
+// ParseResponse records the team ID and the response and returns
+// fakeResponseParser.assertion and fakeResponseParser.err.
+func (f *fakeResponseParser) ParseResponse(
+	teamID, samlResponse string, _ saml.IdP, _ time.Time,
+) (saml.Assertion, error) {
+	f.teamID, f.samlResponse = teamID, samlResponse
+	return f.assertion, f.err
+}
+
+// fakeUsernameValidator is a test fake for UsernameValidator.
+type fakeUsernameValidator struct{ errs []string }
+
+// Validate discards the input parameters and returns
+// fakeUsernameValidator.errs.
+func (f *fakeUsernameValidator) Validate(string) []string { return f.errs }
@@ -0,0 +1,82 @@
+package samlapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// LoginHandler is an api.MethodHandler that can be used to handle GET SAML
+// login requests, which send the user to the identity provider of a team to
+// log in.
+type LoginHandler struct {
+	serviceProvider ServiceProvider
+	teamRetriever   db.Retriever[teamtbl.Team]
+	clientOrigin    string
+	clock           clock.Clock
+	log             log.Errorer
+}
+
+// NewLoginHandler creates and returns a new LoginHandler.
+func NewLoginHandler(
+	serviceProvider ServiceProvider,
+	teamRetriever db.Retriever[teamtbl.Team],
+	clientOrigin string,
+	clock clock.Clock,
+	log log.Errorer,
+) LoginHandler {
+	return LoginHandler{
+		serviceProvider: serviceProvider,
+		teamRetriever:   teamRetriever,
+		clientOrigin:    clientOrigin,
+		clock:           clock,
+		log:             log,
+	}
+}
+
+// Handle handles GET SAML login requests.
+func (h LoginHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	teamID := api.PathParam(r, "teamID")
+
+	// retrieve the team's identity provider
+	team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+	if errors.Is(err, db.ErrNoItem) || err == nil && !team.SAML.IsEnabled() {
+		h.fail(w, r, ErrCodeNotConfigured)
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+
+	// send the user to it with an authentication request
+	authnURL, err := h.serviceProvider.For(teamID).AuthnRequestURL(
+		idpOf(team.SAML), "_"+uuid.NewString(), "", h.clock.Now(),
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeFailed)
+		return
+	}
+	http.Redirect(w, r, authnURL, http.StatusFound)
+}
+
+// fail redirects the user to the client's login page with the given error
+// code.
+func (h LoginHandler) fail(
+	w http.ResponseWriter, r *http.Request, errCode string,
+) {
+	http.Redirect(
+		w, r, loginPageURL(h.clientOrigin, errCode), http.StatusSeeOther,
+	)
+}
@@ -0,0 +1,97 @@
+//go:build utest
+
+package samlapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestLoginHandler tests the Handle method of LoginHandler to assert that it
+// sends the user to the identity provider of the team with an authentication
+// request, or back to the client's login page if the team has none.
+func TestLoginHandler(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	const origin = "https://goteam.io"
+	sut := NewLoginHandler(
+		NewServiceProvider("https://api.goteam.io/"),
+		teamRetriever,
+		origin,
+		&clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		log,
+	)
+	saml := teamtbl.SAML{
+		IdPEntityID: "https://idp.example.com",
+		SSOURL:      "https://idp.example.com/sso",
+	}
+
+	for _, c := range []struct {
+		name         string
+		team         teamtbl.Team
+		errRetrieve  error
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:         "TeamNotFound",
+			errRetrieve:  db.ErrNoItem,
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: loginPageURL(origin, ErrCodeNotConfigured),
+		},
+		{
+			name:         "NotConfigured",
+			team:         teamtbl.Team{ID: "acme"},
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: loginPageURL(origin, ErrCodeNotConfigured),
+		},
+		{
+			name:         "ErrRetrieve",
+			errRetrieve:  errors.New("retrieve failed"),
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: loginPageURL(origin, ErrCodeFailed),
+		},
+		{
+			name:         "OK",
+			team:         teamtbl.Team{ID: "acme", SAML: saml},
+			wantStatus:   http.StatusFound,
+			wantLocation: saml.SSOURL,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodGet, "/sso/acme/login", nil),
+				map[string]string{"teamID": "acme"},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			loc, err := url.Parse(resp.Header.Get("Location"))
+			assert.Nil(t.Fatal, err)
+			assert.True(t.Error,
+				strings.HasPrefix(loc.String(), c.wantLocation),
+			)
+			if c.wantStatus == http.StatusFound {
+				assert.True(t.Error, loc.Query().Get("SAMLRequest") != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package samlapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// MetadataHandler is an api.MethodHandler that can be used to handle GET SAML
+// metadata requests, which serve the metadata of a team's service provider for
+// the team's admin to set up their identity provider with.
+type MetadataHandler struct {
+	serviceProvider ServiceProvider
+	log             log.Errorer
+}
+
+// NewMetadataHandler creates and returns a new MetadataHandler.
+func NewMetadataHandler(
+	serviceProvider ServiceProvider, log log.Errorer,
+) MetadataHandler {
+	return MetadataHandler{serviceProvider: serviceProvider, log: log}
+}
+
+// Handle handles GET SAML metadata requests.
+func (h MetadataHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	sp := h.serviceProvider.For(api.PathParam(r, "teamID"))
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	if _, err := w.Write(sp.Metadata()); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build utest
+
+package samlapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestMetadataHandler tests the Handle method of MetadataHandler to assert
+// that it serves the metadata of the team's service provider.
+func TestMetadataHandler(t *testing.T) {
+	sut := NewMetadataHandler(
+		NewServiceProvider("https://api.goteam.io"), &log.FakeErrorer{},
+	)
+	w := httptest.NewRecorder()
+	r := api.WithPathParams(
+		httptest.NewRequest(http.MethodGet, "/sso/acme/metadata", nil),
+		map[string]string{"teamID": "acme"},
+	)
+
+	sut.Handle(w, r, cookie.Auth{})
+
+	resp := w.Result()
+	assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+	assert.Equal(t.Error,
+		resp.Header.Get("Content-Type"), "application/samlmetadata+xml",
+	)
+	body := w.Body.String()
+	assert.True(t.Error, strings.Contains(body,
+		`entityID="https://api.goteam.io/sso/acme/metadata"`,
+	))
+	assert.True(t.Error, strings.Contains(body,
+		`Location="https://api.goteam.io/sso/acme/acs"`,
+	))
+}
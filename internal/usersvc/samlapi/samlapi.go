@@ -0,0 +1,88 @@
+// Package samlapi contains code for responding to HTTP requests made to the
+// SAML API routes, which are used for logging in the members of a team with
+// the SAML identity provider that the team's admin set up.
+package samlapi
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/saml"
+)
+
+// The codes set on the error query parameter of the client's login page when
+// a login fails, so that the client can tell the user why. They are shared
+// with the OIDC API so that the client handles both in the same way.
+const (
+	ErrCodeFailed        = "ssoFailed"
+	ErrCodeUsername      = "ssoUsername"
+	ErrCodeDisabled      = "ssoDisabled"
+	ErrCodeTeamFull      = "ssoTeamFull"
+	ErrCodeNotConfigured = "ssoNotConfigured"
+)
+
+// ErrReplayed means that a response held an assertion that was already used
+// to log in.
+var ErrReplayed = errors.New("samlapi: assertion was already used")
+
+// ResponseParser describes a type that can be used to parse the responses that
+// the identity provider of a team posts to the team's ACS route.
+type ResponseParser interface {
+	ParseResponse(
+		teamID, samlResponse string, idp saml.IdP, now time.Time,
+	) (saml.Assertion, error)
+}
+
+// ServiceProvider defines the service provider that the members of each team
+// log in to. Each team has its own entity ID and ACS URL so that the identity
+// provider of one team cannot be used to log in to another.
+type ServiceProvider struct {
+	baseURL string
+	replays *saml.ReplayCache
+}
+
+// NewServiceProvider creates and returns a new ServiceProvider with the given
+// base URL, which is the public URL of the user service.
+func NewServiceProvider(baseURL string) ServiceProvider {
+	return ServiceProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		replays: saml.NewReplayCache(),
+	}
+}
+
+// For returns the service provider of the team with the given ID.
+func (p ServiceProvider) For(teamID string) saml.SP {
+	base := p.baseURL + "/sso/" + url.PathEscape(teamID)
+	return saml.SP{EntityID: base + "/metadata", ACSURL: base + "/acs"}
+}
+
+// ParseResponse parses the given response posted to the ACS route of the team
+// with the given ID, rejecting the ones that were already used to log in.
+func (p ServiceProvider) ParseResponse(
+	teamID, samlResponse string, idp saml.IdP, now time.Time,
+) (saml.Assertion, error) {
+	a, err := p.For(teamID).ParseResponse(samlResponse, idp, now)
+	if err != nil {
+		return saml.Assertion{}, err
+	}
+	if !p.replays.Use(a, now) {
+		return saml.Assertion{}, ErrReplayed
+	}
+	return a, nil
+}
+
+// idpOf returns the identity provider described by the given settings.
+func idpOf(s teamtbl.SAML) saml.IdP {
+	return saml.IdP{EntityID: s.IdPEntityID, SSOURL: s.SSOURL, Certs: s.Certs}
+}
+
+// loginPageURL returns the URL of the client's login page with the given
+// error code.
+func loginPageURL(clientOrigin, errCode string) string {
+	return strings.TrimSuffix(clientOrigin, "/") + "/login?" + url.Values{
+		"error": {errCode},
+	}.Encode()
+}
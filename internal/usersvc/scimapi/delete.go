@@ -0,0 +1,106 @@
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the SCIM user route. It deprovisions the user by deactivating them and
+// removing them from the team and its boards. The user item is kept so that
+// their username is not taken by someone else, and they can be provisioned
+// again.
+type DeleteHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the SCIM user route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	// retrieve the user, who must be a member of the team other than its
+	// admin
+	user, err := h.userRetriever.Retrieve(r.Context(), api.PathParam(r, "id"))
+	if errors.Is(err, db.ErrNoItem) || err == nil && !isMember(team, user) {
+		writeErr(w, http.StatusNotFound, "", "User not found.", h.log)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if user.Username == team.ID {
+		writeErr(w, http.StatusBadRequest, "mutability",
+			"Team admin cannot be deprovisioned.", h.log,
+		)
+		return
+	}
+
+	// deactivate the user first so that they are signed out even if removing
+	// them from the team fails, in which case the request can be retried as
+	// they are still a member
+	setActive(&user, false)
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// remove them from the team and its boards
+	isUser := func(username string) bool { return username == user.Username }
+	team.Members = slices.DeleteFunc(slices.Clone(team.Members), isUser)
+	boards := make([]teamtbl.Board, len(team.Boards))
+	for i, b := range team.Boards {
+		b.Members = slices.DeleteFunc(slices.Clone(b.Members), isUser)
+		boards[i] = b
+	}
+	team.Boards = boards
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrConflict) {
+		writeErr(w, http.StatusConflict, "",
+			"Team was modified by someone else. Please try again.", h.log,
+		)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,159 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that
+// it behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(
+		authorizer, userRetriever, userUpdater, teamUpdater, log,
+	)
+	team := teamtbl.Team{
+		ID:      "acme",
+		Members: []string{"acme", "bob123", "jo1234"},
+		Boards: []teamtbl.Board{
+			{ID: "b1", Members: []string{"acme", "bob123"}},
+			{ID: "b2", Members: []string{"jo1234"}},
+		},
+	}
+	authorizer.team = team
+	bob := usertbl.User{
+		Username: "bob123",
+		TeamID:   "acme",
+		Sessions: []usertbl.Session{{ID: "s1"}},
+	}
+
+	for _, c := range []struct {
+		name          string
+		id            string
+		user          usertbl.User
+		errRetrieve   error
+		errUpdateUser error
+		errUpdateTeam error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "NotFound",
+			id:          "bob123",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  onErr("User not found."),
+		},
+		{
+			name:       "NotMember",
+			id:         "bob123",
+			user:       usertbl.User{Username: "bob123", TeamID: "other"},
+			wantStatus: http.StatusNotFound,
+			assertFunc: onErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			id:          "bob123",
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "Admin",
+			id:         "acme",
+			user:       usertbl.User{Username: "acme", TeamID: "acme"},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Team admin cannot be deprovisioned."),
+		},
+		{
+			name:          "ErrUpdateUser",
+			id:            "bob123",
+			user:          bob,
+			errUpdateUser: errors.New("update user failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:          "TeamConflict",
+			id:            "bob123",
+			user:          bob,
+			errUpdateTeam: db.ErrConflict,
+			wantStatus:    http.StatusConflict,
+			assertFunc: onErr(
+				"Team was modified by someone else. Please try again.",
+			),
+		},
+		{
+			name:          "ErrUpdateTeam",
+			id:            "bob123",
+			user:          bob,
+			errUpdateTeam: errors.New("update team failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:       "OK",
+			id:         "bob123",
+			user:       bob,
+			wantStatus: http.StatusNoContent,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				user := userUpdater.Updated
+				assert.True(t.Error, user.IsDisabled)
+				assert.Equal(t.Error, len(user.Sessions), 0)
+
+				got := teamUpdater.Updated
+				assert.AllEqual(t.Error,
+					got.Members, []string{"acme", "jo1234"},
+				)
+				assert.AllEqual(t.Error,
+					got.Boards[0].Members, []string{"acme"},
+				)
+				assert.AllEqual(t.Error,
+					got.Boards[1].Members, []string{"jo1234"},
+				)
+
+				// the team the authorizer returned must be left intact
+				assert.AllEqual(t.Error,
+					team.Members, []string{"acme", "bob123", "jo1234"},
+				)
+				assert.AllEqual(t.Error,
+					team.Boards[0].Members, []string{"acme", "bob123"},
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdateUser
+			teamUpdater.Err = c.errUpdateTeam
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodDelete, "/", nil),
+				map[string]string{"id": c.id},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
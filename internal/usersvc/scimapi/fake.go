@@ -0,0 +1,28 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// fakeAuthorizer is a test fake for Authorizer.
+type fakeAuthorizer struct {
+	team teamtbl.Team
+	err  error
+}
+
+// Authorize discards the input parameters and returns fakeAuthorizer.team and
+// fakeAuthorizer.err.
+func (f *fakeAuthorizer) Authorize(*http.Request) (teamtbl.Team, error) {
+	return f.team, f.err
+}
+
+// fakeUsernameValidator is a test fake for UsernameValidator.
+type fakeUsernameValidator struct{ errs []string }
+
+// Validate discards the input parameters and returns
+// fakeUsernameValidator.errs.
+func (f *fakeUsernameValidator) Validate(string) []string { return f.errs }
@@ -0,0 +1,56 @@
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// SCIM user route.
+type GetHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the SCIM user route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	// retrieve the user, who must be a member of the team
+	user, err := h.userRetriever.Retrieve(r.Context(), api.PathParam(r, "id"))
+	if errors.Is(err, db.ErrNoItem) || err == nil && !isMember(team, user) {
+		writeErr(w, http.StatusNotFound, "", "User not found.", h.log)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUser(user), h.log)
+}
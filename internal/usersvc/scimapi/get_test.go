@@ -0,0 +1,100 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(authorizer, userRetriever, log)
+	team := teamtbl.Team{ID: "acme", Members: []string{"acme", "bob123"}}
+
+	for _, c := range []struct {
+		name        string
+		errAuth     error
+		user        usertbl.User
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "Unauthorized",
+			errAuth:    ErrUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: onErr("Invalid SCIM token."),
+		},
+		{
+			name:       "ErrAuth",
+			errAuth:    errors.New("retrieve team failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:        "NotFound",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  onErr("User not found."),
+		},
+		{
+			name:       "OtherTeam",
+			user:       usertbl.User{Username: "bob123", TeamID: "other"},
+			wantStatus: http.StatusNotFound,
+			assertFunc: onErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name: "OK",
+			user: usertbl.User{
+				Username: "bob123", TeamID: "acme", IsDisabled: true,
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(User{
+				Schemas:  []string{schemaUser},
+				ID:       "bob123",
+				UserName: "bob123",
+				Active:   false,
+				Meta:     Meta{ResourceType: "User"},
+			}),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.team = team
+			authorizer.err = c.errAuth
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(http.MethodGet, "/", nil),
+				map[string]string{"id": "bob123"},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// maxCount is the maximum number of users listed per page.
+const maxCount = 100
+
+// ListResp defines the body of GET SCIM users responses.
+type ListResp struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// ListHandler is an api.MethodHandler that can handle GET requests sent to the
+// SCIM users route. It lists the members of the team a page at a time, and
+// supports only the userName eq filter, which identity providers use to look
+// up a user before they provision them.
+type ListHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	log           log.Errorer
+}
+
+// NewListHandler creates and returns a new ListHandler.
+func NewListHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	log log.Errorer,
+) ListHandler {
+	return ListHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the SCIM users route.
+func (h ListHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	// filter the members of the team
+	query := r.URL.Query()
+	usernames := team.Members
+	if filter := query.Get("filter"); filter != "" {
+		username, ok := parseFilter(filter)
+		if !ok {
+			writeErr(w, http.StatusBadRequest, "invalidFilter",
+				"Only the userName eq filter is supported.", h.log,
+			)
+			return
+		}
+		usernames = nil
+		if slices.Contains(team.Members, username) {
+			usernames = []string{username}
+		}
+	}
+
+	// page through them with the 1-based start index and the count
+	startIndex, count := 1, maxCount
+	if v, err := strconv.Atoi(query.Get("startIndex")); err == nil && v > 1 {
+		startIndex = v
+	}
+	if v, err := strconv.Atoi(query.Get("count")); err == nil && v >= 0 {
+		count = min(v, maxCount)
+	}
+	start := min(startIndex-1, len(usernames))
+	end := min(start+count, len(usernames))
+
+	// retrieve the users on the page, skipping the members whose user was
+	// never inserted
+	resources := []User{}
+	for _, username := range usernames[start:end] {
+		user, err := h.userRetriever.Retrieve(r.Context(), username)
+		if errors.Is(err, db.ErrNoItem) {
+			continue
+		} else if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+		if isMember(team, user) {
+			resources = append(resources, newUser(user))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, ListResp{
+		Schemas:      []string{schemaList},
+		TotalResults: len(usernames),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, h.log)
+}
+
+// parseFilter returns the username in the given filter if it is a userName eq
+// filter, e.g. userName eq "bob123". Attribute names and operators are case
+// insensitive.
+func parseFilter(filter string) (string, bool) {
+	parts := strings.Fields(filter)
+	if len(parts) != 3 || !strings.EqualFold(parts[0], "userName") ||
+		!strings.EqualFold(parts[1], "eq") {
+		return "", false
+	}
+	username, err := strconv.Unquote(parts[2])
+	if err != nil || !strings.HasPrefix(parts[2], `"`) {
+		return "", false
+	}
+	return username, true
+}
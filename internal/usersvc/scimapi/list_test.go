@@ -0,0 +1,134 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// usersByName is a db.Retriever that retrieves the users in it by their
+// usernames, or returns err for the ones that it does not have.
+type usersByName struct {
+	users map[string]usertbl.User
+	err   error
+}
+
+// Retrieve returns the user with the given username.
+func (u usersByName) Retrieve(
+	_ context.Context, username string,
+) (usertbl.User, error) {
+	if user, ok := u.users[username]; ok {
+		return user, nil
+	}
+	return usertbl.User{}, u.err
+}
+
+// TestListHandler tests the Handle method of ListHandler to assert that it
+// lists the members of the team a page at a time, filtered by username.
+func TestListHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	log := &log.FakeErrorer{}
+	users := map[string]usertbl.User{
+		"acme":   {Username: "acme", TeamID: "acme", IsAdmin: true},
+		"bob123": {Username: "bob123", TeamID: "acme"},
+		"jo1234": {Username: "jo1234", TeamID: "acme", IsDisabled: true},
+	}
+	team := teamtbl.Team{
+		ID: "acme", Members: []string{"acme", "bob123", "jo1234", "nouser"},
+	}
+	user := func(username string) User { return newUser(users[username]) }
+	list := func(total, start int, users ...User) ListResp {
+		if users == nil {
+			users = []User{}
+		}
+		return ListResp{
+			Schemas:      []string{schemaList},
+			TotalResults: total,
+			StartIndex:   start,
+			ItemsPerPage: len(users),
+			Resources:    users,
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		query       url.Values
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "InvalidFilter",
+			query:      url.Values{"filter": {`emails co "x"`}},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Only the userName eq filter is supported."),
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "All",
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(list(
+				4, 1, user("acme"), user("bob123"), user("jo1234"),
+			)),
+		},
+		{
+			name:       "Page",
+			query:      url.Values{"startIndex": {"2"}, "count": {"1"}},
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(list(4, 2, user("bob123"))),
+		},
+		{
+			name:       "PastEnd",
+			query:      url.Values{"startIndex": {"10"}},
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(list(4, 10)),
+		},
+		{
+			name:       "Filter",
+			query:      url.Values{"filter": {`userName eq "jo1234"`}},
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(list(1, 1, user("jo1234"))),
+		},
+		{
+			name:       "FilterNotMember",
+			query:      url.Values{"filter": {`USERNAME EQ "other"`}},
+			wantStatus: http.StatusOK,
+			assertFunc: assert.OnRespBody(list(0, 1)),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.team = team
+			retriever := usersByName{users: users, err: db.ErrNoItem}
+			if c.errRetrieve != nil {
+				retriever.err = c.errRetrieve
+			}
+			sut := NewListHandler(authorizer, retriever, log)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.URL.RawQuery = c.query.Encode()
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package scimapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PatchReq defines the body of PATCH SCIM user requests.
+type PatchReq struct {
+	Schemas    []string  `json:"schemas"`
+	Operations []PatchOp `json:"Operations"`
+}
+
+// PatchOp defines an operation of a PATCH SCIM user request. Only replacing
+// or adding the active attribute is supported, either by its path or as the
+// value with no path.
+type PatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
+// the SCIM user route, which is how most identity providers deactivate users.
+type PatchHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PATCH requests sent to the SCIM user route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalidSyntax",
+			"Invalid request body.", h.log,
+		)
+		return
+	}
+
+	// read the active attribute that the last operation sets
+	var active *bool
+	for _, op := range req.Operations {
+		v, ok := op.active()
+		if !ok {
+			writeErr(w, http.StatusBadRequest, "invalidPath",
+				"Only the active attribute can be changed.", h.log,
+			)
+			return
+		}
+		active = &v
+	}
+	if active == nil {
+		writeErr(w, http.StatusBadRequest, "invalidValue",
+			"Operations cannot be empty.", h.log,
+		)
+		return
+	}
+
+	updateActive(w, r, team, *active, h.userRetriever, h.userUpdater, h.log)
+}
+
+// active returns the value that the operation sets the active attribute to.
+// Some identity providers send the op in title case and the value as a
+// string, so both are accepted.
+func (op PatchOp) active() (bool, bool) {
+	if o := strings.ToLower(op.Op); o != "replace" && o != "add" {
+		return false, false
+	}
+	if op.Path != "" {
+		if !strings.EqualFold(op.Path, "active") {
+			return false, false
+		}
+		return parseBool(op.Value)
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &obj); err != nil || len(obj) != 1 {
+		return false, false
+	}
+	value, ok := obj["active"]
+	if !ok {
+		return false, false
+	}
+	return parseBool(value)
+}
+
+// parseBool parses the given JSON boolean or string holding one.
+func parseBool(value json.RawMessage) (bool, bool) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, true
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, false
+	}
+	b, err := strconv.ParseBool(s)
+	return b, err == nil
+}
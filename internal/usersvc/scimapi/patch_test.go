@@ -0,0 +1,141 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// accepts the ways identity providers set the active attribute and rejects
+// the changes to any other. The cases shared with PutHandler are covered in
+// TestPutHandler.
+func TestPatchHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(authorizer, userRetriever, userUpdater, log)
+	authorizer.team = teamtbl.Team{
+		ID: "acme", Members: []string{"acme", "bob123"},
+	}
+	userRetriever.Res = usertbl.User{Username: "bob123", TeamID: "acme"}
+	wantActive := func(want bool) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, _ *http.Response, _ []any) {
+			assert.Equal(t.Error, userUpdater.Updated.IsDisabled, !want)
+		}
+	}
+
+	for _, c := range []struct {
+		name       string
+		body       string
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "InvalidBody",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Invalid request body."),
+		},
+		{
+			name:       "NoOperations",
+			body:       `{"Operations": []}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Operations cannot be empty."),
+		},
+		{
+			name: "Remove",
+			body: `{"Operations": [
+				{"op": "remove", "path": "active"}
+			]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Only the active attribute can be changed."),
+		},
+		{
+			name: "OtherPath",
+			body: `{"Operations": [
+				{"op": "replace", "path": "userName", "value": "bob456"}
+			]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Only the active attribute can be changed."),
+		},
+		{
+			name: "OtherValue",
+			body: `{"Operations": [
+				{"op": "replace", "value": {"active": false, "title": "x"}}
+			]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Only the active attribute can be changed."),
+		},
+		{
+			name: "InvalidValue",
+			body: `{"Operations": [
+				{"op": "replace", "path": "active", "value": "maybe"}
+			]}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Only the active attribute can be changed."),
+		},
+		{
+			name: "Path",
+			body: `{"Operations": [
+				{"op": "replace", "path": "active", "value": false}
+			]}`,
+			wantStatus: http.StatusOK,
+			assertFunc: wantActive(false),
+		},
+		{
+			name: "NoPath",
+			body: `{"Operations": [
+				{"op": "Replace", "value": {"active": false}}
+			]}`,
+			wantStatus: http.StatusOK,
+			assertFunc: wantActive(false),
+		},
+		{
+			name: "StringValue",
+			body: `{"Operations": [
+				{"op": "Add", "path": "active", "value": "False"}
+			]}`,
+			wantStatus: http.StatusOK,
+			assertFunc: wantActive(false),
+		},
+		{
+			name: "LastWins",
+			body: `{"Operations": [
+				{"op": "replace", "path": "active", "value": false},
+				{"op": "replace", "path": "active", "value": true}
+			]}`,
+			wantStatus: http.StatusOK,
+			assertFunc: wantActive(true),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userUpdater.Updated = usertbl.User{IsDisabled: true}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPatch, "/", strings.NewReader(c.body),
+				),
+				map[string]string{"id": "bob123"},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
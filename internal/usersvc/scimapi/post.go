@@ -0,0 +1,149 @@
+package scimapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// UsernameValidator describes a type that can be used to validate the
+// usernames that users are provisioned with.
+type UsernameValidator interface{ Validate(string) []string }
+
+// PostReq defines the body of POST SCIM users requests. Users are active
+// unless active is set to false.
+type PostReq struct {
+	UserName string `json:"userName"`
+	Active   *bool  `json:"active"`
+}
+
+// PostHandler is an api.MethodHandler that can handle POST requests sent to
+// the SCIM users route. It provisions a user by adding them to the team and
+// inserting them into the user table without a password, so they can only log
+// in with the team's identity provider unless one is set for them. A user who
+// was deprovisioned is added back to the team.
+type PostHandler struct {
+	authorizer        Authorizer
+	usernameValidator UsernameValidator
+	teamUpdater       db.Updater[teamtbl.Team]
+	quota             quota.Quota
+	userRetriever     db.Retriever[usertbl.User]
+	userInserter      db.Inserter[usertbl.User]
+	userUpdater       db.Updater[usertbl.User]
+	log               log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authorizer Authorizer,
+	usernameValidator UsernameValidator,
+	teamUpdater db.Updater[teamtbl.Team],
+	quota quota.Quota,
+	userRetriever db.Retriever[usertbl.User],
+	userInserter db.Inserter[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authorizer:        authorizer,
+		usernameValidator: usernameValidator,
+		teamUpdater:       teamUpdater,
+		quota:             quota,
+		userRetriever:     userRetriever,
+		userInserter:      userInserter,
+		userUpdater:       userUpdater,
+		log:               log,
+	}
+}
+
+// Handle handles POST requests sent to the SCIM users route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	// decode and validate the user
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalidSyntax",
+			"Invalid request body.", h.log,
+		)
+		return
+	}
+	if errs := h.usernameValidator.Validate(req.UserName); len(errs) > 0 {
+		writeErr(w, http.StatusBadRequest, "invalidValue", errs[0], h.log)
+		return
+	}
+	active := req.Active == nil || *req.Active
+
+	// the username must not be taken, unless it is by a user of the team who
+	// was deprovisioned
+	user, err := h.userRetriever.Retrieve(r.Context(), req.UserName)
+	isNew := errors.Is(err, db.ErrNoItem)
+	if err != nil && !isNew {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+	if !isNew && (user.TeamID != team.ID || isMember(team, user)) {
+		writeErr(w, http.StatusConflict, "uniqueness",
+			"Username is already taken.", h.log,
+		)
+		return
+	}
+
+	// add the user to the team - they are already a member if inserting
+	// them failed after they were added
+	if !slices.Contains(team.Members, req.UserName) {
+		if !h.quota.Override(team.Quota).AllowsMember(len(team.Members)) {
+			writeErr(w, http.StatusForbidden, "",
+				"This team has reached its member limit. Please ask the "+
+					"team admin to raise it.",
+				h.log,
+			)
+			return
+		}
+		team.Members = append(team.Members, req.UserName)
+		if err = h.teamUpdater.Update(
+			r.Context(), team,
+		); errors.Is(err, db.ErrConflict) {
+			writeErr(w, http.StatusConflict, "",
+				"Team was modified by someone else. Please try again.", h.log,
+			)
+			return
+		} else if err != nil {
+			w.WriteHeader(api.ErrStatus(err))
+			h.log.Error(err)
+			return
+		}
+	}
+
+	// insert the user, or update the one who was deprovisioned
+	if isNew {
+		user = usertbl.NewUser(req.UserName, nil, false, team.ID)
+		setActive(&user, active)
+		err = h.userInserter.Insert(r.Context(), user)
+	} else {
+		setActive(&user, active)
+		err = h.userUpdater.Update(r.Context(), user)
+	}
+	if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newUser(user), h.log)
+}
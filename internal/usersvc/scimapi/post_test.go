@@ -0,0 +1,221 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	usernameValidator := &fakeUsernameValidator{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userInserter := &db.FakeInserter[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authorizer,
+		usernameValidator,
+		teamUpdater,
+		quota.Quota{MaxMembers: 3},
+		userRetriever,
+		userInserter,
+		userUpdater,
+		log,
+	)
+	team := teamtbl.Team{ID: "acme", Members: []string{"acme", "jo1234"}}
+	bob := User{
+		Schemas:  []string{schemaUser},
+		ID:       "bob123",
+		UserName: "bob123",
+		Active:   true,
+		Meta:     Meta{ResourceType: "User"},
+	}
+
+	for _, c := range []struct {
+		name          string
+		body          string
+		team          teamtbl.Team
+		errsValidate  []string
+		user          usertbl.User
+		errRetrieve   error
+		errUpdateTeam error
+		errInsert     error
+		errUpdateUser error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "InvalidBody",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Invalid request body."),
+		},
+		{
+			name:         "InvalidUsername",
+			body:         `{"userName": "b"}`,
+			errsValidate: []string{"Username too short."},
+			wantStatus:   http.StatusBadRequest,
+			assertFunc:   onErr("Username too short."),
+		},
+		{
+			name:        "ErrRetrieve",
+			body:        `{"userName": "bob123"}`,
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "TakenByOtherTeam",
+			body:       `{"userName": "bob123"}`,
+			user:       usertbl.User{Username: "bob123", TeamID: "other"},
+			wantStatus: http.StatusConflict,
+			assertFunc: onErr("Username is already taken."),
+		},
+		{
+			name:       "AlreadyMember",
+			body:       `{"userName": "jo1234"}`,
+			user:       usertbl.User{Username: "jo1234", TeamID: "acme"},
+			wantStatus: http.StatusConflict,
+			assertFunc: onErr("Username is already taken."),
+		},
+		{
+			name: "TeamFull",
+			body: `{"userName": "bob123"}`,
+			team: teamtbl.Team{
+				ID: "acme", Members: []string{"acme", "jo1234", "al1234"},
+			},
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusForbidden,
+			assertFunc: onErr(
+				"This team has reached its member limit. Please ask the " +
+					"team admin to raise it.",
+			),
+		},
+		{
+			name:          "TeamConflict",
+			body:          `{"userName": "bob123"}`,
+			errRetrieve:   db.ErrNoItem,
+			errUpdateTeam: db.ErrConflict,
+			wantStatus:    http.StatusConflict,
+			assertFunc: onErr(
+				"Team was modified by someone else. Please try again.",
+			),
+		},
+		{
+			name:          "ErrUpdateTeam",
+			body:          `{"userName": "bob123"}`,
+			errRetrieve:   db.ErrNoItem,
+			errUpdateTeam: errors.New("update team failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:        "ErrInsert",
+			body:        `{"userName": "bob123"}`,
+			errRetrieve: db.ErrNoItem,
+			errInsert:   errors.New("insert user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("insert user failed"),
+		},
+		{
+			name:          "ErrUpdateUser",
+			body:          `{"userName": "bob123"}`,
+			user:          usertbl.User{Username: "bob123", TeamID: "acme"},
+			errUpdateUser: errors.New("update user failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:        "Created",
+			body:        `{"userName": "bob123"}`,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.OnRespBody(bob)(t, resp, nil)
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.Members,
+					[]string{"acme", "jo1234", "bob123"},
+				)
+				got := userInserter.Inserted
+				assert.Equal(t.Error, got.Username, "bob123")
+				assert.Equal(t.Error, got.TeamID, "acme")
+				assert.True(t.Error, got.Password == nil)
+				assert.True(t.Error, !got.IsAdmin)
+				assert.True(t.Error, !got.IsDisabled)
+			},
+		},
+		{
+			name:        "CreatedInactive",
+			body:        `{"userName": "bob123", "active": false}`,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				want := bob
+				want.Active = false
+				assert.OnRespBody(want)(t, resp, nil)
+				assert.True(t.Error, userInserter.Inserted.IsDisabled)
+			},
+		},
+		{
+			name: "Reprovisioned",
+			body: `{"userName": "bob123"}`,
+			user: usertbl.User{
+				Username: "bob123", TeamID: "acme", IsDisabled: true,
+			},
+			wantStatus: http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.OnRespBody(bob)(t, resp, nil)
+				assert.AllEqual(t.Error,
+					teamUpdater.Updated.Members,
+					[]string{"acme", "jo1234", "bob123"},
+				)
+				assert.True(t.Error, !userUpdater.Updated.IsDisabled)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authorizer.team = team
+			if c.team.ID != "" {
+				authorizer.team = c.team
+			}
+			authorizer.team.Members = append(
+				[]string{}, authorizer.team.Members...,
+			)
+			usernameValidator.errs = c.errsValidate
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdateTeam
+			teamUpdater.Updated = teamtbl.Team{}
+			userInserter.Err = c.errInsert
+			userInserter.Inserted = usertbl.User{}
+			userUpdater.Err = c.errUpdateUser
+			userUpdater.Updated = usertbl.User{}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.body),
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
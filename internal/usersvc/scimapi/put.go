@@ -0,0 +1,114 @@
+package scimapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PutReq defines the body of PUT SCIM user requests. The user is active unless
+// active is set to false.
+type PutReq struct {
+	UserName string `json:"userName"`
+	Active   *bool  `json:"active"`
+}
+
+// PutHandler is an api.MethodHandler that can handle PUT requests sent to the
+// SCIM user route. Only whether the user is active can be changed.
+type PutHandler struct {
+	authorizer    Authorizer
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	authorizer Authorizer,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		authorizer:    authorizer,
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PUT requests sent to the SCIM user route.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	team, ok := authorize(w, r, h.authorizer, h.log)
+	if !ok {
+		return
+	}
+
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalidSyntax",
+			"Invalid request body.", h.log,
+		)
+		return
+	}
+	if req.UserName != api.PathParam(r, "id") {
+		writeErr(w, http.StatusBadRequest, "mutability",
+			"Username cannot be changed.", h.log,
+		)
+		return
+	}
+
+	updateActive(
+		w, r, team, req.Active == nil || *req.Active,
+		h.userRetriever, h.userUpdater, h.log,
+	)
+}
+
+// updateActive activates or deactivates the member of the team with the ID in
+// the request's path and writes them as the response. The team's admin cannot
+// be deactivated so that the team is never left without one.
+func updateActive(
+	w http.ResponseWriter, r *http.Request, team teamtbl.Team, active bool,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) {
+	user, err := userRetriever.Retrieve(r.Context(), api.PathParam(r, "id"))
+	if errors.Is(err, db.ErrNoItem) || err == nil && !isMember(team, user) {
+		writeErr(w, http.StatusNotFound, "", "User not found.", log)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		log.Error(err)
+		return
+	}
+	if !active && user.Username == team.ID {
+		writeErr(w, http.StatusBadRequest, "mutability",
+			"Team admin cannot be deactivated.", log,
+		)
+		return
+	}
+
+	setActive(&user, active)
+	if err = userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		writeErr(w, http.StatusNotFound, "", "User not found.", log)
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		log.Error(err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUser(user), log)
+}
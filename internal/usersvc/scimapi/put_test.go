@@ -0,0 +1,162 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(authorizer, userRetriever, userUpdater, log)
+	authorizer.team = teamtbl.Team{
+		ID: "acme", Members: []string{"acme", "bob123"},
+	}
+
+	for _, c := range []struct {
+		name        string
+		id          string
+		body        string
+		user        usertbl.User
+		errRetrieve error
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "InvalidBody",
+			id:         "bob123",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Invalid request body."),
+		},
+		{
+			name:       "UsernameChanged",
+			id:         "bob123",
+			body:       `{"userName": "bob456"}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Username cannot be changed."),
+		},
+		{
+			name:        "NotFound",
+			id:          "bob123",
+			body:        `{"userName": "bob123"}`,
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  onErr("User not found."),
+		},
+		{
+			name:       "NotMember",
+			id:         "bob123",
+			body:       `{"userName": "bob123"}`,
+			user:       usertbl.User{Username: "bob123", TeamID: "other"},
+			wantStatus: http.StatusNotFound,
+			assertFunc: onErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			id:          "bob123",
+			body:        `{"userName": "bob123"}`,
+			errRetrieve: errors.New("retrieve user failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:       "DeactivateAdmin",
+			id:         "acme",
+			body:       `{"userName": "acme", "active": false}`,
+			user:       usertbl.User{Username: "acme", TeamID: "acme"},
+			wantStatus: http.StatusBadRequest,
+			assertFunc: onErr("Team admin cannot be deactivated."),
+		},
+		{
+			name:       "UpdateNotFound",
+			id:         "bob123",
+			body:       `{"userName": "bob123"}`,
+			user:       usertbl.User{Username: "bob123", TeamID: "acme"},
+			errUpdate:  db.ErrNoItem,
+			wantStatus: http.StatusNotFound,
+			assertFunc: onErr("User not found."),
+		},
+		{
+			name:       "ErrUpdate",
+			id:         "bob123",
+			body:       `{"userName": "bob123"}`,
+			user:       usertbl.User{Username: "bob123", TeamID: "acme"},
+			errUpdate:  errors.New("update user failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name: "Deactivated",
+			id:   "bob123",
+			body: `{"userName": "bob123", "active": false}`,
+			user: usertbl.User{
+				Username: "bob123",
+				TeamID:   "acme",
+				Sessions: []usertbl.Session{{ID: "s1"}},
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.OnRespBody(User{
+					Schemas:  []string{schemaUser},
+					ID:       "bob123",
+					UserName: "bob123",
+					Active:   false,
+					Meta:     Meta{ResourceType: "User"},
+				})(t, resp, nil)
+				assert.True(t.Error, userUpdater.Updated.IsDisabled)
+				assert.Equal(t.Error, len(userUpdater.Updated.Sessions), 0)
+			},
+		},
+		{
+			name: "Activated",
+			id:   "bob123",
+			body: `{"userName": "bob123"}`,
+			user: usertbl.User{
+				Username: "bob123", TeamID: "acme", IsDisabled: true,
+			},
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.True(t.Error, !userUpdater.Updated.IsDisabled)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			userUpdater.Updated = usertbl.User{}
+			w := httptest.NewRecorder()
+			r := api.WithPathParams(
+				httptest.NewRequest(
+					http.MethodPut, "/", strings.NewReader(c.body),
+				),
+				map[string]string{"id": c.id},
+			)
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
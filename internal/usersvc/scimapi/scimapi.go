@@ -0,0 +1,171 @@
+// Package scimapi contains code for responding to HTTP requests made to the
+// SCIM 2.0 Users API routes, which the identity provider of a team uses to
+// provision and deprovision the team's members. They are authenticated with
+// the team's SCIM token rather than with auth tokens.
+//
+// See https://www.rfc-editor.org/rfc/rfc7644.
+package scimapi
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// The URIs of the SCIM schemas used by the routes.
+const (
+	schemaUser      = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaList      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaError     = "urn:ietf:params:scim:api:messages:2.0:Error"
+	contentTypeSCIM = "application/scim+json"
+)
+
+// ErrUnauthorized means that a request did not carry the SCIM token of the
+// team that it was made to.
+var ErrUnauthorized = errors.New("scimapi: unauthorized")
+
+// Authorizer describes a type that can be used to check whether a request was
+// made by the identity provider of the team in its path.
+type Authorizer interface {
+	Authorize(r *http.Request) (teamtbl.Team, error)
+}
+
+// TokenAuthorizer is an Authorizer that authorizes the requests that carry the
+// SCIM token of the team in their path as a bearer token.
+type TokenAuthorizer struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+}
+
+// NewTokenAuthorizer creates and returns a new TokenAuthorizer.
+func NewTokenAuthorizer(
+	teamRetriever db.Retriever[teamtbl.Team],
+) TokenAuthorizer {
+	return TokenAuthorizer{teamRetriever: teamRetriever}
+}
+
+// Authorize returns the team in the request's path if the request carries its
+// SCIM token, or ErrUnauthorized if it does not. The tokens are compared by
+// their hashes in constant time.
+func (a TokenAuthorizer) Authorize(r *http.Request) (teamtbl.Team, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return teamtbl.Team{}, ErrUnauthorized
+	}
+	team, err := a.teamRetriever.Retrieve(
+		r.Context(), api.PathParam(r, "teamID"),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		return teamtbl.Team{}, ErrUnauthorized
+	} else if err != nil {
+		return teamtbl.Team{}, err
+	}
+	hash := sha256.Sum256([]byte(token))
+	if len(team.SCIMTokenHash) == 0 || subtle.ConstantTimeCompare(
+		hash[:], team.SCIMTokenHash,
+	) != 1 {
+		return teamtbl.Team{}, ErrUnauthorized
+	}
+	return team, nil
+}
+
+// User defines the SCIM representation of a user. Its ID is the username,
+// which cannot be changed.
+type User struct {
+	Schemas  []string `json:"schemas"`
+	ID       string   `json:"id"`
+	UserName string   `json:"userName"`
+	Active   bool     `json:"active"`
+	Meta     Meta     `json:"meta"`
+}
+
+// Meta defines the metadata of a SCIM resource.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+}
+
+// newUser returns the SCIM representation of the given user.
+func newUser(u usertbl.User) User {
+	return User{
+		Schemas:  []string{schemaUser},
+		ID:       u.Username,
+		UserName: u.Username,
+		Active:   !u.IsDisabled,
+		Meta:     Meta{ResourceType: "User"},
+	}
+}
+
+// Error defines the body of SCIM error responses.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	SCIMType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// isMember returns whether the given user is a member of the given team. A
+// user who was deprovisioned keeps their team ID but is no longer one of its
+// members.
+func isMember(team teamtbl.Team, user usertbl.User) bool {
+	return user.TeamID == team.ID &&
+		slices.Contains(team.Members, user.Username)
+}
+
+// authorize authorizes the request with the given authorizer, writing the
+// error response and returning false if it was not authorized.
+func authorize(
+	w http.ResponseWriter, r *http.Request, a Authorizer, log log.Errorer,
+) (teamtbl.Team, bool) {
+	team, err := a.Authorize(r)
+	if errors.Is(err, ErrUnauthorized) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeErr(w, http.StatusUnauthorized, "", "Invalid SCIM token.", log)
+		return teamtbl.Team{}, false
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		log.Error(err)
+		return teamtbl.Team{}, false
+	}
+	return team, true
+}
+
+// writeJSON writes the given status and SCIM response body.
+func writeJSON(w http.ResponseWriter, status int, v any, log log.Errorer) {
+	w.Header().Set("Content-Type", contentTypeSCIM)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error(err)
+	}
+}
+
+// writeErr writes the given status and SCIM error.
+func writeErr(
+	w http.ResponseWriter, status int, scimType, detail string,
+	log log.Errorer,
+) {
+	writeJSON(w, status, Error{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	}, log)
+}
+
+// setActive activates or deactivates the user. Deactivating a user also
+// revokes all of their sessions so that they are signed out right away.
+func setActive(user *usertbl.User, active bool) {
+	user.IsDisabled = !active
+	if !active {
+		user.Sessions = nil
+	}
+}
@@ -0,0 +1,108 @@
+//go:build utest
+
+package scimapi
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// onErr returns a function that asserts that the response is a SCIM error with
+// the given detail. Like assert.OnRespErr, it can be initialised in
+// table-driven tests.
+func onErr(wantDetail string) func(*testing.T, *http.Response, []any) {
+	return func(t *testing.T, resp *http.Response, _ []any) {
+		assert.Equal(t.Error,
+			resp.Header.Get("Content-Type"), contentTypeSCIM,
+		)
+		var got Error
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		assert.AllEqual(t.Error, got.Schemas, []string{schemaError})
+		assert.Equal(t.Error, got.Detail, wantDetail)
+	}
+}
+
+// TestTokenAuthorizer tests the Authorize method of TokenAuthorizer to assert
+// that it only authorizes the requests carrying the SCIM token of the team in
+// their path.
+func TestTokenAuthorizer(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	sut := NewTokenAuthorizer(teamRetriever)
+	hash := sha256.Sum256([]byte("s3cret"))
+	errRetrieve := errors.New("retrieve failed")
+
+	for _, c := range []struct {
+		name        string
+		header      string
+		team        teamtbl.Team
+		errRetrieve error
+		wantErr     error
+	}{
+		{name: "NoHeader", wantErr: ErrUnauthorized},
+		{
+			name:    "NotBearer",
+			header:  "Basic s3cret",
+			team:    teamtbl.Team{SCIMTokenHash: hash[:]},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:        "TeamNotFound",
+			header:      "Bearer s3cret",
+			errRetrieve: db.ErrNoItem,
+			wantErr:     ErrUnauthorized,
+		},
+		{
+			name:        "ErrRetrieve",
+			header:      "Bearer s3cret",
+			errRetrieve: errRetrieve,
+			wantErr:     errRetrieve,
+		},
+		{
+			name:    "NotEnabled",
+			header:  "Bearer s3cret",
+			team:    teamtbl.Team{},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "WrongToken",
+			header:  "Bearer s3cre",
+			team:    teamtbl.Team{SCIMTokenHash: hash[:]},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:   "OK",
+			header: "Bearer s3cret",
+			team:   teamtbl.Team{ID: "acme", SCIMTokenHash: hash[:]},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			r := api.WithPathParams(
+				httptest.NewRequest("", "/scim/acme/v2/Users", nil),
+				map[string]string{"teamID": "acme"},
+			)
+			if c.header != "" {
+				r.Header.Set("Authorization", c.header)
+			}
+
+			team, err := sut.Authorize(r)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			if c.wantErr == nil {
+				assert.Equal(t.Error, team.ID, "acme")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package sessionapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DeleteResp defines the body of DELETE session responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can handle DELETE requests sent
+// to the user session route. Deleting a session revokes the auth token that
+// was issued for it, signing out the device it was issued to.
+type DeleteHandler struct {
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	clock         clock.Clock
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	clock clock.Clock,
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		clock:         clock,
+		log:           log,
+	}
+}
+
+// Handle handles DELETE requests sent to the user session route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// remove the session, pruning the expired ones as we go
+	id, now := api.PathParam(r, "sessionID"), h.clock.Now().Unix()
+	if !user.HasSession(id, now) {
+		h.writeErr(w, http.StatusNotFound, "Session not found.")
+		return
+	}
+	var sessions []usertbl.Session
+	for _, s := range user.Sessions {
+		if s.ID != id && s.ExpiresAt > now {
+			sessions = append(sessions, s)
+		}
+	}
+	user.Sessions = sessions
+
+	// update the user
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		h.writeErr(w, http.StatusNotFound, "User not found.")
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+}
+
+// writeErr writes the given status and error message.
+func (h DeleteHandler) writeErr(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(DeleteResp{Error: msg}); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build utest
+
+package sessionapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewDeleteHandler(userRetriever, userUpdater, clk, log)
+
+	future := clk.Time.Add(time.Hour).Unix()
+	user := usertbl.User{
+		Username: "bob123",
+		Sessions: []usertbl.Session{
+			{ID: "sess1", ExpiresAt: 1},
+			{ID: "sess2", ExpiresAt: future},
+			{ID: "sess3", ExpiresAt: future},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		errRetrieve error
+		sessionID   string
+		errUpdate   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "UserNotFound",
+			errRetrieve: db.ErrNoItem,
+			sessionID:   "sess2",
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve failed"),
+			sessionID:   "sess2",
+			errUpdate:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "SessionNotFound",
+			errRetrieve: nil,
+			sessionID:   "sess4",
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Session not found."),
+		},
+		{
+			name:        "SessionExpired",
+			errRetrieve: nil,
+			sessionID:   "sess1",
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Session not found."),
+		},
+		{
+			name:        "UserNotFoundOnUpdate",
+			errRetrieve: nil,
+			sessionID:   "sess2",
+			errUpdate:   db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrUpdate",
+			errRetrieve: nil,
+			sessionID:   "sess2",
+			errUpdate:   errors.New("update failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:        "OK",
+			errRetrieve: nil,
+			sessionID:   "sess2",
+			errUpdate:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				// the session should be removed along with the expired one
+				sessions := userUpdater.Updated.Sessions
+				assert.Equal(t.Fatal, len(sessions), 1)
+				assert.Equal(t.Error, sessions[0].ID, "sess3")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = user
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = api.WithPathParams(r, map[string]string{
+				"sessionID": c.sessionID,
+			})
+
+			sut.Handle(w, r, cookie.Auth{Username: "bob123"})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package sessionapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET sessions responses.
+type GetResp struct {
+	Error    string    `json:"error,omitempty"`
+	Sessions []Session `json:"sessions,omitempty"`
+}
+
+// Session defines a session element in GetResp.
+type Session struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"userAgent"`
+	IssuedAt  int64  `json:"issuedAt"`
+	ExpiresAt int64  `json:"expiresAt"`
+	IsCurrent bool   `json:"isCurrent"`
+}
+
+// GetHandler is an api.MethodHandler that can handle GET requests sent to the
+// user sessions route.
+type GetHandler struct {
+	userRetriever db.Retriever[usertbl.User]
+	clock         clock.Clock
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	userRetriever db.Retriever[usertbl.User],
+	clock clock.Clock,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		userRetriever: userRetriever,
+		clock:         clock,
+		log:           log,
+	}
+}
+
+// Handle handles GET requests sent to the user sessions route.
+func (h GetHandler) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		h.writeResp(w, http.StatusNotFound, GetResp{
+			Error: "User not found.",
+		})
+		return
+	} else if err != nil {
+		w.WriteHeader(api.ErrStatus(err))
+		h.log.Error(err)
+		return
+	}
+
+	// list the sessions that have not expired
+	now := h.clock.Now().Unix()
+	sessions := []Session{}
+	for _, s := range user.Sessions {
+		if s.ExpiresAt <= now {
+			continue
+		}
+		sessions = append(sessions, Session{
+			ID:        s.ID,
+			UserAgent: s.UserAgent,
+			IssuedAt:  s.IssuedAt,
+			ExpiresAt: s.ExpiresAt,
+			IsCurrent: s.ID == auth.SessionID,
+		})
+	}
+
+	h.writeResp(w, http.StatusOK, GetResp{Sessions: sessions})
+}
+
+// writeResp writes the given status and response body.
+func (h GetHandler) writeResp(w http.ResponseWriter, status int, resp GetResp) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build utest
+
+package sessionapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut := NewGetHandler(userRetriever, clk, log)
+
+	future := clk.Time.Add(time.Hour).Unix()
+	user := usertbl.User{
+		Username: "bob123",
+		Sessions: []usertbl.Session{
+			{ID: "sess1", UserAgent: "old", IssuedAt: 0, ExpiresAt: 1},
+			{ID: "sess2", UserAgent: "phone", IssuedAt: 2, ExpiresAt: future},
+			{ID: "sess3", UserAgent: "laptop", IssuedAt: 3, ExpiresAt: future},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		errRetrieve error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "UserNotFound",
+			errRetrieve: db.ErrNoItem,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			errRetrieve: errors.New("retrieve failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:        "RetrieveTimeout",
+			errRetrieve: context.DeadlineExceeded,
+			wantStatus:  http.StatusGatewayTimeout,
+			assertFunc: assert.OnLoggedErr(
+				context.DeadlineExceeded.Error(),
+			),
+		},
+		{
+			name:        "OK",
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			// the expired session should not be listed and the session of the
+			// request should be flagged as current
+			assertFunc: assert.OnRespBody(GetResp{Sessions: []Session{
+				{
+					ID:        "sess2",
+					UserAgent: "phone",
+					IssuedAt:  2,
+					ExpiresAt: future,
+					IsCurrent: false,
+				},
+				{
+					ID:        "sess3",
+					UserAgent: "laptop",
+					IssuedAt:  3,
+					ExpiresAt: future,
+					IsCurrent: true,
+				},
+			}}),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = user
+			userRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			sut.Handle(w, r, cookie.Auth{
+				Username: "bob123", SessionID: "sess3",
+			})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
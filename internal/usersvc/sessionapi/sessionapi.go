@@ -0,0 +1,4 @@
+// Package sessionapi contains code for responding to HTTP requests made to the
+// user sessions API route, which is used for listing the devices a user is
+// logged in on and signing them out.
+package sessionapi
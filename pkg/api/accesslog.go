@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// RequestIDHeader is the header the ID of a request is read from, if the
+// client or a proxy in front of the service set it, and written back in.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen is the maximum length of a request ID taken from a request
+// header, so that a client cannot flood the logs through it.
+const maxRequestIDLen = 64
+
+// accessKey is the context key AccessLog stores the writer of the request
+// under so that Authed can record the user on it.
+type accessKey struct{}
+
+// AccessLog returns a Middleware that logs a line for each request once it is
+// handled, with its method, path, status, duration, user, request ID, and the
+// number of bytes in its response body. The values are logged as key=value
+// pairs so that the lines can be parsed by log processors.
+//
+// Each request is given an ID unless it has a valid one in RequestIDHeader,
+// which is set on its response so that clients can report it. The user is
+// recorded by Authed, so it is empty for requests that are not authenticated.
+func AccessLog(clock clock.Clock, log log.Infoer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := clock.Now()
+
+			id := r.Header.Get(RequestIDHeader)
+			if !isValidRequestID(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			aw := &accessWriter{ResponseWriter: w}
+			next.ServeHTTP(aw, r.WithContext(
+				context.WithValue(r.Context(), accessKey{}, aw),
+			))
+			if aw.status == 0 {
+				aw.status = http.StatusOK
+			}
+
+			log.Info(logfmt(
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", strconv.Itoa(aw.status),
+				"duration", clock.Now().Sub(start).String(),
+				"user", aw.user,
+				"request_id", id,
+				"bytes", strconv.Itoa(aw.bytes),
+			))
+		})
+	}
+}
+
+// recordUser records the given username on the access log line of the request
+// with the given context, if it is logged.
+func recordUser(ctx context.Context, username string) {
+	if aw, ok := ctx.Value(accessKey{}).(*accessWriter); ok {
+		aw.user = username
+	}
+}
+
+// accessUser returns the username recorded on the access log line of the
+// request with the given context, which is empty if it is not logged or not
+// authenticated.
+func accessUser(ctx context.Context) string {
+	if aw, ok := ctx.Value(accessKey{}).(*accessWriter); ok {
+		return aw.user
+	}
+	return ""
+}
+
+// isValidRequestID returns whether the given request ID is safe to log as-is.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' || c == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// logfmt formats the given key-value pairs as space-separated key=value pairs,
+// quoting the values that are empty or contain spaces or quotes.
+func logfmt(keyValues ...string) string {
+	var sb strings.Builder
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(keyValues[i])
+		sb.WriteByte('=')
+		if v := keyValues[i+1]; v == "" || strings.ContainsAny(v, " \"=") {
+			sb.WriteString(strconv.Quote(v))
+		} else {
+			sb.WriteString(v)
+		}
+	}
+	return sb.String()
+}
+
+// accessWriter is a http.ResponseWriter that records the status and the size
+// of the response for the access log.
+type accessWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	user   string
+}
+
+// WriteHeader records the status and writes it to the wrapped writer.
+func (aw *accessWriter) WriteHeader(status int) {
+	if aw.status == 0 {
+		aw.status = status
+	}
+	aw.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the body written to the wrapped writer.
+func (aw *accessWriter) Write(b []byte) (int, error) {
+	if aw.status == 0 {
+		aw.status = http.StatusOK
+	}
+	n, err := aw.ResponseWriter.Write(b)
+	aw.bytes += n
+	return n, err
+}
+
+// Unwrap returns the wrapped writer so that http.ResponseController can reach
+// its optional methods.
+func (aw *accessWriter) Unwrap() http.ResponseWriter {
+	return aw.ResponseWriter
+}
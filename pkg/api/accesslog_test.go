@@ -0,0 +1,95 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestAccessLog tests the handler returned by AccessLog to assert that it logs
+// a single key=value line per request with the details of its response.
+func TestAccessLog(t *testing.T) {
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+
+	t.Run("OK", func(t *testing.T) {
+		log := &log.FakeInfoer{}
+		sut := AccessLog(clk, log)(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				clk.Advance(1500 * time.Microsecond)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("hello"))
+			},
+		))
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/tasks?secret=1", nil)
+		r.Header.Set(RequestIDHeader, "req-1")
+
+		sut.ServeHTTP(w, r)
+
+		assert.Equal(t.Error, w.Code, http.StatusCreated)
+		assert.Equal(t.Error, w.Header().Get(RequestIDHeader), "req-1")
+		assert.Equal(t.Fatal, len(log.Args), 1)
+		assert.Equal(t.Error, log.Args[0],
+			`method=POST path=/tasks status=201 duration=1.5ms user="" `+
+				`request_id=req-1 bytes=5`,
+		)
+	})
+
+	t.Run("Authed", func(t *testing.T) {
+		errLog := &log.FakeErrorer{}
+		log := &log.FakeInfoer{}
+		sut := AccessLog(clk, log)(NewHandler(map[string]MethodHandler{
+			http.MethodGet: Authed(
+				&cookie.FakeDecoder[cookie.Auth]{
+					Res: cookie.Auth{Username: "bob123"},
+				},
+				errLog,
+				&FakeMethodHandler{},
+			),
+		}))
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/team", nil)
+		r.AddCookie(&http.Cookie{Name: cookie.AuthName, Value: "token"})
+
+		sut.ServeHTTP(w, r)
+
+		assert.Equal(t.Fatal, len(log.Args), 1)
+		line := log.Args[0].(string)
+		for _, want := range []string{
+			"method=GET ", "status=200 ", "user=bob123 ", "bytes=0",
+		} {
+			assert.True(t.Error, strings.Contains(line, want))
+		}
+	})
+
+	t.Run("GeneratedRequestID", func(t *testing.T) {
+		for _, header := range []string{
+			"", "has space", `has"quote`, strings.Repeat("a", 65),
+		} {
+			log := &log.FakeInfoer{}
+			sut := AccessLog(clk, log)(http.HandlerFunc(
+				func(http.ResponseWriter, *http.Request) {},
+			))
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(RequestIDHeader, header)
+
+			sut.ServeHTTP(w, r)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.Equal(t.Error, len(id), 36)
+			assert.True(t.Error, strings.Contains(
+				log.Args[0].(string), "request_id="+id,
+			))
+		}
+	})
+}
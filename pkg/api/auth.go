@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// authKey is the context key Authed stores the decoded auth token under.
+type authKey struct{}
+
+// authResp defines the body of the responses written by Authed.
+type authResp struct {
+	Error string `json:"error"`
+}
+
+// Authed wraps the given method handler so that it is only called for requests
+// that carry a valid auth token, either in the auth cookie or as a bearer token
+// in the Authorization header. Other requests get 401 Unauthorized. The
+// decoded token is passed to the handler as the third argument of Handle, as
+// well as in the request context, where it can be read with AuthFrom.
+//
+// The errors that the decoder returns for reasons other than the token being
+// invalid, such as the session table being unavailable, are logged and get
+// the status returned by ErrStatus so that clients do not log their users out
+// during an outage.
+func Authed(
+	decoder cookie.Decoder[cookie.Auth], log log.Errorer, next MethodHandler,
+) MethodHandler {
+	return authed{decoder: decoder, log: log, next: next}
+}
+
+// authed is the MethodHandler returned by Authed.
+type authed struct {
+	decoder cookie.Decoder[cookie.Auth]
+	log     log.Errorer
+	next    MethodHandler
+}
+
+// Handle decodes the auth token of the request and calls the wrapped handler
+// with it if it is valid.
+func (a authed) Handle(
+	w http.ResponseWriter, r *http.Request, _ cookie.Auth,
+) {
+	token, ok := authToken(r)
+	if !ok {
+		writeAuthErr(w, "Auth token not found.")
+		return
+	}
+
+	auth, err := a.decoder.Decode(
+		r.Context(), http.Cookie{Name: cookie.AuthName, Value: token},
+	)
+	if isTokenErr(err) {
+		writeAuthErr(w, "Invalid auth token.")
+		return
+	} else if err != nil {
+		a.log.Error(err)
+		w.WriteHeader(ErrStatus(err))
+		return
+	}
+
+	recordUser(r.Context(), auth.Username)
+	a.next.Handle(w, r.WithContext(WithAuth(r.Context(), auth)), auth)
+}
+
+// authToken returns the auth token that the given request carries, either in
+// the auth cookie or as a bearer token in the Authorization header, and whether
+// it carries one.
+func authToken(r *http.Request) (string, bool) {
+	if ck, err := r.Cookie(cookie.AuthName); err == nil {
+		return ck.Value, true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+// isTokenErr returns whether the given error was returned by a decoder because
+// the token it was given was invalid, as opposed to it failing to check it.
+func isTokenErr(err error) bool {
+	var vErr *jwt.ValidationError
+	return errors.Is(err, cookie.ErrInvalid) || errors.As(err, &vErr) ||
+		errors.Is(err, jwt.ErrTokenExpired)
+}
+
+// WithAuth returns a copy of the given context that carries the given auth
+// token, as Authed passes it to the handlers it wraps.
+func WithAuth(ctx context.Context, auth cookie.Auth) context.Context {
+	return context.WithValue(ctx, authKey{}, auth)
+}
+
+// AuthFrom returns the auth token carried by the given context, which is the
+// zero Auth unless the context is of a request handled through Authed.
+func AuthFrom(ctx context.Context) cookie.Auth {
+	auth, _ := ctx.Value(authKey{}).(cookie.Auth)
+	return auth
+}
+
+// writeAuthErr writes the response for a request that failed authentication.
+func writeAuthErr(w http.ResponseWriter, msg string) {
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(authResp{Error: msg})
+}
@@ -0,0 +1,166 @@
+//go:build utest
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// fakeMethodHandler is a test fake for MethodHandler that records the auth
+// token it was called with, both as an argument and in the request context.
+type fakeMethodHandler struct {
+	called  bool
+	auth    cookie.Auth
+	ctxAuth cookie.Auth
+}
+
+// Handle records the arguments it was called with.
+func (f *fakeMethodHandler) Handle(
+	_ http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	f.called = true
+	f.auth = auth
+	f.ctxAuth = AuthFrom(r.Context())
+}
+
+// TestAuthed tests the MethodHandler returned by Authed to assert that it only
+// calls the wrapped handler with a valid auth token, and that it responds
+// with 401 only when the token is invalid.
+func TestAuthed(t *testing.T) {
+	decoder := &cookie.FakeDecoder[cookie.Auth]{}
+	auth := cookie.Auth{Username: "bob123", IsAdmin: true, TeamID: "team1"}
+	errA := errors.New("failed")
+
+	for _, c := range []struct {
+		name       string
+		cookie     string
+		authHeader string
+		errDecode  error
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoAuth",
+			cookie:     "",
+			authHeader: "",
+			errDecode:  nil,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:       "NotBearer",
+			cookie:     "",
+			authHeader: "Basic Ym9iOnB3ZA==",
+			errDecode:  nil,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:       "InvalidAuth",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode:  cookie.ErrInvalid,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "TokenMalformed",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode: jwt.NewValidationError(
+				"malformed", jwt.ValidationErrorMalformed,
+			),
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "TokenExpired",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode:  jwt.ErrTokenExpired,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "DecodeErr",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode:  errA,
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr(errA.Error()),
+		},
+		{
+			name:       "DecodeTimeout",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode:  context.DeadlineExceeded,
+			wantStatus: http.StatusGatewayTimeout,
+			assertFunc: assert.OnLoggedErr(context.DeadlineExceeded.Error()),
+		},
+		{
+			name:       "OKCookie",
+			cookie:     "nonempty",
+			authHeader: "",
+			errDecode:  nil,
+			wantStatus: http.StatusOK,
+			assertFunc: func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:       "OKBearer",
+			cookie:     "",
+			authHeader: "Bearer nonempty",
+			errDecode:  nil,
+			wantStatus: http.StatusOK,
+			assertFunc: func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			decoder.Res = auth
+			decoder.Err = c.errDecode
+			next := &fakeMethodHandler{}
+			log := &log.FakeErrorer{}
+			sut := Authed(decoder, log, next)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if c.cookie != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.cookie,
+				})
+			}
+			if c.authHeader != "" {
+				r.Header.Set("Authorization", c.authHeader)
+			}
+
+			sut.Handle(w, r, cookie.Auth{})
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+			wantCalled := c.wantStatus == http.StatusOK
+			assert.Equal(t.Error, next.called, wantCalled)
+			if wantCalled {
+				assert.Equal(t.Error, next.auth, auth)
+				assert.Equal(t.Error, next.ctxAuth, auth)
+			}
+		})
+	}
+}
+
+// TestAuthFrom tests AuthFrom to assert that it returns the auth token stored
+// by WithAuth, and the zero Auth for other contexts.
+func TestAuthFrom(t *testing.T) {
+	auth := cookie.Auth{Username: "bob123"}
+
+	assert.Equal(t.Error, AuthFrom(WithAuth(context.Background(), auth)), auth)
+	assert.Equal(t.Error, AuthFrom(context.Background()), cookie.Auth{})
+}
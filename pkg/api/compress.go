@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressMinSize is the size in bytes below which response bodies are sent
+// uncompressed, as compressing them would save little or even add bytes.
+const compressMinSize = 1024
+
+// Compress wraps the given handler so that its response bodies are
+// gzip-compressed for clients that accept it. Bodies smaller than
+// compressMinSize and responses that the handler already encoded are sent
+// as-is. It must wrap any handler that records or replays responses (e.g.
+// Idempotent) so that those only ever see uncompressed bodies.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead ||
+			!acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, status: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptsGzip returns whether the given Accept-Encoding header value allows a
+// gzip-encoded response.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		name, val, _ := strings.Cut(strings.TrimSpace(params), "=")
+		if strings.TrimSpace(name) != "q" {
+			return true
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return err != nil || q > 0
+	}
+	return false
+}
+
+// compressWriter is a http.ResponseWriter that holds back the status and the
+// start of the body until it knows whether the body is large enough to be
+// worth compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+
+	// started is set when the status was written to the wrapped writer, after
+	// which the body is written through gz if it is set or directly otherwise.
+	started bool
+	gz      *gzip.Writer
+}
+
+// WriteHeader records the status to be written when the body's encoding is
+// known.
+func (cw *compressWriter) WriteHeader(status int) {
+	if !cw.started {
+		cw.status = status
+	}
+}
+
+// Write buffers the body until it reaches compressMinSize, and then writes it
+// compressed.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.started {
+		cw.buf.Write(b)
+		if cw.buf.Len() < compressMinSize {
+			return len(b), nil
+		}
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.gz != nil {
+		return cw.gz.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// start writes the status and the buffered body to the wrapped writer,
+// compressed if compress is set and the handler has not encoded the body
+// itself.
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	h := cw.ResponseWriter.Header()
+	if compress && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		cw.gz = gzip.NewWriter(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if cw.gz != nil {
+		_, err := cw.gz.Write(cw.buf.Bytes())
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(cw.buf.Bytes())
+	return err
+}
+
+// close flushes the response, writing bodies smaller than compressMinSize
+// uncompressed.
+func (cw *compressWriter) close() {
+	if !cw.started {
+		_ = cw.start(false)
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Close()
+	}
+}
@@ -0,0 +1,119 @@
+//go:build utest
+
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestCompress tests the handler returned by Compress to assert that it only
+// compresses large enough responses for clients that accept gzip.
+func TestCompress(t *testing.T) {
+	var (
+		body     string
+		encoding string
+	)
+	sut := Compress(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			w.WriteHeader(http.StatusCreated)
+			// write in chunks to cover bodies that cross the threshold
+			for i := 0; i < len(body); i += 100 {
+				_, _ = w.Write([]byte(body[i:min(i+100, len(body))]))
+			}
+		},
+	))
+	large := strings.Repeat(`{"title":"task"}`, compressMinSize/16+1)
+
+	for _, c := range []struct {
+		name           string
+		acceptEncoding string
+		body           string
+		encoding       string
+		wantEncoding   string
+	}{
+		{
+			name:           "NotAccepted",
+			acceptEncoding: "",
+			body:           large,
+			encoding:       "",
+			wantEncoding:   "",
+		},
+		{
+			name:           "Refused",
+			acceptEncoding: "br, gzip;q=0",
+			body:           large,
+			encoding:       "",
+			wantEncoding:   "",
+		},
+		{
+			name:           "Small",
+			acceptEncoding: "gzip",
+			body:           `{"title":"task"}`,
+			encoding:       "",
+			wantEncoding:   "",
+		},
+		{
+			name:           "Empty",
+			acceptEncoding: "gzip",
+			body:           "",
+			encoding:       "",
+			wantEncoding:   "",
+		},
+		{
+			name:           "AlreadyEncoded",
+			acceptEncoding: "gzip",
+			body:           large,
+			encoding:       "identity",
+			wantEncoding:   "identity",
+		},
+		{
+			name:           "Compressed",
+			acceptEncoding: "br;q=1.0, gzip;q=0.8",
+			body:           large,
+			encoding:       "",
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "Wildcard",
+			acceptEncoding: "*",
+			body:           large,
+			encoding:       "",
+			wantEncoding:   "gzip",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			body, encoding = c.body, c.encoding
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			r.Header.Set("Accept-Encoding", c.acceptEncoding)
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, http.StatusCreated)
+			assert.Equal(t.Error, resp.Header.Get("Vary"), "Accept-Encoding")
+			assert.Equal(t.Error,
+				resp.Header.Get("Content-Encoding"), c.wantEncoding,
+			)
+			var rd io.Reader = resp.Body
+			if c.wantEncoding == "gzip" {
+				gz, err := gzip.NewReader(resp.Body)
+				assert.Nil(t.Fatal, err)
+				rd = gz
+			}
+			got, err := io.ReadAll(rd)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, string(got), c.body)
+		})
+	}
+}
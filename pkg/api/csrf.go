@@ -0,0 +1,107 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+// CSRFHeader is the header clients send the CSRF token back in with mutating
+// requests. It is also the response header the token is issued in, since the
+// client app is served from another origin and cannot read the cookie.
+const CSRFHeader = "X-CSRF-Token"
+
+// csrfResp defines the body of the responses written by CSRF.
+type csrfResp struct {
+	Error string `json:"error"`
+}
+
+// SetCSRF issues a new CSRF token with the given auth token, both as a cookie
+// and in the CSRFHeader response header.
+func SetCSRF(w http.ResponseWriter, auth http.Cookie) error {
+	ck, err := cookie.NewCSRF(auth)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &ck)
+	w.Header().Set(CSRFHeader, ck.Value)
+	return nil
+}
+
+// CSRF returns a Middleware that wraps handlers so that mutating requests
+// carrying an auth token are rejected with 403 Forbidden unless they come from
+// the trusted origin and send back the CSRF token issued with the auth token in
+// the CSRFHeader header. Requests made with auth tokens issued before CSRF
+// tokens were are let through on their Origin or Referer header alone. Requests
+// without an auth token, such as logins and webhooks, are let through as they
+// cannot act on behalf of a user.
+// The CSRF token is sent back in the CSRFHeader header of every safe request
+// made with it so that the client app can get it back after a reload without
+// being able to read the cookie. Only the trusted origin can read the header
+// under the CORS policy.
+func CSRF(trustedOrigin string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, errAuth := r.Cookie(cookie.AuthName)
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				if ck, err := r.Cookie(cookie.CSRFName); errAuth == nil &&
+					err == nil && ck.Value != "" {
+					w.Header().Set(CSRFHeader, ck.Value)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if errAuth != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// reject requests sent from other origins outright
+			origin := requestOrigin(r)
+			if origin != "" && origin != trustedOrigin {
+				writeCSRFErr(w)
+				return
+			}
+
+			// double-submit - the token in the header must match the cookie,
+			// which a cross-site request cannot read to forge the header with
+			if ckCSRF, err := r.Cookie(cookie.CSRFName); err == nil {
+				if ckCSRF.Value == "" || subtle.ConstantTimeCompare(
+					[]byte(ckCSRF.Value), []byte(r.Header.Get(CSRFHeader)),
+				) != 1 {
+					writeCSRFErr(w)
+					return
+				}
+			} else if origin == "" {
+				writeCSRFErr(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestOrigin returns the origin the given request was sent from according
+// to its Origin header, falling back to its Referer header. It returns an
+// empty string if neither is set.
+func requestOrigin(r *http.Request) string {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return origin
+	}
+	ref, err := url.Parse(r.Header.Get("Referer"))
+	if err != nil || ref.Host == "" {
+		return ""
+	}
+	return ref.Scheme + "://" + ref.Host
+}
+
+// writeCSRFErr writes the response for a request that failed the CSRF checks.
+func writeCSRFErr(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(csrfResp{Error: "Invalid CSRF token."})
+}
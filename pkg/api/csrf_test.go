@@ -0,0 +1,234 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+// TestSetCSRF tests SetCSRF to assert that it issues the same token in the
+// cookie and the header.
+func TestSetCSRF(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := SetCSRF(w, http.Cookie{
+		Name: cookie.AuthName, Value: "authtoken", Secure: true,
+	})
+	assert.Nil(t.Fatal, err)
+
+	resp := w.Result()
+	cks := resp.Cookies()
+	assert.Equal(t.Fatal, len(cks), 1)
+	assert.Equal(t.Error, cks[0].Name, cookie.CSRFName)
+	assert.Equal(t.Error, resp.Header.Get(CSRFHeader), cks[0].Value)
+}
+
+// TestCSRF tests the handler returned by CSRF to assert that it only calls the
+// wrapped handler for requests that pass the CSRF checks.
+func TestCSRF(t *testing.T) {
+	const trusted = "https://goteam.example"
+
+	for _, c := range []struct {
+		name       string
+		method     string
+		auth       bool
+		ckCSRF     string
+		hdCSRF     string
+		origin     string
+		referer    string
+		wantStatus int
+	}{
+		{
+			name:       "Safe",
+			method:     http.MethodGet,
+			auth:       true,
+			ckCSRF:     "",
+			hdCSRF:     "",
+			origin:     "https://evil.example",
+			referer:    "",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "NoAuth",
+			method:     http.MethodPost,
+			auth:       false,
+			ckCSRF:     "",
+			hdCSRF:     "",
+			origin:     "",
+			referer:    "",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "UntrustedOrigin",
+			method:     http.MethodPost,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "token",
+			origin:     "https://evil.example",
+			referer:    "",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "UntrustedReferer",
+			method:     http.MethodPatch,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "token",
+			origin:     "",
+			referer:    "https://evil.example/boards",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "NoHeader",
+			method:     http.MethodDelete,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "",
+			origin:     trusted,
+			referer:    "",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "WrongHeader",
+			method:     http.MethodPost,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "other",
+			origin:     trusted,
+			referer:    "",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "NoTokenNoOrigin",
+			method:     http.MethodPost,
+			auth:       true,
+			ckCSRF:     "",
+			hdCSRF:     "",
+			origin:     "",
+			referer:    "",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "OKToken",
+			method:     http.MethodPost,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "token",
+			origin:     "",
+			referer:    "",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OKTokenTrustedOrigin",
+			method:     http.MethodPut,
+			auth:       true,
+			ckCSRF:     "token",
+			hdCSRF:     "token",
+			origin:     trusted,
+			referer:    "",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OKFallbackReferer",
+			method:     http.MethodPost,
+			auth:       true,
+			ckCSRF:     "",
+			hdCSRF:     "",
+			origin:     "",
+			referer:    trusted + "/boards/1",
+			wantStatus: http.StatusOK,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := CSRF(trusted)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			))
+			r := httptest.NewRequest(c.method, "/", nil)
+			if c.auth {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: "nonempty",
+				})
+			}
+			if c.ckCSRF != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.CSRFName, Value: c.ckCSRF,
+				})
+			}
+			if c.hdCSRF != "" {
+				r.Header.Set(CSRFHeader, c.hdCSRF)
+			}
+			if c.origin != "" {
+				r.Header.Set("Origin", c.origin)
+			}
+			if c.referer != "" {
+				r.Header.Set("Referer", c.referer)
+			}
+			w := httptest.NewRecorder()
+
+			sut.ServeHTTP(w, r)
+
+			assert.Equal(t.Error, w.Result().StatusCode, c.wantStatus)
+			if c.wantStatus == http.StatusForbidden {
+				assert.OnRespErr("Invalid CSRF token.")(t, w.Result(), nil)
+			}
+		})
+	}
+}
+
+// TestCSRFFlow tests the handler returned by CSRF through a login, a reload
+// that loses the CSRF token held by the client, and the mutating requests made
+// after it to assert that the client can get the token back and use it.
+func TestCSRFFlow(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, _ *http.Request) {
+		ckAuth := http.Cookie{Name: cookie.AuthName, Value: "authtoken"}
+		http.SetCookie(w, &ckAuth)
+		if err := SetCSRF(w, ckAuth); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc("/team", func(w http.ResponseWriter, _ *http.Request) {})
+	srv := httptest.NewServer(CSRF("")(mux))
+	defer srv.Close()
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t.Fatal, err)
+	client := &http.Client{Jar: jar}
+
+	// do sends a request to the given path and returns the response
+	do := func(method, path, csrf string) *http.Response {
+		r, err := http.NewRequest(method, srv.URL+path, nil)
+		assert.Nil(t.Fatal, err)
+		if csrf != "" {
+			r.Header.Set(CSRFHeader, csrf)
+		}
+		resp, err := client.Do(r)
+		assert.Nil(t.Fatal, err)
+		defer resp.Body.Close()
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/login", "")
+	assert.Equal(t.Fatal, resp.StatusCode, http.StatusOK)
+	issued := resp.Header.Get(CSRFHeader)
+	assert.True(t.Fatal, issued != "")
+
+	// the token is forgotten by the client on reload and is required
+	resp = do(http.MethodPatch, "/team", "")
+	assert.Equal(t.Error, resp.StatusCode, http.StatusForbidden)
+
+	// the token is sent back on the next safe request
+	resp = do(http.MethodGet, "/team", "")
+	assert.Equal(t.Fatal, resp.StatusCode, http.StatusOK)
+	assert.Equal(t.Error, resp.Header.Get(CSRFHeader), issued)
+
+	resp = do(http.MethodPatch, "/team", resp.Header.Get(CSRFHeader))
+	assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+}
@@ -0,0 +1,117 @@
+//go:build utest
+
+package api
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestCursorSigner tests that CursorSigner only accepts cursors it signed for
+// the same scope.
+func TestCursorSigner(t *testing.T) {
+	sut := NewCursorSigner([]byte("key"))
+	signed := sut.Sign("board1", "cursor")
+
+	for _, c := range []struct {
+		name       string
+		signer     CursorSigner
+		scope      string
+		signed     string
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name:       "NoMAC",
+			signer:     sut,
+			scope:      "board1",
+			signed:     "Y3Vyc29y",
+			wantCursor: "",
+			wantErr:    ErrInvalidCursor,
+		},
+		{
+			name:       "NotBase64",
+			signer:     sut,
+			scope:      "board1",
+			signed:     "!!!.!!!",
+			wantCursor: "",
+			wantErr:    ErrInvalidCursor,
+		},
+		{
+			name:       "Tampered",
+			signer:     sut,
+			scope:      "board1",
+			signed:     "Y3Vyc29yMg" + signed[len("Y3Vyc29y"):],
+			wantCursor: "",
+			wantErr:    ErrInvalidCursor,
+		},
+		{
+			name:       "WrongScope",
+			signer:     sut,
+			scope:      "board2",
+			signed:     signed,
+			wantCursor: "",
+			wantErr:    ErrInvalidCursor,
+		},
+		{
+			name:       "WrongKey",
+			signer:     NewCursorSigner([]byte("otherkey")),
+			scope:      "board1",
+			signed:     signed,
+			wantCursor: "",
+			wantErr:    ErrInvalidCursor,
+		},
+		{
+			name:       "OK",
+			signer:     sut,
+			scope:      "board1",
+			signed:     signed,
+			wantCursor: "cursor",
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cursor, err := c.signer.Verify(c.scope, c.signed)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			assert.Equal(t.Error, cursor, c.wantCursor)
+		})
+	}
+}
+
+// TestCursorSignerFromEnv tests that CursorSignerFromEnv signs cursors with the
+// key at EnvCursorKey, falling back to the given key if it is not set.
+func TestCursorSignerFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		key          string
+		fallback     []byte
+		wantKey      string
+		wantErrIsNil bool
+	}{
+		{name: "Unset", wantErrIsNil: false},
+		{
+			name:         "Fallback",
+			fallback:     []byte("jwtkey"),
+			wantKey:      "jwtkey",
+			wantErrIsNil: true,
+		},
+		{
+			name:         "Key",
+			key:          "cursorkey",
+			fallback:     []byte("jwtkey"),
+			wantKey:      "cursorkey",
+			wantErrIsNil: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvCursorKey, c.key)
+
+			sut, err := CursorSignerFromEnv(c.fallback)
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, string(sut.key), c.wantKey)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETag wraps the given handler so that its successful GET responses carry an
+// ETag computed from their bodies, and requests with a matching If-None-Match
+// header get 304 Not Modified without a body. The ETags are weak since the
+// same body may be sent with different encodings (see Compress).
+func ETag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		if rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(rec.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		WriteConditional(w, r, etag, rec.body.Bytes())
+	})
+}
+
+// WriteConditional sets the given ETag on the response and writes the given
+// body with 200 OK, or 304 Not Modified without a body if the request's
+// If-None-Match header matches the ETag.
+func WriteConditional(
+	w http.ResponseWriter, r *http.Request, etag string, body []byte,
+) {
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// etagMatches returns whether the given If-None-Match header value matches the
+// given ETag using weak comparison.
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") ==
+			strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter is a http.ResponseWriter that holds the whole response in
+// memory instead of sending it.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the headers of the buffered response.
+func (bw *bufferedWriter) Header() http.Header { return bw.header }
+
+// WriteHeader records the status of the buffered response.
+func (bw *bufferedWriter) WriteHeader(status int) { bw.status = status }
+
+// Write appends to the body of the buffered response.
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
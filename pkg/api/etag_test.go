@@ -0,0 +1,126 @@
+//go:build utest
+
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestETag tests the handler returned by ETag to assert that it responds with
+// 304 Not Modified to requests whose If-None-Match matches the response.
+func TestETag(t *testing.T) {
+	var (
+		status int
+		body   string
+	)
+	sut := ETag(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Test", "test")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	// get the ETag of the body used in the cases below
+	status, body = http.StatusOK, `{"id":"board"}`
+	w := httptest.NewRecorder()
+	sut.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	etag := w.Result().Header.Get("ETag")
+	assert.True(t.Fatal, len(etag) > 4 && etag[:3] == `W/"`)
+
+	for _, c := range []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+		body        string
+		wantStatus  int
+		wantETag    string
+		wantBody    string
+	}{
+		{
+			name:        "NotGET",
+			method:      http.MethodPatch,
+			ifNoneMatch: etag,
+			status:      http.StatusOK,
+			body:        `{"id":"board"}`,
+			wantStatus:  http.StatusOK,
+			wantETag:    "",
+			wantBody:    `{"id":"board"}`,
+		},
+		{
+			name:        "NotOK",
+			method:      http.MethodGet,
+			ifNoneMatch: etag,
+			status:      http.StatusNotFound,
+			body:        `{"error":"not found"}`,
+			wantStatus:  http.StatusNotFound,
+			wantETag:    "",
+			wantBody:    `{"error":"not found"}`,
+		},
+		{
+			name:        "NoIfNoneMatch",
+			method:      http.MethodGet,
+			ifNoneMatch: "",
+			status:      http.StatusOK,
+			body:        `{"id":"board"}`,
+			wantStatus:  http.StatusOK,
+			wantETag:    etag,
+			wantBody:    `{"id":"board"}`,
+		},
+		{
+			name:        "Modified",
+			method:      http.MethodGet,
+			ifNoneMatch: etag,
+			status:      http.StatusOK,
+			body:        `{"id":"other"}`,
+			wantStatus:  http.StatusOK,
+			wantETag:    "",
+			wantBody:    `{"id":"other"}`,
+		},
+		{
+			name:        "NotModified",
+			method:      http.MethodGet,
+			ifNoneMatch: `"other", ` + etag,
+			status:      http.StatusOK,
+			body:        `{"id":"board"}`,
+			wantStatus:  http.StatusNotModified,
+			wantETag:    etag,
+			wantBody:    "",
+		},
+		{
+			name:        "NotModifiedStrong",
+			method:      http.MethodGet,
+			ifNoneMatch: etag[2:],
+			status:      http.StatusOK,
+			body:        `{"id":"board"}`,
+			wantStatus:  http.StatusNotModified,
+			wantETag:    etag,
+			wantBody:    "",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			status, body = c.status, c.body
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(c.method, "/tasks", nil)
+			r.Header.Set("If-None-Match", c.ifNoneMatch)
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			assert.Equal(t.Error, resp.Header.Get("X-Test"), "test")
+			if c.wantETag != "" {
+				assert.Equal(t.Error, resp.Header.Get("ETag"), c.wantETag)
+			} else if c.status != http.StatusOK || c.method != http.MethodGet {
+				assert.Equal(t.Error, resp.Header.Get("ETag"), "")
+			}
+			got, err := io.ReadAll(resp.Body)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, string(got), c.wantBody)
+		})
+	}
+}
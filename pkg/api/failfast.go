@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Breaker defines a type that reports whether a dependency is failing and how
+// long it is given to recover for, such as *breaker.Breaker.
+type Breaker interface {
+	RetryAfter() (time.Duration, bool)
+}
+
+// failFastResp defines the body of the responses written by FailFast.
+type failFastResp struct {
+	Error string `json:"error"`
+}
+
+// FailFast returns a Middleware that wraps handlers so that requests get 503
+// Service Unavailable with a Retry-After header while the breaker is open,
+// instead of piling up behind calls to a dependency that is down. The 503
+// responses written by the handlers it lets through, for the breaker opening
+// while they were handled, are given a Retry-After header too.
+func FailFast(b Breaker) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			retryAfter, isOpen := b.RetryAfter()
+			if !isOpen {
+				next.ServeHTTP(&failFastWriter{ResponseWriter: w, b: b}, r)
+				return
+			}
+
+			setRetryAfter(w, retryAfter)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(failFastResp{
+				Error: "Service is temporarily unavailable. Please try again " +
+					"later.",
+			})
+		})
+	}
+}
+
+// setRetryAfter sets the Retry-After header to the given duration, rounded up
+// to at least a second.
+func setRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	secs := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+}
+
+// failFastWriter is a http.ResponseWriter that sets the Retry-After header of
+// the 503 responses written through it that do not set one.
+type failFastWriter struct {
+	http.ResponseWriter
+	b Breaker
+}
+
+// WriteHeader sets the Retry-After header by the breaker if the status is 503
+// and writes the status to the wrapped writer.
+func (fw *failFastWriter) WriteHeader(status int) {
+	if status == http.StatusServiceUnavailable &&
+		fw.Header().Get("Retry-After") == "" {
+		retryAfter, _ := fw.b.RetryAfter()
+		setRetryAfter(fw.ResponseWriter, retryAfter)
+	}
+	fw.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the wrapped writer so that http.ResponseController can reach
+// its optional methods.
+func (fw *failFastWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
@@ -0,0 +1,124 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// fakeBreaker is a test fake for Breaker.
+type fakeBreaker struct {
+	retryAfter time.Duration
+	isOpen     bool
+}
+
+// RetryAfter returns the fakeBreaker's fields.
+func (f fakeBreaker) RetryAfter() (time.Duration, bool) {
+	return f.retryAfter, f.isOpen
+}
+
+// TestFailFast tests the handler returned by FailFast to assert that it only
+// calls the wrapped handler while the breaker is closed.
+func TestFailFast(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		breaker        fakeBreaker
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{
+			name:           "Closed",
+			breaker:        fakeBreaker{},
+			wantStatus:     http.StatusOK,
+			wantRetryAfter: "",
+		},
+		{
+			name:           "Open",
+			breaker:        fakeBreaker{2500 * time.Millisecond, true},
+			wantStatus:     http.StatusServiceUnavailable,
+			wantRetryAfter: "3",
+		},
+		{
+			name:           "Trialing",
+			breaker:        fakeBreaker{0, true},
+			wantStatus:     http.StatusServiceUnavailable,
+			wantRetryAfter: "1",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := FailFast(c.breaker)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			))
+			w := httptest.NewRecorder()
+
+			sut.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			assert.Equal(t.Error,
+				resp.Header.Get("Retry-After"), c.wantRetryAfter,
+			)
+		})
+	}
+}
+
+// TestFailFastUnavailable tests the handler returned by FailFast to assert that
+// it sets the Retry-After header of the 503 responses written by the handlers
+// it lets through, unless they set it themselves.
+func TestFailFastUnavailable(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		status         int
+		retryAfter     string
+		wantRetryAfter string
+	}{
+		{
+			name:           "Unavailable",
+			status:         http.StatusServiceUnavailable,
+			retryAfter:     "",
+			wantRetryAfter: "30",
+		},
+		{
+			name:           "UnavailableRetryAfterSet",
+			status:         http.StatusServiceUnavailable,
+			retryAfter:     "5",
+			wantRetryAfter: "5",
+		},
+		{
+			name:           "OtherStatus",
+			status:         http.StatusInternalServerError,
+			retryAfter:     "",
+			wantRetryAfter: "",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			b := &fakeBreaker{}
+			sut := FailFast(b)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					// the breaker opens while the request is handled
+					b.retryAfter, b.isOpen = 30*time.Second, true
+					if c.retryAfter != "" {
+						w.Header().Set("Retry-After", c.retryAfter)
+					}
+					w.WriteHeader(c.status)
+				},
+			))
+			w := httptest.NewRecorder()
+
+			sut.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.status)
+			assert.Equal(t.Error,
+				resp.Header.Get("Retry-After"), c.wantRetryAfter,
+			)
+		})
+	}
+}
@@ -2,22 +2,24 @@ package api
 
 import (
 	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
 )
 
 // FakeMethodHandler is a test fake for MethodHandler.
 type FakeMethodHandler struct {
 	InResponseWriter http.ResponseWriter
 	InR              *http.Request
-	InSub            string
+	InAuth           cookie.Auth
 }
 
 // Handle implements the MethodHandler interface on FakeMethodHandler. It
 // assigns the parameters passed into it to their corresponding In... fields on
 // the fake instance.
 func (f *FakeMethodHandler) Handle(
-	w http.ResponseWriter, r *http.Request, sub string,
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
 ) {
-	f.InResponseWriter, f.InR, f.InSub = w, r, sub
+	f.InResponseWriter, f.InR, f.InAuth = w, r, auth
 }
 
 // FakeStringValidator is a test fake for StringValidator.
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/featureflag"
+)
+
+// flaggedResp defines the body of the responses written by Flagged.
+type flaggedResp struct {
+	Error string `json:"error"`
+}
+
+// Flagged wraps the given method handler so that it is only called for teams
+// that the given flag is enabled for. Other requests get 404 Not Found as if
+// the route did not exist. It must be wrapped by Authed, which provides the
+// team ID of the request.
+func Flagged(
+	flags featureflag.Checker, flag featureflag.Flag, next MethodHandler,
+) MethodHandler {
+	return flagged{flags: flags, flag: flag, next: next}
+}
+
+// flagged is the MethodHandler returned by Flagged.
+type flagged struct {
+	flags featureflag.Checker
+	flag  featureflag.Flag
+	next  MethodHandler
+}
+
+// Handle calls the wrapped handler if the flag is enabled for the team of the
+// given auth claims.
+func (f flagged) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	if !f.flags.Enabled(f.flag, auth.TeamID) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(flaggedResp{
+			Error: "Feature is not enabled for your team.",
+		})
+		return
+	}
+	f.next.Handle(w, r, auth)
+}
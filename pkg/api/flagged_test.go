@@ -0,0 +1,50 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/featureflag"
+)
+
+// TestFlagged tests the MethodHandler returned by Flagged to assert that it
+// only calls the wrapped handler for the teams the flag is enabled for.
+func TestFlagged(t *testing.T) {
+	flag := featureflag.Flag{Name: "flag"}
+	auth := cookie.Auth{Username: "bob123", TeamID: "team1"}
+
+	t.Run("Disabled", func(t *testing.T) {
+		flags := &featureflag.FakeChecker{Res: false}
+		next := &fakeMethodHandler{}
+		sut := Flagged(flags, flag, next)
+		w := httptest.NewRecorder()
+
+		sut.Handle(w, httptest.NewRequest(http.MethodPost, "/", nil), auth)
+
+		assert.Equal(t.Error, flags.InFlag, flag)
+		assert.Equal(t.Error, flags.InTeamID, "team1")
+		assert.True(t.Error, !next.called)
+		assert.OnRespErr("Feature is not enabled for your team.")(
+			t, w.Result(), nil,
+		)
+		assert.Equal(t.Error, w.Code, http.StatusNotFound)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		flags := &featureflag.FakeChecker{Res: true}
+		next := &fakeMethodHandler{}
+		sut := Flagged(flags, flag, next)
+		w := httptest.NewRecorder()
+
+		sut.Handle(w, httptest.NewRequest(http.MethodPost, "/", nil), auth)
+
+		assert.True(t.Error, next.called)
+		assert.Equal(t.Error, next.auth, auth)
+		assert.Equal(t.Error, w.Code, http.StatusOK)
+	})
+}
@@ -3,16 +3,43 @@ package api
 import (
 	"net/http"
 	"os"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
 )
 
 // MethodHandler describes a type that can be used to serve a certain part of an
-// API route that corresponds to a specific HTTP method. It is intended for its
-// Handle method to be called after authentication, and with the authenticated
-// user's username as the third argument.
+// API route that corresponds to a specific HTTP method. Its Handle method is
+// called with the auth claims carried by the request context as the third
+// argument, which are those of the authenticated user for handlers wrapped with
+// Authed.
 type MethodHandler interface {
+	Handle(w http.ResponseWriter, r *http.Request, auth cookie.Auth)
+}
+
+// UsernameHandler describes the signature of MethodHandler from before auth
+// claims were passed to handlers, when they were only passed the username of
+// the authenticated user. It can be served with AdaptUsername until it is
+// migrated to MethodHandler.
+type UsernameHandler interface {
 	Handle(w http.ResponseWriter, r *http.Request, username string)
 }
 
+// AdaptUsername adapts the given UsernameHandler to a MethodHandler that passes
+// it the username of the auth claims it is called with.
+func AdaptUsername(h UsernameHandler) MethodHandler {
+	return usernameAdapter{next: h}
+}
+
+// usernameAdapter is the MethodHandler returned by AdaptUsername.
+type usernameAdapter struct{ next UsernameHandler }
+
+// Handle calls the adapted handler with the username of the given auth claims.
+func (a usernameAdapter) Handle(
+	w http.ResponseWriter, r *http.Request, auth cookie.Auth,
+) {
+	a.next.Handle(w, r, auth.Username)
+}
+
 // Handler is a http.Handler that can be used to handle requests.
 type Handler struct{ methodHandlers map[string]MethodHandler }
 
@@ -25,7 +52,14 @@ func NewHandler(methodHandlers map[string]MethodHandler) Handler {
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// add cors headers
 	w.Header().Set("Access-Control-Allow-Origin", os.Getenv("CLIENTORIGIN"))
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set(
+		"Access-Control-Allow-Headers",
+		"Content-Type, "+idempotencyKeyHeader+", "+CSRFHeader,
+	)
+	w.Header().Set(
+		"Access-Control-Expose-Headers",
+		NextCursorHeader+", "+CSRFHeader+", "+RequestIDHeader,
+	)
 	w.Header().Add("Access-Control-Allow-Credentials", "true")
 
 	// add allowed methods header
@@ -48,7 +82,7 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	methodHandler.Handle(w, r, "")
+	methodHandler.Handle(w, r, AuthFrom(r.Context()))
 }
 
 // allowedMethodsHeader takes in a slice of allowed HTTP methods and returns the
@@ -59,6 +59,8 @@ func TestHandler(t *testing.T) {
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(httpMethod, "/", nil)
 				r.AddCookie(&http.Cookie{Name: cookie.AuthName, Value: ""})
+				auth := cookie.Auth{Username: "bob123", TeamID: "team1"}
+				r = r.WithContext(WithAuth(r.Context(), auth))
 
 				sut.ServeHTTP(w, r)
 
@@ -67,7 +69,31 @@ func TestHandler(t *testing.T) {
 				fakeMethodHandler := methodHandler.(*FakeMethodHandler)
 				assert.Equal(t.Error, fakeMethodHandler.InResponseWriter, w)
 				assert.Equal(t.Error, fakeMethodHandler.InR, r)
+				assert.Equal(t.Error, fakeMethodHandler.InAuth, auth)
 			})
 		}
 	})
 }
+
+// fakeUsernameHandler is a test fake for UsernameHandler.
+type fakeUsernameHandler struct{ username string }
+
+// Handle records the username it was called with.
+func (f *fakeUsernameHandler) Handle(
+	_ http.ResponseWriter, _ *http.Request, username string,
+) {
+	f.username = username
+}
+
+// TestAdaptUsername tests the MethodHandler returned by AdaptUsername to assert
+// that it passes the username of the auth claims to the adapted handler.
+func TestAdaptUsername(t *testing.T) {
+	next := &fakeUsernameHandler{}
+	sut := AdaptUsername(next)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	sut.Handle(w, r, cookie.Auth{Username: "bob123", IsAdmin: true})
+
+	assert.Equal(t.Error, next.username, "bob123")
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+const (
+	// idempotencyKeyHeader is the header clients set to make a request safe to
+	// retry.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotencyTTL is how long the response to a request made with an
+	// idempotency key is kept for replaying to retries.
+	idempotencyTTL = 24 * time.Hour
+)
+
+// IdempotencyStore holds the accessors Idempotent uses to store idempotency
+// records.
+type IdempotencyStore struct {
+	Inserter  db.Inserter[idemtbl.Record]
+	Retriever db.Retriever[idemtbl.Record]
+	Updater   db.Updater[idemtbl.Record]
+	Deleter   db.Deleter
+}
+
+// idempotencyResp defines the body of the responses written by Idempotent.
+type idempotencyResp struct {
+	Error string `json:"error"`
+}
+
+// Idempotent returns a Middleware that wraps handlers so that unsafe requests
+// sent with an Idempotency-Key header are only handled once. Retries with the
+// same key and the same request get the stored response of the first request
+// replayed to them, retries while the first request is still being handled get
+// 409, and reusing a key for a different request gets 422. Keys are scoped to
+// the caller's auth token, from the auth cookie or the Authorization header as
+// Authed reads it, so that different callers cannot see each other's
+// responses. The keys of requests without an auth token, such as registering,
+// are scoped to the request itself instead, so that only a retry of the very
+// same request is replayed the response. Requests without the header are
+// handled as usual.
+func Idempotent(store IdempotencyStore, log log.Errorer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" || r.Method == http.MethodGet ||
+				r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// read the body to fingerprint the request, restoring it for next
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(ErrStatus(err))
+				log.Error(err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := hash(r.Method, r.URL.Path, string(body))
+			var id string
+			if caller, ok := authToken(r); ok {
+				id = hash(caller, key)
+			} else {
+				id = hash("", key, fingerprint)
+			}
+
+			// reserve the key, or answer from the record of an earlier request
+			err = store.Inserter.Insert(
+				r.Context(), idemtbl.NewRecord(id, fingerprint, idempotencyTTL),
+			)
+			if errors.Is(err, db.ErrDupKey) {
+				replay(w, r, store, id, fingerprint, log)
+				return
+			} else if err != nil {
+				w.WriteHeader(ErrStatus(err))
+				log.Error(err)
+				return
+			}
+
+			// release the key if the handler panics so that the request can
+			// be retried, letting the panic carry on to Recover
+			handled := false
+			defer func() {
+				if handled {
+					return
+				}
+				if err := store.Deleter.Delete(r.Context(), id); err != nil {
+					log.Error(err)
+				}
+			}()
+
+			// handle the request, recording the response
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			handled = true
+
+			// release the key on server errors so that the request can be
+			// retried, otherwise store the response to replay it to retries
+			if rec.status >= http.StatusInternalServerError {
+				err = store.Deleter.Delete(r.Context(), id)
+			} else {
+				record := idemtbl.NewRecord(id, fingerprint, idempotencyTTL)
+				record.Status = rec.status
+				// the cookies set by the response are not stored so that
+				// they are not handed out again to replays
+				record.Header = w.Header().Clone()
+				delete(record.Header, "Set-Cookie")
+				record.Body = rec.body.Bytes()
+				err = store.Updater.Update(r.Context(), record)
+			}
+			if err != nil {
+				log.Error(err)
+			}
+		})
+	}
+}
+
+// replay writes the stored response of the request with the given ID, or an
+// error if that request is different or still being handled.
+func replay(
+	w http.ResponseWriter,
+	r *http.Request,
+	store IdempotencyStore,
+	id, fingerprint string,
+	log log.Errorer,
+) {
+	record, err := store.Retriever.Retrieve(r.Context(), id)
+	if errors.Is(err, db.ErrNoItem) {
+		// the record expired or was released in the meantime
+		writeIdempotencyErr(w, http.StatusConflict,
+			"Request could not be completed. Please try again.", log,
+		)
+		return
+	} else if err != nil {
+		w.WriteHeader(ErrStatus(err))
+		log.Error(err)
+		return
+	}
+
+	switch {
+	case record.Fingerprint != fingerprint:
+		writeIdempotencyErr(w, http.StatusUnprocessableEntity,
+			"Idempotency key was already used for a different request.", log,
+		)
+	case record.Status == 0:
+		writeIdempotencyErr(w, http.StatusConflict,
+			"A request with this idempotency key is already in progress.", log,
+		)
+	default:
+		for k, v := range record.Header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(record.Status)
+		if _, err := w.Write(record.Body); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// writeIdempotencyErr writes the given status and error message.
+func writeIdempotencyErr(
+	w http.ResponseWriter, status int, msg string, log log.Errorer,
+) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(
+		idempotencyResp{Error: msg},
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+// hash returns the hex-encoded SHA-256 hash of the given parts.
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder is a http.ResponseWriter that records the status and the
+// body written to the http.ResponseWriter it wraps.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader records the status and writes it to the wrapped writer.
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = status, true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body and writes it to the wrapped writer.
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,325 @@
+//go:build utest
+
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestIdempotent tests the handler returned by Idempotent to assert that it
+// only handles requests with the same idempotency key once.
+func TestIdempotent(t *testing.T) {
+	var nextCalled bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+		body, _ := io.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "new"})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+	inserter := &db.FakeInserter[idemtbl.Record]{}
+	retriever := &db.FakeRetriever[idemtbl.Record]{}
+	updater := &db.FakeUpdater[idemtbl.Record]{}
+	log := &log.FakeErrorer{}
+	sut := Idempotent(IdempotencyStore{
+		Inserter:  inserter,
+		Retriever: retriever,
+		Updater:   updater,
+		Deleter:   &db.FakeDeleter{},
+	}, log)(next)
+
+	const body = `{"name":"board"}`
+	fingerprint := hash(http.MethodPost, "/boards", body)
+
+	for _, c := range []struct {
+		name         string
+		method       string
+		key          string
+		noAuth       bool
+		errInsert    error
+		record       idemtbl.Record
+		errRetrieve  error
+		wantNext     bool
+		wantStatus   int
+		wantReplayed bool
+		assertFunc   func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:         "NoKey",
+			method:       http.MethodPost,
+			key:          "",
+			errInsert:    errors.New("must not insert"),
+			record:       idemtbl.Record{},
+			errRetrieve:  nil,
+			wantNext:     true,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: false,
+			assertFunc:   func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:         "SafeMethod",
+			method:       http.MethodGet,
+			key:          "key",
+			errInsert:    errors.New("must not insert"),
+			record:       idemtbl.Record{},
+			errRetrieve:  nil,
+			wantNext:     true,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: false,
+			assertFunc:   func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:         "NoAuthToken",
+			method:       http.MethodPost,
+			key:          "key",
+			noAuth:       true,
+			errInsert:    nil,
+			record:       idemtbl.Record{},
+			errRetrieve:  nil,
+			wantNext:     true,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: false,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error,
+					updater.Updated.Status, http.StatusCreated,
+				)
+			},
+		},
+		{
+			name:      "NoAuthTokenReplayed",
+			method:    http.MethodPost,
+			key:       "key",
+			noAuth:    true,
+			errInsert: db.ErrDupKey,
+			record: idemtbl.Record{
+				Fingerprint: fingerprint,
+				Status:      http.StatusCreated,
+				Body:        []byte(`{"error":"replayed"}`),
+			},
+			errRetrieve:  nil,
+			wantNext:     false,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: true,
+			assertFunc:   assert.OnRespErr("replayed"),
+		},
+		{
+			name:         "ErrInsert",
+			method:       http.MethodPost,
+			key:          "key",
+			errInsert:    errors.New("insert failed"),
+			record:       idemtbl.Record{},
+			errRetrieve:  nil,
+			wantNext:     false,
+			wantStatus:   http.StatusInternalServerError,
+			wantReplayed: false,
+			assertFunc:   assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:         "ErrRetrieve",
+			method:       http.MethodPost,
+			key:          "key",
+			errInsert:    db.ErrDupKey,
+			record:       idemtbl.Record{},
+			errRetrieve:  errors.New("retrieve failed"),
+			wantNext:     false,
+			wantStatus:   http.StatusInternalServerError,
+			wantReplayed: false,
+			assertFunc:   assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:         "Released",
+			method:       http.MethodPost,
+			key:          "key",
+			errInsert:    db.ErrDupKey,
+			record:       idemtbl.Record{},
+			errRetrieve:  db.ErrNoItem,
+			wantNext:     false,
+			wantStatus:   http.StatusConflict,
+			wantReplayed: false,
+			assertFunc: assert.OnRespErr(
+				"Request could not be completed. Please try again.",
+			),
+		},
+		{
+			name:      "DifferentRequest",
+			method:    http.MethodPost,
+			key:       "key",
+			errInsert: db.ErrDupKey,
+			record: idemtbl.Record{
+				Fingerprint: "other", Status: http.StatusCreated,
+			},
+			errRetrieve:  nil,
+			wantNext:     false,
+			wantStatus:   http.StatusUnprocessableEntity,
+			wantReplayed: false,
+			assertFunc: assert.OnRespErr(
+				"Idempotency key was already used for a different request.",
+			),
+		},
+		{
+			name:         "InProgress",
+			method:       http.MethodPost,
+			key:          "key",
+			errInsert:    db.ErrDupKey,
+			record:       idemtbl.Record{Fingerprint: fingerprint, Status: 0},
+			errRetrieve:  nil,
+			wantNext:     false,
+			wantStatus:   http.StatusConflict,
+			wantReplayed: false,
+			assertFunc: assert.OnRespErr(
+				"A request with this idempotency key is already in progress.",
+			),
+		},
+		{
+			name:      "Replayed",
+			method:    http.MethodPost,
+			key:       "key",
+			errInsert: db.ErrDupKey,
+			record: idemtbl.Record{
+				Fingerprint: fingerprint,
+				Status:      http.StatusCreated,
+				Body:        []byte(`{"error":"replayed"}`),
+			},
+			errRetrieve:  nil,
+			wantNext:     false,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: true,
+			assertFunc:   assert.OnRespErr("replayed"),
+		},
+		{
+			name:         "FirstRequest",
+			method:       http.MethodPost,
+			key:          "key",
+			errInsert:    nil,
+			record:       idemtbl.Record{},
+			errRetrieve:  nil,
+			wantNext:     true,
+			wantStatus:   http.StatusCreated,
+			wantReplayed: false,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				got, _ := io.ReadAll(resp.Body)
+				assert.Equal(t.Error, string(got), body)
+
+				// the response is stored without the cookies it sets
+				assert.Equal(t.Error,
+					updater.Updated.Status, http.StatusCreated,
+				)
+				_, ok := updater.Updated.Header["Set-Cookie"]
+				assert.True(t.Error, !ok)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			nextCalled = false
+			updater.Updated = idemtbl.Record{}
+			inserter.Err = c.errInsert
+			retriever.Res = c.record
+			retriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(c.method, "/boards", strings.NewReader(body))
+			if c.key != "" {
+				r.Header.Set(idempotencyKeyHeader, c.key)
+			}
+			if !c.noAuth {
+				r.Header.Set("Authorization", "Bearer token1")
+			}
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, nextCalled, c.wantNext)
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			assert.Equal(t.Error,
+				resp.Header.Get("Idempotent-Replayed") == "true",
+				c.wantReplayed,
+			)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
+
+// TestIdempotentCaller tests the handler returned by Idempotent to assert that
+// it scopes idempotency keys to the auth token of the caller, wherever the
+// token is sent, and to the request itself when there is no token.
+func TestIdempotentCaller(t *testing.T) {
+	inserter := &db.FakeInserter[idemtbl.Record]{}
+	sut := Idempotent(IdempotencyStore{
+		Inserter:  inserter,
+		Retriever: &db.FakeRetriever[idemtbl.Record]{},
+		Updater:   &db.FakeUpdater[idemtbl.Record]{},
+		Deleter:   &db.FakeDeleter{},
+	}, &log.FakeErrorer{})(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {},
+	))
+
+	// insertedID returns the ID of the record inserted for a request sent
+	// with the key and the given auth setup
+	insertedID := func(body string, setAuth func(*http.Request)) string {
+		r := httptest.NewRequest(
+			http.MethodPost, "/boards", strings.NewReader(body),
+		)
+		r.Header.Set(idempotencyKeyHeader, "key")
+		setAuth(r)
+		sut.ServeHTTP(httptest.NewRecorder(), r)
+		return inserter.Inserted.ID
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	noAuth := func(*http.Request) {}
+
+	id1 := insertedID("a", bearer("token1"))
+	id2 := insertedID("a", bearer("token2"))
+	idCookie := insertedID("b", func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: cookie.AuthName, Value: "token1"})
+	})
+	idNoAuthA := insertedID("a", noAuth)
+	idNoAuthB := insertedID("b", noAuth)
+
+	assert.True(t.Error, id1 != "" && id1 != id2)
+	assert.Equal(t.Error, idCookie, id1)
+	assert.True(t.Error, idNoAuthA != "" && idNoAuthA != idNoAuthB)
+	assert.True(t.Error, idNoAuthA != id1 && idNoAuthA != id2)
+	assert.Equal(t.Error, insertedID("a", noAuth), idNoAuthA)
+}
+
+// TestIdempotentPanic tests the handler returned by Idempotent to assert that
+// it releases the key of a request whose handler panics, and that the panic
+// carries on to be recovered further out.
+func TestIdempotentPanic(t *testing.T) {
+	deleter := &db.FakeDeleter{}
+	inserter := &db.FakeInserter[idemtbl.Record]{}
+	sut := Idempotent(IdempotencyStore{
+		Inserter:  inserter,
+		Retriever: &db.FakeRetriever[idemtbl.Record]{},
+		Updater:   &db.FakeUpdater[idemtbl.Record]{},
+		Deleter:   deleter,
+	}, &log.FakeErrorer{})(http.HandlerFunc(
+		func(http.ResponseWriter, *http.Request) { panic("boom") },
+	))
+	r := httptest.NewRequest(http.MethodPost, "/boards", nil)
+	r.Header.Set(idempotencyKeyHeader, "key")
+	r.Header.Set("Authorization", "Bearer token1")
+
+	defer func() {
+		assert.Equal(t.Error, recover(), any("boom"))
+		assert.True(t.Error, deleter.Deleted != "")
+		assert.Equal(t.Error, deleter.Deleted, inserter.Inserted.ID)
+	}()
+	sut.ServeHTTP(httptest.NewRecorder(), r)
+	t.Error("panic was not carried on")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/i18n"
+)
+
+// Localize wraps the given handler so that the messages in its error
+// responses are translated into the language the client prefers according to
+// its Accept-Language header. Only the string values in JSON bodies of
+// responses with a status of 400 or above are translated, so that user
+// content is never touched. Bodies that the handler encoded (e.g. with
+// Compress) are sent as-is.
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		lang := i18n.FromRequest(r)
+		w.Header().Set("Content-Language", lang)
+		if lang == i18n.Default {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &localizeWriter{ResponseWriter: w, lang: lang}
+		defer lw.close()
+		next.ServeHTTP(lw, r)
+	})
+}
+
+// localizeWriter is a http.ResponseWriter that holds back error responses
+// until they are complete so that their messages can be translated.
+type localizeWriter struct {
+	http.ResponseWriter
+	lang   string
+	status int
+	buf    bytes.Buffer
+
+	// started is set once the status is known, after which the body is
+	// buffered if it is an error response or written through otherwise.
+	started   bool
+	buffering bool
+}
+
+// WriteHeader writes the status through unless it is an error status, in
+// which case it is held back until the body is translated.
+func (lw *localizeWriter) WriteHeader(status int) {
+	if lw.started {
+		return
+	}
+	lw.started = true
+	lw.status = status
+	lw.buffering = status >= http.StatusBadRequest &&
+		lw.Header().Get("Content-Encoding") == ""
+	if !lw.buffering {
+		lw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write buffers the body of error responses and writes the rest through.
+func (lw *localizeWriter) Write(b []byte) (int, error) {
+	if !lw.started {
+		lw.WriteHeader(http.StatusOK)
+	}
+	if lw.buffering {
+		return lw.buf.Write(b)
+	}
+	return lw.ResponseWriter.Write(b)
+}
+
+// close writes the held back error response with its messages translated.
+func (lw *localizeWriter) close() {
+	if !lw.buffering {
+		return
+	}
+	body := lw.buf.Bytes()
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if translated, err := json.Marshal(translate(lw.lang, v)); err == nil {
+			body = append(translated, '\n')
+		}
+	}
+	lw.Header().Del("Content-Length")
+	lw.ResponseWriter.WriteHeader(lw.status)
+	_, _ = lw.ResponseWriter.Write(body)
+}
+
+// translate returns the decoded JSON value with all of its strings translated
+// into the given language.
+func translate(lang string, v any) any {
+	switch v := v.(type) {
+	case string:
+		return i18n.T(lang, v)
+	case []any:
+		for i := range v {
+			v[i] = translate(lang, v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = translate(lang, v[k])
+		}
+	}
+	return v
+}
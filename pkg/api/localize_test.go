@@ -0,0 +1,122 @@
+//go:build utest
+
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestLocalize tests the handler returned by Localize to assert that it only
+// translates the messages in error responses for clients that prefer a
+// language other than English.
+func TestLocalize(t *testing.T) {
+	var (
+		status   int
+		body     string
+		encoding string
+	)
+	sut := Localize(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		},
+	))
+
+	for _, c := range []struct {
+		name           string
+		acceptLanguage string
+		status         int
+		body           string
+		encoding       string
+		wantLanguage   string
+		wantBody       string
+	}{
+		{
+			name:           "Default",
+			acceptLanguage: "",
+			status:         http.StatusNotFound,
+			body:           `{"error":"Board not found."}`,
+			encoding:       "",
+			wantLanguage:   "en",
+			wantBody:       `{"error":"Board not found."}`,
+		},
+		{
+			name:           "Success",
+			acceptLanguage: "tr",
+			status:         http.StatusOK,
+			body:           `{"name":"Board not found."}`,
+			encoding:       "",
+			wantLanguage:   "tr",
+			wantBody:       `{"name":"Board not found."}`,
+		},
+		{
+			name:           "Error",
+			acceptLanguage: "tr",
+			status:         http.StatusNotFound,
+			body:           `{"error":"Board not found."}`,
+			encoding:       "",
+			wantLanguage:   "tr",
+			wantBody:       `{"error":"Pano bulunamadı."}` + "\n",
+		},
+		{
+			name:           "ValidationErrors",
+			acceptLanguage: "tr-TR",
+			status:         http.StatusBadRequest,
+			body: `{"validationErrors":{"username":` +
+				`["Username cannot be empty.","Unknown."]}}`,
+			encoding:     "",
+			wantLanguage: "tr",
+			wantBody: `{"validationErrors":{"username":` +
+				`["Kullanıcı adı boş olamaz.","Unknown."]}}` + "\n",
+		},
+		{
+			name:           "NotJSON",
+			acceptLanguage: "tr",
+			status:         http.StatusBadRequest,
+			body:           "Board not found.",
+			encoding:       "",
+			wantLanguage:   "tr",
+			wantBody:       "Board not found.",
+		},
+		{
+			name:           "AlreadyEncoded",
+			acceptLanguage: "tr",
+			status:         http.StatusNotFound,
+			body:           `{"error":"Board not found."}`,
+			encoding:       "identity",
+			wantLanguage:   "tr",
+			wantBody:       `{"error":"Board not found."}`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			status, body, encoding = c.status, c.body, c.encoding
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", c.acceptLanguage)
+			}
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.status)
+			assert.Equal(
+				t.Error, resp.Header.Get("Content-Language"), c.wantLanguage,
+			)
+			assert.Equal(t.Error, resp.Header.Get("Vary"), "Accept-Language")
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t.Error, string(b), c.wantBody)
+		})
+	}
+}
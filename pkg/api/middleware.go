@@ -0,0 +1,23 @@
+package api
+
+import "net/http"
+
+// Middleware describes a function that wraps a http.Handler with a concern
+// shared between routes, such as compression or CSRF protection.
+type Middleware func(next http.Handler) http.Handler
+
+// Chain composes the given middlewares into a single Middleware. They are
+// applied in the order they are given in, so the first middleware is the
+// outermost one and sees each request first.
+func Chain(mws ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// Use returns the handler wrapped with the given middlewares, which are
+// composed as by Chain.
+func (h Handler) Use(mws ...Middleware) http.Handler { return Chain(mws...)(h) }
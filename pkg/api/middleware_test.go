@@ -0,0 +1,64 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestChain tests the Middleware returned by Chain to assert that it applies
+// the given middlewares in order, with the first one being the outermost.
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, name)
+					next.ServeHTTP(w, r)
+				},
+			)
+		}
+	}
+	sut := Chain(record("first"), record("second"), record("third"))(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			calls = append(calls, "handler")
+		}),
+	)
+
+	sut.ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil),
+	)
+
+	assert.AllEqual(t.Error, calls, []string{
+		"first", "second", "third", "handler",
+	})
+}
+
+// TestHandlerUse tests the Use method of Handler to assert that it serves the
+// handler through the given middlewares.
+func TestHandlerUse(t *testing.T) {
+	methodHandler := &FakeMethodHandler{}
+	setHeader := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Middleware", "called")
+			next.ServeHTTP(w, r)
+		})
+	}
+	sut := NewHandler(map[string]MethodHandler{
+		http.MethodGet: methodHandler,
+	}).Use(setHeader)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	sut.ServeHTTP(w, r)
+
+	resp := w.Result()
+	assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+	assert.Equal(t.Error, resp.Header.Get("X-Middleware"), "called")
+	assert.Equal(t.Error, methodHandler.InR, r)
+}
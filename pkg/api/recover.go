@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/report"
+)
+
+// recoverReportTimeout is how long reporting a panic may take.
+const recoverReportTimeout = 10 * time.Second
+
+// recoverResp defines the body of the responses written by Recover.
+type recoverResp struct {
+	Error string `json:"error"`
+}
+
+// Recover returns a Middleware that recovers from panics in the handlers it
+// wraps, logging and reporting them with the context of the request before
+// responding 500 Internal Server Error. It should be wrapped by AccessLog so
+// that the reports carry the request ID and the user.
+//
+// The panic is reported by Recover itself, so the logger should not report the
+// errors it logs as well or the panic is reported twice.
+func Recover(reporter report.Reporter, log log.Errorer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				// let the server abort the response as intended
+				if err, ok := v.(error); ok &&
+					errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				msg, stack := fmt.Sprint("panic: ", v), string(debug.Stack())
+				log.Error(msg, "\n", stack)
+
+				// build the event before reporting it in the background as the
+				// response writer must not be used after the handler returns
+				e := report.Event{
+					Level:   report.LevelError,
+					Message: msg,
+					Stack:   stack,
+					Request: &report.Request{
+						Method: r.Method,
+						URL:    r.URL.Path,
+						ID:     w.Header().Get(RequestIDHeader),
+						User:   accessUser(r.Context()),
+					},
+				}
+				ctx, cancel := context.WithTimeout(
+					context.WithoutCancel(r.Context()), recoverReportTimeout,
+				)
+				go func() {
+					defer cancel()
+					if err := reporter.Report(ctx, e); err != nil {
+						log.Error("failed to report panic:", err)
+					}
+				}()
+
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(recoverResp{
+					Error: "Internal server error.",
+				})
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
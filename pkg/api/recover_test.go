@@ -0,0 +1,116 @@
+//go:build utest
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/report"
+)
+
+// panicker is a MethodHandler that panics with its value.
+type panicker struct{ v any }
+
+// Handle panics with the panicker's value.
+func (p panicker) Handle(http.ResponseWriter, *http.Request, cookie.Auth) {
+	panic(p.v)
+}
+
+// TestRecover tests the handler returned by Recover to assert that it responds
+// 500 to requests whose handlers panic, and that it logs and reports the panic
+// with the context of the request.
+func TestRecover(t *testing.T) {
+	t.Run("NoPanic", func(t *testing.T) {
+		reporter := &report.FakeReporter{Events: make(chan report.Event, 1)}
+		log := &log.FakeErrorer{}
+		sut := Recover(reporter, log)(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+		))
+		w := httptest.NewRecorder()
+
+		sut.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t.Error, w.Code, http.StatusTeapot)
+		assert.Equal(t.Error, len(log.Args), 0)
+		assert.Equal(t.Error, len(reporter.Events), 0)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		reporter := &report.FakeReporter{Events: make(chan report.Event, 1)}
+		errLog := &log.FakeErrorer{}
+		infoLog := &log.FakeInfoer{}
+		sut := Chain(
+			AccessLog(&clock.Fake{}, infoLog), Recover(reporter, errLog),
+		)(NewHandler(map[string]MethodHandler{
+			http.MethodGet: Authed(
+				&cookie.FakeDecoder[cookie.Auth]{
+					Res: cookie.Auth{Username: "bob123"},
+				},
+				&log.FakeErrorer{},
+				panicker{v: "boom"},
+			),
+		}))
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/team?token=secret", nil)
+		r.Header.Set(RequestIDHeader, "req-1")
+		r.AddCookie(&http.Cookie{Name: cookie.AuthName, Value: "token"})
+
+		sut.ServeHTTP(w, r)
+
+		assert.Equal(t.Error, w.Code, http.StatusInternalServerError)
+		var resp recoverResp
+		assert.Nil(t.Fatal, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t.Error, resp.Error, "Internal server error.")
+		assert.Equal(t.Fatal, len(errLog.Args), 3)
+		assert.Equal(t.Error, errLog.Args[0], "panic: boom")
+		assert.True(t.Error, strings.Contains(
+			infoLog.Args[0].(string), "status=500",
+		))
+
+		select {
+		case e := <-reporter.Events:
+			assert.Equal(t.Error, e.Level, report.LevelError)
+			assert.Equal(t.Error, e.Message, "panic: boom")
+			assert.True(t.Error, strings.Contains(e.Stack, "panicker.Handle"))
+			assert.Equal(t.Error, *e.Request, report.Request{
+				Method: http.MethodGet,
+				URL:    "/team",
+				ID:     "req-1",
+				User:   "bob123",
+			})
+		case <-time.After(time.Second):
+			t.Error("panic was not reported")
+		}
+	})
+
+	t.Run("ErrAbortHandler", func(t *testing.T) {
+		reporter := &report.FakeReporter{Events: make(chan report.Event, 1)}
+		log := &log.FakeErrorer{}
+		sut := Recover(reporter, log)(http.HandlerFunc(
+			func(http.ResponseWriter, *http.Request) {
+				panic(http.ErrAbortHandler)
+			},
+		))
+
+		defer func() {
+			assert.Equal(t.Error, recover(), any(http.ErrAbortHandler))
+			assert.Equal(t.Error, len(log.Args), 0)
+			assert.Equal(t.Error, len(reporter.Events), 0)
+		}()
+		sut.ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, "/", nil),
+		)
+	})
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net"
+	"net/http"
+)
+
+// RemoteIP returns the IP address of the client that made the request, or its
+// remote address as is if it has no port.
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,29 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestRemoteIP(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{name: "IPv4", remoteAddr: "203.0.113.7:54321", want: "203.0.113.7"},
+		{name: "IPv6", remoteAddr: "[2001:db8::1]:54321", want: "2001:db8::1"},
+		{name: "NoPort", remoteAddr: "203.0.113.7", want: "203.0.113.7"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("", "/", nil)
+			r.RemoteAddr = c.remoteAddr
+
+			assert.Equal(t.Error, RemoteIP(r), c.want)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Router is a http.Handler that routes requests to handlers based on path
+// patterns. A pattern is made up of static segments and parameter segments
+// wrapped in curly braces (e.g. /boards/{boardID}/tasks/{taskID}). The values
+// of parameter segments can be read by handlers by calling PathParam.
+type Router struct{ routes []route }
+
+// route is a pattern registered on a Router with its corresponding handler.
+type route struct {
+	segments []string
+	handler  http.Handler
+}
+
+// NewRouter creates and returns a new Router.
+func NewRouter() *Router { return &Router{} }
+
+// Handle registers the handler for the given pattern. Patterns are matched in
+// the order they were registered in.
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	rt.routes = append(rt.routes, route{
+		segments: splitPath(pattern), handler: handler,
+	})
+}
+
+// ServeHTTP dispatches the request to the handler of the first pattern that
+// matches the request's path, or responds with 404 if none matches.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+	for _, rte := range rt.routes {
+		params, ok := rte.match(segments)
+		if !ok {
+			continue
+		}
+		if len(params) > 0 {
+			r = WithPathParams(r, params)
+		}
+		rte.handler.ServeHTTP(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// match checks whether the given path segments match the route's pattern and
+// returns the values of the parameter segments if so.
+func (rte route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rte.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, s := range rte.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[s[1:len(s)-1]] = segments[i]
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// splitPath splits the given path into its segments, ignoring the leading and
+// trailing slashes.
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// pathParamsKey is the context key used for storing path parameters.
+type pathParamsKey struct{}
+
+// WithPathParams returns a shallow copy of the given request with the given
+// path parameters stored in its context.
+func WithPathParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), pathParamsKey{}, params))
+}
+
+// PathParam returns the value of the path parameter with the given name, or an
+// empty string if the request's path did not contain it.
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
+
+// Deprecated wraps a handler that is kept only as an alias for a newer route,
+// marking its responses with the Deprecation header so that clients can tell
+// that they should migrate.
+func Deprecated(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		handler.ServeHTTP(w, r)
+	})
+}
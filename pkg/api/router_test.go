@@ -0,0 +1,118 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestRouter tests the ServeHTTP method of Router to assert that it routes
+// requests to the correct handlers with the correct path parameters.
+func TestRouter(t *testing.T) {
+	var (
+		gotRoute  string
+		gotParams map[string]string
+	)
+	newHandler := func(name string, params ...string) http.Handler {
+		return http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			gotRoute = name
+			gotParams = map[string]string{}
+			for _, p := range params {
+				gotParams[p] = PathParam(r, p)
+			}
+		})
+	}
+	sut := NewRouter()
+	sut.Handle("/boards", newHandler("boards"))
+	sut.Handle("/boards/{boardID}", newHandler("board", "boardID"))
+	sut.Handle(
+		"/boards/{boardID}/tasks/{taskID}",
+		newHandler("task", "boardID", "taskID"),
+	)
+	sut.Handle("/board", Deprecated(newHandler("legacy")))
+
+	for _, c := range []struct {
+		name           string
+		path           string
+		wantStatus     int
+		wantRoute      string
+		wantParams     map[string]string
+		wantDeprecated bool
+	}{
+		{
+			name:       "NotFound",
+			path:       "/foo",
+			wantStatus: http.StatusNotFound,
+			wantRoute:  "",
+			wantParams: nil,
+		},
+		{
+			name:       "TooManySegments",
+			path:       "/boards/board1/tasks",
+			wantStatus: http.StatusNotFound,
+			wantRoute:  "",
+			wantParams: nil,
+		},
+		{
+			name:       "Static",
+			path:       "/boards",
+			wantStatus: http.StatusOK,
+			wantRoute:  "boards",
+			wantParams: map[string]string{},
+		},
+		{
+			name:       "TrailingSlash",
+			path:       "/boards/",
+			wantStatus: http.StatusOK,
+			wantRoute:  "boards",
+			wantParams: map[string]string{},
+		},
+		{
+			name:       "OneParam",
+			path:       "/boards/board1",
+			wantStatus: http.StatusOK,
+			wantRoute:  "board",
+			wantParams: map[string]string{"boardID": "board1"},
+		},
+		{
+			name:       "TwoParams",
+			path:       "/boards/board1/tasks/task1",
+			wantStatus: http.StatusOK,
+			wantRoute:  "task",
+			wantParams: map[string]string{
+				"boardID": "board1", "taskID": "task1",
+			},
+		},
+		{
+			name:           "Deprecated",
+			path:           "/board?id=board1",
+			wantStatus:     http.StatusOK,
+			wantRoute:      "legacy",
+			wantParams:     map[string]string{},
+			wantDeprecated: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			gotRoute, gotParams = "", nil
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			assert.Equal(t.Error, gotRoute, c.wantRoute)
+			assert.Equal(t.Error, len(gotParams), len(c.wantParams))
+			for k, v := range c.wantParams {
+				assert.Equal(t.Error, gotParams[k], v)
+			}
+			assert.Equal(t.Error,
+				resp.Header.Get("Deprecation") == "true", c.wantDeprecated,
+			)
+		})
+	}
+}
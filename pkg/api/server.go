@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Names of the environment variables to load the server settings from. Each
+// is optional and falls back to the value in DefaultServerConfig when unset.
+const (
+	EnvReadTimeout       = "HTTP_READ_TIMEOUT"
+	EnvReadHeaderTimeout = "HTTP_READ_HEADER_TIMEOUT"
+	EnvWriteTimeout      = "HTTP_WRITE_TIMEOUT"
+	EnvIdleTimeout       = "HTTP_IDLE_TIMEOUT"
+	EnvMaxHeaderBytes    = "HTTP_MAX_HEADER_BYTES"
+)
+
+// ServerConfig defines the limits the HTTP servers apply to their connections
+// so that slow or stuck clients cannot hold them open indefinitely.
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+}
+
+// DefaultServerConfig returns the ServerConfig used unless configured
+// otherwise.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+	}
+}
+
+// ServerConfigFromEnv returns the default server config with each setting
+// replaced by the value of its environment variable if set.
+func ServerConfigFromEnv() (ServerConfig, error) {
+	c := DefaultServerConfig()
+	for name, timeout := range map[string]*time.Duration{
+		EnvReadTimeout:       &c.ReadTimeout,
+		EnvReadHeaderTimeout: &c.ReadHeaderTimeout,
+		EnvWriteTimeout:      &c.WriteTimeout,
+		EnvIdleTimeout:       &c.IdleTimeout,
+	} {
+		s := os.Getenv(name)
+		if s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return ServerConfig{}, fmt.Errorf(
+				"%s must be a positive duration, got %q", name, s,
+			)
+		}
+		*timeout = d
+	}
+	if s := os.Getenv(EnvMaxHeaderBytes); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return ServerConfig{}, fmt.Errorf(
+				"%s must be a positive integer, got %q", EnvMaxHeaderBytes, s,
+			)
+		}
+		c.MaxHeaderBytes = n
+	}
+	return c, nil
+}
+
+// NewServer creates and returns a new http.Server that serves the handler on
+// the given address with the limits in the config applied.
+func (c ServerConfig) NewServer(addr string, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadTimeout:       c.ReadTimeout,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		WriteTimeout:      c.WriteTimeout,
+		IdleTimeout:       c.IdleTimeout,
+		MaxHeaderBytes:    c.MaxHeaderBytes,
+	}
+}
@@ -0,0 +1,92 @@
+//go:build utest
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestServerConfigFromEnv tests the ServerConfigFromEnv function to assert
+// that it overrides the default settings with the ones set in the environment.
+func TestServerConfigFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		readTimeout    string
+		writeTimeout   string
+		maxHeaderBytes string
+		want           ServerConfig
+		wantErrIsNil   bool
+	}{
+		{
+			name:           "Unset",
+			readTimeout:    "",
+			writeTimeout:   "",
+			maxHeaderBytes: "",
+			want:           DefaultServerConfig(),
+			wantErrIsNil:   true,
+		},
+		{
+			name:           "Set",
+			readTimeout:    "3s",
+			writeTimeout:   "1m",
+			maxHeaderBytes: "4096",
+			want: ServerConfig{
+				ReadTimeout:       3 * time.Second,
+				ReadHeaderTimeout: DefaultServerConfig().ReadHeaderTimeout,
+				WriteTimeout:      time.Minute,
+				IdleTimeout:       DefaultServerConfig().IdleTimeout,
+				MaxHeaderBytes:    4096,
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:           "InvalidTimeout",
+			readTimeout:    "0s",
+			writeTimeout:   "",
+			maxHeaderBytes: "",
+			want:           ServerConfig{},
+			wantErrIsNil:   false,
+		},
+		{
+			name:           "InvalidMaxHeaderBytes",
+			readTimeout:    "",
+			writeTimeout:   "",
+			maxHeaderBytes: "lots",
+			want:           ServerConfig{},
+			wantErrIsNil:   false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvReadTimeout, c.readTimeout)
+			t.Setenv(EnvReadHeaderTimeout, "")
+			t.Setenv(EnvWriteTimeout, c.writeTimeout)
+			t.Setenv(EnvIdleTimeout, "")
+			t.Setenv(EnvMaxHeaderBytes, c.maxHeaderBytes)
+
+			cfg, err := ServerConfigFromEnv()
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, cfg, c.want)
+		})
+	}
+}
+
+// TestNewServer tests the NewServer method of ServerConfig to assert that it
+// applies the config to the server it creates.
+func TestNewServer(t *testing.T) {
+	cfg := DefaultServerConfig()
+	h := http.NewServeMux()
+
+	srv := cfg.NewServer(":8080", h)
+
+	assert.Equal(t.Error, srv.Addr, ":8080")
+	assert.Equal(t.Error, srv.ReadTimeout, cfg.ReadTimeout)
+	assert.Equal(t.Error, srv.ReadHeaderTimeout, cfg.ReadHeaderTimeout)
+	assert.Equal(t.Error, srv.WriteTimeout, cfg.WriteTimeout)
+	assert.Equal(t.Error, srv.IdleTimeout, cfg.IdleTimeout)
+	assert.Equal(t.Error, srv.MaxHeaderBytes, cfg.MaxHeaderBytes)
+}
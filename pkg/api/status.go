@@ -0,0 +1,25 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// ErrStatus returns the status code to respond with for an unexpected error.
+// Errors caused by a call running past its deadline map to 504 Gateway Timeout
+// so that clients can tell a slow dependency apart from a bug, errors caused
+// by an open breaker map to 503 Service Unavailable, which FailFast sets the
+// Retry-After header of, and all other errors map to 500 Internal Server
+// Error.
+func ErrStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	if errors.Is(err, db.ErrUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
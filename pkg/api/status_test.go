@@ -0,0 +1,49 @@
+//go:build utest
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// TestErrStatus tests the ErrStatus function to assert that it maps deadline
+// errors to 504, unavailable errors to 503, and all other errors to 500.
+func TestErrStatus(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "DeadlineExceeded",
+			err:  context.DeadlineExceeded,
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "WrappedDeadlineExceeded",
+			err:  fmt.Errorf("get item: %w", context.DeadlineExceeded),
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "Unavailable",
+			err:  fmt.Errorf("get item: %w", db.ErrUnavailable),
+			want: http.StatusServiceUnavailable,
+		},
+		{
+			name: "Other",
+			err:  errors.New("failed to get item"),
+			want: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, ErrStatus(c.err), c.want)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -43,6 +44,17 @@ func AllEqual[T comparable](logErr func(...any), got, want []T) {
 	}
 }
 
+// DeepEqual asserts that two given values are deeply equal. Unlike Equal, it
+// can be used for values that contain slices, maps, or pointers, which are
+// compared by what they hold, and it reports the paths to where the values
+// differ. Values whose type has an Equal method, such as time.Time, are
+// compared by it.
+func DeepEqual(logErr func(...any), got, want any) {
+	if d := diff(got, want); d != "" {
+		logErr(fmt.Errorf("%w\ndiff:%s", newErr(got, want), d))
+	}
+}
+
 // ErrIs asserts that the given two errors are the same.
 func ErrIs(logErr func(...any), got, want error) {
 	if !errors.Is(got, want) {
@@ -50,6 +62,14 @@ func ErrIs(logErr func(...any), got, want error) {
 	}
 }
 
+// ErrAs asserts that the given error is, or wraps, an error that can be
+// assigned to the value that target points to, and assigns it to it.
+func ErrAs(logErr func(...any), got error, target any) {
+	if !errors.As(got, target) {
+		logErr(newErr(got, reflect.TypeOf(target).Elem()))
+	}
+}
+
 // Nil asserts that a given value is nil.
 func Nil(logErr func(...any), got any) {
 	if got != nil {
@@ -72,7 +92,7 @@ func True(logErr func(...any), got bool) {
 //   - string to match the signature of OnLoggedErr so that it can
 //     be used interchangeably with it in table-driven tests.
 //
-// This two-step function cal is for being able to initialise it before use in
+// This two-step function call is for being able to initialise it before use in
 // table-driven tests.
 func OnRespErr(
 	wantErrMsg string,
@@ -86,15 +106,64 @@ func OnRespErr(
 	}
 }
 
+// OnRespBody can be used in HTTP tests to assert that the response body is the
+// JSON encoding of a given value. It decodes the body into a value of the same
+// type and asserts that it is deeply equal to the given one. Like OnRespErr, it
+// returns a function so that it can be initialised in table-driven tests.
+func OnRespBody[T any](want T) func(*testing.T, *http.Response, []any) {
+	return func(t *testing.T, resp *http.Response, _ []any) {
+		var got T
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		DeepEqual(t.Error, got, want)
+	}
+}
+
+// Resp defines the parts of an HTTP response asserted on by OnResp.
+type Resp struct {
+	// Status is the wanted status code.
+	Status int
+
+	// Header holds the wanted values of the headers to check. The headers not
+	// in it are not checked.
+	Header map[string]string
+
+	// Body is the value the response body is wanted to be the JSON encoding
+	// of. The body is not checked if it is nil.
+	Body any
+}
+
+// OnResp asserts that the given HTTP response has the status code, headers,
+// and body in want. The body is decoded into a new value of the type of
+// want.Body before it is compared.
+func OnResp(t *testing.T, resp *http.Response, want Resp) {
+	t.Helper()
+	Equal(t.Error, resp.StatusCode, want.Status)
+	for name, wantVal := range want.Header {
+		if got := resp.Header.Get(name); got != wantVal {
+			t.Error(fmt.Errorf("header %s:%w", name, newErr(got, wantVal)))
+		}
+	}
+	if want.Body == nil {
+		return
+	}
+	got := reflect.New(reflect.TypeOf(want.Body))
+	if err := json.NewDecoder(resp.Body).Decode(got.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	DeepEqual(t.Error, got.Elem().Interface(), want.Body)
+}
+
 // OnLoggedErr can be used in HTTP tests to assert that a given error message
 // was logged. It takes in the expected error message and returns a function
 // that takes in:
 //   - *testing.T to be able to either call Fatal or Error,
-//   - *http.Response to match the signature of OnResErr so that it can be used
+//   - *http.Response to match the signature of OnRespErr so that it can be used
 //     interchangeably with it in table-driven tests,
 //   - string to check what error was logged.
 //
-// This two-step function cal is for being able to initialise it before use in
+// This two-step function call is for being able to initialise it before use in
 // table-driven tests.
 func OnLoggedErr(wantErrMsg string) func(*testing.T, *http.Response, []any) {
 	return func(t *testing.T, _ *http.Response, logArgs []any) {
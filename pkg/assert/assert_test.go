@@ -0,0 +1,152 @@
+//go:build utest
+
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDeepEqual tests DeepEqual to assert that it only logs an error when the
+// given values differ, and that the error points out where they differ.
+func TestDeepEqual(t *testing.T) {
+	type inner struct {
+		Name string
+		At   time.Time
+	}
+	type outer struct {
+		ID     int
+		Tags   []string
+		Attrs  map[string]int
+		Inner  *inner
+		hidden bool
+	}
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	base := func() outer {
+		return outer{
+			ID:    1,
+			Tags:  []string{"a", "b"},
+			Attrs: map[string]int{"x": 1},
+			Inner: &inner{Name: "in", At: at},
+		}
+	}
+
+	for _, c := range []struct {
+		name      string
+		got       any
+		want      any
+		wantDiffs []string
+	}{
+		{name: "Equal", got: base(), want: base(), wantDiffs: nil},
+		{
+			name: "SameInstantDifferentZone",
+			got: func() outer {
+				o := base()
+				o.Inner.At = at.In(time.FixedZone("X", 3600))
+				return o
+			}(),
+			want:      base(),
+			wantDiffs: nil,
+		},
+		{
+			name: "FieldsDiffer",
+			got: func() outer {
+				o := base()
+				o.ID = 2
+				o.Inner.Name = "out"
+				o.hidden = true
+				return o
+			}(),
+			want: base(),
+			wantDiffs: []string{
+				`.ID: got 2, want 1`,
+				`.Inner.Name: got "out", want "in"`,
+				`.hidden: got true, want false`,
+			},
+		},
+		{
+			name: "SliceLengthsDiffer",
+			got: func() outer {
+				o := base()
+				o.Tags = o.Tags[:1]
+				return o
+			}(),
+			want:      base(),
+			wantDiffs: []string{`.Tags[1]: got <missing>, want "b"`},
+		},
+		{
+			name: "MapKeysDiffer",
+			got: func() outer {
+				o := base()
+				o.Attrs = map[string]int{"y": 1}
+				return o
+			}(),
+			want: base(),
+			wantDiffs: []string{
+				`.Attrs[x]: got <missing>, want 1`,
+				`.Attrs[y]: got 1, want <missing>`,
+			},
+		},
+		{
+			name:      "TypesDiffer",
+			got:       1,
+			want:      "1",
+			wantDiffs: []string{`.: got 1, want "1"`},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var logged []any
+			DeepEqual(func(args ...any) { logged = args }, c.got, c.want)
+
+			if c.wantDiffs == nil {
+				Equal(t.Error, len(logged), 0)
+				return
+			}
+			msg := fmt.Sprint(logged...)
+			for _, d := range c.wantDiffs {
+				True(t.Error, strings.Contains(msg, d))
+			}
+			Equal(t.Error, strings.Count(msg, "\n  "), len(c.wantDiffs))
+		})
+	}
+}
+
+// TestErrAs tests ErrAs to assert that it assigns the matching error to the
+// target, and logs an error if there is none.
+func TestErrAs(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist}
+
+	var target *fs.PathError
+	var logged []any
+	ErrAs(func(args ...any) { logged = args },
+		fmt.Errorf("wrapped: %w", pathErr), &target,
+	)
+	Equal(t.Error, len(logged), 0)
+	Equal(t.Error, target, pathErr)
+
+	ErrAs(func(args ...any) { logged = args }, errors.New("other"), &target)
+	Equal(t.Error, len(logged), 1)
+}
+
+// TestOnResp tests OnResp to assert that it passes on a matching response.
+func TestOnResp(t *testing.T) {
+	type body struct {
+		IDs []string `json:"ids"`
+	}
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.WriteString(`{"ids":["a","b"]}`)
+
+	OnResp(t, w.Result(), Resp{
+		Status: http.StatusCreated,
+		Header: map[string]string{"Content-Type": "application/json"},
+		Body:   body{IDs: []string{"a", "b"}},
+	})
+}
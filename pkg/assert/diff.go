@@ -0,0 +1,136 @@
+//go:build utest || itest
+
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// diff returns the differences between the two given values, one per line, each
+// prefixed with the path to where the values differ (e.g. .Boards[1].Name). It
+// returns an empty string if the values are deeply equal.
+func diff(got, want any) string {
+	var b strings.Builder
+	writeDiff(&b, "", reflect.ValueOf(got), reflect.ValueOf(want))
+	return b.String()
+}
+
+// writeDiff walks the two given values in step and writes a line to b for each
+// point where they differ.
+func writeDiff(b *strings.Builder, path string, got, want reflect.Value) {
+	if !got.IsValid() || !want.IsValid() {
+		if got.IsValid() != want.IsValid() {
+			writeDiffLine(b, path, got, want)
+		}
+		return
+	}
+	if got.Type() != want.Type() {
+		writeDiffLine(b, path, got, want)
+		return
+	}
+
+	// compare the values by their own Equal method if they have one (e.g.
+	// time.Time) rather than by their internals
+	if eq, ok := equalMethod(got, want); ok {
+		if !eq {
+			writeDiffLine(b, path, got, want)
+		}
+		return
+	}
+
+	switch got.Kind() {
+	case reflect.Struct:
+		for i := 0; i < got.NumField(); i++ {
+			writeDiff(b,
+				path+"."+got.Type().Field(i).Name, got.Field(i), want.Field(i),
+			)
+		}
+	case reflect.Slice, reflect.Array:
+		if got.Kind() == reflect.Slice && got.IsNil() != want.IsNil() {
+			writeDiffLine(b, path, got, want)
+			return
+		}
+		for i := 0; i < max(got.Len(), want.Len()); i++ {
+			var gotElem, wantElem reflect.Value
+			if i < got.Len() {
+				gotElem = got.Index(i)
+			}
+			if i < want.Len() {
+				wantElem = want.Index(i)
+			}
+			writeDiff(b, fmt.Sprintf("%s[%d]", path, i), gotElem, wantElem)
+		}
+	case reflect.Map:
+		if got.IsNil() != want.IsNil() {
+			writeDiffLine(b, path, got, want)
+			return
+		}
+		keys := want.MapKeys()
+		for _, k := range got.MapKeys() {
+			if !want.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			writeDiff(b,
+				fmt.Sprintf("%s[%v]", path, k), got.MapIndex(k), want.MapIndex(k),
+			)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				writeDiffLine(b, path, got, want)
+			}
+			return
+		}
+		writeDiff(b, path, got.Elem(), want.Elem())
+	case reflect.Func:
+		if got.IsNil() != want.IsNil() {
+			writeDiffLine(b, path, got, want)
+		}
+	default:
+		if !got.Equal(want) {
+			writeDiffLine(b, path, got, want)
+		}
+	}
+}
+
+// equalMethod compares the two given values by calling an Equal method defined
+// on their type, if there is one that takes the same type and returns a bool.
+// ok is false if there is no such method or it cannot be called.
+func equalMethod(got, want reflect.Value) (eq, ok bool) {
+	if !got.CanInterface() || !want.CanInterface() {
+		return false, false
+	}
+	m := got.MethodByName("Equal")
+	if !m.IsValid() {
+		return false, false
+	}
+	mt := m.Type()
+	if mt.NumIn() != 1 || mt.In(0) != want.Type() ||
+		mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+	return m.Call([]reflect.Value{want})[0].Bool(), true
+}
+
+// writeDiffLine writes a line to b for a single difference at the given path.
+func writeDiffLine(b *strings.Builder, path string, got, want reflect.Value) {
+	if path == "" {
+		path = "."
+	}
+	fmt.Fprintf(b,
+		"\n  %s: got %s, want %s", path, fmtValue(got), fmtValue(want),
+	)
+}
+
+// fmtValue formats the given value for a diff line, marking the values missing
+// from a slice or map.
+func fmtValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<missing>"
+	}
+	return fmt.Sprintf("%#v", v)
+}
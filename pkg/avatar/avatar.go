@@ -0,0 +1,119 @@
+// Package avatar contains the code to process the profile pictures users
+// upload, generate identicons for the users who have not uploaded one, and
+// build the URLs they are served from.
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"  // register the GIF decoder
+	_ "image/jpeg" // register the JPEG decoder
+	"image/png"
+	"net/url"
+)
+
+// Size is the width and height in pixels of the avatars.
+const Size = 128
+
+// MaxDimension is the maximum width and height in pixels of an uploaded
+// image, which bounds the memory used to decode it.
+const MaxDimension = 4096
+
+var (
+	// ErrFormat means that the uploaded image was not a PNG, JPEG, or GIF.
+	ErrFormat = errors.New("image format not supported")
+
+	// ErrDimensions means that the uploaded image was wider or taller than
+	// MaxDimension.
+	ErrDimensions = errors.New("image too large")
+)
+
+// URL returns the path of the given user's avatar on the user service. The
+// hash of the user's uploaded avatar is added as a query parameter so that
+// clients fetch it again once it changes, and is empty for the users who are
+// shown an identicon.
+func URL(username, hash string) string {
+	u := "/users/" + url.PathEscape(username) + "/avatar"
+	if hash != "" {
+		u += "?v=" + url.QueryEscape(hash)
+	}
+	return u
+}
+
+// Hash returns the hash of the given encoded avatar used to version its URL
+// and as its ETag.
+func Hash(img []byte) string {
+	sum := sha256.Sum256(img)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Process decodes the given PNG, JPEG, or GIF image, crops it to a square
+// around its centre, scales it to Size, and returns it PNG-encoded. It returns
+// ErrFormat or ErrDimensions for the images that cannot be used as avatars.
+func Process(data []byte) ([]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrFormat
+	}
+	switch format {
+	case "png", "jpeg", "gif":
+	default:
+		return nil, ErrFormat
+	}
+	if cfg.Width < 1 || cfg.Height < 1 ||
+		cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, ErrDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrFormat
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, scale(src, Size)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scale crops the given image to a square around its centre and scales it to
+// the given size, averaging the pixels that fall into each pixel of the result
+// when scaling down.
+func scale(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0 := y0 + y*side/size
+		sy1 := max(y0+(y+1)*side/size, sy0+1)
+		for x := 0; x < size; x++ {
+			sx0 := x0 + x*side/size
+			sx1 := max(x0+(x+1)*side/size, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg),
+						b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,91 @@
+//go:build utest
+
+package avatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestURL(t *testing.T) {
+	assert.Equal(t.Error, URL("bob123", ""), "/users/bob123/avatar")
+	assert.Equal(t.Error, URL("bob123", "abc"), "/users/bob123/avatar?v=abc")
+}
+
+func TestProcess(t *testing.T) {
+	// encode returns an image of the given size in the given format, with its
+	// left half red and its right half blue
+	encode := func(format string, w, h int) []byte {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				c := color.NRGBA{R: 255, A: 255}
+				if x >= w/2 {
+					c = color.NRGBA{B: 255, A: 255}
+				}
+				img.Set(x, y, c)
+			}
+		}
+		var buf bytes.Buffer
+		switch format {
+		case "png":
+			_ = png.Encode(&buf, img)
+		case "jpeg":
+			_ = jpeg.Encode(&buf, img, nil)
+		case "gif":
+			_ = gif.Encode(&buf, img, nil)
+		}
+		return buf.Bytes()
+	}
+
+	for _, c := range []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "Empty", data: nil, wantErr: ErrFormat},
+		{name: "NotImage", data: []byte("<svg/>"), wantErr: ErrFormat},
+		{
+			name:    "Truncated",
+			data:    encode("png", 64, 64)[:64],
+			wantErr: ErrFormat,
+		},
+		{
+			name:    "TooWide",
+			data:    encode("png", MaxDimension+1, 1),
+			wantErr: ErrDimensions,
+		},
+		{name: "PNG", data: encode("png", 300, 200), wantErr: nil},
+		{name: "JPEG", data: encode("jpeg", 200, 300), wantErr: nil},
+		{name: "GIF", data: encode("gif", 40, 40), wantErr: nil},
+		{name: "Tiny", data: encode("png", 4, 3), wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := Process(c.data)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			if c.wantErr != nil {
+				return
+			}
+			img, format, err := image.Decode(bytes.NewReader(res))
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, format, "png")
+			assert.Equal(t.Error, img.Bounds().Dx(), Size)
+			assert.Equal(t.Error, img.Bounds().Dy(), Size)
+
+			// the image is cropped around its centre, so it is still red on
+			// the left and blue on the right
+			r, _, b, _ := img.At(0, Size/2).RGBA()
+			assert.True(t.Error, r > b)
+			r, _, b, _ = img.At(Size-1, Size/2).RGBA()
+			assert.True(t.Error, b > r)
+		})
+	}
+}
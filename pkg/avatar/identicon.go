@@ -0,0 +1,64 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// identiconGrid is the number of cells on each side of an identicon, and
+// identiconCell is their size in pixels, which leaves an even margin around
+// the cells.
+const (
+	identiconGrid = 5
+	identiconCell = 20
+)
+
+// identiconBackground is the colour of the cells of an identicon that are not
+// filled.
+var identiconBackground = color.NRGBA{R: 240, G: 240, B: 240, A: 255}
+
+// Identicon returns the PNG-encoded identicon of the given user, which is a
+// horizontally symmetric pattern of cells in a colour both derived from the
+// hash of their username. The same username always gives the same identicon.
+func Identicon(username string) []byte {
+	sum := sha256.Sum256([]byte(username))
+
+	// keep the colour away from the extremes so that it stands out from the
+	// background without being too dark
+	fg := color.NRGBA{
+		R: 48 + sum[29]%160,
+		G: 48 + sum[30]%160,
+		B: 48 + sum[31]%160,
+		A: 255,
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, Size, Size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(identiconBackground),
+		image.Point{}, draw.Src,
+	)
+	margin := (Size - identiconCell*identiconGrid) / 2
+	for row := 0; row < identiconGrid; row++ {
+		for col := 0; col < (identiconGrid+1)/2; col++ {
+			if sum[row*identiconGrid+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, identiconGrid - 1 - col} {
+				x := margin + c*identiconCell
+				y := margin + row*identiconCell
+				draw.Draw(img,
+					image.Rect(x, y, x+identiconCell, y+identiconCell),
+					image.NewUniform(fg), image.Point{}, draw.Src,
+				)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	// encoding an in-memory image into a buffer cannot fail
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
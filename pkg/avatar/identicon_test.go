@@ -0,0 +1,33 @@
+//go:build utest
+
+package avatar
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestIdenticon(t *testing.T) {
+	bob := Identicon("bob123")
+
+	// the same username always gives the same identicon
+	assert.True(t.Error, bytes.Equal(bob, Identicon("bob123")))
+	assert.True(t.Error, !bytes.Equal(bob, Identicon("jo1234")))
+
+	img, err := png.Decode(bytes.NewReader(bob))
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, img.Bounds().Dx(), Size)
+	assert.Equal(t.Error, img.Bounds().Dy(), Size)
+
+	// the pattern is horizontally symmetric
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size/2; x++ {
+			if img.At(x, y) != img.At(Size-1-x, y) {
+				t.Fatalf("pixel (%d, %d) is not mirrored", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package awsjson contains a client for the AWS APIs that use the JSON
+// protocol, such as those of SQS and DynamoDB Streams, for the services whose
+// SDK clients are not among the dependencies.
+package awsjson
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// Client can be used to call the actions of an AWS API that uses the JSON
+// protocol with requests signed by Signature Version 4.
+type Client struct {
+	endpoint string
+	target   string
+	service  string
+	region   string
+	creds    aws.CredentialsProvider
+	signer   *v4.Signer
+	clock    clock.Clock
+	client   *http.Client
+}
+
+// NewClient creates and returns a new Client that calls the API at the given
+// endpoint, prefixing the actions with the given target, e.g. "AmazonSQS", and
+// signing the requests for the given service and region with the given
+// credentials.
+func NewClient(
+	endpoint, target, service, region string,
+	creds aws.CredentialsProvider,
+	clock clock.Clock,
+	client *http.Client,
+) Client {
+	return Client{
+		endpoint: endpoint,
+		target:   target,
+		service:  service,
+		region:   region,
+		creds:    creds,
+		signer:   v4.NewSigner(),
+		clock:    clock,
+		client:   client,
+	}
+}
+
+// Error is returned by Call when the API responds with a non-2xx status.
+type Error struct {
+	Service string
+	Action  string
+	Status  int
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error returns the action, status, type, and message of the error.
+func (e *Error) Error() string {
+	return fmt.Sprintf(
+		"%s %s responded %d: %s %s",
+		e.Service, e.Action, e.Status, e.Type, e.Message,
+	)
+}
+
+// Call calls the given action with the given input, decoding the response
+// into out unless it is nil.
+func (c Client) Call(ctx context.Context, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.endpoint, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", c.target+"."+action)
+
+	creds, err := c.creds.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(
+		ctx, creds, req, hex.EncodeToString(hash[:]), c.service, c.region,
+		c.clock.Now(),
+	); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e := &Error{Service: c.service, Action: action, Status: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(e)
+		return e
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,86 @@
+//go:build utest
+
+package awsjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestClient tests the Call method of Client to assert that it sends signed
+// requests for the given action and decodes the responses and errors.
+func TestClient(t *testing.T) {
+	var (
+		gotTarget string
+		gotAuth   string
+		gotIn     map[string]string
+		status    int
+		resp      string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			gotTarget = r.Header.Get("X-Amz-Target")
+			gotAuth = r.Header.Get("Authorization")
+			_ = json.NewDecoder(r.Body).Decode(&gotIn)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(resp))
+		},
+	))
+	defer srv.Close()
+
+	sut := NewClient(
+		srv.URL,
+		"AmazonSQS",
+		"sqs",
+		"eu-west-2",
+		credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		&clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		srv.Client(),
+	)
+
+	t.Run("OK", func(t *testing.T) {
+		status, resp = http.StatusOK, `{"MessageId":"m1"}`
+		var out struct{ MessageId string }
+
+		err := sut.Call(
+			context.Background(), "SendMessage", map[string]string{"A": "b"},
+			&out,
+		)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotTarget, "AmazonSQS.SendMessage")
+		assert.True(t.Error, strings.HasPrefix(
+			gotAuth,
+			"AWS4-HMAC-SHA256 Credential=key/20240315/eu-west-2/sqs/",
+		))
+		assert.Equal(t.Error, gotIn["A"], "b")
+		assert.Equal(t.Error, out.MessageId, "m1")
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		status, resp = http.StatusBadRequest, `{"__type":"Throttling",`+
+			`"message":"Rate exceeded"}`
+
+		err := sut.Call(context.Background(), "SendMessage", nil, nil)
+
+		var apiErr *Error
+		assert.True(t.Fatal, errors.As(err, &apiErr))
+		assert.Equal(t.Error, apiErr.Status, http.StatusBadRequest)
+		assert.Equal(t.Error, apiErr.Type, "Throttling")
+		assert.Equal(
+			t.Error, err.Error(),
+			"sqs SendMessage responded 400: Throttling Rate exceeded",
+		)
+	})
+}
@@ -0,0 +1,30 @@
+// Package clock contains code for telling the current time in a way that can
+// be frozen or advanced in tests.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock defines a type that can be used to tell the current time.
+type Clock interface{ Now() time.Time }
+
+// System is a Clock that tells the current time by the system clock.
+type System struct{}
+
+// Now returns the current local time.
+func (System) Now() time.Time { return time.Now() }
+
+// Sleep waits for the given duration or until the given context is done,
+// whichever comes first, returning the context's error in the latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+//go:build utest
+
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSleep tests Sleep to assert that it returns once the duration passes or
+// the context is done, whichever comes first.
+func TestSleep(t *testing.T) {
+	t.Run("Elapsed", func(t *testing.T) {
+		err := Sleep(context.Background(), time.Millisecond)
+
+		assert.Nil(t.Error, err)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Sleep(ctx, time.Hour)
+
+		assert.ErrIs(t.Error, err, context.Canceled)
+	})
+}
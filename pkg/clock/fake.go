@@ -0,0 +1,15 @@
+//go:build utest
+
+package clock
+
+import "time"
+
+// Fake is a test fake for Clock that tells the time it was set to until it is
+// advanced.
+type Fake struct{ Time time.Time }
+
+// Now returns the Fake's Time field value.
+func (f *Fake) Now() time.Time { return f.Time }
+
+// Advance moves the Fake's time forward by the given duration.
+func (f *Fake) Advance(d time.Duration) { f.Time = f.Time.Add(d) }
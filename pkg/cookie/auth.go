@@ -1,10 +1,12 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
-	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
 )
 
 // AuthName is the name of the auth token.
@@ -15,6 +17,15 @@ type Auth struct {
 	Username string
 	IsAdmin  bool
 	TeamID   string
+
+	// SessionID identifies the session the token was issued for so that it
+	// can be revoked. It is empty for tokens issued before sessions were
+	// tracked.
+	SessionID string
+
+	// IsGuest is set for guests, who can only see the boards they were
+	// invited to and cannot see the team's members.
+	IsGuest bool
 }
 
 // NewAuth creates and returns a new Auth.
@@ -24,56 +35,65 @@ func NewAuth(username string, isAdmin bool, teamID string) Auth {
 
 // EncoderAuth defines a type that can be used to encode an auth token.
 type EncoderAuth struct {
-	key []byte
-	dur time.Duration
+	keys  Keys
+	cfg   Config
+	clock clock.Clock
 }
 
-// NewAuthEncoder creates and returns a new AuthEncoder.
-func NewAuthEncoder(jwtKey []byte, duration time.Duration) EncoderAuth {
-	return EncoderAuth{key: jwtKey, dur: duration}
+// NewAuthEncoder creates and returns a new AuthEncoder that encodes auth
+// tokens valid for the config's MaxAge into cookies with its attributes. The
+// tokens' expiry is counted from the time told by the given clock.
+func NewAuthEncoder(keys Keys, cfg Config, clock clock.Clock) EncoderAuth {
+	return EncoderAuth{keys: keys, cfg: cfg, clock: clock}
 }
 
 // Encode encodes an Auth into a JWT string.
-func (e EncoderAuth) Encode(auth Auth) (http.Cookie, error) {
-	exp := time.Now().Add(e.dur)
+func (e EncoderAuth) Encode(
+	_ context.Context, auth Auth,
+) (http.Cookie, error) {
+	now := e.clock.Now()
+	exp := now.Add(e.cfg.MaxAge)
 
-	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"username": auth.Username,
 		"isAdmin":  auth.IsAdmin,
 		"teamID":   auth.TeamID,
 		"exp":      exp.Unix(),
-	}).SignedString(e.key)
+	}
+	if auth.SessionID != "" {
+		claims["jti"] = auth.SessionID
+	}
+	if auth.IsGuest {
+		claims["isGuest"] = true
+	}
+	tk, err := e.keys.sign(claims)
 	if err != nil {
 		return http.Cookie{}, err
 	}
 
-	return http.Cookie{
-		Name:     AuthName,
-		Value:    tk,
-		Expires:  exp.UTC(),
-		SameSite: http.SameSiteNoneMode,
-		Secure:   true,
-	}, nil
+	return e.cfg.newCookie(AuthName, tk, now, exp), nil
 }
 
 // AuthDecoder defines a type that can be used to decode an auth token.
-type AuthDecoder struct{ key []byte }
+type AuthDecoder struct {
+	keys  Keys
+	clock clock.Clock
+}
 
-// NewAuthDecoder creates and returns a new AuthDecoder.
-func NewAuthDecoder(key []byte) AuthDecoder { return AuthDecoder{key: key} }
+// NewAuthDecoder creates and returns a new AuthDecoder that rejects the tokens
+// that expired by the time told by the given clock.
+func NewAuthDecoder(keys Keys, clock clock.Clock) AuthDecoder {
+	return AuthDecoder{keys: keys, clock: clock}
+}
 
 // Decode validates and decodes a raw JWT string into an Auth.
-func (d AuthDecoder) Decode(ck http.Cookie) (Auth, error) {
+func (d AuthDecoder) Decode(_ context.Context, ck http.Cookie) (Auth, error) {
 	if ck.Value == "" {
 		return Auth{}, ErrInvalid
 	}
 
-	claims := jwt.MapClaims{}
-	if _, err := jwt.ParseWithClaims(
-		ck.Value, &claims, func(token *jwt.Token) (any, error) {
-			return d.key, nil
-		},
-	); err != nil {
+	claims, err := parseClaims(ck.Value, d.keys, d.clock)
+	if err != nil {
 		return Auth{}, err
 	}
 
@@ -92,5 +112,8 @@ func (d AuthDecoder) Decode(ck http.Cookie) (Auth, error) {
 		return Auth{}, ErrInvalid
 	}
 
-	return NewAuth(username, isAdmin, teamID), nil
+	auth := NewAuth(username, isAdmin, teamID)
+	auth.SessionID, _ = claims["jti"].(string)
+	auth.IsGuest, _ = claims["isGuest"].(bool)
+	return auth, nil
 }
@@ -3,6 +3,7 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
@@ -10,29 +11,32 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 )
 
 func TestAuth(t *testing.T) {
 	key := []byte("signkey")
+	keys := NewKeys(key)
 	username := "bob123"
 	isAdmin := true
 	teamID := "teamid"
+	now := time.Now().Truncate(time.Second)
+	clk := &clock.Fake{Time: now}
 
 	t.Run("Encode", func(t *testing.T) {
-		dur := 1 * time.Hour
-		sut := NewAuthEncoder(key, dur)
+		sut := NewAuthEncoder(keys, DefaultConfig(), clk)
 
-		ck, err := sut.Encode(NewAuth(username, isAdmin, teamID))
+		ck, err := sut.Encode(
+			context.Background(), NewAuth(username, isAdmin, teamID),
+		)
 		assert.Nil(t.Fatal, err)
 
 		assert.Nil(t.Fatal, ck.Valid())
 		assert.Equal(t.Error, ck.Name, AuthName)
 		assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
 		assert.True(t.Error, ck.Secure)
-		assert.True(t.Error,
-			ck.Expires.UTC().After(time.Now().Add(59*time.Minute).UTC()))
-		assert.True(t.Error,
-			ck.Expires.UTC().Before(time.Now().Add(61*time.Minute).UTC()))
+		assert.Equal(t.Error, ck.MaxAge, 3600)
+		assert.Equal(t.Error, ck.Expires, now.Add(time.Hour).UTC())
 
 		claims := jwt.MapClaims{}
 		_, err = jwt.ParseWithClaims(
@@ -45,18 +49,58 @@ func TestAuth(t *testing.T) {
 		assert.Equal(t.Error, claims["username"].(string), username)
 		assert.Equal(t.Error, claims["isAdmin"].(bool), isAdmin)
 		assert.Equal(t.Error, claims["teamID"].(string), teamID)
-		assert.True(t.Error,
-			int64(claims["exp"].(float64)) >
-				time.Now().Add(59*time.Minute).Unix(),
+		assert.Equal(t.Error,
+			int64(claims["exp"].(float64)), now.Add(time.Hour).Unix(),
+		)
+	})
+
+	t.Run("ExpiresByClock", func(t *testing.T) {
+		clk := &clock.Fake{Time: now}
+		ck, err := NewAuthEncoder(keys, DefaultConfig(), clk).Encode(
+			context.Background(), NewAuth(username, isAdmin, teamID),
+		)
+		assert.Nil(t.Fatal, err)
+		sut := NewAuthDecoder(keys, clk)
+
+		clk.Advance(59 * time.Minute)
+		_, err = sut.Decode(context.Background(), ck)
+		assert.Nil(t.Error, err)
+
+		clk.Advance(2 * time.Minute)
+		_, err = sut.Decode(context.Background(), ck)
+		assert.ErrIs(t.Error, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("SessionID", func(t *testing.T) {
+		auth := NewAuth(username, isAdmin, teamID)
+		auth.SessionID = "sessionid"
+
+		ck, err := NewAuthEncoder(keys, DefaultConfig(), clk).Encode(
+			context.Background(), auth,
 		)
-		assert.True(t.Error,
-			int64(claims["exp"].(float64)) <
-				time.Now().Add(61*time.Minute).Unix(),
+		assert.Nil(t.Fatal, err)
+
+		got, err := NewAuthDecoder(keys, clk).Decode(context.Background(), ck)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, got, auth)
+	})
+
+	t.Run("IsGuest", func(t *testing.T) {
+		auth := NewAuth(username, false, teamID)
+		auth.IsGuest = true
+
+		ck, err := NewAuthEncoder(keys, DefaultConfig(), clk).Encode(
+			context.Background(), auth,
 		)
+		assert.Nil(t.Fatal, err)
+
+		got, err := NewAuthDecoder(keys, clk).Decode(context.Background(), ck)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, got, auth)
 	})
 
 	t.Run("Decode", func(t *testing.T) {
-		sut := NewAuthDecoder(key)
+		sut := NewAuthDecoder(keys, clk)
 
 		for _, c := range []struct {
 			name         string
@@ -108,7 +152,9 @@ func TestAuth(t *testing.T) {
 			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
-				auth, err := sut.Decode(http.Cookie{Value: c.token})
+				auth, err := sut.Decode(
+					context.Background(), http.Cookie{Value: c.token},
+				)
 
 				assert.ErrIs(t.Error, err, c.wantErr)
 				assert.Equal(t.Error, auth.Username, c.wantUsername)
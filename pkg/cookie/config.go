@@ -0,0 +1,161 @@
+package cookie
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the environment variables to load the cookie settings from. Each
+// is optional and falls back to the value in DefaultConfig when unset.
+const (
+	EnvDomain   = "COOKIE_DOMAIN"
+	EnvPath     = "COOKIE_PATH"
+	EnvSameSite = "COOKIE_SAME_SITE"
+	EnvSecure   = "COOKIE_SECURE"
+	EnvMaxAge   = "COOKIE_MAX_AGE"
+
+	EnvAuthMode        = "AUTH_MODE"
+	EnvSessionLifetime = "SESSION_MAX_LIFETIME"
+)
+
+// AuthMode defines what the auth cookies carry.
+type AuthMode string
+
+const (
+	// AuthModeJWT is the mode in which auth cookies carry signed JWTs that
+	// hold the user's auth.
+	AuthModeJWT AuthMode = "jwt"
+
+	// AuthModeSession is the mode in which auth cookies carry only the opaque
+	// IDs of server-side sessions, which hold the user's auth instead. The
+	// cookies are smaller, their claims cannot be read, and a session is
+	// revoked as soon as it is deleted, at the cost of a read of the session
+	// table on every request.
+	AuthModeSession AuthMode = "session"
+)
+
+// Config defines the attributes of the cookies set on clients, so that they
+// can be sent over plain HTTP in local development or shared across the
+// subdomains of a deployment.
+type Config struct {
+	Domain   string
+	Path     string
+	SameSite http.SameSite
+	Secure   bool
+
+	// MaxAge is how long auth tokens, and the CSRF tokens issued with them,
+	// are valid for. Invite tokens are valid for as long as their encoders
+	// are created with. Under AuthModeSession, it is how long sessions are
+	// valid for since they were last used instead.
+	MaxAge time.Duration
+
+	// AuthMode is what the auth cookies carry.
+	AuthMode AuthMode
+
+	// SessionLifetime is how long sessions can be extended by use for under
+	// AuthModeSession, after which their users must log in again.
+	SessionLifetime time.Duration
+}
+
+// DefaultConfig returns the Config used unless configured otherwise, which
+// suits a client app served from another site over HTTPS.
+func DefaultConfig() Config {
+	return Config{
+		SameSite: http.SameSiteNoneMode,
+		Secure:   true,
+		MaxAge:   1 * time.Hour,
+
+		AuthMode:        AuthModeJWT,
+		SessionLifetime: 7 * 24 * time.Hour,
+	}
+}
+
+// ConfigFromEnv returns the default cookie config with each setting replaced
+// by the value of its environment variable if set.
+func ConfigFromEnv() (Config, error) {
+	c := DefaultConfig()
+	c.Domain = os.Getenv(EnvDomain)
+	c.Path = os.Getenv(EnvPath)
+	if s := os.Getenv(EnvSameSite); s != "" {
+		switch strings.ToLower(s) {
+		case "none":
+			c.SameSite = http.SameSiteNoneMode
+		case "lax":
+			c.SameSite = http.SameSiteLaxMode
+		case "strict":
+			c.SameSite = http.SameSiteStrictMode
+		default:
+			return Config{}, fmt.Errorf(
+				"%s must be none, lax, or strict, got %q", EnvSameSite, s,
+			)
+		}
+	}
+	if s := os.Getenv(EnvSecure); s != "" {
+		secure, err := strconv.ParseBool(s)
+		if err != nil {
+			return Config{}, fmt.Errorf(
+				"%s must be true or false, got %q", EnvSecure, s,
+			)
+		}
+		c.Secure = secure
+	}
+	if s := os.Getenv(EnvMaxAge); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < time.Second {
+			return Config{}, fmt.Errorf(
+				"%s must be a duration of at least 1s, got %q", EnvMaxAge, s,
+			)
+		}
+		c.MaxAge = d
+	}
+	if s := os.Getenv(EnvAuthMode); s != "" {
+		switch mode := AuthMode(strings.ToLower(s)); mode {
+		case AuthModeJWT, AuthModeSession:
+			c.AuthMode = mode
+		default:
+			return Config{}, fmt.Errorf(
+				"%s must be jwt or session, got %q", EnvAuthMode, s,
+			)
+		}
+	}
+	if s := os.Getenv(EnvSessionLifetime); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < c.MaxAge {
+			return Config{}, fmt.Errorf(
+				"%s must be a duration of at least %s, got %q",
+				EnvSessionLifetime, EnvMaxAge, s,
+			)
+		}
+		c.SessionLifetime = d
+	}
+
+	// browsers reject SameSite=None cookies that are not Secure
+	if c.SameSite == http.SameSiteNoneMode && !c.Secure {
+		return Config{}, fmt.Errorf(
+			"%s must be true when %s is none", EnvSecure, EnvSameSite,
+		)
+	}
+	return c, nil
+}
+
+// newCookie creates and returns a new cookie with the given name and value
+// that is issued and expires at the given times, with the attributes in the
+// config.
+func (c Config) newCookie(
+	name, value string, issued, expires time.Time,
+) http.Cookie {
+	return http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Expires:  expires.UTC(),
+		MaxAge:   max(int(expires.Sub(issued).Seconds()), 1),
+		SameSite: c.SameSite,
+		Secure:   c.Secure,
+	}
+}
@@ -0,0 +1,132 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestConfigFromEnv tests the ConfigFromEnv function to assert that it
+// overrides the default settings with the ones set in the environment.
+func TestConfigFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		domain       string
+		sameSite     string
+		secure       string
+		maxAge       string
+		authMode     string
+		lifetime     string
+		want         Config
+		wantErrIsNil bool
+	}{
+		{
+			name:         "Unset",
+			domain:       "",
+			sameSite:     "",
+			secure:       "",
+			maxAge:       "",
+			want:         DefaultConfig(),
+			wantErrIsNil: true,
+		},
+		{
+			name:     "Set",
+			domain:   "goteam.example",
+			sameSite: "Lax",
+			secure:   "false",
+			maxAge:   "8h",
+			want: Config{
+				Domain:   "goteam.example",
+				SameSite: http.SameSiteLaxMode,
+				Secure:   false,
+				MaxAge:   8 * time.Hour,
+
+				AuthMode:        AuthModeJWT,
+				SessionLifetime: 7 * 24 * time.Hour,
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:     "Session",
+			authMode: "Session",
+			lifetime: "720h",
+			want: Config{
+				SameSite: http.SameSiteNoneMode,
+				Secure:   true,
+				MaxAge:   1 * time.Hour,
+
+				AuthMode:        AuthModeSession,
+				SessionLifetime: 720 * time.Hour,
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:         "InvalidAuthMode",
+			authMode:     "cookie",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "LifetimeBelowMaxAge",
+			maxAge:       "8h",
+			lifetime:     "1h",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidSameSite",
+			domain:       "",
+			sameSite:     "sometimes",
+			secure:       "",
+			maxAge:       "",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidSecure",
+			domain:       "",
+			sameSite:     "",
+			secure:       "maybe",
+			maxAge:       "",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidMaxAge",
+			domain:       "",
+			sameSite:     "",
+			secure:       "",
+			maxAge:       "500ms",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "SameSiteNoneNotSecure",
+			domain:       "",
+			sameSite:     "none",
+			secure:       "false",
+			maxAge:       "",
+			want:         Config{},
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvDomain, c.domain)
+			t.Setenv(EnvPath, "")
+			t.Setenv(EnvSameSite, c.sameSite)
+			t.Setenv(EnvSecure, c.secure)
+			t.Setenv(EnvMaxAge, c.maxAge)
+			t.Setenv(EnvAuthMode, c.authMode)
+			t.Setenv(EnvSessionLifetime, c.lifetime)
+
+			cfg, err := ConfigFromEnv()
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, cfg, c.want)
+		})
+	}
+}
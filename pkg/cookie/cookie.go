@@ -3,18 +3,49 @@
 package cookie
 
 import (
+	"context"
 	"errors"
 	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
 )
 
-// Encoder defines a type that can be used to encode a JWT.
-type Encoder[T any] interface{ Encode(T) (http.Cookie, error) }
+// Encoder defines a type that can be used to encode a JWT. The context bounds
+// any writes the encoder makes to issue the JWT.
+type Encoder[T any] interface {
+	Encode(context.Context, T) (http.Cookie, error)
+}
 
-// Decoder defines a type that can be used to decode a JWT.
-type Decoder[T any] interface{ Decode(http.Cookie) (T, error) }
+// Decoder defines a type that can be used to decode a JWT. The context bounds
+// any lookups the decoder makes to validate the JWT.
+type Decoder[T any] interface {
+	Decode(context.Context, http.Cookie) (T, error)
+}
+
+// StringEncoder defines a type that can be used to encode a JWT into a string.
+type StringEncoder[T any] interface{ Encode(T) (string, error) }
 
 // StringDecoder defines a type that can be used to decode a JWT from a string.
 type StringDecoder[T any] interface{ Decode(string) (T, error) }
 
 // ErrInvalid means that the given cookie was invalid.
 var ErrInvalid = errors.New("invalid cookie")
+
+// parseClaims validates the signature of the given JWT with the given keys and
+// returns its claims. It returns jwt.ErrTokenExpired if the JWT expired by the
+// time told by the given clock.
+func parseClaims(
+	token string, keys Keys, clock clock.Clock,
+) (jwt.MapClaims, error) {
+	now := clock.Now()
+	claims, err := keys.parse(token, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.VerifyExpiresAt(now.Unix(), false) {
+		return nil, jwt.ErrTokenExpired
+	}
+	return claims, nil
+}
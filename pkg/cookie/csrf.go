@@ -0,0 +1,25 @@
+package cookie
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+)
+
+// CSRFName is the name of the CSRF token, which is issued alongside the auth
+// token and must be sent back in a header with every mutating request.
+const CSRFName = "csrf-token"
+
+// NewCSRF creates and returns a new CSRF token to be issued with the given
+// auth token, with the same attributes and expiry.
+func NewCSRF(auth http.Cookie) (http.Cookie, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return http.Cookie{}, err
+	}
+
+	ck := auth
+	ck.Name = CSRFName
+	ck.Value = base64.RawURLEncoding.EncodeToString(b)
+	return ck, nil
+}
@@ -0,0 +1,44 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestNewCSRF(t *testing.T) {
+	auth := http.Cookie{
+		Name:     AuthName,
+		Value:    "authtoken",
+		Domain:   "goteam.example",
+		Path:     "/",
+		Expires:  time.Now().Add(time.Hour).UTC(),
+		MaxAge:   3600,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+	}
+
+	ck, err := NewCSRF(auth)
+	assert.Nil(t.Fatal, err)
+
+	assert.Nil(t.Fatal, ck.Valid())
+	assert.Equal(t.Error, ck.Name, CSRFName)
+	assert.Equal(t.Error, len(ck.Value), 43)
+
+	// the token must have the same attributes as the auth token
+	assert.Equal(t.Error, ck.Domain, auth.Domain)
+	assert.Equal(t.Error, ck.Path, auth.Path)
+	assert.True(t.Error, ck.Expires.Equal(auth.Expires))
+	assert.Equal(t.Error, ck.MaxAge, auth.MaxAge)
+	assert.Equal(t.Error, ck.SameSite, auth.SameSite)
+	assert.Equal(t.Error, ck.Secure, auth.Secure)
+
+	// every token must be unique
+	other, err := NewCSRF(auth)
+	assert.Nil(t.Fatal, err)
+	assert.True(t.Error, other.Value != ck.Value)
+}
@@ -0,0 +1,171 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvEncryptionKeys is the name of the environment variable that holds the
+// keys that signed JWTs are encrypted with, as a comma separated list of
+// id:key pairs where each key is 32 base64 encoded bytes. The first key
+// encrypts new JWTs while the rest only decrypt the JWTs encrypted before
+// it, and JWTs are only signed if it is empty. The JWTs that are only signed
+// are still accepted when it is set, so that the ones issued before it was
+// set stay valid.
+const EnvEncryptionKeys = "JWT_ENCRYPTION_KEYS"
+
+// EncryptionKey is a key that signed JWTs are encrypted with as a JWE, using
+// direct encryption with AES-256-GCM. Its ID is set as the kid header of the
+// JWEs so that the key that decrypts them can be found.
+type EncryptionKey struct {
+	ID     string
+	Secret []byte
+}
+
+// encryptionKeysFromEnv returns the encryption keys set at EnvEncryptionKeys.
+func encryptionKeysFromEnv() ([]EncryptionKey, error) {
+	raw := os.Getenv(EnvEncryptionKeys)
+	if raw == "" {
+		return nil, nil
+	}
+	pairs, err := parsePairs(EnvEncryptionKeys, raw)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]EncryptionKey, len(pairs))
+	for i, p := range pairs {
+		secret, err := base64.StdEncoding.DecodeString(string(p.Secret))
+		if err != nil || len(secret) != 32 {
+			return nil, fmt.Errorf(
+				"%s: key %s must be 32 base64 encoded bytes",
+				EnvEncryptionKeys, p.ID,
+			)
+		}
+		keys[i] = EncryptionKey{ID: p.ID, Secret: secret}
+	}
+	return keys, nil
+}
+
+// jweHeader is the protected header of the JWEs that signed JWTs are
+// encrypted into.
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Cty string `json:"cty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// The values of the JWE header that mark direct encryption with AES-256-GCM
+// of a signed JWT.
+const (
+	jweAlg = "dir"
+	jweEnc = "A256GCM"
+	jweCty = "JWT"
+)
+
+// b64 is the encoding of each part of JWTs and JWEs.
+var b64 = base64.RawURLEncoding
+
+// encrypt encrypts the given signed JWT into a JWE in compact serialisation
+// with the first encryption key, or returns it as is if none are held.
+func (ks Keys) encrypt(token string) (string, error) {
+	if len(ks.Encryption) == 0 {
+		return token, nil
+	}
+	key := ks.Encryption[0]
+
+	hdr, err := json.Marshal(jweHeader{
+		Alg: jweAlg, Enc: jweEnc, Cty: jweCty, Kid: key.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	encHdr := b64.EncodeToString(hdr)
+
+	aead, err := newAEAD(key.Secret)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(iv); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nil, iv, []byte(token), []byte(encHdr))
+	ct, tag := sealed[:len(sealed)-aead.Overhead()],
+		sealed[len(sealed)-aead.Overhead():]
+
+	// the encrypted key is empty under direct encryption
+	return strings.Join([]string{
+		encHdr, "", b64.EncodeToString(iv),
+		b64.EncodeToString(ct), b64.EncodeToString(tag),
+	}, "."), nil
+}
+
+// decrypt decrypts the given JWE into the signed JWT it holds with the
+// encryption key its kid header names, or returns the given token as is if it
+// is not a JWE so that it is validated as a JWT that is only signed.
+func (ks Keys) decrypt(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return token, nil
+	}
+	if parts[1] != "" {
+		return "", ErrInvalid
+	}
+
+	b, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	var hdr jweHeader
+	if err = json.Unmarshal(b, &hdr); err != nil ||
+		hdr.Alg != jweAlg || hdr.Enc != jweEnc {
+		return "", ErrInvalid
+	}
+	iv, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	ct, err := b64.DecodeString(parts[3])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	tag, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return "", ErrInvalid
+	}
+
+	for _, key := range ks.Encryption {
+		if key.ID != hdr.Kid {
+			continue
+		}
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return "", err
+		}
+		if len(iv) != aead.NonceSize() {
+			return "", ErrInvalid
+		}
+		pt, err := aead.Open(nil, iv, append(ct, tag...), []byte(parts[0]))
+		if err != nil {
+			return "", ErrInvalid
+		}
+		return string(pt), nil
+	}
+	return "", errUnknownKey
+}
+
+// newAEAD returns the AES-256-GCM cipher for the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
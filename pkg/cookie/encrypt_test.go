@@ -0,0 +1,191 @@
+//go:build utest
+
+package cookie
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestEncryptionKeysFromEnv tests the encryptionKeysFromEnv function to assert
+// that it loads the encryption keys from EnvEncryptionKeys.
+func TestEncryptionKeysFromEnv(t *testing.T) {
+	var (
+		key1 = strings.Repeat("1", 32)
+		key2 = strings.Repeat("2", 32)
+		enc  = base64.StdEncoding.EncodeToString
+	)
+
+	for _, c := range []struct {
+		name         string
+		raw          string
+		want         []EncryptionKey
+		wantErrIsNil bool
+	}{
+		{name: "Unset", raw: "", want: nil, wantErrIsNil: true},
+		{
+			name: "OK",
+			raw:  "e2:" + enc([]byte(key2)) + ",e1:" + enc([]byte(key1)),
+			want: []EncryptionKey{
+				{ID: "e2", Secret: []byte(key2)},
+				{ID: "e1", Secret: []byte(key1)},
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:         "NotPairs",
+			raw:          enc([]byte(key1)),
+			want:         nil,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "NotBase64",
+			raw:          "e1:" + key1,
+			want:         nil,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "TooShort",
+			raw:          "e1:" + enc([]byte(key1[:16])),
+			want:         nil,
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvEncryptionKeys, c.raw)
+
+			keys, err := encryptionKeysFromEnv()
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Fatal, len(keys), len(c.want))
+			for i, k := range keys {
+				assert.Equal(t.Error, k.ID, c.want[i].ID)
+				assert.Equal(t.Error,
+					string(k.Secret), string(c.want[i].Secret),
+				)
+			}
+		})
+	}
+}
+
+// TestEncrypt tests the encryption of the signed JWTs to assert that their
+// claims cannot be read without the encryption key, and that they are only
+// accepted back if they decrypt with one of the keys held.
+func TestEncrypt(t *testing.T) {
+	clk := &clock.Fake{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var (
+		signing = NewKeys([]byte("secret"))
+		e1      = EncryptionKey{
+			ID: "e1", Secret: []byte(strings.Repeat("1", 32)),
+		}
+		e2 = EncryptionKey{
+			ID: "e2", Secret: []byte(strings.Repeat("2", 32)),
+		}
+
+		// encrypted returns the signing keys with the given encryption keys
+		encrypted = func(keys ...EncryptionKey) Keys {
+			ks := signing
+			ks.Encryption = keys
+			return ks
+		}
+		auth = NewAuth("bob123", true, "team1")
+	)
+
+	// encode returns the auth token encoded with the given keys
+	encode := func(t *testing.T, keys Keys) string {
+		ck, err := NewAuthEncoder(keys, DefaultConfig(), clk).Encode(
+			context.Background(), auth,
+		)
+		assert.Nil(t.Fatal, err)
+		return ck.Value
+	}
+
+	t.Run("Unreadable", func(t *testing.T) {
+		token := encode(t, encrypted(e1))
+
+		assert.Equal(t.Error, strings.Count(token, "."), 4)
+		_, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		assert.True(t.Error, err != nil)
+		for _, part := range strings.Split(token, ".") {
+			b, _ := base64.RawURLEncoding.DecodeString(part)
+			assert.True(t.Error, !strings.Contains(string(b), "bob123"))
+		}
+	})
+
+	var (
+		withE1     = encode(t, encrypted(e1))
+		signedOnly = encode(t, signing)
+		parts      = strings.Split(withE1, ".")
+	)
+	ct, err := base64.RawURLEncoding.DecodeString(parts[3])
+	assert.Nil(t.Fatal, err)
+	ct[0] ^= 1
+	parts[3] = base64.RawURLEncoding.EncodeToString(ct)
+	tampered := strings.Join(parts, ".")
+
+	for _, c := range []struct {
+		name         string
+		token        string
+		decodeKeys   Keys
+		wantErrIsNil bool
+	}{
+		{
+			name:         "Current",
+			token:        withE1,
+			decodeKeys:   encrypted(e1),
+			wantErrIsNil: true,
+		},
+		{
+			name:         "Previous",
+			token:        withE1,
+			decodeKeys:   encrypted(e2, e1),
+			wantErrIsNil: true,
+		},
+		{
+			name:         "SignedOnly",
+			token:        signedOnly,
+			decodeKeys:   encrypted(e1),
+			wantErrIsNil: true,
+		},
+		{
+			name:         "UnknownKey",
+			token:        withE1,
+			decodeKeys:   encrypted(e2),
+			wantErrIsNil: false,
+		},
+		{
+			name:         "NoEncryption",
+			token:        withE1,
+			decodeKeys:   signing,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "Tampered",
+			token:        tampered,
+			decodeKeys:   encrypted(e1),
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := NewAuthDecoder(c.decodeKeys, clk)
+
+			got, err := sut.Decode(
+				context.Background(), http.Cookie{Value: c.token},
+			)
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			if c.wantErrIsNil {
+				assert.Equal(t.Error, got, auth)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -14,7 +15,7 @@ type FakeEncoder[T any] struct {
 
 // Encode discards the input parameters and returns the FakeEncoder's Res and
 // Err field values.
-func (f *FakeEncoder[T]) Encode(T) (http.Cookie, error) {
+func (f *FakeEncoder[T]) Encode(context.Context, T) (http.Cookie, error) {
 	return f.Res, f.Err
 }
 
@@ -26,7 +27,19 @@ type FakeDecoder[T any] struct {
 
 // Decode discards the input parameters and returns the FakeDecoder's Res and
 // Err field values.
-func (f *FakeDecoder[T]) Decode(http.Cookie) (T, error) {
+func (f *FakeDecoder[T]) Decode(context.Context, http.Cookie) (T, error) {
+	return f.Res, f.Err
+}
+
+// FakeStringEncoder is a test fake for StringEncoder.
+type FakeStringEncoder[T any] struct {
+	Res string
+	Err error
+}
+
+// Encode discards the input parameters and returns the FakeStringEncoder's
+// Res and Err field values.
+func (f *FakeStringEncoder[T]) Encode(T) (string, error) {
 	return f.Res, f.Err
 }
 
@@ -1,71 +1,111 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
 )
 
 // InviteName is the name of the invite token.
 const InviteName = "invite-token"
 
 // Invite defines the body of an Invite token.
-type Invite struct{ TeamID string }
+type Invite struct {
+	TeamID string
+
+	// Email is the address the invite was sent to. It is empty for invites
+	// that admins share themselves.
+	Email string
+
+	// Nonce identifies the invite among the team's pending invites so that it
+	// can only be used once.
+	Nonce string
+
+	// IsGuest is set for invites to join the team as a guest. The boards the
+	// guest can see are held by the team's pending invite.
+	IsGuest bool
+}
 
 // NewInvite creates and returns a new Invite.
-func NewInvite(teamID string) Invite { return Invite{TeamID: teamID} }
+func NewInvite(teamID, nonce string) Invite {
+	return Invite{TeamID: teamID, Nonce: nonce}
+}
+
+// NewEmailInvite creates and returns a new Invite bound to the given email
+// address.
+func NewEmailInvite(teamID, email, nonce string) Invite {
+	return Invite{TeamID: teamID, Email: email, Nonce: nonce}
+}
 
 // InviteEncoder defines a type that can be used to encode an invite token.
 type InviteEncoder struct {
-	key []byte
-	dur time.Duration
+	keys  Keys
+	dur   time.Duration
+	cfg   Config
+	clock clock.Clock
 }
 
-// NewInviteEncoder creates and returns a new InviteEncoder.
-func NewInviteEncoder(key []byte, dur time.Duration) InviteEncoder {
-	return InviteEncoder{key: key, dur: dur}
+// NewInviteEncoder creates and returns a new InviteEncoder that encodes
+// invite tokens valid for the given duration into cookies with the config's
+// attributes. The tokens' expiry is counted from the time told by the given
+// clock.
+func NewInviteEncoder(
+	keys Keys, dur time.Duration, cfg Config, clock clock.Clock,
+) InviteEncoder {
+	return InviteEncoder{keys: keys, dur: dur, cfg: cfg, clock: clock}
 }
 
 // Encode encodes an Invite into a JWT string.
-func (e InviteEncoder) Encode(inv Invite) (http.Cookie, error) {
-	exp := time.Now().Add(e.dur)
+func (e InviteEncoder) Encode(
+	_ context.Context, inv Invite,
+) (http.Cookie, error) {
+	now := e.clock.Now()
+	exp := now.Add(e.dur)
 
-	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"teamID": inv.TeamID,
-		"exp":    exp.Unix(),
-	}).SignedString(e.key)
+	claims := jwt.MapClaims{"teamID": inv.TeamID, "exp": exp.Unix()}
+	if inv.Email != "" {
+		claims["email"] = inv.Email
+	}
+	if inv.Nonce != "" {
+		claims["nonce"] = inv.Nonce
+	}
+	if inv.IsGuest {
+		claims["guest"] = true
+	}
+	tk, err := e.keys.sign(claims)
 	if err != nil {
 		return http.Cookie{}, err
 	}
 
-	return http.Cookie{
-		Name:     InviteName,
-		Value:    tk,
-		Expires:  exp.UTC(),
-		SameSite: http.SameSiteNoneMode,
-		Secure:   true,
-	}, nil
+	return e.cfg.newCookie(InviteName, tk, now, exp), nil
 }
 
 // InviteDecoder defines a type that can be used to decode an invite token.
-type InviteDecoder struct{ key []byte }
+type InviteDecoder struct {
+	keys  Keys
+	clock clock.Clock
+}
 
-// NewInviteDecoder creates and returns a new InviteDecoder.
-func NewInviteDecoder(key []byte) InviteDecoder {
-	return InviteDecoder{key: key}
+// NewInviteDecoder creates and returns a new InviteDecoder that rejects the
+// tokens that expired by the time told by the given clock.
+func NewInviteDecoder(keys Keys, clock clock.Clock) InviteDecoder {
+	return InviteDecoder{keys: keys, clock: clock}
 }
 
 // Decode validates and decodes a raw JWT string into an Invite.
 func (d InviteDecoder) Decode(token string) (Invite, error) {
-	claims := jwt.MapClaims{}
-	if _, err := jwt.ParseWithClaims(
-		token, &claims, func(token *jwt.Token) (any, error) {
-			return d.key, nil
-		},
-	); err != nil {
+	claims, err := parseClaims(token, d.keys, d.clock)
+	if err != nil {
 		return Invite{}, err
 	}
 
-	return NewInvite(claims["teamID"].(string)), nil
+	email, _ := claims["email"].(string)
+	nonce, _ := claims["nonce"].(string)
+	inv := NewEmailInvite(claims["teamID"].(string), email, nonce)
+	inv.IsGuest, _ = claims["guest"].(bool)
+	return inv, nil
 }
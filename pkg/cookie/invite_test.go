@@ -3,6 +3,7 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
@@ -10,28 +11,40 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 )
 
 func TestInvite(t *testing.T) {
 	key := []byte("signkey")
+	keys := NewKeys(key)
 	teamID := "teamid"
+	now := time.Now().Truncate(time.Second)
+	clk := &clock.Fake{Time: now}
 
 	t.Run("Encode", func(t *testing.T) {
-		sut := NewInviteEncoder(key, 1*time.Hour)
+		// the invite's own duration is used rather than the config's
+		cfg := Config{
+			Domain:   "goteam.example",
+			Path:     "/",
+			SameSite: http.SameSiteLaxMode,
+			Secure:   false,
+			MaxAge:   24 * time.Hour,
+		}
+		sut := NewInviteEncoder(keys, 1*time.Hour, cfg, clk)
 
-		ck, err := sut.Encode(NewInvite(teamID))
+		ck, err := sut.Encode(context.Background(), NewInvite(teamID, "nonce1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		assert.Nil(t.Fatal, ck.Valid())
 		assert.Equal(t.Error, ck.Name, InviteName)
-		assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
-		assert.True(t.Error, ck.Secure)
-		assert.True(t.Error,
-			ck.Expires.UTC().After(time.Now().Add(59*time.Minute).UTC()))
-		assert.True(t.Error,
-			ck.Expires.UTC().Before(time.Now().Add(61*time.Minute).UTC()))
+		assert.Equal(t.Error, ck.Domain, cfg.Domain)
+		assert.Equal(t.Error, ck.Path, cfg.Path)
+		assert.Equal(t.Error, ck.SameSite, http.SameSiteLaxMode)
+		assert.Equal(t.Error, ck.Secure, false)
+		assert.Equal(t.Error, ck.MaxAge, 3600)
+		assert.Equal(t.Error, ck.Expires, now.Add(time.Hour).UTC())
 
 		claims := jwt.MapClaims{}
 		if _, err = jwt.ParseWithClaims(
@@ -43,18 +56,45 @@ func TestInvite(t *testing.T) {
 		}
 
 		assert.Equal(t.Error, claims["teamID"].(string), teamID)
-		assert.True(t.Error,
-			int64(claims["exp"].(float64)) >
-				time.Now().Add(59*time.Minute).Unix(),
+		assert.Equal(t.Error, claims["nonce"].(string), "nonce1")
+		assert.Equal(t.Error,
+			int64(claims["exp"].(float64)), now.Add(time.Hour).Unix(),
 		)
-		assert.True(t.Error,
-			int64(claims["exp"].(float64)) <
-				time.Now().Add(61*time.Minute).Unix(),
+		_, ok := claims["email"]
+		assert.Equal(t.Error, ok, false)
+	})
+
+	t.Run("EncodeDecodeEmail", func(t *testing.T) {
+		ck, err := NewInviteEncoder(
+			keys, 1*time.Hour, DefaultConfig(), clk,
+		).Encode(
+			context.Background(),
+			NewEmailInvite(teamID, "bob@example.com", "nonce1"),
 		)
+		assert.Nil(t.Fatal, err)
+
+		inv, err := NewInviteDecoder(keys, clk).Decode(ck.Value)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, inv.TeamID, teamID)
+		assert.Equal(t.Error, inv.Email, "bob@example.com")
+		assert.Equal(t.Error, inv.Nonce, "nonce1")
+	})
+
+	t.Run("EncodeDecodeGuest", func(t *testing.T) {
+		want := NewEmailInvite(teamID, "bob@example.com", "nonce1")
+		want.IsGuest = true
+		ck, err := NewInviteEncoder(
+			keys, 1*time.Hour, DefaultConfig(), clk,
+		).Encode(context.Background(), want)
+		assert.Nil(t.Fatal, err)
+
+		inv, err := NewInviteDecoder(keys, clk).Decode(ck.Value)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, inv, want)
 	})
 
 	t.Run("Decode", func(t *testing.T) {
-		sut := NewInviteDecoder(key)
+		sut := NewInviteDecoder(keys, clk)
 
 		for _, c := range []struct {
 			name       string
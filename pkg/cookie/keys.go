@@ -0,0 +1,315 @@
+package cookie
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Names of the environment variables to load the keys that JWTs are signed
+// with from. EnvKeys and the file at EnvKeysFile hold a comma separated list of
+// id:secret pairs, the first of which signs new JWTs while the rest only verify
+// the JWTs signed before it. EnvKeys takes precedence over EnvKeysFile, which
+// is there for keys mounted from a secrets manager, and both over EnvKey, which
+// holds a single key without an ID.
+const (
+	EnvKey      = "JWT_KEY"
+	EnvKeys     = "JWT_KEYS"
+	EnvKeysFile = "JWT_KEYS_FILE"
+
+	// EnvPreviousUntil is the name of the environment variable that holds the
+	// RFC 3339 time until which the keys after the first are accepted. They
+	// are accepted until they are removed from the list if it is empty. Share
+	// tokens do not expire, so the links shared before a rotation stop working
+	// once the key they were signed with is no longer accepted.
+	EnvPreviousUntil = "JWT_PREVIOUS_KEYS_UNTIL"
+
+	// EnvAlgorithm is the name of the environment variable that holds the
+	// algorithm JWTs are signed with, which is HS256 if it is empty. Under
+	// EdDSA and RS256, the secret of each key is the path to its PEM-encoded
+	// private key, or to its public key on the services that only verify JWTs
+	// so that they need not hold the private key.
+	EnvAlgorithm = "JWT_ALGORITHM"
+
+	// EnvIssuer and EnvAudience are the names of the environment variables
+	// that hold the iss and aud claims set on JWTs, which the JWTs without
+	// them are rejected for when set. They keep the JWTs minted by one
+	// deployment from being accepted by another that shares its key, but
+	// setting them rejects the share links shared before as well.
+	EnvIssuer   = "JWT_ISSUER"
+	EnvAudience = "JWT_AUDIENCE"
+)
+
+// Key is a secret that JWTs are signed with. Its ID is set as the kid header of
+// the JWTs signed with it so that keys can be rotated without invalidating the
+// JWTs signed with the previous ones.
+type Key struct {
+	ID     string
+	Secret []byte
+
+	// Method is the asymmetric method that JWTs are signed with Private and
+	// verified with Public under. JWTs are signed with Secret under HS256 if
+	// it is nil, and Private is nil on the services that only verify them.
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// errNoPrivateKey means that a JWT was to be signed with a key that is only
+// held to verify JWTs with.
+var errNoPrivateKey = errors.New("signing key has no private key")
+
+// method returns the method that JWTs are signed with the key under.
+func (k Key) method() jwt.SigningMethod {
+	if k.Method == nil {
+		return jwt.SigningMethodHS256
+	}
+	return k.Method
+}
+
+// signingKey returns the key that JWTs are signed with.
+func (k Key) signingKey() (any, error) {
+	if k.Method == nil {
+		return k.Secret, nil
+	}
+	if k.Private == nil {
+		return nil, errNoPrivateKey
+	}
+	return k.Private, nil
+}
+
+// verificationKey returns the key that JWTs are verified with.
+func (k Key) verificationKey() any {
+	if k.Method == nil {
+		return k.Secret
+	}
+	return k.Public
+}
+
+// newPEMKey creates and returns the key with the given ID that signs JWTs
+// under the given asymmetric algorithm with the given PEM-encoded private key,
+// or only verifies them if it is a public key.
+func newPEMKey(id, alg string, b []byte) (Key, error) {
+	switch alg {
+	case jwt.SigningMethodEdDSA.Alg():
+		k := Key{ID: id, Method: jwt.SigningMethodEdDSA}
+		if priv, err := jwt.ParseEdPrivateKeyFromPEM(b); err == nil {
+			k.Private = priv
+			k.Public = priv.(ed25519.PrivateKey).Public()
+			return k, nil
+		}
+		pub, err := jwt.ParseEdPublicKeyFromPEM(b)
+		if err != nil {
+			return Key{}, err
+		}
+		k.Public = pub
+		return k, nil
+	case jwt.SigningMethodRS256.Alg():
+		k := Key{ID: id, Method: jwt.SigningMethodRS256}
+		if priv, err := jwt.ParseRSAPrivateKeyFromPEM(b); err == nil {
+			k.Private, k.Public = priv, &priv.PublicKey
+			return k, nil
+		}
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(b)
+		if err != nil {
+			return Key{}, err
+		}
+		k.Public = pub
+		return k, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+}
+
+// Keys holds the keys that JWTs are signed and verified with.
+type Keys struct {
+	// Current is the key that new JWTs are signed with.
+	Current Key
+
+	// Previous holds the keys that were rotated out, which are only used to
+	// verify the JWTs signed with them until PreviousUntil, or for as long as
+	// they are held if it is zero.
+	Previous      []Key
+	PreviousUntil time.Time
+
+	// Issuer and Audience are set as the iss and aud claims of the JWTs
+	// signed, and the JWTs whose claims do not match them are rejected. They
+	// are neither set nor checked if empty.
+	Issuer   string
+	Audience string
+
+	// Encryption holds the keys that the signed JWTs are encrypted with so
+	// that their claims cannot be read by client-side scripts or in logs.
+	// The first key encrypts and all of them decrypt, and the JWTs are only
+	// signed if it is empty.
+	Encryption []EncryptionKey
+}
+
+// NewKeys creates and returns the Keys that sign and verify JWTs with the given
+// secret alone, without setting their kid header.
+func NewKeys(secret []byte) Keys { return Keys{Current: Key{Secret: secret}} }
+
+// KeysFromEnv returns the keys set in the environment variables, which are
+// described alongside EnvKeys and EnvAlgorithm.
+func KeysFromEnv() (Keys, error) {
+	alg := os.Getenv(EnvAlgorithm)
+	switch alg {
+	case "", jwt.SigningMethodHS256.Alg():
+		alg = ""
+	case jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg():
+	default:
+		return Keys{}, fmt.Errorf(
+			"%s must be HS256, EdDSA, or RS256, got %q", EnvAlgorithm, alg,
+		)
+	}
+
+	name, raw := EnvKeys, os.Getenv(EnvKeys)
+	if raw == "" {
+		if path := os.Getenv(EnvKeysFile); path != "" {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return Keys{}, fmt.Errorf("%s: %w", EnvKeysFile, err)
+			}
+			name, raw = EnvKeysFile, strings.TrimSpace(string(b))
+		}
+	}
+
+	var keys []Key
+	if raw == "" {
+		secret := os.Getenv(EnvKey)
+		if secret == "" {
+			return Keys{}, fmt.Errorf(
+				"%s, %s, or %s must be set", EnvKeys, EnvKeysFile, EnvKey,
+			)
+		}
+		name, keys = EnvKey, []Key{{Secret: []byte(secret)}}
+	} else {
+		var err error
+		if keys, err = parsePairs(name, raw); err != nil {
+			return Keys{}, err
+		}
+	}
+
+	// replace the secrets with the keys at their paths under an asymmetric
+	// algorithm
+	if alg != "" {
+		for i, k := range keys {
+			b, err := os.ReadFile(string(k.Secret))
+			if err != nil {
+				return Keys{}, fmt.Errorf("%s: %w", name, err)
+			}
+			if keys[i], err = newPEMKey(k.ID, alg, b); err != nil {
+				return Keys{}, fmt.Errorf("%s: %s: %w", name, k.Secret, err)
+			}
+		}
+	}
+	ks := Keys{Current: keys[0], Previous: keys[1:]}
+
+	if until := os.Getenv(EnvPreviousUntil); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return Keys{}, fmt.Errorf(
+				"%s must be an RFC 3339 time: %w", EnvPreviousUntil, err,
+			)
+		}
+		ks.PreviousUntil = t
+	}
+	ks.Issuer, ks.Audience = os.Getenv(EnvIssuer), os.Getenv(EnvAudience)
+
+	enc, err := encryptionKeysFromEnv()
+	if err != nil {
+		return Keys{}, err
+	}
+	ks.Encryption = enc
+	return ks, nil
+}
+
+// parsePairs parses the given comma separated list of id:secret pairs, set in
+// the environment variable of the given name, into keys.
+func parsePairs(name, raw string) ([]Key, error) {
+	var keys []Key
+	for _, pair := range strings.Split(raw, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf(
+				"%s must be a comma separated list of id:secret pairs", name,
+			)
+		}
+		keys = append(keys, Key{ID: id, Secret: []byte(secret)})
+	}
+	return keys, nil
+}
+
+// sign signs the given claims into a JWT with the current key, setting its
+// issuer and audience, and encrypts it if encryption keys are held.
+func (ks Keys) sign(claims jwt.MapClaims) (string, error) {
+	key, err := ks.Current.signingKey()
+	if err != nil {
+		return "", err
+	}
+	if ks.Issuer != "" {
+		claims["iss"] = ks.Issuer
+	}
+	if ks.Audience != "" {
+		claims["aud"] = ks.Audience
+	}
+	tk := jwt.NewWithClaims(ks.Current.method(), claims)
+	if ks.Current.ID != "" {
+		tk.Header["kid"] = ks.Current.ID
+	}
+	signed, err := tk.SignedString(key)
+	if err != nil {
+		return "", err
+	}
+	return ks.encrypt(signed)
+}
+
+// errUnknownKey means that the JWT was signed with a key that is not held or
+// is no longer accepted.
+var errUnknownKey = errors.New("unknown signing key")
+
+// parse decrypts the given JWT if it is encrypted, validates its signature
+// against the keys accepted at the given time, as well as its issuer and
+// audience, and returns its claims.
+// The JWTs without a kid header are checked against each of the keys, so that
+// the ones signed before the keys had IDs stay valid through the first
+// rotation.
+func (ks Keys) parse(token string, now time.Time) (jwt.MapClaims, error) {
+	token, err := ks.decrypt(token)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := []Key{ks.Current}
+	if ks.PreviousUntil.IsZero() || now.Before(ks.PreviousUntil) {
+		accepted = append(accepted, ks.Previous...)
+	}
+
+	err = errUnknownKey
+	for _, key := range accepted {
+		claims := jwt.MapClaims{}
+		if _, err = jwt.ParseWithClaims(
+			token, &claims, func(tk *jwt.Token) (any, error) {
+				if kid, ok := tk.Header["kid"].(string); ok && kid != key.ID {
+					return nil, errUnknownKey
+				}
+				return key.verificationKey(), nil
+			},
+			jwt.WithValidMethods([]string{key.method().Alg()}),
+			jwt.WithoutClaimsValidation(),
+		); err == nil {
+			if ks.Issuer != "" && !claims.VerifyIssuer(ks.Issuer, true) ||
+				ks.Audience != "" && !claims.VerifyAudience(ks.Audience, true) {
+				return nil, ErrInvalid
+			}
+			return claims, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,414 @@
+//go:build utest
+
+package cookie
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestKeysFromEnv tests the KeysFromEnv function to assert that it loads the
+// keys from the environment variables in their order of precedence.
+func TestKeysFromEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keys")
+	err := os.WriteFile(file, []byte("k3:filesecret\n"), 0o600)
+	assert.Nil(t.Fatal, err)
+
+	for _, c := range []struct {
+		name          string
+		algorithm     string
+		key           string
+		keys          string
+		keysFile      string
+		previousUntil string
+		issuer        string
+		audience      string
+		want          Keys
+		wantErrIsNil  bool
+	}{
+		{
+			name:         "Unset",
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "Key",
+			key:          "secret",
+			want:         NewKeys([]byte("secret")),
+			wantErrIsNil: true,
+		},
+		{
+			name:     "Keys",
+			key:      "secret",
+			keys:     "k2:newsecret,k1:oldsecret",
+			keysFile: file,
+			want: Keys{
+				Current:  Key{ID: "k2", Secret: []byte("newsecret")},
+				Previous: []Key{{ID: "k1", Secret: []byte("oldsecret")}},
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:     "KeysFile",
+			key:      "secret",
+			keysFile: file,
+			want: Keys{
+				Current:  Key{ID: "k3", Secret: []byte("filesecret")},
+				Previous: []Key{},
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:          "PreviousUntil",
+			keys:          "k2:newsecret,k1:oldsecret",
+			previousUntil: "2024-01-02T00:00:00Z",
+			want: Keys{
+				Current:       Key{ID: "k2", Secret: []byte("newsecret")},
+				Previous:      []Key{{ID: "k1", Secret: []byte("oldsecret")}},
+				PreviousUntil: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:     "IssuerAudience",
+			key:      "secret",
+			issuer:   "https://api.goteam.example",
+			audience: "goteam.example",
+			want: Keys{
+				Current:  Key{Secret: []byte("secret")},
+				Issuer:   "https://api.goteam.example",
+				Audience: "goteam.example",
+			},
+			wantErrIsNil: true,
+		},
+		{
+			name:         "KeysFileMissing",
+			keysFile:     filepath.Join(t.TempDir(), "missing"),
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidKeys",
+			keys:         "k2:newsecret,oldsecret",
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidAlgorithm",
+			algorithm:    "HS512",
+			key:          "secret",
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "AlgorithmKeyFileMissing",
+			algorithm:    "EdDSA",
+			keys:         "k1:" + filepath.Join(t.TempDir(), "missing"),
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "AlgorithmInvalidKey",
+			algorithm:    "RS256",
+			keys:         "k1:" + file,
+			want:         Keys{},
+			wantErrIsNil: false,
+		},
+		{
+			name:          "InvalidPreviousUntil",
+			keys:          "k2:newsecret,k1:oldsecret",
+			previousUntil: "tomorrow",
+			want:          Keys{},
+			wantErrIsNil:  false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvAlgorithm, c.algorithm)
+			t.Setenv(EnvKey, c.key)
+			t.Setenv(EnvKeys, c.keys)
+			t.Setenv(EnvKeysFile, c.keysFile)
+			t.Setenv(EnvPreviousUntil, c.previousUntil)
+			t.Setenv(EnvIssuer, c.issuer)
+			t.Setenv(EnvAudience, c.audience)
+
+			keys, err := KeysFromEnv()
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, keys.Current.ID, c.want.Current.ID)
+			assert.Equal(t.Error,
+				string(keys.Current.Secret), string(c.want.Current.Secret),
+			)
+			assert.Equal(t.Fatal, len(keys.Previous), len(c.want.Previous))
+			for i, k := range keys.Previous {
+				assert.Equal(t.Error, k.ID, c.want.Previous[i].ID)
+				assert.Equal(t.Error,
+					string(k.Secret), string(c.want.Previous[i].Secret),
+				)
+			}
+			assert.Equal(t.Error,
+				keys.PreviousUntil.Equal(c.want.PreviousUntil), true,
+			)
+			assert.Equal(t.Error, keys.Issuer, c.want.Issuer)
+			assert.Equal(t.Error, keys.Audience, c.want.Audience)
+		})
+	}
+}
+
+// TestKeys tests the keys that JWTs are signed and verified with to assert
+// that the JWTs signed before a rotation stay valid until the end of the grace
+// period.
+func TestKeys(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := &clock.Fake{Time: now}
+	var (
+		legacy  = NewKeys([]byte("legacysecret"))
+		old     = Keys{Current: Key{ID: "k1", Secret: []byte("oldsecret")}}
+		rotated = Keys{
+			Current: Key{ID: "k2", Secret: []byte("newsecret")},
+			Previous: []Key{
+				{ID: "k1", Secret: []byte("oldsecret")},
+				{ID: "legacy", Secret: []byte("legacysecret")},
+			},
+			PreviousUntil: now.Add(time.Hour),
+		}
+
+		// scoped returns the given keys with the given issuer and audience
+		scoped = func(keys Keys, iss, aud string) Keys {
+			keys.Issuer, keys.Audience = iss, aud
+			return keys
+		}
+	)
+
+	// encode returns the auth token encoded with the given keys
+	encode := func(t *testing.T, keys Keys) string {
+		ck, err := NewAuthEncoder(keys, DefaultConfig(), clk).Encode(
+			context.Background(), NewAuth("bob123", true, "team1"),
+		)
+		assert.Nil(t.Fatal, err)
+		return ck.Value
+	}
+
+	t.Run("KeyID", func(t *testing.T) {
+		tk, _, err := jwt.NewParser().ParseUnverified(
+			encode(t, rotated), jwt.MapClaims{},
+		)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, tk.Header["kid"], any("k2"))
+
+		tk, _, err = jwt.NewParser().ParseUnverified(
+			encode(t, legacy), jwt.MapClaims{},
+		)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, tk.Header["kid"], nil)
+	})
+
+	for _, c := range []struct {
+		name         string
+		encodeKeys   Keys
+		decodeKeys   Keys
+		now          time.Time
+		wantErrIsNil bool
+	}{
+		{
+			name:         "Current",
+			encodeKeys:   rotated,
+			decodeKeys:   rotated,
+			now:          now,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "Previous",
+			encodeKeys:   old,
+			decodeKeys:   rotated,
+			now:          now,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "PreviousWithoutID",
+			encodeKeys:   legacy,
+			decodeKeys:   rotated,
+			now:          now,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "PreviousAfterGrace",
+			encodeKeys:   old,
+			decodeKeys:   rotated,
+			now:          now.Add(time.Hour),
+			wantErrIsNil: false,
+		},
+		{
+			name: "UnknownID",
+			encodeKeys: Keys{
+				Current: Key{ID: "k0", Secret: []byte("oldsecret")},
+			},
+			decodeKeys:   rotated,
+			now:          now,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "IssuerAudience",
+			encodeKeys:   scoped(rotated, "goteam-a", "goteam.example"),
+			decodeKeys:   scoped(rotated, "goteam-a", "goteam.example"),
+			now:          now,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "NoIssuerAudience",
+			encodeKeys:   rotated,
+			decodeKeys:   scoped(rotated, "goteam-a", "goteam.example"),
+			now:          now,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "WrongIssuer",
+			encodeKeys:   scoped(rotated, "goteam-b", "goteam.example"),
+			decodeKeys:   scoped(rotated, "goteam-a", "goteam.example"),
+			now:          now,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "WrongAudience",
+			encodeKeys:   scoped(rotated, "goteam-a", "other.example"),
+			decodeKeys:   scoped(rotated, "goteam-a", "goteam.example"),
+			now:          now,
+			wantErrIsNil: false,
+		},
+		{
+			name:         "Unknown",
+			encodeKeys:   NewKeys([]byte("othersecret")),
+			decodeKeys:   rotated,
+			now:          now,
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			token := encode(t, c.encodeKeys)
+			sut := NewAuthDecoder(c.decodeKeys, &clock.Fake{Time: c.now})
+
+			_, err := sut.Decode(
+				context.Background(), http.Cookie{Value: token},
+			)
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+		})
+	}
+}
+
+// TestKeysAsymmetric tests the keys that JWTs are signed and verified with
+// under the asymmetric algorithms to assert that the services that only hold
+// the public keys can verify the JWTs but cannot sign them.
+func TestKeysAsymmetric(t *testing.T) {
+	dir := t.TempDir()
+	clk := &clock.Fake{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	// writePEM writes the given key as a PEM block of the given type to a
+	// file and returns its path
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(
+			path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600,
+		)
+		assert.Nil(t.Fatal, err)
+		return path
+	}
+
+	// writeKeyPair writes the given private key and its public key to files
+	// and returns their paths
+	writeKeyPair := func(
+		name string, priv crypto.Signer,
+	) (privPath, pubPath string) {
+		privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+		assert.Nil(t.Fatal, err)
+		pubDER, err := x509.MarshalPKIXPublicKey(priv.Public())
+		assert.Nil(t.Fatal, err)
+		return writePEM(name+".pem", "PRIVATE KEY", privDER),
+			writePEM(name+".pub.pem", "PUBLIC KEY", pubDER)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t.Fatal, err)
+	edPriv, edPub := writeKeyPair("ed", edKey)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+	rsaPriv, rsaPub := writeKeyPair("rsa", rsaKey)
+
+	// loadKeys loads the keys from the given environment variables
+	loadKeys := func(t *testing.T, alg, keys string) Keys {
+		t.Setenv(EnvAlgorithm, alg)
+		t.Setenv(EnvKeys, keys)
+		t.Setenv(EnvKeysFile, "")
+		t.Setenv(EnvKey, "")
+		t.Setenv(EnvPreviousUntil, "")
+		ks, err := KeysFromEnv()
+		assert.Nil(t.Fatal, err)
+		return ks
+	}
+
+	for _, c := range []struct {
+		alg     string
+		privKey string
+		pubKey  string
+	}{
+		{alg: "EdDSA", privKey: edPriv, pubKey: edPub},
+		{alg: "RS256", privKey: rsaPriv, pubKey: rsaPub},
+	} {
+		t.Run(c.alg, func(t *testing.T) {
+			signer := loadKeys(t, c.alg, "k1:"+c.privKey)
+			verifier := loadKeys(t, c.alg, "k1:"+c.pubKey)
+			auth := NewAuth("bob123", true, "team1")
+
+			ck, err := NewAuthEncoder(signer, DefaultConfig(), clk).Encode(
+				context.Background(), auth,
+			)
+			assert.Nil(t.Fatal, err)
+			tk, _, err := jwt.NewParser().ParseUnverified(
+				ck.Value, jwt.MapClaims{},
+			)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, tk.Header["alg"], any(c.alg))
+
+			got, err := NewAuthDecoder(verifier, clk).Decode(
+				context.Background(), ck,
+			)
+			assert.Nil(t.Error, err)
+			assert.Equal(t.Error, got, auth)
+
+			// the services that only hold the public key cannot sign
+			_, err = NewAuthEncoder(verifier, DefaultConfig(), clk).Encode(
+				context.Background(), auth,
+			)
+			assert.ErrIs(t.Error, err, errNoPrivateKey)
+
+			// the JWTs signed under HS256 are not accepted, so the public
+			// key cannot be used as an HMAC secret to forge them
+			pubPEM, err := os.ReadFile(c.pubKey)
+			assert.Nil(t.Fatal, err)
+			forged, err := NewAuthEncoder(
+				Keys{Current: Key{ID: "k1", Secret: pubPEM}},
+				DefaultConfig(),
+				clk,
+			).Encode(context.Background(), auth)
+			assert.Nil(t.Fatal, err)
+			_, err = NewAuthDecoder(verifier, clk).Decode(
+				context.Background(), forged,
+			)
+			assert.True(t.Error, err != nil)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cookie
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// SessionDecoder is a Decoder[Auth] that rejects the auth tokens whose session
+// was revoked by their user, and the auth tokens of disabled users.
+type SessionDecoder struct {
+	authDecoder   Decoder[Auth]
+	userRetriever db.Retriever[usertbl.User]
+	clock         clock.Clock
+}
+
+// NewSessionDecoder creates and returns a new SessionDecoder that rejects the
+// tokens whose session expired by the time told by the given clock.
+func NewSessionDecoder(
+	authDecoder Decoder[Auth],
+	userRetriever db.Retriever[usertbl.User],
+	clock clock.Clock,
+) SessionDecoder {
+	return SessionDecoder{
+		authDecoder: authDecoder, userRetriever: userRetriever, clock: clock,
+	}
+}
+
+// Decode decodes the auth token and checks that its user is not disabled and
+// still holds its session. Tokens issued before sessions were tracked carry no
+// session ID and are accepted until they expire unless their user is disabled.
+func (d SessionDecoder) Decode(
+	ctx context.Context, ck http.Cookie,
+) (Auth, error) {
+	auth, err := d.authDecoder.Decode(ctx, ck)
+	if err != nil {
+		return auth, err
+	}
+
+	user, err := d.userRetriever.Retrieve(ctx, auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		return Auth{}, ErrInvalid
+	} else if err != nil {
+		return Auth{}, err
+	}
+	if user.IsDisabled {
+		return Auth{}, ErrInvalid
+	}
+	if auth.SessionID != "" &&
+		!user.HasSession(auth.SessionID, d.clock.Now().Unix()) {
+		return Auth{}, ErrInvalid
+	}
+	return auth, nil
+}
@@ -0,0 +1,145 @@
+//go:build utest
+
+package cookie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// TestSessionDecoder tests the Decode method of SessionDecoder to assert that it
+// behaves correctly in all possible scenarios.
+func TestSessionDecoder(t *testing.T) {
+	authDecoder := &FakeDecoder[Auth]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	clk := &clock.Fake{Time: time.Unix(1700000000, 0)}
+	sut := NewSessionDecoder(authDecoder, userRetriever, clk)
+
+	auth := Auth{Username: "bob123", SessionID: "sess1"}
+	future := clk.Time.Add(time.Hour).Unix()
+
+	for _, c := range []struct {
+		name        string
+		authDecoded Auth
+		errDecode   error
+		user        usertbl.User
+		errRetrieve error
+		wantErr     error
+	}{
+		{
+			name:        "ErrDecode",
+			authDecoded: Auth{},
+			errDecode:   ErrInvalid,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "NoSessionID",
+			authDecoded: Auth{Username: "bob123"},
+			errDecode:   nil,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "NoSessionIDUserDisabled",
+			authDecoded: Auth{Username: "bob123"},
+			errDecode:   nil,
+			user:        usertbl.User{IsDisabled: true},
+			errRetrieve: nil,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "UserNotFound",
+			authDecoded: auth,
+			errDecode:   nil,
+			user:        usertbl.User{},
+			errRetrieve: db.ErrNoItem,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "UserNotFoundWrapped",
+			authDecoded: auth,
+			errDecode:   nil,
+			user:        usertbl.User{},
+			errRetrieve: fmt.Errorf("retrieve: %w", db.ErrNoItem),
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "ErrRetrieve",
+			authDecoded: auth,
+			errDecode:   nil,
+			user:        usertbl.User{},
+			errRetrieve: errors.New("retrieve failed"),
+			wantErr:     errors.New("retrieve failed"),
+		},
+		{
+			name:        "UserDisabled",
+			authDecoded: auth,
+			errDecode:   nil,
+			user: usertbl.User{IsDisabled: true, Sessions: []usertbl.Session{
+				{ID: "sess1", ExpiresAt: future},
+			}},
+			errRetrieve: nil,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "SessionRevoked",
+			authDecoded: auth,
+			errDecode:   nil,
+			user: usertbl.User{Sessions: []usertbl.Session{
+				{ID: "sess2", ExpiresAt: future},
+			}},
+			errRetrieve: nil,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "SessionExpired",
+			authDecoded: auth,
+			errDecode:   nil,
+			user: usertbl.User{Sessions: []usertbl.Session{
+				{ID: "sess1", ExpiresAt: clk.Time.Unix()},
+			}},
+			errRetrieve: nil,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "OK",
+			authDecoded: auth,
+			errDecode:   nil,
+			user: usertbl.User{Sessions: []usertbl.Session{
+				{ID: "sess1", ExpiresAt: future},
+			}},
+			errRetrieve: nil,
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecode
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+
+			got, err := sut.Decode(
+				context.Background(), http.Cookie{Value: "nonempty"},
+			)
+
+			if c.wantErr == nil {
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, got, c.authDecoded)
+			} else {
+				assert.Equal(t.Error, err.Error(), c.wantErr.Error())
+			}
+		})
+	}
+}
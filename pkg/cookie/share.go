@@ -0,0 +1,73 @@
+package cookie
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// shareType is the type claim of share tokens, which keeps the other tokens
+// signed with the same key from being accepted as one.
+const shareType = "share"
+
+// Share defines the body of a Share token, which grants read-only access to a
+// single board without logging in.
+type Share struct {
+	TeamID  string
+	BoardID string
+
+	// Nonce must match the share nonce of the board for the token to be
+	// valid, so that the token can be revoked by changing or clearing it.
+	Nonce string
+}
+
+// NewShare creates and returns a new Share.
+func NewShare(teamID, boardID, nonce string) Share {
+	return Share{TeamID: teamID, BoardID: boardID, Nonce: nonce}
+}
+
+// ShareEncoder defines a type that can be used to encode a share token. Share
+// tokens are handed out in links rather than cookies, and they do not expire
+// as they are revoked instead.
+type ShareEncoder struct{ keys Keys }
+
+// NewShareEncoder creates and returns a new ShareEncoder.
+func NewShareEncoder(keys Keys) ShareEncoder { return ShareEncoder{keys: keys} }
+
+// Encode encodes a Share into a JWT string.
+func (e ShareEncoder) Encode(sh Share) (string, error) {
+	return e.keys.sign(jwt.MapClaims{
+		"typ":     shareType,
+		"teamID":  sh.TeamID,
+		"boardID": sh.BoardID,
+		"nonce":   sh.Nonce,
+	})
+}
+
+// ShareDecoder defines a type that can be used to decode a share token.
+type ShareDecoder struct {
+	keys  Keys
+	clock clock.Clock
+}
+
+// NewShareDecoder creates and returns a new ShareDecoder.
+func NewShareDecoder(keys Keys, clock clock.Clock) ShareDecoder {
+	return ShareDecoder{keys: keys, clock: clock}
+}
+
+// Decode validates and decodes a raw JWT string into a Share.
+func (d ShareDecoder) Decode(token string) (Share, error) {
+	claims, err := parseClaims(token, d.keys, d.clock)
+	if err != nil {
+		return Share{}, err
+	}
+
+	typ, _ := claims["typ"].(string)
+	teamID, _ := claims["teamID"].(string)
+	boardID, _ := claims["boardID"].(string)
+	nonce, _ := claims["nonce"].(string)
+	if typ != shareType || teamID == "" || boardID == "" || nonce == "" {
+		return Share{}, ErrInvalid
+	}
+	return NewShare(teamID, boardID, nonce), nil
+}
@@ -0,0 +1,96 @@
+//go:build utest
+
+package cookie
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+func TestShare(t *testing.T) {
+	key := []byte("signkey")
+	keys := NewKeys(key)
+	clk := &clock.Fake{Time: time.Now()}
+	encoder := NewShareEncoder(keys)
+	sut := NewShareDecoder(keys, clk)
+
+	t.Run("EncodeDecode", func(t *testing.T) {
+		tk, err := encoder.Encode(NewShare("teamid", "boardid", "nonce1"))
+		assert.Nil(t.Fatal, err)
+
+		sh, err := sut.Decode(tk)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, sh.TeamID, "teamid")
+		assert.Equal(t.Error, sh.BoardID, "boardid")
+		assert.Equal(t.Error, sh.Nonce, "nonce1")
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		sign := func(claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS256, claims,
+			).SignedString(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tk
+		}
+		valid, err := encoder.Encode(NewShare("teamid", "boardid", "nonce1"))
+		assert.Nil(t.Fatal, err)
+
+		for _, c := range []struct {
+			name    string
+			token   string
+			wantErr error
+		}{
+			{
+				name:    "InvalidSignature",
+				token:   valid[:len(valid)-2] + "xx",
+				wantErr: jwt.ErrSignatureInvalid,
+			},
+			{
+				name:    "TokenMalformed",
+				token:   "notatoken",
+				wantErr: jwt.ErrTokenMalformed,
+			},
+			{
+				// invite tokens are signed with the same key
+				name: "NotShare",
+				token: sign(jwt.MapClaims{
+					"teamID": "teamid", "nonce": "nonce1",
+				}),
+				wantErr: ErrInvalid,
+			},
+			{
+				name: "NoBoardID",
+				token: sign(jwt.MapClaims{
+					"typ": "share", "teamID": "teamid", "nonce": "nonce1",
+				}),
+				wantErr: ErrInvalid,
+			},
+			{
+				name: "Expired",
+				token: sign(jwt.MapClaims{
+					"typ":     "share",
+					"teamID":  "teamid",
+					"boardID": "boardid",
+					"nonce":   "nonce1",
+					"exp":     clk.Time.Add(-time.Minute).Unix(),
+				}),
+				wantErr: jwt.ErrTokenExpired,
+			},
+			{name: "Success", token: valid, wantErr: nil},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := sut.Decode(c.token)
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+			})
+		}
+	})
+}
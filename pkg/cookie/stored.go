@@ -0,0 +1,139 @@
+package cookie
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sesstbl"
+)
+
+// SessionStore holds the accessors that StoredAuthEncoder and
+// StoredAuthDecoder use to store server-side sessions.
+type SessionStore struct {
+	Inserter  db.Inserter[sesstbl.Session]
+	Retriever db.Retriever[sesstbl.Session]
+	Updater   db.Updater[sesstbl.Session]
+}
+
+// StoredAuthEncoder is an Encoder[Auth] that stores the auth in a new
+// server-side session and encodes only the session's opaque token into the
+// cookie, which is why it is used under AuthModeSession.
+type StoredAuthEncoder struct {
+	store SessionStore
+	cfg   Config
+	clock clock.Clock
+}
+
+// NewStoredAuthEncoder creates and returns a new StoredAuthEncoder that starts
+// sessions that are valid for the config's MaxAge since they were last used,
+// up to its SessionLifetime, as told by the given clock.
+func NewStoredAuthEncoder(
+	store SessionStore, cfg Config, clock clock.Clock,
+) StoredAuthEncoder {
+	return StoredAuthEncoder{store: store, cfg: cfg, clock: clock}
+}
+
+// Encode stores the Auth in a new session and encodes its token into a cookie
+// that expires at the end of the session's lifetime.
+func (e StoredAuthEncoder) Encode(
+	ctx context.Context, auth Auth,
+) (http.Cookie, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return http.Cookie{}, err
+	}
+	token := b64.EncodeToString(b)
+
+	now := e.clock.Now()
+	ends := now.Add(e.cfg.SessionLifetime)
+	if err := e.store.Inserter.Insert(ctx, sesstbl.Session{
+		ID:            hashSessionToken(token),
+		Username:      auth.Username,
+		IsAdmin:       auth.IsAdmin,
+		TeamID:        auth.TeamID,
+		IsGuest:       auth.IsGuest,
+		UserSessionID: auth.SessionID,
+		ExpiresAt:     now.Add(e.cfg.MaxAge).Unix(),
+		EndsAt:        ends.Unix(),
+	}); err != nil {
+		return http.Cookie{}, err
+	}
+
+	return e.cfg.newCookie(AuthName, token, now, ends), nil
+}
+
+// StoredAuthDecoder is a Decoder[Auth] that decodes the auth tokens issued by
+// StoredAuthEncoder by retrieving the sessions they carry the tokens of.
+type StoredAuthDecoder struct {
+	jwtDecoder Decoder[Auth]
+	store      SessionStore
+	cfg        Config
+	clock      clock.Clock
+}
+
+// NewStoredAuthDecoder creates and returns a new StoredAuthDecoder. The JWTs
+// issued before the switch to AuthModeSession are decoded by the given
+// decoder so that their users are not logged out until they expire.
+func NewStoredAuthDecoder(
+	jwtDecoder Decoder[Auth], store SessionStore, cfg Config, clock clock.Clock,
+) StoredAuthDecoder {
+	return StoredAuthDecoder{
+		jwtDecoder: jwtDecoder, store: store, cfg: cfg, clock: clock,
+	}
+}
+
+// Decode retrieves the session that the given cookie carries the token of and
+// returns its Auth, extending the session by the config's MaxAge once half of
+// it has passed since it was last extended.
+func (d StoredAuthDecoder) Decode(
+	ctx context.Context, ck http.Cookie,
+) (Auth, error) {
+	if ck.Value == "" {
+		return Auth{}, ErrInvalid
+	}
+	if strings.Contains(ck.Value, ".") {
+		return d.jwtDecoder.Decode(ctx, ck)
+	}
+
+	s, err := d.store.Retriever.Retrieve(ctx, hashSessionToken(ck.Value))
+	if errors.Is(err, db.ErrNoItem) {
+		return Auth{}, ErrInvalid
+	} else if err != nil {
+		return Auth{}, err
+	}
+	now := d.clock.Now()
+	if s.ExpiresAt <= now.Unix() || s.EndsAt <= now.Unix() {
+		return Auth{}, ErrInvalid
+	}
+
+	// extend the session - the session is still valid until it expires if
+	// this fails, so the request is let through and the next one retries
+	if time.Unix(s.ExpiresAt, 0).Sub(now) < d.cfg.MaxAge/2 {
+		s.ExpiresAt = min(now.Add(d.cfg.MaxAge).Unix(), s.EndsAt)
+		if err = d.store.Updater.Update(
+			ctx, s,
+		); errors.Is(err, db.ErrNoItem) {
+			return Auth{}, ErrInvalid
+		}
+	}
+
+	auth := NewAuth(s.Username, s.IsAdmin, s.TeamID)
+	auth.SessionID = s.UserSessionID
+	auth.IsGuest = s.IsGuest
+	return auth, nil
+}
+
+// hashSessionToken returns the hash of the given session token that its
+// session is stored under, so that the tokens cannot be read from the table.
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+//go:build utest
+
+package cookie
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sesstbl"
+)
+
+// ctxInserter is a db.Inserter that fails with the error of the context it is
+// called with, if any.
+type ctxInserter struct{}
+
+// Insert returns the error of the given context.
+func (ctxInserter) Insert(ctx context.Context, _ sesstbl.Session) error {
+	return ctx.Err()
+}
+
+// TestStoredAuthEncoder tests the Encode method of StoredAuthEncoder to assert
+// that it stores the auth in a session that the cookie only carries the token
+// of, within the context it is called with.
+func TestStoredAuthEncoder(t *testing.T) {
+	inserter := &db.FakeInserter[sesstbl.Session]{}
+	clk := &clock.Fake{Time: time.Unix(1700000000, 0)}
+	cfg := DefaultConfig()
+	sut := NewStoredAuthEncoder(SessionStore{Inserter: inserter}, cfg, clk)
+
+	auth := NewAuth("bob123", true, "team1")
+	auth.SessionID = "sess1"
+
+	t.Run("Err", func(t *testing.T) {
+		wantErr := errors.New("insert failed")
+		inserter.Err = wantErr
+
+		_, err := sut.Encode(context.Background(), auth)
+
+		assert.ErrIs(t.Error, err, wantErr)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewStoredAuthEncoder(
+			SessionStore{Inserter: ctxInserter{}}, cfg, clk,
+		).Encode(ctx, auth)
+
+		assert.ErrIs(t.Error, err, context.Canceled)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		inserter.Err = nil
+
+		ck, err := sut.Encode(context.Background(), auth)
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, ck.Name, AuthName)
+		assert.Equal(t.Error, len(ck.Value), 43)
+		assert.Equal(t.Error,
+			ck.Expires, clk.Time.Add(cfg.SessionLifetime).UTC(),
+		)
+		assert.Equal(t.Error, inserter.Inserted, sesstbl.Session{
+			ID:            hashSessionToken(ck.Value),
+			Username:      "bob123",
+			IsAdmin:       true,
+			TeamID:        "team1",
+			UserSessionID: "sess1",
+			ExpiresAt:     clk.Time.Add(cfg.MaxAge).Unix(),
+			EndsAt:        clk.Time.Add(cfg.SessionLifetime).Unix(),
+		})
+	})
+}
+
+// TestStoredAuthDecoder tests the Decode method of StoredAuthDecoder to assert
+// that it behaves correctly in all possible scenarios.
+func TestStoredAuthDecoder(t *testing.T) {
+	jwtDecoder := &FakeDecoder[Auth]{}
+	retriever := &db.FakeRetriever[sesstbl.Session]{}
+	updater := &db.FakeUpdater[sesstbl.Session]{}
+	clk := &clock.Fake{Time: time.Unix(1700000000, 0)}
+	cfg := DefaultConfig()
+	sut := NewStoredAuthDecoder(jwtDecoder, SessionStore{
+		Retriever: retriever, Updater: updater,
+	}, cfg, clk)
+
+	errA := errors.New("retrieve failed")
+	var (
+		now = clk.Time.Unix()
+		ses = sesstbl.Session{
+			ID:            hashSessionToken("token"),
+			Username:      "bob123",
+			IsAdmin:       true,
+			TeamID:        "team1",
+			IsGuest:       true,
+			UserSessionID: "sess1",
+			ExpiresAt:     now + 3000,
+			EndsAt:        now + 6000,
+		}
+		auth = Auth{
+			Username:  "bob123",
+			IsAdmin:   true,
+			TeamID:    "team1",
+			SessionID: "sess1",
+			IsGuest:   true,
+		}
+
+		// with returns the session with the given expiry times
+		with = func(expiresAt, endsAt int64) sesstbl.Session {
+			s := ses
+			s.ExpiresAt, s.EndsAt = expiresAt, endsAt
+			return s
+		}
+	)
+
+	for _, c := range []struct {
+		name        string
+		token       string
+		jwtAuth     Auth
+		session     sesstbl.Session
+		errRetrieve error
+		errUpdate   error
+		wantAuth    Auth
+		wantErr     error
+		wantUpdated sesstbl.Session
+	}{
+		{
+			name:     "Empty",
+			token:    "",
+			wantAuth: Auth{},
+			wantErr:  ErrInvalid,
+		},
+		{
+			name:     "JWT",
+			token:    "header.claims.signature",
+			jwtAuth:  NewAuth("jo", false, "team2"),
+			wantAuth: NewAuth("jo", false, "team2"),
+			wantErr:  nil,
+		},
+		{
+			name:        "NotFound",
+			token:       "token",
+			errRetrieve: db.ErrNoItem,
+			wantAuth:    Auth{},
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "ErrRetrieve",
+			token:       "token",
+			errRetrieve: errA,
+			wantAuth:    Auth{},
+			wantErr:     errA,
+		},
+		{
+			name:     "Expired",
+			token:    "token",
+			session:  with(now, now+6000),
+			wantAuth: Auth{},
+			wantErr:  ErrInvalid,
+		},
+		{
+			name:     "Ended",
+			token:    "token",
+			session:  with(now+3000, now),
+			wantAuth: Auth{},
+			wantErr:  ErrInvalid,
+		},
+		{
+			name:     "NotExtended",
+			token:    "token",
+			session:  ses,
+			wantAuth: auth,
+			wantErr:  nil,
+		},
+		{
+			name:        "Extended",
+			token:       "token",
+			session:     with(now+60, now+6000),
+			wantAuth:    auth,
+			wantErr:     nil,
+			wantUpdated: with(now+3600, now+6000),
+		},
+		{
+			name:        "ExtendedToEnd",
+			token:       "token",
+			session:     with(now+60, now+600),
+			wantAuth:    auth,
+			wantErr:     nil,
+			wantUpdated: with(now+600, now+600),
+		},
+		{
+			name:        "DeletedWhileExtending",
+			token:       "token",
+			session:     with(now+60, now+6000),
+			errUpdate:   db.ErrNoItem,
+			wantAuth:    Auth{},
+			wantErr:     ErrInvalid,
+			wantUpdated: with(now+3600, now+6000),
+		},
+		{
+			name:        "ErrExtend",
+			token:       "token",
+			session:     with(now+60, now+6000),
+			errUpdate:   errors.New("update failed"),
+			wantAuth:    auth,
+			wantErr:     nil,
+			wantUpdated: with(now+3600, now+6000),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			jwtDecoder.Res = c.jwtAuth
+			retriever.Res = c.session
+			retriever.Err = c.errRetrieve
+			retriever.Key = ""
+			updater.Err = c.errUpdate
+			updater.Updated = sesstbl.Session{}
+
+			got, err := sut.Decode(
+				context.Background(), http.Cookie{Value: c.token},
+			)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			assert.Equal(t.Error, got, c.wantAuth)
+			assert.Equal(t.Error, updater.Updated, c.wantUpdated)
+			if c.session.ID != "" {
+				assert.Equal(t.Error, retriever.Key, ses.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package audittbl contains code to interact with the audit table in DynamoDB,
+// which is an append-only record of the privileged actions taken by the admins
+// of each team.
+package audittbl
+
+import "time"
+
+// tableName is the name of the environment variable to retrieve the audit
+// table's name from.
+const tableName = "AUDIT_TABLE_NAME"
+
+// Action is a privileged action that is recorded in the audit table.
+type Action string
+
+// The privileged actions recorded in the audit table.
+const (
+	// ActionDeleteBoard is the deletion of a board, which is targeted by its
+	// ID.
+	ActionDeleteBoard Action = "board.delete"
+
+	// ActionInvite is the issuing of an invite, which is targeted by the email
+	// address it was sent to. It replaces any earlier invite to the same
+	// address.
+	ActionInvite Action = "invite.create"
+)
+
+// Entry defines the audit entry entity, which records a single privileged
+// action taken within a team.
+type Entry struct {
+	TeamID string // hash key
+	At     int64  // range key - Unix time of the action in nanoseconds
+	Action Action
+	Actor  string // username of the admin who took the action
+	Target string
+	IP     string
+}
+
+// NewEntry creates and returns a new Entry for an action taken now.
+func NewEntry(
+	teamID string, action Action, actor string, target string, ip string,
+) Entry {
+	return Entry{
+		TeamID: teamID,
+		At:     time.Now().UnixNano(),
+		Action: action,
+		Actor:  actor,
+		Target: target,
+		IP:     ip,
+	}
+}
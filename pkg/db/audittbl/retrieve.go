@@ -0,0 +1,66 @@
+package audittbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve all entries for a team from the audit
+// table.
+type Retriever struct{ queryer db.DynamoQueryer }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(queryer db.DynamoQueryer) Retriever {
+	return Retriever{queryer: queryer}
+}
+
+// Retrieve retrieves all entries for a team from the audit table, newest
+// first.
+func (r Retriever) Retrieve(
+	ctx context.Context, teamID string,
+) ([]Entry, error) {
+	keyCond := expression.Key("TeamID").Equal(expression.Value(teamID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(false),
+	}
+
+	// follow LastEvaluatedKey as each page is capped at 1 MB
+	var entries []Entry
+	for {
+		out, err := r.queryer.Query(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Entry
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return entries, nil
+		}
+		next := *in
+		next.ExclusiveStartKey = out.LastEvaluatedKey
+		in = &next
+	}
+}
@@ -0,0 +1,119 @@
+//go:build utest
+
+package audittbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetriever(queryer)
+
+	errA := errors.New("failed")
+	entryA := Entry{
+		TeamID: "577965d9-c7ba-4a18-ae7b-47d879b12879",
+		At:     1700000000000000000,
+		Action: ActionDeleteBoard,
+		Actor:  "bob123",
+		Target: "8c5088eb-e86f-4371-86d0-da186dab78a7",
+		IP:     "203.0.113.7",
+	}
+
+	for _, c := range []struct {
+		name        string
+		dqOut       *dynamodb.QueryOutput
+		dqErr       error
+		wantEntries []Entry
+		wantErr     error
+	}{
+		{
+			name:        "Err",
+			dqOut:       nil,
+			dqErr:       errA,
+			wantEntries: []Entry{},
+			wantErr:     errA,
+		},
+		{
+			name: "OK",
+			dqOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{{
+					"TeamID": &types.AttributeValueMemberS{
+						Value: entryA.TeamID,
+					},
+					"At": &types.AttributeValueMemberN{
+						Value: strconv.FormatInt(entryA.At, 10),
+					},
+					"Action": &types.AttributeValueMemberS{
+						Value: string(entryA.Action),
+					},
+					"Actor": &types.AttributeValueMemberS{
+						Value: entryA.Actor,
+					},
+					"Target": &types.AttributeValueMemberS{
+						Value: entryA.Target,
+					},
+					"IP": &types.AttributeValueMemberS{Value: entryA.IP},
+				}},
+			},
+			dqErr:       nil,
+			wantEntries: []Entry{entryA},
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.dqOut
+			queryer.Err = c.dqErr
+
+			entries, err := sut.Retrieve(context.Background(), "")
+			assert.Equal(t.Fatal, err, c.wantErr)
+
+			assert.Equal(t.Fatal, len(entries), len(c.wantEntries))
+			for i, we := range c.wantEntries {
+				e := entries[i]
+
+				assert.Equal(t.Error, e, we)
+			}
+		})
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		entryItem := func(at int64) map[string]types.AttributeValue {
+			return map[string]types.AttributeValue{
+				"At": &types.AttributeValueMemberN{
+					Value: strconv.FormatInt(at, 10),
+				},
+			}
+		}
+		queryer.Err = nil
+		queryer.Out = nil
+		queryer.Pages = []*dynamodb.QueryOutput{
+			{
+				Items: []map[string]types.AttributeValue{
+					entryItem(3), entryItem(2),
+				},
+				LastEvaluatedKey: entryItem(2),
+			},
+			{
+				Items: []map[string]types.AttributeValue{entryItem(1)},
+			},
+		}
+
+		entries, err := sut.Retrieve(context.Background(), "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(entries), 3)
+		for i, at := range []int64{3, 2, 1} {
+			assert.Equal(t.Error, entries[i].At, at)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+// Package avatartbl contains code to interact with the avatar table in
+// DynamoDB, which stores the profile pictures users uploaded. They are kept
+// apart from the user table since user items are read on every authenticated
+// request.
+package avatartbl
+
+// tableName is the name of the environment variable to retrieve the avatar
+// table's name from.
+const tableName = "AVATAR_TABLE_NAME"
+
+// Avatar defines the avatar entity.
+type Avatar struct {
+	Username string // hash key
+	Image    []byte // PNG-encoded
+}
@@ -0,0 +1,35 @@
+package avatartbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Inserter can be used to insert an avatar into the avatar table.
+type Inserter struct{ iput db.DynamoItemPutter }
+
+// NewInserter creates and returns a new Inserter.
+func NewInserter(iput db.DynamoItemPutter) Inserter {
+	return Inserter{iput: iput}
+}
+
+// Insert inserts an avatar into the avatar table, replacing the user's
+// existing avatar if they have one.
+func (i Inserter) Insert(ctx context.Context, avatar Avatar) error {
+	item, err := attributevalue.MarshalMap(avatar)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Item:      item,
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+package avatartbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by username an avatar from the avatar
+// table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by username an avatar from the avatar table. It returns
+// db.ErrNoItem if the user has not uploaded one.
+func (r Retriever) Retrieve(
+	ctx context.Context, username string,
+) (Avatar, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+		},
+	})
+	if err != nil {
+		return Avatar{}, err
+	}
+	if out.Item == nil {
+		return Avatar{}, db.ErrNoItem
+	}
+
+	var avatar Avatar
+	if err = attributevalue.UnmarshalMap(out.Item, &avatar); err != nil {
+		return Avatar{}, err
+	}
+	return avatar, nil
+}
@@ -0,0 +1,72 @@
+//go:build utest
+
+package avatartbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name       string
+		igOut      *dynamodb.GetItemOutput
+		igErr      error
+		wantAvatar Avatar
+		wantErr    error
+	}{
+		{
+			name:       "Err",
+			igOut:      nil,
+			igErr:      errA,
+			wantAvatar: Avatar{},
+			wantErr:    errA,
+		},
+		{
+			name:       "NoItem",
+			igOut:      &dynamodb.GetItemOutput{Item: nil},
+			igErr:      nil,
+			wantAvatar: Avatar{},
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{Value: "bob123"},
+					"Image": &types.AttributeValueMemberB{
+						Value: []byte("png"),
+					},
+				},
+			},
+			igErr:      nil,
+			wantAvatar: Avatar{Username: "bob123", Image: []byte("png")},
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			avatar, err := sut.Retrieve(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, avatar.Username, c.wantAvatar.Username)
+			assert.Equal(t.Error,
+				string(avatar.Image), string(c.wantAvatar.Image),
+			)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package breaker
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever is a db.Retriever that calls the retriever it wraps unless its
+// breaker is open, in which case it returns db.ErrUnavailable.
+type Retriever[T any] struct {
+	next    db.Retriever[T]
+	breaker *Breaker
+}
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever[T any](next db.Retriever[T], breaker *Breaker) Retriever[T] {
+	return Retriever[T]{next: next, breaker: breaker}
+}
+
+// Retrieve retrieves the item with the given key using the wrapped retriever.
+func (r Retriever[T]) Retrieve(ctx context.Context, key string) (T, error) {
+	var item T
+	err := r.breaker.Do(func() (err error) {
+		item, err = r.next.Retrieve(ctx, key)
+		return err
+	})
+	return item, err
+}
+
+// RetrieverDualKey is a db.RetrieverDualKey that calls the retriever it wraps
+// unless its breaker is open, in which case it returns db.ErrUnavailable.
+type RetrieverDualKey[T any] struct {
+	next    db.RetrieverDualKey[T]
+	breaker *Breaker
+}
+
+// NewRetrieverDualKey creates and returns a new RetrieverDualKey.
+func NewRetrieverDualKey[T any](
+	next db.RetrieverDualKey[T], breaker *Breaker,
+) RetrieverDualKey[T] {
+	return RetrieverDualKey[T]{next: next, breaker: breaker}
+}
+
+// Retrieve retrieves the item with the given keys using the wrapped retriever.
+func (r RetrieverDualKey[T]) Retrieve(
+	ctx context.Context, key1, key2 string,
+) (T, error) {
+	var item T
+	err := r.breaker.Do(func() (err error) {
+		item, err = r.next.Retrieve(ctx, key1, key2)
+		return err
+	})
+	return item, err
+}
+
+// PageRetriever is a db.PageRetriever that calls the retriever it wraps unless
+// its breaker is open, in which case it returns db.ErrUnavailable.
+type PageRetriever[T any] struct {
+	next    db.PageRetriever[T]
+	breaker *Breaker
+}
+
+// NewPageRetriever creates and returns a new PageRetriever.
+func NewPageRetriever[T any](
+	next db.PageRetriever[T], breaker *Breaker,
+) PageRetriever[T] {
+	return PageRetriever[T]{next: next, breaker: breaker}
+}
+
+// RetrievePage retrieves a page of items using the wrapped retriever.
+func (r PageRetriever[T]) RetrievePage(
+	ctx context.Context, key string, limit int, cursor string,
+) (T, string, error) {
+	var (
+		page T
+		next string
+	)
+	err := r.breaker.Do(func() (err error) {
+		page, next, err = r.next.RetrievePage(ctx, key, limit, cursor)
+		return err
+	})
+	return page, next, err
+}
+
+// Lister is a db.Lister that calls the lister it wraps unless its breaker is
+// open, in which case it returns db.ErrUnavailable.
+type Lister[T any] struct {
+	next    db.Lister[T]
+	breaker *Breaker
+}
+
+// NewLister creates and returns a new Lister.
+func NewLister[T any](next db.Lister[T], breaker *Breaker) Lister[T] {
+	return Lister[T]{next: next, breaker: breaker}
+}
+
+// List lists all items using the wrapped lister.
+func (l Lister[T]) List(ctx context.Context) (T, error) {
+	var items T
+	err := l.breaker.Do(func() (err error) {
+		items, err = l.next.List(ctx)
+		return err
+	})
+	return items, err
+}
+
+// Inserter is a db.Inserter that calls the inserter it wraps unless its breaker
+// is open, in which case it returns db.ErrUnavailable.
+type Inserter[T any] struct {
+	next    db.Inserter[T]
+	breaker *Breaker
+}
+
+// NewInserter creates and returns a new Inserter.
+func NewInserter[T any](next db.Inserter[T], breaker *Breaker) Inserter[T] {
+	return Inserter[T]{next: next, breaker: breaker}
+}
+
+// Insert inserts the item using the wrapped inserter.
+func (i Inserter[T]) Insert(ctx context.Context, item T) error {
+	return i.breaker.Do(func() error { return i.next.Insert(ctx, item) })
+}
+
+// InserterDualKey is a db.InserterDualKey that calls the inserter it wraps
+// unless its breaker is open, in which case it returns db.ErrUnavailable.
+type InserterDualKey[T any] struct {
+	next    db.InserterDualKey[T]
+	breaker *Breaker
+}
+
+// NewInserterDualKey creates and returns a new InserterDualKey.
+func NewInserterDualKey[T any](
+	next db.InserterDualKey[T], breaker *Breaker,
+) InserterDualKey[T] {
+	return InserterDualKey[T]{next: next, breaker: breaker}
+}
+
+// Insert inserts the item with the given key using the wrapped inserter.
+func (i InserterDualKey[T]) Insert(
+	ctx context.Context, key string, item T,
+) error {
+	return i.breaker.Do(func() error {
+		return i.next.Insert(ctx, key, item)
+	})
+}
+
+// Updater is a db.Updater that calls the updater it wraps unless its breaker is
+// open, in which case it returns db.ErrUnavailable.
+type Updater[T any] struct {
+	next    db.Updater[T]
+	breaker *Breaker
+}
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater[T any](next db.Updater[T], breaker *Breaker) Updater[T] {
+	return Updater[T]{next: next, breaker: breaker}
+}
+
+// Update updates the item using the wrapped updater.
+func (u Updater[T]) Update(ctx context.Context, item T) error {
+	return u.breaker.Do(func() error { return u.next.Update(ctx, item) })
+}
+
+// UpdaterDualKey is a db.UpdaterDualKey that calls the updater it wraps unless
+// its breaker is open, in which case it returns db.ErrUnavailable.
+type UpdaterDualKey[T any] struct {
+	next    db.UpdaterDualKey[T]
+	breaker *Breaker
+}
+
+// NewUpdaterDualKey creates and returns a new UpdaterDualKey.
+func NewUpdaterDualKey[T any](
+	next db.UpdaterDualKey[T], breaker *Breaker,
+) UpdaterDualKey[T] {
+	return UpdaterDualKey[T]{next: next, breaker: breaker}
+}
+
+// Update updates the item with the given key using the wrapped updater.
+func (u UpdaterDualKey[T]) Update(
+	ctx context.Context, key string, item T,
+) error {
+	return u.breaker.Do(func() error {
+		return u.next.Update(ctx, key, item)
+	})
+}
+
+// Deleter is a db.Deleter that calls the deleter it wraps unless its breaker is
+// open, in which case it returns db.ErrUnavailable.
+type Deleter struct {
+	next    db.Deleter
+	breaker *Breaker
+}
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(next db.Deleter, breaker *Breaker) Deleter {
+	return Deleter{next: next, breaker: breaker}
+}
+
+// Delete deletes the item with the given key using the wrapped deleter.
+func (d Deleter) Delete(ctx context.Context, key string) error {
+	return d.breaker.Do(func() error { return d.next.Delete(ctx, key) })
+}
+
+// DeleterDualKey is a db.DeleterDualKey that calls the deleter it wraps unless
+// its breaker is open, in which case it returns db.ErrUnavailable.
+type DeleterDualKey struct {
+	next    db.DeleterDualKey
+	breaker *Breaker
+}
+
+// NewDeleterDualKey creates and returns a new DeleterDualKey.
+func NewDeleterDualKey(
+	next db.DeleterDualKey, breaker *Breaker,
+) DeleterDualKey {
+	return DeleterDualKey{next: next, breaker: breaker}
+}
+
+// Delete deletes the item with the given keys using the wrapped deleter.
+func (d DeleterDualKey) Delete(ctx context.Context, key1, key2 string) error {
+	return d.breaker.Do(func() error {
+		return d.next.Delete(ctx, key1, key2)
+	})
+}
@@ -0,0 +1,158 @@
+// Package breaker contains circuit breaker decorators for the pkg/db
+// interfaces. The decorators of a database share a Breaker that trips after a
+// number of consecutive failed calls, after which calls fail fast with
+// db.ErrUnavailable until the database is given time to recover.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Names of the environment variables to load the breaker settings from. Each
+// is optional and falls back to the value in the default breaker when unset.
+const (
+	EnvThreshold = "DB_BREAKER_THRESHOLD"
+	EnvCooldown  = "DB_BREAKER_COOLDOWN"
+)
+
+// The settings used unless configured otherwise.
+const (
+	DefaultThreshold = 5
+	DefaultCooldown  = 30 * time.Second
+)
+
+// Breaker is a circuit breaker. It is closed while calls succeed, opens once
+// threshold calls in a row have failed, and lets a single trial call through
+// once the cooldown has passed - closing again if it succeeds and reopening
+// otherwise.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	trialing bool
+}
+
+// New creates and returns a new Breaker that times its cooldown by the given
+// clock.
+func New(threshold int, cooldown time.Duration, clock clock.Clock) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown, clock: clock}
+}
+
+// FromEnv returns a new Breaker with the default settings replaced by the
+// values of their environment variables if set.
+func FromEnv(clock clock.Clock) (*Breaker, error) {
+	threshold, cooldown := DefaultThreshold, DefaultCooldown
+	if s := os.Getenv(EnvThreshold); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf(
+				"%s must be a positive integer, got %q", EnvThreshold, s,
+			)
+		}
+		threshold = n
+	}
+	if s := os.Getenv(EnvCooldown); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf(
+				"%s must be a positive duration, got %q", EnvCooldown, s,
+			)
+		}
+		cooldown = d
+	}
+	return New(threshold, cooldown, clock), nil
+}
+
+// Do calls fn unless the breaker is open, in which case it returns
+// db.ErrUnavailable, and records whether the call failed.
+func (b *Breaker) Do(fn func() error) error {
+	if !b.allow() {
+		return db.ErrUnavailable
+	}
+	err := fn()
+	b.record(isFailure(err))
+	return err
+}
+
+// RetryAfter returns how long is left until the breaker lets a trial call
+// through, and false if calls can be made - either because the breaker is
+// closed or because its cooldown has passed and no trial call is in flight.
+func (b *Breaker) RetryAfter() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return 0, false
+	}
+	left := b.openedAt.Add(b.cooldown).Sub(b.clock.Now())
+	if left <= 0 && !b.trialing {
+		return 0, false
+	}
+	return max(left, 0), true
+}
+
+// allow returns whether a call can be made, marking it as the trial call if
+// the breaker is open and its cooldown has passed.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.trialing || b.clock.Now().Before(b.openedAt.Add(b.cooldown)) {
+		return false
+	}
+	b.trialing = true
+	return true
+}
+
+// record updates the state of the breaker with the outcome of a call.
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasTrial := b.trialing
+	b.trialing = false
+	if !failed {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+	b.failures++
+	if wasTrial || b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+	}
+}
+
+// isFailure returns whether the error means that the database failed to serve
+// a call, as opposed to the call being rejected for the item's state or
+// cancelled by the caller.
+func isFailure(err error) bool {
+	for _, target := range []error{
+		db.ErrNoItem,
+		db.ErrDupKey,
+		db.ErrConflict,
+		db.ErrLimitReached,
+		db.ErrInvalidCursor,
+		context.Canceled,
+	} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	return err != nil
+}
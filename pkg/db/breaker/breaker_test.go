@@ -0,0 +1,144 @@
+//go:build utest
+
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// TestBreaker tests the Do and RetryAfter methods of Breaker to assert that it
+// opens after consecutive failures, lets a single trial call through once the
+// cooldown passes, and closes again once a call succeeds.
+func TestBreaker(t *testing.T) {
+	clk := &clock.Fake{Time: time.Now()}
+	sut := New(2, time.Minute, clk)
+
+	errDown := errors.New("connection refused")
+	var calls int
+	call := func(err error) error {
+		return sut.Do(func() error { calls++; return err })
+	}
+
+	// errors caused by the item's state or the caller do not count
+	for _, err := range []error{
+		db.ErrNoItem, db.ErrConflict, context.Canceled, errDown, nil, errDown,
+	} {
+		_ = call(err)
+	}
+	_, isOpen := sut.RetryAfter()
+	assert.True(t.Fatal, !isOpen)
+
+	// the second failure in a row opens the breaker
+	assert.ErrIs(t.Error, call(errDown), errDown)
+	retryAfter, isOpen := sut.RetryAfter()
+	assert.True(t.Fatal, isOpen)
+	assert.Equal(t.Error, retryAfter, time.Minute)
+
+	// calls fail fast while the breaker is open
+	calls = 0
+	assert.ErrIs(t.Error, call(nil), db.ErrUnavailable)
+	assert.Equal(t.Error, calls, 0)
+
+	// once the cooldown passes, a failed trial call reopens the breaker
+	clk.Advance(time.Minute)
+	_, isOpen = sut.RetryAfter()
+	assert.True(t.Error, !isOpen)
+	assert.ErrIs(t.Error, call(errDown), errDown)
+	assert.Equal(t.Error, calls, 1)
+	retryAfter, isOpen = sut.RetryAfter()
+	assert.True(t.Error, isOpen)
+	assert.Equal(t.Error, retryAfter, time.Minute)
+
+	// only one trial call is let through at a time
+	clk.Advance(time.Minute)
+	err := sut.Do(func() error {
+		assert.ErrIs(t.Error, call(nil), db.ErrUnavailable)
+		_, isOpen := sut.RetryAfter()
+		assert.True(t.Error, isOpen)
+		return nil
+	})
+	assert.Nil(t.Error, err)
+
+	// the successful trial call closes the breaker
+	_, isOpen = sut.RetryAfter()
+	assert.True(t.Error, !isOpen)
+	assert.Nil(t.Error, call(nil))
+}
+
+// TestFromEnv tests the FromEnv function to assert that it overrides the
+// default settings with the ones set in the environment.
+func TestFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name          string
+		threshold     string
+		cooldown      string
+		wantThreshold int
+		wantCooldown  time.Duration
+		wantErrIsNil  bool
+	}{
+		{
+			name:          "Unset",
+			wantThreshold: DefaultThreshold,
+			wantCooldown:  DefaultCooldown,
+			wantErrIsNil:  true,
+		},
+		{
+			name:          "Set",
+			threshold:     "10",
+			cooldown:      "5s",
+			wantThreshold: 10,
+			wantCooldown:  5 * time.Second,
+			wantErrIsNil:  true,
+		},
+		{
+			name:         "InvalidThreshold",
+			threshold:    "-1",
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidCooldown",
+			cooldown:     "10",
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvThreshold, c.threshold)
+			t.Setenv(EnvCooldown, c.cooldown)
+
+			b, err := FromEnv(clock.System{})
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			if err == nil {
+				assert.Equal(t.Error, b.threshold, c.wantThreshold)
+				assert.Equal(t.Error, b.cooldown, c.wantCooldown)
+			}
+		})
+	}
+}
+
+// TestRetriever tests the Retrieve method of Retriever to assert that it does
+// not call the wrapped retriever while the breaker is open.
+func TestRetriever(t *testing.T) {
+	next := &db.FakeRetriever[string]{Res: "item"}
+	b := New(1, time.Minute, clock.System{})
+	sut := NewRetriever[string](next, b)
+
+	item, err := sut.Retrieve(context.Background(), "key")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, item, "item")
+
+	next.Err = errors.New("timeout")
+	_, err = sut.Retrieve(context.Background(), "key")
+	assert.ErrIs(t.Error, err, next.Err)
+
+	next.Err = nil
+	_, err = sut.Retrieve(context.Background(), "key")
+	assert.ErrIs(t.Error, err, db.ErrUnavailable)
+}
@@ -0,0 +1,140 @@
+// Package cache contains in-process caching decorators for the pkg/db
+// interfaces. Retriever caches the results of an underlying db.Retriever in a
+// size-bounded LRU with a TTL, and the writer decorators invalidate its entries
+// whenever the items they write to change.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Stats holds the hit and miss counts of a Retriever.
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Invalidator defines a type that can evict the item with the given key from a
+// cache.
+type Invalidator interface{ Invalidate(string) }
+
+// entry is an item in the LRU list of a Retriever.
+type entry[T any] struct {
+	key     string
+	item    T
+	expires time.Time
+}
+
+// Retriever is a db.Retriever that serves items from an in-process LRU cache,
+// falling back to the db.Retriever it wraps on a miss. Errors, including
+// db.ErrNoItem, are never cached.
+type Retriever[T any] struct {
+	next  db.Retriever[T]
+	ttl   time.Duration
+	size  int
+	clone func(T) T
+	now   func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // front is most recently used
+	entries map[string]*list.Element
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewRetriever creates and returns a new Retriever that caches up to size items
+// retrieved from next for the given TTL. clone is used to copy items in and out
+// of the cache so that callers cannot mutate cached items.
+func NewRetriever[T any](
+	next db.Retriever[T], ttl time.Duration, size int, clone func(T) T,
+) *Retriever[T] {
+	return &Retriever[T]{
+		next:    next,
+		ttl:     ttl,
+		size:    size,
+		clone:   clone,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Retrieve returns the cached item with the given key if there is an unexpired
+// one, and otherwise retrieves it from the wrapped retriever and caches it.
+func (r *Retriever[T]) Retrieve(ctx context.Context, key string) (T, error) {
+	if item, ok := r.get(key); ok {
+		r.hits.Add(1)
+		return item, nil
+	}
+	r.misses.Add(1)
+
+	item, err := r.next.Retrieve(ctx, key)
+	if err != nil {
+		return item, err
+	}
+	r.put(key, item)
+	return item, nil
+}
+
+// Invalidate evicts the item with the given key from the cache.
+func (r *Retriever[T]) Invalidate(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if el, ok := r.entries[key]; ok {
+		r.lru.Remove(el)
+		delete(r.entries, key)
+	}
+}
+
+// Stats returns the hit and miss counts of the cache so far.
+func (r *Retriever[T]) Stats() Stats {
+	return Stats{Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+// get returns a copy of the unexpired cached item with the given key, if any.
+func (r *Retriever[T]) get(key string) (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var zero T
+	el, ok := r.entries[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[T])
+	if !r.now().Before(e.expires) {
+		r.lru.Remove(el)
+		delete(r.entries, key)
+		return zero, false
+	}
+	r.lru.MoveToFront(el)
+	return r.clone(e.item), true
+}
+
+// put caches a copy of the given item, evicting the least recently used item
+// if the cache is full.
+func (r *Retriever[T]) put(key string, item T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := &entry[T]{key: key, item: r.clone(item), expires: r.now().Add(r.ttl)}
+	if el, ok := r.entries[key]; ok {
+		el.Value = e
+		r.lru.MoveToFront(el)
+		return
+	}
+	r.entries[key] = r.lru.PushFront(e)
+	if r.lru.Len() > r.size {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*entry[T]).key)
+	}
+}
@@ -0,0 +1,179 @@
+//go:build utest
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// fakeRetriever is a db.Retriever that counts its calls and returns its key as
+// the only element of the item.
+type fakeRetriever struct {
+	calls int
+	err   error
+}
+
+func (f *fakeRetriever) Retrieve(_ context.Context, key string) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []string{key}, nil
+}
+
+func cloneStrings(s []string) []string { return append([]string(nil), s...) }
+
+// TestRetriever tests the Retrieve, Invalidate, and Stats methods of Retriever
+// to assert that it serves cached items until they expire, are evicted, or are
+// invalidated.
+func TestRetriever(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	setup := func() (*fakeRetriever, *Retriever[[]string]) {
+		next := &fakeRetriever{}
+		sut := NewRetriever[[]string](next, time.Minute, 2, cloneStrings)
+		sut.now = func() time.Time { return now }
+		return next, sut
+	}
+
+	t.Run("Hit", func(t *testing.T) {
+		next, sut := setup()
+
+		_, _ = sut.Retrieve(ctx, "a")
+		item, err := sut.Retrieve(ctx, "a")
+
+		assert.Nil(t.Fatal, err)
+		assert.AllEqual(t.Error, item, []string{"a"})
+		assert.Equal(t.Error, next.calls, 1)
+		assert.Equal(t.Error, sut.Stats(), Stats{Hits: 1, Misses: 1})
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		next, sut := setup()
+
+		_, _ = sut.Retrieve(ctx, "a")
+		sut.now = func() time.Time { return now.Add(time.Minute) }
+		_, err := sut.Retrieve(ctx, "a")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, next.calls, 2)
+		assert.Equal(t.Error, sut.Stats(), Stats{Hits: 0, Misses: 2})
+	})
+
+	t.Run("Evicted", func(t *testing.T) {
+		next, sut := setup()
+
+		_, _ = sut.Retrieve(ctx, "a")
+		_, _ = sut.Retrieve(ctx, "b")
+		_, _ = sut.Retrieve(ctx, "a") // b is now least recently used
+		_, _ = sut.Retrieve(ctx, "c") // evicts b
+		_, _ = sut.Retrieve(ctx, "a")
+		_, _ = sut.Retrieve(ctx, "b")
+
+		assert.Equal(t.Error, next.calls, 4)
+		assert.Equal(t.Error, sut.Stats(), Stats{Hits: 2, Misses: 4})
+	})
+
+	t.Run("Invalidated", func(t *testing.T) {
+		next, sut := setup()
+
+		_, _ = sut.Retrieve(ctx, "a")
+		sut.Invalidate("a")
+		_, _ = sut.Retrieve(ctx, "a")
+
+		assert.Equal(t.Error, next.calls, 2)
+	})
+
+	t.Run("ErrNotCached", func(t *testing.T) {
+		next, sut := setup()
+		next.err = db.ErrNoItem
+
+		_, err := sut.Retrieve(ctx, "a")
+		assert.ErrIs(t.Error, err, db.ErrNoItem)
+		_, err = sut.Retrieve(ctx, "a")
+		assert.ErrIs(t.Error, err, db.ErrNoItem)
+
+		assert.Equal(t.Error, next.calls, 2)
+	})
+
+	t.Run("Cloned", func(t *testing.T) {
+		_, sut := setup()
+
+		item, _ := sut.Retrieve(ctx, "a")
+		item[0] = "mutated"
+		item, _ = sut.Retrieve(ctx, "a")
+
+		assert.AllEqual(t.Error, item, []string{"a"})
+	})
+}
+
+// fakeInvalidator records the keys it is asked to invalidate.
+type fakeInvalidator struct{ keys []string }
+
+func (f *fakeInvalidator) Invalidate(key string) {
+	f.keys = append(f.keys, key)
+}
+
+// TestInvalidators tests the writer decorators to assert that they return the
+// errors of the writers they wrap and invalidate the written key either way.
+func TestInvalidators(t *testing.T) {
+	ctx := context.Background()
+	errA := errors.New("failed")
+
+	for _, c := range []struct {
+		name  string
+		write func(inv Invalidator, err error) error
+	}{
+		{
+			name: "Updater",
+			write: func(inv Invalidator, err error) error {
+				return NewUpdater[string](
+					&db.FakeUpdater[string]{Err: err},
+					inv,
+					func(s string) string { return s },
+				).Update(ctx, "key")
+			},
+		},
+		{
+			name: "InserterDualKey",
+			write: func(inv Invalidator, err error) error {
+				return NewInserterDualKey[string](
+					&db.FakeInserterDualKey[string]{Err: err}, inv,
+				).Insert(ctx, "key", "item")
+			},
+		},
+		{
+			name: "UpdaterDualKey",
+			write: func(inv Invalidator, err error) error {
+				return NewUpdaterDualKey[string](
+					&db.FakeUpdaterDualKey[string]{Err: err}, inv,
+				).Update(ctx, "key", "item")
+			},
+		},
+		{
+			name: "DeleterDualKey",
+			write: func(inv Invalidator, err error) error {
+				return NewDeleterDualKey(
+					&db.FakeDeleterDualKey{Err: err}, inv,
+				).Delete(ctx, "key", "item")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			for _, wantErr := range []error{nil, errA} {
+				inv := &fakeInvalidator{}
+
+				err := c.write(inv, wantErr)
+
+				assert.ErrIs(t.Error, err, wantErr)
+				assert.AllEqual(t.Error, inv.keys, []string{"key"})
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Updater is a db.Updater that invalidates the cached item it updates. The key
+// of the cached item is determined from the updated item using keyOf.
+type Updater[T any] struct {
+	next  db.Updater[T]
+	inv   Invalidator
+	keyOf func(T) string
+}
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater[T any](
+	next db.Updater[T], inv Invalidator, keyOf func(T) string,
+) Updater[T] {
+	return Updater[T]{next: next, inv: inv, keyOf: keyOf}
+}
+
+// Update updates the item using the wrapped updater and invalidates it. The
+// item is invalidated even if the update fails, as a failed update may be
+// caused by the cached item being stale.
+func (u Updater[T]) Update(ctx context.Context, item T) error {
+	defer u.inv.Invalidate(u.keyOf(item))
+	return u.next.Update(ctx, item)
+}
+
+// InserterDualKey is a db.InserterDualKey that invalidates the cached item
+// with the key it inserts into.
+type InserterDualKey[T any] struct {
+	next db.InserterDualKey[T]
+	inv  Invalidator
+}
+
+// NewInserterDualKey creates and returns a new InserterDualKey.
+func NewInserterDualKey[T any](
+	next db.InserterDualKey[T], inv Invalidator,
+) InserterDualKey[T] {
+	return InserterDualKey[T]{next: next, inv: inv}
+}
+
+// Insert inserts the item using the wrapped inserter and invalidates the
+// cached item with the given key.
+func (i InserterDualKey[T]) Insert(
+	ctx context.Context, key string, item T,
+) error {
+	defer i.inv.Invalidate(key)
+	return i.next.Insert(ctx, key, item)
+}
+
+// UpdaterDualKey is a db.UpdaterDualKey that invalidates the cached item with
+// the key it updates.
+type UpdaterDualKey[T any] struct {
+	next db.UpdaterDualKey[T]
+	inv  Invalidator
+}
+
+// NewUpdaterDualKey creates and returns a new UpdaterDualKey.
+func NewUpdaterDualKey[T any](
+	next db.UpdaterDualKey[T], inv Invalidator,
+) UpdaterDualKey[T] {
+	return UpdaterDualKey[T]{next: next, inv: inv}
+}
+
+// Update updates the item using the wrapped updater and invalidates the cached
+// item with the given key.
+func (u UpdaterDualKey[T]) Update(
+	ctx context.Context, key string, item T,
+) error {
+	defer u.inv.Invalidate(key)
+	return u.next.Update(ctx, key, item)
+}
+
+// DeleterDualKey is a db.DeleterDualKey that invalidates the cached item with
+// the key it deletes from.
+type DeleterDualKey struct {
+	next db.DeleterDualKey
+	inv  Invalidator
+}
+
+// NewDeleterDualKey creates and returns a new DeleterDualKey.
+func NewDeleterDualKey(next db.DeleterDualKey, inv Invalidator) DeleterDualKey {
+	return DeleterDualKey{next: next, inv: inv}
+}
+
+// Delete deletes the item using the wrapped deleter and invalidates the cached
+// item with the given key.
+func (d DeleterDualKey) Delete(ctx context.Context, key, id string) error {
+	defer d.inv.Invalidate(key)
+	return d.next.Delete(ctx, key, id)
+}
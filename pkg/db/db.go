@@ -15,8 +15,20 @@ var (
 	// ErrDupKey means that the item already exists in the table.
 	ErrDupKey = errors.New("duplicate key")
 
+	// ErrConflict means that the item was modified by another write since it
+	// was read.
+	ErrConflict = errors.New("conflicting write")
+
 	// ErrTooManyItems means that the limit of items has been reached.
 	ErrLimitReached = errors.New("too many items")
+
+	// ErrInvalidCursor means that the cursor to retrieve a page of items from
+	// could not be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrUnavailable means that the table was not called as it is failing and
+	// is given time to recover.
+	ErrUnavailable = errors.New("database unavailable")
 )
 
 // Retriever defines a type that can retrieve an item from a DynamoDB table.
@@ -24,6 +36,21 @@ type Retriever[T any] interface {
 	Retrieve(context.Context, string) (T, error)
 }
 
+// PageRetriever defines a type that can retrieve a page of up to a given number
+// of items from a DynamoDB table, starting after the given cursor. An empty
+// cursor starts from the first item, and an empty next cursor means that there
+// are no more items.
+type PageRetriever[T any] interface {
+	RetrievePage(
+		ctx context.Context, key string, limit int, cursor string,
+	) (page T, next string, err error)
+}
+
+// Lister defines a type that can list all items in a DynamoDB table.
+type Lister[T any] interface {
+	List(context.Context) (T, error)
+}
+
 // Inserter defines a type that can insert an item into a DynamoDB table.
 type Inserter[T any] interface {
 	Insert(context.Context, T) error
@@ -39,6 +66,12 @@ type Deleter interface {
 	Delete(context.Context, string) error
 }
 
+// RetrieverDualKey defines a type that can retrieve an item from a DynamoDB
+// table using two identifiers.
+type RetrieverDualKey[T any] interface {
+	Retrieve(context.Context, string, string) (T, error)
+}
+
 // InserterDualKey defines a type that can insert an item into a DynamoDB table
 // using an additional identifier separate to the T's ID field.
 type InserterDualKey[T any] interface {
@@ -75,6 +108,14 @@ type DynamoQueryer interface {
 	) (*dynamodb.QueryOutput, error)
 }
 
+// DynamoScanner defines a type that can be used to scan a DynamoDB table. It is
+// used to dependency-inject the DynamoDB client into Listers.
+type DynamoScanner interface {
+	Scan(
+		context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options),
+	) (*dynamodb.ScanOutput, error)
+}
+
 // DynamoItemPutter defines a type that can be used to put an item into a
 // DynamoDB table. It is used to dependency-inject the DynamoDB client into
 // Inserters and Updaters.
@@ -104,6 +145,17 @@ type DynamoTransactWriter interface {
 	) (*dynamodb.TransactWriteItemsOutput, error)
 }
 
+// DynamoBatchWriter defines a type that can be used to write multiple items to
+// DynamoDB tables in a single call that is not atomic. It is used to
+// dependency-inject the DynamoDB client into retry.Backoff.BatchWrite.
+type DynamoBatchWriter interface {
+	BatchWriteItem(
+		context.Context,
+		*dynamodb.BatchWriteItemInput,
+		...func(*dynamodb.Options),
+	) (*dynamodb.BatchWriteItemOutput, error)
+}
+
 // DynamoItemGetter defines a type that can be used to get and put an item
 // from/to a DynamoDB table. It is used to dependency-inject the DynamoDB client
 // into some deleters and putters that operate in an item's internal fields.
@@ -111,3 +163,15 @@ type DynamoItemGetPutter interface {
 	DynamoItemGetter
 	DynamoItemPutter
 }
+
+// DynamoClient defines a type that can make all the calls to DynamoDB that the
+// table accessors make. It is implemented by the DynamoDB client and by the
+// decorators that redirect its calls.
+type DynamoClient interface {
+	DynamoItemGetter
+	DynamoQueryer
+	DynamoScanner
+	DynamoItemPutter
+	DynamoItemDeleter
+	DynamoTransactWriter
+}
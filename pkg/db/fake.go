@@ -12,31 +12,95 @@ import (
 type FakeRetriever[T any] struct {
 	Res T
 	Err error
+	Key string
 }
 
-// Retrieve discards params and returns FakeRetriever.Item and
-// FakeRetriever.Err.
-func (f *FakeRetriever[T]) Retrieve(context.Context, string) (T, error) {
+// Retrieve records the key it was called with in FakeRetriever.Key and
+// returns FakeRetriever.Res and FakeRetriever.Err.
+func (f *FakeRetriever[T]) Retrieve(_ context.Context, key string) (T, error) {
+	f.Key = key
 	return f.Res, f.Err
 }
 
+// FakePageRetriever is a test fake for PageRetriever.
+type FakePageRetriever[T any] struct {
+	Res  T
+	Next string
+	Err  error
+}
+
+// RetrievePage discards params and returns FakePageRetriever.Res,
+// FakePageRetriever.Next and FakePageRetriever.Err.
+func (f *FakePageRetriever[T]) RetrievePage(
+	context.Context, string, int, string,
+) (T, string, error) {
+	return f.Res, f.Next, f.Err
+}
+
+// FakeLister is a test fake for Lister.
+type FakeLister[T any] struct {
+	Res T
+	Err error
+}
+
+// List discards params and returns FakeLister.Res and FakeLister.Err.
+func (f *FakeLister[T]) List(context.Context) (T, error) { return f.Res, f.Err }
+
 // FakeInserter is a test fake for Inserter.
-type FakeInserter[T any] struct{ Err error }
+type FakeInserter[T any] struct {
+	Err error
 
-// Insert discards params and returns FakeInserter.Err.
-func (f *FakeInserter[T]) Insert(context.Context, T) error { return f.Err }
+	// Inserted is set to the item passed to Insert.
+	Inserted T
+}
+
+// Insert records the given item and returns FakeInserter.Err.
+func (f *FakeInserter[T]) Insert(_ context.Context, item T) error {
+	f.Inserted = item
+	return f.Err
+}
 
 // FakeUpdater is a test fake for Updater.
-type FakeUpdater[T any] struct{ Err error }
+type FakeUpdater[T any] struct {
+	Err error
+
+	// Updated is set to the item passed to Update.
+	Updated T
+}
 
-// Update discards params and returns FakeUpdater.Err.
-func (f *FakeUpdater[T]) Update(context.Context, T) error { return f.Err }
+// Update records the given item and returns FakeUpdater.Err.
+func (f *FakeUpdater[T]) Update(_ context.Context, item T) error {
+	f.Updated = item
+	return f.Err
+}
 
 // FakeDeleter is a test fake for Deleter.
-type FakeDeleter struct{ Err error }
+type FakeDeleter struct {
+	Err error
+
+	// Deleted is set to the ID passed to Delete.
+	Deleted string
+}
 
-// Delete discards params and returns FakeDeleter.Err.
-func (f *FakeDeleter) Delete(context.Context, string) error { return f.Err }
+// Delete records the given ID and returns FakeDeleter.Err.
+func (f *FakeDeleter) Delete(_ context.Context, id string) error {
+	f.Deleted = id
+	return f.Err
+}
+
+// FakeRetrieverDualKey is a test fake for RetrieverDualKey.
+type FakeRetrieverDualKey[T any] struct {
+	Res T
+	Err error
+}
+
+// Retrieve discards params and returns FakeRetrieverDualKey.Res and
+// FakeRetrieverDualKey.Err.
+func (f *FakeRetrieverDualKey[T]) Retrieve(
+	context.Context, string, string,
+) (T, error) {
+	return f.Res, f.Err
+}
 
 // FakeInserterDualKey is a test fake for InserterDualKey.
 type FakeInserterDualKey[T any] struct{ Err error }
@@ -47,10 +111,18 @@ func (f *FakeInserterDualKey[T]) Insert(context.Context, string, T) error {
 }
 
 // FakeUpdaterDualKey is a test fake for UpdaterDualKey.
-type FakeUpdaterDualKey[T any] struct{ Err error }
+type FakeUpdaterDualKey[T any] struct {
+	Err error
+
+	// Updated is set to the item passed to Update.
+	Updated T
+}
 
-// Update discards params and returns FakeUpdaterDualKey.Err.
-func (f *FakeUpdaterDualKey[T]) Update(context.Context, string, T) error {
+// Update records the given item and returns FakeUpdaterDualKey.Err.
+func (f *FakeUpdaterDualKey[T]) Update(
+	_ context.Context, _ string, item T,
+) error {
+	f.Updated = item
 	return f.Err
 }
 
@@ -80,16 +152,49 @@ func (f *FakeDynamoItemGetter) GetItem(
 type FakeDynamoQueryer struct {
 	Out *dynamodb.QueryOutput
 	Err error
+
+	// In is set to the input of the last query.
+	In *dynamodb.QueryInput
+
+	// Pages, if not empty, are returned one by one before Out so that
+	// paginated queries can be tested.
+	Pages []*dynamodb.QueryOutput
 }
 
-// Query discards the input parameters and returns Out and Err fields set on
-// FakeDynamoQueryer.
+// Query records the input and returns the next page in Pages if any, otherwise
+// the Out and Err fields set on FakeDynamoQueryer.
 func (f *FakeDynamoQueryer) Query(
-	context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options),
+	_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options),
 ) (*dynamodb.QueryOutput, error) {
+	f.In = in
+	if len(f.Pages) > 0 && f.Err == nil {
+		out := f.Pages[0]
+		f.Pages = f.Pages[1:]
+		return out, nil
+	}
 	return f.Out, f.Err
 }
 
+// FakeDynamoScanner is a test fake for DynamoScanner. It returns the outputs
+// in Outs one by one on each call so that paginated scans can be tested.
+type FakeDynamoScanner struct {
+	Outs []*dynamodb.ScanOutput
+	Err  error
+}
+
+// Scan discards the input parameters and returns the next output in Outs and
+// the Err field set on FakeDynamoScanner.
+func (f *FakeDynamoScanner) Scan(
+	context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	if f.Err != nil || len(f.Outs) == 0 {
+		return nil, f.Err
+	}
+	out := f.Outs[0]
+	f.Outs = f.Outs[1:]
+	return out, nil
+}
+
 // FakeDynamoItemPutter is a test fake for DynamoItemPutter.
 type FakeDynamoItemPutter struct {
 	Out *dynamodb.PutItemOutput
@@ -122,24 +227,62 @@ func (f *FakeDynamoItemDeleter) DeleteItem(
 type FakeDynamoTransactWriter struct {
 	Out *dynamodb.TransactWriteItemsOutput
 	Err error
+
+	// Ins records the inputs of each call to TransactWriteItems.
+	Ins []*dynamodb.TransactWriteItemsInput
 }
 
-// TransactWriteItems discards the input parameters and returns Out and Err
-// fields set on FakeDynamoTransactWriter.
+// TransactWriteItems records the input and returns Out and Err fields set on
+// FakeDynamoTransactWriter.
 func (f *FakeDynamoTransactWriter) TransactWriteItems(
-	context.Context,
-	*dynamodb.TransactWriteItemsInput,
-	...func(*dynamodb.Options),
+	_ context.Context,
+	in *dynamodb.TransactWriteItemsInput,
+	_ ...func(*dynamodb.Options),
 ) (*dynamodb.TransactWriteItemsOutput, error) {
+	f.Ins = append(f.Ins, in)
 	return f.Out, f.Err
 }
 
+// FakeDynamoBatchWriter is a test fake for DynamoBatchWriter. It returns the
+// outputs in Outs one by one on each call so that retries of unprocessed items
+// can be tested.
+type FakeDynamoBatchWriter struct {
+	Outs []*dynamodb.BatchWriteItemOutput
+	Err  error
+
+	// Ins records the inputs of each call to BatchWriteItem.
+	Ins []*dynamodb.BatchWriteItemInput
+}
+
+// BatchWriteItem records the input and returns the next output in Outs, or an
+// empty output once they run out, and the Err field set on
+// FakeDynamoBatchWriter.
+func (f *FakeDynamoBatchWriter) BatchWriteItem(
+	_ context.Context,
+	in *dynamodb.BatchWriteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	f.Ins = append(f.Ins, in)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if len(f.Outs) == 0 {
+		return &dynamodb.BatchWriteItemOutput{}, nil
+	}
+	out := f.Outs[0]
+	f.Outs = f.Outs[1:]
+	return out, nil
+}
+
 // FakeDynamoItemGetPutter is a test fake for DynamoItemGetPutter.
 type FakeDynamoItemGetPutter struct {
 	OutGet *dynamodb.GetItemOutput
 	ErrGet error
 	OutPut *dynamodb.PutItemOutput
 	ErrPut error
+
+	// InPut records the input of the last call to PutItem.
+	InPut *dynamodb.PutItemInput
 }
 
 // GetItem discards the input parameters and returns OutGet and ErrGet fields
@@ -150,10 +293,13 @@ func (f *FakeDynamoItemGetPutter) GetItem(
 	return f.OutGet, f.ErrGet
 }
 
-// PutItem discards the input parameters and returns OutPut and ErrPut fields
-// set on FakeDynamoItemGetPutter.
+// PutItem records the input and returns OutPut and ErrPut fields set on
+// FakeDynamoItemGetPutter.
 func (f *FakeDynamoItemGetPutter) PutItem(
-	context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options),
+	_ context.Context,
+	in *dynamodb.PutItemInput,
+	_ ...func(*dynamodb.Options),
 ) (*dynamodb.PutItemOutput, error) {
+	f.InPut = in
 	return f.OutPut, f.ErrPut
 }
@@ -0,0 +1,92 @@
+// Package histtbl contains code to interact with the history table in
+// DynamoDB, which stores the edits made to tasks so that teams can see who
+// changed a task and how.
+package histtbl
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+)
+
+// tableName is the name of the environment variable to retrieve the history
+// table's name from.
+const tableName = "HISTORY_TABLE_NAME"
+
+// Entry defines the history entry entity, which records a single edit of a
+// task. The entries that record the edits of multiple tasks made at once share
+// their At, so that they can be undone together.
+type Entry struct {
+	TaskID   string // hash key
+	At       int64  // range key - Unix time of the edit in nanoseconds
+	TeamID   string
+	Username string
+	Changes  []Change
+
+	// Undoes is the At of the entries whose edits the edit undoes, and is 0 if
+	// the edit is not an undo.
+	Undoes int64
+}
+
+// NewEntry creates and returns a new Entry for an edit made now.
+func NewEntry(
+	taskID string, teamID string, username string, changes []Change,
+) Entry {
+	return Entry{
+		TaskID:   taskID,
+		At:       time.Now().UnixNano(),
+		TeamID:   teamID,
+		Username: username,
+		Changes:  changes,
+	}
+}
+
+// Change defines a change made to a single field of a task in an edit.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// FieldColumn is the field of the changes that record a task moving between
+// columns. The old value of the change recording the creation of a task is
+// empty.
+const FieldColumn = "column"
+
+// Created returns the changes that record the creation of the given task.
+func Created(task tasktbl.Task) []Change {
+	return []Change{{Field: FieldColumn, New: strconv.Itoa(task.ColNo)}}
+}
+
+// Diff returns the changes made to the column, title, description, subtasks,
+// and sprint of a task going from old to new. Subtasks are compared as a
+// whole, rendered one per line with their done state.
+func Diff(old, new tasktbl.Task) []Change {
+	var changes []Change
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, Change{Field: field, Old: o, New: n})
+		}
+	}
+	add(FieldColumn, strconv.Itoa(old.ColNo), strconv.Itoa(new.ColNo))
+	add("title", old.Title, new.Title)
+	add("description", old.Description, new.Description)
+	add("subtasks", subtasksText(old.Subtasks), subtasksText(new.Subtasks))
+	add("sprint", old.SprintID, new.SprintID)
+	return changes
+}
+
+// subtasksText renders the given subtasks one per line, e.g. "[x] Do it".
+func subtasksText(subtasks []tasktbl.Subtask) string {
+	lines := make([]string, 0, len(subtasks))
+	for _, st := range subtasks {
+		box := "[ ] "
+		if st.IsDone {
+			box = "[x] "
+		}
+		lines = append(lines, box+st.Title)
+	}
+	return strings.Join(lines, "\n")
+}
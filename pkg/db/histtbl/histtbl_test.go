@@ -0,0 +1,104 @@
+//go:build utest
+
+package histtbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+)
+
+func TestDiff(t *testing.T) {
+	old := tasktbl.Task{
+		Title:       "Do something!",
+		Description: "Do it!",
+		Subtasks: []tasktbl.Subtask{
+			{Title: "Do a thing", IsDone: true},
+			{Title: "Do another thing", IsDone: false},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		new         tasktbl.Task
+		wantChanges []Change
+	}{
+		{
+			name:        "NoChanges",
+			new:         old,
+			wantChanges: nil,
+		},
+		{
+			name: "Title",
+			new: tasktbl.Task{
+				Title:       "Do something else!",
+				Description: old.Description,
+				Subtasks:    old.Subtasks,
+			},
+			wantChanges: []Change{
+				{Field: "title", Old: old.Title, New: "Do something else!"},
+			},
+		},
+		{
+			name: "Column",
+			new: tasktbl.Task{
+				ColNo:       2,
+				Title:       old.Title,
+				Description: old.Description,
+				Subtasks:    old.Subtasks,
+			},
+			wantChanges: []Change{
+				{Field: "column", Old: "0", New: "2"},
+			},
+		},
+		{
+			name: "Sprint",
+			new: tasktbl.Task{
+				Title:       old.Title,
+				Description: old.Description,
+				Subtasks:    old.Subtasks,
+				SprintID:    "sprint1",
+			},
+			wantChanges: []Change{
+				{Field: "sprint", Old: "", New: "sprint1"},
+			},
+		},
+		{
+			name: "All",
+			new: tasktbl.Task{
+				ColNo:       3,
+				Title:       "Do something else!",
+				Description: "",
+				Subtasks: []tasktbl.Subtask{
+					{Title: "Do a thing", IsDone: true},
+					{Title: "Do another thing", IsDone: true},
+				},
+			},
+			wantChanges: []Change{
+				{Field: "column", Old: "0", New: "3"},
+				{Field: "title", Old: old.Title, New: "Do something else!"},
+				{Field: "description", Old: old.Description, New: ""},
+				{
+					Field: "subtasks",
+					Old:   "[x] Do a thing\n[ ] Do another thing",
+					New:   "[x] Do a thing\n[x] Do another thing",
+				},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			changes := Diff(old, c.new)
+
+			assert.AllEqual(t.Error, changes, c.wantChanges)
+		})
+	}
+}
+
+func TestCreated(t *testing.T) {
+	changes := Created(tasktbl.Task{ColNo: 1, Title: "Do something!"})
+
+	assert.AllEqual(t.Error, changes, []Change{
+		{Field: "column", Old: "", New: "1"},
+	})
+}
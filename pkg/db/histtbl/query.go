@@ -0,0 +1,40 @@
+package histtbl
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// queryAll runs the given query, following LastEvaluatedKey until all pages
+// are read, and returns the entries from all pages. DynamoDB caps each page
+// at 1 MB, so a single Query call may not return all matching entries.
+func queryAll(
+	ctx context.Context, queryer db.DynamoQueryer, in *dynamodb.QueryInput,
+) ([]Entry, error) {
+	var entries []Entry
+	for {
+		out, err := queryer.Query(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Entry
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return entries, nil
+		}
+		next := *in
+		next.ExclusiveStartKey = out.LastEvaluatedKey
+		in = &next
+	}
+}
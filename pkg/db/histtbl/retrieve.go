@@ -0,0 +1,43 @@
+package histtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve all entries for a task from the history
+// table.
+type Retriever struct{ queryer db.DynamoQueryer }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(queryer db.DynamoQueryer) Retriever {
+	return Retriever{queryer: queryer}
+}
+
+// Retrieve retrieves all entries for a task from the history table, newest
+// first.
+func (r Retriever) Retrieve(
+	ctx context.Context, taskID string,
+) ([]Entry, error) {
+	keyCond := expression.Key("TaskID").Equal(expression.Value(taskID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(false),
+	}
+
+	return queryAll(ctx, r.queryer, in)
+}
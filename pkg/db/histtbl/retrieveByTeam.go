@@ -0,0 +1,50 @@
+package histtbl
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverByTeam can be used to retrieve the entries recorded for a team's
+// tasks since a given time from the history table.
+type RetrieverByTeam struct{ queryer db.DynamoQueryer }
+
+// NewRetrieverByTeam creates and returns a new RetrieverByTeam.
+func NewRetrieverByTeam(queryer db.DynamoQueryer) RetrieverByTeam {
+	return RetrieverByTeam{queryer: queryer}
+}
+
+// Retrieve retrieves the entries recorded for a team's tasks since the start
+// of the given YYYY-MM-DD date in UTC from the history table, oldest first.
+func (r RetrieverByTeam) Retrieve(
+	ctx context.Context, teamID string, since string,
+) ([]Entry, error) {
+	day, err := time.Parse(time.DateOnly, since)
+	if err != nil {
+		return nil, err
+	}
+
+	keyCond := expression.Key("TeamID").Equal(expression.Value(teamID)).
+		And(expression.Key("At").GreaterThanEqual(
+			expression.Value(day.UnixNano()),
+		))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryAll(ctx, r.queryer, &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		IndexName:                 aws.String("TeamID-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+	})
+}
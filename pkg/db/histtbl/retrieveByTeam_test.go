@@ -0,0 +1,86 @@
+//go:build utest
+
+package histtbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByTeam(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByTeam(queryer)
+
+	errA := errors.New("failed")
+	entryItem := func(at int64) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"TaskID": &types.AttributeValueMemberS{
+				Value: "8c5088eb-e86f-4371-86d0-da186dab78a7",
+			},
+			"At": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(at, 10),
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name    string
+		since   string
+		dqOut   *dynamodb.QueryOutput
+		dqErr   error
+		wantAts []int64
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			since:   "2024-01-01",
+			dqOut:   nil,
+			dqErr:   errA,
+			wantAts: []int64{},
+			wantErr: errA,
+		},
+		{
+			name:  "OK",
+			since: "2024-01-01",
+			dqOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					entryItem(1), entryItem(2),
+				},
+			},
+			dqErr:   nil,
+			wantAts: []int64{1, 2},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.dqOut
+			queryer.Err = c.dqErr
+
+			entries, err := sut.Retrieve(
+				context.Background(), "", c.since,
+			)
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+
+			assert.Equal(t.Fatal, len(entries), len(c.wantAts))
+			for i, at := range c.wantAts {
+				assert.Equal(t.Error, entries[i].At, at)
+			}
+		})
+	}
+
+	t.Run("InvalidSince", func(t *testing.T) {
+		_, err := sut.Retrieve(context.Background(), "", "yesterday")
+
+		var errParse *time.ParseError
+		assert.True(t.Error, errors.As(err, &errParse))
+	})
+}
@@ -0,0 +1,137 @@
+//go:build utest
+
+package histtbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetriever(queryer)
+
+	errA := errors.New("failed")
+	entryA := Entry{
+		TaskID:   "8c5088eb-e86f-4371-86d0-da186dab78a7",
+		At:       1700000000000000000,
+		TeamID:   "577965d9-c7ba-4a18-ae7b-47d879b12879",
+		Username: "bob123",
+		Changes: []Change{
+			{Field: "title", Old: "Do something!", New: "Do it now!"},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		dqOut       *dynamodb.QueryOutput
+		dqErr       error
+		wantEntries []Entry
+		wantErr     error
+	}{
+		{
+			name:        "Err",
+			dqOut:       nil,
+			dqErr:       errA,
+			wantEntries: []Entry{},
+			wantErr:     errA,
+		},
+		{
+			name: "OK",
+			dqOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{{
+					"TaskID": &types.AttributeValueMemberS{
+						Value: entryA.TaskID,
+					},
+					"At": &types.AttributeValueMemberN{
+						Value: strconv.FormatInt(entryA.At, 10),
+					},
+					"TeamID": &types.AttributeValueMemberS{
+						Value: entryA.TeamID,
+					},
+					"Username": &types.AttributeValueMemberS{
+						Value: entryA.Username,
+					},
+					"Changes": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberM{
+								Value: map[string]types.AttributeValue{
+									"Field": &types.AttributeValueMemberS{
+										Value: entryA.Changes[0].Field,
+									},
+									"Old": &types.AttributeValueMemberS{
+										Value: entryA.Changes[0].Old,
+									},
+									"New": &types.AttributeValueMemberS{
+										Value: entryA.Changes[0].New,
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+			dqErr:       nil,
+			wantEntries: []Entry{entryA},
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.dqOut
+			queryer.Err = c.dqErr
+
+			entries, err := sut.Retrieve(context.Background(), "")
+			assert.Equal(t.Fatal, err, c.wantErr)
+
+			assert.Equal(t.Fatal, len(entries), len(c.wantEntries))
+			for i, we := range c.wantEntries {
+				e := entries[i]
+
+				assert.Equal(t.Error, e.TaskID, we.TaskID)
+				assert.Equal(t.Error, e.At, we.At)
+				assert.Equal(t.Error, e.TeamID, we.TeamID)
+				assert.Equal(t.Error, e.Username, we.Username)
+				assert.AllEqual(t.Error, e.Changes, we.Changes)
+			}
+		})
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		entryItem := func(at int64) map[string]types.AttributeValue {
+			return map[string]types.AttributeValue{
+				"At": &types.AttributeValueMemberN{
+					Value: strconv.FormatInt(at, 10),
+				},
+			}
+		}
+		queryer.Err = nil
+		queryer.Out = nil
+		queryer.Pages = []*dynamodb.QueryOutput{
+			{
+				Items: []map[string]types.AttributeValue{
+					entryItem(3), entryItem(2),
+				},
+				LastEvaluatedKey: entryItem(2),
+			},
+			{
+				Items: []map[string]types.AttributeValue{entryItem(1)},
+			},
+		}
+
+		entries, err := sut.Retrieve(context.Background(), "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(entries), 3)
+		for i, at := range []int64{3, 2, 1} {
+			assert.Equal(t.Error, entries[i].At, at)
+		}
+	})
+}
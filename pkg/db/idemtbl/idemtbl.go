@@ -0,0 +1,39 @@
+// Package idemtbl contains code to interact with the idempotency table in
+// DynamoDB, which stores the responses to requests made with an
+// Idempotency-Key header so that retries of the same request can be answered
+// without handling it again.
+package idemtbl
+
+import "time"
+
+// tableName is the name of the environment variable to retrieve the
+// idempotency table's name from.
+const tableName = "IDEMPOTENCY_TABLE_NAME"
+
+// Record defines the idempotency record entity. A record with a zero Status
+// belongs to a request that is still being handled.
+type Record struct {
+	ID          string // hash of the idempotency key and the caller
+	Fingerprint string // hash of the request
+	Status      int
+	Header      map[string][]string
+	Body        []byte
+
+	// ExpiresAt is the Unix time at which the record expires. The table's TTL
+	// is configured on this attribute, and expired records that DynamoDB has
+	// not deleted yet are treated as absent.
+	ExpiresAt int64
+}
+
+// NewRecord creates and returns a new in-progress record that expires after
+// the given duration.
+func NewRecord(id, fingerprint string, ttl time.Duration) Record {
+	return Record{
+		ID:          id,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(ttl).Unix(),
+	}
+}
+
+// IsExpired returns whether the record has expired.
+func (r Record) IsExpired() bool { return r.ExpiresAt <= time.Now().Unix() }
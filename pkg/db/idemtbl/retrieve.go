@@ -0,0 +1,47 @@
+package idemtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by ID a record from the idempotency table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by ID a record from the idempotency table. It returns
+// db.ErrNoItem if the record does not exist or has expired.
+func (r Retriever) Retrieve(ctx context.Context, id string) (Record, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	if out.Item == nil {
+		return Record{}, db.ErrNoItem
+	}
+
+	var rec Record
+	if err = attributevalue.UnmarshalMap(out.Item, &rec); err != nil {
+		return Record{}, err
+	}
+	if rec.IsExpired() {
+		return Record{}, db.ErrNoItem
+	}
+	return rec, nil
+}
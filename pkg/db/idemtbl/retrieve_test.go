@@ -0,0 +1,91 @@
+//go:build utest
+
+package idemtbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	errA := errors.New("failed to get item")
+	item := func(expiresAt time.Time) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ID":          &types.AttributeValueMemberS{Value: "id"},
+			"Fingerprint": &types.AttributeValueMemberS{Value: "fp"},
+			"Status":      &types.AttributeValueMemberN{Value: "201"},
+			"Body":        &types.AttributeValueMemberB{Value: []byte("{}")},
+			"ExpiresAt": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(expiresAt.Unix(), 10),
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name    string
+		igOut   *dynamodb.GetItemOutput
+		igErr   error
+		wantRec Record
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			igOut:   nil,
+			igErr:   errA,
+			wantRec: Record{},
+			wantErr: errA,
+		},
+		{
+			name:    "NoItem",
+			igOut:   &dynamodb.GetItemOutput{Item: nil},
+			igErr:   nil,
+			wantRec: Record{},
+			wantErr: db.ErrNoItem,
+		},
+		{
+			name: "Expired",
+			igOut: &dynamodb.GetItemOutput{
+				Item: item(time.Now().Add(-time.Minute)),
+			},
+			igErr:   nil,
+			wantRec: Record{},
+			wantErr: db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: item(time.Now().Add(time.Minute)),
+			},
+			igErr: nil,
+			wantRec: Record{
+				ID: "id", Fingerprint: "fp", Status: 201, Body: []byte("{}"),
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			rec, err := sut.Retrieve(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, rec.ID, c.wantRec.ID)
+			assert.Equal(t.Error, rec.Fingerprint, c.wantRec.Fingerprint)
+			assert.Equal(t.Error, rec.Status, c.wantRec.Status)
+			assert.Equal(t.Error, string(rec.Body), string(c.wantRec.Body))
+		})
+	}
+}
@@ -1,6 +1,6 @@
 //go:build utest
 
-package tasktbl
+package idemtbl
 
 import (
 	"context"
@@ -14,9 +14,9 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 )
 
-func TestMultiUpdater(t *testing.T) {
-	tw := &db.FakeDynamoTransactWriter{}
-	sut := NewMultiUpdater(tw)
+func TestUpdater(t *testing.T) {
+	ip := &db.FakeDynamoItemPutter{}
+	sut := NewUpdater(ip)
 
 	errA := errors.New("failed to put item")
 
@@ -36,9 +36,9 @@ func TestMultiUpdater(t *testing.T) {
 		{name: "OK", ipErr: nil, wantErr: nil},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			tw.Err = c.ipErr
+			ip.Err = c.ipErr
 
-			err := sut.Update(context.Background(), []Task{})
+			err := sut.Update(context.Background(), Record{})
 
 			assert.ErrIs(t.Fatal, err, c.wantErr)
 		})
@@ -0,0 +1,53 @@
+package locktbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Inserter can be used to acquire a lock by inserting it into the lock table.
+type Inserter struct{ iput db.DynamoItemPutter }
+
+// NewInserter creates and returns a new Inserter.
+func NewInserter(iput db.DynamoItemPutter) Inserter {
+	return Inserter{iput: iput}
+}
+
+// Insert inserts a new lock into the lock table. It returns db.ErrDupKey if an
+// unexpired lock with the same ID is already held.
+func (i Inserter) Insert(ctx context.Context, lock Lock) error {
+	item, err := attributevalue.MarshalMap(lock)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(os.Getenv(EnvTableName)),
+		Item:      item,
+		ConditionExpression: aws.String(
+			"attribute_not_exists(ID) OR ExpiresAt <= :now",
+		),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(time.Now().Unix(), 10),
+			},
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrDupKey
+	}
+
+	return err
+}
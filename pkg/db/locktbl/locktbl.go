@@ -0,0 +1,27 @@
+// Package locktbl contains code to interact with the lock table in DynamoDB,
+// which stores leases on work that only one instance of a service should do at
+// a time, such as a run of a scheduled job.
+package locktbl
+
+import "time"
+
+// EnvTableName is the name of the environment variable to retrieve the lock
+// table's name from. The work that needs a lock is not done if it is empty.
+const EnvTableName = "LOCK_TABLE_NAME"
+
+// Lock defines the lock entity. It is held by its owner until it expires.
+type Lock struct {
+	ID    string // name of the work it is held on
+	Owner string // ID of the instance that holds it
+
+	// ExpiresAt is the Unix time at which the lock expires. The table's TTL is
+	// configured on this attribute, and expired locks that DynamoDB has not
+	// deleted yet can be acquired again.
+	ExpiresAt int64
+}
+
+// NewLock creates and returns a new Lock that is held by the given owner for
+// the given duration from the given time.
+func NewLock(id, owner string, now time.Time, ttl time.Duration) Lock {
+	return Lock{ID: id, Owner: owner, ExpiresAt: now.Add(ttl).Unix()}
+}
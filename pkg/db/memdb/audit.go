@@ -0,0 +1,66 @@
+package memdb
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+)
+
+// AuditInserter can be used to insert a new audit entry into the store.
+type AuditInserter struct{ s *Store }
+
+// NewAuditInserter creates and returns a new AuditInserter.
+func NewAuditInserter(s *Store) AuditInserter {
+	return AuditInserter{s: s}
+}
+
+// Insert inserts a new audit entry into the store. It returns db.ErrDupKey if
+// an entry for the same team at the same time already exists.
+func (i AuditInserter) Insert(_ context.Context, entry audittbl.Entry) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	entries := i.s.audit[entry.TeamID]
+	for _, e := range entries {
+		if e.At == entry.At {
+			return db.ErrDupKey
+		}
+	}
+
+	// keep the entries sorted by time, oldest first
+	pos := len(entries)
+	for pos > 0 && entries[pos-1].At > entry.At {
+		pos--
+	}
+	entries = append(entries, audittbl.Entry{})
+	copy(entries[pos+1:], entries[pos:])
+	entries[pos] = entry
+	i.s.audit[entry.TeamID] = entries
+	return nil
+}
+
+// AuditRetriever can be used to retrieve all audit entries for a team from the
+// store.
+type AuditRetriever struct{ s *Store }
+
+// NewAuditRetriever creates and returns a new AuditRetriever.
+func NewAuditRetriever(s *Store) AuditRetriever {
+	return AuditRetriever{s: s}
+}
+
+// Retrieve retrieves all audit entries for a team from the store, newest
+// first.
+func (r AuditRetriever) Retrieve(
+	_ context.Context, teamID string,
+) ([]audittbl.Entry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.audit[teamID]
+	entries := make([]audittbl.Entry, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		entries = append(entries, stored[i])
+	}
+	return entries, nil
+}
@@ -0,0 +1,44 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+)
+
+func TestAuditAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewAuditInserter(s)
+	retriever := NewAuditRetriever(s)
+
+	entries, err := retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(entries), 0)
+
+	for _, e := range []audittbl.Entry{
+		{TeamID: "t1", At: 2, Action: audittbl.ActionDeleteBoard},
+		{TeamID: "t1", At: 1, Action: audittbl.ActionInvite},
+		{TeamID: "t1", At: 3, Action: audittbl.ActionInvite},
+		{TeamID: "t2", At: 1, Action: audittbl.ActionInvite},
+	} {
+		err = inserter.Insert(ctx, e)
+		assert.Nil(t.Fatal, err)
+	}
+	err = inserter.Insert(ctx, audittbl.Entry{TeamID: "t1", At: 2})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	// entries are retrieved newest first and only for the given team
+	entries, err = retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(entries), 3)
+	for i, at := range []int64{3, 2, 1} {
+		assert.Equal(t.Error, entries[i].At, at)
+	}
+	assert.Equal(t.Error, entries[1].Action, audittbl.ActionDeleteBoard)
+}
@@ -0,0 +1,65 @@
+package memdb
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+)
+
+// AvatarInserter can be used to insert an avatar into the store.
+type AvatarInserter struct{ s *Store }
+
+// NewAvatarInserter creates and returns a new AvatarInserter.
+func NewAvatarInserter(s *Store) AvatarInserter { return AvatarInserter{s: s} }
+
+// Insert inserts an avatar into the store, replacing the user's existing
+// avatar if they have one.
+func (i AvatarInserter) Insert(_ context.Context, a avatartbl.Avatar) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	a.Image = append([]byte(nil), a.Image...)
+	i.s.avatars[a.Username] = a
+	return nil
+}
+
+// AvatarRetriever can be used to retrieve an avatar by username from the
+// store.
+type AvatarRetriever struct{ s *Store }
+
+// NewAvatarRetriever creates and returns a new AvatarRetriever.
+func NewAvatarRetriever(s *Store) AvatarRetriever {
+	return AvatarRetriever{s: s}
+}
+
+// Retrieve retrieves an avatar by username from the store.
+func (r AvatarRetriever) Retrieve(
+	_ context.Context, username string,
+) (avatartbl.Avatar, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, ok := r.s.avatars[username]
+	if !ok {
+		return avatartbl.Avatar{}, db.ErrNoItem
+	}
+	a.Image = append([]byte(nil), a.Image...)
+	return a, nil
+}
+
+// AvatarDeleter can be used to delete an avatar by username from the store.
+type AvatarDeleter struct{ s *Store }
+
+// NewAvatarDeleter creates and returns a new AvatarDeleter.
+func NewAvatarDeleter(s *Store) AvatarDeleter { return AvatarDeleter{s: s} }
+
+// Delete deletes an avatar by username from the store. Deleting an avatar
+// that does not exist is not an error.
+func (d AvatarDeleter) Delete(_ context.Context, username string) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	delete(d.s.avatars, username)
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+)
+
+func TestAvatarAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewAvatarInserter(s)
+	retriever := NewAvatarRetriever(s)
+	deleter := NewAvatarDeleter(s)
+
+	_, err := retriever.Retrieve(ctx, "bob123")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = inserter.Insert(ctx, avatartbl.Avatar{
+		Username: "bob123", Image: []byte("old"),
+	})
+	assert.Nil(t.Fatal, err)
+	err = inserter.Insert(ctx, avatartbl.Avatar{
+		Username: "bob123", Image: []byte("new"),
+	})
+	assert.Nil(t.Fatal, err)
+
+	// mutating a retrieved avatar must not change the stored one
+	got, err := retriever.Retrieve(ctx, "bob123")
+	assert.Nil(t.Fatal, err)
+	got.Image[0] = 'x'
+	got, err = retriever.Retrieve(ctx, "bob123")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, string(got.Image), "new")
+
+	err = deleter.Delete(ctx, "bob123")
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "bob123")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = deleter.Delete(ctx, "bob123")
+	assert.Nil(t.Fatal, err)
+}
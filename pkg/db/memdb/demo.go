@@ -0,0 +1,78 @@
+package memdb
+
+import (
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+const (
+	// DemoUsername is the username of the seeded demo team admin.
+	DemoUsername = "demo"
+
+	// DemoPassword is the password of all seeded demo users.
+	DemoPassword = "Demo1234!"
+)
+
+// NewDemoStore creates and returns a new Store seeded with a sample team, its
+// members, boards, and tasks. Log in as DemoUsername with DemoPassword to see
+// the sample data as the team admin.
+func NewDemoStore() (*Store, error) {
+	pwdHash, err := bcrypt.GenerateFromPassword(
+		[]byte(DemoPassword), bcrypt.DefaultCost,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		teamID   = DemoUsername
+		member   = "demomember"
+		boardID1 = "0d1e2a53-9a57-4c4f-8b83-3c4a5b0f6a01"
+		boardID2 = "0d1e2a53-9a57-4c4f-8b83-3c4a5b0f6a02"
+	)
+
+	s := NewStore()
+	s.users[DemoUsername] = usertbl.NewUser(DemoUsername, pwdHash, true, teamID)
+	s.users[member] = usertbl.NewUser(member, pwdHash, false, teamID)
+	s.teams[teamID] = teamtbl.Team{
+		ID:      teamID,
+		Members: []string{DemoUsername, member},
+		Boards: []teamtbl.Board{
+			{ID: boardID1, Name: "Product Launch", Members: []string{member}},
+			{ID: boardID2, Name: "Bug Bash", Members: []string{}},
+		},
+	}
+	for _, t := range []tasktbl.Task{
+		tasktbl.NewTask(
+			teamID, boardID1, 0, "5a3c1e8f-0d3b-4c1e-9f4e-6d2b7a8c9e01",
+			"Write release notes", "Summarise the changes since v1.",
+			0, []tasktbl.Subtask{
+				tasktbl.NewSubtask("Collect merged changes", true),
+				tasktbl.NewSubtask("Draft the announcement", false),
+			},
+		),
+		tasktbl.NewTask(
+			teamID, boardID1, 1, "5a3c1e8f-0d3b-4c1e-9f4e-6d2b7a8c9e02",
+			"Update landing page", "", 0, []tasktbl.Subtask{},
+		),
+		tasktbl.NewTask(
+			teamID, boardID1, 2, "5a3c1e8f-0d3b-4c1e-9f4e-6d2b7a8c9e03",
+			"Load test the API", "Target 500 requests per second.",
+			0, []tasktbl.Subtask{},
+		),
+		tasktbl.NewTask(
+			teamID, boardID1, 3, "5a3c1e8f-0d3b-4c1e-9f4e-6d2b7a8c9e04",
+			"Set up error tracking", "", 0, []tasktbl.Subtask{},
+		),
+		tasktbl.NewTask(
+			teamID, boardID2, 0, "5a3c1e8f-0d3b-4c1e-9f4e-6d2b7a8c9e05",
+			"Triage open issues", "", 0, []tasktbl.Subtask{},
+		),
+	} {
+		s.tasks[t.ID] = t
+	}
+	return s, nil
+}
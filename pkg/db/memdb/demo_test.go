@@ -0,0 +1,23 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestNewDemoStore(t *testing.T) {
+	s, err := NewDemoStore()
+	assert.Nil(t.Fatal, err)
+
+	user, err := NewUserRetriever(s).Retrieve(context.Background(), DemoUsername)
+	assert.Nil(t.Fatal, err)
+	assert.True(t.Error, user.IsAdmin)
+
+	team, err := NewTeamRetriever(s).Retrieve(context.Background(), user.TeamID)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(team.Boards), 2)
+}
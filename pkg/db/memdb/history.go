@@ -0,0 +1,111 @@
+package memdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+)
+
+// HistoryInserter can be used to insert a new task history entry into the
+// store.
+type HistoryInserter struct{ s *Store }
+
+// NewHistoryInserter creates and returns a new HistoryInserter.
+func NewHistoryInserter(s *Store) HistoryInserter {
+	return HistoryInserter{s: s}
+}
+
+// Insert inserts a new task history entry into the store. It returns
+// db.ErrDupKey if an entry for the same task at the same time already exists.
+func (i HistoryInserter) Insert(_ context.Context, entry histtbl.Entry) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	entries := i.s.history[entry.TaskID]
+	for _, e := range entries {
+		if e.At == entry.At {
+			return db.ErrDupKey
+		}
+	}
+
+	// keep the entries sorted by time, oldest first
+	pos := len(entries)
+	for pos > 0 && entries[pos-1].At > entry.At {
+		pos--
+	}
+	entries = append(entries, histtbl.Entry{})
+	copy(entries[pos+1:], entries[pos:])
+	entries[pos] = copyEntry(entry)
+	i.s.history[entry.TaskID] = entries
+	return nil
+}
+
+// HistoryRetriever can be used to retrieve all history entries for a task from
+// the store.
+type HistoryRetriever struct{ s *Store }
+
+// NewHistoryRetriever creates and returns a new HistoryRetriever.
+func NewHistoryRetriever(s *Store) HistoryRetriever {
+	return HistoryRetriever{s: s}
+}
+
+// Retrieve retrieves all history entries for a task from the store, newest
+// first.
+func (r HistoryRetriever) Retrieve(
+	_ context.Context, taskID string,
+) ([]histtbl.Entry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.history[taskID]
+	entries := make([]histtbl.Entry, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		entries = append(entries, copyEntry(stored[i]))
+	}
+	return entries, nil
+}
+
+// HistoryRetrieverByTeam can be used to retrieve the history entries recorded
+// for a team's tasks since a given date from the store.
+type HistoryRetrieverByTeam struct{ s *Store }
+
+// NewHistoryRetrieverByTeam creates and returns a new HistoryRetrieverByTeam.
+func NewHistoryRetrieverByTeam(s *Store) HistoryRetrieverByTeam {
+	return HistoryRetrieverByTeam{s: s}
+}
+
+// Retrieve retrieves the history entries recorded for a team's tasks since the
+// start of the given YYYY-MM-DD date in UTC from the store, oldest first.
+func (r HistoryRetrieverByTeam) Retrieve(
+	_ context.Context, teamID string, since string,
+) ([]histtbl.Entry, error) {
+	day, err := time.Parse(time.DateOnly, since)
+	if err != nil {
+		return nil, err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var entries []histtbl.Entry
+	for _, stored := range r.s.history {
+		for _, e := range stored {
+			if e.TeamID == teamID && e.At >= day.UnixNano() {
+				entries = append(entries, copyEntry(e))
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].At < entries[j].At
+	})
+	return entries, nil
+}
+
+// copyEntry returns a deep copy of the given history entry.
+func copyEntry(e histtbl.Entry) histtbl.Entry {
+	e.Changes = append([]histtbl.Change(nil), e.Changes...)
+	return e
+}
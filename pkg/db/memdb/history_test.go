@@ -0,0 +1,81 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+)
+
+func TestHistoryAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewHistoryInserter(s)
+	retriever := NewHistoryRetriever(s)
+
+	entries, err := retriever.Retrieve(ctx, "k1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(entries), 0)
+
+	for _, e := range []histtbl.Entry{
+		{TaskID: "k1", At: 2, Changes: []histtbl.Change{{Field: "title"}}},
+		{TaskID: "k1", At: 1},
+		{TaskID: "k1", At: 3},
+		{TaskID: "k2", At: 1},
+	} {
+		err = inserter.Insert(ctx, e)
+		assert.Nil(t.Fatal, err)
+	}
+	err = inserter.Insert(ctx, histtbl.Entry{TaskID: "k1", At: 2})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	// entries are retrieved newest first
+	entries, err = retriever.Retrieve(ctx, "k1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(entries), 3)
+	for i, at := range []int64{3, 2, 1} {
+		assert.Equal(t.Error, entries[i].At, at)
+	}
+
+	// mutating a retrieved entry must not change the stored one
+	entries[1].Changes[0].Field = "description"
+	entries, err = retriever.Retrieve(ctx, "k1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, entries[1].Changes[0].Field, "title")
+}
+
+func TestHistoryRetrieverByTeam(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewHistoryInserter(s)
+	sut := NewHistoryRetrieverByTeam(s)
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano()
+	for _, e := range []histtbl.Entry{
+		{TaskID: "k1", TeamID: "t1", At: day + 3},
+		{TaskID: "k2", TeamID: "t1", At: day + 1},
+		{TaskID: "k1", TeamID: "t1", At: day - 1},
+		{TaskID: "k3", TeamID: "t2", At: day + 2},
+	} {
+		err := inserter.Insert(ctx, e)
+		assert.Nil(t.Fatal, err)
+	}
+
+	// only the team's entries since the date are retrieved, oldest first
+	entries, err := sut.Retrieve(ctx, "t1", "2024-01-02")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(entries), 2)
+	for i, at := range []int64{day + 1, day + 3} {
+		assert.Equal(t.Error, entries[i].At, at)
+	}
+
+	_, err = sut.Retrieve(ctx, "t1", "yesterday")
+	var errParse *time.ParseError
+	assert.True(t.Error, errors.As(err, &errParse))
+}
@@ -0,0 +1,97 @@
+package memdb
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+)
+
+// RecordInserter can be used to insert a new idempotency record into the
+// store.
+type RecordInserter struct{ s *Store }
+
+// NewRecordInserter creates and returns a new RecordInserter.
+func NewRecordInserter(s *Store) RecordInserter { return RecordInserter{s: s} }
+
+// Insert inserts a new idempotency record into the store. It returns
+// db.ErrDupKey if an unexpired record with the same ID already exists.
+func (i RecordInserter) Insert(_ context.Context, rec idemtbl.Record) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	if old, ok := i.s.records[rec.ID]; ok && !old.IsExpired() {
+		return db.ErrDupKey
+	}
+	i.s.records[rec.ID] = copyRecord(rec)
+	return nil
+}
+
+// RecordRetriever can be used to retrieve an idempotency record by ID from the
+// store.
+type RecordRetriever struct{ s *Store }
+
+// NewRecordRetriever creates and returns a new RecordRetriever.
+func NewRecordRetriever(s *Store) RecordRetriever {
+	return RecordRetriever{s: s}
+}
+
+// Retrieve retrieves an idempotency record by ID from the store. Expired
+// records are treated as absent.
+func (r RecordRetriever) Retrieve(
+	_ context.Context, id string,
+) (idemtbl.Record, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	rec, ok := r.s.records[id]
+	if !ok || rec.IsExpired() {
+		return idemtbl.Record{}, db.ErrNoItem
+	}
+	return copyRecord(rec), nil
+}
+
+// RecordUpdater can be used to update an idempotency record in the store.
+type RecordUpdater struct{ s *Store }
+
+// NewRecordUpdater creates and returns a new RecordUpdater.
+func NewRecordUpdater(s *Store) RecordUpdater { return RecordUpdater{s: s} }
+
+// Update updates an idempotency record in the store.
+func (u RecordUpdater) Update(_ context.Context, rec idemtbl.Record) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	if _, ok := u.s.records[rec.ID]; !ok {
+		return db.ErrNoItem
+	}
+	u.s.records[rec.ID] = copyRecord(rec)
+	return nil
+}
+
+// RecordDeleter can be used to delete an idempotency record by ID from the
+// store.
+type RecordDeleter struct{ s *Store }
+
+// NewRecordDeleter creates and returns a new RecordDeleter.
+func NewRecordDeleter(s *Store) RecordDeleter { return RecordDeleter{s: s} }
+
+// Delete deletes an idempotency record by ID from the store.
+func (d RecordDeleter) Delete(_ context.Context, id string) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	delete(d.s.records, id)
+	return nil
+}
+
+// copyRecord returns a deep copy of the given idempotency record.
+func copyRecord(r idemtbl.Record) idemtbl.Record {
+	header := make(map[string][]string, len(r.Header))
+	for k, v := range r.Header {
+		header[k] = append([]string(nil), v...)
+	}
+	r.Header = header
+	r.Body = append([]byte(nil), r.Body...)
+	return r
+}
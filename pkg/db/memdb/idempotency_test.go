@@ -0,0 +1,61 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+)
+
+func TestRecordAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewRecordInserter(s)
+	retriever := NewRecordRetriever(s)
+	updater := NewRecordUpdater(s)
+	deleter := NewRecordDeleter(s)
+
+	_, err := retriever.Retrieve(ctx, "r1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = updater.Update(ctx, idemtbl.Record{ID: "r1"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	rec := idemtbl.NewRecord("r1", "fp", time.Hour)
+	err = inserter.Insert(ctx, rec)
+	assert.Nil(t.Fatal, err)
+	err = inserter.Insert(ctx, rec)
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	rec.Status = 201
+	rec.Body = []byte("body")
+	err = updater.Update(ctx, rec)
+	assert.Nil(t.Fatal, err)
+
+	// mutating a retrieved record must not change the stored one
+	got, err := retriever.Retrieve(ctx, "r1")
+	assert.Nil(t.Fatal, err)
+	got.Body[0] = 'x'
+	got, err = retriever.Retrieve(ctx, "r1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, got.Status, 201)
+	assert.Equal(t.Error, string(got.Body), "body")
+
+	err = deleter.Delete(ctx, "r1")
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "r1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	// expired records are absent and can be replaced
+	err = inserter.Insert(ctx, idemtbl.NewRecord("r2", "fp", -time.Second))
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "r2")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = inserter.Insert(ctx, idemtbl.NewRecord("r2", "fp", time.Hour))
+	assert.Nil(t.Fatal, err)
+}
@@ -0,0 +1,56 @@
+// Package memdb contains in-memory implementations of the pkg/db interfaces
+// for the user, team, task, history, trash, audit, avatar, and idempotency
+// tables. It is used for running the services in demo mode without DynamoDB and
+// for exercising real storage logic in tests.
+package memdb
+
+import (
+	"sync"
+
+	"github.com/kxplxn/goteam/pkg/db/audittbl"
+	"github.com/kxplxn/goteam/pkg/db/avatartbl"
+	"github.com/kxplxn/goteam/pkg/db/histtbl"
+	"github.com/kxplxn/goteam/pkg/db/idemtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// Store holds the items of all tables in memory. It is safe for concurrent
+// use, and the accessors in this package copy items in and out of it so that
+// callers cannot mutate stored items by accident.
+type Store struct {
+	mu    sync.Mutex
+	users map[string]usertbl.User // by username
+	teams map[string]teamtbl.Team // by team ID
+	tasks map[string]tasktbl.Task // by task ID
+
+	history map[string][]histtbl.Entry  // by task ID, oldest first
+	trash   map[string]trashtbl.Item    // by team ID and item ID
+	audit   map[string][]audittbl.Entry // by team ID, oldest first
+	avatars map[string]avatartbl.Avatar // by username
+	records map[string]idemtbl.Record   // by record ID
+}
+
+// NewStore creates and returns a new empty Store.
+func NewStore() *Store {
+	return &Store{
+		users: map[string]usertbl.User{},
+		teams: map[string]teamtbl.Team{},
+		tasks: map[string]tasktbl.Task{},
+
+		history: map[string][]histtbl.Entry{},
+		trash:   map[string]trashtbl.Item{},
+		audit:   map[string][]audittbl.Entry{},
+		avatars: map[string]avatartbl.Avatar{},
+		records: map[string]idemtbl.Record{},
+	}
+}
+
+// copyTask returns a deep copy of the given task.
+func copyTask(t tasktbl.Task) tasktbl.Task {
+	t.Subtasks = append([]tasktbl.Subtask(nil), t.Subtasks...)
+	t.BlockedBy = append([]string(nil), t.BlockedBy...)
+	return t
+}
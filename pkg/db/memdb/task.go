@@ -0,0 +1,226 @@
+package memdb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+)
+
+// TaskRetriever can be used to retrieve by team ID and ID a task from the
+// store.
+type TaskRetriever struct{ s *Store }
+
+// NewTaskRetriever creates and returns a new TaskRetriever.
+func NewTaskRetriever(s *Store) TaskRetriever { return TaskRetriever{s: s} }
+
+// Retrieve retrieves by team ID and ID a task from the store.
+func (r TaskRetriever) Retrieve(
+	_ context.Context, teamID, id string,
+) (tasktbl.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.TeamID != teamID {
+		return tasktbl.Task{}, db.ErrNoItem
+	}
+	return copyTask(task), nil
+}
+
+// TaskInserter can be used to insert a new task into the store.
+type TaskInserter struct{ s *Store }
+
+// NewTaskInserter creates and returns a new TaskInserter.
+func NewTaskInserter(s *Store) TaskInserter { return TaskInserter{s: s} }
+
+// Insert inserts a new task into the store.
+func (i TaskInserter) Insert(_ context.Context, task tasktbl.Task) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	if _, ok := i.s.tasks[task.ID]; ok {
+		return db.ErrDupKey
+	}
+	i.s.tasks[task.ID] = copyTask(task)
+	return nil
+}
+
+// TaskUpdater can be used to update a task in the store.
+type TaskUpdater struct{ s *Store }
+
+// NewTaskUpdater creates and returns a new TaskUpdater.
+func NewTaskUpdater(s *Store) TaskUpdater { return TaskUpdater{s: s} }
+
+// Update updates a task in the store, keeping the stored task's rank if the
+// given task has none.
+func (u TaskUpdater) Update(_ context.Context, task tasktbl.Task) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	stored, ok := u.s.tasks[task.ID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	if task.Rank == "" {
+		task.Rank = stored.Rank
+	}
+	u.s.tasks[task.ID] = copyTask(task)
+	return nil
+}
+
+// TaskTransactionalUpdater can be used to update multiple tasks in the store
+// at once.
+type TaskTransactionalUpdater struct{ s *Store }
+
+// NewTaskTransactionalUpdater creates and returns a new
+// TaskTransactionalUpdater.
+func NewTaskTransactionalUpdater(s *Store) TaskTransactionalUpdater {
+	return TaskTransactionalUpdater{s: s}
+}
+
+// Update updates multiple tasks in the store at once. Like a DynamoDB
+// transaction, none of the tasks are updated if any of them does not exist.
+func (u TaskTransactionalUpdater) Update(
+	_ context.Context, tasks []tasktbl.Task,
+) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	for _, t := range tasks {
+		if _, ok := u.s.tasks[t.ID]; !ok {
+			return db.ErrNoItem
+		}
+	}
+	for _, t := range tasks {
+		u.s.tasks[t.ID] = copyTask(t)
+	}
+	return nil
+}
+
+// TaskTransactionalInserter can be used to insert multiple new tasks into the
+// store at once.
+type TaskTransactionalInserter struct{ s *Store }
+
+// NewTaskTransactionalInserter creates and returns a new
+// TaskTransactionalInserter.
+func NewTaskTransactionalInserter(s *Store) TaskTransactionalInserter {
+	return TaskTransactionalInserter{s: s}
+}
+
+// Insert inserts multiple new tasks into the store at once. None of the tasks
+// are inserted if any of them already exists.
+func (i TaskTransactionalInserter) Insert(
+	_ context.Context, tasks []tasktbl.Task,
+) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	for _, t := range tasks {
+		if _, ok := i.s.tasks[t.ID]; ok {
+			return db.ErrDupKey
+		}
+	}
+	for _, t := range tasks {
+		i.s.tasks[t.ID] = copyTask(t)
+	}
+	return nil
+}
+
+// TaskDeleter can be used to delete a task from the store.
+type TaskDeleter struct{ s *Store }
+
+// NewTaskDeleter creates and returns a new TaskDeleter.
+func NewTaskDeleter(s *Store) TaskDeleter { return TaskDeleter{s: s} }
+
+// Delete deletes the task with the given ID that belongs to the team with the
+// given ID from the store.
+func (d TaskDeleter) Delete(_ context.Context, teamID, taskID string) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	task, ok := d.s.tasks[taskID]
+	if !ok || task.TeamID != teamID {
+		return db.ErrNoItem
+	}
+	delete(d.s.tasks, taskID)
+	return nil
+}
+
+// TaskRetrieverByBoard can be used to retrieve all tasks of a board from the
+// store.
+type TaskRetrieverByBoard struct{ s *Store }
+
+// NewTaskRetrieverByBoard creates and returns a new TaskRetrieverByBoard.
+func NewTaskRetrieverByBoard(s *Store) TaskRetrieverByBoard {
+	return TaskRetrieverByBoard{s: s}
+}
+
+// Retrieve retrieves all tasks of the board with the given ID from the store.
+func (r TaskRetrieverByBoard) Retrieve(
+	_ context.Context, boardID string,
+) ([]tasktbl.Task, error) {
+	return r.s.filterTasks(func(t tasktbl.Task) bool {
+		return t.BoardID == boardID
+	}), nil
+}
+
+// RetrievePage retrieves up to limit tasks of the board with the given ID from
+// the store, ordered by task ID and starting after the task ID in the cursor.
+func (r TaskRetrieverByBoard) RetrievePage(
+	_ context.Context, boardID string, limit int, cursor string,
+) ([]tasktbl.Task, string, error) {
+	tasks := r.s.filterTasks(func(t tasktbl.Task) bool {
+		return t.BoardID == boardID && t.ID > cursor
+	})
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	if len(tasks) <= limit {
+		return tasks, "", nil
+	}
+	tasks = tasks[:limit]
+	return tasks, tasks[limit-1].ID, nil
+}
+
+// TaskRetrieverByTeam can be used to retrieve all tasks of a team from the
+// store.
+type TaskRetrieverByTeam struct{ s *Store }
+
+// NewTaskRetrieverByTeam creates and returns a new TaskRetrieverByTeam.
+func NewTaskRetrieverByTeam(s *Store) TaskRetrieverByTeam {
+	return TaskRetrieverByTeam{s: s}
+}
+
+// Retrieve retrieves all tasks of the team with the given ID from the store.
+func (r TaskRetrieverByTeam) Retrieve(
+	_ context.Context, teamID string,
+) ([]tasktbl.Task, error) {
+	return r.s.filterTasks(func(t tasktbl.Task) bool {
+		return t.TeamID == teamID
+	}), nil
+}
+
+// filterTasks returns copies of the tasks that satisfy the given predicate,
+// ordered by board ID, column number, and rank.
+func (s *Store) filterTasks(keep func(tasktbl.Task) bool) []tasktbl.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []tasktbl.Task
+	for _, t := range s.tasks {
+		if keep(t) {
+			tasks = append(tasks, copyTask(t))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.BoardID != b.BoardID {
+			return a.BoardID < b.BoardID
+		}
+		if a.ColNo != b.ColNo {
+			return a.ColNo < b.ColNo
+		}
+		return tasktbl.Less(a, b)
+	})
+	return tasks
+}
@@ -0,0 +1,117 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+)
+
+func TestTaskAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	retriever := NewTaskRetriever(s)
+	inserter := NewTaskInserter(s)
+	updater := NewTaskUpdater(s)
+	transactionalUpdater := NewTaskTransactionalUpdater(s)
+	transactionalInserter := NewTaskTransactionalInserter(s)
+	deleter := NewTaskDeleter(s)
+	byBoard := NewTaskRetrieverByBoard(s)
+	byTeam := NewTaskRetrieverByTeam(s)
+
+	_, err := retriever.Retrieve(ctx, "t1", "k1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = updater.Update(ctx, tasktbl.Task{ID: "k1"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	for _, task := range []tasktbl.Task{
+		{TeamID: "t1", BoardID: "b1", ID: "k1", ColNo: 1, Rank: "i"},
+		{TeamID: "t1", BoardID: "b1", ID: "k2", ColNo: 0, Order: 1},
+		{TeamID: "t1", BoardID: "b2", ID: "k3", ColNo: 0, Order: 0},
+		{TeamID: "t2", BoardID: "b3", ID: "k4", ColNo: 0, Order: 0},
+	} {
+		err = inserter.Insert(ctx, task)
+		assert.Nil(t.Fatal, err)
+	}
+	err = inserter.Insert(ctx, tasktbl.Task{ID: "k1"})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	tasks, err := byBoard.Retrieve(ctx, "b1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(tasks), 2)
+	assert.Equal(t.Error, tasks[0].ID, "k2")
+	assert.Equal(t.Error, tasks[1].ID, "k1")
+
+	tasks, next, err := byBoard.RetrievePage(ctx, "b1", 1, "")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(tasks), 1)
+	assert.Equal(t.Error, tasks[0].ID, "k1")
+	tasks, next, err = byBoard.RetrievePage(ctx, "b1", 1, next)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(tasks), 1)
+	assert.Equal(t.Error, tasks[0].ID, "k2")
+	assert.Equal(t.Error, next, "")
+
+	tasks, err = byTeam.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(tasks), 3)
+
+	err = updater.Update(ctx, tasktbl.Task{
+		TeamID: "t1", BoardID: "b1", ID: "k1", Title: "Updated",
+	})
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "t2", "k1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	task, err := retriever.Retrieve(ctx, "t1", "k1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, task.Title, "Updated")
+	assert.Equal(t.Error, task.Rank, "i")
+
+	// multi update is all or nothing
+	err = transactionalUpdater.Update(ctx, []tasktbl.Task{
+		{TeamID: "t1", BoardID: "b1", ID: "k2", ColNo: 3},
+		{TeamID: "t1", BoardID: "b1", ID: "k9", ColNo: 3},
+	})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	task, err = retriever.Retrieve(ctx, "t1", "k2")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, task.ColNo, 0)
+
+	err = transactionalUpdater.Update(ctx, []tasktbl.Task{
+		{TeamID: "t1", BoardID: "b1", ID: "k2", ColNo: 3},
+	})
+	assert.Nil(t.Fatal, err)
+	task, err = retriever.Retrieve(ctx, "t1", "k2")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, task.ColNo, 3)
+
+	// multi insert is all or nothing
+	err = transactionalInserter.Insert(ctx, []tasktbl.Task{
+		{TeamID: "t1", BoardID: "b4", ID: "k5"},
+		{TeamID: "t1", BoardID: "b4", ID: "k1"},
+	})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+	_, err = retriever.Retrieve(ctx, "t1", "k5")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = transactionalInserter.Insert(ctx, []tasktbl.Task{
+		{TeamID: "t1", BoardID: "b4", ID: "k5"},
+		{TeamID: "t1", BoardID: "b4", ID: "k6"},
+	})
+	assert.Nil(t.Fatal, err)
+	tasks, err = byBoard.Retrieve(ctx, "b4")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(tasks), 2)
+
+	// tasks can only be deleted by their own team
+	err = deleter.Delete(ctx, "t2", "k1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = deleter.Delete(ctx, "t1", "k1")
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "t1", "k1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+}
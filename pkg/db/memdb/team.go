@@ -0,0 +1,250 @@
+package memdb
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// TeamRetriever can be used to retrieve by ID a team from the store.
+type TeamRetriever struct{ s *Store }
+
+// NewTeamRetriever creates and returns a new TeamRetriever.
+func NewTeamRetriever(s *Store) TeamRetriever { return TeamRetriever{s: s} }
+
+// Retrieve retrieves by ID a team from the store.
+func (r TeamRetriever) Retrieve(
+	_ context.Context, id string,
+) (teamtbl.Team, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	team, ok := r.s.teams[id]
+	if !ok {
+		return teamtbl.Team{}, db.ErrNoItem
+	}
+	return team.Clone(), nil
+}
+
+// TeamInserter can be used to insert a new team into the store.
+type TeamInserter struct{ s *Store }
+
+// NewTeamInserter creates and returns a new TeamInserter.
+func NewTeamInserter(s *Store) TeamInserter { return TeamInserter{s: s} }
+
+// Insert inserts a new team into the store.
+func (i TeamInserter) Insert(_ context.Context, team teamtbl.Team) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	if _, ok := i.s.teams[team.ID]; ok {
+		return db.ErrDupKey
+	}
+	i.s.teams[team.ID] = team.Clone()
+	return nil
+}
+
+// TeamUpdater can be used to update a team in the store.
+type TeamUpdater struct{ s *Store }
+
+// NewTeamUpdater creates and returns a new TeamUpdater.
+func NewTeamUpdater(s *Store) TeamUpdater { return TeamUpdater{s: s} }
+
+// Update updates a team in the store.
+func (u TeamUpdater) Update(_ context.Context, team teamtbl.Team) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	if _, ok := u.s.teams[team.ID]; !ok {
+		return db.ErrNoItem
+	}
+	u.s.teams[team.ID] = team.Clone()
+	return nil
+}
+
+// BoardInserter can be used to insert a board into a team's boards.
+type BoardInserter struct {
+	s     *Store
+	quota quota.Quota
+}
+
+// NewBoardInserter creates and returns a new BoardInserter that enforces the
+// given default quota unless the team overrides it.
+func NewBoardInserter(s *Store, quota quota.Quota) BoardInserter {
+	return BoardInserter{s: s, quota: quota}
+}
+
+// Insert inserts the given board into the boards of the team with the given ID.
+func (i BoardInserter) Insert(
+	_ context.Context, teamID string, board teamtbl.Board,
+) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	team, ok := i.s.teams[teamID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	for _, b := range team.Boards {
+		if b.ID == board.ID {
+			return db.ErrDupKey
+		}
+	}
+	if !i.quota.Override(team.Quota).AllowsBoard(len(team.Boards)) {
+		return db.ErrLimitReached
+	}
+
+	team = team.Clone()
+	team.Boards = append(team.Boards, board)
+	i.s.teams[teamID] = team
+	return nil
+}
+
+// BoardUpdater can be used to update a board in a team's boards.
+type BoardUpdater struct{ s *Store }
+
+// NewBoardUpdater creates and returns a new BoardUpdater.
+func NewBoardUpdater(s *Store) BoardUpdater { return BoardUpdater{s: s} }
+
+// Update makes the changes in the given patch to a board in the boards of the
+// team with the given ID.
+func (u BoardUpdater) Update(
+	_ context.Context, teamID string, patch teamtbl.BoardPatch,
+) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	team, ok := u.s.teams[teamID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	team = team.Clone()
+	for i, b := range team.Boards {
+		if b.ID == patch.ID {
+			board := patch.Apply(b)
+			team.Boards[i] = board
+			team.Boards[i].Members = append([]string(nil), board.Members...)
+			team.Boards[i].Columns = append(
+				[]teamtbl.Column(nil), board.Columns...,
+			)
+			u.s.teams[teamID] = team
+			return nil
+		}
+	}
+	return db.ErrNoItem
+}
+
+// BoardDeleter can be used to delete a board from a team's boards.
+type BoardDeleter struct{ s *Store }
+
+// NewBoardDeleter creates and returns a new BoardDeleter.
+func NewBoardDeleter(s *Store) BoardDeleter { return BoardDeleter{s: s} }
+
+// Delete deletes the board with the given ID from the team with the given ID.
+func (d BoardDeleter) Delete(_ context.Context, teamID, boardID string) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	team, ok := d.s.teams[teamID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	team = team.Clone()
+	for i, b := range team.Boards {
+		if b.ID == boardID {
+			team.Boards = append(team.Boards[:i], team.Boards[i+1:]...)
+			d.s.teams[teamID] = team
+			return nil
+		}
+	}
+	return db.ErrNoItem
+}
+
+// SprintInserter can be used to insert a sprint into a team's sprints.
+type SprintInserter struct{ s *Store }
+
+// NewSprintInserter creates and returns a new SprintInserter.
+func NewSprintInserter(s *Store) SprintInserter { return SprintInserter{s: s} }
+
+// Insert inserts the given sprint into the sprints of the team with the given
+// ID.
+func (i SprintInserter) Insert(
+	_ context.Context, teamID string, sprint teamtbl.Sprint,
+) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	team, ok := i.s.teams[teamID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	if _, ok := team.Sprint(sprint.ID); ok {
+		return db.ErrDupKey
+	}
+	if !team.HasBoard(sprint.BoardID) {
+		return db.ErrNoItem
+	}
+
+	team = team.Clone()
+	team.Sprints = append(team.Sprints, sprint)
+	i.s.teams[teamID] = team
+	return nil
+}
+
+// SprintUpdater can be used to update a sprint in a team's sprints.
+type SprintUpdater struct{ s *Store }
+
+// NewSprintUpdater creates and returns a new SprintUpdater.
+func NewSprintUpdater(s *Store) SprintUpdater { return SprintUpdater{s: s} }
+
+// Update updates a sprint in the sprints of the team with the given ID.
+func (u SprintUpdater) Update(
+	_ context.Context, teamID string, sprint teamtbl.Sprint,
+) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	team, ok := u.s.teams[teamID]
+	if !ok || !team.HasBoard(sprint.BoardID) {
+		return db.ErrNoItem
+	}
+	team = team.Clone()
+	for i, s := range team.Sprints {
+		if s.ID == sprint.ID {
+			team.Sprints[i] = sprint
+			u.s.teams[teamID] = team
+			return nil
+		}
+	}
+	return db.ErrNoItem
+}
+
+// SprintDeleter can be used to delete a sprint from a team's sprints.
+type SprintDeleter struct{ s *Store }
+
+// NewSprintDeleter creates and returns a new SprintDeleter.
+func NewSprintDeleter(s *Store) SprintDeleter { return SprintDeleter{s: s} }
+
+// Delete deletes the sprint with the given ID from the team with the given ID.
+func (d SprintDeleter) Delete(
+	_ context.Context, teamID, sprintID string,
+) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	team, ok := d.s.teams[teamID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	team = team.Clone()
+	for i, s := range team.Sprints {
+		if s.ID == sprintID {
+			team.Sprints = append(team.Sprints[:i], team.Sprints[i+1:]...)
+			d.s.teams[teamID] = team
+			return nil
+		}
+	}
+	return db.ErrNoItem
+}
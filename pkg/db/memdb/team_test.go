@@ -0,0 +1,151 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+func TestTeamAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	retriever := NewTeamRetriever(s)
+	inserter := NewTeamInserter(s)
+	updater := NewTeamUpdater(s)
+
+	_, err := retriever.Retrieve(ctx, "t1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = updater.Update(ctx, teamtbl.Team{ID: "t1"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	team := teamtbl.NewTeam("t1", []string{"bob"}, nil)
+	err = inserter.Insert(ctx, team)
+	assert.Nil(t.Fatal, err)
+	err = inserter.Insert(ctx, team)
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	// mutating a retrieved team must not change the stored one
+	got, err := retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	got.Members[0] = "alice"
+	got, err = retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, got.Members[0], "bob")
+
+	team.Members = append(team.Members, "alice")
+	err = updater.Update(ctx, team)
+	assert.Nil(t.Fatal, err)
+	got, err = retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.AllEqual(t.Error, got.Members, []string{"bob", "alice"})
+}
+
+func TestBoardAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	s.teams["t1"] = teamtbl.NewTeam("t1", []string{"bob"}, nil)
+	retriever := NewTeamRetriever(s)
+	inserter := NewBoardInserter(s, quota.Default())
+	updater := NewBoardUpdater(s)
+	deleter := NewBoardDeleter(s)
+
+	err := inserter.Insert(ctx, "t2", teamtbl.NewBoard("b1", "Board 1"))
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	for _, id := range []string{"b1", "b2", "b3"} {
+		err = inserter.Insert(ctx, "t1", teamtbl.NewBoard(id, "Board"))
+		assert.Nil(t.Fatal, err)
+	}
+	err = inserter.Insert(ctx, "t1", teamtbl.NewBoard("b1", "Board"))
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+	err = inserter.Insert(ctx, "t1", teamtbl.NewBoard("b4", "Board"))
+	assert.ErrIs(t.Fatal, err, db.ErrLimitReached)
+
+	// the share nonce and the fields not in the patch are kept on update
+	s.teams["t1"].Boards[1].ShareNonce = "nonce1"
+	s.teams["t1"].Boards[1].Visibility = teamtbl.VisibilityTeam
+	name := "Renamed"
+	err = updater.Update(ctx, "t1", teamtbl.BoardPatch{ID: "b4", Name: &name})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = updater.Update(ctx, "t1", teamtbl.BoardPatch{ID: "b2", Name: &name})
+	assert.Nil(t.Fatal, err)
+
+	err = deleter.Delete(ctx, "t1", "b4")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = deleter.Delete(ctx, "t1", "b1")
+	assert.Nil(t.Fatal, err)
+
+	team, err := retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(team.Boards), 2)
+	assert.Equal(t.Error, team.Boards[0].ID, "b2")
+	assert.Equal(t.Error, team.Boards[0].Name, "Renamed")
+	assert.Equal(t.Error, team.Boards[0].ShareNonce, "nonce1")
+	assert.Equal(t.Error, team.Boards[0].Visibility, teamtbl.VisibilityTeam)
+	assert.Equal(t.Error, team.Boards[1].ID, "b3")
+}
+
+func TestBoardInserterQuotaOverride(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	team := teamtbl.NewTeam("t1", []string{"bob"}, nil)
+	team.Quota = quota.Quota{MaxBoards: 1}
+	s.teams["t1"] = team
+	inserter := NewBoardInserter(s, quota.Default())
+
+	err := inserter.Insert(ctx, "t1", teamtbl.NewBoard("b1", "Board"))
+	assert.Nil(t.Fatal, err)
+	err = inserter.Insert(ctx, "t1", teamtbl.NewBoard("b2", "Board"))
+	assert.ErrIs(t.Fatal, err, db.ErrLimitReached)
+}
+
+func TestSprintAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	s.teams["t1"] = teamtbl.NewTeam("t1", []string{"bob"}, []teamtbl.Board{
+		teamtbl.NewBoard("b1", "Board 1"),
+	})
+	retriever := NewTeamRetriever(s)
+	inserter := NewSprintInserter(s)
+	updater := NewSprintUpdater(s)
+	deleter := NewSprintDeleter(s)
+
+	err := inserter.Insert(ctx, "t2", teamtbl.Sprint{ID: "s1", BoardID: "b1"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = inserter.Insert(ctx, "t1", teamtbl.Sprint{ID: "s1", BoardID: "b2"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	for _, id := range []string{"s1", "s2"} {
+		err = inserter.Insert(ctx, "t1", teamtbl.Sprint{ID: id, BoardID: "b1"})
+		assert.Nil(t.Fatal, err)
+	}
+	err = inserter.Insert(ctx, "t1", teamtbl.Sprint{ID: "s1", BoardID: "b1"})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+
+	err = updater.Update(ctx, "t1", teamtbl.Sprint{ID: "s3", BoardID: "b1"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = updater.Update(ctx, "t1", teamtbl.Sprint{ID: "s2", BoardID: "b2"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = updater.Update(ctx, "t1", teamtbl.Sprint{
+		ID: "s2", BoardID: "b1", Name: "Renamed",
+	})
+	assert.Nil(t.Fatal, err)
+
+	err = deleter.Delete(ctx, "t1", "s3")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = deleter.Delete(ctx, "t1", "s1")
+	assert.Nil(t.Fatal, err)
+
+	team, err := retriever.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(team.Sprints), 1)
+	assert.Equal(t.Error, team.Sprints[0].ID, "s2")
+	assert.Equal(t.Error, team.Sprints[0].Name, "Renamed")
+}
@@ -0,0 +1,129 @@
+package memdb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+)
+
+// TrashInserter can be used to insert an item into the trash in the store.
+type TrashInserter struct{ s *Store }
+
+// NewTrashInserter creates and returns a new TrashInserter.
+func NewTrashInserter(s *Store) TrashInserter { return TrashInserter{s: s} }
+
+// Insert inserts an item into the trash in the store, replacing any item with
+// the same ID deleted before.
+func (i TrashInserter) Insert(_ context.Context, item trashtbl.Item) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	i.s.trash[trashKey(item.TeamID, item.ID)] = copyTrashItem(item)
+	return nil
+}
+
+// TrashRetriever can be used to retrieve by team ID and ID an item from the
+// trash in the store.
+type TrashRetriever struct{ s *Store }
+
+// NewTrashRetriever creates and returns a new TrashRetriever.
+func NewTrashRetriever(s *Store) TrashRetriever { return TrashRetriever{s: s} }
+
+// Retrieve retrieves by team ID and ID an item from the trash in the store.
+// Expired items are treated as absent.
+func (r TrashRetriever) Retrieve(
+	_ context.Context, teamID, id string,
+) (trashtbl.Item, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	item, ok := r.s.trash[trashKey(teamID, id)]
+	if !ok || item.IsExpired() {
+		return trashtbl.Item{}, db.ErrNoItem
+	}
+	return copyTrashItem(item), nil
+}
+
+// TrashRetrieverByTeam can be used to retrieve all items of a team from the
+// trash in the store.
+type TrashRetrieverByTeam struct{ s *Store }
+
+// NewTrashRetrieverByTeam creates and returns a new TrashRetrieverByTeam.
+func NewTrashRetrieverByTeam(s *Store) TrashRetrieverByTeam {
+	return TrashRetrieverByTeam{s: s}
+}
+
+// Retrieve retrieves all unexpired items of a team from the trash in the
+// store, ordered by ID like a DynamoDB query would.
+func (r TrashRetrieverByTeam) Retrieve(
+	_ context.Context, teamID string,
+) ([]trashtbl.Item, error) {
+	return r.s.filterTrash(func(item trashtbl.Item) bool {
+		return item.TeamID == teamID && !item.IsExpired()
+	}), nil
+}
+
+// TrashExpiredRetrieverByTeam can be used to retrieve the expired items of a
+// team from the trash in the store.
+type TrashExpiredRetrieverByTeam struct{ s *Store }
+
+// NewTrashExpiredRetrieverByTeam creates and returns a new
+// TrashExpiredRetrieverByTeam.
+func NewTrashExpiredRetrieverByTeam(s *Store) TrashExpiredRetrieverByTeam {
+	return TrashExpiredRetrieverByTeam{s: s}
+}
+
+// Retrieve retrieves all expired items of a team from the trash in the store,
+// ordered by ID like a DynamoDB query would.
+func (r TrashExpiredRetrieverByTeam) Retrieve(
+	_ context.Context, teamID string,
+) ([]trashtbl.Item, error) {
+	return r.s.filterTrash(func(item trashtbl.Item) bool {
+		return item.TeamID == teamID && item.IsExpired()
+	}), nil
+}
+
+// TrashDeleter can be used to delete an item from the trash in the store.
+type TrashDeleter struct{ s *Store }
+
+// NewTrashDeleter creates and returns a new TrashDeleter.
+func NewTrashDeleter(s *Store) TrashDeleter { return TrashDeleter{s: s} }
+
+// Delete deletes by team ID and ID an item from the trash in the store.
+// Deleting an item that does not exist is not an error.
+func (d TrashDeleter) Delete(_ context.Context, teamID, id string) error {
+	d.s.mu.Lock()
+	defer d.s.mu.Unlock()
+
+	delete(d.s.trash, trashKey(teamID, id))
+	return nil
+}
+
+// filterTrash returns copies of the items in the trash that satisfy the given
+// predicate, ordered by ID.
+func (s *Store) filterTrash(keep func(trashtbl.Item) bool) []trashtbl.Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []trashtbl.Item
+	for _, item := range s.trash {
+		if keep(item) {
+			items = append(items, copyTrashItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// trashKey returns the key of the item with the given team ID and ID in the
+// store's trash.
+func trashKey(teamID, id string) string { return teamID + "/" + id }
+
+// copyTrashItem returns a deep copy of the given trash item.
+func copyTrashItem(item trashtbl.Item) trashtbl.Item {
+	item.Board.Members = append([]string(nil), item.Board.Members...)
+	item.Task = copyTask(item.Task)
+	return item
+}
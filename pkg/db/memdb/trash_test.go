@@ -0,0 +1,74 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/trashtbl"
+)
+
+func TestTrashAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	inserter := NewTrashInserter(s)
+	retriever := NewTrashRetriever(s)
+	byTeam := NewTrashRetrieverByTeam(s)
+	expiredByTeam := NewTrashExpiredRetrieverByTeam(s)
+	deleter := NewTrashDeleter(s)
+
+	_, err := retriever.Retrieve(ctx, "t1", "b1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	expired := trashtbl.NewTaskItem("t1", "bob", tasktbl.Task{ID: "k0"})
+	expired.ExpiresAt = 0
+	for _, item := range []trashtbl.Item{
+		trashtbl.NewBoardItem("t1", "bob", teamtbl.Board{
+			ID: "b1", Members: []string{"bob"},
+		}),
+		trashtbl.NewTaskItem("t1", "bob", tasktbl.Task{ID: "k1"}),
+		trashtbl.NewTaskItem("t2", "alice", tasktbl.Task{ID: "k2"}),
+		expired,
+	} {
+		err = inserter.Insert(ctx, item)
+		assert.Nil(t.Fatal, err)
+	}
+
+	// items are only retrieved by their own team, and expired ones are absent
+	_, err = retriever.Retrieve(ctx, "t2", "b1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	_, err = retriever.Retrieve(ctx, "t1", "k0")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	item, err := retriever.Retrieve(ctx, "t1", "b1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, item.Kind, trashtbl.KindBoard)
+
+	// mutating a retrieved item must not change the stored one
+	item.Board.Members[0] = "alice"
+	item, err = retriever.Retrieve(ctx, "t1", "b1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, item.Board.Members[0], "bob")
+
+	items, err := byTeam.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(items), 2)
+	assert.Equal(t.Error, items[0].ID, "b1")
+	assert.Equal(t.Error, items[1].ID, "k1")
+
+	items, err = expiredByTeam.Retrieve(ctx, "t1")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(items), 1)
+	assert.Equal(t.Error, items[0].ID, "k0")
+
+	err = deleter.Delete(ctx, "t1", "b1")
+	assert.Nil(t.Fatal, err)
+	_, err = retriever.Retrieve(ctx, "t1", "b1")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+	err = deleter.Delete(ctx, "t1", "b1")
+	assert.Nil(t.Fatal, err)
+}
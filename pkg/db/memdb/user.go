@@ -0,0 +1,123 @@
+package memdb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// UserRetriever can be used to retrieve by username a user from the store.
+type UserRetriever struct{ s *Store }
+
+// NewUserRetriever creates and returns a new UserRetriever.
+func NewUserRetriever(s *Store) UserRetriever { return UserRetriever{s: s} }
+
+// Retrieve retrieves by username a user from the store.
+func (r UserRetriever) Retrieve(
+	_ context.Context, username string,
+) (usertbl.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[username]
+	if !ok {
+		return usertbl.User{}, db.ErrNoItem
+	}
+	return copyUser(user), nil
+}
+
+// UserInserter can be used to insert a new user into the store.
+type UserInserter struct{ s *Store }
+
+// NewUserInserter creates and returns a new UserInserter.
+func NewUserInserter(s *Store) UserInserter { return UserInserter{s: s} }
+
+// Insert inserts a new user into the store.
+func (i UserInserter) Insert(_ context.Context, user usertbl.User) error {
+	i.s.mu.Lock()
+	defer i.s.mu.Unlock()
+
+	if _, ok := i.s.users[user.Username]; ok {
+		return db.ErrDupKey
+	}
+	i.s.users[user.Username] = copyUser(user)
+	return nil
+}
+
+// UserUpdater can be used to update a user in the store.
+type UserUpdater struct{ s *Store }
+
+// NewUserUpdater creates and returns a new UserUpdater.
+func NewUserUpdater(s *Store) UserUpdater { return UserUpdater{s: s} }
+
+// Update updates a user in the store.
+func (u UserUpdater) Update(_ context.Context, user usertbl.User) error {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	if _, ok := u.s.users[user.Username]; !ok {
+		return db.ErrNoItem
+	}
+	u.s.users[user.Username] = copyUser(user)
+	return nil
+}
+
+// UserLister can be used to list all users in the store.
+type UserLister struct{ s *Store }
+
+// NewUserLister creates and returns a new UserLister.
+func NewUserLister(s *Store) UserLister { return UserLister{s: s} }
+
+// List returns all users in the store ordered by username.
+func (l UserLister) List(context.Context) ([]usertbl.User, error) {
+	l.s.mu.Lock()
+	defer l.s.mu.Unlock()
+
+	users := make([]usertbl.User, 0, len(l.s.users))
+	for _, u := range l.s.users {
+		users = append(users, copyUser(u))
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// UserRetrieverByEmail can be used to retrieve by email the users from the
+// store.
+type UserRetrieverByEmail struct{ s *Store }
+
+// NewUserRetrieverByEmail creates and returns a new UserRetrieverByEmail.
+func NewUserRetrieverByEmail(s *Store) UserRetrieverByEmail {
+	return UserRetrieverByEmail{s: s}
+}
+
+// Retrieve retrieves by email all users from the store that have it as their
+// verified email address, ordered by username.
+func (r UserRetrieverByEmail) Retrieve(
+	_ context.Context, email string,
+) ([]usertbl.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var users []usertbl.User
+	for _, u := range r.s.users {
+		if email != "" && u.Email == email {
+			users = append(users, copyUser(u))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// copyUser returns a copy of the user that does not share its favorites with
+// the original.
+func copyUser(u usertbl.User) usertbl.User {
+	u.Favorites = append([]string(nil), u.Favorites...)
+	u.Sessions = append([]usertbl.Session(nil), u.Sessions...)
+	return u
+}
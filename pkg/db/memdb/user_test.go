@@ -0,0 +1,63 @@
+//go:build utest
+
+package memdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+func TestUserAccessors(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	retriever := NewUserRetriever(s)
+	inserter := NewUserInserter(s)
+	updater := NewUserUpdater(s)
+	lister := NewUserLister(s)
+	retrieverByEmail := NewUserRetrieverByEmail(s)
+
+	_, err := retriever.Retrieve(ctx, "bob")
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = updater.Update(ctx, usertbl.User{Username: "bob"})
+	assert.ErrIs(t.Fatal, err, db.ErrNoItem)
+
+	err = inserter.Insert(ctx, usertbl.User{Username: "bob", TeamID: "t1"})
+	assert.Nil(t.Fatal, err)
+	err = inserter.Insert(ctx, usertbl.User{Username: "bob"})
+	assert.ErrIs(t.Fatal, err, db.ErrDupKey)
+	err = inserter.Insert(ctx, usertbl.User{
+		Username: "alice", Email: "alice@acme.com",
+	})
+	assert.Nil(t.Fatal, err)
+
+	err = updater.Update(ctx, usertbl.User{
+		Username: "bob", TeamID: "t1", IsDisabled: true,
+	})
+	assert.Nil(t.Fatal, err)
+
+	user, err := retriever.Retrieve(ctx, "bob")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, user.TeamID, "t1")
+	assert.True(t.Error, user.IsDisabled)
+
+	users, err := lister.List(ctx)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(users), 2)
+	assert.Equal(t.Error, users[0].Username, "alice")
+	assert.Equal(t.Error, users[1].Username, "bob")
+
+	byEmail, err := retrieverByEmail.Retrieve(ctx, "alice@acme.com")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(byEmail), 1)
+	assert.Equal(t.Error, byEmail[0].Username, "alice")
+
+	// users without an email address cannot be retrieved by the empty one
+	byEmail, err = retrieverByEmail.Retrieve(ctx, "")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(byEmail), 0)
+}
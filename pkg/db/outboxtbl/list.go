@@ -0,0 +1,53 @@
+package outboxtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Lister can be used to list all items in the outbox table.
+type Lister struct{ scanner db.DynamoScanner }
+
+// NewLister creates and returns a new Lister.
+func NewLister(scanner db.DynamoScanner) Lister {
+	return Lister{scanner: scanner}
+}
+
+// List scans the outbox table and returns all items in it. The table only
+// holds the items that are yet to be dispatched, so it is kept small.
+func (l Lister) List(ctx context.Context) ([]Item, error) {
+	var (
+		items    []Item
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		out, err := l.scanner.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(os.Getenv(tableName)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Item
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		// keep scanning until there are no more pages
+		if len(out.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,77 @@
+//go:build utest
+
+package outboxtbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestLister(t *testing.T) {
+	scanner := &db.FakeDynamoScanner{}
+	sut := NewLister(scanner)
+
+	errA := errors.New("failed to scan")
+	item := func(id string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ID":       &types.AttributeValueMemberS{Value: id},
+			"Kind":     &types.AttributeValueMemberS{Value: "notify"},
+			"Attempts": &types.AttributeValueMemberN{Value: "2"},
+		}
+	}
+
+	for _, c := range []struct {
+		name      string
+		outs      []*dynamodb.ScanOutput
+		scanErr   error
+		wantItems []Item
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			outs:      nil,
+			scanErr:   errA,
+			wantItems: nil,
+			wantErr:   errA,
+		},
+		{
+			name: "Paginated",
+			outs: []*dynamodb.ScanOutput{
+				{
+					Items: []map[string]types.AttributeValue{
+						item("i1"),
+					},
+					LastEvaluatedKey: item("i1"),
+				},
+				{
+					Items: []map[string]types.AttributeValue{
+						item("i2"),
+					},
+				},
+			},
+			scanErr: nil,
+			wantItems: []Item{
+				{ID: "i1", Kind: "notify", Attempts: 2},
+				{ID: "i2", Kind: "notify", Attempts: 2},
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			scanner.Outs = c.outs
+			scanner.Err = c.scanErr
+
+			items, err := sut.List(context.Background())
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.AllEqual(t.Error, items, c.wantItems)
+		})
+	}
+}
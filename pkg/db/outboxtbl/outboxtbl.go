@@ -0,0 +1,57 @@
+// Package outboxtbl contains code to interact with the outbox table in
+// DynamoDB, which stores the events that are to be dispatched to the queue.
+// The events are written in the same transactions as the writes they are of,
+// so that an event is dispatched if and only if its write is made.
+package outboxtbl
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// tableName is the name of the environment variable to retrieve the outbox
+// table's name from.
+const tableName = "OUTBOX_TABLE_NAME"
+
+// Item defines the outbox item entity. Its kind and body are those of the
+// queue message it is dispatched as.
+type Item struct {
+	ID   string
+	Kind string
+	Body string // JSON
+
+	// Attempts is the number of times the item has been claimed for
+	// dispatching, and NextAttemptAt is the Unix time before which it must
+	// not be claimed again.
+	Attempts      int
+	NextAttemptAt int64
+}
+
+// NewItem creates and returns a new Item of the given kind whose body is the
+// JSON encoding of the given value.
+func NewItem(kind string, body any) (Item, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return Item{}, err
+	}
+	return Item{ID: uuid.NewString(), Kind: kind, Body: string(b)}, nil
+}
+
+// Put returns the write that puts the given item into the outbox table, for
+// adding to the transactions of the writes that it is the event of.
+func Put(item Item) (types.TransactWriteItem, error) {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(os.Getenv(tableName)),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(ID)"),
+	}}, nil
+}
@@ -0,0 +1,38 @@
+//go:build utest
+
+package outboxtbl
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestNewItem tests NewItem and Put to assert that the items are created with
+// their bodies JSON-encoded, and put into the outbox table only if they are
+// new.
+func TestNewItem(t *testing.T) {
+	t.Setenv(tableName, "goteam-outbox")
+
+	item, err := NewItem("notify", map[string]string{"teamID": "t1"})
+
+	assert.Nil(t.Fatal, err)
+	assert.True(t.Error, item.ID != "")
+	assert.Equal(t.Error, item.Kind, "notify")
+	assert.Equal(t.Error, item.Body, `{"teamID":"t1"}`)
+
+	w, err := Put(item)
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, aws.ToString(w.Put.TableName), "goteam-outbox")
+	assert.Equal(
+		t.Error,
+		aws.ToString(w.Put.ConditionExpression),
+		"attribute_not_exists(ID)",
+	)
+	id, _ := w.Put.Item["ID"].(*types.AttributeValueMemberS)
+	assert.Equal(t.Error, id.Value, item.ID)
+}
@@ -0,0 +1,55 @@
+package outboxtbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Updater can be used to update an item in the outbox table.
+type Updater struct{ iput db.DynamoItemPutter }
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater(iput db.DynamoItemPutter) Updater { return Updater{iput: iput} }
+
+// Update updates an item in the outbox table only if its attempts were one
+// fewer when it was read, so that of the dispatchers that read it, only the
+// first one to count an attempt claims it. It returns db.ErrConflict if
+// another dispatcher claimed it first, and db.ErrNoItem if it was deleted.
+func (u Updater) Update(ctx context.Context, item Item) error {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(os.Getenv(tableName)),
+		Item:                av,
+		ConditionExpression: aws.String("Attempts = :a"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":a": &types.AttributeValueMemberN{
+				Value: strconv.Itoa(item.Attempts - 1),
+			},
+		},
+		ReturnValuesOnConditionCheckFailure: types.
+			ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		if ex.Item == nil {
+			return db.ErrNoItem
+		}
+		return db.ErrConflict
+	}
+
+	return err
+}
@@ -0,0 +1,228 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever is a db.Retriever that retries the throttled calls to the
+// retriever it wraps.
+type Retriever[T any] struct {
+	next    db.Retriever[T]
+	backoff Backoff
+}
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever[T any](next db.Retriever[T], backoff Backoff) Retriever[T] {
+	return Retriever[T]{next: next, backoff: backoff}
+}
+
+// Retrieve retrieves the item with the given key using the wrapped retriever.
+func (r Retriever[T]) Retrieve(ctx context.Context, key string) (T, error) {
+	var item T
+	err := r.backoff.Do(ctx, func(ctx context.Context) (err error) {
+		item, err = r.next.Retrieve(ctx, key)
+		return err
+	})
+	return item, err
+}
+
+// RetrieverDualKey is a db.RetrieverDualKey that retries the throttled calls
+// to the retriever it wraps.
+type RetrieverDualKey[T any] struct {
+	next    db.RetrieverDualKey[T]
+	backoff Backoff
+}
+
+// NewRetrieverDualKey creates and returns a new RetrieverDualKey.
+func NewRetrieverDualKey[T any](
+	next db.RetrieverDualKey[T], backoff Backoff,
+) RetrieverDualKey[T] {
+	return RetrieverDualKey[T]{next: next, backoff: backoff}
+}
+
+// Retrieve retrieves the item with the given keys using the wrapped retriever.
+func (r RetrieverDualKey[T]) Retrieve(
+	ctx context.Context, key1, key2 string,
+) (T, error) {
+	var item T
+	err := r.backoff.Do(ctx, func(ctx context.Context) (err error) {
+		item, err = r.next.Retrieve(ctx, key1, key2)
+		return err
+	})
+	return item, err
+}
+
+// PageRetriever is a db.PageRetriever that retries the throttled calls to the
+// retriever it wraps.
+type PageRetriever[T any] struct {
+	next    db.PageRetriever[T]
+	backoff Backoff
+}
+
+// NewPageRetriever creates and returns a new PageRetriever.
+func NewPageRetriever[T any](
+	next db.PageRetriever[T], backoff Backoff,
+) PageRetriever[T] {
+	return PageRetriever[T]{next: next, backoff: backoff}
+}
+
+// RetrievePage retrieves a page of items using the wrapped retriever.
+func (r PageRetriever[T]) RetrievePage(
+	ctx context.Context, key string, limit int, cursor string,
+) (T, string, error) {
+	var (
+		page T
+		next string
+	)
+	err := r.backoff.Do(ctx, func(ctx context.Context) (err error) {
+		page, next, err = r.next.RetrievePage(ctx, key, limit, cursor)
+		return err
+	})
+	return page, next, err
+}
+
+// Lister is a db.Lister that retries the throttled calls to the lister it
+// wraps.
+type Lister[T any] struct {
+	next    db.Lister[T]
+	backoff Backoff
+}
+
+// NewLister creates and returns a new Lister.
+func NewLister[T any](next db.Lister[T], backoff Backoff) Lister[T] {
+	return Lister[T]{next: next, backoff: backoff}
+}
+
+// List lists all items using the wrapped lister.
+func (l Lister[T]) List(ctx context.Context) (T, error) {
+	var items T
+	err := l.backoff.Do(ctx, func(ctx context.Context) (err error) {
+		items, err = l.next.List(ctx)
+		return err
+	})
+	return items, err
+}
+
+// Inserter is a db.Inserter that retries the throttled calls to the inserter
+// it wraps.
+type Inserter[T any] struct {
+	next    db.Inserter[T]
+	backoff Backoff
+}
+
+// NewInserter creates and returns a new Inserter.
+func NewInserter[T any](next db.Inserter[T], backoff Backoff) Inserter[T] {
+	return Inserter[T]{next: next, backoff: backoff}
+}
+
+// Insert inserts the item using the wrapped inserter.
+func (i Inserter[T]) Insert(ctx context.Context, item T) error {
+	return i.backoff.Do(ctx, func(ctx context.Context) error {
+		return i.next.Insert(ctx, item)
+	})
+}
+
+// InserterDualKey is a db.InserterDualKey that retries the throttled calls to
+// the inserter it wraps.
+type InserterDualKey[T any] struct {
+	next    db.InserterDualKey[T]
+	backoff Backoff
+}
+
+// NewInserterDualKey creates and returns a new InserterDualKey.
+func NewInserterDualKey[T any](
+	next db.InserterDualKey[T], backoff Backoff,
+) InserterDualKey[T] {
+	return InserterDualKey[T]{next: next, backoff: backoff}
+}
+
+// Insert inserts the item with the given key using the wrapped inserter.
+func (i InserterDualKey[T]) Insert(
+	ctx context.Context, key string, item T,
+) error {
+	return i.backoff.Do(ctx, func(ctx context.Context) error {
+		return i.next.Insert(ctx, key, item)
+	})
+}
+
+// Updater is a db.Updater that retries the throttled calls to the updater it
+// wraps.
+type Updater[T any] struct {
+	next    db.Updater[T]
+	backoff Backoff
+}
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater[T any](next db.Updater[T], backoff Backoff) Updater[T] {
+	return Updater[T]{next: next, backoff: backoff}
+}
+
+// Update updates the item using the wrapped updater.
+func (u Updater[T]) Update(ctx context.Context, item T) error {
+	return u.backoff.Do(ctx, func(ctx context.Context) error {
+		return u.next.Update(ctx, item)
+	})
+}
+
+// UpdaterDualKey is a db.UpdaterDualKey that retries the throttled calls to
+// the updater it wraps.
+type UpdaterDualKey[T any] struct {
+	next    db.UpdaterDualKey[T]
+	backoff Backoff
+}
+
+// NewUpdaterDualKey creates and returns a new UpdaterDualKey.
+func NewUpdaterDualKey[T any](
+	next db.UpdaterDualKey[T], backoff Backoff,
+) UpdaterDualKey[T] {
+	return UpdaterDualKey[T]{next: next, backoff: backoff}
+}
+
+// Update updates the item with the given key using the wrapped updater.
+func (u UpdaterDualKey[T]) Update(
+	ctx context.Context, key string, item T,
+) error {
+	return u.backoff.Do(ctx, func(ctx context.Context) error {
+		return u.next.Update(ctx, key, item)
+	})
+}
+
+// Deleter is a db.Deleter that retries the throttled calls to the deleter it
+// wraps.
+type Deleter struct {
+	next    db.Deleter
+	backoff Backoff
+}
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(next db.Deleter, backoff Backoff) Deleter {
+	return Deleter{next: next, backoff: backoff}
+}
+
+// Delete deletes the item with the given key using the wrapped deleter.
+func (d Deleter) Delete(ctx context.Context, key string) error {
+	return d.backoff.Do(ctx, func(ctx context.Context) error {
+		return d.next.Delete(ctx, key)
+	})
+}
+
+// DeleterDualKey is a db.DeleterDualKey that retries the throttled calls to
+// the deleter it wraps.
+type DeleterDualKey struct {
+	next    db.DeleterDualKey
+	backoff Backoff
+}
+
+// NewDeleterDualKey creates and returns a new DeleterDualKey.
+func NewDeleterDualKey(next db.DeleterDualKey, backoff Backoff) DeleterDualKey {
+	return DeleterDualKey{next: next, backoff: backoff}
+}
+
+// Delete deletes the item with the given keys using the wrapped deleter.
+func (d DeleterDualKey) Delete(ctx context.Context, key1, key2 string) error {
+	return d.backoff.Do(ctx, func(ctx context.Context) error {
+		return d.next.Delete(ctx, key1, key2)
+	})
+}
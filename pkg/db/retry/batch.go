@@ -0,0 +1,125 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// maxBatchWriteItems is the maximum number of write requests DynamoDB accepts
+// in a single BatchWriteItem call.
+const maxBatchWriteItems = 25
+
+// UnprocessedError means that some of the write requests passed to BatchWrite
+// were not applied. It holds the requests by table name so that callers can
+// report or retry them.
+type UnprocessedError struct {
+	Items map[string][]types.WriteRequest
+
+	// Err is the error that stopped the batches from being written, or nil if
+	// DynamoDB kept leaving the requests unprocessed until the attempts ran
+	// out.
+	Err error
+}
+
+// Error returns how many write requests were left unprocessed, and why.
+func (e *UnprocessedError) Error() string {
+	var n int
+	for _, reqs := range e.Items {
+		n += len(reqs)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%d write requests unprocessed: %v", n, e.Err)
+	}
+	return fmt.Sprintf("%d write requests unprocessed", n)
+}
+
+// Unwrap returns the error that stopped the batches from being written.
+func (e *UnprocessedError) Unwrap() error { return e.Err }
+
+// BatchWrite writes the given write requests, keyed by table name, in batches
+// of up to 25. DynamoDB applies only some of a batch's requests when it is
+// throttled, so the requests it reports as unprocessed are written again after
+// a delay until the attempts run out. It returns an *UnprocessedError holding
+// the requests that were not written if any of them were not.
+func (b Backoff) BatchWrite(
+	ctx context.Context,
+	bw db.DynamoBatchWriter,
+	reqs map[string][]types.WriteRequest,
+) error {
+	// split the requests into batches, in table name order so that they are
+	// written deterministically
+	tables := make([]string, 0, len(reqs))
+	for table := range reqs {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	var batches []map[string][]types.WriteRequest
+	var size int
+	for _, table := range tables {
+		for _, req := range reqs[table] {
+			if size%maxBatchWriteItems == 0 {
+				batches = append(batches, map[string][]types.WriteRequest{})
+			}
+			batch := batches[len(batches)-1]
+			batch[table] = append(batch[table], req)
+			size++
+		}
+	}
+
+	for i, batch := range batches {
+		pending, err := b.writeBatch(ctx, bw, batch)
+		if len(pending) == 0 && err == nil {
+			continue
+		}
+
+		// report the requests of this batch that were not written and the
+		// requests of the batches that were not attempted
+		for _, rest := range batches[i+1:] {
+			for table, reqs := range rest {
+				pending[table] = append(pending[table], reqs...)
+			}
+		}
+		return &UnprocessedError{Items: pending, Err: err}
+	}
+	return nil
+}
+
+// writeBatch writes a single batch of write requests, retrying the unprocessed
+// ones until there are none left or the attempts run out. It returns the
+// requests that are still unprocessed.
+func (b Backoff) writeBatch(
+	ctx context.Context,
+	bw db.DynamoBatchWriter,
+	pending map[string][]types.WriteRequest,
+) (map[string][]types.WriteRequest, error) {
+	for attempt := 0; attempt < b.maxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := b.sleep(ctx, b.delay(attempt)); err != nil {
+				return pending, err
+			}
+		}
+
+		var out *dynamodb.BatchWriteItemOutput
+		if err := b.Do(ctx, func(ctx context.Context) error {
+			var err error
+			out, err = bw.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: pending,
+			})
+			return err
+		}); err != nil {
+			return pending, err
+		}
+
+		if len(out.UnprocessedItems) == 0 {
+			return nil, nil
+		}
+		pending = out.UnprocessedItems
+	}
+	return pending, nil
+}
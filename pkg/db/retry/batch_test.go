@@ -0,0 +1,145 @@
+//go:build utest
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// TestBatchWrite tests the BatchWrite method of Backoff to assert that it
+// splits the write requests into batches of up to 25, retries the requests
+// DynamoDB leaves unprocessed, and reports the ones that are never written.
+func TestBatchWrite(t *testing.T) {
+	// putReqs returns n put requests for items with sequential IDs.
+	putReqs := func(n int) []types.WriteRequest {
+		reqs := make([]types.WriteRequest, n)
+		for i := range reqs {
+			reqs[i] = types.WriteRequest{PutRequest: &types.PutRequest{
+				Item: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: strconv.Itoa(i)},
+				},
+			}}
+		}
+		return reqs
+	}
+
+	// setup returns a Backoff that records the delays it sleeps for instead
+	// of sleeping.
+	setup := func() (Backoff, *[]time.Duration) {
+		var delays []time.Duration
+		b := NewBackoff(3, 10*time.Millisecond, 15*time.Millisecond, 0)
+		b.sleep = func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+		return b, &delays
+	}
+
+	t.Run("Batches", func(t *testing.T) {
+		b, delays := setup()
+		bw := &db.FakeDynamoBatchWriter{}
+
+		err := b.BatchWrite(
+			context.Background(),
+			bw,
+			map[string][]types.WriteRequest{
+				"task": putReqs(30), "team": putReqs(1),
+			},
+		)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(bw.Ins), 2)
+		assert.Equal(t.Error, len(bw.Ins[0].RequestItems["task"]), 25)
+		assert.Equal(t.Error, len(bw.Ins[1].RequestItems["task"]), 5)
+		assert.Equal(t.Error, len(bw.Ins[1].RequestItems["team"]), 1)
+		assert.Equal(t.Error, len(*delays), 0)
+	})
+
+	t.Run("RetriedUnprocessed", func(t *testing.T) {
+		b, delays := setup()
+		unprocessed := map[string][]types.WriteRequest{"task": putReqs(2)}
+		bw := &db.FakeDynamoBatchWriter{Outs: []*dynamodb.BatchWriteItemOutput{
+			{UnprocessedItems: unprocessed}, {},
+		}}
+
+		err := b.BatchWrite(
+			context.Background(),
+			bw,
+			map[string][]types.WriteRequest{"task": putReqs(10)},
+		)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(bw.Ins), 2)
+		assert.Equal(t.Error, len(bw.Ins[1].RequestItems["task"]), 2)
+		assert.Equal(t.Error, len(*delays), 1)
+	})
+
+	t.Run("Unprocessed", func(t *testing.T) {
+		b, _ := setup()
+		unprocessed := map[string][]types.WriteRequest{"task": putReqs(2)}
+		bw := &db.FakeDynamoBatchWriter{Outs: []*dynamodb.BatchWriteItemOutput{
+			{UnprocessedItems: unprocessed},
+			{UnprocessedItems: unprocessed},
+			{UnprocessedItems: unprocessed},
+		}}
+
+		err := b.BatchWrite(
+			context.Background(),
+			bw,
+			map[string][]types.WriteRequest{"task": putReqs(27)},
+		)
+
+		// the 2 requests left unprocessed in the first batch and the 2 in the
+		// batch that was not attempted are reported
+		var uerr *UnprocessedError
+		assert.True(t.Fatal, errors.As(err, &uerr))
+		assert.Equal(t.Error, len(bw.Ins), 3)
+		assert.Equal(t.Error, len(uerr.Items["task"]), 4)
+		assert.Nil(t.Error, uerr.Err)
+		assert.Equal(t.Error, err.Error(), "4 write requests unprocessed")
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		b, _ := setup()
+		errA := errors.New("failed")
+		bw := &db.FakeDynamoBatchWriter{Err: errA}
+
+		err := b.BatchWrite(
+			context.Background(),
+			bw,
+			map[string][]types.WriteRequest{"task": putReqs(3)},
+		)
+
+		var uerr *UnprocessedError
+		assert.True(t.Fatal, errors.As(err, &uerr))
+		assert.ErrIs(t.Error, err, errA)
+		assert.Equal(t.Error, len(bw.Ins), 1)
+		assert.Equal(t.Error, len(uerr.Items["task"]), 3)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		b, delays := setup()
+		bw := &db.FakeDynamoBatchWriter{Err: errThrottled}
+
+		err := b.BatchWrite(
+			context.Background(),
+			bw,
+			map[string][]types.WriteRequest{"task": putReqs(3)},
+		)
+
+		// the throttled call is retried by Do before the batch gives up
+		assert.ErrIs(t.Error, err, errThrottled)
+		assert.Equal(t.Error, len(bw.Ins), 3)
+		assert.Equal(t.Error, len(*delays), 2)
+	})
+}
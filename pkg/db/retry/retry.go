@@ -0,0 +1,159 @@
+// Package retry contains decorators for the pkg/db interfaces that retry the
+// calls to the accessors they wrap when DynamoDB throttles them, backing off
+// exponentially with jitter between attempts. Each attempt is bounded by a
+// timeout so that a call cannot hang on network trouble.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// Names of the environment variables to load the backoff settings from. Each
+// is optional and falls back to the value in DefaultBackoff when unset.
+const (
+	EnvMaxAttempts = "DB_RETRY_MAX_ATTEMPTS"
+	EnvBaseDelay   = "DB_RETRY_BASE_DELAY"
+	EnvMaxDelay    = "DB_RETRY_MAX_DELAY"
+	EnvCallTimeout = "DB_CALL_TIMEOUT"
+)
+
+// throttlingCodes are the error codes DynamoDB responds with when it throttles
+// a request. Throttled requests are rejected before they are applied, so it is
+// safe to retry them even if they are not idempotent.
+var throttlingCodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"ThrottlingException":                    true,
+	"Throttling":                             true,
+}
+
+// IsThrottled returns whether the error was caused by DynamoDB throttling the
+// request, including transactions that were cancelled due to throttling.
+func IsThrottled(err error) bool {
+	var txErr *types.TransactionCanceledException
+	if errors.As(err, &txErr) {
+		for _, r := range txErr.CancellationReasons {
+			if r.Code != nil && *r.Code == "ThrottlingError" {
+				return true
+			}
+		}
+		return false
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && throttlingCodes[apiErr.ErrorCode()]
+}
+
+// Backoff determines how many times and how long apart a throttled call is
+// attempted, and how long each attempt may take.
+type Backoff struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	callTimeout time.Duration
+	sleep       func(context.Context, time.Duration) error
+}
+
+// NewBackoff creates and returns a new Backoff that attempts a call up to
+// maxAttempts times. The delay before each retry is picked at random up to the
+// base delay doubled for each previous attempt, capped at the max delay. Each
+// attempt is cancelled after callTimeout unless it is zero.
+func NewBackoff(
+	maxAttempts int, baseDelay, maxDelay, callTimeout time.Duration,
+) Backoff {
+	return Backoff{
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+		callTimeout: callTimeout,
+		sleep:       clock.Sleep,
+	}
+}
+
+// DefaultBackoff returns the Backoff used unless configured otherwise.
+func DefaultBackoff() Backoff {
+	return NewBackoff(4, 50*time.Millisecond, 2*time.Second, 5*time.Second)
+}
+
+// BackoffFromEnv returns the default backoff with each setting replaced by the
+// value of its environment variable if set.
+func BackoffFromEnv() (Backoff, error) {
+	b := DefaultBackoff()
+	if s := os.Getenv(EnvMaxAttempts); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return Backoff{}, fmt.Errorf(
+				"%s must be a positive integer, got %q", EnvMaxAttempts, s,
+			)
+		}
+		b.maxAttempts = n
+	}
+	for name, delay := range map[string]*time.Duration{
+		EnvBaseDelay:   &b.baseDelay,
+		EnvMaxDelay:    &b.maxDelay,
+		EnvCallTimeout: &b.callTimeout,
+	} {
+		s := os.Getenv(name)
+		if s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return Backoff{}, fmt.Errorf(
+				"%s must be a positive duration, got %q", name, s,
+			)
+		}
+		*delay = d
+	}
+	return b, nil
+}
+
+// Do calls fn until it returns an error other than a throttling error, the
+// attempts run out, or the context is done. It returns the last error. Each
+// call to fn is passed a context derived from ctx that is cancelled once the
+// call timeout elapses.
+func (b Backoff) Do(ctx context.Context, fn func(context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < b.maxAttempts; attempt++ {
+		if attempt > 0 {
+			if sErr := b.sleep(ctx, b.delay(attempt)); sErr != nil {
+				return err
+			}
+		}
+		if err = b.attempt(ctx, fn); !IsThrottled(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// attempt calls fn once with the call timeout applied to its context.
+func (b Backoff) attempt(
+	ctx context.Context, fn func(context.Context) error,
+) error {
+	if b.callTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, b.callTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// delay returns a random delay to wait for before the given attempt.
+func (b Backoff) delay(attempt int) time.Duration {
+	ceil := b.maxDelay
+	if shift := attempt - 1; shift < 20 && b.baseDelay<<shift < ceil {
+		ceil = b.baseDelay << shift
+	}
+	return time.Duration(rand.Int63n(int64(ceil) + 1))
+}
@@ -0,0 +1,244 @@
+//go:build utest
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// errThrottled is a throttling error as returned by the DynamoDB client.
+var errThrottled = &types.ProvisionedThroughputExceededException{}
+
+// TestIsThrottled tests the IsThrottled function to assert that it only
+// reports the errors DynamoDB responds with when it throttles requests.
+func TestIsThrottled(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Nil", err: nil, want: false},
+		{name: "Other", err: errors.New("failed"), want: false},
+		{name: "NoItem", err: db.ErrNoItem, want: false},
+		{name: "Throughput", err: errThrottled, want: true},
+		{name: "RequestLimit", err: &types.RequestLimitExceeded{}, want: true},
+		{
+			name: "Wrapped",
+			err:  errors.Join(errors.New("put failed"), errThrottled),
+			want: true,
+		},
+		{
+			name: "TransactionThrottled",
+			err: &types.TransactionCanceledException{
+				CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("None")},
+					{Code: aws.String("ThrottlingError")},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "TransactionConflict",
+			err: &types.TransactionCanceledException{
+				CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("ConditionalCheckFailed")},
+				},
+			},
+			want: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, IsThrottled(c.err), c.want)
+		})
+	}
+}
+
+// TestBackoff tests the Do method of Backoff to assert that it retries
+// throttled calls with growing delays until they succeed or run out of
+// attempts.
+func TestBackoff(t *testing.T) {
+	// setup returns a Backoff that records the delays it sleeps for instead of
+	// sleeping, and a function that is throttled the given number of times.
+	setup := func(sleepErr error, throttles int, err error) (
+		Backoff, *[]time.Duration, func(context.Context) error, *int,
+	) {
+		var delays []time.Duration
+		b := NewBackoff(3, 10*time.Millisecond, 15*time.Millisecond, 0)
+		b.sleep = func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return sleepErr
+		}
+		var calls int
+		fn := func(context.Context) error {
+			calls++
+			if calls <= throttles {
+				return errThrottled
+			}
+			return err
+		}
+		return b, &delays, fn, &calls
+	}
+
+	t.Run("Succeeds", func(t *testing.T) {
+		b, delays, fn, calls := setup(nil, 0, nil)
+		err := b.Do(context.Background(), fn)
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, *calls, 1)
+		assert.Equal(t.Error, len(*delays), 0)
+	})
+
+	t.Run("NotThrottled", func(t *testing.T) {
+		b, _, fn, calls := setup(nil, 0, db.ErrConflict)
+		err := b.Do(context.Background(), fn)
+		assert.ErrIs(t.Error, err, db.ErrConflict)
+		assert.Equal(t.Error, *calls, 1)
+	})
+
+	t.Run("SucceedsAfterThrottles", func(t *testing.T) {
+		b, delays, fn, calls := setup(nil, 2, nil)
+		err := b.Do(context.Background(), fn)
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, *calls, 3)
+		assert.Equal(t.Fatal, len(*delays), 2)
+		assert.True(t.Error, (*delays)[0] <= 10*time.Millisecond)
+		assert.True(t.Error, (*delays)[1] <= 15*time.Millisecond)
+	})
+
+	t.Run("OutOfAttempts", func(t *testing.T) {
+		b, _, fn, calls := setup(nil, 5, nil)
+		err := b.Do(context.Background(), fn)
+		assert.True(t.Error, IsThrottled(err))
+		assert.Equal(t.Error, *calls, 3)
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		b, _, fn, calls := setup(context.Canceled, 5, nil)
+		err := b.Do(context.Background(), fn)
+		assert.True(t.Error, IsThrottled(err))
+		assert.Equal(t.Error, *calls, 1)
+	})
+
+	t.Run("CallTimeout", func(t *testing.T) {
+		b := NewBackoff(3, time.Millisecond, time.Millisecond, time.Millisecond)
+		var calls int
+		err := b.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrIs(t.Error, err, context.DeadlineExceeded)
+		assert.Equal(t.Error, calls, 1)
+	})
+}
+
+// TestBackoffFromEnv tests the BackoffFromEnv function to assert that it
+// overrides the default settings with the ones set in the environment.
+func TestBackoffFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		maxAttempts  string
+		baseDelay    string
+		maxDelay     string
+		callTimeout  string
+		wantAttempts int
+		wantBase     time.Duration
+		wantTimeout  time.Duration
+		wantErrIsNil bool
+	}{
+		{
+			name:         "Unset",
+			wantAttempts: DefaultBackoff().maxAttempts,
+			wantBase:     DefaultBackoff().baseDelay,
+			wantTimeout:  DefaultBackoff().callTimeout,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "Set",
+			maxAttempts:  "6",
+			baseDelay:    "20ms",
+			maxDelay:     "5s",
+			callTimeout:  "1s",
+			wantAttempts: 6,
+			wantBase:     20 * time.Millisecond,
+			wantTimeout:  time.Second,
+			wantErrIsNil: true,
+		},
+		{
+			name:         "InvalidAttempts",
+			maxAttempts:  "0",
+			wantErrIsNil: false,
+		},
+		{
+			name:         "InvalidDelay",
+			maxDelay:     "soon",
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvMaxAttempts, c.maxAttempts)
+			t.Setenv(EnvBaseDelay, c.baseDelay)
+			t.Setenv(EnvMaxDelay, c.maxDelay)
+			t.Setenv(EnvCallTimeout, c.callTimeout)
+
+			b, err := BackoffFromEnv()
+
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+			assert.Equal(t.Error, b.maxAttempts, c.wantAttempts)
+			assert.Equal(t.Error, b.baseDelay, c.wantBase)
+			assert.Equal(t.Error, b.callTimeout, c.wantTimeout)
+		})
+	}
+}
+
+// fakeRetriever is a db.Retriever that is throttled the given number of times
+// before returning its key as the item.
+type fakeRetriever struct {
+	throttles int
+	calls     int
+}
+
+func (f *fakeRetriever) Retrieve(
+	_ context.Context, key string,
+) (string, error) {
+	f.calls++
+	if f.calls <= f.throttles {
+		return "", errThrottled
+	}
+	return key, nil
+}
+
+// TestRetriever tests the Retrieve method of Retriever to assert that it
+// returns the item retrieved by the wrapped retriever once it is not throttled.
+func TestRetriever(t *testing.T) {
+	next := &fakeRetriever{throttles: 1}
+	backoff := NewBackoff(2, time.Millisecond, time.Millisecond, 0)
+	sut := NewRetriever[string](next, backoff)
+
+	item, err := sut.Retrieve(context.Background(), "key")
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, item, "key")
+	assert.Equal(t.Error, next.calls, 2)
+}
+
+// TestUpdater tests the Update method of Updater to assert that it returns the
+// error of the wrapped updater once the attempts run out.
+func TestUpdater(t *testing.T) {
+	next := &db.FakeUpdater[string]{Err: errThrottled}
+	backoff := NewBackoff(2, time.Millisecond, time.Millisecond, 0)
+	sut := NewUpdater[string](next, backoff)
+
+	err := sut.Update(context.Background(), "item")
+
+	assert.True(t.Error, IsThrottled(err))
+	assert.Equal(t.Error, next.Updated, "item")
+}
@@ -0,0 +1,48 @@
+package sesstbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by ID a session from the session table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by ID a session from the session table. It returns
+// db.ErrNoItem if the session does not exist. Expired sessions that DynamoDB
+// has not deleted yet are returned as they are.
+func (r Retriever) Retrieve(ctx context.Context, id string) (Session, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		// read the session strongly so that the requests made right after
+		// logging in do not miss it
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	if out.Item == nil {
+		return Session{}, db.ErrNoItem
+	}
+
+	var s Session
+	if err = attributevalue.UnmarshalMap(out.Item, &s); err != nil {
+		return Session{}, err
+	}
+	return s, nil
+}
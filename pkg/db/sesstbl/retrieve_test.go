@@ -0,0 +1,82 @@
+//go:build utest
+
+package sesstbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name    string
+		igOut   *dynamodb.GetItemOutput
+		igErr   error
+		wantSes Session
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			igOut:   nil,
+			igErr:   errA,
+			wantSes: Session{},
+			wantErr: errA,
+		},
+		{
+			name:    "NoItem",
+			igOut:   &dynamodb.GetItemOutput{Item: nil},
+			igErr:   nil,
+			wantSes: Session{},
+			wantErr: db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"ID":       &types.AttributeValueMemberS{Value: "id"},
+					"Username": &types.AttributeValueMemberS{Value: "bob"},
+					"IsAdmin":  &types.AttributeValueMemberBOOL{Value: true},
+					"TeamID":   &types.AttributeValueMemberS{Value: "team1"},
+					"UserSessionID": &types.AttributeValueMemberS{
+						Value: "session1",
+					},
+					"ExpiresAt": &types.AttributeValueMemberN{Value: "60"},
+					"EndsAt":    &types.AttributeValueMemberN{Value: "600"},
+				},
+			},
+			igErr: nil,
+			wantSes: Session{
+				ID:            "id",
+				Username:      "bob",
+				IsAdmin:       true,
+				TeamID:        "team1",
+				UserSessionID: "session1",
+				ExpiresAt:     60,
+				EndsAt:        600,
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			s, err := sut.Retrieve(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, s, c.wantSes)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package sesstbl contains code to interact with the session table in
+// DynamoDB, which stores the server-side sessions that auth cookies carry the
+// opaque IDs of when the services are configured to use them instead of JWTs.
+package sesstbl
+
+// tableName is the name of the environment variable to retrieve the session
+// table's name from.
+const tableName = "SESSION_TABLE_NAME"
+
+// Session defines the session entity, which holds the auth of a user that is
+// logged in on a device.
+type Session struct {
+	ID       string // SHA-256 hash of the opaque token held by the cookie
+	Username string
+	IsAdmin  bool
+	TeamID   string
+	IsGuest  bool
+
+	// UserSessionID is the ID the session is recorded under on its user, so
+	// that it is revoked along with the user's session.
+	UserSessionID string
+
+	// ExpiresAt is the Unix time at which the session expires unless it is
+	// used before then, which extends it up to EndsAt. The table's TTL is
+	// configured on this attribute.
+	ExpiresAt int64
+	EndsAt    int64
+}
@@ -0,0 +1,43 @@
+package sesstbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Updater can be used to update a session in the session table.
+type Updater struct{ iput db.DynamoItemPutter }
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater(iput db.DynamoItemPutter) Updater { return Updater{iput: iput} }
+
+// Update updates a session in the session table. It returns db.ErrNoItem if
+// the session does not exist, so that a session deleted in the meantime is
+// not brought back.
+func (u Updater) Update(ctx context.Context, s Session) error {
+	item, err := attributevalue.MarshalMap(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(os.Getenv(tableName)),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(ID)"),
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
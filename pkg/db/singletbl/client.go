@@ -0,0 +1,322 @@
+package singletbl
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// errUnsupported means that a call to a table stored in the single table could
+// not be redirected to it as it is not one of the calls the table accessors
+// make.
+var errUnsupported = errors.New("call not supported on the single table")
+
+// Client is a db.DynamoClient that redirects the calls to the user, team, and
+// task tables to the single table, passing the calls to other tables through
+// to the client it wraps as-is.
+type Client struct{ next db.DynamoClient }
+
+// NewClient creates and returns a new Client.
+func NewClient(next db.DynamoClient) Client { return Client{next: next} }
+
+// GetItem gets an item by the key it has in its former table.
+func (c Client) GetItem(
+	ctx context.Context,
+	in *dynamodb.GetItemInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.GetItemOutput, error) {
+	e := entityOf(in.TableName)
+	if e == entityNone {
+		return c.next.GetItem(ctx, in, opts...)
+	}
+
+	key, err := primaryKey(e, in.Key)
+	if err != nil {
+		return nil, err
+	}
+	redirected := *in
+	redirected.TableName = aws.String(os.Getenv(EnvTableName))
+	redirected.Key = key
+
+	out, err := c.next.GetItem(ctx, &redirected, opts...)
+	if err != nil {
+		return nil, err
+	}
+	stripKeys(out.Item)
+	return out, nil
+}
+
+// PutItem puts an item, adding the single table's keys to it.
+func (c Client) PutItem(
+	ctx context.Context,
+	in *dynamodb.PutItemInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	e := entityOf(in.TableName)
+	if e == entityNone {
+		return c.next.PutItem(ctx, in, opts...)
+	}
+
+	item, err := keyedItem(e, in.Item)
+	if err != nil {
+		return nil, err
+	}
+	redirected := *in
+	redirected.TableName = aws.String(os.Getenv(EnvTableName))
+	redirected.Item = item
+
+	return c.next.PutItem(ctx, &redirected, opts...)
+}
+
+// DeleteItem deletes an item by the key it has in its former table.
+func (c Client) DeleteItem(
+	ctx context.Context,
+	in *dynamodb.DeleteItemInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	e := entityOf(in.TableName)
+	if e == entityNone {
+		return c.next.DeleteItem(ctx, in, opts...)
+	}
+
+	key, err := primaryKey(e, in.Key)
+	if err != nil {
+		return nil, err
+	}
+	redirected := *in
+	redirected.TableName = aws.String(os.Getenv(EnvTableName))
+	redirected.Key = key
+
+	return c.next.DeleteItem(ctx, &redirected, opts...)
+}
+
+// TransactWriteItems writes items in a transaction, redirecting each write to
+// the user, team, and task tables to the single table.
+func (c Client) TransactWriteItems(
+	ctx context.Context,
+	in *dynamodb.TransactWriteItemsInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	redirected := *in
+	redirected.TransactItems = make(
+		[]types.TransactWriteItem, len(in.TransactItems),
+	)
+	for i, item := range in.TransactItems {
+		var err error
+		if redirected.TransactItems[i], err = redirectWrite(item); err != nil {
+			return nil, err
+		}
+	}
+	return c.next.TransactWriteItems(ctx, &redirected, opts...)
+}
+
+// Query queries the tasks of a team by TeamID, the tasks of a board by BoardID
+// on the BoardID-index, or the users by Email on the Email-index.
+func (c Client) Query(
+	ctx context.Context,
+	in *dynamodb.QueryInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	e := entityOf(in.TableName)
+	switch e {
+	case entityNone:
+		return c.next.Query(ctx, in, opts...)
+	case entityTeam:
+		return nil, errUnsupported
+	}
+
+	attr, value, err := keyCondition(in)
+	if err != nil {
+		return nil, err
+	}
+
+	redirected := *in
+	redirected.TableName = aws.String(os.Getenv(EnvTableName))
+	switch {
+	case e == entityUser:
+		if aws.ToString(in.IndexName) != "Email-index" || attr != "Email" {
+			return nil, errUnsupported
+		}
+		redirected.IndexName = aws.String(indexGSI2)
+		setKeyCondition(
+			&redirected, attrGSI2PK, prefixEmail+value, attrGSI2SK, prefixUser,
+		)
+	case in.IndexName == nil && attr == "TeamID":
+		setKeyCondition(
+			&redirected, attrPK, prefixTeam+value, attrSK, prefixTask,
+		)
+	case aws.ToString(in.IndexName) == "BoardID-index" && attr == "BoardID":
+		redirected.IndexName = aws.String(indexGSI1)
+		setKeyCondition(
+			&redirected, attrGSI1PK, prefixBoard+value, attrGSI1SK, prefixTask,
+		)
+	default:
+		return nil, errUnsupported
+	}
+
+	out, err := c.next.Query(ctx, &redirected, opts...)
+	if err != nil {
+		return nil, err
+	}
+	stripKeys(out.Items...)
+	return out, nil
+}
+
+// Scan scans the user table by querying the users on GSI1, so that the other
+// items in the single table are not read.
+func (c Client) Scan(
+	ctx context.Context,
+	in *dynamodb.ScanInput,
+	opts ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	if entityOf(in.TableName) != entityUser {
+		if entityOf(in.TableName) != entityNone {
+			return nil, errUnsupported
+		}
+		return c.next.Scan(ctx, in, opts...)
+	}
+
+	query := &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(EnvTableName)),
+		IndexName:                 aws.String(indexGSI1),
+		ExclusiveStartKey:         in.ExclusiveStartKey,
+		Limit:                     in.Limit,
+		FilterExpression:          in.FilterExpression,
+		ProjectionExpression:      in.ProjectionExpression,
+		ExpressionAttributeNames:  maps.Clone(in.ExpressionAttributeNames),
+		ExpressionAttributeValues: maps.Clone(in.ExpressionAttributeValues),
+	}
+	setKeyCondition(query, attrGSI1PK, gsi1PKUser, "", "")
+
+	out, err := c.next.Query(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	stripKeys(out.Items...)
+	return &dynamodb.ScanOutput{
+		Items:            out.Items,
+		Count:            out.Count,
+		ScannedCount:     out.ScannedCount,
+		LastEvaluatedKey: out.LastEvaluatedKey,
+	}, nil
+}
+
+// keyedItem returns a copy of the given item of the given entity with the
+// single table's keys added to it.
+func keyedItem(
+	e entity, item map[string]types.AttributeValue,
+) (map[string]types.AttributeValue, error) {
+	key, err := primaryKey(e, item)
+	if err != nil {
+		return nil, err
+	}
+	idxKey, err := indexKey(e, item)
+	if err != nil {
+		return nil, err
+	}
+
+	keyed := maps.Clone(item)
+	maps.Copy(keyed, key)
+	maps.Copy(keyed, idxKey)
+	return keyed, nil
+}
+
+// redirectWrite returns the given write of a transaction redirected to the
+// single table if it is to the user, team, or task tables.
+func redirectWrite(w types.TransactWriteItem) (types.TransactWriteItem, error) {
+	single := aws.String(os.Getenv(EnvTableName))
+	var err error
+	switch {
+	case w.Put != nil && entityOf(w.Put.TableName) != entityNone:
+		put := *w.Put
+		put.Item, err = keyedItem(entityOf(put.TableName), put.Item)
+		put.TableName, w.Put = single, &put
+	case w.Delete != nil && entityOf(w.Delete.TableName) != entityNone:
+		del := *w.Delete
+		del.Key, err = primaryKey(entityOf(del.TableName), del.Key)
+		del.TableName, w.Delete = single, &del
+	case w.Update != nil && entityOf(w.Update.TableName) != entityNone:
+		upd := *w.Update
+		upd.Key, err = primaryKey(entityOf(upd.TableName), upd.Key)
+		upd.TableName, w.Update = single, &upd
+	case w.ConditionCheck != nil &&
+		entityOf(w.ConditionCheck.TableName) != entityNone:
+		check := *w.ConditionCheck
+		check.Key, err = primaryKey(entityOf(check.TableName), check.Key)
+		check.TableName, w.ConditionCheck = single, &check
+	}
+	return w, err
+}
+
+// reKeyCondition matches the key conditions the table accessors query by,
+// which are a single equality built with the expression package.
+var reKeyCondition = regexp.MustCompile(`^\s*(#\w+)\s*=\s*(:\w+)\s*$`)
+
+// keyCondition returns the name of the attribute the given query is keyed by
+// and the value it must equal.
+func keyCondition(in *dynamodb.QueryInput) (attr, value string, err error) {
+	m := reKeyCondition.FindStringSubmatch(
+		aws.ToString(in.KeyConditionExpression),
+	)
+	if m == nil {
+		return "", "", errUnsupported
+	}
+	attr = in.ExpressionAttributeNames[m[1]]
+	v, ok := in.ExpressionAttributeValues[m[2]].(*types.AttributeValueMemberS)
+	if attr == "" || !ok {
+		return "", "", errUnsupported
+	}
+	return attr, v.Value, nil
+}
+
+// setKeyCondition replaces the key condition of the given query with one on
+// the partition key pk equalling pkValue and, if sk is not empty, on the sort
+// key sk beginning with skPrefix. The names and values that only the replaced
+// condition used are removed, as DynamoDB rejects unused ones.
+func setKeyCondition(
+	in *dynamodb.QueryInput, pk, pkValue, sk, skPrefix string,
+) {
+	names := map[string]string{}
+	values := map[string]types.AttributeValue{}
+	others := aws.ToString(in.FilterExpression) + " " +
+		aws.ToString(in.ProjectionExpression)
+	for k, v := range in.ExpressionAttributeNames {
+		if usesPlaceholder(others, k) {
+			names[k] = v
+		}
+	}
+	for k, v := range in.ExpressionAttributeValues {
+		if usesPlaceholder(others, k) {
+			values[k] = v
+		}
+	}
+
+	names["#pk"] = pk
+	values[":pk"] = &types.AttributeValueMemberS{Value: pkValue}
+	cond := "#pk = :pk"
+	if sk != "" {
+		names["#sk"] = sk
+		values[":sk"] = &types.AttributeValueMemberS{Value: skPrefix}
+		cond += " AND begins_with(#sk, :sk)"
+	}
+
+	in.KeyConditionExpression = aws.String(cond)
+	in.ExpressionAttributeNames = names
+	in.ExpressionAttributeValues = values
+}
+
+// usesPlaceholder returns whether the given expression uses the given
+// expression attribute name or value placeholder, e.g. #0 or :0.
+func usesPlaceholder(expr, placeholder string) bool {
+	return regexp.MustCompile(
+		regexp.QuoteMeta(placeholder) + `\b`,
+	).MatchString(expr)
+}
@@ -0,0 +1,428 @@
+//go:build utest
+
+package singletbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// fakeClient is a test fake for db.DynamoClient that records the inputs of the
+// calls made to it.
+type fakeClient struct {
+	getIn   *dynamodb.GetItemInput
+	getOut  *dynamodb.GetItemOutput
+	putIns  []*dynamodb.PutItemInput
+	putErr  error
+	delIn   *dynamodb.DeleteItemInput
+	queryIn *dynamodb.QueryInput
+	qOut    *dynamodb.QueryOutput
+	scanIns []*dynamodb.ScanInput
+	txIn    *dynamodb.TransactWriteItemsInput
+
+	// scanOuts holds the pages returned by Scan for each table name.
+	scanOuts map[string][]*dynamodb.ScanOutput
+}
+
+func (f *fakeClient) GetItem(
+	_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options),
+) (*dynamodb.GetItemOutput, error) {
+	f.getIn = in
+	return f.getOut, nil
+}
+
+func (f *fakeClient) PutItem(
+	_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	f.putIns = append(f.putIns, in)
+	return &dynamodb.PutItemOutput{}, f.putErr
+}
+
+func (f *fakeClient) DeleteItem(
+	_ context.Context,
+	in *dynamodb.DeleteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	f.delIn = in
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (f *fakeClient) Query(
+	_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	f.queryIn = in
+	return f.qOut, nil
+}
+
+func (f *fakeClient) Scan(
+	_ context.Context, in *dynamodb.ScanInput, _ ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	f.scanIns = append(f.scanIns, in)
+	name := aws.ToString(in.TableName)
+	if len(f.scanOuts[name]) == 0 {
+		return &dynamodb.ScanOutput{}, nil
+	}
+	out := f.scanOuts[name][0]
+	f.scanOuts[name] = f.scanOuts[name][1:]
+	return out, nil
+}
+
+func (f *fakeClient) TransactWriteItems(
+	_ context.Context,
+	in *dynamodb.TransactWriteItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	f.txIn = in
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+// setTableNames sets the names of the single table and the tables it stores
+// for the duration of the test.
+func setTableNames(t *testing.T) {
+	t.Setenv(EnvTableName, "goteam")
+	t.Setenv(envUserTableName, "goteam-user")
+	t.Setenv(envTeamTableName, "goteam-team")
+	t.Setenv(envTaskTableName, "goteam-task")
+}
+
+// assertStrAttrs asserts that the given attributes include the given string
+// attributes, given as name-value pairs.
+func assertStrAttrs(
+	t *testing.T, attrs map[string]types.AttributeValue, nameValues ...string,
+) {
+	t.Helper()
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		s, ok := attrs[nameValues[i]].(*types.AttributeValueMemberS)
+		assert.True(t.Fatal, ok)
+		assert.Equal(t.Error, s.Value, nameValues[i+1])
+	}
+}
+
+func TestClientGetItem(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+
+	t.Run("Redirected", func(t *testing.T) {
+		next.getOut = &dynamodb.GetItemOutput{
+			Item: map[string]types.AttributeValue{
+				"PK":       &types.AttributeValueMemberS{Value: "USER#bob"},
+				"SK":       &types.AttributeValueMemberS{Value: "USER"},
+				"Username": &types.AttributeValueMemberS{Value: "bob"},
+				"TeamID":   &types.AttributeValueMemberS{Value: "team1"},
+			},
+		}
+
+		user, err := usertbl.NewRetriever(sut).Retrieve(
+			context.Background(), "bob",
+		)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, aws.ToString(next.getIn.TableName), "goteam")
+		assert.Equal(t.Error, len(next.getIn.Key), 2)
+		assertStrAttrs(t, next.getIn.Key, "PK", "USER#bob", "SK", "USER")
+		assert.Equal(t.Error, user.Username, "bob")
+		assert.Equal(t.Error, user.TeamID, "team1")
+		assert.Equal(t.Error, len(next.getOut.Item), 2)
+	})
+
+	t.Run("TaskKey", func(t *testing.T) {
+		next.getOut = &dynamodb.GetItemOutput{}
+
+		_, _ = tasktbl.NewRetriever(sut).Retrieve(
+			context.Background(), "team1", "task1",
+		)
+
+		assertStrAttrs(
+			t, next.getIn.Key, "PK", "TEAM#team1", "SK", "TASK#task1",
+		)
+	})
+
+	t.Run("PassedThrough", func(t *testing.T) {
+		in := &dynamodb.GetItemInput{TableName: aws.String("goteam-trash")}
+		next.getOut = &dynamodb.GetItemOutput{}
+
+		_, err := sut.GetItem(context.Background(), in)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, next.getIn, in)
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		_, err := sut.GetItem(context.Background(), &dynamodb.GetItemInput{
+			TableName: aws.String("goteam-team"),
+		})
+
+		assert.True(t.Error, err != nil)
+	})
+}
+
+func TestClientPutItem(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+
+	t.Run("Task", func(t *testing.T) {
+		err := tasktbl.NewInserter(sut).Insert(
+			context.Background(),
+			tasktbl.NewTask("team1", "board1", 0, "task1", "Do", "", 0, nil),
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.putIns[len(next.putIns)-1]
+		assert.Equal(t.Error, aws.ToString(in.TableName), "goteam")
+		assert.Equal(
+			t.Error, aws.ToString(in.ConditionExpression),
+			"attribute_not_exists(ID)",
+		)
+		assertStrAttrs(t, in.Item,
+			"PK", "TEAM#team1",
+			"SK", "TASK#task1",
+			"GSI1PK", "BOARD#board1",
+			"GSI1SK", "TASK#task1",
+			"ID", "task1",
+			"Title", "Do",
+		)
+	})
+
+	t.Run("Team", func(t *testing.T) {
+		err := teamtbl.NewInserter(sut).Insert(
+			context.Background(), teamtbl.NewTeam("team1", nil, nil),
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.putIns[len(next.putIns)-1]
+		assertStrAttrs(t, in.Item, "PK", "TEAM#team1", "SK", "TEAM")
+		assert.Equal(t.Error, in.Item["GSI1PK"], nil)
+	})
+
+	t.Run("User", func(t *testing.T) {
+		err := usertbl.NewInserter(sut).Insert(
+			context.Background(),
+			usertbl.NewUser("bob", []byte("hash"), false, "team1"),
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.putIns[len(next.putIns)-1]
+		assertStrAttrs(t, in.Item,
+			"PK", "USER#bob", "SK", "USER", "GSI1PK", "USER", "GSI1SK", "bob",
+		)
+		assert.Equal(t.Error, in.Item["GSI2PK"], nil)
+	})
+
+	t.Run("UserWithEmail", func(t *testing.T) {
+		user := usertbl.NewUser("bob", []byte("hash"), false, "team1")
+		user.Email = "bob@acme.com"
+
+		err := usertbl.NewInserter(sut).Insert(context.Background(), user)
+
+		assert.Nil(t.Fatal, err)
+		in := next.putIns[len(next.putIns)-1]
+		assertStrAttrs(t, in.Item,
+			"GSI1PK", "USER", "GSI1SK", "bob",
+			"GSI2PK", "EMAIL#bob@acme.com", "GSI2SK", "USER#bob",
+		)
+	})
+}
+
+func TestClientDeleteItem(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+
+	err := tasktbl.NewDeleter(sut).Delete(
+		context.Background(), "team1", "task1",
+	)
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, aws.ToString(next.delIn.TableName), "goteam")
+	assert.Equal(t.Error, len(next.delIn.Key), 2)
+	assertStrAttrs(t, next.delIn.Key, "PK", "TEAM#team1", "SK", "TASK#task1")
+}
+
+func TestClientQuery(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+	taskItem := map[string]types.AttributeValue{
+		"PK":     &types.AttributeValueMemberS{Value: "TEAM#team1"},
+		"SK":     &types.AttributeValueMemberS{Value: "TASK#task1"},
+		"TeamID": &types.AttributeValueMemberS{Value: "team1"},
+		"ID":     &types.AttributeValueMemberS{Value: "task1"},
+	}
+
+	t.Run("ByTeam", func(t *testing.T) {
+		next.qOut = &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{taskItem},
+		}
+
+		tasks, err := tasktbl.NewRetrieverByTeam(sut).Retrieve(
+			context.Background(), "team1",
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.queryIn
+		assert.Equal(t.Error, aws.ToString(in.TableName), "goteam")
+		assert.True(t.Error, in.IndexName == nil)
+		assert.Equal(
+			t.Error,
+			aws.ToString(in.KeyConditionExpression),
+			"#pk = :pk AND begins_with(#sk, :sk)",
+		)
+		assert.AllEqual(t.Error,
+			[]string{
+				in.ExpressionAttributeNames["#pk"],
+				in.ExpressionAttributeNames["#sk"],
+			},
+			[]string{"PK", "SK"},
+		)
+		assert.Equal(t.Error, len(in.ExpressionAttributeNames), 2)
+		assert.Equal(t.Error, len(in.ExpressionAttributeValues), 2)
+		assertStrAttrs(t, in.ExpressionAttributeValues,
+			":pk", "TEAM#team1", ":sk", "TASK#",
+		)
+		assert.Equal(t.Fatal, len(tasks), 1)
+		assert.Equal(t.Error, tasks[0].ID, "task1")
+		assert.Equal(t.Error, taskItem["PK"], nil)
+	})
+
+	t.Run("ByBoard", func(t *testing.T) {
+		next.qOut = &dynamodb.QueryOutput{}
+
+		_, err := tasktbl.NewRetrieverByBoard(sut).Retrieve(
+			context.Background(), "board1",
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.queryIn
+		assert.Equal(t.Error, aws.ToString(in.IndexName), "GSI1")
+		assert.Equal(t.Error, in.ExpressionAttributeNames["#pk"], "GSI1PK")
+		assert.Equal(t.Error, in.ExpressionAttributeNames["#sk"], "GSI1SK")
+		assertStrAttrs(t, in.ExpressionAttributeValues,
+			":pk", "BOARD#board1", ":sk", "TASK#",
+		)
+	})
+
+	t.Run("ByEmail", func(t *testing.T) {
+		next.qOut = &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{{
+				"PK":       &types.AttributeValueMemberS{Value: "USER#bob"},
+				"GSI2PK":   &types.AttributeValueMemberS{Value: "EMAIL#b@a.c"},
+				"Username": &types.AttributeValueMemberS{Value: "bob"},
+			}},
+		}
+
+		users, err := usertbl.NewRetrieverByEmail(sut).Retrieve(
+			context.Background(), "b@a.c",
+		)
+
+		assert.Nil(t.Fatal, err)
+		in := next.queryIn
+		assert.Equal(t.Error, aws.ToString(in.TableName), "goteam")
+		assert.Equal(t.Error, aws.ToString(in.IndexName), "GSI2")
+		assert.Equal(t.Error, in.ExpressionAttributeNames["#pk"], "GSI2PK")
+		assert.Equal(t.Error, in.ExpressionAttributeNames["#sk"], "GSI2SK")
+		assertStrAttrs(t, in.ExpressionAttributeValues,
+			":pk", "EMAIL#b@a.c", ":sk", "USER#",
+		)
+		assert.Equal(t.Fatal, len(users), 1)
+		assert.Equal(t.Error, users[0].Username, "bob")
+		assert.Equal(t.Error, next.qOut.Items[0]["GSI2PK"], nil)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := sut.Query(context.Background(), &dynamodb.QueryInput{
+			TableName: aws.String("goteam-team"),
+		})
+
+		assert.True(t.Error, errors.Is(err, errUnsupported))
+	})
+
+	t.Run("UnsupportedUserIndex", func(t *testing.T) {
+		_, err := sut.Query(context.Background(), &dynamodb.QueryInput{
+			TableName:              aws.String("goteam-user"),
+			IndexName:              aws.String("TeamID-index"),
+			KeyConditionExpression: aws.String("#0 = :0"),
+			ExpressionAttributeNames: map[string]string{
+				"#0": "TeamID",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":0": &types.AttributeValueMemberS{Value: "team1"},
+			},
+		})
+
+		assert.True(t.Error, errors.Is(err, errUnsupported))
+	})
+}
+
+func TestClientScan(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+	next.qOut = &dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{{
+			"PK":       &types.AttributeValueMemberS{Value: "USER#bob"},
+			"Username": &types.AttributeValueMemberS{Value: "bob"},
+		}},
+	}
+
+	users, err := usertbl.NewLister(sut).List(context.Background())
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(next.scanIns), 0)
+	in := next.queryIn
+	assert.Equal(t.Error, aws.ToString(in.IndexName), "GSI1")
+	assert.Equal(t.Error, aws.ToString(in.KeyConditionExpression), "#pk = :pk")
+	assertStrAttrs(t, in.ExpressionAttributeValues, ":pk", "USER")
+	assert.Equal(t.Fatal, len(users), 1)
+	assert.Equal(t.Error, users[0].Username, "bob")
+}
+
+func TestClientTransactWriteItems(t *testing.T) {
+	setTableNames(t)
+	next := &fakeClient{}
+	sut := NewClient(next)
+	trashPut := types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String("goteam-trash"),
+	}}
+
+	_, err := sut.TransactWriteItems(
+		context.Background(),
+		&dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				{Put: &types.Put{
+					TableName: aws.String("goteam-task"),
+					Item: strAttrs(
+						"TeamID", "team1", "BoardID", "board1", "ID", "task1",
+					),
+				}},
+				{Delete: &types.Delete{
+					TableName: aws.String("goteam-team"),
+					Key:       strAttrs("ID", "team1"),
+				}},
+				trashPut,
+			},
+		},
+	)
+
+	assert.Nil(t.Fatal, err)
+	items := next.txIn.TransactItems
+	assert.Equal(t.Fatal, len(items), 3)
+	assert.Equal(t.Error, aws.ToString(items[0].Put.TableName), "goteam")
+	assertStrAttrs(t, items[0].Put.Item,
+		"PK", "TEAM#team1", "SK", "TASK#task1", "GSI1PK", "BOARD#board1",
+	)
+	assert.Equal(t.Error, aws.ToString(items[1].Delete.TableName), "goteam")
+	assertStrAttrs(t, items[1].Delete.Key, "PK", "TEAM#team1", "SK", "TEAM")
+	assert.Equal(t.Error, items[2].Put, trashPut.Put)
+}
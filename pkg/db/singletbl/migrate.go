@@ -0,0 +1,66 @@
+package singletbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Migrate copies the users, teams, and tasks from the user, team, and task
+// tables into the single table and returns the number of items it copied. The
+// client must not redirect calls to the single table, as the former tables are
+// scanned through it.
+//
+// Items are put unconditionally, overwriting the copies of them from previous
+// runs. The services should keep using the former tables until it completes,
+// and it can be run again right before they are switched over to pick up the
+// writes made in the meantime.
+func Migrate(ctx context.Context, client db.DynamoClient) (int, error) {
+	if os.Getenv(EnvTableName) == "" {
+		return 0, errors.New(EnvTableName + " was empty")
+	}
+	single := NewClient(client)
+
+	var n int
+	for _, env := range []string{
+		envUserTableName, envTeamTableName, envTaskTableName,
+	} {
+		tableName := os.Getenv(env)
+		if tableName == "" {
+			return n, errors.New(env + " was empty")
+		}
+
+		var startKey map[string]types.AttributeValue
+		for {
+			out, err := client.Scan(ctx, &dynamodb.ScanInput{
+				TableName:         aws.String(tableName),
+				ExclusiveStartKey: startKey,
+			})
+			if err != nil {
+				return n, err
+			}
+
+			for _, item := range out.Items {
+				if _, err = single.PutItem(ctx, &dynamodb.PutItemInput{
+					TableName: aws.String(tableName), Item: item,
+				}); err != nil {
+					return n, err
+				}
+				n++
+			}
+
+			// keep scanning until there are no more pages
+			if len(out.LastEvaluatedKey) == 0 {
+				break
+			}
+			startKey = out.LastEvaluatedKey
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,99 @@
+//go:build utest
+
+package singletbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Run("EmptyTableName", func(t *testing.T) {
+		setTableNames(t)
+		t.Setenv(EnvTableName, "")
+
+		n, err := Migrate(context.Background(), &fakeClient{})
+
+		assert.Equal(t.Error, n, 0)
+		assert.Equal(t.Error, err.Error(), EnvTableName+" was empty")
+	})
+
+	t.Run("PutErr", func(t *testing.T) {
+		setTableNames(t)
+		errA := errors.New("failed")
+		client := &fakeClient{
+			putErr: errA,
+			scanOuts: map[string][]*dynamodb.ScanOutput{
+				"goteam-user": {{
+					Items: []map[string]types.AttributeValue{
+						strAttrs("Username", "bob"),
+					},
+				}},
+			},
+		}
+
+		n, err := Migrate(context.Background(), client)
+
+		assert.Equal(t.Error, n, 0)
+		assert.ErrIs(t.Error, err, errA)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		setTableNames(t)
+		client := &fakeClient{scanOuts: map[string][]*dynamodb.ScanOutput{
+			"goteam-user": {
+				{
+					Items: []map[string]types.AttributeValue{
+						strAttrs("Username", "bob"),
+					},
+					LastEvaluatedKey: strAttrs("Username", "bob"),
+				},
+				{
+					Items: []map[string]types.AttributeValue{
+						strAttrs("Username", "tom"),
+					},
+				},
+			},
+			"goteam-team": {{
+				Items: []map[string]types.AttributeValue{
+					strAttrs("ID", "team1"),
+				},
+			}},
+			"goteam-task": {{
+				Items: []map[string]types.AttributeValue{
+					strAttrs("TeamID", "team1", "BoardID", "b1", "ID", "t1"),
+				},
+			}},
+		}}
+
+		n, err := Migrate(context.Background(), client)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, n, 4)
+
+		// the user table is scanned from where each page stopped
+		assert.Equal(t.Fatal, len(client.scanIns), 4)
+		assertStrAttrs(
+			t, client.scanIns[1].ExclusiveStartKey, "Username", "bob",
+		)
+
+		// each item is put into the single table with its keys
+		assert.Equal(t.Fatal, len(client.putIns), 4)
+		for _, in := range client.putIns {
+			assert.Equal(t.Error, aws.ToString(in.TableName), "goteam")
+		}
+		assertStrAttrs(t, client.putIns[0].Item, "PK", "USER#bob")
+		assertStrAttrs(t, client.putIns[1].Item, "PK", "USER#tom")
+		assertStrAttrs(t, client.putIns[2].Item, "PK", "TEAM#team1")
+		assertStrAttrs(t, client.putIns[3].Item,
+			"PK", "TEAM#team1", "SK", "TASK#t1", "GSI1PK", "BOARD#b1",
+		)
+	})
+}
@@ -0,0 +1,225 @@
+// Package singletbl contains code to store the users, teams, and tasks in a
+// single DynamoDB table instead of the user, team, and task tables.
+//
+// Client sits between the table accessors in usertbl, teamtbl, and tasktbl and
+// the DynamoDB client. It redirects the calls addressed to their tables to the
+// single table, keying the items as below. Items keep all of their attributes,
+// so condition expressions on them work as they do in their former tables.
+//
+//	entity  PK                SK           GSI1PK            GSI1SK
+//	user    USER#<username>   USER         USER              <username>
+//	team    TEAM#<teamID>     TEAM
+//	task    TEAM#<teamID>     TASK#<id>    BOARD#<boardID>   TASK#<id>
+//
+//	entity  GSI2PK            GSI2SK
+//	user    EMAIL#<email>     USER#<username>
+//
+// A team and its tasks share a partition so that they can be read in a single
+// query, and the tasks of a board are read through GSI1. Only the users with
+// an email address are in GSI2, which they are read by email through.
+package singletbl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// EnvTableName is the name of the environment variable to retrieve the single
+// table's name from. The user, team, and task tables are used if it is empty.
+const EnvTableName = "SINGLE_TABLE_NAME"
+
+// Names of the environment variables to retrieve the names of the tables that
+// are stored in the single table from. The calls that name these tables are
+// redirected to the single table, so they must still be set, and they are
+// read from during migration.
+const (
+	envUserTableName = "USER_TABLE_NAME"
+	envTeamTableName = "TEAM_TABLE_NAME"
+	envTaskTableName = "TASK_TABLE_NAME"
+)
+
+// Names of the attributes and the index that key the items in the single
+// table.
+const (
+	attrPK     = "PK"
+	attrSK     = "SK"
+	attrGSI1PK = "GSI1PK"
+	attrGSI1SK = "GSI1SK"
+	indexGSI1  = "GSI1"
+	attrGSI2PK = "GSI2PK"
+	attrGSI2SK = "GSI2SK"
+	indexGSI2  = "GSI2"
+)
+
+// Prefixes and sort keys of the single table's keys.
+const (
+	prefixUser  = "USER#"
+	prefixTeam  = "TEAM#"
+	prefixTask  = "TASK#"
+	prefixBoard = "BOARD#"
+	prefixEmail = "EMAIL#"
+	skUser      = "USER"
+	skTeam      = "TEAM"
+	gsi1PKUser  = "USER"
+)
+
+// entity defines the kinds of items that are stored in the single table.
+type entity int
+
+const (
+	entityNone entity = iota
+	entityUser
+	entityTeam
+	entityTask
+)
+
+// entityOf returns the kind of the items in the table with the given name, or
+// entityNone if the table is not stored in the single table.
+func entityOf(tableName *string) entity {
+	name := aws.ToString(tableName)
+	if name == "" {
+		return entityNone
+	}
+	switch name {
+	case os.Getenv(envUserTableName):
+		return entityUser
+	case os.Getenv(envTeamTableName):
+		return entityTeam
+	case os.Getenv(envTaskTableName):
+		return entityTask
+	default:
+		return entityNone
+	}
+}
+
+// SourceTable returns the name of the table that the item with the given key
+// in the single table would be stored in otherwise, or "" if the key is not
+// of a user, team, or task. It is used for telling which entity a change
+// record from the single table's stream is of.
+func SourceTable(key map[string]types.AttributeValue) string {
+	sk, err := strAttr(key, attrSK)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case sk == skUser:
+		return os.Getenv(envUserTableName)
+	case sk == skTeam:
+		return os.Getenv(envTeamTableName)
+	case strings.HasPrefix(sk, prefixTask):
+		return os.Getenv(envTaskTableName)
+	default:
+		return ""
+	}
+}
+
+// primaryKey returns the key in the single table of the item of the given
+// entity that has the given attributes, which must include the attributes that
+// keyed it in its former table.
+func primaryKey(
+	e entity, attrs map[string]types.AttributeValue,
+) (map[string]types.AttributeValue, error) {
+	switch e {
+	case entityUser:
+		username, err := strAttr(attrs, "Username")
+		if err != nil {
+			return nil, err
+		}
+		return strAttrs(attrPK, prefixUser+username, attrSK, skUser), nil
+	case entityTeam:
+		id, err := strAttr(attrs, "ID")
+		if err != nil {
+			return nil, err
+		}
+		return strAttrs(attrPK, prefixTeam+id, attrSK, skTeam), nil
+	case entityTask:
+		teamID, err := strAttr(attrs, "TeamID")
+		if err != nil {
+			return nil, err
+		}
+		id, err := strAttr(attrs, "ID")
+		if err != nil {
+			return nil, err
+		}
+		return strAttrs(attrPK, prefixTeam+teamID, attrSK, prefixTask+id), nil
+	default:
+		return nil, fmt.Errorf("entity %d is not in the single table", e)
+	}
+}
+
+// indexKey returns the GSI1 and GSI2 keys of the item of the given entity that
+// has the given attributes, or nil if the entity is not indexed.
+func indexKey(
+	e entity, attrs map[string]types.AttributeValue,
+) (map[string]types.AttributeValue, error) {
+	switch e {
+	case entityUser:
+		username, err := strAttr(attrs, "Username")
+		if err != nil {
+			return nil, err
+		}
+		key := strAttrs(attrGSI1PK, gsi1PKUser, attrGSI1SK, username)
+		if email, err := strAttr(attrs, "Email"); err == nil {
+			key[attrGSI2PK] = &types.AttributeValueMemberS{
+				Value: prefixEmail + email,
+			}
+			key[attrGSI2SK] = &types.AttributeValueMemberS{
+				Value: prefixUser + username,
+			}
+		}
+		return key, nil
+	case entityTask:
+		boardID, err := strAttr(attrs, "BoardID")
+		if err != nil {
+			return nil, err
+		}
+		id, err := strAttr(attrs, "ID")
+		if err != nil {
+			return nil, err
+		}
+		return strAttrs(
+			attrGSI1PK, prefixBoard+boardID, attrGSI1SK, prefixTask+id,
+		), nil
+	default:
+		return nil, nil
+	}
+}
+
+// strAttr returns the value of the string attribute with the given name.
+func strAttr(
+	attrs map[string]types.AttributeValue, name string,
+) (string, error) {
+	s, ok := attrs[name].(*types.AttributeValueMemberS)
+	if !ok || s.Value == "" {
+		return "", fmt.Errorf("missing key attribute %q", name)
+	}
+	return s.Value, nil
+}
+
+// strAttrs returns the attributes with the given names and string values,
+// given in pairs.
+func strAttrs(nameValues ...string) map[string]types.AttributeValue {
+	attrs := make(map[string]types.AttributeValue, len(nameValues)/2)
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		attrs[nameValues[i]] = &types.AttributeValueMemberS{
+			Value: nameValues[i+1],
+		}
+	}
+	return attrs
+}
+
+// stripKeys removes the single table's key attributes from the given items so
+// that they are read as they were in their former tables.
+func stripKeys(items ...map[string]types.AttributeValue) {
+	for _, item := range items {
+		for _, name := range []string{
+			attrPK, attrSK, attrGSI1PK, attrGSI1SK, attrGSI2PK, attrGSI2SK,
+		} {
+			delete(item, name)
+		}
+	}
+}
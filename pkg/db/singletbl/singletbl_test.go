@@ -0,0 +1,31 @@
+//go:build utest
+
+package singletbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestSourceTable(t *testing.T) {
+	setTableNames(t)
+
+	for _, c := range []struct {
+		name string
+		sk   string
+		want string
+	}{
+		{name: "User", sk: "USER", want: "goteam-user"},
+		{name: "Team", sk: "TEAM", want: "goteam-team"},
+		{name: "Task", sk: "TASK#task1", want: "goteam-task"},
+		{name: "Unknown", sk: "BOARD#board1", want: ""},
+		{name: "NoSK", sk: "", want: ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := SourceTable(strAttrs("PK", "TEAM#team1", "SK", c.sk))
+
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// errAttrType means that an attribute value had none of the known types.
+var errAttrType = errors.New("unknown attribute type")
+
+// attrJSON defines the JSON encoding of attribute values in change records,
+// e.g. {"S": "foo"} or {"L": [{"N": "1"}]}, which sets one of its fields.
+type attrJSON struct {
+	S    *string
+	N    *string
+	B    []byte
+	BOOL *bool
+	NULL *bool
+	SS   []string
+	NS   []string
+	BS   [][]byte
+	L    []json.RawMessage
+	M    map[string]json.RawMessage
+}
+
+// decodeAttr decodes the given attribute value from its JSON encoding in
+// change records.
+func decodeAttr(raw json.RawMessage) (types.AttributeValue, error) {
+	var v attrJSON
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case v.S != nil:
+		return &types.AttributeValueMemberS{Value: *v.S}, nil
+	case v.N != nil:
+		return &types.AttributeValueMemberN{Value: *v.N}, nil
+	case v.B != nil:
+		return &types.AttributeValueMemberB{Value: v.B}, nil
+	case v.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *v.BOOL}, nil
+	case v.NULL != nil:
+		return &types.AttributeValueMemberNULL{Value: *v.NULL}, nil
+	case v.SS != nil:
+		return &types.AttributeValueMemberSS{Value: v.SS}, nil
+	case v.NS != nil:
+		return &types.AttributeValueMemberNS{Value: v.NS}, nil
+	case v.BS != nil:
+		return &types.AttributeValueMemberBS{Value: v.BS}, nil
+	case v.L != nil:
+		l := make([]types.AttributeValue, len(v.L))
+		for i, raw := range v.L {
+			av, err := decodeAttr(raw)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = av
+		}
+		return &types.AttributeValueMemberL{Value: l}, nil
+	case v.M != nil:
+		m, err := decodeItem(v.M)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	default:
+		return nil, errAttrType
+	}
+}
@@ -0,0 +1,18 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db/cache"
+)
+
+// InvalidateTeams returns a Handler that evicts the teams that are changed
+// from the given cache, including by the writes of other instances.
+func InvalidateTeams(inv cache.Invalidator) Handler {
+	return func(_ context.Context, e Event) error {
+		if e.Entity == EntityTeam {
+			inv.Invalidate(e.TeamID())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,203 @@
+package stream
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/kxplxn/goteam/pkg/awsjson"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+const (
+	// pollInterval is how long a Poller waits between reads of the shards.
+	pollInterval = time.Second
+
+	// describeInterval is how often a Poller looks for new shards, which
+	// DynamoDB opens every few hours and when it splits busy ones.
+	describeInterval = time.Minute
+
+	// retryInterval is how long a Poller waits before trying again after
+	// failing to look for shards.
+	retryInterval = 5 * time.Second
+)
+
+// Poller reads the change records from a DynamoDB stream and passes them to a
+// Consumer. It keeps its position in memory, so it starts from the latest
+// records when it is started and misses the changes made while it was stopped.
+// Use a Lambda function for delivery that survives restarts.
+type Poller struct {
+	streamARN string
+	table     string
+	api       awsjson.Client
+	consumer  Consumer
+	clock     clock.Clock
+	log       log.Errorer
+	sleep     func(context.Context, time.Duration) error
+}
+
+// NewPoller creates and returns a new Poller that reads the stream with the
+// given ARN through the DynamoDB Streams API at the given endpoint, e.g.
+// https://streams.dynamodb.<region>.amazonaws.com/, signing its requests for
+// the given region with the given credentials.
+func NewPoller(
+	streamARN, endpoint, region string,
+	creds aws.CredentialsProvider,
+	clk clock.Clock,
+	client *http.Client,
+	consumer Consumer,
+	log log.Errorer,
+) *Poller {
+	return &Poller{
+		streamARN: streamARN,
+		table:     TableOf(streamARN),
+		api: awsjson.NewClient(
+			endpoint, "DynamoDBStreams_20120810", "dynamodb", region, creds,
+			clk, client,
+		),
+		consumer: consumer,
+		clock:    clk,
+		log:      log,
+		sleep:    clock.Sleep,
+	}
+}
+
+// Endpoint returns the endpoint of the DynamoDB Streams API in the given
+// region, or the given DynamoDB endpoint if it is set, as the local instances
+// that it points to serve both APIs.
+func Endpoint(region, dynamoDBEndpoint string) string {
+	if dynamoDBEndpoint != "" {
+		return dynamoDBEndpoint
+	}
+	return "https://streams.dynamodb." + region + ".amazonaws.com/"
+}
+
+// shard defines a shard of a stream as it is returned by DescribeStream.
+type shard struct {
+	ShardId             string
+	SequenceNumberRange struct{ EndingSequenceNumber string }
+}
+
+// Run reads the records from the stream and passes them to the consumer until
+// the given context is done. The shards that are open when it starts are read
+// from their latest records, and those that are opened later from their start
+// so that no records are missed. A batch of records whose consumption fails is
+// read again, so the handlers must be idempotent.
+func (p *Poller) Run(ctx context.Context) {
+	var (
+		seen      = map[string]bool{}
+		iters     = map[string]string{}
+		described time.Time
+	)
+	for ctx.Err() == nil {
+		if p.clock.Now().Sub(described) >= describeInterval {
+			if err := p.describe(
+				ctx, seen, iters, described.IsZero(),
+			); err != nil {
+				p.log.Error("failed to describe stream:", err)
+				if err := p.sleep(ctx, retryInterval); err != nil {
+					return
+				}
+				continue
+			}
+			described = p.clock.Now()
+		}
+
+		for id, iter := range iters {
+			next, err := p.read(ctx, iter)
+			if err != nil {
+				p.log.Error("failed to read shard", id, "of stream:", err)
+				continue
+			}
+			if next == "" {
+				delete(iters, id)
+			} else {
+				iters[id] = next
+			}
+		}
+
+		if err := p.sleep(ctx, pollInterval); err != nil {
+			return
+		}
+	}
+}
+
+// describe adds iterators for the shards of the stream that have not been seen
+// before. If first is true, the open shards are read from their latest records
+// and the closed ones are skipped.
+func (p *Poller) describe(
+	ctx context.Context, seen map[string]bool, iters map[string]string,
+	first bool,
+) error {
+	var startID string
+	for {
+		in := map[string]any{"StreamArn": p.streamARN}
+		if startID != "" {
+			in["ExclusiveStartShardId"] = startID
+		}
+		var out struct {
+			StreamDescription struct {
+				Shards               []shard
+				LastEvaluatedShardId string
+			}
+		}
+		if err := p.api.Call(ctx, "DescribeStream", in, &out); err != nil {
+			return err
+		}
+
+		for _, s := range out.StreamDescription.Shards {
+			if seen[s.ShardId] {
+				continue
+			}
+			iterType := "TRIM_HORIZON"
+			if first {
+				if s.SequenceNumberRange.EndingSequenceNumber != "" {
+					seen[s.ShardId] = true
+					continue
+				}
+				iterType = "LATEST"
+			}
+
+			var iterOut struct{ ShardIterator string }
+			if err := p.api.Call(ctx, "GetShardIterator", map[string]any{
+				"StreamArn":         p.streamARN,
+				"ShardId":           s.ShardId,
+				"ShardIteratorType": iterType,
+			}, &iterOut); err != nil {
+				return err
+			}
+			seen[s.ShardId] = true
+			iters[s.ShardId] = iterOut.ShardIterator
+		}
+
+		startID = out.StreamDescription.LastEvaluatedShardId
+		if startID == "" {
+			return nil
+		}
+	}
+}
+
+// read passes the records at the given shard iterator to the consumer and
+// returns the iterator to read the next records from, or "" if the shard is
+// closed and has no more records. The given iterator is returned if the
+// records fail to be consumed so that they are read again.
+func (p *Poller) read(ctx context.Context, iter string) (string, error) {
+	var out struct {
+		Records           []Record
+		NextShardIterator string
+	}
+	if err := p.api.Call(ctx, "GetRecords", map[string]any{
+		"ShardIterator": iter,
+	}, &out); err != nil {
+		return iter, err
+	}
+	for _, r := range out.Records {
+		if err := p.consumer.Consume(ctx, p.table, r); err != nil {
+			return iter, err
+		}
+	}
+	return out.NextShardIterator, nil
+}
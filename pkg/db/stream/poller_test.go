@@ -0,0 +1,130 @@
+//go:build utest
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPoller tests the Run method of Poller to assert that it reads the open
+// shards from their latest records and the shards that are opened later from
+// their start, passing their records to the consumer.
+func TestPoller(t *testing.T) {
+	setTableNames(t)
+
+	var (
+		mu        sync.Mutex
+		describes int
+		iterTypes = map[string]string{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			var in map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&in)
+
+			action := strings.TrimPrefix(
+				r.Header.Get("X-Amz-Target"), "DynamoDBStreams_20120810.",
+			)
+			switch action {
+			case "DescribeStream":
+				describes++
+				shards := `{"ShardId":"s0","SequenceNumberRange":` +
+					`{"EndingSequenceNumber":"5"}},{"ShardId":"s1"}`
+				if describes > 1 {
+					shards += `,{"ShardId":"s2","ParentShardId":"s1"}`
+				}
+				_, _ = w.Write([]byte(
+					`{"StreamDescription":{"Shards":[` + shards + `]}}`,
+				))
+			case "GetShardIterator":
+				iterTypes[in["ShardId"]] = in["ShardIteratorType"]
+				_, _ = w.Write([]byte(
+					`{"ShardIterator":"` + in["ShardId"] + `/0"}`,
+				))
+			case "GetRecords":
+				shardID, pos, _ := strings.Cut(in["ShardIterator"], "/")
+				if pos != "0" {
+					// the shard is closed after its first record
+					_, _ = w.Write([]byte(`{"Records":[]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"Records":[{"eventName":"MODIFY",` +
+					`"dynamodb":{"Keys":{"ID":{"S":"` + shardID + `"}},` +
+					`"NewImage":{"ID":{"S":"` + shardID + `"}}}}],` +
+					`"NextShardIterator":"` + shardID + `/1"}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		},
+	))
+	defer srv.Close()
+
+	var got []string
+	clk := &clock.Fake{Time: time.Now()}
+	sut := NewPoller(
+		"arn:aws:dynamodb:eu-west-2:123:table/goteam-team/stream/2024",
+		srv.URL,
+		"eu-west-2",
+		credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		clk,
+		srv.Client(),
+		NewConsumer(func(_ context.Context, e Event) error {
+			got = append(got, e.TeamID())
+			return nil
+		}),
+		&log.FakeErrorer{},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+	sut.sleep = func(_ context.Context, d time.Duration) error {
+		assert.Equal(t.Error, d, pollInterval)
+		polls++
+		if polls == 4 {
+			cancel()
+			return context.Canceled
+		}
+		clk.Advance(describeInterval / 2)
+		return nil
+	}
+
+	sut.Run(ctx)
+
+	assert.Equal(t.Error, describes, 2)
+	assert.Equal(t.Error, iterTypes["s0"], "")
+	assert.Equal(t.Error, iterTypes["s1"], "LATEST")
+	assert.Equal(t.Error, iterTypes["s2"], "TRIM_HORIZON")
+	assert.AllEqual(t.Error, got, []string{"s1", "s2"})
+}
+
+// TestTableOf tests the TableOf function to assert that it returns the name of
+// the table from stream ARNs.
+func TestTableOf(t *testing.T) {
+	for _, c := range []struct{ arn, want string }{
+		{
+			arn:  "arn:aws:dynamodb:eu-west-2:123:table/goteam/stream/2024",
+			want: "goteam",
+		},
+		{
+			arn:  "arn:aws:dynamodb:eu-west-2:123:table/goteam-task",
+			want: "goteam-task",
+		},
+		{arn: "", want: ""},
+	} {
+		assert.Equal(t.Error, TableOf(c.arn), c.want)
+	}
+}
@@ -0,0 +1,250 @@
+// Package stream contains code for consuming the change records of the
+// DynamoDB streams on the user, team, and task tables, or on the single table,
+// as domain events. Since the records are of the writes to the tables, the
+// systems that are derived from them stay consistent with the tables even when
+// the writes bypass the API, such as those of the admin CLI and migrations.
+//
+// The records are received either by a Lambda function that Consumer.Lambda is
+// the handler of, or by a Poller that reads them from the stream.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// Names of the environment variables to retrieve the names of the tables that
+// the change records are of from.
+const (
+	envUserTableName = "USER_TABLE_NAME"
+	envTeamTableName = "TEAM_TABLE_NAME"
+	envTaskTableName = "TASK_TABLE_NAME"
+)
+
+// Entity is the kind of the item that an event is of.
+type Entity string
+
+// Entities that events can be of.
+const (
+	EntityUser Entity = "user"
+	EntityTeam Entity = "team"
+	EntityTask Entity = "task"
+)
+
+// Op is the kind of change that an event is of.
+type Op string
+
+// Ops that events can be of.
+const (
+	OpCreated Op = "created"
+	OpUpdated Op = "updated"
+	OpDeleted Op = "deleted"
+)
+
+// Event defines a change to a user, team, or task.
+type Event struct {
+	Entity Entity
+	Op     Op
+
+	// Old and New are the item before and after the change as a usertbl.User,
+	// teamtbl.Team, or tasktbl.Task by Entity. Old is nil for created items
+	// and New is nil for deleted ones.
+	Old, New any
+}
+
+// TeamID returns the ID of the team that the event's item belongs to, or "" if
+// it is of a user who is not on a team.
+func (e Event) TeamID() string {
+	item := e.New
+	if item == nil {
+		item = e.Old
+	}
+	switch item := item.(type) {
+	case usertbl.User:
+		return item.TeamID
+	case teamtbl.Team:
+		return item.ID
+	case tasktbl.Task:
+		return item.TeamID
+	default:
+		return ""
+	}
+}
+
+// Record defines a change record as it is read from a stream, and as it is
+// passed to Lambda functions.
+type Record struct {
+	EventName string `json:"eventName"`
+
+	// EventSourceARN is the ARN of the stream the record is from. It is only
+	// set on the records passed to Lambda functions.
+	EventSourceARN string `json:"eventSourceARN,omitempty"`
+
+	DynamoDB struct {
+		Keys           map[string]json.RawMessage `json:"Keys"`
+		NewImage       map[string]json.RawMessage `json:"NewImage"`
+		OldImage       map[string]json.RawMessage `json:"OldImage"`
+		SequenceNumber string                     `json:"SequenceNumber"`
+	} `json:"dynamodb"`
+}
+
+// LambdaEvent defines the event that Lambda functions are invoked with by a
+// DynamoDB stream.
+type LambdaEvent struct {
+	Records []Record `json:"Records"`
+}
+
+// Handler describes a function that updates a derived system with an event.
+type Handler func(context.Context, Event) error
+
+// Consumer can be used to convert change records into events and pass them to
+// handlers.
+type Consumer struct{ handlers []Handler }
+
+// NewConsumer creates and returns a new Consumer that passes the events to the
+// given handlers in order.
+func NewConsumer(handlers ...Handler) Consumer {
+	return Consumer{handlers: handlers}
+}
+
+// Lambda passes the events of the records that a Lambda function is invoked
+// with to the handlers. Its signature is that of a Lambda handler, so it can
+// be passed to lambda.Start. It returns the first error so that Lambda retries
+// the batch.
+func (c Consumer) Lambda(ctx context.Context, e LambdaEvent) error {
+	for _, r := range e.Records {
+		if err := c.Consume(ctx, TableOf(r.EventSourceARN), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Consume passes the event of the given record from the stream on the table
+// with the given name to the handlers. Records of other tables, and of items
+// in the single table that are not users, teams, or tasks, are skipped.
+func (c Consumer) Consume(ctx context.Context, table string, r Record) error {
+	e, ok, err := toEvent(table, r)
+	if err != nil {
+		return fmt.Errorf("record %s: %w", r.DynamoDB.SequenceNumber, err)
+	} else if !ok {
+		return nil
+	}
+	for _, h := range c.handlers {
+		if err := h(ctx, e); err != nil {
+			return fmt.Errorf("record %s: %w", r.DynamoDB.SequenceNumber, err)
+		}
+	}
+	return nil
+}
+
+// TableOf returns the name of the table that the stream with the given ARN is
+// on, e.g. goteam-task for arn:aws:dynamodb:<region>:<account>:table/
+// goteam-task/stream/<label>.
+func TableOf(streamARN string) string {
+	_, rest, _ := strings.Cut(streamARN, ":table/")
+	table, _, _ := strings.Cut(rest, "/stream/")
+	return table
+}
+
+// toEvent converts the given record from the stream on the table with the
+// given name into an event, returning false if it is not of a user, team, or
+// task.
+func toEvent(table string, r Record) (Event, bool, error) {
+	keys, err := decodeItem(r.DynamoDB.Keys)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if table != "" && table == os.Getenv(singletbl.EnvTableName) {
+		table = singletbl.SourceTable(keys)
+	}
+
+	var e Event
+	switch table {
+	case "":
+		return Event{}, false, nil
+	case os.Getenv(envUserTableName):
+		e.Entity = EntityUser
+	case os.Getenv(envTeamTableName):
+		e.Entity = EntityTeam
+	case os.Getenv(envTaskTableName):
+		e.Entity = EntityTask
+	default:
+		return Event{}, false, nil
+	}
+
+	switch r.EventName {
+	case "INSERT":
+		e.Op = OpCreated
+	case "MODIFY":
+		e.Op = OpUpdated
+	case "REMOVE":
+		e.Op = OpDeleted
+	default:
+		return Event{}, false, fmt.Errorf(
+			"unknown event name %q", r.EventName,
+		)
+	}
+
+	if e.Old, err = decodeEntity(e.Entity, r.DynamoDB.OldImage); err != nil {
+		return Event{}, false, err
+	}
+	if e.New, err = decodeEntity(e.Entity, r.DynamoDB.NewImage); err != nil {
+		return Event{}, false, err
+	}
+	return e, true, nil
+}
+
+// decodeEntity decodes the given image into the type of the given entity, or
+// returns nil if the image is empty.
+func decodeEntity(
+	entity Entity, image map[string]json.RawMessage,
+) (any, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+	item, err := decodeItem(image)
+	if err != nil {
+		return nil, err
+	}
+	switch entity {
+	case EntityUser:
+		var user usertbl.User
+		err = attributevalue.UnmarshalMap(item, &user)
+		return user, err
+	case EntityTeam:
+		var team teamtbl.Team
+		err = attributevalue.UnmarshalMap(item, &team)
+		return team, err
+	default:
+		var task tasktbl.Task
+		err = attributevalue.UnmarshalMap(item, &task)
+		return task, err
+	}
+}
+
+// decodeItem decodes the given item from its JSON encoding in change records.
+func decodeItem(
+	item map[string]json.RawMessage,
+) (map[string]types.AttributeValue, error) {
+	attrs := make(map[string]types.AttributeValue, len(item))
+	for name, raw := range item {
+		av, err := decodeAttr(raw)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		attrs[name] = av
+	}
+	return attrs, nil
+}
@@ -0,0 +1,215 @@
+//go:build utest
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/singletbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// setTableNames sets the names of the tables for the duration of the test.
+func setTableNames(t *testing.T) {
+	t.Setenv(singletbl.EnvTableName, "goteam")
+	t.Setenv(envUserTableName, "goteam-user")
+	t.Setenv(envTeamTableName, "goteam-team")
+	t.Setenv(envTaskTableName, "goteam-task")
+}
+
+// newRecord returns the record decoded from the given JSON.
+func newRecord(t *testing.T, s string) Record {
+	var r Record
+	assert.Nil(t.Fatal, json.Unmarshal([]byte(s), &r))
+	return r
+}
+
+// TestConsumer tests the Consume method of Consumer to assert that it converts
+// the records of the user, team, and task tables, and of the single table,
+// into events for its handlers.
+func TestConsumer(t *testing.T) {
+	setTableNames(t)
+	ctx := context.Background()
+
+	var got []Event
+	sut := NewConsumer(func(_ context.Context, e Event) error {
+		got = append(got, e)
+		return nil
+	})
+
+	for _, c := range []struct {
+		name     string
+		table    string
+		record   string
+		wantOK   bool
+		wantEvt  Event
+		wantTeam string
+	}{
+		{
+			name:  "UserCreated",
+			table: "goteam-user",
+			record: `{"eventName":"INSERT","dynamodb":{` +
+				`"Keys":{"Username":{"S":"bob"}},` +
+				`"NewImage":{"Username":{"S":"bob"},"TeamID":{"S":"t1"},` +
+				`"IsAdmin":{"BOOL":true}}}}`,
+			wantOK: true,
+			wantEvt: Event{
+				Entity: EntityUser,
+				Op:     OpCreated,
+				New: usertbl.User{
+					Username: "bob", TeamID: "t1", IsAdmin: true,
+				},
+			},
+			wantTeam: "t1",
+		},
+		{
+			name:  "TaskDeleted",
+			table: "goteam-task",
+			record: `{"eventName":"REMOVE","dynamodb":{` +
+				`"Keys":{"ID":{"S":"k1"}},` +
+				`"OldImage":{"ID":{"S":"k1"},"TeamID":{"S":"t1"},` +
+				`"Order":{"N":"2"}}}}`,
+			wantOK: true,
+			wantEvt: Event{
+				Entity: EntityTask,
+				Op:     OpDeleted,
+				Old:    tasktbl.Task{ID: "k1", TeamID: "t1", Order: 2},
+			},
+			wantTeam: "t1",
+		},
+		{
+			name:  "SingleTableTeamUpdated",
+			table: "goteam",
+			record: `{"eventName":"MODIFY","dynamodb":{` +
+				`"Keys":{"PK":{"S":"TEAM#t1"},"SK":{"S":"TEAM"}},` +
+				`"OldImage":{"PK":{"S":"TEAM#t1"},"SK":{"S":"TEAM"},` +
+				`"ID":{"S":"t1"},"Members":{"L":[{"S":"bob"}]}},` +
+				`"NewImage":{"PK":{"S":"TEAM#t1"},"SK":{"S":"TEAM"},` +
+				`"ID":{"S":"t1"},"Members":{"L":[]}}}}`,
+			wantOK: true,
+			wantEvt: Event{
+				Entity: EntityTeam,
+				Op:     OpUpdated,
+				Old:    teamtbl.Team{ID: "t1", Members: []string{"bob"}},
+				New:    teamtbl.Team{ID: "t1", Members: []string{}},
+			},
+			wantTeam: "t1",
+		},
+		{
+			name:  "OtherTable",
+			table: "goteam-trash",
+			record: `{"eventName":"INSERT","dynamodb":{` +
+				`"Keys":{"ID":{"S":"b1"}}}}`,
+			wantOK: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got = nil
+
+			err := sut.Consume(ctx, c.table, newRecord(t, c.record))
+
+			assert.Nil(t.Fatal, err)
+			if !c.wantOK {
+				assert.Equal(t.Error, len(got), 0)
+				return
+			}
+			assert.Equal(t.Fatal, len(got), 1)
+			assert.DeepEqual(t.Error, got[0], c.wantEvt)
+			assert.Equal(t.Error, got[0].TeamID(), c.wantTeam)
+		})
+	}
+}
+
+// TestConsumerErr tests the Consume method of Consumer to assert that it
+// returns the errors of decoding records and of its handlers.
+func TestConsumerErr(t *testing.T) {
+	setTableNames(t)
+	ctx := context.Background()
+	errA := errors.New("handler failed")
+	sut := NewConsumer(func(context.Context, Event) error { return errA })
+
+	t.Run("Handler", func(t *testing.T) {
+		err := sut.Consume(ctx, "goteam-team", newRecord(t,
+			`{"eventName":"INSERT","dynamodb":{"SequenceNumber":"7",`+
+				`"Keys":{"ID":{"S":"t1"}},"NewImage":{"ID":{"S":"t1"}}}}`,
+		))
+
+		assert.ErrIs(t.Error, err, errA)
+		assert.Equal(t.Error, err.Error(), "record 7: handler failed")
+	})
+
+	t.Run("AttrType", func(t *testing.T) {
+		err := sut.Consume(ctx, "goteam-team", newRecord(t,
+			`{"eventName":"INSERT","dynamodb":{`+
+				`"Keys":{"ID":{"X":"t1"}}}}`,
+		))
+
+		assert.ErrIs(t.Error, err, errAttrType)
+	})
+
+	t.Run("EventName", func(t *testing.T) {
+		err := sut.Consume(ctx, "goteam-team", newRecord(t,
+			`{"eventName":"TRUNCATE","dynamodb":{"Keys":{"ID":{"S":"t1"}}}}`,
+		))
+
+		assert.True(t.Error, err != nil)
+	})
+}
+
+// TestConsumerLambda tests the Lambda method of Consumer to assert that it
+// tells the table of each record from the ARN of its stream.
+func TestConsumerLambda(t *testing.T) {
+	setTableNames(t)
+
+	var got []Entity
+	sut := NewConsumer(func(_ context.Context, e Event) error {
+		got = append(got, e.Entity)
+		return nil
+	})
+	var e LambdaEvent
+	assert.Nil(t.Fatal, json.Unmarshal([]byte(`{"Records":[`+
+		`{"eventName":"INSERT","eventSourceARN":`+
+		`"arn:aws:dynamodb:eu-west-2:123:table/goteam-team/stream/2024",`+
+		`"dynamodb":{"Keys":{"ID":{"S":"t1"}},"NewImage":{"ID":{"S":"t1"}}}},`+
+		`{"eventName":"INSERT","eventSourceARN":`+
+		`"arn:aws:dynamodb:eu-west-2:123:table/goteam-user/stream/2024",`+
+		`"dynamodb":{"Keys":{"Username":{"S":"bob"}},`+
+		`"NewImage":{"Username":{"S":"bob"}}}}`+
+		`]}`), &e))
+
+	err := sut.Lambda(context.Background(), e)
+
+	assert.Nil(t.Fatal, err)
+	assert.AllEqual(t.Error, got, []Entity{EntityTeam, EntityUser})
+}
+
+// fakeInvalidator is a cache.Invalidator that records the keys it is called
+// with.
+type fakeInvalidator struct{ keys []string }
+
+func (f *fakeInvalidator) Invalidate(key string) {
+	f.keys = append(f.keys, key)
+}
+
+// TestInvalidateTeams tests the InvalidateTeams function to assert that the
+// handler it returns only invalidates the teams that are changed.
+func TestInvalidateTeams(t *testing.T) {
+	inv := &fakeInvalidator{}
+	sut := InvalidateTeams(inv)
+
+	for _, e := range []Event{
+		{Entity: EntityTeam, Op: OpUpdated, New: teamtbl.Team{ID: "t1"}},
+		{Entity: EntityTask, Op: OpCreated, New: tasktbl.Task{TeamID: "t2"}},
+		{Entity: EntityTeam, Op: OpDeleted, Old: teamtbl.Team{ID: "t3"}},
+	} {
+		assert.Nil(t.Fatal, sut(context.Background(), e))
+	}
+
+	assert.AllEqual(t.Error, inv.keys, []string{"t1", "t3"})
+}
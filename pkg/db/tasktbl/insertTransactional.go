@@ -0,0 +1,28 @@
+package tasktbl
+
+import (
+	"context"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// TransactionalInserter can be used to insert multiple new tasks into the task
+// table in transactions.
+type TransactionalInserter struct{ tw db.DynamoTransactWriter }
+
+// NewTransactionalInserter creates and returns a new TransactionalInserter.
+func NewTransactionalInserter(
+	tw db.DynamoTransactWriter,
+) TransactionalInserter {
+	return TransactionalInserter{tw: tw}
+}
+
+// Insert inserts multiple new tasks into the task table in chunks of up to 100,
+// each chunk in a single transaction. It returns db.ErrDupKey if a task with
+// the same ID already exists, in which case the chunks before the one
+// containing that task will have been inserted.
+func (i TransactionalInserter) Insert(ctx context.Context, tasks []Task) error {
+	return transactPut(
+		ctx, i.tw, tasks, "attribute_not_exists(ID)", db.ErrDupKey,
+	)
+}
@@ -0,0 +1,104 @@
+//go:build utest
+
+package tasktbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestTransactionalInserter(t *testing.T) {
+	tw := &db.FakeDynamoTransactWriter{}
+	sut := NewTransactionalInserter(tw)
+
+	errA := errors.New("failed to put item")
+	newTasks := func(n int) []Task {
+		tasks := make([]Task, n)
+		for i := range tasks {
+			tasks[i] = Task{ID: strconv.Itoa(i)}
+		}
+		return tasks
+	}
+
+	for _, c := range []struct {
+		name       string
+		tasks      []Task
+		twErr      error
+		wantErr    error
+		wantChunks []int
+	}{
+		{
+			name:       "Err",
+			tasks:      newTasks(1),
+			twErr:      errA,
+			wantErr:    errA,
+			wantChunks: []int{1},
+		},
+		{
+			name:  "ConditionalCheckFailed",
+			tasks: newTasks(1),
+			twErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr:    db.ErrDupKey,
+			wantChunks: []int{1},
+		},
+		{
+			name:  "TransactionCanceled",
+			tasks: newTasks(2),
+			twErr: &smithy.OperationError{
+				Err: &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed")},
+					},
+				},
+			},
+			wantErr:    db.ErrDupKey,
+			wantChunks: []int{2},
+		},
+		{
+			name:       "None",
+			tasks:      nil,
+			twErr:      nil,
+			wantErr:    nil,
+			wantChunks: []int{},
+		},
+		{
+			name:       "OK",
+			tasks:      newTasks(100),
+			twErr:      nil,
+			wantErr:    nil,
+			wantChunks: []int{100},
+		},
+		{
+			name:       "OKChunked",
+			tasks:      newTasks(250),
+			twErr:      nil,
+			wantErr:    nil,
+			wantChunks: []int{100, 100, 50},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			tw.Err = c.twErr
+			tw.Ins = nil
+
+			err := sut.Insert(context.Background(), c.tasks)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(tw.Ins), len(c.wantChunks))
+			for i, n := range c.wantChunks {
+				assert.Equal(t.Error, len(tw.Ins[i].TransactItems), n)
+			}
+		})
+	}
+}
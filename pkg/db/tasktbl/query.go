@@ -0,0 +1,70 @@
+package tasktbl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// queryAll runs the given query, following LastEvaluatedKey until all pages
+// are read, and returns the tasks from all pages. DynamoDB caps each page at
+// 1 MB, so a single Query call may not return all matching tasks.
+func queryAll(
+	ctx context.Context, queryer db.DynamoQueryer, in *dynamodb.QueryInput,
+) ([]Task, error) {
+	var tasks []Task
+	for {
+		out, err := queryer.Query(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Task
+		if err = attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return tasks, nil
+		}
+		next := *in
+		next.ExclusiveStartKey = out.LastEvaluatedKey
+		in = &next
+	}
+}
+
+// encodeCursor encodes the key a query stopped at into an opaque cursor that
+// the next page can be retrieved with. All task table and index keys are
+// strings.
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	var m map[string]string
+	if err := attributevalue.UnmarshalMap(key, &m); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor decodes a cursor created by encodeCursor back into the key to
+// start the next query after.
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, db.ErrInvalidCursor
+	}
+	var m map[string]string
+	if err = json.Unmarshal(b, &m); err != nil || len(m) == 0 {
+		return nil, db.ErrInvalidCursor
+	}
+	return attributevalue.MarshalMap(m)
+}
@@ -0,0 +1,145 @@
+package tasktbl
+
+import (
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/rank"
+)
+
+// maxRankLen is the length beyond which ranks generated for a column trigger a
+// rebalance of the column so that ranks do not grow indefinitely.
+const maxRankLen = 24
+
+// Less reports whether task a sorts before task b within the same column. Tasks
+// without a rank, which were created before ranks were introduced, sort before
+// those with one and are ordered by their Order.
+func Less(a, b Task) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.Order < b.Order
+}
+
+// SortByRank sorts the given tasks by board, column, and rank, and sets the
+// Order of each task to its index within its column so that API responses keep
+// their integer ordering.
+func SortByRank(tasks []Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.BoardID != b.BoardID {
+			return a.BoardID < b.BoardID
+		}
+		if a.ColNo != b.ColNo {
+			return a.ColNo < b.ColNo
+		}
+		return Less(a, b)
+	})
+
+	for i := range tasks {
+		if i > 0 &&
+			tasks[i].BoardID == tasks[i-1].BoardID &&
+			tasks[i].ColNo == tasks[i-1].ColNo {
+			tasks[i].Order = tasks[i-1].Order + 1
+		} else {
+			tasks[i].Order = 0
+		}
+	}
+}
+
+// RankAt returns a rank for a task that is to be inserted at index i of the
+// given column, which must be sorted by rank.
+func RankAt(col []Task, i int) string {
+	i = max(0, min(i, len(col)))
+
+	var lo, hi string
+	if i > 0 {
+		lo = col[i-1].Rank
+	}
+	if i < len(col) {
+		hi = col[i].Rank
+	}
+	if hi != "" && lo >= hi {
+		// the column has unranked tasks - place after them instead
+		hi = ""
+	}
+	return rank.Between(lo, hi)
+}
+
+// Rerank sets the ranks of the given tasks, which are all the tasks of a single
+// column in their new order. It keeps the stored ranks of the largest set of
+// tasks that are still in the same order relative to each other, so that moving
+// a single task only changes that task's rank. If any of the tasks has no
+// stored rank, or the ranks would grow too long, the column is rebalanced
+// instead.
+func Rerank(col []Task, stored map[string]Task) {
+	// find the stored rank of each task that has not changed columns
+	ranks := make([]string, len(col))
+	for i, t := range col {
+		s, ok := stored[t.ID]
+		if ok && s.Rank == "" {
+			Rebalance(col)
+			return
+		}
+		if ok && s.BoardID == t.BoardID && s.ColNo == t.ColNo {
+			ranks[i] = s.Rank
+		}
+	}
+
+	// find the longest subsequence of tasks whose stored ranks are still in
+	// ascending order - these are the tasks that did not move
+	lengths, prevs := make([]int, len(col)), make([]int, len(col))
+	last := -1
+	for i := range col {
+		prevs[i] = -1
+		if ranks[i] == "" {
+			continue
+		}
+		lengths[i] = 1
+		for j := 0; j < i; j++ {
+			if ranks[j] != "" && ranks[j] < ranks[i] &&
+				lengths[j]+1 > lengths[i] {
+				lengths[i], prevs[i] = lengths[j]+1, j
+			}
+		}
+		if last == -1 || lengths[i] > lengths[last] {
+			last = i
+		}
+	}
+	keep := make([]bool, len(col))
+	for i := last; i != -1; i = prevs[i] {
+		keep[i] = true
+	}
+
+	// keep the ranks of the tasks that did not move, and generate new ones
+	// between their neighbours for the ones that did
+	for i := range col {
+		if keep[i] {
+			col[i].Rank = ranks[i]
+			continue
+		}
+
+		var lo, hi string
+		if i > 0 {
+			lo = col[i-1].Rank
+		}
+		for j := i + 1; j < len(col); j++ {
+			if keep[j] {
+				hi = ranks[j]
+				break
+			}
+		}
+		col[i].Rank = rank.Between(lo, hi)
+		if len(col[i].Rank) > maxRankLen {
+			Rebalance(col)
+			return
+		}
+	}
+}
+
+// Rebalance assigns evenly spread ranks to the given tasks, which are all the
+// tasks of a single column in their order.
+func Rebalance(col []Task) {
+	for i, r := range rank.Spread(len(col)) {
+		col[i].Rank = r
+	}
+}
@@ -0,0 +1,114 @@
+//go:build utest
+
+package tasktbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSortByRank tests the SortByRank function to assert that it sorts tasks
+// by board, column, and rank, and renumbers their orders within each column.
+func TestSortByRank(t *testing.T) {
+	tasks := []Task{
+		{ID: "b0c1", BoardID: "b", ColNo: 1, Rank: "i"},
+		{ID: "a1c0", BoardID: "a", ColNo: 0, Rank: "r"},
+		{ID: "a0c0", BoardID: "a", ColNo: 0, Rank: "9"},
+		{ID: "a1c1", BoardID: "a", ColNo: 1, Rank: "", Order: 5},
+		{ID: "a0c1", BoardID: "a", ColNo: 1, Rank: "", Order: 2},
+		{ID: "a2c1", BoardID: "a", ColNo: 1, Rank: "1"},
+	}
+
+	SortByRank(tasks)
+
+	for i, want := range []struct {
+		id    string
+		order int
+	}{
+		{id: "a0c0", order: 0},
+		{id: "a1c0", order: 1},
+		{id: "a0c1", order: 0},
+		{id: "a1c1", order: 1},
+		{id: "a2c1", order: 2},
+		{id: "b0c1", order: 0},
+	} {
+		assert.Equal(t.Error, tasks[i].ID, want.id)
+		assert.Equal(t.Error, tasks[i].Order, want.order)
+	}
+}
+
+// TestRankAt tests the RankAt function to assert that it returns ranks that
+// sort at the given index of a column.
+func TestRankAt(t *testing.T) {
+	col := []Task{{Rank: "a"}, {Rank: "c"}}
+
+	for _, c := range []struct {
+		name string
+		i    int
+		want string
+	}{
+		{name: "Start", i: 0, want: "5"},
+		{name: "Middle", i: 1, want: "b"},
+		{name: "End", i: 2, want: "o"},
+		{name: "PastEnd", i: 10, want: "o"},
+		{name: "Negative", i: -1, want: "5"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, RankAt(col, c.i), c.want)
+		})
+	}
+
+	t.Run("Unranked", func(t *testing.T) {
+		assert.Equal(t.Error, RankAt([]Task{{}, {}}, 1), "i")
+	})
+}
+
+// TestRerank tests the Rerank function to assert that it only changes the
+// ranks of the tasks that moved.
+func TestRerank(t *testing.T) {
+	stored := map[string]Task{
+		"t0": {ID: "t0", ColNo: 0, Rank: "a"},
+		"t1": {ID: "t1", ColNo: 0, Rank: "b"},
+		"t2": {ID: "t2", ColNo: 0, Rank: "c"},
+		"t3": {ID: "t3", ColNo: 0, Rank: "d"},
+		"u0": {ID: "u0", ColNo: 1, Rank: "b"},
+	}
+
+	t.Run("MoveWithinColumn", func(t *testing.T) {
+		col := []Task{{ID: "t0"}, {ID: "t2"}, {ID: "t3"}, {ID: "t1"}}
+
+		Rerank(col, stored)
+
+		assert.Equal(t.Error, col[0].Rank, "a")
+		assert.Equal(t.Error, col[1].Rank, "c")
+		assert.Equal(t.Error, col[2].Rank, "d")
+		assert.True(t.Error, col[3].Rank > "d")
+	})
+
+	t.Run("MoveIntoColumn", func(t *testing.T) {
+		col := []Task{
+			{ID: "t0"}, {ID: "u0"}, {ID: "t1"}, {ID: "t2"}, {ID: "t3"},
+		}
+
+		Rerank(col, stored)
+
+		assert.Equal(t.Error, col[0].Rank, "a")
+		assert.True(t.Error, "a" < col[1].Rank && col[1].Rank < "b")
+		assert.Equal(t.Error, col[2].Rank, "b")
+		assert.Equal(t.Error, col[3].Rank, "c")
+		assert.Equal(t.Error, col[4].Rank, "d")
+	})
+
+	t.Run("Unranked", func(t *testing.T) {
+		col := []Task{{ID: "t1"}, {ID: "legacy"}, {ID: "t0"}}
+
+		Rerank(col, map[string]Task{
+			"t0": stored["t0"], "t1": stored["t1"], "legacy": {ID: "legacy"},
+		})
+
+		assert.Equal(t.Error, col[0].Rank, "9")
+		assert.Equal(t.Error, col[1].Rank, "i")
+		assert.Equal(t.Error, col[2].Rank, "r")
+	})
+}
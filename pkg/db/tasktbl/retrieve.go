@@ -12,20 +12,24 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 )
 
-// Retriever can be used to retrieve by ID a task from the task table.
+// Retriever can be used to retrieve by team ID and ID a task from the task
+// table.
 type Retriever struct{ iget db.DynamoItemGetter }
 
-// NewRetriever creates and returns a new Getter.
+// NewRetriever creates and returns a new Retriever.
 func NewRetriever(iget db.DynamoItemGetter) Retriever {
 	return Retriever{iget: iget}
 }
 
-// Retrieve retrieves by ID a task from the task table.
-func (r Retriever) Retrieve(ctx context.Context, id string) (Task, error) {
+// Retrieve retrieves by team ID and ID a task from the task table.
+func (r Retriever) Retrieve(
+	ctx context.Context, teamID, id string,
+) (Task, error) {
 	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(os.Getenv(tableName)),
 		Key: map[string]types.AttributeValue{
-			"ID": &types.AttributeValueMemberS{Value: id},
+			"TeamID": &types.AttributeValueMemberS{Value: teamID},
+			"ID":     &types.AttributeValueMemberS{Value: id},
 		},
 	})
 	if err != nil {
@@ -31,18 +31,55 @@ func (r RetrieverByBoard) Retrieve(
 		return nil, err
 	}
 
-	out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
+	return queryAll(ctx, r.queryer, &dynamodb.QueryInput{
 		TableName:                 aws.String(os.Getenv(tableName)),
 		IndexName:                 aws.String("BoardID-index"),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		KeyConditionExpression:    expr.KeyCondition(),
 	})
+}
+
+// RetrievePage retrieves up to limit tasks for a board from the task table,
+// starting after the given cursor. It also returns the cursor to retrieve the
+// next page with, which is empty if there are no more tasks.
+func (r RetrieverByBoard) RetrievePage(
+	ctx context.Context, boardID string, limit int, cursor string,
+) ([]Task, string, error) {
+	keyCond := expression.Key("BoardID").Equal(expression.Value(boardID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		IndexName:                 aws.String("BoardID-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		Limit:                     aws.Int32(int32(limit)),
+	}
+	if cursor != "" {
+		if in.ExclusiveStartKey, err = decodeCursor(cursor); err != nil {
+			return nil, "", err
+		}
 	}
 
+	out, err := r.queryer.Query(ctx, in)
+	if err != nil {
+		return nil, "", err
+	}
 	var tasks []Task
-	err = attributevalue.UnmarshalListOfMaps(out.Items, &tasks)
-	return tasks, err
+	if err = attributevalue.UnmarshalListOfMaps(out.Items, &tasks); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(out.LastEvaluatedKey) > 0 {
+		if next, err = encodeCursor(out.LastEvaluatedKey); err != nil {
+			return nil, "", err
+		}
+	}
+	return tasks, next, nil
 }
@@ -151,4 +151,98 @@ func TestRetrieverByBoard(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		taskItem := func(id string) map[string]types.AttributeValue {
+			return map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: id},
+			}
+		}
+		queryer.Err = nil
+		queryer.Out = nil
+		queryer.Pages = []*dynamodb.QueryOutput{
+			{
+				Items: []map[string]types.AttributeValue{
+					taskItem("task1"), taskItem("task2"),
+				},
+				LastEvaluatedKey: taskItem("task2"),
+			},
+			{
+				Items: []map[string]types.AttributeValue{
+					taskItem("task3"),
+				},
+			},
+		}
+
+		tasks, err := sut.Retrieve(context.Background(), "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(tasks), 3)
+		for i, id := range []string{"task1", "task2", "task3"} {
+			assert.Equal(t.Error, tasks[i].ID, id)
+		}
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		key := map[string]types.AttributeValue{
+			"TeamID":  &types.AttributeValueMemberS{Value: "team1"},
+			"BoardID": &types.AttributeValueMemberS{Value: "board1"},
+			"ID":      &types.AttributeValueMemberS{Value: "task2"},
+		}
+		queryer.Pages = nil
+		queryer.Err = nil
+		queryer.Out = &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{
+				{"ID": &types.AttributeValueMemberS{Value: "task1"}},
+				{"ID": &types.AttributeValueMemberS{Value: "task2"}},
+			},
+			LastEvaluatedKey: key,
+		}
+
+		tasks, next, err := sut.RetrievePage(context.Background(), "", 2, "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(tasks), 2)
+		assert.Equal(t.Error, tasks[1].ID, "task2")
+		assert.Equal(t.Error, *queryer.In.Limit, int32(2))
+		assert.Equal(t.Error, len(queryer.In.ExclusiveStartKey), 0)
+		assert.True(t.Fatal, next != "")
+
+		// the next page should start after the key the last one stopped at
+		queryer.Out = &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{
+				{"ID": &types.AttributeValueMemberS{Value: "task3"}},
+			},
+		}
+
+		tasks, next, err = sut.RetrievePage(context.Background(), "", 2, next)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(tasks), 1)
+		assert.Equal(t.Error, tasks[0].ID, "task3")
+		assert.Equal(t.Error, next, "")
+		startKey := queryer.In.ExclusiveStartKey
+		assert.Equal(t.Fatal, len(startKey), len(key))
+		for k, v := range key {
+			assert.Equal(t.Error,
+				startKey[k].(*types.AttributeValueMemberS).Value,
+				v.(*types.AttributeValueMemberS).Value,
+			)
+		}
+	})
+
+	t.Run("PageInvalidCursor", func(t *testing.T) {
+		_, _, err := sut.RetrievePage(context.Background(), "", 2, "!!!")
+
+		assert.ErrIs(t.Error, err, db.ErrInvalidCursor)
+	})
+
+	t.Run("PageErr", func(t *testing.T) {
+		queryer.Out = nil
+		queryer.Err = errA
+
+		_, _, err := sut.RetrievePage(context.Background(), "", 2, "")
+
+		assert.ErrIs(t.Error, err, errA)
+	})
 }
@@ -151,4 +151,35 @@ func TestRetrieverByTeam(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		taskItem := func(id string) map[string]types.AttributeValue {
+			return map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: id},
+			}
+		}
+		queryer.Err = nil
+		queryer.Out = nil
+		queryer.Pages = []*dynamodb.QueryOutput{
+			{
+				Items: []map[string]types.AttributeValue{
+					taskItem("task1"), taskItem("task2"),
+				},
+				LastEvaluatedKey: taskItem("task2"),
+			},
+			{
+				Items: []map[string]types.AttributeValue{
+					taskItem("task3"),
+				},
+			},
+		}
+
+		tasks, err := sut.Retrieve(context.Background(), "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(tasks), 3)
+		for i, id := range []string{"task1", "task2", "task3"} {
+			assert.Equal(t.Error, tasks[i].ID, id)
+		}
+	})
 }
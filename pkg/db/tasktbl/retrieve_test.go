@@ -107,7 +107,7 @@ func TestRetriever(t *testing.T) {
 			ig.Out = c.igOut
 			ig.Err = c.igErr
 
-			task, err := sut.Retrieve(context.Background(), "")
+			task, err := sut.Retrieve(context.Background(), "", "")
 
 			assert.Equal(t.Fatal, err, c.wantErr)
 			if c.wantTask != nil {
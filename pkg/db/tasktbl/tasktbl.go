@@ -1,6 +1,8 @@
 // Package tasktbl contains code to interact with the task table in DynamoDB.
 package tasktbl
 
+import "slices"
+
 // tableName is the name of the environment variable to retrieve the task
 // table's name from.
 const tableName = "TASK_TABLE_NAME"
@@ -15,6 +17,25 @@ type Task struct {
 	Description string    `json:"description"`
 	Order       int       `json:"order"`
 	Subtasks    []Subtask `json:"subtasks"`
+
+	// SprintID is the ID of the sprint the task is assigned to, which is on
+	// the same board as the task. It is empty if the task is in the backlog.
+	SprintID string `json:"sprintID,omitempty"`
+
+	// ArchivedAt is the Unix time at which the task was archived for having
+	// sat in the last column of its board for longer than the board allows,
+	// and 0 if it is not archived.
+	ArchivedAt int64 `json:"archivedAt,omitempty"`
+
+	// BlockedBy holds the IDs of the tasks on the same board that have to be
+	// done before the task. The IDs of deleted tasks are left in it so that
+	// restoring them restores their links, and they do not block the task.
+	BlockedBy []string `json:"blockedBy,omitempty"`
+
+	// Rank is the lexicographic key the task is ordered by within its column.
+	// It is generated server-side and is not exposed by the API, which keeps
+	// reporting the task's position in its column as Order.
+	Rank string `json:"-"`
 }
 
 // NewTask creates and returns a new Task.
@@ -53,3 +74,15 @@ func NewSubtask(title string, isDone bool) Subtask {
 		IsDone: isDone,
 	}
 }
+
+// IsBlocked returns whether any of the tasks that block the task is among the
+// given tasks of its board and is not in the column with the given number,
+// which is the last column of the board.
+func (t Task) IsBlocked(boardTasks []Task, lastColNo int) bool {
+	for _, bt := range boardTasks {
+		if slices.Contains(t.BlockedBy, bt.ID) && bt.ColNo != lastColNo {
+			return true
+		}
+	}
+	return false
+}
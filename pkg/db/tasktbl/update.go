@@ -14,21 +14,47 @@ import (
 )
 
 // Updater can be used to update a task in the task table.
-type Updater struct{ ItemPutter db.DynamoItemPutter }
+type Updater struct{ igetput db.DynamoItemGetPutter }
 
 // NewUpdater creates and returns a new Updater.
-func NewUpdater(ip db.DynamoItemPutter) Updater {
-	return Updater{ItemPutter: ip}
+func NewUpdater(igetput db.DynamoItemGetPutter) Updater {
+	return Updater{igetput: igetput}
 }
 
-// Update updates a task in the task table.
-func (p Updater) Update(ctx context.Context, task Task) error {
+// Update updates a task in the task table. If the given task has no rank, the
+// stored task's rank is kept so that editing a task does not move it.
+func (u Updater) Update(ctx context.Context, task Task) error {
+	if task.Rank == "" {
+		out, err := u.igetput.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(os.Getenv(tableName)),
+			Key: map[string]types.AttributeValue{
+				"TeamID": &types.AttributeValueMemberS{Value: task.TeamID},
+				"ID":     &types.AttributeValueMemberS{Value: task.ID},
+			},
+			ProjectionExpression: aws.String("#rank"),
+			ExpressionAttributeNames: map[string]string{
+				"#rank": "Rank",
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if out.Item == nil {
+			return db.ErrNoItem
+		}
+		if err = attributevalue.Unmarshal(
+			out.Item["Rank"], &task.Rank,
+		); err != nil {
+			return err
+		}
+	}
+
 	item, err := attributevalue.MarshalMap(task)
 	if err != nil {
 		return err
 	}
 
-	_, err = p.ItemPutter.PutItem(ctx, &dynamodb.PutItemInput{
+	_, err = u.igetput.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName:           aws.String(os.Getenv(tableName)),
 		Item:                item,
 		ConditionExpression: aws.String("attribute_exists(ID)"),
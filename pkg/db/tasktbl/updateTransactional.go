@@ -0,0 +1,98 @@
+package tasktbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// maxTransactItems is the maximum number of items DynamoDB allows in a single
+// TransactWriteItems call.
+const maxTransactItems = 100
+
+// TransactionalUpdater can be used to update multiple tasks in the task table
+// in transactions so that a column move is never left half-applied.
+type TransactionalUpdater struct{ tw db.DynamoTransactWriter }
+
+// NewTransactionalUpdater creates and returns a new TransactionalUpdater.
+func NewTransactionalUpdater(tw db.DynamoTransactWriter) TransactionalUpdater {
+	return TransactionalUpdater{tw: tw}
+}
+
+// Update updates multiple tasks in the task table. Tasks are written in chunks
+// of up to 100, each chunk in a single transaction, so that all tasks in a
+// chunk are either updated together or not at all. Since the tasks of a
+// column move are almost always fewer than 100, the move is atomic in
+// practice.
+func (u TransactionalUpdater) Update(ctx context.Context, tasks []Task) error {
+	return transactPut(
+		ctx, u.tw, tasks, "attribute_exists(ID)", db.ErrNoItem,
+	)
+}
+
+// transactPut puts the given tasks into the task table in transactions of up
+// to maxTransactItems, each item on the given condition. It returns condErr if
+// a transaction was cancelled because the condition failed for a task.
+func transactPut(
+	ctx context.Context,
+	tw db.DynamoTransactWriter,
+	tasks []Task,
+	cond string,
+	condErr error,
+) error {
+	tableName := os.Getenv(tableName)
+
+	for start := 0; start < len(tasks); start += maxTransactItems {
+		end := min(start+maxTransactItems, len(tasks))
+
+		items := make([]types.TransactWriteItem, 0, end-start)
+		for _, task := range tasks[start:end] {
+			item, err := attributevalue.MarshalMap(task)
+			if err != nil {
+				return err
+			}
+			items = append(items, types.TransactWriteItem{
+				Put: &types.Put{
+					TableName:           &tableName,
+					Item:                item,
+					ConditionExpression: aws.String(cond),
+				},
+			})
+		}
+
+		if _, err := tw.TransactWriteItems(
+			ctx,
+			&dynamodb.TransactWriteItemsInput{TransactItems: items},
+		); err != nil {
+			return transactErr(err, condErr)
+		}
+	}
+	return nil
+}
+
+// transactErr maps the error returned from a transactional write of tasks to
+// condErr if the write was cancelled because a task's condition failed.
+func transactErr(err, condErr error) error {
+	var exCond *types.ConditionalCheckFailedException
+	if errors.As(err, &exCond) {
+		return condErr
+	}
+
+	var exCancel *types.TransactionCanceledException
+	if errors.As(err, &exCancel) {
+		for _, reason := range exCancel.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return condErr
+			}
+		}
+	}
+
+	return err
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/smithy-go"
 
@@ -15,30 +16,80 @@ import (
 )
 
 func TestUpdater(t *testing.T) {
-	ip := &db.FakeDynamoItemPutter{}
-	sut := NewUpdater(ip)
+	igetput := &db.FakeDynamoItemGetPutter{}
+	sut := NewUpdater(igetput)
 
 	errA := errors.New("failed to put item")
+	outGetOK := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+		"ID":   &types.AttributeValueMemberS{Value: "taskID"},
+		"Rank": &types.AttributeValueMemberS{Value: "i"},
+	}}
 
 	for _, c := range []struct {
 		name    string
-		ipErr   error
+		task    Task
+		outGet  *dynamodb.GetItemOutput
+		errGet  error
+		errPut  error
 		wantErr error
 	}{
-		{name: "Err", ipErr: errA, wantErr: errA},
 		{
-			name: "NoItem",
-			ipErr: &smithy.OperationError{
+			name:    "ErrGet",
+			task:    Task{},
+			outGet:  nil,
+			errGet:  errA,
+			errPut:  nil,
+			wantErr: errA,
+		},
+		{
+			name:    "NoItemGet",
+			task:    Task{},
+			outGet:  &dynamodb.GetItemOutput{Item: nil},
+			errGet:  nil,
+			errPut:  nil,
+			wantErr: db.ErrNoItem,
+		},
+		{
+			name:    "ErrPut",
+			task:    Task{},
+			outGet:  outGetOK,
+			errGet:  nil,
+			errPut:  errA,
+			wantErr: errA,
+		},
+		{
+			name:   "NoItemPut",
+			task:   Task{},
+			outGet: outGetOK,
+			errGet: nil,
+			errPut: &smithy.OperationError{
 				Err: &types.ConditionalCheckFailedException{},
 			},
 			wantErr: db.ErrNoItem,
 		},
-		{name: "OK", ipErr: nil, wantErr: nil},
+		{
+			name:    "OK",
+			task:    Task{},
+			outGet:  outGetOK,
+			errGet:  nil,
+			errPut:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "OKRanked",
+			task:    Task{Rank: "r"},
+			outGet:  nil,
+			errGet:  errA,
+			errPut:  nil,
+			wantErr: nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			ip.Err = c.ipErr
+			igetput.OutGet = c.outGet
+			igetput.ErrGet = c.errGet
+			igetput.ErrPut = c.errPut
 
-			err := sut.Update(context.Background(), Task{})
+			err := sut.Update(context.Background(), c.task)
 
 			assert.ErrIs(t.Fatal, err, c.wantErr)
 		})
@@ -22,6 +22,8 @@ func NewBoardDeleter(igetput db.DynamoItemGetPutter) BoardDeleter {
 }
 
 // Delete deletes the board with the given ID from the team with the given ID.
+// It returns db.ErrConflict if the team was modified by another write after it
+// was read.
 func (d BoardDeleter) Delete(
 	ctx context.Context, teamID string, boardID string,
 ) error {
@@ -52,40 +54,19 @@ func (d BoardDeleter) Delete(
 
 	// check board to be deleted exists and remove it from team's boards
 	var found bool
-	newTeam := Team{
-		ID:      team.ID,
-		Members: team.Members,
-		Boards:  make([]Board, len(team.Boards)-1),
-	}
-	if len(team.Boards) == 1 {
-		if team.Boards[0].ID == boardID {
+	boards := make([]Board, 0, len(team.Boards))
+	for _, b := range team.Boards {
+		if b.ID == boardID {
 			found = true
+			continue
 		}
-	} else {
-		for i := 0; i < len(team.Boards)-1; i++ {
-			if team.Boards[i].ID == boardID {
-				found = true
-				i--
-				continue
-			}
-			newTeam.Boards[i] = team.Boards[i]
-		}
+		boards = append(boards, b)
 	}
 	if !found {
 		return db.ErrNoItem
 	}
+	team.Boards = boards
 
-	// marshal the new team
-	newItem, err := attributevalue.MarshalMap(newTeam)
-	if err != nil {
-		return err
-	}
-
-	// update the team based on the new team
-	_, err = d.igetput.PutItem(ctx, &dynamodb.PutItemInput{
-		Item:      newItem,
-		TableName: aws.String(os.Getenv(tableName)),
-	})
-
-	return err
+	// update the team unless it was modified since it was read
+	return putVersioned(ctx, d.igetput, team)
 }
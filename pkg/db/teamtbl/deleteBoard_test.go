@@ -77,6 +77,13 @@ func TestBoardDeleter(t *testing.T) {
 			errPutItem: errA,
 			wantErr:    errA,
 		},
+		{
+			name:       "ErrConflict",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: &types.ConditionalCheckFailedException{Item: itemA},
+			wantErr:    db.ErrConflict,
+		},
 		{
 			name:       "OK",
 			errGetItem: nil,
@@ -84,6 +91,34 @@ func TestBoardDeleter(t *testing.T) {
 			errPutItem: nil,
 			wantErr:    nil,
 		},
+		{
+			name:       "OKLastOfMany",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberM{
+								Value: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberS{
+										Value: "otherBoardID",
+									},
+								},
+							},
+							&types.AttributeValueMemberM{
+								Value: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberS{
+										Value: "boardID",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			igetput.ErrGet = c.errGetItem
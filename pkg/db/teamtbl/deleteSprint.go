@@ -0,0 +1,67 @@
+package teamtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// SprintDeleter is a type that can be used to delete an item from a team's
+// sprints.
+type SprintDeleter struct{ igetput db.DynamoItemGetPutter }
+
+// NewSprintDeleter creates and returns a new SprintDeleter.
+func NewSprintDeleter(igetput db.DynamoItemGetPutter) SprintDeleter {
+	return SprintDeleter{igetput: igetput}
+}
+
+// Delete deletes the sprint with the given ID from the team with the given ID.
+// It returns db.ErrConflict if the team was modified by another write after it
+// was read.
+func (d SprintDeleter) Delete(
+	ctx context.Context, teamID string, sprintID string,
+) error {
+	// get the existing team as-is
+	out, err := d.igetput.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: teamID},
+		},
+		TableName: aws.String(os.Getenv(tableName)),
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item == nil {
+		return db.ErrNoItem
+	}
+
+	// unmarshal the team
+	var team Team
+	if err := attributevalue.UnmarshalMap(out.Item, &team); err != nil {
+		return err
+	}
+
+	// check sprint to be deleted exists and remove it from team's sprints
+	var found bool
+	sprints := make([]Sprint, 0, len(team.Sprints))
+	for _, s := range team.Sprints {
+		if s.ID == sprintID {
+			found = true
+			continue
+		}
+		sprints = append(sprints, s)
+	}
+	if !found {
+		return db.ErrNoItem
+	}
+	team.Sprints = sprints
+
+	// update the team unless it was modified since it was read
+	return putVersioned(ctx, d.igetput, team)
+}
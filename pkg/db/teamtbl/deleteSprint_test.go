@@ -0,0 +1,94 @@
+//go:build utest
+
+package teamtbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestSprintDeleter(t *testing.T) {
+	igetput := &db.FakeDynamoItemGetPutter{}
+	sut := NewSprintDeleter(igetput)
+
+	errA := errors.New("failed")
+	itemA := map[string]types.AttributeValue{
+		"Sprints": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{&types.AttributeValueMemberM{
+				Value: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: "sprintID"},
+				},
+			}},
+		},
+	}
+
+	for _, c := range []struct {
+		name       string
+		errGetItem error
+		outGetItem *dynamodb.GetItemOutput
+		errPutItem error
+		wantErr    error
+	}{
+		{
+			name:       "ErrGetItem",
+			errGetItem: errA,
+			outGetItem: nil,
+			errPutItem: nil,
+			wantErr:    errA,
+		},
+		{
+			name:       "ErrNoItemTeam",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: nil},
+			errPutItem: nil,
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name:       "ErrNoItemSprint",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{},
+			},
+			errPutItem: nil,
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name:       "ErrPutItem",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: errA,
+			wantErr:    errA,
+		},
+		{
+			name:       "ErrConflict",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: &types.ConditionalCheckFailedException{Item: itemA},
+			wantErr:    db.ErrConflict,
+		},
+		{
+			name:       "OK",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			igetput.ErrGet = c.errGetItem
+			igetput.OutGet = c.outGetItem
+			igetput.ErrPut = c.errPutItem
+
+			err := sut.Delete(context.Background(), "", "sprintID")
+
+			assert.Equal(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -10,18 +10,44 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 // BoardInserter is a type that can be used to insert an item into a team's
 // boards.
-type BoardInserter struct{ igetput db.DynamoItemGetPutter }
+type BoardInserter struct {
+	igetput db.DynamoItemGetPutter
+	quota   quota.Quota
 
-// NewBoardInserter creates and returns a new BoardInserter.
-func NewBoardInserter(igetput db.DynamoItemGetPutter) BoardInserter {
-	return BoardInserter{igetput: igetput}
+	// tw and newOutboxItem are set by WithOutbox.
+	tw            db.DynamoTransactWriter
+	newOutboxItem func(teamID string, board Board) (outboxtbl.Item, error)
+}
+
+// NewBoardInserter creates and returns a new BoardInserter that enforces the
+// given default quota unless the team overrides it.
+func NewBoardInserter(
+	igetput db.DynamoItemGetPutter, quota quota.Quota,
+) BoardInserter {
+	return BoardInserter{igetput: igetput, quota: quota}
+}
+
+// WithOutbox returns a copy of the BoardInserter that writes the outbox item
+// that newItem returns for each board in the same transaction as the board, so
+// that the event it carries is dispatched if and only if the board is
+// inserted.
+func (i BoardInserter) WithOutbox(
+	tw db.DynamoTransactWriter,
+	newItem func(teamID string, board Board) (outboxtbl.Item, error),
+) BoardInserter {
+	i.tw, i.newOutboxItem = tw, newItem
+	return i
 }
 
 // Insert inserts the given board into the boards of the team with the given ID.
+// It returns db.ErrLimitReached if the team's board quota is used up, and
+// db.ErrConflict if the team was modified by another write after it was read.
 func (i BoardInserter) Insert(
 	ctx context.Context, teamID string, board Board,
 ) error {
@@ -45,37 +71,31 @@ func (i BoardInserter) Insert(
 		return err
 	}
 
-	// check there are boards to delete
-	var dupKey bool
-	var count int
+	// check the board is new and the team has room for it
 	for _, b := range team.Boards {
 		if b.ID == board.ID {
-			dupKey = true
-			break
+			return db.ErrDupKey
 		}
-		count++
 	}
-	if dupKey {
-		return db.ErrDupKey
-	}
-	if count > 2 {
+	if !i.quota.Override(team.Quota).AllowsBoard(len(team.Boards)) {
 		return db.ErrLimitReached
 	}
 
 	// add the new board into the boards of the team
 	team.Boards = append(team.Boards, board)
 
-	// marshal back the team
-	newItem, err := attributevalue.MarshalMap(team)
+	// update the team unless it was modified since it was read, along with
+	// the outbox item of the board if there is one
+	if i.newOutboxItem == nil {
+		return putVersioned(ctx, i.igetput, team)
+	}
+	item, err := i.newOutboxItem(teamID, board)
 	if err != nil {
 		return err
 	}
-
-	// update the team
-	_, err = i.igetput.PutItem(ctx, &dynamodb.PutItemInput{
-		Item:      newItem,
-		TableName: aws.String(os.Getenv(tableName)),
-	})
-
-	return err
+	put, err := outboxtbl.Put(item)
+	if err != nil {
+		return err
+	}
+	return transactVersioned(ctx, i.tw, team, put)
 }
@@ -7,16 +7,19 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 func TestBoardInserter(t *testing.T) {
 	igetput := &db.FakeDynamoItemGetPutter{}
-	sut := NewBoardInserter(igetput)
+	sut := NewBoardInserter(igetput, quota.Default())
 
 	errA := errors.New("failed")
 	itemA := map[string]types.AttributeValue{
@@ -113,6 +116,24 @@ func TestBoardInserter(t *testing.T) {
 			errPutItem: nil,
 			wantErr:    db.ErrLimitReached,
 		},
+		{
+			name:       "ErrLimitReachedOverride",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": itemA["Boards"],
+					"Quota": &types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"MaxBoards": &types.AttributeValueMemberN{
+								Value: "1",
+							},
+						},
+					},
+				},
+			},
+			errPutItem: nil,
+			wantErr:    db.ErrLimitReached,
+		},
 		{
 			name:       "ErrPutItem",
 			errGetItem: nil,
@@ -120,6 +141,13 @@ func TestBoardInserter(t *testing.T) {
 			errPutItem: errA,
 			wantErr:    errA,
 		},
+		{
+			name:       "ErrConflict",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: &types.ConditionalCheckFailedException{Item: itemA},
+			wantErr:    db.ErrConflict,
+		},
 		{
 			name:       "OK",
 			errGetItem: nil,
@@ -139,3 +167,80 @@ func TestBoardInserter(t *testing.T) {
 		})
 	}
 }
+
+func TestBoardInserterWithOutbox(t *testing.T) {
+	t.Setenv(tableName, "goteam-team")
+	igetput := &db.FakeDynamoItemGetPutter{
+		OutGet: &dynamodb.GetItemOutput{
+			Item: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: "team1"},
+			},
+		},
+	}
+	tw := &db.FakeDynamoTransactWriter{}
+	errA := errors.New("failed")
+	var newItemErr error
+	sut := NewBoardInserter(igetput, quota.Default()).WithOutbox(
+		tw, func(teamID string, board Board) (outboxtbl.Item, error) {
+			return outboxtbl.Item{ID: teamID + "/" + board.ID}, newItemErr
+		},
+	)
+
+	for _, c := range []struct {
+		name       string
+		newItemErr error
+		errTx      error
+		wantErr    error
+	}{
+		{name: "ErrNewItem", newItemErr: errA, wantErr: errA},
+		{name: "ErrTx", errTx: errA, wantErr: errA},
+		{
+			name: "ErrNoItem",
+			errTx: &types.TransactionCanceledException{
+				CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("ConditionalCheckFailed")},
+					{Code: aws.String("None")},
+				},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{
+			name: "ErrConflict",
+			errTx: &types.TransactionCanceledException{
+				CancellationReasons: []types.CancellationReason{
+					{
+						Code: aws.String("ConditionalCheckFailed"),
+						Item: igetput.OutGet.Item,
+					},
+					{Code: aws.String("None")},
+				},
+			},
+			wantErr: db.ErrConflict,
+		},
+		{name: "OK", wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			newItemErr = c.newItemErr
+			tw.Err = c.errTx
+			tw.Ins = nil
+
+			err := sut.Insert(
+				context.Background(), "team1", Board{ID: "board1"},
+			)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			if c.newItemErr != nil {
+				assert.Equal(t.Error, len(tw.Ins), 0)
+				return
+			}
+			assert.Equal(t.Fatal, len(tw.Ins), 1)
+			items := tw.Ins[0].TransactItems
+			assert.Equal(t.Fatal, len(items), 2)
+			assert.Equal(
+				t.Error, aws.ToString(items[0].Put.TableName), "goteam-team",
+			)
+			id := items[1].Put.Item["ID"].(*types.AttributeValueMemberS)
+			assert.Equal(t.Error, id.Value, "team1/board1")
+		})
+	}
+}
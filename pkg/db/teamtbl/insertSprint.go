@@ -0,0 +1,63 @@
+package teamtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// SprintInserter is a type that can be used to insert an item into a team's
+// sprints.
+type SprintInserter struct{ igetput db.DynamoItemGetPutter }
+
+// NewSprintInserter creates and returns a new SprintInserter.
+func NewSprintInserter(igetput db.DynamoItemGetPutter) SprintInserter {
+	return SprintInserter{igetput: igetput}
+}
+
+// Insert inserts the given sprint into the sprints of the team with the given
+// ID. It returns db.ErrNoItem if the team does not have the sprint's board, and
+// db.ErrConflict if the team was modified by another write after it was read.
+func (i SprintInserter) Insert(
+	ctx context.Context, teamID string, sprint Sprint,
+) error {
+	// get the existing team as-is
+	out, err := i.igetput.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: teamID},
+		},
+		TableName: aws.String(os.Getenv(tableName)),
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item == nil {
+		return db.ErrNoItem
+	}
+
+	// unmarshal the team
+	var team Team
+	if err := attributevalue.UnmarshalMap(out.Item, &team); err != nil {
+		return err
+	}
+
+	// check the sprint is new and its board belongs to the team
+	if _, ok := team.Sprint(sprint.ID); ok {
+		return db.ErrDupKey
+	}
+	if !team.HasBoard(sprint.BoardID) {
+		return db.ErrNoItem
+	}
+
+	// add the new sprint into the sprints of the team
+	team.Sprints = append(team.Sprints, sprint)
+
+	// update the team unless it was modified since it was read
+	return putVersioned(ctx, i.igetput, team)
+}
@@ -1,6 +1,8 @@
 // Package teamtbl contains code to interact with the team table in DynamoDB.
 package teamtbl
 
+import "github.com/kxplxn/goteam/pkg/quota"
+
 // tableName is the name of the environment variable to retrieve the team
 // table's name from.
 const tableName = "TEAM_TABLE_NAME"
@@ -10,6 +12,48 @@ type Team struct {
 	ID      string   `json:"id"`      // admin's username
 	Members []string `json:"members"` // usernames
 	Boards  []Board  `json:"boards"`
+
+	// Sprints holds the team's sprints. A sprint is left in place when its
+	// board is deleted so that it is back on the board if it is restored. It
+	// is not exposed by the team API as the sprint API serves them filtered by
+	// the boards the user can see.
+	Sprints []Sprint `json:"-"`
+
+	// Checklists holds the team's checklist templates, which can be applied
+	// to tasks to add their items as subtasks. It is not exposed by the team
+	// API as the checklist API serves them.
+	Checklists []Checklist `json:"-"`
+
+	// Version is incremented on every write to the team so that concurrent
+	// read-modify-write operations can detect that they would overwrite each
+	// other.
+	Version int `json:"version"`
+
+	// Slack holds the team's Slack integration settings. It is not exposed by
+	// the team API as the webhook URL is a secret only admins should see.
+	Slack Slack `json:"-"`
+
+	// Invites holds the pending invites to join the team. Each is removed when
+	// it is used to register so that it cannot be used again. It is not
+	// exposed by the team API to keep the addresses private.
+	Invites []Invite `json:"-"`
+
+	// Quota overrides the default limits for this team. Limits left at zero
+	// fall back to the defaults. It is not exposed by the team API.
+	Quota quota.Quota `json:"-"`
+
+	// Billing holds the state of the team's subscription to the paid plan. It
+	// is not exposed by the team API.
+	Billing Billing `json:"-"`
+
+	// SAML holds the team's SAML single sign-on settings. It is not exposed by
+	// the team API.
+	SAML SAML `json:"-"`
+
+	// SCIMTokenHash is the SHA-256 hash of the bearer token that the team's
+	// identity provider provisions its members with over SCIM. Provisioning
+	// is disabled if it is empty. It is not exposed by the team API.
+	SCIMTokenHash []byte `json:"-"`
 }
 
 // NewTeam creates and returns a new team.
@@ -17,11 +61,263 @@ func NewTeam(id string, members []string, boards []Board) Team {
 	return Team{ID: id, Members: members, Boards: boards}
 }
 
+// Clone returns a deep copy of the team so that it can be handed out without
+// the receiver being able to mutate the original's members or boards.
+func (t Team) Clone() Team {
+	t.Members = append([]string(nil), t.Members...)
+	boards := make([]Board, len(t.Boards))
+	for i, b := range t.Boards {
+		b.Members = append([]string(nil), b.Members...)
+		b.Columns = append([]Column(nil), b.Columns...)
+		boards[i] = b
+	}
+	t.Boards = boards
+	t.Sprints = append([]Sprint(nil), t.Sprints...)
+	checklists := make([]Checklist, len(t.Checklists))
+	for i, c := range t.Checklists {
+		c.Items = append([]string(nil), c.Items...)
+		checklists[i] = c
+	}
+	t.Checklists = checklists
+	t.Invites = append([]Invite(nil), t.Invites...)
+	return t
+}
+
+// HasBoard returns whether the team owns the board with the given ID.
+func (t Team) HasBoard(id string) bool {
+	for _, b := range t.Boards {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Board returns the board with the given ID and whether the team owns it.
+func (t Team) Board(id string) (Board, bool) {
+	for _, b := range t.Boards {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Board{}, false
+}
+
+// Sprint returns the sprint with the given ID and whether the team has it.
+func (t Team) Sprint(id string) (Sprint, bool) {
+	for _, s := range t.Sprints {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Sprint{}, false
+}
+
+// Checklist returns the checklist template with the given ID and whether the
+// team has it.
+func (t Team) Checklist(id string) (Checklist, bool) {
+	for _, c := range t.Checklists {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Checklist{}, false
+}
+
 // Board defines the board entity which a team may own one/many of.
 type Board struct {
 	ID      string   `json:"id"` // uuid
 	Name    string   `json:"name"`
 	Members []string `json:"members"`
+
+	// Description documents what the board is for. It is markdown that is
+	// sanitized when it is written, so that it is safe for the client to
+	// render.
+	Description string `json:"description,omitempty"`
+
+	// Columns holds the settings of the board's columns, indexed by column
+	// number. Columns past the end of the list use the default settings.
+	Columns []Column `json:"columns,omitempty"`
+
+	// ShareNonce is held by the share token of the board, which grants
+	// read-only access to it without logging in. The board is not shared if
+	// it is empty, and changing it revokes the token. It is not exposed by
+	// the team API.
+	ShareNonce string `json:"-"`
+
+	// Visibility determines which of the team's members can access the
+	// board besides its admins. Boards without one are restricted.
+	Visibility string `json:"visibility,omitempty"`
+
+	// ArchiveAfterDays is the number of days after which the tasks that sit
+	// in the board's last column are archived. They are never archived if it
+	// is 0.
+	ArchiveAfterDays int `json:"archiveAfterDays,omitempty"`
+
+	// BlockedTasks determines what happens when a task is moved into the
+	// board's last column while tasks that block it are not in it. Such moves
+	// are let through without a warning if it is empty.
+	BlockedTasks string `json:"blockedTasks,omitempty"`
+}
+
+// visibilities a board can have.
+const (
+	// VisibilityRestricted restricts a board to the members in its list.
+	VisibilityRestricted = "restricted"
+
+	// VisibilityTeam makes a board accessible to all members of the team
+	// except guests, who can only access the boards they are members of.
+	VisibilityTeam = "team"
+)
+
+// settings a board can have for the tasks moved into its last column while
+// they are blocked.
+const (
+	// BlockedTasksWarn lets the move through with a warning.
+	BlockedTasksWarn = "warn"
+
+	// BlockedTasksRefuse refuses the move.
+	BlockedTasksRefuse = "refuse"
+)
+
+// IsVisibleTo returns whether the user with the given username and role can
+// access the board. Admins can access all of their team's boards.
+func (b Board) IsVisibleTo(username string, isAdmin, isGuest bool) bool {
+	if isAdmin {
+		return true
+	}
+	if b.Visibility == VisibilityTeam && !isGuest {
+		return true
+	}
+	for _, m := range b.Members {
+		if m == username {
+			return true
+		}
+	}
+	return false
+}
+
+// BoardPatch defines the changes to be made to the board with its ID. The
+// fields that are nil are left as they are on the board, so that the clients
+// that only edit some of its settings do not reset the rest.
+type BoardPatch struct {
+	ID               string    `json:"id"` // uuid
+	Name             *string   `json:"name,omitempty"`
+	Members          *[]string `json:"members,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	Columns          *[]Column `json:"columns,omitempty"`
+	Visibility       *string   `json:"visibility,omitempty"`
+	ArchiveAfterDays *int      `json:"archiveAfterDays,omitempty"`
+	BlockedTasks     *string   `json:"blockedTasks,omitempty"`
+}
+
+// Apply returns the given board with the changes in the patch made to it.
+func (p BoardPatch) Apply(b Board) Board {
+	if p.Name != nil {
+		b.Name = *p.Name
+	}
+	if p.Members != nil {
+		b.Members = *p.Members
+	}
+	if p.Description != nil {
+		b.Description = *p.Description
+	}
+	if p.Columns != nil {
+		b.Columns = *p.Columns
+	}
+	if p.Visibility != nil {
+		b.Visibility = *p.Visibility
+	}
+	if p.ArchiveAfterDays != nil {
+		b.ArchiveAfterDays = *p.ArchiveAfterDays
+	}
+	if p.BlockedTasks != nil {
+		b.BlockedTasks = *p.BlockedTasks
+	}
+	return b
+}
+
+// Column defines the settings of a column on a board.
+type Column struct {
+	Color       string `json:"color,omitempty"` // hex, e.g. #1a2b3c
+	Description string `json:"description,omitempty"`
+}
+
+// Sprint defines the sprint entity which a team may own one/many of. Each
+// sprint is planned on one of the team's boards.
+type Sprint struct {
+	ID        string `json:"id"`      // uuid
+	BoardID   string `json:"boardID"` // uuid
+	Name      string `json:"name"`
+	StartDate string `json:"startDate"` // YYYY-MM-DD
+	EndDate   string `json:"endDate"`   // YYYY-MM-DD, inclusive
+}
+
+// Checklist defines a named list of subtasks which a team may keep to apply
+// to the tasks that need them, e.g. a release checklist.
+type Checklist struct {
+	ID    string   `json:"id"` // uuid
+	Name  string   `json:"name"`
+	Items []string `json:"items"` // subtask titles
+}
+
+// Slack defines the Slack integration settings of a team.
+type Slack struct {
+	// WebhookURL is the URL of the Slack incoming webhook messages are posted
+	// to. The integration is disabled if it is empty.
+	WebhookURL string `json:"webhookURL"`
+
+	// OnBoardCreated determines whether a message is posted when a board is
+	// created.
+	OnBoardCreated bool `json:"onBoardCreated"`
+}
+
+// Billing defines the state of a team's subscription to the paid plan.
+type Billing struct {
+	CustomerID     string // Stripe customer ID
+	SubscriptionID string // Stripe subscription ID
+
+	// Status is the status of the Stripe subscription, e.g. active or
+	// canceled. It is empty if the team never subscribed.
+	Status string
+}
+
+// IsPaid returns whether the team is on the paid plan.
+func (b Billing) IsPaid() bool {
+	return b.Status == "active" || b.Status == "trialing"
+}
+
+// SAML defines the SAML single sign-on settings of a team, read from the
+// metadata of its identity provider.
+type SAML struct {
+	// IdPEntityID is the entity ID of the identity provider. Single sign-on
+	// is disabled if it is empty.
+	IdPEntityID string `json:"idpEntityID"`
+
+	// SSOURL is the URL that users are sent to to log in.
+	SSOURL string `json:"ssoURL"`
+
+	// Certs holds the DER encoded certificates that the identity provider
+	// signs its responses with.
+	Certs [][]byte `json:"-"`
+
+	// UsernameAttribute is the name of the attribute that holds the username
+	// of the user. The name ID of the user is used if it is empty.
+	UsernameAttribute string `json:"usernameAttribute"`
+}
+
+// IsEnabled returns whether SAML single sign-on is enabled for the team.
+func (s SAML) IsEnabled() bool { return s.IdPEntityID != "" }
+
+// Invite defines a pending invite to join a team.
+type Invite struct {
+	Nonce     string // uuid, also held by the invite token
+	Email     string // empty for the invite shared by the team's admin
+	ExpiresAt int64  // unix seconds
+
+	// BoardIDs holds the boards that the invitee joins as a guest. It is
+	// empty for invites to join as a member.
+	BoardIDs []string
 }
 
 // NewBoard creates and returns a new board.
@@ -0,0 +1,121 @@
+//go:build utest
+
+package teamtbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestBoardIsVisibleTo tests the IsVisibleTo method of Board to assert that it
+// returns whether the board can be accessed according to its visibility.
+func TestBoardIsVisibleTo(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		visibility string
+		username   string
+		isAdmin    bool
+		isGuest    bool
+		want       bool
+	}{
+		{name: "Admin", username: "alice", isAdmin: true, want: true},
+		{name: "Member", username: "bob", want: true},
+		{name: "NotMember", username: "sally", want: false},
+		{
+			name:       "RestrictedNotMember",
+			visibility: VisibilityRestricted,
+			username:   "sally",
+			want:       false,
+		},
+		{
+			name:       "TeamNotMember",
+			visibility: VisibilityTeam,
+			username:   "sally",
+			want:       true,
+		},
+		{
+			name:       "TeamGuestNotMember",
+			visibility: VisibilityTeam,
+			username:   "sally",
+			isGuest:    true,
+			want:       false,
+		},
+		{
+			name:       "TeamGuestMember",
+			visibility: VisibilityTeam,
+			username:   "bob",
+			isGuest:    true,
+			want:       true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			b := Board{Members: []string{"bob"}, Visibility: c.visibility}
+
+			got := b.IsVisibleTo(c.username, c.isAdmin, c.isGuest)
+
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+// TestBoardPatchApply tests the Apply method of BoardPatch to assert that it
+// only changes the fields of the board that are set on the patch.
+func TestBoardPatchApply(t *testing.T) {
+	board := Board{
+		ID:               "board1",
+		Name:             "Board 1",
+		Members:          []string{"bob"},
+		Description:      "For the backlog.",
+		Columns:          []Column{{Color: "#1a2b3c"}},
+		ShareNonce:       "nonce1",
+		Visibility:       VisibilityTeam,
+		ArchiveAfterDays: 14,
+		BlockedTasks:     BlockedTasksRefuse,
+	}
+
+	t.Run("Name", func(t *testing.T) {
+		name := "Renamed"
+
+		got := BoardPatch{ID: "board1", Name: &name}.Apply(board)
+
+		want := board
+		want.Name = name
+		assert.DeepEqual(t.Error, got, want)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		var (
+			name    = "Renamed"
+			members = []string{"alice"}
+			desc    = ""
+			cols    = []Column{}
+			vis     = VisibilityRestricted
+			days    = 0
+			blocked = BlockedTasksWarn
+		)
+
+		got := BoardPatch{
+			ID:               "board1",
+			Name:             &name,
+			Members:          &members,
+			Description:      &desc,
+			Columns:          &cols,
+			Visibility:       &vis,
+			ArchiveAfterDays: &days,
+			BlockedTasks:     &blocked,
+		}.Apply(board)
+
+		assert.DeepEqual(t.Error, got, Board{
+			ID:               "board1",
+			Name:             name,
+			Members:          members,
+			Description:      desc,
+			Columns:          cols,
+			ShareNonce:       "nonce1",
+			Visibility:       vis,
+			ArchiveAfterDays: days,
+			BlockedTasks:     blocked,
+		})
+	})
+}
@@ -2,13 +2,6 @@ package teamtbl
 
 import (
 	"context"
-	"errors"
-	"os"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/db"
 )
@@ -19,23 +12,8 @@ type Updater struct{ iput db.DynamoItemPutter }
 // NewUpdater creates and returns a new Updater.
 func NewUpdater(iput db.DynamoItemPutter) Updater { return Updater{iput: iput} }
 
-// Update updates a team in the team table.
+// Update updates a team in the team table. The team's version must match the
+// stored version, otherwise db.ErrConflict is returned.
 func (p Updater) Update(ctx context.Context, team Team) error {
-	item, err := attributevalue.MarshalMap(team)
-	if err != nil {
-		return err
-	}
-
-	_, err = p.iput.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName:           aws.String(os.Getenv(tableName)),
-		Item:                item,
-		ConditionExpression: aws.String("attribute_exists(ID)"),
-	})
-
-	var ex *types.ConditionalCheckFailedException
-	if errors.As(err, &ex) {
-		return db.ErrNoItem
-	}
-
-	return err
+	return putVersioned(ctx, p.iput, team)
 }
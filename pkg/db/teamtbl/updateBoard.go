@@ -21,9 +21,11 @@ func NewBoardUpdater(igetput db.DynamoItemGetPutter) BoardUpdater {
 	return BoardUpdater{igetput: igetput}
 }
 
-// Update updates a board in the boards of the team with the given ID.
+// Update makes the changes in the given patch to a board in the boards of the
+// team with the given ID. It returns db.ErrConflict if the team was modified by
+// another write after it was read.
 func (d BoardUpdater) Update(
-	ctx context.Context, teamID string, board Board,
+	ctx context.Context, teamID string, patch BoardPatch,
 ) error {
 	// get the existing team as-is
 	out, err := d.igetput.GetItem(ctx, &dynamodb.GetItemInput{
@@ -53,8 +55,8 @@ func (d BoardUpdater) Update(
 	// check board to be updated exists and update it
 	var found bool
 	for i, b := range team.Boards {
-		if b.ID == board.ID {
-			team.Boards[i] = board
+		if b.ID == patch.ID {
+			team.Boards[i] = patch.Apply(b)
 			found = true
 			break
 		}
@@ -63,17 +65,6 @@ func (d BoardUpdater) Update(
 		return db.ErrNoItem
 	}
 
-	// marshal the new team
-	newItem, err := attributevalue.MarshalMap(team)
-	if err != nil {
-		return err
-	}
-
-	// update the team based on the new team
-	_, err = d.igetput.PutItem(ctx, &dynamodb.PutItemInput{
-		Item:      newItem,
-		TableName: aws.String(os.Getenv(tableName)),
-	})
-
-	return err
+	// update the team unless it was modified since it was read
+	return putVersioned(ctx, d.igetput, team)
 }
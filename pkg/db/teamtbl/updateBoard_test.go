@@ -7,6 +7,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -16,7 +17,7 @@ import (
 
 func TestBoardUpdater(t *testing.T) {
 	igetput := &db.FakeDynamoItemGetPutter{}
-	sut := NewBoardDeleter(igetput)
+	sut := NewBoardUpdater(igetput)
 
 	errA := errors.New("failed")
 	itemA := map[string]types.AttributeValue{
@@ -25,6 +26,20 @@ func TestBoardUpdater(t *testing.T) {
 				Value: map[string]types.AttributeValue{
 					"ID":   &types.AttributeValueMemberS{Value: "boardID"},
 					"Name": &types.AttributeValueMemberS{Value: "boardName"},
+					"Members": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberS{Value: "bob"},
+						},
+					},
+					"Visibility": &types.AttributeValueMemberS{
+						Value: VisibilityTeam,
+					},
+					"ArchiveAfterDays": &types.AttributeValueMemberN{
+						Value: "14",
+					},
+					"BlockedTasks": &types.AttributeValueMemberS{
+						Value: BlockedTasksRefuse,
+					},
 				},
 			}},
 		},
@@ -71,6 +86,13 @@ func TestBoardUpdater(t *testing.T) {
 			errPutItem: errA,
 			wantErr:    errA,
 		},
+		{
+			name:       "ErrConflict",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: &types.ConditionalCheckFailedException{Item: itemA},
+			wantErr:    db.ErrConflict,
+		},
 		{
 			name:       "OK",
 			errGetItem: nil,
@@ -84,9 +106,30 @@ func TestBoardUpdater(t *testing.T) {
 			igetput.OutGet = c.outGetItem
 			igetput.ErrPut = c.errPutItem
 
-			err := sut.Delete(context.Background(), "", "boardID")
+			name := "Renamed"
+
+			err := sut.Update(context.Background(), "", BoardPatch{
+				ID: "boardID", Name: &name,
+			})
 
 			assert.Equal(t.Fatal, err, c.wantErr)
+			if c.wantErr != nil {
+				return
+			}
+
+			// the fields that were not in the patch are kept
+			var team Team
+			err = attributevalue.UnmarshalMap(igetput.InPut.Item, &team)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Fatal, len(team.Boards), 1)
+			assert.DeepEqual(t.Error, team.Boards[0], Board{
+				ID:               "boardID",
+				Name:             "Renamed",
+				Members:          []string{"bob"},
+				Visibility:       VisibilityTeam,
+				ArchiveAfterDays: 14,
+				BlockedTasks:     BlockedTasksRefuse,
+			})
 		})
 	}
 }
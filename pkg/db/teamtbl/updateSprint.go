@@ -0,0 +1,70 @@
+package teamtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// SprintUpdater is a type that can be used to update an item in a team's
+// sprints.
+type SprintUpdater struct{ igetput db.DynamoItemGetPutter }
+
+// NewSprintUpdater creates and returns a new SprintUpdater.
+func NewSprintUpdater(igetput db.DynamoItemGetPutter) SprintUpdater {
+	return SprintUpdater{igetput: igetput}
+}
+
+// Update updates a sprint in the sprints of the team with the given ID. It
+// returns db.ErrNoItem if the team does not have the sprint or its board, and
+// db.ErrConflict if the team was modified by another write after it was read.
+func (u SprintUpdater) Update(
+	ctx context.Context, teamID string, sprint Sprint,
+) error {
+	// get the existing team as-is
+	out, err := u.igetput.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: teamID},
+		},
+		TableName: aws.String(os.Getenv(tableName)),
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item == nil {
+		return db.ErrNoItem
+	}
+
+	// unmarshal the team
+	var team Team
+	if err := attributevalue.UnmarshalMap(out.Item, &team); err != nil {
+		return err
+	}
+
+	// check the board the sprint is on belongs to the team
+	if !team.HasBoard(sprint.BoardID) {
+		return db.ErrNoItem
+	}
+
+	// check sprint to be updated exists and update it
+	var found bool
+	for i, s := range team.Sprints {
+		if s.ID == sprint.ID {
+			team.Sprints[i] = sprint
+			found = true
+			break
+		}
+	}
+	if !found {
+		return db.ErrNoItem
+	}
+
+	// update the team unless it was modified since it was read
+	return putVersioned(ctx, u.igetput, team)
+}
@@ -33,6 +33,17 @@ func TestUpdater(t *testing.T) {
 			},
 			wantErr: db.ErrNoItem,
 		},
+		{
+			name: "Conflict",
+			ipErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{
+					Item: map[string]types.AttributeValue{
+						"Version": &types.AttributeValueMemberN{Value: "2"},
+					},
+				},
+			},
+			wantErr: db.ErrConflict,
+		},
 		{name: "OK", ipErr: nil, wantErr: nil},
 	} {
 		t.Run(c.name, func(t *testing.T) {
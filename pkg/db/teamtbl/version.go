@@ -0,0 +1,105 @@
+package teamtbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// versionCondition is the condition that the team being written exists and
+// still has the version that it was read at, which versionValues sets.
+const versionCondition = "attribute_exists(ID) AND " +
+	"(attribute_not_exists(Version) OR Version = :v)"
+
+// versionValues returns the expression attribute values of versionCondition
+// for the given read version.
+func versionValues(readVersion int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":v": &types.AttributeValueMemberN{Value: strconv.Itoa(readVersion)},
+	}
+}
+
+// putVersioned writes the given team into the team table only if the stored
+// team still has the version that the given team was read at, incrementing the
+// version as part of the write. It returns db.ErrNoItem if the team does not
+// exist and db.ErrConflict if it was modified since it was read.
+func putVersioned(
+	ctx context.Context, iput db.DynamoItemPutter, team Team,
+) error {
+	readVersion := team.Version
+	team.Version++
+
+	item, err := attributevalue.MarshalMap(team)
+	if err != nil {
+		return err
+	}
+
+	_, err = iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		Item:                      item,
+		ConditionExpression:       aws.String(versionCondition),
+		ExpressionAttributeValues: versionValues(readVersion),
+		ReturnValuesOnConditionCheckFailure: types.
+			ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		if ex.Item == nil {
+			return db.ErrNoItem
+		}
+		return db.ErrConflict
+	}
+
+	return err
+}
+
+// transactVersioned writes the given team as putVersioned does, along with
+// the given writes to other tables in the same transaction, so that none of
+// them are made if the team was modified since it was read.
+func transactVersioned(
+	ctx context.Context,
+	tw db.DynamoTransactWriter,
+	team Team,
+	writes ...types.TransactWriteItem,
+) error {
+	readVersion := team.Version
+	team.Version++
+
+	item, err := attributevalue.MarshalMap(team)
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{{Put: &types.Put{
+			TableName:                 aws.String(os.Getenv(tableName)),
+			Item:                      item,
+			ConditionExpression:       aws.String(versionCondition),
+			ExpressionAttributeValues: versionValues(readVersion),
+			ReturnValuesOnConditionCheckFailure: types.
+				ReturnValuesOnConditionCheckFailureAllOld,
+		}}}, writes...),
+	})
+
+	// the team's write is first, so its reason is the first one
+	var ex *types.TransactionCanceledException
+	if errors.As(err, &ex) && len(ex.CancellationReasons) > 0 &&
+		aws.ToString(ex.CancellationReasons[0].Code) ==
+			"ConditionalCheckFailed" {
+		if ex.CancellationReasons[0].Item == nil {
+			return db.ErrNoItem
+		}
+		return db.ErrConflict
+	}
+
+	return err
+}
@@ -0,0 +1,51 @@
+package trashtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by team ID and ID an item from the trash
+// table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by team ID and ID an item from the trash table. It
+// returns db.ErrNoItem if the item does not exist or has expired.
+func (r Retriever) Retrieve(
+	ctx context.Context, teamID, id string,
+) (Item, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"TeamID": &types.AttributeValueMemberS{Value: teamID},
+			"ID":     &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	if out.Item == nil {
+		return Item{}, db.ErrNoItem
+	}
+
+	var item Item
+	if err = attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return Item{}, err
+	}
+	if item.IsExpired() {
+		return Item{}, db.ErrNoItem
+	}
+	return item, nil
+}
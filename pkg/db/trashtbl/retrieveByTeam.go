@@ -0,0 +1,95 @@
+package trashtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverByTeam can be used to retrieve all items of a team from the trash
+// table.
+type RetrieverByTeam struct{ queryer db.DynamoQueryer }
+
+// NewRetrieverByTeam creates and returns a new RetrieverByTeam.
+func NewRetrieverByTeam(queryer db.DynamoQueryer) RetrieverByTeam {
+	return RetrieverByTeam{queryer: queryer}
+}
+
+// Retrieve retrieves all unexpired items of a team from the trash table.
+func (r RetrieverByTeam) Retrieve(
+	ctx context.Context, teamID string,
+) ([]Item, error) {
+	return retrieveByTeam(ctx, r.queryer, teamID, false)
+}
+
+// ExpiredRetrieverByTeam can be used to retrieve the expired items of a team
+// that DynamoDB has not deleted yet from the trash table, so that they can be
+// purged without waiting for the table's TTL.
+type ExpiredRetrieverByTeam struct{ queryer db.DynamoQueryer }
+
+// NewExpiredRetrieverByTeam creates and returns a new ExpiredRetrieverByTeam.
+func NewExpiredRetrieverByTeam(
+	queryer db.DynamoQueryer,
+) ExpiredRetrieverByTeam {
+	return ExpiredRetrieverByTeam{queryer: queryer}
+}
+
+// Retrieve retrieves all expired items of a team from the trash table.
+func (r ExpiredRetrieverByTeam) Retrieve(
+	ctx context.Context, teamID string,
+) ([]Item, error) {
+	return retrieveByTeam(ctx, r.queryer, teamID, true)
+}
+
+// retrieveByTeam retrieves the items of a team from the trash table that are
+// expired or unexpired as given.
+func retrieveByTeam(
+	ctx context.Context, queryer db.DynamoQueryer, teamID string, expired bool,
+) ([]Item, error) {
+	keyCond := expression.Key("TeamID").Equal(expression.Value(teamID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+	}
+
+	// follow LastEvaluatedKey as each page is capped at 1 MB
+	var items []Item
+	for {
+		out, err := queryer.Query(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Item
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		for _, item := range page {
+			if item.IsExpired() == expired {
+				items = append(items, item)
+			}
+		}
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		next := *in
+		next.ExclusiveStartKey = out.LastEvaluatedKey
+		in = &next
+	}
+}
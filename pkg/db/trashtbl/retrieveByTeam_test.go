@@ -0,0 +1,113 @@
+//go:build utest
+
+package trashtbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByTeam(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByTeam(queryer)
+
+	errA := errors.New("failed")
+	var (
+		future = time.Now().Add(time.Minute)
+		past   = time.Now().Add(-time.Minute)
+	)
+
+	for _, c := range []struct {
+		name    string
+		dqOut   *dynamodb.QueryOutput
+		dqErr   error
+		wantIDs []string
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			dqOut:   nil,
+			dqErr:   errA,
+			wantIDs: []string{},
+			wantErr: errA,
+		},
+		{
+			name: "OK",
+			dqOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					trashItem("b1", future),
+					trashItem("b2", past),
+					trashItem("b3", future),
+				},
+			},
+			dqErr:   nil,
+			wantIDs: []string{"b1", "b3"},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.dqOut
+			queryer.Err = c.dqErr
+
+			items, err := sut.Retrieve(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(items), len(c.wantIDs))
+			for i, id := range c.wantIDs {
+				assert.Equal(t.Error, items[i].ID, id)
+			}
+		})
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		queryer.Err = nil
+		queryer.Out = nil
+		queryer.Pages = []*dynamodb.QueryOutput{
+			{
+				Items: []map[string]types.AttributeValue{
+					trashItem("b1", future), trashItem("b2", future),
+				},
+				LastEvaluatedKey: trashItem("b2", future),
+			},
+			{
+				Items: []map[string]types.AttributeValue{
+					trashItem("b3", future),
+				},
+			},
+		}
+
+		items, err := sut.Retrieve(context.Background(), "")
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(items), 3)
+		for i, id := range []string{"b1", "b2", "b3"} {
+			assert.Equal(t.Error, items[i].ID, id)
+		}
+	})
+}
+
+func TestExpiredRetrieverByTeam(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{
+		Out: &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{
+				trashItem("b1", time.Now().Add(time.Minute)),
+				trashItem("b2", time.Now().Add(-time.Minute)),
+			},
+		},
+	}
+	sut := NewExpiredRetrieverByTeam(queryer)
+
+	items, err := sut.Retrieve(context.Background(), "")
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(items), 1)
+	assert.Equal(t.Error, items[0].ID, "b2")
+}
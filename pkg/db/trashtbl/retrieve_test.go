@@ -0,0 +1,106 @@
+//go:build utest
+
+package trashtbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// trashItem returns the attribute values of a deleted board with the given ID
+// that expires at the given time.
+func trashItem(
+	id string, expiresAt time.Time,
+) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"TeamID":    &types.AttributeValueMemberS{Value: "team1"},
+		"ID":        &types.AttributeValueMemberS{Value: id},
+		"Kind":      &types.AttributeValueMemberS{Value: string(KindBoard)},
+		"DeletedBy": &types.AttributeValueMemberS{Value: "bob123"},
+		"ExpiresAt": &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(expiresAt.Unix(), 10),
+		},
+		"Board": &types.AttributeValueMemberM{
+			Value: map[string]types.AttributeValue{
+				"ID":   &types.AttributeValueMemberS{Value: id},
+				"Name": &types.AttributeValueMemberS{Value: "board " + id},
+			},
+		},
+	}
+}
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name     string
+		igOut    *dynamodb.GetItemOutput
+		igErr    error
+		wantItem Item
+		wantErr  error
+	}{
+		{
+			name:     "Err",
+			igOut:    nil,
+			igErr:    errA,
+			wantItem: Item{},
+			wantErr:  errA,
+		},
+		{
+			name:     "NoItem",
+			igOut:    &dynamodb.GetItemOutput{Item: nil},
+			igErr:    nil,
+			wantItem: Item{},
+			wantErr:  db.ErrNoItem,
+		},
+		{
+			name: "Expired",
+			igOut: &dynamodb.GetItemOutput{
+				Item: trashItem("b1", time.Now().Add(-time.Minute)),
+			},
+			igErr:    nil,
+			wantItem: Item{},
+			wantErr:  db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: trashItem("b1", time.Now().Add(time.Minute)),
+			},
+			igErr: nil,
+			wantItem: Item{
+				TeamID:    "team1",
+				ID:        "b1",
+				Kind:      KindBoard,
+				DeletedBy: "bob123",
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			item, err := sut.Retrieve(context.Background(), "", "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, item.TeamID, c.wantItem.TeamID)
+			assert.Equal(t.Error, item.ID, c.wantItem.ID)
+			assert.Equal(t.Error, item.Kind, c.wantItem.Kind)
+			assert.Equal(t.Error, item.DeletedBy, c.wantItem.DeletedBy)
+			assert.Equal(t.Error, item.Board.ID, c.wantItem.ID)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package trashtbl contains code to interact with the trash table in DynamoDB,
+// which stores deleted boards and tasks for a while so that they can be
+// restored.
+package trashtbl
+
+import (
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// tableName is the name of the environment variable to retrieve the trash
+// table's name from.
+const tableName = "TRASH_TABLE_NAME"
+
+// Retention is how long deleted items are kept in the trash before they are
+// deleted for good.
+const Retention = 30 * 24 * time.Hour
+
+// Kind defines the kind of a deleted item.
+type Kind string
+
+// Kinds of deleted items.
+const (
+	KindBoard Kind = "board"
+	KindTask  Kind = "task"
+)
+
+// Item defines the trash item entity, which holds a deleted board or task.
+type Item struct {
+	TeamID    string // hash key
+	ID        string // range key - the ID of the deleted board or task
+	Kind      Kind
+	DeletedBy string // username
+	DeletedAt int64  // Unix time
+
+	// ExpiresAt is the Unix time at which the item expires. The table's TTL is
+	// configured on this attribute, and expired items that DynamoDB has not
+	// deleted yet are treated as absent.
+	ExpiresAt int64
+
+	Board teamtbl.Board // set if Kind is KindBoard
+	Task  tasktbl.Task  // set if Kind is KindTask
+}
+
+// NewBoardItem creates and returns a new Item for a board deleted now.
+func NewBoardItem(teamID, deletedBy string, board teamtbl.Board) Item {
+	item := newItem(teamID, board.ID, KindBoard, deletedBy)
+	item.Board = board
+	return item
+}
+
+// NewTaskItem creates and returns a new Item for a task deleted now.
+func NewTaskItem(teamID, deletedBy string, task tasktbl.Task) Item {
+	item := newItem(teamID, task.ID, KindTask, deletedBy)
+	item.Task = task
+	return item
+}
+
+// newItem creates and returns a new Item deleted now that expires after
+// Retention.
+func newItem(teamID, id string, kind Kind, deletedBy string) Item {
+	now := time.Now()
+	return Item{
+		TeamID:    teamID,
+		ID:        id,
+		Kind:      kind,
+		DeletedBy: deletedBy,
+		DeletedAt: now.Unix(),
+		ExpiresAt: now.Add(Retention).Unix(),
+	}
+}
+
+// IsExpired returns whether the item has expired.
+func (i Item) IsExpired() bool { return i.ExpiresAt <= time.Now().Unix() }
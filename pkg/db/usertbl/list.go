@@ -0,0 +1,52 @@
+package usertbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Lister can be used to list all users in the user table.
+type Lister struct{ scanner db.DynamoScanner }
+
+// NewLister creates and returns a new Lister.
+func NewLister(scanner db.DynamoScanner) Lister {
+	return Lister{scanner: scanner}
+}
+
+// List scans the user table and returns all users in it.
+func (l Lister) List(ctx context.Context) ([]User, error) {
+	var (
+		users    []User
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		out, err := l.scanner.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(os.Getenv(tableName)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []User
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+
+		// keep scanning until there are no more pages
+		if len(out.LastEvaluatedKey) == 0 {
+			return users, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
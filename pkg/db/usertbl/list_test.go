@@ -0,0 +1,89 @@
+//go:build utest
+
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestLister(t *testing.T) {
+	scanner := &db.FakeDynamoScanner{}
+	sut := NewLister(scanner)
+
+	errA := errors.New("failed to scan")
+	userItem := func(username string, isDisabled bool) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"Username":   &types.AttributeValueMemberS{Value: username},
+			"TeamID":     &types.AttributeValueMemberS{Value: "team1"},
+			"IsDisabled": &types.AttributeValueMemberBOOL{Value: isDisabled},
+		}
+	}
+
+	for _, c := range []struct {
+		name      string
+		outs      []*dynamodb.ScanOutput
+		scanErr   error
+		wantUsers []User
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			outs:      nil,
+			scanErr:   errA,
+			wantUsers: nil,
+			wantErr:   errA,
+		},
+		{
+			name:      "None",
+			outs:      []*dynamodb.ScanOutput{{}},
+			scanErr:   nil,
+			wantUsers: nil,
+			wantErr:   nil,
+		},
+		{
+			name: "Paginated",
+			outs: []*dynamodb.ScanOutput{
+				{
+					Items: []map[string]types.AttributeValue{
+						userItem("bob123", false),
+					},
+					LastEvaluatedKey: userItem("bob123", false),
+				},
+				{
+					Items: []map[string]types.AttributeValue{
+						userItem("sally", true),
+					},
+				},
+			},
+			scanErr: nil,
+			wantUsers: []User{
+				{Username: "bob123", TeamID: "team1", IsDisabled: false},
+				{Username: "sally", TeamID: "team1", IsDisabled: true},
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			scanner.Outs = c.outs
+			scanner.Err = c.scanErr
+
+			users, err := sut.List(context.Background())
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(users), len(c.wantUsers))
+			for i, u := range users {
+				assert.Equal(t.Error, u.Username, c.wantUsers[i].Username)
+				assert.Equal(t.Error, u.TeamID, c.wantUsers[i].TeamID)
+				assert.Equal(t.Error, u.IsDisabled, c.wantUsers[i].IsDisabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package usertbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverByEmail can be used to retrieve by email the users from the user
+// table.
+type RetrieverByEmail struct{ queryer db.DynamoQueryer }
+
+// NewRetrieverByEmail creates and returns a new RetrieverByEmail.
+func NewRetrieverByEmail(queryer db.DynamoQueryer) RetrieverByEmail {
+	return RetrieverByEmail{queryer: queryer}
+}
+
+// Retrieve retrieves by email all users from the user table that have it as
+// their verified email address. The email address must be in lower case.
+func (r RetrieverByEmail) Retrieve(
+	ctx context.Context, email string,
+) ([]User, error) {
+	keyCond := expression.Key("Email").Equal(expression.Value(email))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		users    []User
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(os.Getenv(tableName)),
+			IndexName:                 aws.String("Email-index"),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []User
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+
+		// keep querying until there are no more pages
+		if len(out.LastEvaluatedKey) == 0 {
+			return users, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
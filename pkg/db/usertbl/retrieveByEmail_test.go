@@ -0,0 +1,87 @@
+//go:build utest
+
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByEmail(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByEmail(queryer)
+
+	errA := errors.New("failed to query")
+	userItem := func(username string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+			"Email":    &types.AttributeValueMemberS{Value: "bob@acme.com"},
+		}
+	}
+
+	for _, c := range []struct {
+		name      string
+		pages     []*dynamodb.QueryOutput
+		queryErr  error
+		wantUsers []string
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			queryErr:  errA,
+			wantUsers: nil,
+			wantErr:   errA,
+		},
+		{
+			name:      "None",
+			pages:     []*dynamodb.QueryOutput{{}},
+			wantUsers: nil,
+			wantErr:   nil,
+		},
+		{
+			name: "Paginated",
+			pages: []*dynamodb.QueryOutput{
+				{
+					Items: []map[string]types.AttributeValue{
+						userItem("bob123"),
+					},
+					LastEvaluatedKey: userItem("bob123"),
+				},
+				{
+					Items: []map[string]types.AttributeValue{
+						userItem("bob456"),
+					},
+				},
+			},
+			wantUsers: []string{"bob123", "bob456"},
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Pages = c.pages
+			queryer.Err = c.queryErr
+
+			users, err := sut.Retrieve(context.Background(), "bob@acme.com")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(users), len(c.wantUsers))
+			for i, u := range users {
+				assert.Equal(t.Error, u.Username, c.wantUsers[i])
+				assert.Equal(t.Error, u.Email, "bob@acme.com")
+			}
+			if c.queryErr == nil {
+				assert.Equal(t.Error,
+					aws.ToString(queryer.In.IndexName), "Email-index",
+				)
+			}
+		})
+	}
+}
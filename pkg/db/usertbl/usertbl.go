@@ -11,6 +11,82 @@ type User struct {
 	Password []byte
 	IsAdmin  bool
 	TeamID   string
+
+	// Email is the user's verified email address in lower case, which they
+	// can log in with instead of their username. It is set from the invite
+	// they registered with if it was emailed to them, and is empty otherwise.
+	// More than one user can have the same email address, in which case none
+	// of them can log in with it. It is left out of the item when empty since
+	// it keys the Email-index, and index keys cannot be empty strings.
+	Email string `dynamodbav:",omitempty"`
+
+	// IsDisabled is set by operators to prevent a user from logging in.
+	IsDisabled bool
+
+	// IsGuest is set for the users who joined the team as guests. Guests can
+	// only see the boards they are members of and not the team's members.
+	IsGuest bool
+
+	// AvatarHash is the hash of the avatar the user uploaded, which versions
+	// its URL so that clients fetch it again once it changes. It is empty for
+	// the users who have not uploaded one.
+	AvatarHash string
+
+	// Favorites holds the IDs of the boards the user has starred, in the
+	// order they were starred.
+	Favorites []string
+
+	// Sessions holds the sessions the user is logged in with. Auth tokens
+	// issued for a session are rejected once it is removed from here.
+	Sessions []Session
+}
+
+// Session defines a session a user is logged in with on a device.
+type Session struct {
+	ID        string // uuid, also held by the auth token as its jti
+	UserAgent string
+	IssuedAt  int64 // unix seconds
+	ExpiresAt int64 // unix seconds
+}
+
+// maxUserAgentLen is the maximum number of bytes of a user agent stored for a
+// session, so that a client cannot grow the user item without bounds.
+const maxUserAgentLen = 256
+
+// NewSession creates and returns a new Session.
+func NewSession(id, userAgent string, issuedAt, expiresAt int64) Session {
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	return Session{
+		ID:        id,
+		UserAgent: userAgent,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// StartSession records the given session on the user, dropping the sessions
+// that expired before it was issued.
+func (u *User) StartSession(s Session) {
+	var sessions []Session
+	for _, old := range u.Sessions {
+		if old.ExpiresAt > s.IssuedAt {
+			sessions = append(sessions, old)
+		}
+	}
+	u.Sessions = append(sessions, s)
+}
+
+// HasSession returns whether the user has a session with the given ID that has
+// not expired at the given unix time.
+func (u User) HasSession(id string, now int64) bool {
+	for _, s := range u.Sessions {
+		if s.ID == id && s.ExpiresAt > now {
+			return true
+		}
+	}
+	return false
 }
 
 // NewUser creates and returns a new User,
@@ -0,0 +1,17 @@
+//go:build utest
+
+package featureflag
+
+// FakeChecker is a test fake for Checker.
+type FakeChecker struct {
+	InFlag   Flag
+	InTeamID string
+	Res      bool
+}
+
+// Enabled records the arguments it was called with and returns
+// FakeChecker.Res.
+func (f *FakeChecker) Enabled(flag Flag, teamID string) bool {
+	f.InFlag, f.InTeamID = flag, teamID
+	return f.Res
+}
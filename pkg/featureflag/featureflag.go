@@ -0,0 +1,139 @@
+// Package featureflag contains the flags that gate risky features and the code
+// to decide which teams they are enabled for, so that the features can be
+// rolled out to some teams or a percentage of them before all.
+package featureflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+)
+
+// Names of the environment variables to load the rules of the flags from. Both
+// are optional and hold a JSON object of flag names to rules, e.g.
+// {"graphql": {"percent": 25, "teams": ["team1"]}}. The rules set in EnvRules
+// replace those of the same flags in the file at EnvFile.
+const (
+	EnvFile  = "FEATURE_FLAGS_FILE"
+	EnvRules = "FEATURE_FLAGS"
+)
+
+// Flag defines a feature that can be enabled for some teams only.
+type Flag struct {
+	Name string
+
+	// Default is whether the feature is enabled for the teams when its flag
+	// has no rule, which is true for features that already shipped to all
+	// teams and are only kept behind a flag to be switched off.
+	Default bool
+}
+
+// Flags that gate features. A flag must be listed in All for rules to be set
+// for it.
+var (
+	// GraphQL gates the POST /graphql endpoint of the team service.
+	GraphQL = Flag{Name: "graphql", Default: true}
+)
+
+// All is the list of every flag that the rules can be set for.
+var All = []Flag{GraphQL}
+
+// Rule defines which teams a flag is enabled for.
+type Rule struct {
+	// Teams are the IDs of the teams the flag is enabled for regardless of
+	// Percent.
+	Teams []string `json:"teams"`
+
+	// Percent is the percentage of teams the flag is enabled for. The same
+	// teams are picked for the same percentage of a flag each time, and a
+	// team that is picked keeps being picked as the percentage increases.
+	Percent int `json:"percent"`
+}
+
+// Enables returns whether the rule enables the given flag for the team with
+// the given ID.
+func (r Rule) Enables(flag Flag, teamID string) bool {
+	if slices.Contains(r.Teams, teamID) {
+		return true
+	}
+	if r.Percent <= 0 {
+		return false
+	}
+	return bucket(flag, teamID) < r.Percent
+}
+
+// bucket returns the bucket in [0, 100) of the team with the given ID for the
+// given flag, which is stable for the pair and differs across flags so that
+// the same teams are not always the first to get new features.
+func bucket(flag Flag, teamID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag.Name + ":" + teamID))
+	return int(h.Sum32() % 100)
+}
+
+// Checker describes a type that can be used to check whether a flag is enabled
+// for a team.
+type Checker interface {
+	Enabled(flag Flag, teamID string) bool
+}
+
+// Set is a Checker that holds the rules of the flags by their names.
+type Set map[string]Rule
+
+// Enabled returns whether the given flag is enabled for the team with the
+// given ID, which is its default if the set has no rule for it.
+func (s Set) Enabled(flag Flag, teamID string) bool {
+	r, ok := s[flag.Name]
+	if !ok {
+		return flag.Default
+	}
+	return r.Enables(flag, teamID)
+}
+
+// FromEnv returns the set of the rules in the file at EnvFile and in EnvRules,
+// which is empty if neither is set.
+func FromEnv() (Set, error) {
+	s := Set{}
+	if path := os.Getenv(EnvFile); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvFile, err)
+		}
+		if err := s.merge(EnvFile, b); err != nil {
+			return nil, err
+		}
+	}
+	if rules := os.Getenv(EnvRules); rules != "" {
+		if err := s.merge(EnvRules, []byte(rules)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// merge decodes the given JSON object of rules into s, replacing the rules of
+// the same flags. The rules are validated and name is used to tell where the
+// invalid ones were read from.
+func (s Set) merge(name string, b []byte) error {
+	var rules map[string]Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return fmt.Errorf("%s must be a JSON object of rules: %w", name, err)
+	}
+	for flag, r := range rules {
+		if !slices.ContainsFunc(All, func(f Flag) bool {
+			return f.Name == flag
+		}) {
+			return fmt.Errorf("%s: unknown flag %q", name, flag)
+		}
+		if r.Percent < 0 || r.Percent > 100 {
+			return fmt.Errorf(
+				"%s: percent of %q must be between 0 and 100, got %d",
+				name, flag, r.Percent,
+			)
+		}
+		s[flag] = r
+	}
+	return nil
+}
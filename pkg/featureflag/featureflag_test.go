@@ -0,0 +1,139 @@
+//go:build utest
+
+package featureflag
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSet tests the Enabled method of Set to assert that it enables flags for
+// the teams their rules pick, falling back to their defaults.
+func TestSet(t *testing.T) {
+	on := Flag{Name: "on", Default: true}
+	off := Flag{Name: "off"}
+
+	t.Run("NoRule", func(t *testing.T) {
+		sut := Set{}
+
+		assert.True(t.Error, sut.Enabled(on, "team1"))
+		assert.True(t.Error, !sut.Enabled(off, "team1"))
+	})
+
+	t.Run("Teams", func(t *testing.T) {
+		sut := Set{"on": {Teams: []string{"team1"}}}
+
+		assert.True(t.Error, sut.Enabled(on, "team1"))
+		assert.True(t.Error, !sut.Enabled(on, "team2"))
+	})
+
+	t.Run("Percent", func(t *testing.T) {
+		for _, c := range []struct {
+			percent int
+			wantMin int
+			wantMax int
+		}{
+			{percent: 0, wantMin: 0, wantMax: 0},
+			{percent: 25, wantMin: 200, wantMax: 300},
+			{percent: 100, wantMin: 1000, wantMax: 1000},
+		} {
+			t.Run(strconv.Itoa(c.percent), func(t *testing.T) {
+				sut := Set{"off": {Percent: c.percent}}
+
+				n := 0
+				for i := 0; i < 1000; i++ {
+					if sut.Enabled(off, "team"+strconv.Itoa(i)) {
+						n++
+					}
+				}
+
+				assert.True(t.Error, n >= c.wantMin && n <= c.wantMax)
+			})
+		}
+	})
+
+	t.Run("PercentIsStable", func(t *testing.T) {
+		lo, hi := Set{"off": {Percent: 10}}, Set{"off": {Percent: 50}}
+
+		for i := 0; i < 1000; i++ {
+			teamID := "team" + strconv.Itoa(i)
+			if lo.Enabled(off, teamID) {
+				assert.True(t.Error, hi.Enabled(off, teamID))
+			}
+		}
+	})
+}
+
+// TestFromEnv tests the FromEnv function to assert that it loads the rules
+// from the file and the environment variable, the latter replacing the former.
+func TestFromEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "flags.json")
+	err := os.WriteFile(file, []byte(
+		`{"graphql": {"percent": 100}}`,
+	), 0o600)
+	assert.Nil(t.Fatal, err)
+
+	for _, c := range []struct {
+		name    string
+		file    string
+		rules   string
+		want    Set
+		wantErr string
+	}{
+		{name: "Unset", want: Set{}},
+		{
+			name: "File",
+			file: file,
+			want: Set{"graphql": {Percent: 100}},
+		},
+		{
+			name:  "RulesReplaceFile",
+			file:  file,
+			rules: `{"graphql": {"teams": ["team1"]}}`,
+			want:  Set{"graphql": {Teams: []string{"team1"}}},
+		},
+		{
+			name:    "FileNotFound",
+			file:    filepath.Join(t.TempDir(), "missing.json"),
+			wantErr: EnvFile + ": open ",
+		},
+		{
+			name:    "NotJSON",
+			rules:   "graphql=on",
+			wantErr: EnvRules + " must be a JSON object of rules: ",
+		},
+		{
+			name:    "UnknownFlag",
+			rules:   `{"grapql": {"percent": 10}}`,
+			wantErr: EnvRules + `: unknown flag "grapql"`,
+		},
+		{
+			name:  "InvalidPercent",
+			rules: `{"graphql": {"percent": 101}}`,
+			wantErr: EnvRules + `: percent of "graphql" must be between 0 ` +
+				`and 100, got 101`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvFile, c.file)
+			t.Setenv(EnvRules, c.rules)
+
+			set, err := FromEnv()
+
+			if c.wantErr != "" {
+				assert.True(t.Fatal, err != nil)
+				assert.True(
+					t.Error, strings.HasPrefix(err.Error(), c.wantErr),
+				)
+				return
+			}
+			assert.Nil(t.Fatal, err)
+			assert.DeepEqual(t.Error, set, c.want)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package featureflag
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// Provider describes a type that can be used to fetch the rules of the flags
+// from a remote source, such as a feature flag service or a config store.
+type Provider interface {
+	Fetch(context.Context) (Set, error)
+}
+
+// Remote is a Checker that checks the flags against the rules last fetched
+// from a Provider, so that they can be changed without restarting the server.
+type Remote struct {
+	provider Provider
+	log      log.Errorer
+
+	mu  sync.Mutex
+	set Set
+}
+
+// NewRemote creates and returns a new Remote that checks the flags against the
+// given set until the rules are fetched from the provider.
+func NewRemote(provider Provider, fallback Set, log log.Errorer) *Remote {
+	return &Remote{provider: provider, log: log, set: fallback}
+}
+
+// Enabled returns whether the given flag is enabled for the team with the
+// given ID by the rules last fetched.
+func (r *Remote) Enabled(flag Flag, teamID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.set.Enabled(flag, teamID)
+}
+
+// Refresh fetches the rules from the provider. The rules last fetched are kept
+// if it fails.
+func (r *Remote) Refresh(ctx context.Context) error {
+	set, err := r.provider.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.set = set
+	return nil
+}
+
+// Run refreshes the rules at the given interval until ctx is done, logging the
+// errors in fetching them.
+func (r *Remote) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Refresh(ctx); err != nil {
+			r.log.Error("failed to refresh feature flags:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build utest
+
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// fakeProvider is a test fake for Provider.
+type fakeProvider struct {
+	set Set
+	err error
+}
+
+// Fetch returns the fake's set and error.
+func (f *fakeProvider) Fetch(context.Context) (Set, error) {
+	return f.set, f.err
+}
+
+// TestRemote tests Remote to assert that it checks the flags against the rules
+// last fetched from its provider, or the fallback until they are fetched.
+func TestRemote(t *testing.T) {
+	flag := Flag{Name: "flag"}
+	provider := &fakeProvider{}
+	sut := NewRemote(
+		provider, Set{"flag": {Teams: []string{"team1"}}}, &log.FakeErrorer{},
+	)
+
+	// fallback
+	assert.True(t.Error, sut.Enabled(flag, "team1"))
+	assert.True(t.Error, !sut.Enabled(flag, "team2"))
+
+	// fetched
+	provider.set = Set{"flag": {Teams: []string{"team2"}}}
+	assert.Nil(t.Fatal, sut.Refresh(context.Background()))
+	assert.True(t.Error, !sut.Enabled(flag, "team1"))
+	assert.True(t.Error, sut.Enabled(flag, "team2"))
+
+	// failed to fetch
+	provider.set, provider.err = nil, errors.New("fetch failed")
+	assert.ErrIs(t.Error, sut.Refresh(context.Background()), provider.err)
+	assert.True(t.Error, sut.Enabled(flag, "team2"))
+}
+
+// TestRemoteRun tests the Run method of Remote to assert that it fetches the
+// rules and logs the errors in fetching them until its context is done.
+func TestRemoteRun(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("fetch failed")}
+	log := &log.FakeErrorer{}
+	sut := NewRemote(provider, Set{}, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sut.Run(ctx, time.Hour)
+
+	assert.Equal(t.Fatal, len(log.Args), 2)
+	assert.Equal(t.Error, log.Args[0], "failed to refresh feature flags:")
+	assert.ErrIs(t.Error, log.Args[1].(error), provider.err)
+}
@@ -0,0 +1,261 @@
+// Package graphql contains a minimal GraphQL query parser and the helpers to
+// shape resolved values according to a query's selection sets. It supports
+// the subset of the query language needed by the API - anonymous or named
+// queries with nested selection sets and scalar arguments - and leaves out
+// mutations, variables, fragments, and directives.
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrSyntax means that the query could not be parsed.
+var ErrSyntax = errors.New("syntax error")
+
+// Field defines a field selected by a query.
+type Field struct {
+	Name       string
+	Args       map[string]string
+	Selections []Field
+}
+
+// Parse parses the given query and returns the fields selected at its root.
+func Parse(query string) ([]Field, error) {
+	p := &parser{src: query}
+	p.skipIgnored()
+	if name := p.peekName(); name == "query" {
+		p.name()
+		p.skipIgnored()
+		p.name()
+		p.skipIgnored()
+	} else if name != "" {
+		return nil, p.errorf("unsupported operation %q", name)
+	}
+	fields, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipIgnored(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return fields, nil
+}
+
+// Select returns the JSON representation of v reduced to the given selections.
+// A nil selection set returns v's JSON representation as is.
+func Select(v any, selections []Field) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw any
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return project(raw, selections)
+}
+
+// project reduces the given decoded JSON value to the given selections.
+func project(v any, selections []Field) (any, error) {
+	if selections == nil {
+		return v, nil
+	}
+	switch val := v.(type) {
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			projected, err := project(item, selections)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = projected
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(selections))
+		for _, f := range selections {
+			fv, ok := val[f.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", f.Name)
+			}
+			projected, err := project(fv, f.Selections)
+			if err != nil {
+				return nil, err
+			}
+			out[f.Name] = projected
+		}
+		return out, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, errors.New("cannot select fields of a scalar")
+	}
+}
+
+// parser holds the state of parsing a query.
+type parser struct {
+	src string
+	pos int
+}
+
+// selectionSet parses a selection set wrapped in curly braces.
+func (p *parser) selectionSet() ([]Field, error) {
+	if !p.consume('{') {
+		return nil, p.errorf("expected '{'")
+	}
+	var fields []Field
+	for {
+		p.skipIgnored()
+		if p.consume('}') {
+			if len(fields) == 0 {
+				return nil, p.errorf("empty selection set")
+			}
+			return fields, nil
+		}
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+}
+
+// field parses a field with its optional arguments and selection set.
+func (p *parser) field() (Field, error) {
+	name := p.name()
+	if name == "" {
+		return Field{}, p.errorf("expected field name")
+	}
+	f := Field{Name: name}
+	p.skipIgnored()
+	if p.consume('(') {
+		f.Args = map[string]string{}
+		for {
+			p.skipIgnored()
+			if p.consume(')') {
+				break
+			}
+			argName := p.name()
+			if argName == "" {
+				return Field{}, p.errorf("expected argument name")
+			}
+			p.skipIgnored()
+			if !p.consume(':') {
+				return Field{}, p.errorf("expected ':'")
+			}
+			p.skipIgnored()
+			val, err := p.value()
+			if err != nil {
+				return Field{}, err
+			}
+			f.Args[argName] = val
+		}
+		p.skipIgnored()
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '{' {
+		sel, err := p.selectionSet()
+		if err != nil {
+			return Field{}, err
+		}
+		f.Selections = sel
+	}
+	return f, nil
+}
+
+// value parses a string literal or a bare scalar (number, boolean, enum).
+func (p *parser) value() (string, error) {
+	if !p.consume('"') {
+		start := p.pos
+		for p.pos < len(p.src) && isValueChar(rune(p.src[p.pos])) {
+			p.pos++
+		}
+		if start == p.pos {
+			return "", p.errorf("expected value")
+		}
+		return p.src[start:p.pos], nil
+	}
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.pos == len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			b.WriteByte(p.src[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// name parses a name and returns it, or returns an empty string if there is
+// no name at the current position.
+func (p *parser) name() string {
+	n := p.peekName()
+	p.pos += len(n)
+	return n
+}
+
+// peekName returns the name at the current position without consuming it.
+func (p *parser) peekName() string {
+	end := p.pos
+	for end < len(p.src) {
+		c := rune(p.src[end])
+		if c == '_' || unicode.IsLetter(c) || (end > p.pos && unicode.IsDigit(c)) {
+			end++
+			continue
+		}
+		break
+	}
+	return p.src[p.pos:end]
+}
+
+// consume advances past the given character if it is at the current position
+// and reports whether it did.
+func (p *parser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipIgnored advances past whitespace, commas, and comments.
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ',' || unicode.IsSpace(rune(c)):
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// errorf returns a syntax error with the current position and the given
+// message.
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf(
+		"%w at position %d: %s", ErrSyntax, p.pos, fmt.Sprintf(format, args...),
+	)
+}
+
+// isValueChar reports whether c can be part of a bare scalar value.
+func isValueChar(c rune) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,146 @@
+//go:build utest
+
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestParse tests the Parse function to assert that it returns the correct
+// fields or error for the given query.
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		query   string
+		wantErr error
+		want    string
+	}{
+		{
+			name:    "Empty",
+			query:   "",
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "EmptySelection",
+			query:   "{ }",
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "Mutation",
+			query:   "mutation { team { id } }",
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "Unterminated",
+			query:   "{ team { id }",
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "TrailingChars",
+			query:   "{ team } }",
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "UnterminatedString",
+			query:   `{ tasks(boardID: "board1) { id } }`,
+			wantErr: ErrSyntax,
+			want:    "null",
+		},
+		{
+			name:    "Anonymous",
+			query:   "{ members }",
+			wantErr: nil,
+			want:    `[{"Name":"members","Args":null,"Selections":null}]`,
+		},
+		{
+			name: "NamedWithArgsAndComments",
+			query: `query Home {
+				# the team
+				team { id, boards { name } }
+				tasks(boardID: "board1", limit: 5) { id }
+			}`,
+			wantErr: nil,
+			want: `[` +
+				`{"Name":"team","Args":null,"Selections":[` +
+				`{"Name":"id","Args":null,"Selections":null},` +
+				`{"Name":"boards","Args":null,"Selections":[` +
+				`{"Name":"name","Args":null,"Selections":null}]}]},` +
+				`{"Name":"tasks","Args":{"boardID":"board1","limit":"5"},` +
+				`"Selections":[{"Name":"id","Args":null,"Selections":null}]}]`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			fields, err := Parse(c.query)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			got, err := json.Marshal(fields)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, string(got), c.want)
+		})
+	}
+}
+
+// TestSelect tests the Select function to assert that it reduces the given
+// value to the given selections.
+func TestSelect(t *testing.T) {
+	type board struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type team struct {
+		ID     string  `json:"id"`
+		Boards []board `json:"boards"`
+	}
+	v := team{ID: "team1", Boards: []board{{ID: "b1", Name: "Board 1"}}}
+
+	for _, c := range []struct {
+		name       string
+		selections []Field
+		wantErr    bool
+		want       string
+	}{
+		{
+			name:       "NoSelections",
+			selections: nil,
+			wantErr:    false,
+			want:       `{"boards":[{"id":"b1","name":"Board 1"}],"id":"team1"}`,
+		},
+		{
+			name:       "UnknownField",
+			selections: []Field{{Name: "foo"}},
+			wantErr:    true,
+			want:       "null",
+		},
+		{
+			name:       "SelectOnScalar",
+			selections: []Field{{Name: "id", Selections: []Field{{Name: "x"}}}},
+			wantErr:    true,
+			want:       "null",
+		},
+		{
+			name: "Nested",
+			selections: []Field{
+				{Name: "boards", Selections: []Field{{Name: "name"}}},
+			},
+			wantErr: false,
+			want:    `{"boards":[{"name":"Board 1"}]}`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := Select(v, c.selections)
+
+			assert.Equal(t.Error, err != nil, c.wantErr)
+			got, err := json.Marshal(res)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, string(got), c.want)
+		})
+	}
+}
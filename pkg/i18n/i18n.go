@@ -0,0 +1,74 @@
+// Package i18n contains the message catalog used to localize user-facing
+// messages, and the code to pick the language to localize them into based on
+// the Accept-Language header of a request.
+//
+// The catalog is keyed by the English messages the handlers and validators
+// respond with, so that a message missing from a catalog falls back to
+// English.
+package i18n
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Languages the user-facing messages are available in.
+const (
+	EN = "en"
+	TR = "tr"
+)
+
+// Default is the language the user-facing messages are written in, used when
+// the client accepts none of the other languages.
+const Default = EN
+
+// catalogs maps each language other than Default to its translations of the
+// English messages.
+var catalogs = map[string]map[string]string{
+	TR: tr,
+}
+
+// T returns the translation of the English message into the given language,
+// or the message itself if there is none.
+func T(lang, msg string) string {
+	if t, ok := catalogs[lang][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// FromRequest returns the language to localize the response to the request
+// into.
+func FromRequest(r *http.Request) string {
+	return Parse(r.Header.Get("Accept-Language"))
+}
+
+// Parse returns the supported language that is most preferred in the given
+// Accept-Language header value, or Default if there is none. Regional variants
+// (e.g. tr-TR) match their base language.
+func Parse(header string) string {
+	lang, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		base = strings.ToLower(base)
+		if base != Default && catalogs[base] == nil {
+			continue
+		}
+
+		q := 1.0
+		name, val, _ := strings.Cut(strings.TrimSpace(params), "=")
+		if strings.TrimSpace(name) == "q" {
+			var err error
+			q, err = strconv.ParseFloat(strings.TrimSpace(val), 64)
+			if err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			lang, bestQ = base, q
+		}
+	}
+	return lang
+}
@@ -0,0 +1,97 @@
+//go:build utest
+
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestT tests the T function to assert that it translates the messages in the
+// catalog and falls back to English for the rest.
+func TestT(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		lang string
+		msg  string
+		want string
+	}{
+		{
+			name: "Default",
+			lang: EN,
+			msg:  "Board not found.",
+			want: "Board not found.",
+		},
+		{
+			name: "Translated",
+			lang: TR,
+			msg:  "Board not found.",
+			want: "Pano bulunamadı.",
+		},
+		{
+			name: "NotInCatalog",
+			lang: TR,
+			msg:  "Something else.",
+			want: "Something else.",
+		},
+		{
+			name: "UnsupportedLang",
+			lang: "de",
+			msg:  "Board not found.",
+			want: "Board not found.",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, T(c.lang, c.msg), c.want)
+		})
+	}
+}
+
+// TestParse tests the Parse function to assert that it picks the most
+// preferred supported language in the Accept-Language header.
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Empty", header: "", want: EN},
+		{name: "Unsupported", header: "de-DE, fr;q=0.8", want: EN},
+		{name: "Wildcard", header: "*", want: EN},
+		{name: "Turkish", header: "tr", want: TR},
+		{name: "Region", header: "tr-TR", want: TR},
+		{name: "UpperCase", header: "TR-tr", want: TR},
+		{name: "FirstOfEqualQ", header: "en, tr", want: EN},
+		{name: "HigherQ", header: "en;q=0.5, tr;q=0.9", want: TR},
+		{name: "FallbackToSupported", header: "de, tr;q=0.5", want: TR},
+		{name: "ZeroQ", header: "tr;q=0", want: EN},
+		{name: "InvalidQ", header: "tr;q=high", want: EN},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Parse(c.header), c.want)
+		})
+	}
+}
+
+// TestFromRequest tests the FromRequest function to assert that it reads the
+// Accept-Language header of the request.
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "tr-TR,tr;q=0.9,en;q=0.8")
+
+	assert.Equal(t.Error, FromRequest(r), TR)
+}
+
+// TestCatalogs tests the catalogs to assert that each of their entries has a
+// translation.
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, translation := range catalog {
+			if translation == "" || translation == msg {
+				t.Errorf("%s: missing translation for %q", lang, msg)
+			}
+		}
+	}
+}
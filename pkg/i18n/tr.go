@@ -0,0 +1,327 @@
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// tr is the Turkish message catalog.
+var tr = map[string]string{
+	// auth
+	"Auth token not found.": "Oturum anahtarı bulunamadı.",
+	"Invalid auth token.":   "Geçersiz oturum anahtarı.",
+	"Invalid CSRF token.":   "Geçersiz CSRF anahtarı.",
+
+	// requests
+	"Invalid request body.":            "Geçersiz istek gövdesi.",
+	"Invalid cursor.":                  "Geçersiz sayfa imleci.",
+	"Limit must be between 1 and 100.": "Limit 1 ile 100 arasında olmalıdır.",
+	"A request with this idempotency key is already in progress.": "Bu " +
+		"idempotency anahtarına sahip bir istek zaten işleniyor.",
+	"Idempotency key was already used for a different request.": "Bu " +
+		"idempotency anahtarı farklı bir istek için zaten kullanıldı.",
+	"Request could not be completed. Please try again.": "İstek " +
+		"tamamlanamadı. Lütfen tekrar deneyin.",
+	"Service is temporarily unavailable. Please try again later.": "Hizmet " +
+		"geçici olarak kullanılamıyor. Lütfen daha sonra tekrar deneyin.",
+	"Feature is not enabled for your team.": "Bu özellik takımınız için " +
+		"etkin değil.",
+
+	// users
+	"User not found.":            "Kullanıcı bulunamadı.",
+	"Session not found.":         "Oturum bulunamadı.",
+	"Username is already taken.": "Bu kullanıcı adı zaten alınmış.",
+	"Username cannot be empty.":  "Kullanıcı adı boş olamaz.",
+	fmt.Sprintf(
+		"Username cannot be shorter than %d characters.",
+		validator.MinUsernameLen,
+	): fmt.Sprintf(
+		"Kullanıcı adı %d karakterden kısa olamaz.", validator.MinUsernameLen,
+	),
+	fmt.Sprintf(
+		"Username cannot be longer than %d characters.",
+		validator.MaxUsernameLen,
+	): fmt.Sprintf(
+		"Kullanıcı adı %d karakterden uzun olamaz.", validator.MaxUsernameLen,
+	),
+	"Username can contain only letters (a-z/A-Z) and digits (0-9).": "" +
+		"Kullanıcı adı yalnızca harf (a-z/A-Z) ve rakam (0-9) içerebilir.",
+	"Username can start only with a letter (a-z/A-Z).": "Kullanıcı adı " +
+		"yalnızca bir harfle (a-z/A-Z) başlayabilir.",
+	"Password cannot be empty.": "Şifre boş olamaz.",
+	fmt.Sprintf(
+		"Password cannot be shorter than %d characters.",
+		validator.MinPasswordLen,
+	): fmt.Sprintf(
+		"Şifre %d karakterden kısa olamaz.", validator.MinPasswordLen,
+	),
+	fmt.Sprintf(
+		"Password cannot be longer than %d characters.",
+		validator.MaxPasswordLen,
+	): fmt.Sprintf(
+		"Şifre %d karakterden uzun olamaz.", validator.MaxPasswordLen,
+	),
+	"Password must contain a lowercase letter (a-z).": "Şifre bir küçük " +
+		"harf (a-z) içermelidir.",
+	"Password must contain an uppercase letter (A-Z).": "Şifre bir büyük " +
+		"harf (A-Z) içermelidir.",
+	"Password must contain a digit (0-9).": "Şifre bir rakam (0-9) " +
+		"içermelidir.",
+	"Password must contain one of the following special characters: " +
+		"! \" # $ % & ' ( ) * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~.": "" +
+		"Şifre şu özel karakterlerden birini içermelidir: ! \" # $ % & ' ( " +
+		") * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~.",
+	"Password cannot contain spaces.": "Şifre boşluk içeremez.",
+	"Password can contain only letters (a-z/A-Z), digits (0-9), and the " +
+		"following special characters: ! \" # $ % & ' ( ) * + , - . / : ; " +
+		"< = > ? [ \\ ] ^ _ ` { | } ~.": "Şifre yalnızca harf (a-z/A-Z), " +
+		"rakam (0-9) ve şu özel karakterleri içerebilir: ! \" # $ % & ' ( ) " +
+		"* + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~.",
+	"Password cannot be based on a commonly used password.": "Şifre sık " +
+		"kullanılan bir şifreye dayanamaz.",
+	"Password cannot contain repeated characters (e.g. aaa).": "Şifre " +
+		"tekrarlanan karakterler (ör. aaa) içeremez.",
+	"Password cannot contain sequences (e.g. abc, 123).": "Şifre ardışık " +
+		"karakterler (ör. abc, 123) içeremez.",
+	"Password cannot contain keyboard patterns (e.g. qwerty).": "Şifre " +
+		"klavye desenleri (ör. qwerty) içeremez.",
+	"Password cannot contain years (e.g. 1990).": "Şifre yıl (ör. 1990) " +
+		"içeremez.",
+	"Password is too easy to guess. Make it longer or less predictable.": "" +
+		"Şifre tahmin edilmesi çok kolay. Daha uzun veya daha az " +
+		"öngörülebilir yapın.",
+	"Email cannot be empty.": "E-posta boş olamaz.",
+	"Email cannot be longer than 254 characters.": "E-posta 254 " +
+		"karakterden uzun olamaz.",
+	"Email is invalid.": "E-posta geçersiz.",
+	"CAPTCHA verification failed. Please try again.": "CAPTCHA doğrulaması " +
+		"başarısız oldu. Lütfen tekrar deneyin.",
+	"CAPTCHA could not be verified. Please try again later.": "CAPTCHA " +
+		"doğrulanamadı. Lütfen daha sonra tekrar deneyin.",
+	"You have been registered successfully but something went wrong. " +
+		"Please log in using the credentials you registered with.": "" +
+		"Kaydınız başarıyla tamamlandı ancak bir sorun oluştu. Lütfen " +
+		"kayıt olduğunuz bilgilerle giriş yapın.",
+	"Username is reserved.": "Bu kullanıcı adı ayrılmıştır.",
+	"Username is too similar to that of a team member.": "Kullanıcı " +
+		"adı bir ekip üyesininkine çok benziyor.",
+	"This email address belongs to more than one account. " +
+		"Please log in with your username.": "Bu e-posta adresi birden " +
+		"fazla hesaba ait. Lütfen kullanıcı adınızla giriş yapın.",
+	"Avatar must be a PNG, JPEG, or GIF image of up to 2 MB.": "Profil " +
+		"resmi en fazla 2 MB boyutunda bir PNG, JPEG veya GIF resmi " +
+		"olmalıdır.",
+	"Avatar cannot be larger than 4096x4096 pixels.": "Profil resmi " +
+		"4096x4096 pikselden büyük olamaz.",
+
+	// teams
+	"Team not found.": "Takım bulunamadı.",
+	"Team was modified by someone else. Please try again.": "Takım başka " +
+		"biri tarafından değiştirildi. Lütfen tekrar deneyin.",
+	"Team was modified by someone else. Please refresh the page and try " +
+		"again.": "Takım başka biri tarafından değiştirildi. Lütfen sayfayı " +
+		"yenileyip tekrar deneyin.",
+	"Team is already on the paid plan.": "Takım zaten ücretli planda.",
+	"This team has reached its member limit. Please ask the team admin to " +
+		"raise it.": "Bu takım üye sınırına ulaştı. Lütfen takım " +
+		"yöneticisinden sınırı artırmasını isteyin.",
+	"Invalid invite token.": "Geçersiz davet anahtarı.",
+	"Invite token has expired or has already been used.": "Davet " +
+		"anahtarının süresi dolmuş veya zaten kullanılmış.",
+	"Invite email could not be sent. Please try again later.": "Davet " +
+		"e-postası gönderilemedi. Lütfen daha sonra tekrar deneyin.",
+	"Checkout could not be started. Please try again later.": "Ödeme " +
+		"başlatılamadı. Lütfen daha sonra tekrar deneyin.",
+	"Webhook URL cannot be longer than 500 characters.": "Webhook URL'si " +
+		"500 karakterden uzun olamaz.",
+	"Webhook URL must be a Slack incoming webhook URL.": "Webhook URL'si " +
+		"bir Slack gelen webhook URL'si olmalıdır.",
+	"Metadata cannot be longer than 100000 characters.": "Meta veriler " +
+		"100000 karakterden uzun olamaz.",
+	"Username attribute cannot be longer than 200 characters.": "" +
+		"Kullanıcı adı özniteliği 200 karakterden uzun olamaz.",
+	"Metadata must be the SAML metadata of an identity provider.": "Meta " +
+		"veriler bir kimlik sağlayıcısının SAML meta verileri olmalıdır.",
+	"Item not found in trash.": "Öğe çöp kutusunda bulunamadı.",
+	"Only team admins can invite members.": "Yalnızca takım yöneticileri " +
+		"üye davet edebilir.",
+	"Guests cannot see the team's members.": "Misafirler takımın " +
+		"üyelerini göremez.",
+	"Search term cannot be empty.": "Arama terimi boş olamaz.",
+	"Only team admins can manage billing.": "Yalnızca takım yöneticileri " +
+		"faturalandırmayı yönetebilir.",
+	"Only team admins can view integrations.": "Yalnızca takım yöneticileri " +
+		"entegrasyonları görüntüleyebilir.",
+	"Only team admins can edit integrations.": "Yalnızca takım yöneticileri " +
+		"entegrasyonları düzenleyebilir.",
+	"Only team admins can view single sign-on settings.": "Yalnızca takım " +
+		"yöneticileri çoklu oturum açma ayarlarını görüntüleyebilir.",
+	"Only team admins can edit single sign-on settings.": "Yalnızca takım " +
+		"yöneticileri çoklu oturum açma ayarlarını düzenleyebilir.",
+	"Only team admins can manage the SCIM token.": "Yalnızca takım " +
+		"yöneticileri SCIM anahtarını yönetebilir.",
+	"Only team admins can view the audit log.": "Yalnızca takım " +
+		"yöneticileri denetim kaydını görüntüleyebilir.",
+	"Only team admins can view the trash.": "Yalnızca takım yöneticileri " +
+		"çöp kutusunu görüntüleyebilir.",
+	"Only team admins can restore boards and tasks.": "Yalnızca takım " +
+		"yöneticileri panoları ve görevleri geri yükleyebilir.",
+	"Only team admins can undo actions.": "Yalnızca takım yöneticileri " +
+		"işlemleri geri alabilir.",
+	"Nothing to undo.": "Geri alınacak bir şey yok.",
+	"The moved tasks have been changed since. Nothing to undo.": "Taşınan " +
+		"görevler o zamandan beri değiştirildi. Geri alınacak bir şey yok.",
+
+	// boards
+	"Board not found.":          "Pano bulunamadı.",
+	"Board already exists.":     "Pano zaten mevcut.",
+	"Board ID cannot be empty.": "Pano kimliği boş olamaz.",
+	"Board ID must be a UUID.":  "Pano kimliği bir UUID olmalıdır.",
+	"Board ID is must be a valid UUID.": "Pano kimliği geçerli bir UUID " +
+		"olmalıdır.",
+	"Board name cannot be empty.": "Pano adı boş olamaz.",
+	"Board name cannot be longer than 35 characters.": "Pano adı 35 " +
+		"karakterden uzun olamaz.",
+	"Board description cannot be longer than 1000 characters.": "Pano " +
+		"açıklaması 1000 karakterden uzun olamaz.",
+	"Board visibility must be team or restricted.": "Pano görünürlüğü " +
+		"team veya restricted olmalıdır.",
+	"Archive days must be between 0 and 365.": "Arşivleme gün sayısı 0 " +
+		"ile 365 arasında olmalıdır.",
+	"Blocked tasks setting must be warn or refuse.": "Engellenen görevler " +
+		"ayarı warn veya refuse olmalıdır.",
+	"Board was modified by someone else. Please refresh the page and try " +
+		"again.": "Pano başka biri tarafından değiştirildi. Lütfen sayfayı " +
+		"yenileyip tekrar deneyin.",
+	"You do not have access to this board.": "Bu panoya erişiminiz yok.",
+	"Only team admins can edit boards.": "Yalnızca takım yöneticileri " +
+		"panoları düzenleyebilir.",
+	"Only team admins can import boards.": "Yalnızca takım yöneticileri " +
+		"pano içe aktarabilir.",
+	"Only team admins can share boards.": "Yalnızca takım yöneticileri " +
+		"panoları paylaşabilir.",
+	"Share token cannot be empty.": "Paylaşım anahtarı boş olamaz.",
+	"Share link is invalid or was revoked.": "Paylaşım bağlantısı " +
+		"geçersiz veya iptal edilmiş.",
+	"You have already created the maximum amount of boards allowed per " +
+		"team. Please delete one of your boards to create a new one.": "" +
+		"Takım başına izin verilen en fazla sayıda panoyu zaten " +
+		"oluşturdunuz. Yeni bir pano oluşturmak için panolarınızdan birini " +
+		"silin.",
+	"You have already created the maximum amount of boards allowed per " +
+		"team. Please delete one of your boards to import a new one.": "" +
+		"Takım başına izin verilen en fazla sayıda panoyu zaten " +
+		"oluşturdunuz. Yeni bir pano içe aktarmak için panolarınızdan " +
+		"birini silin.",
+	"You have already created the maximum amount of boards allowed per " +
+		"team. Please delete one of your boards to restore this one.": "" +
+		"Takım başına izin verilen en fazla sayıda panoyu zaten " +
+		"oluşturdunuz. Bu panoyu geri yüklemek için panolarınızdan birini " +
+		"silin.",
+	"Board cannot have more than 4 columns.": "Pano 4'ten fazla sütuna " +
+		"sahip olamaz.",
+	"Column color must be a hex color (e.g. #1a2b3c).": "Sütun rengi " +
+		"onaltılık bir renk (ör. #1a2b3c) olmalıdır.",
+	"Column description cannot be longer than 200 characters.": "Sütun " +
+		"açıklaması 200 karakterden uzun olamaz.",
+	"Import file is not a valid board export.": "İçe aktarma dosyası " +
+		"geçerli bir pano dışa aktarımı değil.",
+	"Import file format is not supported.": "İçe aktarma dosyasının " +
+		"biçimi desteklenmiyor.",
+	"Import file has an invalid column.": "İçe aktarma dosyasında geçersiz " +
+		"bir sütun var.",
+	"Import file has tasks with missing or duplicate IDs.": "İçe aktarma " +
+		"dosyasında kimliği eksik veya yinelenen görevler var.",
+	"Import file has a task with an empty or too long title, description, " +
+		"or subtask.": "İçe aktarma dosyasında başlığı, açıklaması veya alt " +
+		"görevi boş ya da çok uzun olan bir görev var.",
+
+	// sprints
+	"Sprint not found.":            "Sprint bulunamadı.",
+	"Sprint or board not found.":   "Sprint veya pano bulunamadı.",
+	"Sprint ID cannot be empty.":   "Sprint kimliği boş olamaz.",
+	"Sprint ID must be a UUID.":    "Sprint kimliği bir UUID olmalıdır.",
+	"Sprint name cannot be empty.": "Sprint adı boş olamaz.",
+	"Sprint name cannot be longer than 35 characters.": "Sprint adı 35 " +
+		"karakterden uzun olamaz.",
+	"Sprint cannot end before it starts.": "Sprint başlamadan önce " +
+		"bitemez.",
+	"Sprint dates must be in the YYYY-MM-DD format.": "Sprint tarihleri " +
+		"YYYY-AA-GG biçiminde olmalıdır.",
+	"Only team admins can create sprints.": "Yalnızca takım yöneticileri " +
+		"sprint oluşturabilir.",
+	"Only team admins can edit sprints.": "Yalnızca takım yöneticileri " +
+		"sprintleri düzenleyebilir.",
+
+	// checklists
+	"Checklist not found.":          "Kontrol listesi bulunamadı.",
+	"Checklist ID cannot be empty.": "Kontrol listesi kimliği boş olamaz.",
+	"Checklist ID must be a UUID.": "Kontrol listesi kimliği bir UUID " +
+		"olmalıdır.",
+	"Checklist name cannot be empty.": "Kontrol listesi adı boş olamaz.",
+	"Checklist name cannot be longer than 35 characters.": "Kontrol " +
+		"listesi adı 35 karakterden uzun olamaz.",
+	"Checklist must have at least one item.": "Kontrol listesinde en az " +
+		"bir madde olmalıdır.",
+	"Checklist cannot have more than 30 items.": "Kontrol listesinde " +
+		"30'dan fazla madde olamaz.",
+	"Checklist items cannot be empty.": "Kontrol listesi maddeleri boş " +
+		"olamaz.",
+	"Checklist items cannot be longer than 50 characters.": "Kontrol " +
+		"listesi maddeleri 50 karakterden uzun olamaz.",
+	"A team cannot have more than 20 checklists.": "Bir takımın 20'den " +
+		"fazla kontrol listesi olamaz.",
+	"Only team admins can create checklists.": "Yalnızca takım " +
+		"yöneticileri kontrol listesi oluşturabilir.",
+	"Only team admins can edit checklists.": "Yalnızca takım yöneticileri " +
+		"kontrol listelerini düzenleyebilir.",
+
+	// analytics
+	"Dates must be in the YYYY-MM-DD format.": "Tarihler YYYY-AA-GG " +
+		"biçiminde olmalıdır.",
+	"Start date cannot be after end date.": "Başlangıç tarihi bitiş " +
+		"tarihinden sonra olamaz.",
+	"Date range cannot be longer than 90 days.": "Tarih aralığı 90 " +
+		"günden uzun olamaz.",
+
+	// tasks
+	"Task not found.":             "Görev bulunamadı.",
+	"Task already exists.":        "Görev zaten mevcut.",
+	"Task ID cannot be empty.":    "Görev kimliği boş olamaz.",
+	"No tasks provided.":          "Hiç görev gönderilmedi.",
+	"Task title cannot be empty.": "Görev başlığı boş olamaz.",
+	"Task title cannot be longer than 50 characters.": "Görev başlığı 50 " +
+		"karakterden uzun olamaz.",
+	"Task description cannot be longer than 500 characters.": "Görev " +
+		"açıklaması 500 karakterden uzun olamaz.",
+	"Subtask title cannot be empty.": "Alt görev başlığı boş olamaz.",
+	"Subtask title cannot be longer than 50 characters.": "Alt görev " +
+		"başlığı 50 karakterden uzun olamaz.",
+	"Column number must be between 0 and 3.": "Sütun numarası 0 ile 3 " +
+		"arasında olmalıdır.",
+	"Invalid column number.":       "Geçersiz sütun numarası.",
+	"Order cannot be negative.":    "Sıra negatif olamaz.",
+	"Position cannot be negative.": "Konum negatif olamaz.",
+	"A task cannot be blocked by more than 20 tasks.": "Bir görev 20'den " +
+		"fazla görev tarafından engellenemez.",
+	"A task cannot block itself.": "Bir görev kendisini engelleyemez.",
+	"Blocking task not found.":    "Engelleyen görev bulunamadı.",
+	"Tasks cannot block each other in a cycle.": "Görevler birbirlerini " +
+		"döngü halinde engelleyemez.",
+	"Task is blocked by tasks that are not done.": "Görev, tamamlanmamış " +
+		"görevler tarafından engelleniyor.",
+	"Only team admins can create tasks.": "Yalnızca takım yöneticileri " +
+		"görev oluşturabilir.",
+	"Only team admins can edit tasks.": "Yalnızca takım yöneticileri " +
+		"görevleri düzenleyebilir.",
+	"Only team admins can delete tasks.": "Yalnızca takım yöneticileri " +
+		"görevleri silebilir.",
+	"The task's board is deleted. Restore the board first.": "Görevin " +
+		"panosu silinmiş. Önce panoyu geri yükleyin.",
+	"You have already created the maximum amount of tasks allowed per " +
+		"board. Please delete one of the board's tasks to create a new " +
+		"one.": "Pano başına izin verilen en fazla sayıda görevi zaten " +
+		"oluşturdunuz. Yeni bir görev oluşturmak için panonun " +
+		"görevlerinden birini silin.",
+}
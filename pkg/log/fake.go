@@ -6,3 +6,10 @@ type FakeErrorer struct{ Args []any }
 // Log implements the Errorer interface on FakeErrorer. It assigns the message
 // passed into it to the InMessage field on the fake instance.
 func (f *FakeErrorer) Error(args ...any) { f.Args = args }
+
+// FakeInfoer is a test fake for Infoer.
+type FakeInfoer struct{ Args []any }
+
+// Info implements the Infoer interface on FakeInfoer. It assigns the args
+// passed into it to the Args field on the fake instance.
+func (f *FakeInfoer) Info(args ...any) { f.Args = args }
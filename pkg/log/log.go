@@ -3,30 +3,71 @@
 package log
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/report"
 )
 
+// reportTimeout is how long reporting a logged error may take.
+const reportTimeout = 10 * time.Second
+
 // Errorer describes a type that can be used to log an error-level message to
 // the console.
 type Errorer interface{ Error(...any) }
 
+// Infoer describes a type that can be used to log an information-level message
+// to the console.
+type Infoer interface{ Info(...any) }
+
 // Log can be used to log messages of different log levels across the project.
-type Log struct{}
+type Log struct{ reporter report.Reporter }
 
 // New creates and returns a new Log.
 func New() Log { return Log{} }
 
+// ReportTo returns a copy of the Log that also reports the error-level and
+// fatal-level messages it logs to the given reporter.
+func (l Log) ReportTo(reporter report.Reporter) Log {
+	l.reporter = reporter
+	return l
+}
+
 // Info logs an information-level message to the console.
 func (l Log) Info(args ...any) {
 	log.Println(append([]any{"--[INFO]--"}, args...)...)
 }
 
-// Info logs an error-level message to the console.
+// Error logs an error-level message to the console. It is reported in the
+// background so that the caller does not wait for the reporter.
 func (l Log) Error(args ...any) {
 	log.Println(append([]any{"--[ERROR]--"}, args...)...)
+	if l.reporter != nil {
+		go l.report(report.LevelError, args)
+	}
 }
 
-// Fatal logs a fatal-level message to the console.
+// Fatal logs a fatal-level message to the console. It is reported before it
+// returns as the program is expected to exit right after.
 func (l Log) Fatal(args ...any) {
 	log.Println(append([]any{"--[FATAL]--"}, args...)...)
+	if l.reporter != nil {
+		l.report(report.LevelFatal, args)
+	}
+}
+
+// report reports a message logged at the given level. An error reporting it is
+// only printed, so that it is not reported in turn.
+func (l Log) report(level report.Level, args []any) {
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	msg := fmt.Sprintln(args...)
+	if err := l.reporter.Report(ctx, report.Event{
+		Level: level, Message: msg[:len(msg)-1],
+	}); err != nil {
+		log.Println("--[ERROR]--", "failed to report error:", err)
+	}
 }
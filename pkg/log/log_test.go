@@ -4,10 +4,13 @@ package log
 
 import (
 	"bytes"
+	"io"
 	"log"
+	"os"
 	"testing"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/report"
 )
 
 func TestLog(t *testing.T) {
@@ -51,3 +54,25 @@ func TestLog(t *testing.T) {
 		})
 	}
 }
+
+// TestLogReportTo tests the Log returned by ReportTo to assert that it reports
+// the error-level and fatal-level messages it logs, but not the others.
+func TestLogReportTo(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	reporter := &report.FakeReporter{Events: make(chan report.Event, 1)}
+	sut := New().ReportTo(reporter)
+
+	sut.Info("some information")
+	sut.Error("an error occured:", 42)
+	e := <-reporter.Events
+	assert.Equal(t.Error, e.Level, report.LevelError)
+	assert.Equal(t.Error, e.Message, "an error occured: 42")
+
+	sut.Fatal("fatal error occured")
+	e = <-reporter.Events
+	assert.Equal(t.Error, e.Level, report.LevelFatal)
+	assert.Equal(t.Error, e.Message, "fatal error occured")
+
+	assert.Equal(t.Error, len(reporter.Events), 0)
+}
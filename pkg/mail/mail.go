@@ -0,0 +1,54 @@
+// Package mail contains code for sending emails to users, such as invites to
+// join a team.
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidHeader means that a message's recipient or subject contained a line
+// break, which could be used to inject headers into the message.
+var ErrInvalidHeader = errors.New("invalid header value")
+
+// Message defines a plain-text email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender describes a type that can be used to send an email message.
+type Sender interface {
+	Send(context.Context, Message) error
+}
+
+// Infoer describes a type that can be used to log an info-level message.
+type Infoer interface{ Info(...any) }
+
+// Log is a Sender that logs messages instead of sending them. It is used when
+// no mail server is configured, such as on local and in demo mode.
+type Log struct{ log Infoer }
+
+// NewLog creates and returns a new Log.
+func NewLog(log Infoer) Log { return Log{log: log} }
+
+// Send logs the given message.
+func (l Log) Send(_ context.Context, m Message) error {
+	if err := validate(m); err != nil {
+		return err
+	}
+	l.log.Info("mail to", m.To, "-", m.Subject+"\n"+m.Body)
+	return nil
+}
+
+// validate returns ErrInvalidHeader if the message's recipient or subject
+// contains a line break.
+func validate(m Message) error {
+	if strings.ContainsAny(m.To, "\r\n") ||
+		strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+// QueueKind is the kind of the queue messages that Queued sends.
+const QueueKind = "mail"
+
+// Queued is a Sender that queues messages for a worker to send so that the
+// sender does not wait for the mail server, and the messages are retried if
+// the server is down.
+type Queued struct{ queue queue.Sender }
+
+// NewQueued creates and returns a new Queued that queues messages on the given
+// queue.
+func NewQueued(q queue.Sender) Queued { return Queued{queue: q} }
+
+// Send queues the given message to be sent. Messages that would inject headers
+// are rejected before they are queued.
+func (q Queued) Send(ctx context.Context, m Message) error {
+	if err := validate(m); err != nil {
+		return err
+	}
+	return queue.Send(ctx, q.queue, QueueKind, m)
+}
+
+// NewHandler returns a queue.Handler that sends the messages queued by Queued
+// through the given Sender.
+func NewHandler(s Sender) queue.Handler {
+	return func(ctx context.Context, body json.RawMessage) error {
+		var m Message
+		if err := json.Unmarshal(body, &m); err != nil {
+			return err
+		}
+		return s.Send(ctx, m)
+	}
+}
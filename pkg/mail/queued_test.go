@@ -0,0 +1,44 @@
+//go:build utest
+
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+// TestQueued tests Queued and NewHandler to assert that the messages queued by
+// the former are sent by the latter, and that messages that would inject
+// headers are not queued.
+func TestQueued(t *testing.T) {
+	ctx := context.Background()
+	q := &queue.FakeSender{}
+	sut := NewQueued(q)
+
+	t.Run("InvalidHeader", func(t *testing.T) {
+		err := sut.Send(ctx, Message{To: "a@b.c\nBcc: d@e.f"})
+
+		assert.ErrIs(t.Error, err, ErrInvalidHeader)
+		assert.Equal(t.Error, len(q.Messages), 0)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		msg := Message{To: "a@b.c", Subject: "Hi", Body: "Hello"}
+
+		err := sut.Send(ctx, msg)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(q.Messages), 1)
+		assert.Equal(t.Error, q.Messages[0].Kind, QueueKind)
+
+		sender := &FakeSender{}
+		err = NewHandler(sender)(ctx, q.Messages[0].Body)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(sender.Messages), 1)
+		assert.Equal(t.Error, sender.Messages[0], msg)
+	})
+}
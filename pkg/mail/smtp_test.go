@@ -0,0 +1,53 @@
+//go:build utest
+
+package mail
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestFormat tests the format function to assert that it adds the headers to
+// messages and rejects the ones that would inject headers.
+func TestFormat(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		msg     Message
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "ToLineBreak",
+			msg:     Message{To: "a@b.c\r\nBcc: d@e.f", Subject: "Hi"},
+			want:    "",
+			wantErr: ErrInvalidHeader,
+		},
+		{
+			name:    "SubjectLineBreak",
+			msg:     Message{To: "a@b.c", Subject: "Hi\nBcc: d@e.f"},
+			want:    "",
+			wantErr: ErrInvalidHeader,
+		},
+		{
+			name: "OK",
+			msg: Message{
+				To: "a@b.c", Subject: "Hi", Body: "Line one\nLine two",
+			},
+			want: "From: goteam@b.c\r\n" +
+				"To: a@b.c\r\n" +
+				"Subject: Hi\r\n" +
+				"MIME-Version: 1.0\r\n" +
+				"Content-Type: text/plain; charset=UTF-8\r\n\r\n" +
+				"Line one\r\nLine two",
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			msg, err := format("goteam@b.c", c.msg)
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			assert.Equal(t.Error, string(msg), c.want)
+		})
+	}
+}
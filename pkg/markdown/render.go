@@ -0,0 +1,342 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxQuoteDepth is the deepest that blockquotes are nested when rendered.
+// Deeper ones are rendered as text so that the size of the HTML stays in
+// proportion to that of the markdown.
+const maxQuoteDepth = 4
+
+// linkRel is the rel attribute of the links in rendered markdown, which keeps
+// the pages they open from accessing the app and search engines from crediting
+// them.
+const linkRel = "nofollow noopener noreferrer"
+
+// Render renders the markdown src into HTML. It supports the common subset of
+// markdown - headings, paragraphs, emphasis, code, blockquotes, lists, rules,
+// and links - and escapes everything else, including raw HTML. Links to URLs
+// with schemes other than http, https, and mailto are rendered as their text,
+// and images as links to them so that they are not loaded by viewing them.
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.Map(dropInvisible, src)
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"), 0)
+	return b.String()
+}
+
+// renderBlocks renders lines as block elements, within depth blockquotes.
+func renderBlocks(b *strings.Builder, lines []string, depth int) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		t := strings.TrimLeft(line, " ")
+		switch {
+		case t == "":
+			i++
+
+		case isFence(line):
+			fence, _ := fenceOf(line)
+			var code []string
+			for i++; i < len(lines); i++ {
+				if f, ok := fenceOf(lines[i]); ok && f == fence {
+					i++
+					break
+				}
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+
+		case headingLevel(t) > 0:
+			lvl := strconv.Itoa(headingLevel(t))
+			text := strings.TrimRight(
+				strings.TrimSpace(strings.TrimLeft(t, "#")), "#",
+			)
+			b.WriteString("<h" + lvl + ">")
+			renderInline(b, strings.TrimSpace(text), true)
+			b.WriteString("</h" + lvl + ">\n")
+			i++
+
+		case isRule(t):
+			b.WriteString("<hr>\n")
+			i++
+
+		case isQuote(t, depth):
+			var quoted []string
+			for ; i < len(lines); i++ {
+				q := strings.TrimLeft(lines[i], " ")
+				if !strings.HasPrefix(q, ">") {
+					break
+				}
+				quoted = append(quoted, strings.TrimPrefix(q[1:], " "))
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted, depth+1)
+			b.WriteString("</blockquote>\n")
+
+		case isListItem(t):
+			i = renderList(b, lines, i)
+
+		default:
+			// a paragraph runs until a blank line or the start of another
+			// block, and has at least the line it started with
+			para := []string{t}
+			for i++; i < len(lines); i++ {
+				t := strings.TrimLeft(lines[i], " ")
+				if t == "" || isBlockStart(lines[i], depth) {
+					break
+				}
+				para = append(para, t)
+			}
+			b.WriteString("<p>")
+			renderInline(b, strings.Join(para, "\n"), true)
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// renderList renders the list that starts at lines[i], and returns the index
+// of the line after it. The lines that are indented under an item and are not
+// items themselves are continuations of the item's text.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	ordered, _ := listItem(strings.TrimLeft(lines[i], " "))
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag + ">\n")
+
+	var item []string
+	flush := func() {
+		if item == nil {
+			return
+		}
+		b.WriteString("<li>")
+		renderInline(b, strings.Join(item, "\n"), true)
+		b.WriteString("</li>\n")
+		item = nil
+	}
+	for ; i < len(lines); i++ {
+		t := strings.TrimLeft(lines[i], " ")
+		if isListItem(t) {
+			o, text := listItem(t)
+			if o != ordered {
+				break
+			}
+			flush()
+			item = []string{text}
+			continue
+		}
+		if t == "" || len(lines[i])-len(t) < 2 {
+			break
+		}
+		item = append(item, t)
+	}
+	flush()
+
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderInline renders s as inline markdown, with links unless links is
+// false, which is the case in the text of a link.
+func renderInline(b *strings.Builder, s string, links bool) {
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+
+		case c == '`':
+			n := runLen(s, i, '`')
+			end := closingRun(s, i+n, n)
+			if end == -1 {
+				b.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			code := s[i+n : end]
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			i = end + n
+
+		case c == '*' || c == '_':
+			n := emphasis(b, s, i, links)
+			i += n
+
+		case links && (c == '[' || c == '!' && strings.HasPrefix(s[i:], "![")):
+			n := link(b, s, i)
+			i += n
+
+		case links && c == '<':
+			i += autoLink(b, s, i)
+
+		default:
+			j := i + 1
+			for j < len(s) && !strings.ContainsRune("\\`*_[!<", rune(s[j])) {
+				j++
+			}
+			b.WriteString(html.EscapeString(s[i:j]))
+			i = j
+		}
+	}
+}
+
+// emphasis renders the emphasis that starts with the run of * or _ at s[i],
+// or the run itself if it is not closed, and returns the length of what it
+// rendered. Runs of two or more are rendered as strong emphasis. Underscores
+// within words are not emphasis, so that snake_case names are left intact.
+func emphasis(b *strings.Builder, s string, i int, links bool) int {
+	c := s[i]
+	n := runLen(s, i, c)
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		b.WriteString(s[i : i+n])
+		return n
+	}
+
+	delim, tag := s[i:i+1], "em"
+	if n >= 2 {
+		delim, tag = s[i:i+2], "strong"
+	}
+	start := i + len(delim)
+	end := strings.Index(s[start:], delim)
+	if end <= 0 || s[start] == ' ' || s[start+end-1] == ' ' {
+		b.WriteString(s[i : i+n])
+		return n
+	}
+
+	b.WriteString("<" + tag + ">")
+	renderInline(b, s[start:start+end], links)
+	b.WriteString("</" + tag + ">")
+	return len(delim) + end + len(delim)
+}
+
+// link renders the inline link or image that starts at s[i], or its opening
+// bracket if it is not one, and returns the length of what it rendered.
+func link(b *strings.Builder, s string, i int) int {
+	m := leadingLink.FindStringSubmatch(s[i:])
+	if m == nil {
+		n := 1
+		if s[i] == '!' {
+			n = 2
+		}
+		b.WriteString(html.EscapeString(s[i : i+n]))
+		return n
+	}
+
+	if url := destOf(m[2]); isSafeURL(url) {
+		b.WriteString(`<a href="` + html.EscapeString(url) + `" rel="` +
+			linkRel + `">`)
+		renderInline(b, m[1], false)
+		b.WriteString("</a>")
+	} else {
+		renderInline(b, m[1], false)
+	}
+	return len(m[0])
+}
+
+// leadingLink matches the inline link or image at the start of a text.
+var leadingLink = regexp.MustCompile("^" + inlineLink.String())
+
+// autoLink renders the URL written between angle brackets that starts at
+// s[i], or an escaped angle bracket if it is not one, and returns the length of
+// what it rendered.
+func autoLink(b *strings.Builder, s string, i int) int {
+	loc := leadingAutolink.FindStringIndex(s[i:])
+	if loc == nil {
+		b.WriteString("&lt;")
+		return 1
+	}
+	url := s[i+1 : i+loc[1]-1]
+	if isSafeURL(url) {
+		b.WriteString(`<a href="` + html.EscapeString(url) + `" rel="` +
+			linkRel + `">` + html.EscapeString(url) + "</a>")
+	}
+	return loc[1]
+}
+
+// leadingAutolink matches the URL written between angle brackets at the start
+// of a text.
+var leadingAutolink = regexp.MustCompile("^" + autolink.String())
+
+// isFence returns whether line opens a fenced code block.
+func isFence(line string) bool {
+	_, ok := fenceOf(line)
+	return ok
+}
+
+// headingLevel returns the level of the heading in t, or 0 if it is not one.
+func headingLevel(t string) int {
+	n := runLen(t, 0, '#')
+	if n == 0 || n > 6 || (len(t) > n && t[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// isRule returns whether t is a thematic break - three or more of the same
+// one of -, *, or _, which can be separated by spaces.
+func isRule(t string) bool {
+	t = strings.ReplaceAll(t, " ", "")
+	if len(t) < 3 || !strings.ContainsRune("-*_", rune(t[0])) {
+		return false
+	}
+	return strings.Count(t, t[:1]) == len(t)
+}
+
+// isQuote returns whether t is a line of a blockquote that can be rendered
+// within depth blockquotes.
+func isQuote(t string, depth int) bool {
+	return depth < maxQuoteDepth && strings.HasPrefix(t, ">")
+}
+
+// isListItem returns whether t is an item of a list.
+func isListItem(t string) bool {
+	if len(t) >= 2 && strings.ContainsRune("-*+", rune(t[0])) && t[1] == ' ' {
+		return true
+	}
+	n := 0
+	for n < len(t) && n < 9 && t[n] >= '0' && t[n] <= '9' {
+		n++
+	}
+	return n > 0 && len(t) > n+1 && (t[n] == '.' || t[n] == ')') &&
+		t[n+1] == ' '
+}
+
+// listItem returns whether the list item t is of an ordered list, and its
+// text.
+func listItem(t string) (bool, string) {
+	if strings.ContainsRune("-*+", rune(t[0])) {
+		return false, strings.TrimSpace(t[2:])
+	}
+	_, text, _ := strings.Cut(t, " ")
+	return true, strings.TrimSpace(text)
+}
+
+// isBlockStart returns whether line starts a block other than a paragraph
+// within depth blockquotes, which ends the paragraph before it.
+func isBlockStart(line string, depth int) bool {
+	t := strings.TrimLeft(line, " ")
+	return isFence(line) || headingLevel(t) > 0 || isRule(t) ||
+		isQuote(t, depth) || isListItem(t)
+}
+
+// isPunct returns whether c is ASCII punctuation, which can be escaped with a
+// backslash.
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) != -1
+}
+
+// isWordByte returns whether c is an ASCII letter or digit.
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,100 @@
+//go:build utest
+
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestRender tests the Render function to assert that it renders the supported
+// markdown into HTML and escapes everything else.
+func TestRender(t *testing.T) {
+	a := func(url, text string) string {
+		return `<a href="` + url + `" rel="` + linkRel + `">` + text + "</a>"
+	}
+
+	for _, c := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "Empty", src: "", want: ""},
+		{
+			name: "Paragraphs",
+			src:  "one\ntwo\n\nthree",
+			want: "<p>one\ntwo</p>\n<p>three</p>\n",
+		},
+		{
+			name: "Headings",
+			src:  "# One\n### Three ###\n#hashtag",
+			want: "<h1>One</h1>\n<h3>Three</h3>\n<p>#hashtag</p>\n",
+		},
+		{
+			name: "Emphasis",
+			src:  "*em* _em_ **strong** __strong__ snake_case_name 2 * 3",
+			want: "<p><em>em</em> <em>em</em> <strong>strong</strong> " +
+				"<strong>strong</strong> snake_case_name 2 * 3</p>\n",
+		},
+		{
+			name: "Code",
+			src:  "`a <b>` ``x ` y``\n```go\nif a < b {\n```",
+			want: "<p><code>a &lt;b&gt;</code> <code>x ` y</code></p>\n" +
+				"<pre><code>if a &lt; b {</code></pre>\n",
+		},
+		{
+			name: "Lists",
+			src:  "- one\n  more\n* two\n\n1. first\n2) second",
+			want: "<ul>\n<li>one\nmore</li>\n<li>two</li>\n</ul>\n" +
+				"<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			name: "Quote",
+			src:  "> quoted\n> > nested\n\nafter",
+			want: "<blockquote>\n<p>quoted</p>\n<blockquote>\n" +
+				"<p>nested</p>\n</blockquote>\n</blockquote>\n" +
+				"<p>after</p>\n",
+		},
+		{
+			name: "Rules",
+			src:  "---\n* * *",
+			want: "<hr>\n<hr>\n",
+		},
+		{
+			name: "Links",
+			src:  "[a *b*](https://x.io?a=1&b=2 \"t\") <mailto:a@b.c>",
+			want: "<p>" + a("https://x.io?a=1&amp;b=2", "a <em>b</em>") +
+				" " + a("mailto:a@b.c", "mailto:a@b.c") + "</p>\n",
+		},
+		{
+			name: "Image",
+			src:  "![logo](/logo.png)",
+			want: "<p>" + a("/logo.png", "logo") + "</p>\n",
+		},
+		{
+			name: "UnsafeLinks",
+			src:  "[a](javascript:x(1)) <javascript:x> ![b](data:x)",
+			want: "<p>a  b</p>\n",
+		},
+		{
+			name: "HTML",
+			src:  "<script>alert(\"x\")</script> & [x] \\*",
+			want: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; " +
+				"&amp; [x] *</p>\n",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Render(c.src), c.want)
+		})
+	}
+
+	t.Run("QuoteDepth", func(t *testing.T) {
+		got := Render(strings.Repeat(">", 10) + " deep")
+		assert.Equal(t.Error,
+			strings.Count(got, "<blockquote>"), maxQuoteDepth,
+		)
+		assert.True(t.Error, strings.Contains(got, "&gt;&gt;"))
+	})
+}
@@ -0,0 +1,267 @@
+// Package markdown contains the code to sanitize the markdown that board and
+// task descriptions are written in, and to render it into HTML that is safe to
+// display.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Sanitize normalizes the line endings of src and strips the parts of it that
+// are unsafe to render: raw HTML, including the contents of elements such as
+// script and style, links to URLs with schemes other than http, https and
+// mailto, and the invisible characters that can be used to disguise text.
+// Code is left intact, as it is displayed rather than rendered.
+func Sanitize(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.Map(dropInvisible, src)
+
+	// sanitize the text between the fenced code blocks
+	var b, text strings.Builder
+	var fence string
+	for i, line := range strings.Split(src, "\n") {
+		if i > 0 {
+			if fence == "" {
+				text.WriteByte('\n')
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		if f, ok := fenceOf(line); ok && (fence == "" || f == fence) {
+			if fence == "" {
+				b.WriteString(sanitizeText(text.String()))
+				text.Reset()
+				fence = f
+			} else {
+				fence = ""
+			}
+			b.WriteString(line)
+			continue
+		}
+		if fence == "" {
+			text.WriteString(line)
+		} else {
+			b.WriteString(line)
+		}
+	}
+	b.WriteString(sanitizeText(text.String()))
+
+	return strings.TrimRight(b.String(), " \t\n")
+}
+
+// sanitizeText sanitizes markdown that is not in a fenced code block, leaving
+// its code spans intact.
+func sanitizeText(s string) string {
+	var b strings.Builder
+	for _, seg := range splitCode(s) {
+		if seg.isCode {
+			b.WriteString(seg.text)
+			continue
+		}
+		// strip HTML until there is none left, as stripping it can join the
+		// text around it into more
+		t := seg.text
+		for prev := ""; t != prev; {
+			prev = t
+			for _, re := range unsafeElems {
+				t = re.ReplaceAllString(t, "")
+			}
+			t = htmlComment.ReplaceAllString(t, "")
+			t = htmlTag.ReplaceAllString(t, "")
+		}
+		t = inlineLink.ReplaceAllStringFunc(t, func(m string) string {
+			parts := inlineLink.FindStringSubmatch(m)
+			if isSafeURL(destOf(parts[2])) {
+				return m
+			}
+			return parts[1]
+		})
+		t = autolink.ReplaceAllStringFunc(t, func(m string) string {
+			if isSafeURL(m[1 : len(m)-1]) {
+				return m
+			}
+			return ""
+		})
+		t = linkDef.ReplaceAllStringFunc(t, func(m string) string {
+			if isSafeURL(linkDef.FindStringSubmatch(m)[1]) {
+				return m
+			}
+			return ""
+		})
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+var (
+	// unsafeElems match the elements whose contents are stripped along with
+	// them, up to the end of the text if they are not closed.
+	unsafeElems = func() []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, name := range []string{
+			"script", "style", "iframe", "object", "embed", "noscript",
+			"template", "textarea", "title", "xmp",
+		} {
+			res = append(res, regexp.MustCompile(
+				`(?is)<`+name+`\b.*?(?:</`+name+`\s*>|$)`,
+			))
+		}
+		return res
+	}()
+
+	// htmlComment matches HTML comments, up to the end of the text if they
+	// are not closed.
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)`)
+
+	// htmlTag matches opening and closing HTML tags, declarations, and
+	// processing instructions.
+	htmlTag = regexp.MustCompile(
+		`</?[A-Za-z][A-Za-z0-9-]*(?:\s[^>]*)?/?>|<[!?][^>]*>`,
+	)
+
+	// inlineLink matches inline links and images, capturing their text and
+	// their destination, which can contain a level of balanced parentheses.
+	inlineLink = regexp.MustCompile(
+		`!?\[([^\]]*)\]\(((?:[^()]|\([^()]*\))*)\)`,
+	)
+
+	// autolink matches the URLs written between angle brackets.
+	autolink = regexp.MustCompile(`<[A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\s]*>`)
+
+	// linkDef matches the definitions of reference links, capturing their
+	// destination.
+	linkDef = regexp.MustCompile(
+		`(?m)^ {0,3}\[[^\]]+\]:[ \t]*<?([^\s>]*)>?.*$`,
+	)
+)
+
+// destOf returns the URL in the destination of an inline link, which can be
+// followed by a title and written between angle brackets.
+func destOf(dest string) string {
+	fields := strings.Fields(dest)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(fields[0], "<"), ">")
+}
+
+// isSafeURL returns whether url is relative or has the http, https or mailto
+// scheme. The entities and backslash escapes that markdown renderers decode,
+// and the whitespace that browsers ignore, are taken into account so that
+// they cannot be used to disguise a scheme.
+func isSafeURL(url string) bool {
+	url = strings.ReplaceAll(html.UnescapeString(url), `\`, "")
+	url = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, url)
+	i := strings.IndexAny(url, ":/?#")
+	if i == -1 || url[i] != ':' {
+		return true
+	}
+	switch strings.ToLower(url[:i]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+// dropInvisible drops the control characters other than tabs and newlines,
+// and the invisible characters that change the direction of or hide text.
+// Zero width joiners are kept as emoji are made with them.
+func dropInvisible(r rune) rune {
+	switch {
+	case r == '\n' || r == '\t':
+		return r
+	case unicode.IsControl(r),
+		r == '\u200B', r == '\u200E', r == '\u200F', r == '\uFEFF',
+		r >= '\u202A' && r <= '\u202E',
+		r >= '\u2066' && r <= '\u2069':
+		return -1
+	default:
+		return r
+	}
+}
+
+// fenceOf returns the fence that line opens or closes a fenced code block
+// with, and false if it is not a fence.
+func fenceOf(line string) (string, bool) {
+	t := strings.TrimLeft(line, " ")
+	if len(line)-len(t) > 3 {
+		return "", false
+	}
+	for _, f := range []string{"```", "~~~"} {
+		if strings.HasPrefix(t, f) {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// segment is a part of a text that is either a code span or not.
+type segment struct {
+	text   string
+	isCode bool
+}
+
+// splitCode splits s into its code spans, which start and end with the same
+// number of backticks, and the text around them.
+func splitCode(s string) []segment {
+	var segs []segment
+	start := 0
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		n := runLen(s, i, '`')
+		end := closingRun(s, i+n, n)
+		if end == -1 {
+			i += n
+			continue
+		}
+		if start < i {
+			segs = append(segs, segment{text: s[start:i]})
+		}
+		segs = append(segs, segment{text: s[i : end+n], isCode: true})
+		i = end + n
+		start = i
+	}
+	if start < len(s) {
+		segs = append(segs, segment{text: s[start:]})
+	}
+	return segs
+}
+
+// runLen returns the number of consecutive c bytes in s starting at i.
+func runLen(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// closingRun returns the index of the first run of exactly n backticks in s
+// at or after i, or -1 if there is none.
+func closingRun(s string, i, n int) int {
+	for i < len(s) {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		m := runLen(s, i, '`')
+		if m == n {
+			return i
+		}
+		i += m
+	}
+	return -1
+}
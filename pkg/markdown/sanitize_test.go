@@ -0,0 +1,110 @@
+//go:build utest
+
+package markdown
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSanitize tests the Sanitize function to assert that it strips the parts
+// of markdown that are unsafe to render and leaves the rest intact.
+func TestSanitize(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "Safe",
+			src:  "# Title\n\nSome **bold** and [a link](https://x.io).",
+			want: "# Title\n\nSome **bold** and [a link](https://x.io).",
+		},
+		{
+			name: "LineEndings",
+			src:  "one\r\ntwo\rthree\n\n",
+			want: "one\ntwo\nthree",
+		},
+		{
+			name: "Invisible",
+			src:  "a‮b​c\x00d\te👩‍💻",
+			want: "abcd\te👩‍💻",
+		},
+		{
+			name: "Script",
+			src:  "before<script>alert(1)</script>after",
+			want: "beforeafter",
+		},
+		{
+			name: "UnclosedScript",
+			src:  "before<SCRIPT src=x>\nalert(1)",
+			want: "before",
+		},
+		{
+			name: "Tags",
+			src:  `<b onclick="x()">bold</b><img src=x onerror=y><br/>`,
+			want: "bold",
+		},
+		{
+			name: "JoinedTags",
+			src:  "<<b>img src=x onerror=y>",
+			want: "",
+		},
+		{
+			name: "Comment",
+			src:  "a<!-- hidden -->b",
+			want: "ab",
+		},
+		{
+			name: "NotTags",
+			src:  "1 < 2 and 3 > 2, mail <bob@acme.com>",
+			want: "1 < 2 and 3 > 2, mail <bob@acme.com>",
+		},
+		{
+			name: "UnsafeLink",
+			src:  "[click](javascript:alert(1)) me",
+			want: "click me",
+		},
+		{
+			name: "DisguisedLink",
+			src: "[a](&#106;ava&#x0A;script&#58;x) [b](JAVASCRIPT\\:x) " +
+				"[c](data:x)",
+			want: "a b c",
+		},
+		{
+			name: "SafeLinks",
+			src:  `[a](/boards/1) [b](#x) [c](mailto:a@b.c "t") [d](<http:x>)`,
+			want: `[a](/boards/1) [b](#x) [c](mailto:a@b.c "t") [d](<http:x>)`,
+		},
+		{
+			name: "UnsafeImage",
+			src:  "![pic](vbscript:x)",
+			want: "pic",
+		},
+		{
+			name: "Autolinks",
+			src:  "<https://x.io> <javascript:alert(1)> end",
+			want: "<https://x.io>  end",
+		},
+		{
+			name: "LinkDefs",
+			src:  "[a]: https://x.io\n[b]: javascript:x\n[a] [b]",
+			want: "[a]: https://x.io\n\n[a] [b]",
+		},
+		{
+			name: "Code",
+			src:  "use `<div>` and\n```\n<script>x</script>\n```\n<b>x</b>",
+			want: "use `<div>` and\n```\n<script>x</script>\n```\nx",
+		},
+		{
+			name: "UnclosedFence",
+			src:  "~~~\n<b>x</b>",
+			want: "~~~\n<b>x</b>",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Sanitize(c.src), c.want)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build utest
+
+package notify
+
+import "context"
+
+// FakeNotifier is a test fake for Notifier.
+type FakeNotifier struct {
+	Err error
+
+	// Events records the events passed to Notify.
+	Events []Event
+}
+
+// Notify records the given event and returns FakeNotifier.Err.
+func (f *FakeNotifier) Notify(_ context.Context, e Event) error {
+	f.Events = append(f.Events, e)
+	return f.Err
+}
@@ -0,0 +1,58 @@
+// Package notify contains code for notifying teams of events that happen in
+// their boards through external channels such as Slack.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+// Kind is the kind of an event that teams can be notified of.
+type Kind string
+
+// KindBoardCreated is the kind of the event of a board being created.
+const KindBoardCreated Kind = "boardCreated"
+
+// Event defines an event that a team is notified of.
+type Event struct {
+	TeamID string
+	Kind   Kind
+	Text   string
+}
+
+// Notifier describes a type that can be used to notify a team of an event.
+// Implementations decide whether the team has opted in to the event's kind.
+type Notifier interface {
+	Notify(context.Context, Event) error
+}
+
+// QueueKind is the kind of the queue messages that Queued sends.
+const QueueKind = "notify"
+
+// Queued is a Notifier that queues notifications for a worker to send so that
+// handlers do not wait for external services to respond, and notifications
+// are retried if they fail.
+type Queued struct{ queue queue.Sender }
+
+// NewQueued creates and returns a new Queued that queues notifications on the
+// given queue.
+func NewQueued(q queue.Sender) Queued { return Queued{queue: q} }
+
+// Notify queues the notification of the given event to be sent.
+func (q Queued) Notify(ctx context.Context, e Event) error {
+	return queue.Send(ctx, q.queue, QueueKind, e)
+}
+
+// NewHandler returns a queue.Handler that sends the notifications queued by
+// Queued through the given Notifier.
+func NewHandler(next Notifier) queue.Handler {
+	return func(ctx context.Context, body json.RawMessage) error {
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			return err
+		}
+		return next.Notify(ctx, e)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build utest
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+// TestQueued tests Queued and NewHandler to assert that the notifications
+// queued by the former are sent by the latter, which returns their errors so
+// that they are retried.
+func TestQueued(t *testing.T) {
+	ctx := context.Background()
+	q := &queue.FakeSender{}
+	e := Event{TeamID: "t1", Kind: KindBoardCreated, Text: "Board created."}
+
+	err := NewQueued(q).Notify(ctx, e)
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(q.Messages), 1)
+	assert.Equal(t.Error, q.Messages[0].Kind, QueueKind)
+
+	for _, wantErr := range []error{nil, errors.New("notify failed")} {
+		next := &FakeNotifier{Err: wantErr}
+
+		err = NewHandler(next)(ctx, q.Messages[0].Body)
+
+		assert.ErrIs(t.Error, err, wantErr)
+		assert.Equal(t.Fatal, len(next.Events), 1)
+		assert.Equal(t.Error, next.Events[0], e)
+	}
+}
+
+// TestNewBoardCreatedItem tests the NewBoardCreatedItem function to assert
+// that the outbox item it returns is handled as the board's notification.
+func TestNewBoardCreatedItem(t *testing.T) {
+	item, err := NewBoardCreatedItem("t1", teamtbl.Board{Name: "Roadmap"})
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, item.Kind, QueueKind)
+
+	next := &FakeNotifier{}
+	err = NewHandler(next)(context.Background(), []byte(item.Body))
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(next.Events), 1)
+	assert.Equal(t.Error, next.Events[0], Event{
+		TeamID: "t1",
+		Kind:   KindBoardCreated,
+		Text:   `Board "Roadmap" was created.`,
+	})
+}
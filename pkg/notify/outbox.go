@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// NewBoardCreatedItem returns the outbox item that notifies the team with the
+// given ID of the given board being created. It is passed to
+// teamtbl.BoardInserter.WithOutbox so that the item is written with the board.
+func NewBoardCreatedItem(
+	teamID string, board teamtbl.Board,
+) (outboxtbl.Item, error) {
+	return outboxtbl.NewItem(QueueKind, Event{
+		TeamID: teamID,
+		Kind:   KindBoardCreated,
+		Text:   fmt.Sprintf("Board %q was created.", board.Name),
+	})
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// Slack is a Notifier that posts events to the incoming webhook set in the
+// team's Slack integration settings.
+type Slack struct {
+	teamRetriever db.Retriever[teamtbl.Team]
+	client        *http.Client
+}
+
+// NewSlack creates and returns a new Slack.
+func NewSlack(
+	teamRetriever db.Retriever[teamtbl.Team], client *http.Client,
+) Slack {
+	return Slack{teamRetriever: teamRetriever, client: client}
+}
+
+// slackMsg defines the body of the requests sent to Slack incoming webhooks.
+type slackMsg struct {
+	Text string `json:"text"`
+}
+
+// Notify posts the given event's text to the team's Slack webhook if the team
+// has set one and opted in to the event's kind.
+func (s Slack) Notify(ctx context.Context, e Event) error {
+	team, err := s.teamRetriever.Retrieve(ctx, e.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if team.Slack.WebhookURL == "" || !slackEnabled(team.Slack, e.Kind) {
+		return nil
+	}
+
+	body, err := json.Marshal(slackMsg{Text: e.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, team.Slack.WebhookURL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"slack webhook for team %s responded %d", e.TeamID, resp.StatusCode,
+		)
+	}
+	return nil
+}
+
+// slackEnabled returns whether the given settings opt in to events of the
+// given kind.
+func slackEnabled(settings teamtbl.Slack, kind Kind) bool {
+	switch kind {
+	case KindBoardCreated:
+		return settings.OnBoardCreated
+	default:
+		return false
+	}
+}
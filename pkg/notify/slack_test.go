@@ -0,0 +1,119 @@
+//go:build utest
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// TestSlack tests the Notify method of Slack to assert that it posts events to
+// the team's webhook only if the team opted in to them.
+func TestSlack(t *testing.T) {
+	var (
+		posted     []string
+		respStatus int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var msg slackMsg
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			posted = append(posted, msg.Text)
+			w.WriteHeader(respStatus)
+		},
+	))
+	defer srv.Close()
+
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	sut := NewSlack(teamRetriever, srv.Client())
+	errA := errors.New("failed to retrieve team")
+
+	for _, c := range []struct {
+		name        string
+		slack       teamtbl.Slack
+		errRetrieve error
+		respStatus  int
+		wantPosted  bool
+		wantErr     bool
+	}{
+		{
+			name:        "ErrRetrieve",
+			slack:       teamtbl.Slack{},
+			errRetrieve: errA,
+			respStatus:  http.StatusOK,
+			wantPosted:  false,
+			wantErr:     true,
+		},
+		{
+			name:        "NoTeam",
+			slack:       teamtbl.Slack{},
+			errRetrieve: db.ErrNoItem,
+			respStatus:  http.StatusOK,
+			wantPosted:  false,
+			wantErr:     false,
+		},
+		{
+			name:        "NoWebhook",
+			slack:       teamtbl.Slack{OnBoardCreated: true},
+			errRetrieve: nil,
+			respStatus:  http.StatusOK,
+			wantPosted:  false,
+			wantErr:     false,
+		},
+		{
+			name:        "OptedOut",
+			slack:       teamtbl.Slack{WebhookURL: srv.URL},
+			errRetrieve: nil,
+			respStatus:  http.StatusOK,
+			wantPosted:  false,
+			wantErr:     false,
+		},
+		{
+			name: "ErrWebhook",
+			slack: teamtbl.Slack{
+				WebhookURL: srv.URL, OnBoardCreated: true,
+			},
+			errRetrieve: nil,
+			respStatus:  http.StatusNotFound,
+			wantPosted:  true,
+			wantErr:     true,
+		},
+		{
+			name: "OK",
+			slack: teamtbl.Slack{
+				WebhookURL: srv.URL, OnBoardCreated: true,
+			},
+			errRetrieve: nil,
+			respStatus:  http.StatusOK,
+			wantPosted:  true,
+			wantErr:     false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			posted = nil
+			respStatus = c.respStatus
+			teamRetriever.Res = teamtbl.Team{ID: "team1", Slack: c.slack}
+			teamRetriever.Err = c.errRetrieve
+
+			err := sut.Notify(context.Background(), Event{
+				TeamID: "team1", Kind: KindBoardCreated, Text: "hello",
+			})
+
+			assert.Equal(t.Error, err != nil, c.wantErr)
+			if c.wantPosted {
+				assert.Equal(t.Fatal, len(posted), 1)
+				assert.Equal(t.Error, posted[0], "hello")
+			} else {
+				assert.Equal(t.Error, len(posted), 0)
+			}
+		})
+	}
+}
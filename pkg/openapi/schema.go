@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaOf returns the schema of the JSON encoding of the given value's type,
+// reading property names from the json struct tags the same way
+// encoding/json does.
+func SchemaOf(v any) *Schema { return schemaOf(reflect.TypeOf(v)) }
+
+// schemaOf returns the schema of the JSON encoding of the given type.
+func schemaOf(t reflect.Type) *Schema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				// the fields of embedded structs are promoted, even if the
+				// struct is unexported, like encoding/json does
+				for k, v := range schemaOf(f.Type).Properties {
+					s.Properties[k] = v
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			s.Properties[name] = schemaOf(f.Type)
+		}
+		return s
+	default:
+		return &Schema{}
+	}
+}
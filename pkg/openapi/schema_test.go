@@ -0,0 +1,50 @@
+//go:build utest
+
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestSchemaOf tests the SchemaOf function to assert that it returns the
+// correct schema for the JSON encoding of the given value.
+func TestSchemaOf(t *testing.T) {
+	type item struct {
+		Name   string `json:"name"`
+		IsDone bool   `json:"done,omitempty"`
+		hidden string
+		Skip   string `json:"-"`
+	}
+	type embedded struct {
+		Note string `json:"note"`
+	}
+	type body struct {
+		embedded
+		ID    string
+		Count int       `json:"count"`
+		Items []item    `json:"items"`
+		Ratio float64   `json:"ratio"`
+		At    time.Time `json:"at"`
+	}
+
+	s := SchemaOf(body{})
+
+	assert.Equal(t.Error, s.Type, "object")
+	assert.Equal(t.Error, len(s.Properties), 6)
+	assert.Equal(t.Error, s.Properties["note"].Type, "string")
+	assert.Equal(t.Error, s.Properties["ID"].Type, "string")
+	assert.Equal(t.Error, s.Properties["count"].Type, "integer")
+	assert.Equal(t.Error, s.Properties["ratio"].Type, "number")
+	assert.Equal(t.Error, s.Properties["at"].Type, "string")
+	assert.Equal(t.Error, s.Properties["at"].Format, "date-time")
+
+	items := s.Properties["items"]
+	assert.Equal(t.Error, items.Type, "array")
+	assert.Equal(t.Error, items.Items.Type, "object")
+	assert.Equal(t.Error, len(items.Items.Properties), 2)
+	assert.Equal(t.Error, items.Items.Properties["name"].Type, "string")
+	assert.Equal(t.Error, items.Items.Properties["done"].Type, "boolean")
+}
@@ -0,0 +1,137 @@
+// Package outbox contains the dispatcher that sends the events in the outbox
+// table to the queue, retrying them until they are sent. Since the events are
+// written in the same transactions as the writes they are of, an event is not
+// lost if the service stops between making a write and sending its event.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+const (
+	// pollInterval is how long a Dispatcher waits between dispatches.
+	pollInterval = 2 * time.Second
+
+	// maxAttempts is how many times an item is attempted before it is given
+	// up on and deleted.
+	maxAttempts = 10
+
+	// maxBackoff is the longest a Dispatcher waits before attempting an item
+	// again.
+	maxBackoff = 10 * time.Minute
+)
+
+// Dispatcher sends the items in the outbox table to the queue.
+type Dispatcher struct {
+	clock   clock.Clock
+	lister  db.Lister[[]outboxtbl.Item]
+	updater db.Updater[outboxtbl.Item]
+	deleter db.Deleter
+	queue   queue.Sender
+	log     log.Errorer
+	sleep   func(context.Context, time.Duration) error
+}
+
+// NewDispatcher creates and returns a new Dispatcher.
+func NewDispatcher(
+	clk clock.Clock,
+	lister db.Lister[[]outboxtbl.Item],
+	updater db.Updater[outboxtbl.Item],
+	deleter db.Deleter,
+	queue queue.Sender,
+	log log.Errorer,
+) *Dispatcher {
+	return &Dispatcher{
+		clock:   clk,
+		lister:  lister,
+		updater: updater,
+		deleter: deleter,
+		queue:   queue,
+		log:     log,
+		sleep:   clock.Sleep,
+	}
+}
+
+// Run dispatches the items in the outbox every two seconds until the given
+// context is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			d.log.Error("failed to dispatch outbox:", err)
+		}
+		if err := d.sleep(ctx, pollInterval); err != nil {
+			return
+		}
+	}
+}
+
+// Dispatch sends the items in the outbox that are due to the queue, deleting
+// them once they are sent. Each item is claimed before it is sent so that of
+// the dispatchers running on different instances, only one sends it, and an
+// item whose dispatcher stops before deleting it is sent again once its
+// backoff passes. Failed items are attempted again with an exponential
+// backoff, and deleted after their last attempt.
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	items, err := d.lister.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := d.clock.Now()
+	for _, item := range items {
+		if item.NextAttemptAt > now.Unix() {
+			continue
+		}
+
+		// claim the item for this attempt, skipping it if another
+		// dispatcher claimed it first or already deleted it
+		item.Attempts++
+		item.NextAttemptAt = now.Add(backoff(item.Attempts)).Unix()
+		if err := d.updater.Update(ctx, item); errors.Is(
+			err, db.ErrConflict,
+		) || errors.Is(err, db.ErrNoItem) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if err := d.queue.Send(ctx, queue.Message{
+			Kind: item.Kind, Body: json.RawMessage(item.Body),
+		}); err != nil {
+			if item.Attempts < maxAttempts {
+				d.log.Error(
+					"failed to dispatch outbox item", item.ID,
+					"on attempt", item.Attempts, "-", err,
+				)
+				continue
+			}
+			d.log.Error(
+				"giving up on outbox item", item.ID, "of kind", item.Kind,
+				"after", item.Attempts, "attempts -", err,
+			)
+		}
+		if err := d.deleter.Delete(ctx, item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoff returns how long to wait before the attempt after the given one,
+// which doubles from a second on each attempt up to maxBackoff.
+func backoff(attempt int) time.Duration {
+	d := time.Second << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
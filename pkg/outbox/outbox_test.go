@@ -0,0 +1,129 @@
+//go:build utest
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/outboxtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/queue"
+)
+
+// fakeTable is an outbox table in memory that claims items as the outbox
+// table does.
+type fakeTable struct{ items map[string]outboxtbl.Item }
+
+func (f *fakeTable) List(context.Context) ([]outboxtbl.Item, error) {
+	var items []outboxtbl.Item
+	for _, item := range f.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (f *fakeTable) Update(_ context.Context, item outboxtbl.Item) error {
+	stored, ok := f.items[item.ID]
+	if !ok {
+		return db.ErrNoItem
+	}
+	if stored.Attempts != item.Attempts-1 {
+		return db.ErrConflict
+	}
+	f.items[item.ID] = item
+	return nil
+}
+
+func (f *fakeTable) Delete(_ context.Context, id string) error {
+	delete(f.items, id)
+	return nil
+}
+
+// TestDispatcher tests the Dispatch method of Dispatcher to assert that it
+// sends the items that are due to the queue and deletes them, and attempts
+// the ones that fail again with a backoff until it gives up on them.
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock.Fake{Time: time.Now()}
+	tbl := &fakeTable{items: map[string]outboxtbl.Item{
+		"i1": {ID: "i1", Kind: "notify", Body: `{"teamID":"t1"}`},
+	}}
+	q := &queue.FakeSender{}
+	sut := NewDispatcher(clk, tbl, tbl, tbl, q, &log.FakeErrorer{})
+
+	t.Run("OK", func(t *testing.T) {
+		err := sut.Dispatch(ctx)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(q.Messages), 1)
+		assert.Equal(t.Error, q.Messages[0].Kind, "notify")
+		assert.Equal(t.Error, string(q.Messages[0].Body), `{"teamID":"t1"}`)
+		assert.Equal(t.Error, len(tbl.items), 0)
+	})
+
+	t.Run("Retried", func(t *testing.T) {
+		tbl.items["i2"] = outboxtbl.Item{ID: "i2", Kind: "notify"}
+		q.Err = errors.New("queue down")
+		q.Messages = nil
+
+		// the item is attempted again once each backoff passes
+		for attempt := 1; attempt < maxAttempts; attempt++ {
+			err := sut.Dispatch(ctx)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Fatal, len(q.Messages), attempt)
+
+			clk.Advance(backoff(attempt) - time.Second)
+			err = sut.Dispatch(ctx)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Fatal, len(q.Messages), attempt)
+
+			clk.Advance(time.Second)
+		}
+		assert.Equal(t.Error, tbl.items["i2"].Attempts, maxAttempts-1)
+
+		// and deleted after its last attempt
+		err := sut.Dispatch(ctx)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, len(q.Messages), maxAttempts)
+		assert.Equal(t.Error, len(tbl.items), 0)
+	})
+
+	t.Run("Claimed", func(t *testing.T) {
+		tbl.items["i3"] = outboxtbl.Item{ID: "i3", Kind: "notify"}
+		q.Err = nil
+		q.Messages = nil
+
+		// another dispatcher claims the item after it is listed
+		sut.lister = &db.FakeLister[[]outboxtbl.Item]{
+			Res: []outboxtbl.Item{tbl.items["i3"]},
+		}
+		tbl.items["i3"] = outboxtbl.Item{ID: "i3", Kind: "notify", Attempts: 1}
+
+		err := sut.Dispatch(ctx)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, len(q.Messages), 0)
+		assert.Equal(t.Error, len(tbl.items), 1)
+	})
+}
+
+// TestBackoff tests the backoff function to assert that it doubles on each
+// attempt up to maxBackoff.
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		5:  16 * time.Second,
+		10: 512 * time.Second,
+		11: maxBackoff,
+		99: maxBackoff,
+	} {
+		assert.Equal(t.Error, backoff(attempt), want)
+	}
+}
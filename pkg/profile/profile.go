@@ -0,0 +1,91 @@
+// Package profile contains the environment profiles that the services can be
+// run with, each switching the defaults of the settings that differ between
+// local development and deployments.
+package profile
+
+import (
+	"fmt"
+	"os"
+)
+
+// EnvName is the name of the environment variable to read the profile from. It
+// defaults to prod so that deployments keep their settings if it is unset.
+const EnvName = "GOTEAM_ENV"
+
+// Profile is an environment that the services can be run in.
+type Profile string
+
+// Profiles that the services can be run with.
+const (
+	Dev     Profile = "dev"
+	Staging Profile = "staging"
+	Prod    Profile = "prod"
+)
+
+// defaults are the values of the environment variables that each profile sets
+// when they are unset. The settings default to what suits a deployment in their
+// own packages, so only dev needs to set any.
+var defaults = map[Profile]map[string]string{
+	// dev runs the services over plain HTTP against the dynamodb-local
+	// container and tables created by make db-run and make db-init, on the
+	// ports that the Makefile publishes, for the web app's dev server
+	Dev: {
+		"CLIENT_ORIGIN":     "http://localhost:3000",
+		"COOKIE_SAME_SITE":  "lax",
+		"COOKIE_SECURE":     "false",
+		"DYNAMODB_ENDPOINT": "http://localhost:8000",
+
+		"USER_SERVICE_PORT": "8080",
+		"TEAM_SERVICE_PORT": "8081",
+		"TASK_SERVICE_PORT": "8082",
+
+		"TEAM_SERVICE_DEBUG_ADDR": "localhost:6061",
+
+		"USER_TABLE_NAME":        "goteam-user",
+		"TEAM_TABLE_NAME":        "goteam-team",
+		"TASK_TABLE_NAME":        "goteam-task",
+		"HISTORY_TABLE_NAME":     "goteam-history",
+		"TRASH_TABLE_NAME":       "goteam-trash",
+		"AUDIT_TABLE_NAME":       "goteam-audit",
+		"AVATAR_TABLE_NAME":      "goteam-avatar",
+		"IDEMPOTENCY_TABLE_NAME": "goteam-idempotency",
+		"SESSION_TABLE_NAME":     "goteam-session",
+		"LOCK_TABLE_NAME":        "goteam-lock",
+		"OUTBOX_TABLE_NAME":      "goteam-outbox",
+	},
+	Staging: {},
+	Prod:    {},
+}
+
+// FromEnv returns the profile set in the environment, or Prod if it is unset.
+func FromEnv() (Profile, error) {
+	p := Profile(os.Getenv(EnvName))
+	if p == "" {
+		return Prod, nil
+	}
+	if _, ok := defaults[p]; !ok {
+		return "", fmt.Errorf(
+			"%s must be dev, staging, or prod, got %q", EnvName, p,
+		)
+	}
+	return p, nil
+}
+
+// Apply sets each environment variable that the profile set in the environment
+// has a default for to it, unless it is already set, and returns the profile.
+// It must be called before the settings are loaded from the environment.
+func Apply() (Profile, error) {
+	p, err := FromEnv()
+	if err != nil {
+		return "", err
+	}
+	for name, value := range defaults[p] {
+		if os.Getenv(name) != "" {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return "", err
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,83 @@
+//go:build utest
+
+package profile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestApply tests the Apply function to assert that it defaults the settings
+// that are not set to those of the profile set in the environment.
+func TestApply(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		env          string
+		cookieSecure string
+		wantProfile  Profile
+		wantSecure   string
+		wantEndpoint string
+		wantErr      string
+	}{
+		{
+			name:        "Unset",
+			env:         "",
+			wantProfile: Prod,
+		},
+		{
+			name:        "Prod",
+			env:         "prod",
+			wantProfile: Prod,
+		},
+		{
+			name:        "Staging",
+			env:         "staging",
+			wantProfile: Staging,
+		},
+		{
+			name:         "Dev",
+			env:          "dev",
+			wantProfile:  Dev,
+			wantSecure:   "false",
+			wantEndpoint: "http://localhost:8000",
+		},
+		{
+			name:         "DevSettingSet",
+			env:          "dev",
+			cookieSecure: "true",
+			wantProfile:  Dev,
+			wantSecure:   "true",
+			wantEndpoint: "http://localhost:8000",
+		},
+		{
+			name: "Invalid",
+			env:  "production",
+			wantErr: `GOTEAM_ENV must be dev, staging, or prod, got ` +
+				`"production"`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			// restore the settings Apply sets after the test
+			for name := range defaults[Dev] {
+				t.Setenv(name, "")
+			}
+			t.Setenv(EnvName, c.env)
+			t.Setenv("COOKIE_SECURE", c.cookieSecure)
+
+			p, err := Apply()
+
+			if c.wantErr != "" {
+				assert.Equal(t.Error, err.Error(), c.wantErr)
+				return
+			}
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, p, c.wantProfile)
+			assert.Equal(t.Error, os.Getenv("COOKIE_SECURE"), c.wantSecure)
+			assert.Equal(
+				t.Error, os.Getenv("DYNAMODB_ENDPOINT"), c.wantEndpoint,
+			)
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Package pwdhash contains code for hashing passwords with bcrypt or Argon2id
+// and comparing plaintext passwords with their hashes.
+package pwdhash
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithm is a password hashing algorithm.
+type Algorithm string
+
+// The supported password hashing algorithms.
+const (
+	Bcrypt   Algorithm = "bcrypt"
+	Argon2id Algorithm = "argon2id"
+)
+
+// ErrMismatch means that the plaintext password did not match the hash. It is
+// the same error as bcrypt.ErrMismatchedHashAndPassword so that either can be
+// checked for.
+var ErrMismatch = bcrypt.ErrMismatchedHashAndPassword
+
+// ErrInvalidHash means that the hash could not be parsed.
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// ErrUnknownPepper means that the hash was made with a pepper that the Hasher
+// was not given.
+var ErrUnknownPepper = errors.New("unknown password pepper")
+
+var (
+	// argon2idPrefix is the prefix of the encoded Argon2id hashes.
+	argon2idPrefix = []byte("$argon2id$")
+
+	// pepperPrefix is the prefix of the hashes made with a pepper, followed by
+	// the ID of the pepper and the hash itself.
+	pepperPrefix = []byte("$pepper=")
+)
+
+const (
+	// argon2idSaltLen is the length of the random salts used for Argon2id.
+	argon2idSaltLen = 16
+
+	// argon2idKeyLen is the length of the Argon2id keys derived.
+	argon2idKeyLen = 32
+)
+
+// Params defines the parameters that passwords are hashed with.
+type Params struct {
+	Algorithm Algorithm
+
+	// BcryptCost is the cost of bcrypt hashes.
+	BcryptCost int
+
+	// Argon2Time, Argon2Memory (in KiB) and Argon2Threads are the parameters
+	// of Argon2id hashes.
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// DefaultParams returns the parameters that passwords are hashed with unless
+// configured otherwise. The Argon2id parameters are the ones recommended by
+// RFC 9106 for memory-constrained environments.
+func DefaultParams() Params {
+	return Params{
+		Algorithm:     Bcrypt,
+		BcryptCost:    11,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+	}
+}
+
+// Validate checks that the parameters can be used to hash passwords.
+func (p Params) Validate() error {
+	switch p.Algorithm {
+	case Bcrypt:
+		if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf(
+				"bcrypt cost must be between %d and %d",
+				bcrypt.MinCost, bcrypt.MaxCost,
+			)
+		}
+	case Argon2id:
+		if p.Argon2Time < 1 || p.Argon2Memory < 8*uint32(p.Argon2Threads) ||
+			p.Argon2Threads < 1 {
+			return errors.New(
+				"argon2 time and threads must be at least 1 and memory at " +
+					"least 8 KiB per thread",
+			)
+		}
+	default:
+		return fmt.Errorf("unknown password hashing algorithm %q", p.Algorithm)
+	}
+	return nil
+}
+
+// Pepper is a server-side secret that is combined with passwords before they
+// are hashed so that leaked hashes cannot be cracked without it. Its ID is
+// stored alongside the hashes made with it so that peppers can be rotated.
+type Pepper struct {
+	ID  string
+	Key []byte
+}
+
+// Hasher can be used to hash passwords, compare them with their hashes, and
+// tell whether a hash was made with outdated parameters.
+type Hasher struct {
+	params  Params
+	peppers []Pepper
+}
+
+// NewHasher creates and returns a new Hasher that hashes passwords with the
+// given parameters. New hashes are made with the first of the given peppers if
+// any, and the rest are only used for comparing the hashes made before it.
+func NewHasher(params Params, peppers ...Pepper) Hasher {
+	return Hasher{params: params, peppers: peppers}
+}
+
+// Hash hashes a plaintext password and returns the encoded hash.
+func (h Hasher) Hash(plaintext string) ([]byte, error) {
+	if len(h.peppers) == 0 {
+		return h.hash(plaintext)
+	}
+
+	pepper := h.peppers[0]
+	hash, err := h.hash(pepper.apply(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	peppered := append([]byte(nil), pepperPrefix...)
+	peppered = append(append(peppered, pepper.ID...), hash...)
+	return peppered, nil
+}
+
+// hash hashes a plaintext password with the Hasher's parameters.
+func (h Hasher) hash(plaintext string) ([]byte, error) {
+	if h.params.Algorithm != Argon2id {
+		return bcrypt.GenerateFromPassword(
+			[]byte(plaintext), h.params.BcryptCost,
+		)
+	}
+
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encodeArgon2id(argon2idHash{
+		time:    h.params.Argon2Time,
+		memory:  h.params.Argon2Memory,
+		threads: h.params.Argon2Threads,
+		salt:    salt,
+		key: argon2.IDKey(
+			[]byte(plaintext),
+			salt,
+			h.params.Argon2Time,
+			h.params.Argon2Memory,
+			h.params.Argon2Threads,
+			argon2idKeyLen,
+		),
+	}), nil
+}
+
+// Compare compares a plaintext password with a hash made by either algorithm,
+// with or without a pepper, returning ErrMismatch if they do not match. Users
+// who log in with single sign-on have no hash, which matches no password.
+func (h Hasher) Compare(hash []byte, plaintext string) error {
+	if len(hash) == 0 {
+		return ErrMismatch
+	}
+	id, hash := splitPepper(hash)
+	if id != "" {
+		pepper, ok := h.pepper(id)
+		if !ok {
+			return ErrUnknownPepper
+		}
+		plaintext = pepper.apply(plaintext)
+	}
+
+	if !bytes.HasPrefix(hash, argon2idPrefix) {
+		return bcrypt.CompareHashAndPassword(hash, []byte(plaintext))
+	}
+
+	a, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	key := argon2.IDKey(
+		[]byte(plaintext), a.salt, a.time, a.memory, a.threads,
+		uint32(len(a.key)),
+	)
+	if subtle.ConstantTimeCompare(key, a.key) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// NeedsRehash returns whether the hash was made with a different algorithm,
+// different parameters or a different pepper than the ones the Hasher uses,
+// in which case the password should be hashed again once its plaintext is
+// known.
+func (h Hasher) NeedsRehash(hash []byte) bool {
+	id, hash := splitPepper(hash)
+	if len(h.peppers) == 0 && id != "" ||
+		len(h.peppers) > 0 && id != h.peppers[0].ID {
+		return true
+	}
+
+	if !bytes.HasPrefix(hash, argon2idPrefix) {
+		cost, err := bcrypt.Cost(hash)
+		return h.params.Algorithm != Bcrypt || err != nil ||
+			cost != h.params.BcryptCost
+	}
+
+	a, err := decodeArgon2id(hash)
+	return h.params.Algorithm != Argon2id || err != nil ||
+		a.time != h.params.Argon2Time ||
+		a.memory != h.params.Argon2Memory ||
+		a.threads != h.params.Argon2Threads
+}
+
+// pepper returns the Hasher's pepper with the given ID.
+func (h Hasher) pepper(id string) (Pepper, bool) {
+	for _, p := range h.peppers {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Pepper{}, false
+}
+
+// apply combines the pepper with a plaintext password by keying an HMAC-SHA256
+// of the password with it. The MAC is base64 encoded so that it contains no
+// null bytes and fits within the 72 bytes that bcrypt hashes.
+func (p Pepper) apply(plaintext string) string {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(plaintext))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// splitPepper splits a hash into the ID of the pepper it was made with, which
+// is empty if it was made without one, and the hash itself.
+func splitPepper(hash []byte) (string, []byte) {
+	if !bytes.HasPrefix(hash, pepperPrefix) {
+		return "", hash
+	}
+	rest := hash[len(pepperPrefix):]
+	i := bytes.IndexByte(rest, '$')
+	if i < 0 {
+		return "", hash
+	}
+	return string(rest[:i]), rest[i:]
+}
+
+// argon2idHash defines the parts of an encoded Argon2id hash.
+type argon2idHash struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// encodeArgon2id encodes an Argon2id hash in the PHC string format.
+func encodeArgon2id(a argon2idHash) []byte {
+	return []byte(fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.memory, a.time, a.threads,
+		base64.RawStdEncoding.EncodeToString(a.salt),
+		base64.RawStdEncoding.EncodeToString(a.key),
+	))
+}
+
+// decodeArgon2id decodes an Argon2id hash from the PHC string format.
+func decodeArgon2id(hash []byte) (argon2idHash, error) {
+	var (
+		a             argon2idHash
+		version       int
+		salt, encoded string
+	)
+	parts := bytes.Split(hash, []byte("$"))
+	if len(parts) != 6 {
+		return a, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(
+		string(parts[2]), "v=%d", &version,
+	); err != nil || version != argon2.Version {
+		return a, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(
+		string(parts[3]), "m=%d,t=%d,p=%d", &a.memory, &a.time, &a.threads,
+	); err != nil {
+		return a, ErrInvalidHash
+	}
+	salt, encoded = string(parts[4]), string(parts[5])
+
+	var err error
+	if a.salt, err = base64.RawStdEncoding.DecodeString(salt); err != nil {
+		return a, ErrInvalidHash
+	}
+	if a.key, err = base64.RawStdEncoding.DecodeString(encoded); err != nil ||
+		len(a.key) == 0 {
+		return a, ErrInvalidHash
+	}
+	return a, nil
+}
@@ -0,0 +1,330 @@
+//go:build utest
+
+package pwdhash
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// bcryptParams and argon2idParams are cheap parameters to keep the tests fast.
+var (
+	bcryptParams = Params{Algorithm: Bcrypt, BcryptCost: bcrypt.MinCost}
+
+	argon2idParams = Params{
+		Algorithm: Argon2id, Argon2Time: 1, Argon2Memory: 64, Argon2Threads: 1,
+	}
+)
+
+// TestHasher tests the Hash and Compare methods of Hasher to assert that the
+// hashes made with either algorithm only match their plaintext.
+func TestHasher(t *testing.T) {
+	for _, params := range []Params{bcryptParams, argon2idParams} {
+		sut := NewHasher(params)
+
+		for _, c := range []struct {
+			name           string
+			inPlaintext    string
+			matchPlaintext string
+			wantErr        error
+		}{
+			{
+				name:           "NoMatch",
+				inPlaintext:    "password",
+				matchPlaintext: "differentPassword",
+				wantErr:        ErrMismatch,
+			},
+			{
+				name:           "Match",
+				inPlaintext:    "password",
+				matchPlaintext: "password",
+				wantErr:        nil,
+			},
+		} {
+			t.Run(string(params.Algorithm)+c.name, func(t *testing.T) {
+				hash, err := sut.Hash(c.inPlaintext)
+				assert.Nil(t.Fatal, err)
+
+				err = sut.Compare(hash, c.matchPlaintext)
+				assert.Equal(t.Error, err, c.wantErr)
+			})
+		}
+	}
+
+	t.Run("Argon2idSalted", func(t *testing.T) {
+		sut := NewHasher(argon2idParams)
+
+		hash1, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+		hash2, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+
+		assert.True(t.Error, strings.HasPrefix(
+			string(hash1), "$argon2id$v=19$m=64,t=1,p=1$",
+		))
+		assert.True(t.Error, string(hash1) != string(hash2))
+	})
+}
+
+// TestHasherCompare tests the Compare method of Hasher to assert that it
+// compares plaintext passwords with existing hashes of either algorithm.
+func TestHasherCompare(t *testing.T) {
+	sut := NewHasher(DefaultParams())
+
+	for _, c := range []struct {
+		name        string
+		inHash      []byte
+		inPlaintext string
+		wantErr     error
+	}{
+		{
+			name:        "NoHash",
+			inPlaintext: "",
+			inHash:      nil,
+			wantErr:     ErrMismatch,
+		},
+		{
+			name:        "BcryptWrongPassword",
+			inPlaintext: "password",
+			inHash: []byte(
+				"$2a$04$ngqMWrzBWyg8KO3MGk1cnOISt3wyeBwbFlkvghSHKBkSYOeO2.7XG",
+			),
+			wantErr: ErrMismatch,
+		},
+		{
+			name:        "BcryptError",
+			inPlaintext: "password",
+			inHash:      []byte("$2a$04$ngqMWrzBWyg8K"),
+			wantErr:     bcrypt.ErrHashTooShort,
+		},
+		{
+			name:        "BcryptSuccess",
+			inPlaintext: "password",
+			inHash: []byte(
+				"$2a$04$W4ABZofxx5uoJVgTlYS1wuFHz1LLQaBfoO0iwz/04WWmg9LQdCPsS",
+			),
+			wantErr: nil,
+		},
+		{
+			name:        "Argon2idInvalid",
+			inPlaintext: "password",
+			inHash:      []byte("$argon2id$v=19$m=64,t=1,p=1$c2FsdA"),
+			wantErr:     ErrInvalidHash,
+		},
+		{
+			name:        "Argon2idWrongVersion",
+			inPlaintext: "password",
+			inHash: []byte(
+				"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+			),
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:        "Argon2idWrongPassword",
+			inPlaintext: "password",
+			inHash: []byte(
+				"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+			),
+			wantErr: ErrMismatch,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut.Compare(c.inHash, c.inPlaintext)
+
+			assert.Equal(t.Error, err, c.wantErr)
+		})
+	}
+}
+
+// TestHasherNeedsRehash tests the NeedsRehash method of Hasher to assert that
+// it reports the hashes made with another algorithm or other parameters.
+func TestHasherNeedsRehash(t *testing.T) {
+	bcryptHash, err := NewHasher(bcryptParams).Hash("password")
+	assert.Nil(t.Fatal, err)
+	argon2idHash, err := NewHasher(argon2idParams).Hash("password")
+	assert.Nil(t.Fatal, err)
+
+	costlier := bcryptParams
+	costlier.BcryptCost++
+	hungrier := argon2idParams
+	hungrier.Argon2Memory *= 2
+
+	for _, c := range []struct {
+		name   string
+		params Params
+		hash   []byte
+		want   bool
+	}{
+		{name: "BcryptSame", params: bcryptParams, hash: bcryptHash},
+		{
+			name:   "BcryptCost",
+			params: costlier,
+			hash:   bcryptHash,
+			want:   true,
+		},
+		{
+			name:   "BcryptToArgon2id",
+			params: argon2idParams,
+			hash:   bcryptHash,
+			want:   true,
+		},
+		{name: "Argon2idSame", params: argon2idParams, hash: argon2idHash},
+		{
+			name:   "Argon2idMemory",
+			params: hungrier,
+			hash:   argon2idHash,
+			want:   true,
+		},
+		{
+			name:   "Argon2idToBcrypt",
+			params: bcryptParams,
+			hash:   argon2idHash,
+			want:   true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := NewHasher(c.params).NeedsRehash(c.hash)
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+// TestParamsValidate tests the Validate method of Params to assert that it
+// rejects the parameters that cannot be used to hash passwords.
+func TestParamsValidate(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{name: "Default", params: DefaultParams(), wantErr: false},
+		{name: "Bcrypt", params: bcryptParams, wantErr: false},
+		{name: "Argon2id", params: argon2idParams, wantErr: false},
+		{
+			name:    "UnknownAlgorithm",
+			params:  Params{Algorithm: "md5"},
+			wantErr: true,
+		},
+		{
+			name:    "BcryptCostTooLow",
+			params:  Params{Algorithm: Bcrypt, BcryptCost: 1},
+			wantErr: true,
+		},
+		{
+			name: "Argon2idNoThreads",
+			params: Params{
+				Algorithm: Argon2id, Argon2Time: 1, Argon2Memory: 64,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Argon2idTooLittleMemory",
+			params: Params{
+				Algorithm:     Argon2id,
+				Argon2Time:    1,
+				Argon2Memory:  8,
+				Argon2Threads: 2,
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.params.Validate()
+			assert.Equal(t.Error, err != nil, c.wantErr)
+		})
+	}
+}
+
+// TestHasherPepper tests the Hasher to assert that the hashes made with a
+// pepper record its ID and only match with it, and that they are rehashed when
+// the pepper is rotated.
+func TestHasherPepper(t *testing.T) {
+	v1 := Pepper{ID: "v1", Key: []byte("pepper1")}
+	v2 := Pepper{ID: "v2", Key: []byte("pepper2")}
+
+	unpeppered, err := NewHasher(bcryptParams).Hash("password")
+	assert.Nil(t.Fatal, err)
+	peppered, err := NewHasher(bcryptParams, v1).Hash("password")
+	assert.Nil(t.Fatal, err)
+	assert.True(t.Error, strings.HasPrefix(string(peppered), "$pepper=v1$2a$"))
+
+	for _, c := range []struct {
+		name            string
+		peppers         []Pepper
+		hash            []byte
+		plaintext       string
+		wantErr         error
+		wantNeedsRehash bool
+	}{
+		{
+			name:            "Match",
+			peppers:         []Pepper{v1},
+			hash:            peppered,
+			plaintext:       "password",
+			wantErr:         nil,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "NoMatch",
+			peppers:         []Pepper{v1},
+			hash:            peppered,
+			plaintext:       "differentPassword",
+			wantErr:         ErrMismatch,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "WrongKey",
+			peppers:         []Pepper{{ID: "v1", Key: []byte("other")}},
+			hash:            peppered,
+			plaintext:       "password",
+			wantErr:         ErrMismatch,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "UnknownPepper",
+			peppers:         []Pepper{v2},
+			hash:            peppered,
+			plaintext:       "password",
+			wantErr:         ErrUnknownPepper,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Rotated",
+			peppers:         []Pepper{v2, v1},
+			hash:            peppered,
+			plaintext:       "password",
+			wantErr:         nil,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Added",
+			peppers:         []Pepper{v1},
+			hash:            unpeppered,
+			plaintext:       "password",
+			wantErr:         nil,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Removed",
+			peppers:         nil,
+			hash:            peppered,
+			plaintext:       "password",
+			wantErr:         ErrUnknownPepper,
+			wantNeedsRehash: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := NewHasher(bcryptParams, c.peppers...)
+
+			err := sut.Compare(c.hash, c.plaintext)
+			assert.Equal(t.Error, err, c.wantErr)
+
+			needsRehash := sut.NeedsRehash(c.hash)
+			assert.Equal(t.Error, needsRehash, c.wantNeedsRehash)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build utest
+
+package queue
+
+import "context"
+
+// FakeSender is a test fake for Sender.
+type FakeSender struct {
+	Err error
+
+	// Messages records the messages passed to Send.
+	Messages []Message
+}
+
+// Send records the given message and returns FakeSender.Err.
+func (f *FakeSender) Send(_ context.Context, m Message) error {
+	f.Messages = append(f.Messages, m)
+	return f.Err
+}
@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+const (
+	// memVisibility is how long a message received from a Memory queue is
+	// hidden from other receives for, like the default visibility timeout of
+	// SQS queues.
+	memVisibility = 30 * time.Second
+
+	// memWait is how long Memory.Receive waits for a message to be sent before
+	// returning none, like SQS long polling.
+	memWait = time.Second
+)
+
+// Memory is a Queue that holds its messages in memory. It is used when no SQS
+// queue is configured, such as on local and in demo mode, and in tests. Its
+// messages are lost when the service stops.
+type Memory struct {
+	clock clock.Clock
+	mu    sync.Mutex
+	msgs  []memMsg
+	seq   int
+	sent  chan struct{}
+}
+
+// memMsg defines a message held by a Memory queue.
+type memMsg struct {
+	Message
+	handle    string
+	visibleAt time.Time
+}
+
+// NewMemory creates and returns a new Memory queue.
+func NewMemory(clock clock.Clock) *Memory {
+	return &Memory{clock: clock, sent: make(chan struct{}, 1)}
+}
+
+// Send adds the given message to the queue.
+func (m *Memory) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	m.seq++
+	m.msgs = append(m.msgs, memMsg{
+		Message: msg, handle: strconv.Itoa(m.seq),
+	})
+	m.mu.Unlock()
+
+	select {
+	case m.sent <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Receive returns the messages that are visible, hiding them for the
+// visibility timeout. If there are none, it waits for a message to be sent for
+// up to a second.
+func (m *Memory) Receive(ctx context.Context) ([]Delivery, error) {
+	if ds := m.receive(); len(ds) > 0 {
+		return ds, nil
+	}
+
+	t := time.NewTimer(memWait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.C:
+	case <-m.sent:
+	}
+	return m.receive(), nil
+}
+
+// receive returns the messages that are visible, hiding them for the
+// visibility timeout.
+func (m *Memory) receive() []Delivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	var ds []Delivery
+	for i, msg := range m.msgs {
+		if msg.visibleAt.After(now) {
+			continue
+		}
+		m.msgs[i].visibleAt = now.Add(memVisibility)
+		ds = append(ds, Delivery{Message: msg.Message, handle: msg.handle})
+	}
+	return ds
+}
+
+// Delete removes the given delivery's message from the queue.
+func (m *Memory) Delete(_ context.Context, d Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, msg := range m.msgs {
+		if msg.handle == d.handle {
+			m.msgs = append(m.msgs[:i], m.msgs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+//go:build utest
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestMemory tests the Memory queue to assert that it delivers the messages
+// sent to it, hides them while they are being handled, and delivers them again
+// unless they are deleted.
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock.Fake{Time: time.Now()}
+	sut := NewMemory(clk)
+
+	for _, kind := range []string{"a", "b"} {
+		err := sut.Send(ctx, Message{Kind: kind, Body: []byte(`{}`)})
+		assert.Nil(t.Fatal, err)
+	}
+
+	ds, err := sut.Receive(ctx)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(ds), 2)
+	assert.Equal(t.Error, ds[0].Kind, "a")
+	assert.Equal(t.Error, ds[1].Kind, "b")
+
+	// the received messages are hidden until the visibility timeout passes
+	assert.Nil(t.Fatal, sut.Delete(ctx, ds[0]))
+	clk.Advance(memVisibility - time.Second)
+	assert.Equal(t.Error, len(sut.receive()), 0)
+
+	// the message that was not deleted is delivered again
+	clk.Advance(time.Second)
+	ds, err = sut.Receive(ctx)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(ds), 1)
+	assert.Equal(t.Error, ds[0].Kind, "b")
+}
+
+// TestMemoryReceiveWait tests the Receive method of Memory to assert that it
+// waits for a message to be sent when there are none, and returns once its
+// context is done.
+func TestMemoryReceiveWait(t *testing.T) {
+	sut := NewMemory(&clock.Fake{Time: time.Now()})
+
+	t.Run("Sent", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_ = sut.Send(context.Background(), Message{Kind: "a"})
+		}()
+
+		ds, err := sut.Receive(context.Background())
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, len(ds), 1)
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := sut.Receive(ctx)
+
+		assert.ErrIs(t.Error, err, context.Canceled)
+	})
+}
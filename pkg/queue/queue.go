@@ -0,0 +1,55 @@
+// Package queue contains code for handing work off to workers through a
+// queue, so that slow side effects such as sending emails do not hold up the
+// requests that cause them, and are retried if they fail.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// EnvURL is the name of the environment variable to retrieve the URL of the
+// SQS queue from. An in-memory queue is used if it is empty.
+const EnvURL = "QUEUE_URL"
+
+// Message defines a unit of work on a queue. Its kind tells the worker which
+// handler to pass its body to.
+type Message struct {
+	Kind string          `json:"kind"`
+	Body json.RawMessage `json:"body"`
+}
+
+// Delivery defines a message that is received from a queue. It is delivered
+// again after a while unless it is deleted.
+type Delivery struct {
+	Message
+	handle string
+}
+
+// Sender describes a type that can be used to send a message to a queue.
+type Sender interface {
+	Send(context.Context, Message) error
+}
+
+// Receiver describes a type that can be used to receive messages from a queue
+// and delete them once they are handled.
+type Receiver interface {
+	Receive(context.Context) ([]Delivery, error)
+	Delete(context.Context, Delivery) error
+}
+
+// Queue describes a type that can be used to both send and receive messages.
+type Queue interface {
+	Sender
+	Receiver
+}
+
+// Send sends a message of the given kind to the given queue with the JSON
+// encoding of v as its body.
+func Send(ctx context.Context, s Sender, kind string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Send(ctx, Message{Kind: kind, Body: body})
+}
@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/kxplxn/goteam/pkg/awsjson"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+const (
+	// sqsWaitSeconds is how long SQS.Receive waits for a message to arrive
+	// before returning none. The HTTP client's timeout must be longer.
+	sqsWaitSeconds = 20
+
+	// sqsMaxMessages is the most messages SQS.Receive returns at once.
+	sqsMaxMessages = 10
+)
+
+// SQS is a Queue that sends messages to and receives them from an Amazon SQS
+// queue through its JSON API.
+type SQS struct {
+	queueURL string
+	endpoint string
+	api      awsjson.Client
+}
+
+// NewSQS creates and returns a new SQS for the queue at the given URL, e.g.
+// https://sqs.eu-west-2.amazonaws.com/123456789012/goteam, that signs its
+// requests for the given region with the given credentials.
+func NewSQS(
+	queueURL, region string,
+	creds aws.CredentialsProvider,
+	clock clock.Clock,
+	client *http.Client,
+) (SQS, error) {
+	u, err := url.Parse(queueURL)
+	if err != nil || u.Host == "" || u.Path == "" {
+		return SQS{}, fmt.Errorf("%s must be a valid queue URL", EnvURL)
+	}
+	endpoint := u.Scheme + "://" + u.Host + "/"
+	return SQS{
+		queueURL: queueURL,
+		endpoint: endpoint,
+		api: awsjson.NewClient(
+			endpoint, "AmazonSQS", "sqs", region, creds, clock, client,
+		),
+	}, nil
+}
+
+// sqsMessage defines a message as it is returned by ReceiveMessage.
+type sqsMessage struct {
+	Body          string
+	ReceiptHandle string
+}
+
+// Send sends the given message to the queue.
+func (s SQS) Send(ctx context.Context, m Message) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.api.Call(ctx, "SendMessage", map[string]any{
+		"QueueUrl":    s.queueURL,
+		"MessageBody": string(body),
+	}, nil)
+}
+
+// Receive waits for up to 20 seconds for messages to arrive and returns them.
+func (s SQS) Receive(ctx context.Context) ([]Delivery, error) {
+	var out struct{ Messages []sqsMessage }
+	if err := s.api.Call(ctx, "ReceiveMessage", map[string]any{
+		"QueueUrl":            s.queueURL,
+		"MaxNumberOfMessages": sqsMaxMessages,
+		"WaitTimeSeconds":     sqsWaitSeconds,
+	}, &out); err != nil {
+		return nil, err
+	}
+
+	ds := make([]Delivery, 0, len(out.Messages))
+	for _, m := range out.Messages {
+		d := Delivery{handle: m.ReceiptHandle}
+		if err := json.Unmarshal([]byte(m.Body), &d.Message); err != nil {
+			// leave the messages that are not ours for the queue's
+			// redrive policy to move to the dead-letter queue
+			continue
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// Delete deletes the given delivery's message from the queue.
+func (s SQS) Delete(ctx context.Context, d Delivery) error {
+	return s.api.Call(ctx, "DeleteMessage", map[string]any{
+		"QueueUrl":      s.queueURL,
+		"ReceiptHandle": d.handle,
+	}, nil)
+}
@@ -0,0 +1,136 @@
+//go:build utest
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestNewSQS tests the NewSQS function to assert that it derives the endpoint
+// from the queue URL and rejects invalid URLs.
+func TestNewSQS(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		queueURL     string
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{name: "NoHost", queueURL: "/123/goteam", wantErr: true},
+		{name: "NoPath", queueURL: "https://sqs.example", wantErr: true},
+		{
+			name:         "OK",
+			queueURL:     "https://sqs.eu-west-2.amazonaws.com/123/goteam",
+			wantEndpoint: "https://sqs.eu-west-2.amazonaws.com/",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSQS(
+				c.queueURL, "eu-west-2", nil, &clock.Fake{}, http.DefaultClient,
+			)
+
+			assert.Equal(t.Error, err != nil, c.wantErr)
+			assert.Equal(t.Error, s.endpoint, c.wantEndpoint)
+		})
+	}
+}
+
+// TestSQS tests the methods of SQS to assert that they call the SQS JSON API
+// with signed requests and decode its responses.
+func TestSQS(t *testing.T) {
+	var (
+		gotTarget string
+		gotAuth   string
+		gotIn     map[string]any
+		status    int
+		resp      string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			gotTarget = r.Header.Get("X-Amz-Target")
+			gotAuth = r.Header.Get("Authorization")
+			gotIn = nil
+			_ = json.NewDecoder(r.Body).Decode(&gotIn)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(resp))
+		},
+	))
+	defer srv.Close()
+
+	queueURL := srv.URL + "/123/goteam"
+	sut, err := NewSQS(
+		queueURL,
+		"eu-west-2",
+		credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		&clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		srv.Client(),
+	)
+	assert.Nil(t.Fatal, err)
+	ctx := context.Background()
+
+	t.Run("Send", func(t *testing.T) {
+		status, resp = http.StatusOK, `{"MessageId":"m1"}`
+
+		err := sut.Send(ctx, Message{Kind: "mail", Body: []byte(`{"a":1}`)})
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotTarget, "AmazonSQS.SendMessage")
+		assert.True(t.Error, strings.HasPrefix(
+			gotAuth,
+			"AWS4-HMAC-SHA256 Credential=key/20240315/eu-west-2/sqs/",
+		))
+		assert.Equal(t.Error, gotIn["QueueUrl"], queueURL)
+		assert.Equal(
+			t.Error, gotIn["MessageBody"], `{"kind":"mail","body":{"a":1}}`,
+		)
+	})
+
+	t.Run("Receive", func(t *testing.T) {
+		status, resp = http.StatusOK, `{"Messages":[`+
+			`{"ReceiptHandle":"h1","Body":"{\"kind\":\"mail\",\"body\":{}}"},`+
+			`{"ReceiptHandle":"h2","Body":"not ours"}]}`
+
+		ds, err := sut.Receive(ctx)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotTarget, "AmazonSQS.ReceiveMessage")
+		assert.Equal(t.Error, gotIn["WaitTimeSeconds"], float64(20))
+		assert.Equal(t.Fatal, len(ds), 1)
+		assert.Equal(t.Error, ds[0].Kind, "mail")
+		assert.Equal(t.Error, ds[0].handle, "h1")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		status, resp = http.StatusOK, `{}`
+
+		err := sut.Delete(ctx, Delivery{handle: "h1"})
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotTarget, "AmazonSQS.DeleteMessage")
+		assert.Equal(t.Error, gotIn["ReceiptHandle"], "h1")
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		status, resp = http.StatusBadRequest, `{`+
+			`"__type":"com.amazonaws.sqs#QueueDoesNotExist",`+
+			`"message":"The specified queue does not exist."}`
+
+		err := sut.Send(ctx, Message{Kind: "mail", Body: []byte(`{}`)})
+
+		assert.Equal(
+			t.Error, err.Error(), "sqs SendMessage responded 400: "+
+				"com.amazonaws.sqs#QueueDoesNotExist "+
+				"The specified queue does not exist.",
+		)
+	})
+}
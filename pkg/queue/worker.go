@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+const (
+	// handleTimeout is how long a handler may take to handle a message.
+	handleTimeout = 30 * time.Second
+
+	// receiveRetryInterval is how long a Worker waits before receiving again
+	// after failing to receive messages.
+	receiveRetryInterval = 5 * time.Second
+)
+
+// Handler describes a function that does the work described by the body of a
+// message. The message is delivered again if it returns an error.
+type Handler func(ctx context.Context, body json.RawMessage) error
+
+// Worker receives messages from a queue and passes them to the handlers of
+// their kinds.
+type Worker struct {
+	receiver Receiver
+	handlers map[string]Handler
+	log      log.Errorer
+	sleep    func(context.Context, time.Duration) error
+}
+
+// NewWorker creates and returns a new Worker that receives messages from the
+// given Receiver.
+func NewWorker(receiver Receiver, log log.Errorer) *Worker {
+	return &Worker{
+		receiver: receiver,
+		handlers: map[string]Handler{},
+		log:      log,
+		sleep:    clock.Sleep,
+	}
+}
+
+// Handle registers the given handler for messages of the given kind. It must
+// be called before Run.
+func (w *Worker) Handle(kind string, h Handler) { w.handlers[kind] = h }
+
+// Run receives and handles messages until the given context is done.
+func (w *Worker) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		ds, err := w.receiver.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			w.log.Error("failed to receive from queue:", err)
+			if err := w.sleep(ctx, receiveRetryInterval); err != nil {
+				return
+			}
+			continue
+		}
+		for _, d := range ds {
+			w.handle(ctx, d)
+		}
+	}
+}
+
+// handle passes the given delivery to the handler of its kind and deletes it
+// if it is handled. Deliveries that fail, and those of kinds with no handler,
+// which a newer version of the service may know of, are left to be delivered
+// again.
+func (w *Worker) handle(ctx context.Context, d Delivery) {
+	h, ok := w.handlers[d.Kind]
+	if !ok {
+		w.log.Error("no handler for queued message of kind", d.Kind)
+		return
+	}
+
+	hctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+	if err := h(hctx, d.Body); err != nil {
+		w.log.Error("failed to handle queued", d.Kind, "message:", err)
+		return
+	}
+	if err := w.receiver.Delete(ctx, d); err != nil {
+		w.log.Error("failed to delete queued", d.Kind, "message:", err)
+	}
+}
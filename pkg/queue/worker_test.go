@@ -0,0 +1,101 @@
+//go:build utest
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestWorker tests the Run method of Worker to assert that it passes the
+// messages it receives to the handlers of their kinds and only deletes the
+// ones that are handled.
+func TestWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := &clock.Fake{Time: time.Now()}
+	q := NewMemory(clk)
+	log := &log.FakeErrorer{}
+
+	type mail struct{ To string }
+	var got []string
+	sut := NewWorker(q, log)
+	sut.Handle("mail", func(_ context.Context, body json.RawMessage) error {
+		var m mail
+		if err := json.Unmarshal(body, &m); err != nil {
+			return err
+		}
+		got = append(got, m.To)
+		if m.To == "fail" {
+			return errors.New("smtp down")
+		}
+		return nil
+	})
+
+	for _, to := range []string{"bob", "fail"} {
+		assert.Nil(t.Fatal, Send(ctx, q, "mail", mail{To: to}))
+	}
+	assert.Nil(t.Fatal, q.Send(ctx, Message{Kind: "unknown"}))
+
+	ds, err := q.Receive(ctx)
+	assert.Nil(t.Fatal, err)
+	for _, d := range ds {
+		sut.handle(ctx, d)
+	}
+
+	assert.AllEqual(t.Error, got, []string{"bob", "fail"})
+	assert.Equal(t.Fatal, len(log.Args), 2)
+	assert.Equal(t.Error, log.Args[1], "unknown")
+
+	// the failed message and the one with no handler are delivered again
+	clk.Advance(memVisibility)
+	ds, err = q.Receive(ctx)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(ds), 2)
+	assert.Equal(t.Error, string(ds[0].Body), `{"To":"fail"}`)
+	assert.Equal(t.Error, ds[1].Kind, "unknown")
+}
+
+// errReceiver is a Receiver whose receives fail.
+type errReceiver struct{ err error }
+
+func (r errReceiver) Receive(context.Context) ([]Delivery, error) {
+	return nil, r.err
+}
+
+func (errReceiver) Delete(context.Context, Delivery) error { return nil }
+
+// TestWorkerReceiveErr tests the Run method of Worker to assert that it logs
+// the errors from receiving and waits before receiving again.
+func TestWorkerReceiveErr(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errA := errors.New("throttled")
+	log := &log.FakeErrorer{}
+	sut := NewWorker(errReceiver{err: errA}, log)
+	var slept []time.Duration
+	sut.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		if len(slept) == 2 {
+			cancel()
+			return context.Canceled
+		}
+		return nil
+	}
+
+	sut.Run(ctx)
+
+	assert.AllEqual(
+		t.Error, slept, []time.Duration{
+			receiveRetryInterval, receiveRetryInterval,
+		},
+	)
+	assert.Equal(t.Fatal, len(log.Args), 2)
+	assert.ErrIs(t.Error, log.Args[1].(error), errA)
+}
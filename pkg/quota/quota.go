@@ -0,0 +1,96 @@
+// Package quota contains the limits on how many boards, members, and tasks a
+// team can have, and the code to load their defaults from the environment.
+package quota
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Names of the environment variables to load the default quota from. Each is
+// optional and falls back to the value in Default when unset. The same names
+// prefixed with PaidEnvPrefix are used to load the paid plan's quota.
+const (
+	EnvMaxBoards        = "QUOTA_MAX_BOARDS"
+	EnvMaxMembers       = "QUOTA_MAX_MEMBERS"
+	EnvMaxTasksPerBoard = "QUOTA_MAX_TASKS_PER_BOARD"
+
+	PaidEnvPrefix = "PAID_"
+)
+
+// Quota defines the limits a team is subject to. A zero limit means unlimited
+// in an effective quota and "inherit the default" in a per-team override.
+type Quota struct {
+	MaxBoards        int `json:"maxBoards"`
+	MaxMembers       int `json:"maxMembers"`
+	MaxTasksPerBoard int `json:"maxTasksPerBoard"`
+}
+
+// Default returns the quota teams are subject to unless configured otherwise.
+// It keeps the limit of 3 boards per team that predates quotas.
+func Default() Quota { return Quota{MaxBoards: 3} }
+
+// Paid returns the quota of teams subscribed to the paid plan unless
+// configured otherwise. It is applied as an override, so the limits it leaves
+// at zero fall back to the defaults.
+func Paid() Quota { return Quota{MaxBoards: 20} }
+
+// FromEnv returns the default quota with each limit replaced by the value of
+// its environment variable if set.
+func FromEnv() (Quota, error) { return fromEnv("", Default()) }
+
+// PaidFromEnv returns the paid plan's quota with each limit replaced by the
+// value of its environment variable prefixed with PaidEnvPrefix if set.
+func PaidFromEnv() (Quota, error) { return fromEnv(PaidEnvPrefix, Paid()) }
+
+// fromEnv returns q with each limit replaced by the value of its environment
+// variable with the given prefix if set.
+func fromEnv(prefix string, q Quota) (Quota, error) {
+	for name, limit := range map[string]*int{
+		EnvMaxBoards:        &q.MaxBoards,
+		EnvMaxMembers:       &q.MaxMembers,
+		EnvMaxTasksPerBoard: &q.MaxTasksPerBoard,
+	} {
+		name = prefix + name
+		s := os.Getenv(name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return Quota{}, fmt.Errorf(
+				"%s must be a non-negative integer, got %q", name, s,
+			)
+		}
+		*limit = n
+	}
+	return q, nil
+}
+
+// Override returns q with each limit that is set in o replaced by o's.
+func (q Quota) Override(o Quota) Quota {
+	if o.MaxBoards > 0 {
+		q.MaxBoards = o.MaxBoards
+	}
+	if o.MaxMembers > 0 {
+		q.MaxMembers = o.MaxMembers
+	}
+	if o.MaxTasksPerBoard > 0 {
+		q.MaxTasksPerBoard = o.MaxTasksPerBoard
+	}
+	return q
+}
+
+// AllowsBoard returns whether a team that has n boards can create another.
+func (q Quota) AllowsBoard(n int) bool { return allows(q.MaxBoards, n) }
+
+// AllowsMember returns whether a team that has n members can add another.
+func (q Quota) AllowsMember(n int) bool { return allows(q.MaxMembers, n) }
+
+// AllowsTask returns whether a board that has n tasks can have another.
+func (q Quota) AllowsTask(n int) bool { return allows(q.MaxTasksPerBoard, n) }
+
+// allows returns whether n items can be added to without exceeding max, where
+// a max of zero means unlimited.
+func allows(max, n int) bool { return max == 0 || n < max }
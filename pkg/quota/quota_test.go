@@ -0,0 +1,84 @@
+//go:build utest
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestFromEnv tests the FromEnv function to assert that it overrides the
+// default limits with the ones set in the environment.
+func TestFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		maxBoards    string
+		maxMembers   string
+		maxTasks     string
+		wantQuota    Quota
+		wantErrIsNil bool
+	}{
+		{
+			name:         "Unset",
+			wantQuota:    Default(),
+			wantErrIsNil: true,
+		},
+		{
+			name:         "Set",
+			maxBoards:    "5",
+			maxMembers:   "10",
+			maxTasks:     "0",
+			wantQuota:    Quota{MaxBoards: 5, MaxMembers: 10},
+			wantErrIsNil: true,
+		},
+		{
+			name:         "NotInteger",
+			maxMembers:   "ten",
+			wantQuota:    Quota{},
+			wantErrIsNil: false,
+		},
+		{
+			name:         "Negative",
+			maxTasks:     "-1",
+			wantQuota:    Quota{},
+			wantErrIsNil: false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvMaxBoards, c.maxBoards)
+			t.Setenv(EnvMaxMembers, c.maxMembers)
+			t.Setenv(EnvMaxTasksPerBoard, c.maxTasks)
+
+			q, err := FromEnv()
+
+			assert.Equal(t.Error, q, c.wantQuota)
+			assert.Equal(t.Error, err == nil, c.wantErrIsNil)
+		})
+	}
+}
+
+// TestPaidFromEnv tests the PaidFromEnv function to assert that it reads the
+// prefixed environment variables.
+func TestPaidFromEnv(t *testing.T) {
+	t.Setenv(EnvMaxBoards, "5")
+	t.Setenv(PaidEnvPrefix+EnvMaxMembers, "50")
+
+	q, err := PaidFromEnv()
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, q, Quota{MaxBoards: Paid().MaxBoards, MaxMembers: 50})
+}
+
+// TestQuota tests the Override and Allows methods of Quota to assert that
+// overrides only replace the limits they set and that zero means unlimited.
+func TestQuota(t *testing.T) {
+	q := Quota{MaxBoards: 3, MaxMembers: 5}.Override(Quota{MaxMembers: 8})
+	assert.Equal(t.Error, q, Quota{MaxBoards: 3, MaxMembers: 8})
+
+	assert.True(t.Error, q.AllowsBoard(2))
+	assert.True(t.Error, !q.AllowsBoard(3))
+	assert.True(t.Error, q.AllowsMember(7))
+	assert.True(t.Error, !q.AllowsMember(8))
+	assert.True(t.Error, q.AllowsTask(1000))
+}
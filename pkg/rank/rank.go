@@ -0,0 +1,68 @@
+// Package rank contains helpers to generate lexicographic ordering keys. A key
+// can always be generated between any two distinct keys, so an item can be
+// moved within an ordered list by changing only its own key instead of
+// renumbering its siblings.
+package rank
+
+import "strings"
+
+// digits are the digits keys are made up of, in ascending byte order so that
+// keys compare correctly as plain strings.
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// base is the number of digits.
+const base = len(digits)
+
+// Between returns a key that sorts strictly after lo and strictly before hi.
+// An empty lo means there is no lower bound, and an empty hi means there is no
+// upper bound. lo must sort before hi if both are non-empty. The returned key
+// never ends with the lowest digit, so that there is always room for another
+// key before it.
+func Between(lo, hi string) string {
+	var key []byte
+	bounded := hi != ""
+	for i := 0; ; i++ {
+		l := 0
+		if i < len(lo) {
+			l = strings.IndexByte(digits, lo[i])
+		}
+		h := base
+		if bounded && i < len(hi) {
+			h = strings.IndexByte(digits, hi[i])
+		}
+
+		if h-l > 1 {
+			return string(append(key, digits[(l+h)/2]))
+		}
+
+		// no room at this digit - take the lower digit and look further along,
+		// where anything goes if the key has already dropped below hi
+		key = append(key, digits[l])
+		if h-l == 1 {
+			bounded = false
+		}
+	}
+}
+
+// Spread returns n keys in ascending order that are spread evenly across the
+// key space and are as short as possible, leaving room to insert new keys
+// between any two of them.
+func Spread(n int) []string {
+	width, space := 1, base
+	for space <= n {
+		width++
+		space *= base
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		v := (i + 1) * space / (n + 1)
+		key := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			key[j] = digits[v%base]
+			v /= base
+		}
+		keys[i] = strings.TrimRight(string(key), digits[:1])
+	}
+	return keys
+}
@@ -0,0 +1,70 @@
+//go:build utest
+
+package rank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestBetween tests the Between function to assert that it returns a key that
+// sorts between the given keys.
+func TestBetween(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		lo   string
+		hi   string
+		want string
+	}{
+		{name: "Unbounded", lo: "", hi: "", want: "i"},
+		{name: "NoLower", lo: "", hi: "i", want: "9"},
+		{name: "NoUpper", lo: "i", hi: "", want: "r"},
+		{name: "Gap", lo: "a", hi: "c", want: "b"},
+		{name: "Adjacent", lo: "a", hi: "b", want: "ai"},
+		{name: "Prefix", lo: "a", hi: "a1", want: "a0i"},
+		{name: "Highest", lo: "z", hi: "", want: "zi"},
+		{name: "Lowest", lo: "", hi: "1", want: "0i"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := Between(c.lo, c.hi)
+
+			assert.Equal(t.Error, got, c.want)
+			assert.True(t.Error, got > c.lo)
+			assert.True(t.Error, c.hi == "" || got < c.hi)
+		})
+	}
+
+	t.Run("Repeated", func(t *testing.T) {
+		// insert repeatedly at the same spot to make sure keys keep sorting
+		// correctly as they get longer
+		lo, hi := "a", "b"
+		for i := 0; i < 100; i++ {
+			key := Between(lo, hi)
+			assert.True(t.Fatal, lo < key && key < hi)
+			assert.True(t.Fatal, !strings.HasSuffix(key, "0"))
+			if i%2 == 0 {
+				lo = key
+			} else {
+				hi = key
+			}
+		}
+	})
+}
+
+// TestSpread tests the Spread function to assert that it returns ascending keys
+// that leave room between them.
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 35, 36, 1000} {
+		keys := Spread(n)
+
+		assert.Equal(t.Fatal, len(keys), n)
+		for i, key := range keys {
+			assert.True(t.Error, key != "" && !strings.HasSuffix(key, "0"))
+			if i > 0 {
+				assert.True(t.Error, keys[i-1] < key)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build utest
+
+package report
+
+import "context"
+
+// FakeReporter is a test fake for Reporter. It sends the events reported to it
+// on Events so that tests can wait for the ones reported in the background.
+type FakeReporter struct {
+	Events chan Event
+	Err    error
+}
+
+// Report sends the given event on FakeReporter.Events and returns
+// FakeReporter.Err.
+func (f *FakeReporter) Report(_ context.Context, e Event) error {
+	f.Events <- e
+	return f.Err
+}
@@ -0,0 +1,77 @@
+// Package report contains code for reporting errors to an error tracker such as
+// Sentry or GlitchTip so that they can be noticed without reading the logs.
+package report
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// Names of the environment variables to configure error reporting with.
+const (
+	// EnvDSN is the name of the environment variable to read the DSN of the
+	// Sentry-compatible project to report errors to from. Errors are not
+	// reported if it is empty.
+	EnvDSN = "SENTRY_DSN"
+
+	// EnvRelease is the name of the environment variable to read the release
+	// that the reported errors happened in from, e.g. a version or commit.
+	EnvRelease = "SENTRY_RELEASE"
+)
+
+// Level is the severity of a reported event.
+type Level string
+
+// Levels of the events that are reported.
+const (
+	LevelError Level = "error"
+	LevelFatal Level = "fatal"
+)
+
+// Event defines an error that is reported.
+type Event struct {
+	Level   Level
+	Message string
+
+	// Stack is the stack trace of the goroutine the error happened in. It is
+	// empty unless the error was a panic.
+	Stack string
+
+	// Request is the request that was being handled when the error happened,
+	// or nil if the error did not happen while handling one.
+	Request *Request
+}
+
+// Request defines the context of the request that a reported error happened
+// while handling.
+type Request struct {
+	Method string
+	URL    string
+	ID     string // request ID, as logged by api.AccessLog
+	User   string // username, empty for unauthenticated requests
+}
+
+// Reporter describes a type that can be used to report an error event.
+type Reporter interface {
+	Report(context.Context, Event) error
+}
+
+// Discard is a Reporter that discards the events reported to it. It is used
+// when error reporting is not configured.
+type Discard struct{}
+
+// Report discards the given event and returns nil.
+func (Discard) Report(context.Context, Event) error { return nil }
+
+// FromEnv returns a Sentry reporter for the DSN and the release set in the
+// environment, or Discard if the DSN is not set.
+func FromEnv(clock clock.Clock, client *http.Client) (Reporter, error) {
+	dsn := os.Getenv(EnvDSN)
+	if dsn == "" {
+		return Discard{}, nil
+	}
+	return NewSentry(dsn, os.Getenv(EnvRelease), clock, client)
+}
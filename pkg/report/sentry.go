@@ -0,0 +1,146 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// sentryClient is the name and version the events are sent as.
+const sentryClient = "goteam/1.0"
+
+// Sentry is a Reporter that sends events to the store endpoint of a Sentry
+// project, which GlitchTip and other Sentry-compatible trackers also serve.
+type Sentry struct {
+	storeURL string
+	key      string
+	release  string
+	server   string
+	clock    clock.Clock
+	client   *http.Client
+}
+
+// NewSentry creates and returns a new Sentry that reports events to the
+// project with the given DSN, e.g. https://<key>@sentry.example/<projectID>,
+// tagging them with the given release.
+func NewSentry(
+	dsn, release string, clock clock.Clock, client *http.Client,
+) (Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Host == "" || u.User == nil ||
+		u.User.Username() == "" {
+		return Sentry{}, fmt.Errorf("%s must be a valid DSN", EnvDSN)
+	}
+	prefix, projectID := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if projectID == "" {
+		return Sentry{}, fmt.Errorf("%s must be a valid DSN", EnvDSN)
+	}
+
+	server, _ := os.Hostname()
+	return Sentry{
+		storeURL: u.Scheme + "://" + u.Host +
+			path.Join(prefix, "api", projectID, "store") + "/",
+		key:     u.User.Username(),
+		release: release,
+		server:  server,
+		clock:   clock,
+		client:  client,
+	}, nil
+}
+
+// sentryEvent defines the body of the requests sent to the store endpoint.
+type sentryEvent struct {
+	EventID    string            `json:"event_id"`
+	Timestamp  string            `json:"timestamp"`
+	Level      Level             `json:"level"`
+	Platform   string            `json:"platform"`
+	Release    string            `json:"release,omitempty"`
+	ServerName string            `json:"server_name,omitempty"`
+	Message    sentryMessage     `json:"message"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	User       *sentryUser       `json:"user,omitempty"`
+	Request    *sentryRequest    `json:"request,omitempty"`
+	Extra      map[string]string `json:"extra,omitempty"`
+}
+
+// sentryMessage defines the message of a sentryEvent.
+type sentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+// sentryUser defines the user of a sentryEvent.
+type sentryUser struct {
+	Username string `json:"username"`
+}
+
+// sentryRequest defines the request of a sentryEvent.
+type sentryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Report sends the given event to the Sentry project.
+func (s Sentry) Report(ctx context.Context, e Event) error {
+	body, err := json.Marshal(s.newEvent(e))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, s.storeURL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=%s, sentry_key=%s",
+		sentryClient, s.key,
+	))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sentry responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newEvent returns the body of the request to send the given event with.
+func (s Sentry) newEvent(e Event) sentryEvent {
+	se := sentryEvent{
+		EventID:    strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Timestamp:  s.clock.Now().UTC().Format(time.RFC3339),
+		Level:      e.Level,
+		Platform:   "go",
+		Release:    s.release,
+		ServerName: s.server,
+		Message:    sentryMessage{Formatted: e.Message},
+	}
+	if e.Stack != "" {
+		se.Extra = map[string]string{"stack": e.Stack}
+	}
+	if r := e.Request; r != nil {
+		se.Request = &sentryRequest{Method: r.Method, URL: r.URL}
+		if r.ID != "" {
+			se.Tags = map[string]string{"request_id": r.ID}
+		}
+		if r.User != "" {
+			se.User = &sentryUser{Username: r.User}
+		}
+	}
+	return se
+}
@@ -0,0 +1,164 @@
+//go:build utest
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+)
+
+// TestNewSentry tests the NewSentry function to assert that it derives the
+// store endpoint from the DSN and rejects invalid DSNs.
+func TestNewSentry(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		dsn          string
+		wantStoreURL string
+		wantErr      bool
+	}{
+		{name: "NoKey", dsn: "https://sentry.example/1", wantErr: true},
+		{name: "NoProject", dsn: "https://key@sentry.example/", wantErr: true},
+		{name: "Invalid", dsn: "://key@sentry.example/1", wantErr: true},
+		{
+			name:         "OK",
+			dsn:          "https://key@sentry.example/42",
+			wantStoreURL: "https://sentry.example/api/42/store/",
+		},
+		{
+			name:         "PathPrefix",
+			dsn:          "http://key@glitchtip.example/errors/42",
+			wantStoreURL: "http://glitchtip.example/errors/api/42/store/",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSentry(c.dsn, "", &clock.Fake{}, http.DefaultClient)
+
+			assert.Equal(t.Error, err != nil, c.wantErr)
+			assert.Equal(t.Error, s.storeURL, c.wantStoreURL)
+		})
+	}
+}
+
+// TestSentry tests the Report method of Sentry to assert that it sends events
+// to the store endpoint with the request context and release they carry.
+func TestSentry(t *testing.T) {
+	var (
+		gotPath  string
+		gotAuth  string
+		gotEvent sentryEvent
+		status   int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotAuth = r.Header.Get("X-Sentry-Auth")
+			_ = json.NewDecoder(r.Body).Decode(&gotEvent)
+			w.WriteHeader(status)
+		},
+	))
+	defer srv.Close()
+
+	clk := &clock.Fake{Time: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)}
+	sut, err := NewSentry(
+		strings.Replace(srv.URL, "://", "://key123@", 1)+"/7",
+		"v1.2.3",
+		clk,
+		srv.Client(),
+	)
+	assert.Nil(t.Fatal, err)
+
+	t.Run("OK", func(t *testing.T) {
+		status = http.StatusOK
+
+		err := sut.Report(context.Background(), Event{
+			Level:   LevelError,
+			Message: "panic: boom",
+			Stack:   "goroutine 1",
+			Request: &Request{
+				Method: http.MethodGet,
+				URL:    "/team",
+				ID:     "req-1",
+				User:   "bob123",
+			},
+		})
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotPath, "/api/7/store/")
+		assert.True(t.Error, strings.Contains(gotAuth, "sentry_key=key123"))
+		assert.Equal(t.Error, len(gotEvent.EventID), 32)
+		assert.Equal(t.Error, gotEvent.Timestamp, "2024-03-15T12:00:00Z")
+		assert.Equal(t.Error, gotEvent.Level, LevelError)
+		assert.Equal(t.Error, gotEvent.Release, "v1.2.3")
+		assert.Equal(t.Error, gotEvent.Message.Formatted, "panic: boom")
+		assert.Equal(t.Error, gotEvent.Extra["stack"], "goroutine 1")
+		assert.Equal(t.Error, gotEvent.Tags["request_id"], "req-1")
+		assert.Equal(t.Error, gotEvent.User.Username, "bob123")
+		assert.Equal(t.Error, gotEvent.Request.Method, http.MethodGet)
+		assert.Equal(t.Error, gotEvent.Request.URL, "/team")
+	})
+
+	t.Run("NoRequest", func(t *testing.T) {
+		status = http.StatusOK
+		gotEvent = sentryEvent{}
+
+		err := sut.Report(context.Background(), Event{
+			Level: LevelFatal, Message: "failed to start",
+		})
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, gotEvent.Level, LevelFatal)
+		assert.True(t.Error, gotEvent.Request == nil)
+		assert.True(t.Error, gotEvent.User == nil)
+		assert.Equal(t.Error, len(gotEvent.Tags), 0)
+	})
+
+	t.Run("ErrStatus", func(t *testing.T) {
+		status = http.StatusTooManyRequests
+
+		err := sut.Report(context.Background(), Event{Level: LevelError})
+
+		assert.Equal(t.Error, err.Error(), "sentry responded 429")
+	})
+}
+
+// TestFromEnv tests the FromEnv function to assert that it only reports to
+// Sentry when a DSN is set.
+func TestFromEnv(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv(EnvDSN, "")
+
+		r, err := FromEnv(&clock.Fake{}, http.DefaultClient)
+
+		assert.Nil(t.Fatal, err)
+		_, ok := r.(Discard)
+		assert.True(t.Error, ok)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		t.Setenv(EnvDSN, "https://key@sentry.example/1")
+		t.Setenv(EnvRelease, "v1")
+
+		r, err := FromEnv(&clock.Fake{}, http.DefaultClient)
+
+		assert.Nil(t.Fatal, err)
+		s, ok := r.(Sentry)
+		assert.True(t.Fatal, ok)
+		assert.Equal(t.Error, s.release, "v1")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv(EnvDSN, "not a dsn")
+
+		_, err := FromEnv(&clock.Fake{}, http.DefaultClient)
+
+		assert.Equal(t.Error, err.Error(), EnvDSN+" must be a valid DSN")
+	})
+}
@@ -0,0 +1,149 @@
+package saml
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// canonicalize returns the exclusive XML canonicalization, without comments,
+// of the element, leaving out the excluded element as the enveloped signature
+// transform does. The namespaces with the given prefixes are rendered as they
+// are in inclusive canonicalization, where "#default" is the default
+// namespace.
+//
+// See https://www.w3.org/TR/xml-exc-c14n/.
+func canonicalize(e, excluded *element, inclusive []string) []byte {
+	var b strings.Builder
+	incl := map[string]bool{}
+	for _, p := range inclusive {
+		if p == "#default" {
+			p = ""
+		}
+		incl[p] = true
+	}
+	writeCanonical(&b, e, excluded, incl, map[string]string{})
+	return []byte(b.String())
+}
+
+// writeCanonical writes the canonical form of the element to b, where
+// rendered holds the namespaces that its output ancestors rendered.
+func writeCanonical(
+	b *strings.Builder, e, excluded *element, incl map[string]bool,
+	rendered map[string]string,
+) {
+	// find the namespaces visibly utilized by the element and its attributes,
+	// along with the inclusive ones that are in scope
+	var attrs []xml.Attr
+	used := map[string]bool{e.prefix: true}
+	for _, a := range e.attrs {
+		if a.Name.Space == "xmlns" ||
+			a.Name.Space == "" && a.Name.Local == "xmlns" {
+			continue
+		}
+		attrs = append(attrs, a)
+		if a.Name.Space != "" && a.Name.Space != "xml" {
+			used[a.Name.Space] = true
+		}
+	}
+	for p := range incl {
+		if _, ok := e.lookup(p); ok {
+			used[p] = true
+		}
+	}
+
+	// render the ones that an output ancestor has not rendered with the same
+	// value, passing them down to the children
+	var decls []xml.Attr
+	inScope := rendered
+	for p := range used {
+		uri, ok := e.lookup(p)
+		if !ok || p == "xml" || rendered[p] == uri {
+			continue
+		}
+		if len(decls) == 0 {
+			inScope = make(map[string]string, len(rendered)+len(used))
+			for k, v := range rendered {
+				inScope[k] = v
+			}
+		}
+		inScope[p] = uri
+		decls = append(decls, xml.Attr{Name: xml.Name{Local: p}, Value: uri})
+	}
+	sort.Slice(decls, func(i, j int) bool {
+		return decls[i].Name.Local < decls[j].Name.Local
+	})
+	sort.Slice(attrs, func(i, j int) bool {
+		nsI, nsJ := e.attrNS(attrs[i]), e.attrNS(attrs[j])
+		if nsI != nsJ {
+			return nsI < nsJ
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+
+	b.WriteString("<")
+	b.WriteString(qname(e.prefix, e.local))
+	for _, d := range decls {
+		if d.Name.Local == "" {
+			b.WriteString(` xmlns="`)
+		} else {
+			b.WriteString(" xmlns:" + d.Name.Local + `="`)
+		}
+		b.WriteString(escapeAttr(d.Value))
+		b.WriteString(`"`)
+	}
+	for _, a := range attrs {
+		b.WriteString(" " + qname(a.Name.Space, a.Name.Local) + `="`)
+		b.WriteString(escapeAttr(a.Value))
+		b.WriteString(`"`)
+	}
+	b.WriteString(">")
+
+	for _, c := range e.children {
+		switch c := c.(type) {
+		case *element:
+			if c != excluded {
+				writeCanonical(b, c, excluded, incl, inScope)
+			}
+		case text:
+			b.WriteString(escapeText(string(c)))
+		case xml.ProcInst:
+			b.WriteString("<?" + c.Target)
+			if len(c.Inst) > 0 {
+				b.WriteString(" " + string(c.Inst))
+			}
+			b.WriteString("?>")
+		}
+	}
+
+	b.WriteString("</" + qname(e.prefix, e.local) + ">")
+}
+
+// attrNS returns the namespace of the given attribute of the element, which is
+// "" for unprefixed attributes as they are not in the default namespace.
+func (e *element) attrNS(a xml.Attr) string {
+	if a.Name.Space == "" {
+		return ""
+	}
+	ns, _ := e.lookup(a.Name.Space)
+	return ns
+}
+
+// qname returns the qualified name with the given prefix and local name.
+func qname(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+// escapeAttr escapes an attribute value for its canonical form.
+var escapeAttr = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;",
+	"\r", "&#xD;",
+).Replace
+
+// escapeText escapes character data for its canonical form.
+var escapeText = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;",
+).Replace
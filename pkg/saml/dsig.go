@@ -0,0 +1,158 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	// register the hashes that signatures are accepted with
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// The namespaces and algorithms of XML signatures.
+const (
+	nsDSig      = "http://www.w3.org/2000/09/xmldsig#"
+	nsExcC14N   = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnvelope = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+)
+
+// signatureMethods are the signature algorithms that signatures are accepted
+// with, by the hash they use. SHA-1 is left out as it is broken.
+var signatureMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+}
+
+// digestMethods are the digest algorithms that references are accepted with.
+var digestMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2001/04/xmlenc#sha256":       crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+}
+
+var (
+	// errNotSigned means that an element had no signature.
+	errNotSigned = errors.New("saml: not signed")
+
+	// ErrSignature means that a signature was not one that is accepted, or
+	// that it did not verify with any of the identity provider's
+	// certificates.
+	ErrSignature = errors.New("saml: invalid signature")
+)
+
+// verify verifies the enveloped signature of the element, which must sign the
+// element itself by its ID, with the given certificates. It returns
+// errNotSigned if the element has no signature, or ErrSignature if it fails
+// verification. Only the content of the element is covered by the signature,
+// so callers must read what they trust from it rather than from elsewhere in
+// the document.
+func verify(e *element, certs []*x509.Certificate) error {
+	sig := e.child(nsDSig, "Signature")
+	if sig == nil {
+		return errNotSigned
+	}
+	signedInfo := sig.child(nsDSig, "SignedInfo")
+
+	// the SignedInfo must be canonicalized with exclusive canonicalization
+	// and signed with a supported algorithm
+	cm := signedInfo.child(nsDSig, "CanonicalizationMethod")
+	if cm == nil || cm.attr("Algorithm") != nsExcC14N {
+		return fmt.Errorf(
+			"%w: unsupported canonicalization method", ErrSignature,
+		)
+	}
+	hash, ok := signatureMethods[signedInfo.child(
+		nsDSig, "SignatureMethod",
+	).attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("%w: unsupported signature method", ErrSignature)
+	}
+
+	// the signature must have a single reference to the element, transformed
+	// with the enveloped signature transform and exclusive canonicalization
+	refs := signedInfo.all(nsDSig, "Reference")
+	if len(refs) != 1 {
+		return fmt.Errorf("%w: must have one reference", ErrSignature)
+	}
+	ref := refs[0]
+	if id := e.attr("ID"); id == "" || ref.attr("URI") != "#"+id {
+		return fmt.Errorf("%w: wrong reference", ErrSignature)
+	}
+	var (
+		enveloped bool
+		inclusive []string
+	)
+	for _, t := range ref.child(nsDSig, "Transforms").all(
+		nsDSig, "Transform",
+	) {
+		switch t.attr("Algorithm") {
+		case algEnvelope:
+			enveloped = true
+		case nsExcC14N:
+			inclusive = prefixList(t)
+		default:
+			return fmt.Errorf("%w: unsupported transform %s",
+				ErrSignature, t.attr("Algorithm"),
+			)
+		}
+	}
+	if !enveloped {
+		return fmt.Errorf("%w: not enveloped", ErrSignature)
+	}
+
+	// the digest of the element without its signature must match
+	digestHash, ok := digestMethods[ref.child(
+		nsDSig, "DigestMethod",
+	).attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("%w: unsupported digest method", ErrSignature)
+	}
+	wantDigest, err := decodeBase64(ref.child(nsDSig, "DigestValue").text())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSignature, err)
+	}
+	h := digestHash.New()
+	h.Write(canonicalize(e, sig, inclusive))
+	if subtle.ConstantTimeCompare(h.Sum(nil), wantDigest) != 1 {
+		return ErrSignature
+	}
+
+	// and the SignedInfo must be signed by one of the certificates
+	sigValue, err := decodeBase64(sig.child(nsDSig, "SignatureValue").text())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSignature, err)
+	}
+	h = hash.New()
+	h.Write(canonicalize(signedInfo, nil, prefixList(cm)))
+	digest := h.Sum(nil)
+	for _, cert := range certs {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		if rsa.VerifyPKCS1v15(pub, hash, digest, sigValue) == nil {
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// prefixList returns the prefixes in the PrefixList of the InclusiveNamespaces
+// of the given canonicalization method or transform.
+func prefixList(e *element) []string {
+	return strings.Fields(
+		e.child(nsExcC14N, "InclusiveNamespaces").attr("PrefixList"),
+	)
+}
+
+// decodeBase64 decodes base64 that may be broken into lines.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,40 @@
+package saml
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache remembers the IDs of the assertions that were used to log in
+// until they expire so that a response cannot be posted again to log in with
+// it a second time. It is held in memory, so it only guards the instance of
+// the service that it was created in.
+type ReplayCache struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// NewReplayCache creates and returns a new ReplayCache.
+func NewReplayCache() *ReplayCache {
+	return &ReplayCache{used: map[string]time.Time{}}
+}
+
+// Use records the given assertion as used and returns whether it was not used
+// before.
+func (c *ReplayCache) Use(a Assertion, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// forget the assertions that can no longer be used anyway
+	for id, exp := range c.used {
+		if now.Add(-maxClockSkew).After(exp) {
+			delete(c.used, id)
+		}
+	}
+
+	if _, ok := c.used[a.ID]; ok {
+		return false
+	}
+	c.used[a.ID] = a.ExpiresAt
+	return true
+}
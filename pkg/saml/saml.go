@@ -0,0 +1,354 @@
+// Package saml contains code for logging users in with a SAML 2.0 identity
+// provider as a service provider, using the HTTP-Redirect binding to send
+// authentication requests and the HTTP-POST binding to receive responses.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The namespaces and values of the SAML protocol.
+const (
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+	bindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	bindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	methodBearer    = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// maxClockSkew is how far the clocks of the identity provider and the service
+// may drift apart before the validity periods of assertions are misjudged.
+const maxClockSkew = 3 * time.Minute
+
+// ErrInvalid means that a response was not a valid response to the service
+// provider, and that the user must not be logged in with it.
+var ErrInvalid = errors.New("saml: invalid response")
+
+// IdP defines an identity provider as described by its metadata.
+type IdP struct {
+	EntityID string
+
+	// SSOURL is the URL that authentication requests are redirected to.
+	SSOURL string
+
+	// Certs holds the DER encoded certificates that the identity provider
+	// signs its responses with. There may be more than one while it rotates
+	// its keys.
+	Certs [][]byte
+}
+
+// ParseMetadata parses the entity descriptor of an identity provider from
+// its metadata.
+func ParseMetadata(data []byte) (IdP, error) {
+	root, err := parse(data)
+	if err != nil {
+		return IdP{}, err
+	}
+
+	// find the first entity that is an identity provider, which may be
+	// among others in an EntitiesDescriptor
+	entities := []*element{root}
+	if root.is(nsMetadata, "EntitiesDescriptor") {
+		entities = root.all(nsMetadata, "EntityDescriptor")
+	}
+	var entity, sso *element
+	for _, e := range entities {
+		if e.is(nsMetadata, "EntityDescriptor") {
+			if sso = e.child(nsMetadata, "IDPSSODescriptor"); sso != nil {
+				entity = e
+				break
+			}
+		}
+	}
+	if entity == nil {
+		return IdP{}, errors.New("saml: metadata has no identity provider")
+	}
+
+	idp := IdP{EntityID: entity.attr("entityID")}
+	for _, s := range sso.all(nsMetadata, "SingleSignOnService") {
+		if s.attr("Binding") == bindingRedirect {
+			idp.SSOURL = s.attr("Location")
+			break
+		}
+	}
+	for _, k := range sso.all(nsMetadata, "KeyDescriptor") {
+		if use := k.attr("use"); use != "" && use != "signing" {
+			continue
+		}
+		for _, x := range k.child(nsDSig, "KeyInfo").all(nsDSig, "X509Data") {
+			for _, c := range x.all(nsDSig, "X509Certificate") {
+				der, err := decodeBase64(c.text())
+				if err != nil {
+					return IdP{}, fmt.Errorf("saml: certificate: %w", err)
+				}
+				if _, err := x509.ParseCertificate(der); err != nil {
+					return IdP{}, fmt.Errorf("saml: certificate: %w", err)
+				}
+				idp.Certs = append(idp.Certs, der)
+			}
+		}
+	}
+
+	switch {
+	case idp.EntityID == "":
+		return IdP{}, errors.New("saml: metadata has no entity ID")
+	case idp.SSOURL == "":
+		return IdP{}, errors.New(
+			"saml: metadata has no HTTP-Redirect single sign-on service",
+		)
+	case len(idp.Certs) == 0:
+		return IdP{}, errors.New("saml: metadata has no signing certificate")
+	}
+	return idp, nil
+}
+
+// SP defines the service provider that users log in to.
+type SP struct {
+	EntityID string
+
+	// ACSURL is the URL of the assertion consumer service that identity
+	// providers post their responses to.
+	ACSURL string
+}
+
+// Metadata returns the metadata of the service provider, which identity
+// providers can be configured with.
+func (sp SP) Metadata() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<md:EntityDescriptor xmlns:md="` + nsMetadata + `"`)
+	b.WriteString(` entityID="` + escapeAttr(sp.EntityID) + `">`)
+	b.WriteString(`<md:SPSSODescriptor AuthnRequestsSigned="false"`)
+	b.WriteString(` WantAssertionsSigned="true"`)
+	b.WriteString(` protocolSupportEnumeration="` + nsProtocol + `">`)
+	b.WriteString(`<md:AssertionConsumerService Binding="` + bindingPOST + `"`)
+	b.WriteString(` Location="` + escapeAttr(sp.ACSURL) + `" index="0"`)
+	b.WriteString(` isDefault="true"/>`)
+	b.WriteString(`</md:SPSSODescriptor></md:EntityDescriptor>`)
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+// AuthnRequestURL returns the URL that sends the user to the identity
+// provider with an authentication request with the given ID, which the
+// identity provider posts its response back to the assertion consumer service
+// with, along with the given relay state.
+func (sp SP) AuthnRequestURL(
+	idp IdP, id, relayState string, now time.Time,
+) (string, error) {
+	var req strings.Builder
+	req.WriteString(`<samlp:AuthnRequest xmlns:samlp="` + nsProtocol + `"`)
+	req.WriteString(` xmlns:saml="` + nsAssertion + `"`)
+	req.WriteString(` ID="` + escapeAttr(id) + `" Version="2.0"`)
+	req.WriteString(` IssueInstant="`)
+	req.WriteString(now.UTC().Format(time.RFC3339) + `"`)
+	req.WriteString(` Destination="` + escapeAttr(idp.SSOURL) + `"`)
+	req.WriteString(` AssertionConsumerServiceURL="`)
+	req.WriteString(escapeAttr(sp.ACSURL) + `"`)
+	req.WriteString(` ProtocolBinding="` + bindingPOST + `">`)
+	req.WriteString(`<saml:Issuer>` + escapeText(sp.EntityID))
+	req.WriteString(`</saml:Issuer></samlp:AuthnRequest>`)
+
+	// the HTTP-Redirect binding deflates and base64 encodes the request
+	var b bytes.Buffer
+	w, err := flate.NewWriter(&b, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write([]byte(req.String())); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(idp.SSOURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("SAMLRequest", base64.StdEncoding.EncodeToString(b.Bytes()))
+	if relayState != "" {
+		q.Set("RelayState", relayState)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Assertion defines what the identity provider asserted about the user.
+type Assertion struct {
+	// ID is the ID of the assertion, which is unique to it so that it can be
+	// used to tell when a response is replayed.
+	ID string
+
+	// NameID is the identifier of the user.
+	NameID string
+
+	// Attributes holds the values of the user's attributes by their names.
+	Attributes map[string][]string
+
+	// ExpiresAt is when the assertion can no longer be used to log in.
+	ExpiresAt time.Time
+}
+
+// ParseResponse parses and validates the given base64 encoded response that
+// the identity provider posted to the assertion consumer service, returning
+// the assertion it holds. It returns ErrInvalid, or ErrSignature if the
+// signature failed verification, if the user must not be logged in with it.
+func (sp SP) ParseResponse(
+	samlResponse string, idp IdP, now time.Time,
+) (Assertion, error) {
+	data, err := decodeBase64(samlResponse)
+	if err != nil {
+		return Assertion{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	resp, err := parse(data)
+	if err != nil {
+		return Assertion{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	invalid := func(reason string) (Assertion, error) {
+		return Assertion{}, fmt.Errorf("%w: %s", ErrInvalid, reason)
+	}
+
+	if !resp.is(nsProtocol, "Response") {
+		return invalid("not a response")
+	}
+	if dest := resp.attr("Destination"); dest != "" && dest != sp.ACSURL {
+		return invalid("wrong destination")
+	}
+	if status := resp.child(nsProtocol, "Status").child(
+		nsProtocol, "StatusCode",
+	).attr("Value"); status != statusSuccess {
+		return invalid("status " + status)
+	}
+
+	certs := make([]*x509.Certificate, 0, len(idp.Certs))
+	for _, der := range idp.Certs {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return Assertion{}, err
+		}
+		certs = append(certs, cert)
+	}
+
+	// the response or the assertion in it must be signed, and the assertion
+	// read from the element that was verified
+	respSigned := false
+	if err := verify(resp, certs); err == nil {
+		respSigned = true
+	} else if !errors.Is(err, errNotSigned) {
+		return Assertion{}, err
+	}
+	if resp.child(nsAssertion, "EncryptedAssertion") != nil {
+		return invalid("encrypted assertions are not supported")
+	}
+	assertions := resp.all(nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return invalid("response must have one assertion")
+	}
+	a := assertions[0]
+	if err := verify(a, certs); errors.Is(err, errNotSigned) {
+		if !respSigned {
+			return Assertion{}, fmt.Errorf("%w: not signed", ErrSignature)
+		}
+	} else if err != nil {
+		return Assertion{}, err
+	}
+
+	if a.child(nsAssertion, "Issuer").text() != idp.EntityID {
+		return invalid("wrong issuer")
+	}
+
+	// the assertion must be valid now and meant for the service provider
+	conds := a.child(nsAssertion, "Conditions")
+	if conds == nil {
+		return invalid("no conditions")
+	}
+	notBefore, err := parseTime(conds.attr("NotBefore"), time.Time{})
+	if err != nil {
+		return Assertion{}, err
+	}
+	notOnOrAfter, err := parseTime(conds.attr("NotOnOrAfter"), time.Time{})
+	if err != nil {
+		return Assertion{}, err
+	}
+	if now.Add(maxClockSkew).Before(notBefore) ||
+		!notOnOrAfter.IsZero() && !now.Add(-maxClockSkew).Before(notOnOrAfter) {
+		return invalid("expired or not yet valid")
+	}
+	var forSP bool
+	for _, r := range conds.all(nsAssertion, "AudienceRestriction") {
+		for _, aud := range r.all(nsAssertion, "Audience") {
+			forSP = forSP || aud.text() == sp.EntityID
+		}
+	}
+	if !forSP {
+		return invalid("wrong audience")
+	}
+
+	// the subject must be confirmed as the bearer of the assertion when it is
+	// posted to the assertion consumer service
+	subject := a.child(nsAssertion, "Subject")
+	var expiresAt time.Time
+	for _, sc := range subject.all(nsAssertion, "SubjectConfirmation") {
+		data := sc.child(nsAssertion, "SubjectConfirmationData")
+		if sc.attr("Method") != methodBearer ||
+			data.attr("Recipient") != sp.ACSURL {
+			continue
+		}
+		exp, err := parseTime(data.attr("NotOnOrAfter"), time.Time{})
+		if err != nil {
+			return Assertion{}, err
+		}
+		if now.Add(-maxClockSkew).Before(exp) {
+			expiresAt = exp
+			break
+		}
+	}
+	if expiresAt.IsZero() {
+		return invalid("no bearer subject confirmation")
+	}
+
+	out := Assertion{
+		ID:         a.attr("ID"),
+		NameID:     subject.child(nsAssertion, "NameID").text(),
+		Attributes: map[string][]string{},
+		ExpiresAt:  expiresAt,
+	}
+	for _, st := range a.all(nsAssertion, "AttributeStatement") {
+		for _, attr := range st.all(nsAssertion, "Attribute") {
+			name := attr.attr("Name")
+			for _, v := range attr.all(nsAssertion, "AttributeValue") {
+				out.Attributes[name] = append(out.Attributes[name], v.text())
+			}
+		}
+	}
+	if out.ID == "" {
+		return invalid("no assertion ID")
+	}
+	return out, nil
+}
+
+// parseTime parses the given xs:dateTime, or returns def if it is empty.
+func parseTime(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return t, nil
+}
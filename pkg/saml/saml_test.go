@@ -0,0 +1,533 @@
+//go:build utest
+
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// newCert generates a key and a self-signed certificate for it.
+func newCert(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader, tmpl, tmpl, &key.PublicKey, key,
+	)
+	assert.Nil(t.Fatal, err)
+	return key, der
+}
+
+// find returns the element with the given ID in the tree under e.
+func find(e *element, id string) *element {
+	if e.attr("ID") == id {
+		return e
+	}
+	for _, c := range e.children {
+		if c, ok := c.(*element); ok {
+			if found := find(c, id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// markers matches the places in a document where the elements with the IDs
+// they hold are signed.
+var markers = regexp.MustCompile(`\{sig:[^}]*\}`)
+
+// sign signs the element with the given ID in the document with an enveloped
+// signature, put in place of its {sig:ID} marker.
+func sign(t *testing.T, key *rsa.PrivateKey, doc, id string) string {
+	root, err := parse([]byte(markers.ReplaceAllString(doc, "")))
+	assert.Nil(t.Fatal, err)
+	digest := sha256.Sum256(canonicalize(find(root, id), nil, nil))
+
+	sig := `<ds:Signature xmlns:ds="` + nsDSig + `"><ds:SignedInfo>` +
+		`<ds:CanonicalizationMethod Algorithm="` + nsExcC14N + `"/>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/` +
+		`xmldsig-more#rsa-sha256"/><ds:Reference URI="#` + id + `">` +
+		`<ds:Transforms><ds:Transform Algorithm="` + algEnvelope + `"/>` +
+		`<ds:Transform Algorithm="` + nsExcC14N + `"/></ds:Transforms>` +
+		`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/` +
+		`xmlenc#sha256"/><ds:DigestValue>` +
+		base64.StdEncoding.EncodeToString(digest[:]) +
+		`</ds:DigestValue></ds:Reference></ds:SignedInfo>` +
+		`<ds:SignatureValue></ds:SignatureValue></ds:Signature>`
+	doc = strings.Replace(doc, "{sig:"+id+"}", sig, 1)
+
+	root, err = parse([]byte(markers.ReplaceAllString(doc, "")))
+	assert.Nil(t.Fatal, err)
+	signedInfo := find(root, id).child(nsDSig, "Signature").child(
+		nsDSig, "SignedInfo",
+	)
+	h := sha256.Sum256(canonicalize(signedInfo, nil, nil))
+	value, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	assert.Nil(t.Fatal, err)
+	return strings.Replace(doc,
+		"<ds:SignatureValue></ds:SignatureValue>",
+		"<ds:SignatureValue>"+base64.StdEncoding.EncodeToString(value)+
+			"</ds:SignatureValue>",
+		1,
+	)
+}
+
+// TestCanonicalize tests the canonicalize function to assert that it renders
+// the namespaces that elements utilize, sorts attributes, and escapes text.
+func TestCanonicalize(t *testing.T) {
+	root, err := parse([]byte(
+		`<a:root xmlns:a="urn:a" xmlns:b="urn:b" xmlns="urn:d">` +
+			`<a:child z="1" b:y="2" a="&quot;" xmlns:c="urn:c">` +
+			`x &gt; y &amp; <?pi data?><c:n/></a:child><sig/></a:root>`,
+	))
+	assert.Nil(t.Fatal, err)
+	child := root.children[0].(*element)
+	sig := root.children[1].(*element)
+
+	for _, c := range []struct {
+		name      string
+		e         *element
+		excluded  *element
+		inclusive []string
+		want      string
+	}{
+		{
+			name: "Child",
+			e:    child,
+			want: `<a:child xmlns:a="urn:a" xmlns:b="urn:b" a="&quot;" z="1"` +
+				` b:y="2">x &gt; y &amp; <?pi data?>` +
+				`<c:n xmlns:c="urn:c"></c:n></a:child>`,
+		},
+		{
+			name:     "Excluded",
+			e:        root,
+			excluded: sig,
+			want: `<a:root xmlns:a="urn:a"><a:child xmlns:b="urn:b"` +
+				` a="&quot;" z="1" b:y="2">x &gt; y &amp; <?pi data?>` +
+				`<c:n xmlns:c="urn:c"></c:n></a:child></a:root>`,
+		},
+		{
+			name:      "Inclusive",
+			e:         sig,
+			inclusive: []string{"#default", "b"},
+			want:      `<sig xmlns="urn:d" xmlns:b="urn:b"></sig>`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := string(canonicalize(c.e, c.excluded, c.inclusive))
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+// TestParseMetadata tests the ParseMetadata function to assert that it reads
+// the entity ID, the single sign-on URL, and the signing certificates of an
+// identity provider from its metadata.
+func TestParseMetadata(t *testing.T) {
+	_, der := newCert(t)
+	cert := base64.StdEncoding.EncodeToString(der)
+	const redirect = `<md:SingleSignOnService Binding="` + bindingRedirect +
+		`" Location="https://idp.example.com/sso"/>`
+	keyDesc := func(use, cert string) string {
+		return `<md:KeyDescriptor use="` + use + `"><ds:KeyInfo><ds:X509Data>` +
+			`<ds:X509Certificate>` + cert + `</ds:X509Certificate>` +
+			`</ds:X509Data></ds:KeyInfo></md:KeyDescriptor>`
+	}
+	entity := func(content string) string {
+		return `<md:EntityDescriptor xmlns:md="` + nsMetadata + `"` +
+			` xmlns:ds="` + nsDSig + `" entityID="https://idp.example.com">` +
+			`<md:IDPSSODescriptor protocolSupportEnumeration="` + nsProtocol +
+			`">` + content + `</md:IDPSSODescriptor></md:EntityDescriptor>`
+	}
+
+	for _, c := range []struct {
+		name     string
+		metadata string
+		wantErr  bool
+		wantIdP  IdP
+	}{
+		{name: "NotXML", metadata: "metadata", wantErr: true},
+		{
+			name: "DTD",
+			metadata: `<!DOCTYPE x [<!ENTITY e "e">]>` +
+				entity(keyDesc("signing", cert)+redirect),
+			wantErr: true,
+		},
+		{
+			name:     "NoIdP",
+			metadata: `<md:EntityDescriptor xmlns:md="` + nsMetadata + `"/>`,
+			wantErr:  true,
+		},
+		{
+			name:     "NoRedirectBinding",
+			metadata: entity(keyDesc("signing", cert)),
+			wantErr:  true,
+		},
+		{
+			name:     "NoSigningCert",
+			metadata: entity(keyDesc("encryption", cert) + redirect),
+			wantErr:  true,
+		},
+		{
+			name:     "BadCert",
+			metadata: entity(keyDesc("signing", "YmFk") + redirect),
+			wantErr:  true,
+		},
+		{
+			name: "OK",
+			metadata: `<md:EntitiesDescriptor xmlns:md="` + nsMetadata +
+				`">` + entity(keyDesc("", cert)+redirect) +
+				`</md:EntitiesDescriptor>`,
+			wantIdP: IdP{
+				EntityID: "https://idp.example.com",
+				SSOURL:   "https://idp.example.com/sso",
+				Certs:    [][]byte{der},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idp, err := ParseMetadata([]byte(c.metadata))
+			assert.Equal(t.Fatal, err != nil, c.wantErr)
+			assert.Equal(t.Error, idp.EntityID, c.wantIdP.EntityID)
+			assert.Equal(t.Error, idp.SSOURL, c.wantIdP.SSOURL)
+			assert.Equal(t.Fatal, len(idp.Certs), len(c.wantIdP.Certs))
+			for i := range idp.Certs {
+				assert.True(t.Error,
+					bytes.Equal(idp.Certs[i], c.wantIdP.Certs[i]),
+				)
+			}
+		})
+	}
+}
+
+// TestSP tests the AuthnRequestURL and Metadata methods of SP to assert that
+// they describe the service provider to identity providers.
+func TestSP(t *testing.T) {
+	sp := SP{
+		EntityID: "https://sp.example.com",
+		ACSURL:   "https://sp.example.com/acs",
+	}
+	idp := IdP{SSOURL: "https://idp.example.com/sso?tenant=acme"}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("AuthnRequestURL", func(t *testing.T) {
+		got, err := sp.AuthnRequestURL(idp, "_r1", "st4te", now)
+		assert.Nil(t.Fatal, err)
+		u, err := url.Parse(got)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, u.Host, "idp.example.com")
+		assert.Equal(t.Error, u.Query().Get("tenant"), "acme")
+		assert.Equal(t.Error, u.Query().Get("RelayState"), "st4te")
+
+		deflated, err := base64.StdEncoding.DecodeString(
+			u.Query().Get("SAMLRequest"),
+		)
+		assert.Nil(t.Fatal, err)
+		req, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+		assert.Nil(t.Fatal, err)
+		root, err := parse(req)
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, root.is(nsProtocol, "AuthnRequest"))
+		assert.Equal(t.Error, root.attr("ID"), "_r1")
+		assert.Equal(t.Error, root.attr("IssueInstant"), "2024-03-15T12:00:00Z")
+		assert.Equal(t.Error,
+			root.attr("AssertionConsumerServiceURL"), sp.ACSURL,
+		)
+		assert.Equal(t.Error,
+			root.child(nsAssertion, "Issuer").text(), sp.EntityID,
+		)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		root, err := parse(sp.Metadata())
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, root.attr("entityID"), sp.EntityID)
+		acs := root.child(nsMetadata, "SPSSODescriptor").child(
+			nsMetadata, "AssertionConsumerService",
+		)
+		assert.Equal(t.Error, acs.attr("Location"), sp.ACSURL)
+		assert.Equal(t.Error, acs.attr("Binding"), bindingPOST)
+	})
+}
+
+// TestParseResponse tests the ParseResponse method of SP to assert that it
+// returns the assertions of the valid responses of the identity provider and
+// rejects the rest.
+func TestParseResponse(t *testing.T) {
+	key, der := newCert(t)
+	otherKey, _ := newCert(t)
+	sp := SP{
+		EntityID: "https://sp.example.com",
+		ACSURL:   "https://sp.example.com/acs",
+	}
+	idp := IdP{EntityID: "https://idp.example.com", Certs: [][]byte{der}}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// the assertion relies on the response for its namespace declaration,
+	// which canonicalization must carry over to it
+	const assertion = `
+	<saml:Assertion ID="_a1" Version="2.0"
+		IssueInstant="2024-03-15T11:59:00Z">
+		<saml:Issuer>https://idp.example.com</saml:Issuer>{sig:_a1}
+		<saml:Subject>
+			<saml:NameID>bob123</saml:NameID>
+			<saml:SubjectConfirmation Method="` + methodBearer + `">
+				<saml:SubjectConfirmationData
+					Recipient="https://sp.example.com/acs"
+					NotOnOrAfter="2024-03-15T12:05:00Z"/>
+			</saml:SubjectConfirmation>
+		</saml:Subject>
+		<saml:Conditions NotBefore="2024-03-15T11:59:00Z"
+			NotOnOrAfter="2024-03-15T12:05:00Z">
+			<saml:AudienceRestriction>
+				<saml:Audience>https://sp.example.com</saml:Audience>
+			</saml:AudienceRestriction>
+		</saml:Conditions>
+		<saml:AttributeStatement>
+			<saml:Attribute Name="groups">
+				<saml:AttributeValue>dev</saml:AttributeValue>
+				<saml:AttributeValue>ops</saml:AttributeValue>
+			</saml:Attribute>
+		</saml:AttributeStatement>
+	</saml:Assertion>`
+	const response = `<samlp:Response xmlns:samlp="` + nsProtocol + `"
+	xmlns:saml="` + nsAssertion + `" ID="_r1" Version="2.0"
+	Destination="https://sp.example.com/acs">{sig:_r1}
+	<samlp:Status>
+		<samlp:StatusCode Value="` + statusSuccess + `"/>
+	</samlp:Status>{assertion}
+</samlp:Response>`
+	evil := strings.ReplaceAll(
+		strings.Replace(assertion, "bob123", "admin", 1), "_a1", "_evil",
+	)
+
+	for _, c := range []struct {
+		name string
+		// edit changes the unsigned response
+		edit func(string) string
+		// signAssertion and signResponse determine what is signed
+		signAssertion bool
+		signResponse  bool
+		// tamper changes the signed response
+		tamper  func(string) string
+		wantErr error
+	}{
+		{
+			name:          "DTD",
+			signAssertion: true,
+			tamper:        func(s string) string { return "<!DOCTYPE x>" + s },
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "WrongDestination",
+			edit: func(s string) string {
+				return strings.Replace(s, "/acs", "/other", 1)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "NotSuccess",
+			edit: func(s string) string {
+				return strings.Replace(s, "Success", "Requester", 1)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name:    "NotSigned",
+			wantErr: ErrSignature,
+		},
+		{
+			name:          "AssertionTampered",
+			signAssertion: true,
+			tamper: func(s string) string {
+				return strings.Replace(s, "bob123", "admin", 1)
+			},
+			wantErr: ErrSignature,
+		},
+		{
+			name:         "ResponseTampered",
+			signResponse: true,
+			tamper: func(s string) string {
+				return strings.Replace(s, "bob123", "admin", 1)
+			},
+			wantErr: ErrSignature,
+		},
+		{
+			name:          "WrappedAssertion",
+			signAssertion: true,
+			tamper: func(s string) string {
+				// move the signed assertion out of the way so that an
+				// unsigned one is read instead
+				s = strings.Replace(s, "<saml:Assertion",
+					"<samlp:Extensions><saml:Assertion", 1,
+				)
+				return strings.Replace(s, "</saml:Assertion>",
+					"</saml:Assertion></samlp:Extensions>"+evil, 1,
+				)
+			},
+			wantErr: ErrSignature,
+		},
+		{
+			name:          "SignatureMoved",
+			signAssertion: true,
+			tamper: func(s string) string {
+				// keep only the signature of the signed assertion, moved
+				// into another one
+				sig := s[strings.Index(s, "<ds:Signature") : strings.Index(
+					s, "</ds:Signature>",
+				)+len("</ds:Signature>")]
+				start := strings.Index(s, "<saml:Assertion")
+				end := strings.Index(s, "</saml:Assertion>") +
+					len("</saml:Assertion>")
+				return s[:start] + strings.Replace(
+					evil, "{sig:_evil}", sig, 1,
+				) + s[end:]
+			},
+			wantErr: ErrSignature,
+		},
+		{
+			name: "WrongIssuer",
+			edit: func(s string) string {
+				return strings.Replace(s,
+					"<saml:Issuer>https://idp.example.com",
+					"<saml:Issuer>https://evil.example.com", 1,
+				)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "WrongAudience",
+			edit: func(s string) string {
+				return strings.Replace(s,
+					"<saml:Audience>https://sp.example.com",
+					"<saml:Audience>https://other.example.com", 1,
+				)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "Expired",
+			edit: func(s string) string {
+				return strings.ReplaceAll(s, "12:05:00Z", "11:56:00Z")
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "NotYetValid",
+			edit: func(s string) string {
+				return strings.Replace(s,
+					`NotBefore="2024-03-15T11:59:00Z"`,
+					`NotBefore="2024-03-15T12:04:00Z"`, 1,
+				)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "WrongRecipient",
+			edit: func(s string) string {
+				return strings.Replace(s,
+					`Recipient="https://sp.example.com/acs"`,
+					`Recipient="https://other.example.com/acs"`, 1,
+				)
+			},
+			signAssertion: true,
+			wantErr:       ErrInvalid,
+		},
+		{
+			name: "TwoAssertions",
+			edit: func(s string) string {
+				return strings.Replace(s, "</samlp:Response>",
+					evil+"</samlp:Response>", 1,
+				)
+			},
+			signResponse: true,
+			wantErr:      ErrInvalid,
+		},
+		{name: "AssertionSigned", signAssertion: true},
+		{name: "ResponseSigned", signResponse: true},
+		{name: "BothSigned", signAssertion: true, signResponse: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			doc := strings.Replace(response, "{assertion}", assertion, 1)
+			if c.edit != nil {
+				doc = c.edit(doc)
+			}
+			if c.signAssertion {
+				doc = sign(t, key, doc, "_a1")
+			}
+			if c.signResponse {
+				doc = sign(t, key, doc, "_r1")
+			}
+			doc = markers.ReplaceAllString(doc, "")
+			if c.tamper != nil {
+				doc = c.tamper(doc)
+			}
+
+			a, err := sp.ParseResponse(
+				base64.StdEncoding.EncodeToString([]byte(doc)), idp, now,
+			)
+
+			if c.wantErr != nil {
+				assert.ErrIs(t.Error, err, c.wantErr)
+				return
+			}
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, a.ID, "_a1")
+			assert.Equal(t.Error, a.NameID, "bob123")
+			assert.AllEqual(t.Error,
+				a.Attributes["groups"], []string{"dev", "ops"},
+			)
+			assert.Equal(t.Error,
+				a.ExpiresAt, time.Date(2024, 3, 15, 12, 5, 0, 0, time.UTC),
+			)
+		})
+	}
+
+	t.Run("OtherKey", func(t *testing.T) {
+		doc := strings.Replace(response, "{assertion}", assertion, 1)
+		doc = markers.ReplaceAllString(sign(t, otherKey, doc, "_a1"), "")
+		_, err := sp.ParseResponse(
+			base64.StdEncoding.EncodeToString([]byte(doc)), idp, now,
+		)
+		assert.ErrIs(t.Error, err, ErrSignature)
+	})
+
+	t.Run("Replayed", func(t *testing.T) {
+		cache := NewReplayCache()
+		a := Assertion{ID: "_a1", ExpiresAt: now.Add(5 * time.Minute)}
+		assert.True(t.Error, cache.Use(a, now))
+		assert.True(t.Error, !cache.Use(a, now.Add(time.Minute)))
+
+		// the assertion is forgotten once it can no longer be used
+		assert.True(t.Error, cache.Use(a, now.Add(10*time.Minute)))
+	})
+}
@@ -0,0 +1,178 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// nsXML is the namespace that the xml prefix is bound to.
+const nsXML = "http://www.w3.org/XML/1998/namespace"
+
+// element is an XML element parsed with its namespace prefixes and
+// declarations as they were written, which encoding/xml resolves away, as
+// canonicalizing the element for its signature depends on them.
+type element struct {
+	parent *element
+	prefix string
+	local  string
+
+	// attrs holds the attributes with their prefixes in Name.Space, including
+	// the namespace declarations.
+	attrs []xml.Attr
+
+	// children holds the child *element, text, and xml.ProcInst nodes in the
+	// order they were written.
+	children []any
+}
+
+// text is the character data in an element.
+type text string
+
+// errDTD means that a document had a DTD, which is refused so that entities
+// cannot be used to alter the signed content or exhaust memory.
+var errDTD = errors.New("saml: documents with a DTD are not allowed")
+
+// parse parses the given XML document into its root element.
+func parse(data []byte) (*element, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root, cur *element
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if cur == nil && root != nil {
+				return nil, errors.New("saml: more than one root element")
+			}
+			e := &element{
+				parent: cur,
+				prefix: tok.Name.Space,
+				local:  tok.Name.Local,
+				attrs:  tok.Copy().Attr,
+			}
+			if cur == nil {
+				root = e
+			} else {
+				cur.children = append(cur.children, e)
+			}
+			cur = e
+		case xml.EndElement:
+			if cur == nil || tok.Name.Space != cur.prefix ||
+				tok.Name.Local != cur.local {
+				return nil, fmt.Errorf(
+					"saml: unexpected end element %s", tok.Name.Local,
+				)
+			}
+			cur = cur.parent
+		case xml.CharData:
+			if cur != nil {
+				cur.children = append(cur.children, text(tok))
+			} else if len(bytes.TrimSpace(tok)) > 0 {
+				return nil, errors.New("saml: text outside the root element")
+			}
+		case xml.ProcInst:
+			if cur != nil {
+				cur.children = append(cur.children, tok.Copy())
+			}
+		case xml.Directive:
+			return nil, errDTD
+		}
+	}
+	if root == nil || cur != nil {
+		return nil, errors.New("saml: incomplete document")
+	}
+	if _, ok := root.lookup(root.prefix); !ok {
+		return nil, fmt.Errorf("saml: unbound prefix %s", root.prefix)
+	}
+	return root, nil
+}
+
+// lookup returns the namespace that the given prefix is bound to in the scope
+// of the element, where the empty prefix is the default namespace.
+func (e *element) lookup(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return nsXML, true
+	}
+	for cur := e; cur != nil; cur = cur.parent {
+		for _, a := range cur.attrs {
+			if prefix == "" && a.Name.Space == "" && a.Name.Local == "xmlns" ||
+				prefix != "" && a.Name.Space == "xmlns" &&
+					a.Name.Local == prefix {
+				return a.Value, true
+			}
+		}
+	}
+	return "", prefix == ""
+}
+
+// is returns whether the element has the given namespace and local name.
+func (e *element) is(ns, local string) bool {
+	uri, _ := e.lookup(e.prefix)
+	return e.local == local && uri == ns
+}
+
+// attr returns the value of the unprefixed attribute with the given name, or
+// "" if the element is nil.
+func (e *element) attr(name string) string {
+	if e == nil {
+		return ""
+	}
+	for _, a := range e.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element with the given namespace and local
+// name, or nil if there is none.
+func (e *element) child(ns, local string) *element {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.children {
+		if c, ok := c.(*element); ok && c.is(ns, local) {
+			return c
+		}
+	}
+	return nil
+}
+
+// all returns the child elements with the given namespace and local name.
+func (e *element) all(ns, local string) []*element {
+	if e == nil {
+		return nil
+	}
+	var es []*element
+	for _, c := range e.children {
+		if c, ok := c.(*element); ok && c.is(ns, local) {
+			es = append(es, c)
+		}
+	}
+	return es
+}
+
+// text returns the character data directly in the element with the leading
+// and trailing white space removed, or "" if the element is nil.
+func (e *element) text() string {
+	if e == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range e.children {
+		if t, ok := c.(text); ok {
+			b.WriteString(string(t))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,71 @@
+package validator
+
+// Limits of the values received by the API, shared by all the handlers that
+// receive them so that they stay consistent.
+const (
+	MinUsernameLen      = 5
+	MaxUsernameLen      = 15
+	MinPasswordLen      = 8
+	MaxPasswordLen      = 64
+	MaxEmailLen         = 254
+	MaxBoardNameLen     = 35
+	MaxBoardDescLen     = 1000
+	MaxBoardDescHTML    = 10000
+	MaxSprintNameLen    = 35
+	MaxTaskTitleLen     = 50
+	MaxTaskDescLen      = 500
+	MaxTaskDescHTML     = 5000
+	MaxSubtaskTitleLen  = 50
+	MaxColNo            = 3
+	MaxColDescLen       = 200
+	MaxAnalyticsDays    = 90
+	MaxArchiveDays      = 365
+	MaxBlockers         = 20
+	MaxChecklists       = 20
+	MaxChecklistNameLen = 35
+	MaxChecklistItems   = 30
+)
+
+var (
+	// ID validates the ID of a board or a task.
+	ID = All(NotEmpty(), UUID())
+
+	// Email validates the email address an invite is sent to.
+	Email = All(NotEmpty(), MaxLen(MaxEmailLen), EmailAddr())
+
+	// BoardName validates the name of a board.
+	BoardName = All(NotEmpty(), MaxLen(MaxBoardNameLen))
+
+	// BoardDesc validates the markdown description of a board.
+	BoardDesc = All(MaxLen(MaxBoardDescLen), MaxRenderedLen(MaxBoardDescHTML))
+
+	// SprintName validates the name of a sprint.
+	SprintName = All(NotEmpty(), MaxLen(MaxSprintNameLen))
+
+	// SprintDate validates the start and end dates of a sprint.
+	SprintDate = All(NotEmpty(), Date())
+
+	// TaskTitle validates the title of a task.
+	TaskTitle = All(NotEmpty(), MaxLen(MaxTaskTitleLen))
+
+	// TaskDesc validates the markdown description of a task.
+	TaskDesc = All(MaxLen(MaxTaskDescLen), MaxRenderedLen(MaxTaskDescHTML))
+
+	// SubtaskTitle validates the title of a subtask.
+	SubtaskTitle = All(NotEmpty(), MaxLen(MaxSubtaskTitleLen))
+
+	// ChecklistName validates the name of a checklist template.
+	ChecklistName = All(NotEmpty(), MaxLen(MaxChecklistNameLen))
+
+	// ColNo validates the number of the column a task is in.
+	ColNo = Between(0, MaxColNo)
+
+	// ColColor validates the color of a column, which is optional.
+	ColColor = Optional(HexColor())
+
+	// ColDesc validates the description of a column.
+	ColDesc = MaxLen(MaxColDescLen)
+
+	// Order validates the order of a task within its column.
+	Order = Min(0)
+)
@@ -0,0 +1,258 @@
+//go:build utest
+
+package validator
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestDomainStrings tests the validators of the strings received by the API
+// to assert that they apply the shared limits.
+func TestDomainStrings(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		sut     Func[string]
+		value   string
+		wantErr error
+	}{
+		{name: "IDEmpty", sut: ID, value: "", wantErr: ErrEmpty},
+		{name: "IDNotUUID", sut: ID, value: "21", wantErr: ErrWrongFormat},
+		{
+			name:    "IDOK",
+			sut:     ID,
+			value:   "97377e55-5a2a-4172-bf5d-354b40aa2735",
+			wantErr: nil,
+		},
+		{name: "EmailEmpty", sut: Email, value: "", wantErr: ErrEmpty},
+		{
+			name:    "EmailTooLong",
+			sut:     Email,
+			value:   strings.Repeat("a", 250) + "@b.co",
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "EmailNoAt",
+			sut:     Email,
+			value:   "bob.example.com",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "EmailDisplayName",
+			sut:     Email,
+			value:   "Bob <bob@example.com>",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "EmailLineBreak",
+			sut:     Email,
+			value:   "bob@example.com\r\nBcc: eve@example.com",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "EmailOK",
+			sut:     Email,
+			value:   "bob@example.com",
+			wantErr: nil,
+		},
+		{
+			name:    "BoardNameEmpty",
+			sut:     BoardName,
+			value:   "",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "BoardNameTooLong",
+			sut:     BoardName,
+			value:   "boardyboardsyboardkyboardishboardxyz",
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "BoardNameOK",
+			sut:     BoardName,
+			value:   "My Board",
+			wantErr: nil,
+		},
+		{
+			name:    "BoardDescTooLong",
+			sut:     BoardDesc,
+			value:   strings.Repeat("a", MaxBoardDescLen+1),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "BoardDescRenderedTooLong",
+			sut:     BoardDesc,
+			value:   strings.Repeat(">>>>\n\n", 160),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "BoardDescOK",
+			sut:     BoardDesc,
+			value:   "",
+			wantErr: nil,
+		},
+		{
+			name:    "SprintNameEmpty",
+			sut:     SprintName,
+			value:   "",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "SprintNameTooLong",
+			sut:     SprintName,
+			value:   strings.Repeat("a", MaxSprintNameLen+1),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "SprintDateEmpty",
+			sut:     SprintDate,
+			value:   "",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "SprintDateWrongFormat",
+			sut:     SprintDate,
+			value:   "01/02/2024",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "SprintDateOK",
+			sut:     SprintDate,
+			value:   "2024-01-02",
+			wantErr: nil,
+		},
+		{
+			name:    "TaskTitleEmpty",
+			sut:     TaskTitle,
+			value:   "",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "TaskTitleTooLong",
+			sut:     TaskTitle,
+			value:   strings.Repeat("a", MaxTaskTitleLen+1),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "TaskTitleOK",
+			sut:     TaskTitle,
+			value:   "Some Task",
+			wantErr: nil,
+		},
+		{
+			name:    "TaskDescTooLong",
+			sut:     TaskDesc,
+			value:   strings.Repeat("a", MaxTaskDescLen+1),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "TaskDescRenderedTooLong",
+			sut:     TaskDesc,
+			value:   strings.Repeat(">>>>\n\n", 80),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "TaskDescEmpty",
+			sut:     TaskDesc,
+			value:   "",
+			wantErr: nil,
+		},
+		{name: "ColColorEmpty", sut: ColColor, value: "", wantErr: nil},
+		{
+			name:    "ColColorInvalid",
+			sut:     ColColor,
+			value:   "red",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "ColDescTooLong",
+			sut:     ColDesc,
+			value:   strings.Repeat("a", MaxColDescLen+1),
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "SubtaskTitleEmpty",
+			sut:     SubtaskTitle,
+			value:   "",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "SubtaskTitleTooLong",
+			sut:     SubtaskTitle,
+			value:   strings.Repeat("a", MaxSubtaskTitleLen+1),
+			wantErr: ErrTooLong,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrIs(t.Error, c.sut.Validate(c.value), c.wantErr)
+		})
+	}
+}
+
+// TestDomainInts tests the validators of the integers received by the API to
+// assert that they apply the shared limits.
+func TestDomainInts(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		sut     Func[int]
+		value   int
+		wantErr error
+	}{
+		{name: "ColNoTooSmall", sut: ColNo, value: -1, wantErr: ErrOutOfBounds},
+		{name: "ColNoTooBig", sut: ColNo, value: 4, wantErr: ErrOutOfBounds},
+		{name: "ColNoOK", sut: ColNo, value: 2, wantErr: nil},
+		{name: "OrderNegative", sut: Order, value: -1, wantErr: ErrOutOfBounds},
+		{name: "OrderOK", sut: Order, value: 0, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrIs(t.Error, c.sut.Validate(c.value), c.wantErr)
+		})
+	}
+}
+
+// FuzzBoardName fuzzes BoardName to assert that it accepts exactly the names
+// that are not empty and are at most MaxBoardNameLen characters long.
+func FuzzBoardName(f *testing.F) {
+	fuzzRequiredMaxLen(f, BoardName, MaxBoardNameLen)
+}
+
+// FuzzTaskTitle fuzzes TaskTitle to assert that it accepts exactly the titles
+// that are not empty and are at most MaxTaskTitleLen characters long.
+func FuzzTaskTitle(f *testing.F) {
+	fuzzRequiredMaxLen(f, TaskTitle, MaxTaskTitleLen)
+}
+
+// fuzzRequiredMaxLen fuzzes the given rule to assert that it accepts exactly
+// the strings that are not empty and are at most maxLen characters long,
+// counting each invalid UTF-8 byte as a character.
+func fuzzRequiredMaxLen(f *testing.F, rule Func[string], maxLen int) {
+	for _, s := range []string{
+		"",
+		"a",
+		strings.Repeat("a", maxLen),
+		strings.Repeat("a", maxLen+1),
+		strings.Repeat("ş", maxLen),
+		strings.Repeat("ş", maxLen+1),
+		"\xff\xfe\x00",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		n := utf8.RuneCountInString(s)
+		assert.Equal(t.Error, rule(s) == nil, n > 0 && n <= maxLen)
+	})
+}
+
+// FuzzColNo fuzzes ColNo to assert that it accepts exactly the column numbers
+// from 0 to MaxColNo.
+func FuzzColNo(f *testing.F) {
+	for _, n := range []int{-1, 0, MaxColNo, MaxColNo + 1, -1 << 63} {
+		f.Add(n)
+	}
+	f.Fuzz(func(t *testing.T, n int) {
+		assert.Equal(t.Error, ColNo(n) == nil, n >= 0 && n <= MaxColNo)
+	})
+}
@@ -8,6 +8,12 @@ type FakeString struct{ Err error }
 // Validate discards the given param and returns the fake's Err field value.
 func (f FakeString) Validate(string) error { return f.Err }
 
+// FakeInt is a test fake for Int.
+type FakeInt struct{ Err error }
+
+// Validate discards the given param and returns the fake's Err field value.
+func (f FakeInt) Validate(int) error { return f.Err }
+
 // FakeFunc is a test fake for Func.
 type FakeFunc[T any] struct{ Err error }
 
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// FieldErr is the error of a rule that failed for a field, along with the path
+// to the field in the value being validated (e.g. subtasks[0].title).
+type FieldErr struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path to the field and the error of the rule that failed.
+func (e FieldErr) Error() string { return e.Path + ": " + e.Err.Error() }
+
+// Unwrap returns the error of the rule that failed.
+func (e FieldErr) Unwrap() error { return e.Err }
+
+// Errs is the aggregated errors of all the fields that failed validation, in
+// the order they were validated.
+type Errs []FieldErr
+
+// Error returns the errors of all fields that failed validation.
+func (e Errs) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of all fields so that errors.Is and errors.As
+// match any of them.
+func (e Errs) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Field validates the value of the field at the given path with the rule,
+// returning a FieldErr if it fails.
+func Field[T any](path string, v T, rule Func[T]) error {
+	if err := rule(v); err != nil {
+		return FieldErr{Path: path, Err: err}
+	}
+	return nil
+}
+
+// Collect aggregates the given errors into Errs, flattening any Errs among
+// them, and returns nil if all are nil. Errors that are not field errors are
+// kept with an empty path.
+func Collect(errs ...error) error {
+	var all Errs
+	for _, err := range errs {
+		var fe FieldErr
+		var fes Errs
+		switch {
+		case err == nil:
+			continue
+		case errors.As(err, &fes):
+			all = append(all, fes...)
+		case errors.As(err, &fe):
+			all = append(all, fe)
+		default:
+			all = append(all, FieldErr{Err: err})
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	return all
+}
+
+// First returns the first field error in err, which is either a FieldErr or
+// an Errs, and whether there was one.
+func First(err error) (FieldErr, bool) {
+	var fes Errs
+	if errors.As(err, &fes) && len(fes) > 0 {
+		return fes[0], true
+	}
+	var fe FieldErr
+	if errors.As(err, &fe) {
+		return fe, true
+	}
+	return FieldErr{}, false
+}
+
+// Path joins the given elements into the path of a nested field, with strings
+// as field names and ints as indexes (e.g. Path("subtasks", 0, "title") is
+// "subtasks[0].title").
+func Path(elems ...any) string {
+	var b strings.Builder
+	for _, e := range elems {
+		switch e := e.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(e) + "]")
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(e)
+		}
+	}
+	return b.String()
+}
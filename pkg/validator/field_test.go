@@ -0,0 +1,130 @@
+//go:build utest
+
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestField tests the Field function to assert that it returns a FieldErr
+// with the path to the field only when the rule fails.
+func TestField(t *testing.T) {
+	t.Run("Fails", func(t *testing.T) {
+		err := Field("title", "", NotEmpty())
+
+		var fe FieldErr
+		assert.True(t.Fatal, errors.As(err, &fe))
+		assert.Equal(t.Error, fe.Path, "title")
+		assert.ErrIs(t.Error, err, ErrEmpty)
+		assert.Equal(t.Error, err.Error(), "title: empty")
+	})
+
+	t.Run("Passes", func(t *testing.T) {
+		assert.Nil(t.Error, Field("title", "Some Task", NotEmpty()))
+	})
+}
+
+// TestCollect tests the Collect function to assert that it aggregates the
+// errors of all failed fields in order.
+func TestCollect(t *testing.T) {
+	t.Run("NoErrs", func(t *testing.T) {
+		assert.Nil(t.Error, Collect(nil, nil))
+	})
+
+	t.Run("Errs", func(t *testing.T) {
+		errOther := errors.New("other")
+
+		err := Collect(
+			Field("title", "", NotEmpty()),
+			nil,
+			Collect(Field("colNo", 4, ColNo), Field("order", -1, Order)),
+			errOther,
+		)
+
+		var errs Errs
+		assert.True(t.Fatal, errors.As(err, &errs))
+		assert.Equal(t.Fatal, len(errs), 4)
+		assert.Equal(t.Error, errs[0].Path, "title")
+		assert.Equal(t.Error, errs[1].Path, "colNo")
+		assert.Equal(t.Error, errs[2].Path, "order")
+		assert.Equal(t.Error, errs[3].Path, "")
+		assert.ErrIs(t.Error, err, ErrEmpty)
+		assert.ErrIs(t.Error, err, ErrOutOfBounds)
+		assert.ErrIs(t.Error, err, errOther)
+		assert.Equal(
+			t.Error,
+			err.Error(),
+			"title: empty; colNo: out of bounds; order: out of bounds; : other",
+		)
+	})
+}
+
+// TestFirst tests the First function to assert that it returns the first
+// field error in an error.
+func TestFirst(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		err      error
+		wantPath string
+		wantOK   bool
+	}{
+		{
+			name:     "Nil",
+			err:      nil,
+			wantPath: "",
+			wantOK:   false,
+		},
+		{
+			name:     "Other",
+			err:      errors.New("other"),
+			wantPath: "",
+			wantOK:   false,
+		},
+		{
+			name:     "FieldErr",
+			err:      Field("title", "", NotEmpty()),
+			wantPath: "title",
+			wantOK:   true,
+		},
+		{
+			name: "Errs",
+			err: Collect(
+				Field("colNo", 4, ColNo), Field("title", "", NotEmpty()),
+			),
+			wantPath: "colNo",
+			wantOK:   true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			fe, ok := First(c.err)
+
+			assert.Equal(t.Error, ok, c.wantOK)
+			assert.Equal(t.Error, fe.Path, c.wantPath)
+		})
+	}
+}
+
+// TestPath tests the Path function to assert that it joins field names and
+// indexes into paths.
+func TestPath(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		elems []any
+		want  string
+	}{
+		{name: "Empty", elems: nil, want: ""},
+		{name: "Field", elems: []any{"title"}, want: "title"},
+		{
+			name:  "Nested",
+			elems: []any{"columns", 2, "tasks", 0, "title"},
+			want:  "columns[2].tasks[0].title",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Path(c.elems...), c.want)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"net/mail"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/markdown"
+)
+
+// NotEmpty returns a rule that fails with ErrEmpty for empty strings.
+func NotEmpty() Func[string] {
+	return func(s string) error {
+		if s == "" {
+			return ErrEmpty
+		}
+		return nil
+	}
+}
+
+// Optional returns a rule that applies the given rule to non-empty strings
+// only.
+func Optional(rule Func[string]) Func[string] {
+	return func(s string) error {
+		if s == "" {
+			return nil
+		}
+		return rule(s)
+	}
+}
+
+// MinLen returns a rule that fails with ErrTooShort for strings with fewer
+// than n characters.
+func MinLen(n int) Func[string] {
+	return func(s string) error {
+		if len([]rune(s)) < n {
+			return ErrTooShort
+		}
+		return nil
+	}
+}
+
+// MaxLen returns a rule that fails with ErrTooLong for strings with more than
+// n characters.
+func MaxLen(n int) Func[string] {
+	return func(s string) error {
+		if len([]rune(s)) > n {
+			return ErrTooLong
+		}
+		return nil
+	}
+}
+
+// MaxRenderedLen returns a rule that fails with ErrTooLong for markdown that
+// renders into more than n bytes of HTML, which keeps markup that is short but
+// expands a lot when rendered from being stored.
+func MaxRenderedLen(n int) Func[string] {
+	return func(s string) error {
+		if len(markdown.Render(s)) > n {
+			return ErrTooLong
+		}
+		return nil
+	}
+}
+
+// UUID returns a rule that fails with ErrWrongFormat for strings that are not
+// UUIDs.
+func UUID() Func[string] {
+	return func(s string) error {
+		if _, err := uuid.Parse(s); err != nil {
+			return ErrWrongFormat
+		}
+		return nil
+	}
+}
+
+// HexColor returns a rule that fails with ErrWrongFormat for strings that are
+// not hex colors in the #rrggbb format.
+func HexColor() Func[string] {
+	return func(s string) error {
+		if !hexColor.MatchString(s) {
+			return ErrWrongFormat
+		}
+		return nil
+	}
+}
+
+// hexColor matches hex colors in the #rrggbb format.
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// DateLayout is the layout of the dates received by the API.
+const DateLayout = "2006-01-02"
+
+// Date returns a rule that fails with ErrWrongFormat for strings that are not
+// dates in the YYYY-MM-DD format.
+func Date() Func[string] {
+	return func(s string) error {
+		if _, err := time.Parse(DateLayout, s); err != nil {
+			return ErrWrongFormat
+		}
+		return nil
+	}
+}
+
+// EmailAddr returns a rule that fails with ErrWrongFormat for strings that are
+// not bare email addresses, without a display name.
+func EmailAddr() Func[string] {
+	return func(s string) error {
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return ErrWrongFormat
+		}
+		return nil
+	}
+}
+
+// Between returns a rule that fails with ErrOutOfBounds for integers outside
+// the range from min to max, inclusive.
+func Between(min, max int) Func[int] {
+	return func(n int) error {
+		if n < min || n > max {
+			return ErrOutOfBounds
+		}
+		return nil
+	}
+}
+
+// Min returns a rule that fails with ErrOutOfBounds for integers smaller than
+// min.
+func Min(min int) Func[int] {
+	return func(n int) error {
+		if n < min {
+			return ErrOutOfBounds
+		}
+		return nil
+	}
+}
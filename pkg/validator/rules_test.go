@@ -0,0 +1,115 @@
+//go:build utest
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestStringRules tests the rules for strings to assert that they fail with
+// the correct error.
+func TestStringRules(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		rule    Func[string]
+		value   string
+		wantErr error
+	}{
+		{name: "NotEmpty", rule: NotEmpty(), value: "", wantErr: ErrEmpty},
+		{name: "NotEmptyOK", rule: NotEmpty(), value: "a", wantErr: nil},
+		{name: "Optional", rule: Optional(UUID()), value: "", wantErr: nil},
+		{
+			name:    "OptionalSet",
+			rule:    Optional(UUID()),
+			value:   "21",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "HexColor",
+			rule:    HexColor(),
+			value:   "#12345g",
+			wantErr: ErrWrongFormat,
+		},
+		{name: "HexColorOK", rule: HexColor(), value: "#1a2B3c", wantErr: nil},
+		{
+			name:    "Date",
+			rule:    Date(),
+			value:   "2024-02-30",
+			wantErr: ErrWrongFormat,
+		},
+		{name: "DateOK", rule: Date(), value: "2024-02-29", wantErr: nil},
+		{name: "MinLen", rule: MinLen(3), value: "ab", wantErr: ErrTooShort},
+		{name: "MinLenOK", rule: MinLen(3), value: "abc", wantErr: nil},
+		{name: "MaxLen", rule: MaxLen(3), value: "abcd", wantErr: ErrTooLong},
+		{name: "MaxLenOK", rule: MaxLen(3), value: "abc", wantErr: nil},
+		{name: "MaxLenRunes", rule: MaxLen(3), value: "çöü", wantErr: nil},
+		{
+			name:    "MaxRenderedLen",
+			rule:    MaxRenderedLen(20),
+			value:   "**a**",
+			wantErr: ErrTooLong,
+		},
+		{
+			name:    "MaxRenderedLenOK",
+			rule:    MaxRenderedLen(20),
+			value:   "a",
+			wantErr: nil,
+		},
+		{name: "UUID", rule: UUID(), value: "21", wantErr: ErrWrongFormat},
+		{
+			name:    "UUIDOK",
+			rule:    UUID(),
+			value:   "97377e55-5a2a-4172-bf5d-354b40aa2735",
+			wantErr: nil,
+		},
+		{
+			name:    "EmailAddr",
+			rule:    EmailAddr(),
+			value:   "Bob <bob@example.com>",
+			wantErr: ErrWrongFormat,
+		},
+		{
+			name:    "EmailAddrOK",
+			rule:    EmailAddr(),
+			value:   "bob@example.com",
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrIs(t.Error, c.rule(c.value), c.wantErr)
+		})
+	}
+}
+
+// TestIntRules tests the rules for integers to assert that they fail with the
+// correct error.
+func TestIntRules(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		rule    Func[int]
+		value   int
+		wantErr error
+	}{
+		{
+			name:    "BetweenLow",
+			rule:    Between(0, 3),
+			value:   -1,
+			wantErr: ErrOutOfBounds,
+		},
+		{
+			name:    "BetweenHigh",
+			rule:    Between(0, 3),
+			value:   4,
+			wantErr: ErrOutOfBounds,
+		},
+		{name: "BetweenOK", rule: Between(0, 3), value: 3, wantErr: nil},
+		{name: "Min", rule: Min(0), value: -1, wantErr: ErrOutOfBounds},
+		{name: "MinOK", rule: Min(0), value: 0, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrIs(t.Error, c.rule(c.value), c.wantErr)
+		})
+	}
+}
@@ -1,4 +1,6 @@
-// Package validator contains code reused by validators.
+// Package validator contains the rules that values received by the API are
+// validated against, and the code to compose them into validators and to
+// aggregate their errors across the fields of a request.
 package validator
 
 import (
@@ -9,6 +11,9 @@ var (
 	// ErrEmpty means that the value being validated was empty.
 	ErrEmpty = errors.New("empty")
 
+	// ErrTooShort means that the value being validated was too short.
+	ErrTooShort = errors.New("too short")
+
 	// ErrTooLong means that the value being validated was too long.
 	ErrTooLong = errors.New("too long")
 
@@ -27,5 +32,23 @@ type String interface{ Validate(string) error }
 // Int describes a type that can be used to validate an integer.
 type Int interface{ Validate(int) error }
 
-// Func describes a function that can be used to validate a value.
+// Func describes a function that can be used to validate a value. Funcs are
+// the rules that validators are composed of.
 type Func[T any] func(T) error
+
+// Validate validates the value using the function, so that a Func[string] can
+// be used as a String and a Func[int] as an Int.
+func (f Func[T]) Validate(v T) error { return f(v) }
+
+// All returns a Func that applies the given rules in order and returns the
+// error of the first one that fails.
+func All[T any](rules ...Func[T]) Func[T] {
+	return func(v T) error {
+		for _, rule := range rules {
+			if err := rule(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
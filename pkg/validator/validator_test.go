@@ -0,0 +1,58 @@
+//go:build utest
+
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestAll tests the Func returned by All to assert that it applies the rules
+// in order and returns the error of the first one that fails.
+func TestAll(t *testing.T) {
+	var calls []int
+	rule := func(i int, err error) Func[string] {
+		return func(string) error {
+			calls = append(calls, i)
+			return err
+		}
+	}
+	errRule := errors.New("rule failed")
+
+	for _, c := range []struct {
+		name      string
+		rules     []Func[string]
+		wantErr   error
+		wantCalls []int
+	}{
+		{
+			name:      "None",
+			rules:     nil,
+			wantErr:   nil,
+			wantCalls: nil,
+		},
+		{
+			name:      "AllPass",
+			rules:     []Func[string]{rule(0, nil), rule(1, nil)},
+			wantErr:   nil,
+			wantCalls: []int{0, 1},
+		},
+		{
+			name:      "FirstFails",
+			rules:     []Func[string]{rule(0, errRule), rule(1, nil)},
+			wantErr:   errRule,
+			wantCalls: []int{0},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			calls = nil
+
+			err := All(c.rules...).Validate("value")
+
+			assert.ErrIs(t.Error, err, c.wantErr)
+			assert.AllEqual(t.Error, calls, c.wantCalls)
+		})
+	}
+}
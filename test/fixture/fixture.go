@@ -0,0 +1,29 @@
+//go:build itest
+
+// Package fixture contains builders for the entities stored in the test tables
+// and helpers to turn them into the write requests the tables are populated
+// with, so that the integration tests can declare their data in terms of the
+// domain rather than raw DynamoDB attribute values.
+package fixture
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// MustWriteReqs marshals each of the given items the same way the inserters in
+// pkg/db do, and returns a put request for each. It panics if an item cannot be
+// marshalled, which can only happen due to a programming error in a test.
+func MustWriteReqs[T any](items ...T) []types.WriteRequest {
+	reqs := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			panic(err)
+		}
+		reqs = append(reqs, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: av},
+		})
+	}
+	return reqs
+}
@@ -0,0 +1,53 @@
+//go:build itest
+
+package fixture
+
+import "github.com/kxplxn/goteam/pkg/db/tasktbl"
+
+// TaskBuilder builds a tasktbl.Task. Its methods return a modified copy of the
+// builder so that calls can be chained.
+type TaskBuilder struct{ task tasktbl.Task }
+
+// Task returns a TaskBuilder for a task with the given ID on the given board of
+// the given team. The task is in the first column and has no subtasks.
+func Task(teamID, boardID, id string) TaskBuilder {
+	return TaskBuilder{task: tasktbl.Task{
+		TeamID: teamID, BoardID: boardID, ID: id,
+	}}
+}
+
+// Col sets the number of the column the task is in.
+func (b TaskBuilder) Col(no int) TaskBuilder {
+	b.task.ColNo = no
+	return b
+}
+
+// Title sets the task's title.
+func (b TaskBuilder) Title(title string) TaskBuilder {
+	b.task.Title = title
+	return b
+}
+
+// Description sets the task's description.
+func (b TaskBuilder) Description(descr string) TaskBuilder {
+	b.task.Description = descr
+	return b
+}
+
+// Order sets the task's position in its column.
+func (b TaskBuilder) Order(order int) TaskBuilder {
+	b.task.Order = order
+	return b
+}
+
+// Subtask adds a subtask with the given title and done state to the task.
+func (b TaskBuilder) Subtask(title string, isDone bool) TaskBuilder {
+	b.task.Subtasks = append(
+		append([]tasktbl.Subtask{}, b.task.Subtasks...),
+		tasktbl.NewSubtask(title, isDone),
+	)
+	return b
+}
+
+// Build returns the built task.
+func (b TaskBuilder) Build() tasktbl.Task { return b.task }
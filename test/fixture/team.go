@@ -0,0 +1,58 @@
+//go:build itest
+
+package fixture
+
+import "github.com/kxplxn/goteam/pkg/db/teamtbl"
+
+// TeamBuilder builds a teamtbl.Team. Its methods return a modified copy of the
+// builder so that calls can be chained.
+type TeamBuilder struct{ team teamtbl.Team }
+
+// Team returns a TeamBuilder for a team with the given ID and no members or
+// boards.
+func Team(id string) TeamBuilder {
+	return TeamBuilder{team: teamtbl.Team{
+		ID: id, Members: []string{}, Boards: []teamtbl.Board{},
+	}}
+}
+
+// Members adds the given usernames to the team's members.
+func (b TeamBuilder) Members(usernames ...string) TeamBuilder {
+	b.team.Members = append(append([]string{}, b.team.Members...), usernames...)
+	return b
+}
+
+// Boards adds the boards built by the given builders to the team.
+func (b TeamBuilder) Boards(boards ...BoardBuilder) TeamBuilder {
+	b.team.Boards = append([]teamtbl.Board{}, b.team.Boards...)
+	for _, board := range boards {
+		b.team.Boards = append(b.team.Boards, board.Build())
+	}
+	return b
+}
+
+// Build returns the built team.
+func (b TeamBuilder) Build() teamtbl.Team { return b.team }
+
+// BoardBuilder builds a teamtbl.Board. Its methods return a modified copy of
+// the builder so that calls can be chained.
+type BoardBuilder struct{ board teamtbl.Board }
+
+// Board returns a BoardBuilder for a board with the given ID and name. Its
+// members are left unset, as they are on boards created before board
+// membership was introduced, until Members is called.
+func Board(id, name string) BoardBuilder {
+	return BoardBuilder{board: teamtbl.NewBoard(id, name)}
+}
+
+// Members adds the given usernames to the board's members. Calling it with no
+// usernames sets the members to an empty list.
+func (b BoardBuilder) Members(usernames ...string) BoardBuilder {
+	b.board.Members = append(
+		append([]string{}, b.board.Members...), usernames...,
+	)
+	return b
+}
+
+// Build returns the built board.
+func (b BoardBuilder) Build() teamtbl.Board { return b.board }
@@ -0,0 +1,47 @@
+//go:build itest
+
+package fixture
+
+import "github.com/kxplxn/goteam/pkg/db/usertbl"
+
+// PasswordHash is the bcrypt hash of Password, which the users built by
+// UserBuilder have unless told otherwise.
+const PasswordHash = "$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZ" +
+	"NCqTO"
+
+// Password is the plaintext password the users built by UserBuilder log in
+// with unless told otherwise.
+const Password = "P4ssw@rd123"
+
+// UserBuilder builds a usertbl.User. Its methods return a modified copy of the
+// builder so that calls can be chained.
+type UserBuilder struct{ user usertbl.User }
+
+// User returns a UserBuilder for a non-admin user with the given username and
+// PasswordHash as its password.
+func User(username string) UserBuilder {
+	return UserBuilder{user: usertbl.User{
+		Username: username, Password: []byte(PasswordHash),
+	}}
+}
+
+// PasswordHash sets the user's password to the given bcrypt hash.
+func (b UserBuilder) PasswordHash(hash string) UserBuilder {
+	b.user.Password = []byte(hash)
+	return b
+}
+
+// Admin makes the user the admin of its team.
+func (b UserBuilder) Admin() UserBuilder {
+	b.user.IsAdmin = true
+	return b
+}
+
+// Team sets the ID of the team the user is a member of.
+func (b UserBuilder) Team(id string) UserBuilder {
+	b.user.TeamID = id
+	return b
+}
+
+// Build returns the built user.
+func (b UserBuilder) Build() usertbl.User { return b.user }
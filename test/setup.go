@@ -4,14 +4,20 @@ package test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db/retry"
 )
 
 // AddAuthCookie is used in various test cases to authenticate the request being
@@ -34,18 +40,88 @@ func AddStateCookie(token string) func(*http.Request) {
 	}
 }
 
+// dbImage is the Docker image StartDB runs dynamodb-local from. It is the same
+// one used by build/package/db for local development.
+const dbImage = "amazon/dynamodb-local:2.2.1"
+
+// StartDB starts a dynamodb-local container for the integration tests to run
+// against and points DB at it, so that the tests need neither AWS credentials
+// nor network access beyond the Docker daemon. If DYNAMODB_ENDPOINT is set, no
+// container is started and that endpoint is used instead. The returned
+// tear-down function stops the container, which removes it as well.
+func StartDB() (func() error, error) {
+	if os.Getenv("DYNAMODB_ENDPOINT") != "" {
+		return tearDownNone, nil
+	}
+
+	fmt.Println("starting dynamodb-local container")
+	out, err := exec.Command(
+		"docker", "run", "--detach", "--rm", "--publish", "127.0.0.1::8000",
+		dbImage, "-jar", "DynamoDBLocal.jar", "-inMemory", "-sharedDb",
+	).Output()
+	if err != nil {
+		return tearDownNone, fmt.Errorf("docker run: %w", err)
+	}
+	id := strings.TrimSpace(string(out))
+	tearDown := func() error {
+		return exec.Command("docker", "stop", id).Run()
+	}
+
+	// find the host port Docker assigned to the container's port 8000
+	out, err = exec.Command("docker", "port", id, "8000/tcp").Output()
+	if err != nil {
+		return tearDown, fmt.Errorf("docker port: %w", err)
+	}
+	addr, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if err := os.Setenv("DYNAMODB_ENDPOINT", "http://"+addr); err != nil {
+		return tearDown, err
+	}
+
+	return tearDown, ensureDBReady(DB())
+}
+
 // DB returns the DynamoDB client used in integration tests. If the client has
-// not yet been created, it is created and returned.
+// not yet been created, it is created and returned. It connects to the
+// endpoint in DYNAMODB_ENDPOINT, or to dynamodb-local on port 8000 if unset.
 func DB() *dynamodb.Client {
 	if db == nil {
+		endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "http://localhost:8000"
+		}
 		db = dynamodb.NewFromConfig(aws.Config{
 			Region:       "local",
-			BaseEndpoint: aws.String("http://localhost:8000"),
+			BaseEndpoint: aws.String(endpoint),
+			Credentials: credentials.NewStaticCredentialsProvider(
+				"local", "local", "",
+			),
 		})
 	}
 	return db
 }
 
+// ensureDBReady lists the tables in DynamoDB every 500 milliseconds until it
+// succeeds, or returns an error if it does not within 30 seconds. It is used to
+// wait for a freshly started dynamodb-local container to accept requests.
+func ensureDBReady(svc *dynamodb.Client) error {
+	fmt.Println("waiting for dynamodb-local to be ready")
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		_, err := svc.ListTables(
+			context.TODO(), &dynamodb.ListTablesInput{},
+		)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Join(
+				errors.New("dynamodb-local was not ready in time"), err,
+			)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
 // SetUpTestTable sets up a test table in DynamoDB.
 func SetUpTestTable(
 	envVar string,
@@ -73,13 +149,13 @@ func SetUpTestTable(
 		return tearDown, err
 	}
 
-	// populate test table with given write requests
-	_, err = DB().BatchWriteItem(context.TODO(), &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{
-			tableName: writeReqs,
-		},
-	})
-	if err != nil {
+	// populate test table with given write requests, retrying the ones that
+	// dynamodb-local leaves unprocessed so that no fixture is silently missing
+	if err := retry.DefaultBackoff().BatchWrite(
+		context.TODO(),
+		DB(),
+		map[string][]types.WriteRequest{tableName: writeReqs},
+	); err != nil {
 		return tearDown, err
 	}
 
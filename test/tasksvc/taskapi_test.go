@@ -19,34 +19,51 @@ import (
 	"github.com/kxplxn/goteam/internal/tasksvc/taskapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/validator"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTaskAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
-	titleValidator := taskapi.NewTitleValidator()
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewKeys(test.JWTKey), clock.System{},
+	)
+	store := memdb.NewStore()
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
-		http.MethodPost: taskapi.NewPostHandler(
-			authDecoder,
+		http.MethodPost: api.Authed(authDecoder, log, taskapi.NewPostHandler(
 			taskapi.ValidatePostReq,
+			quota.Default(),
+			teamRetriever(),
+			tasktbl.NewRetrieverByBoard(test.DB()),
 			tasktbl.NewInserter(test.DB()),
+			memdb.NewHistoryInserter(store),
 			log,
-		),
-		http.MethodPatch: taskapi.NewPatchHandler(
-			authDecoder,
-			titleValidator,
-			titleValidator,
+		)),
+		http.MethodPatch: api.Authed(authDecoder, log, taskapi.NewPatchHandler(
+			validator.TaskTitle,
+			validator.TaskDesc,
+			validator.SubtaskTitle,
+			teamRetriever(),
+			tasktbl.NewRetriever(test.DB()),
+			tasktbl.NewRetrieverByBoard(test.DB()),
 			tasktbl.NewUpdater(test.DB()),
+			memdb.NewHistoryInserter(store),
 			log,
-		),
-		http.MethodDelete: taskapi.NewDeleteHandler(
-			authDecoder,
-			tasktbl.NewDeleter(test.DB()),
-			log,
+		)),
+		http.MethodDelete: api.Authed(
+			authDecoder, log, taskapi.NewDeleteHandler(
+				tasktbl.NewRetriever(test.DB()),
+				memdb.NewTrashInserter(store),
+				memdb.NewTrashDeleter(store),
+				tasktbl.NewDeleter(test.DB()),
+				log,
+			),
 		),
 	})
 
@@ -17,29 +17,39 @@ import (
 	"github.com/kxplxn/goteam/internal/tasksvc/tasksapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTasksAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewKeys(test.JWTKey), clock.System{},
+	)
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
-		http.MethodGet: tasksapi.NewGetHandler(
-			tasksapi.NewBoardIDValidator(),
+		http.MethodGet: api.Authed(authDecoder, log, tasksapi.NewGetHandler(
+			validator.ID,
+			validator.ColNo,
+			tasktbl.NewRetrieverByBoard(test.DB()),
 			tasktbl.NewRetrieverByBoard(test.DB()),
-			authDecoder,
 			tasktbl.NewRetrieverByTeam(test.DB()),
+			teamRetriever(),
+			api.NewCursorSigner(test.JWTKey),
 			log,
-		),
-		http.MethodPatch: tasksapi.NewPatchHandler(
-			authDecoder,
-			tasksapi.NewColNoValidator(),
-			tasktbl.NewMultiUpdater(test.DB()),
+		)),
+		http.MethodPatch: api.Authed(authDecoder, log, tasksapi.NewPatchHandler(
+			validator.ColNo,
+			teamRetriever(),
+			tasktbl.NewRetrieverByBoard(test.DB()),
+			tasktbl.NewTransactionalUpdater(test.DB()),
+			memdb.NewHistoryInserter(memdb.NewStore()),
 			log,
-		),
+		)),
 	})
 
 	t.Run("GET", func(t *testing.T) {
@@ -83,7 +93,7 @@ func TestTasksAPI(t *testing.T) {
 					assertFunc: func(
 						t *testing.T, resp *http.Response, _ string,
 					) {
-						wantResp := tasksapi.GetResp{
+						wantResp := []tasktbl.Task{
 							{
 								TeamID: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bb" +
 									"c",
@@ -190,7 +200,7 @@ func TestTasksAPI(t *testing.T) {
 					assertFunc: func(
 						t *testing.T, resp *http.Response, _ string,
 					) {
-						wantResp := tasksapi.GetResp{
+						wantResp := []tasktbl.Task{
 							{
 								TeamID:  "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
 								BoardID: "91536664-9749-4dbb-a470-6e52aa353ae4",
@@ -16,35 +16,47 @@ import (
 	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/validator"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestBoardAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
-	nameValidator := boardapi.NewNameValidator()
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewKeys(test.JWTKey), clock.System{},
+	)
+	store := memdb.NewStore()
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
-		http.MethodPost: boardapi.NewPostHandler(
-			authDecoder,
-			nameValidator,
-			teamtbl.NewBoardInserter(test.DB()),
+		http.MethodPost: api.Authed(authDecoder, log, boardapi.NewPostHandler(
+			validator.BoardName,
+			validator.BoardDesc,
+			teamtbl.NewBoardInserter(test.DB(), quota.Default()),
 			log,
+		)),
+		http.MethodDelete: api.Authed(
+			authDecoder, log, boardapi.NewDeleteHandler(
+				teamtbl.NewRetriever(test.DB()),
+				memdb.NewTrashInserter(store),
+				memdb.NewTrashDeleter(store),
+				teamtbl.NewBoardDeleter(test.DB()),
+				memdb.NewAuditInserter(store),
+				log,
+			),
 		),
-		http.MethodDelete: boardapi.NewDeleteHandler(
-			authDecoder,
-			teamtbl.NewBoardDeleter(test.DB()),
-			log,
-		),
-		http.MethodPatch: boardapi.NewPatchHandler(
-			authDecoder,
-			boardapi.NewIDValidator(),
-			nameValidator,
+		http.MethodPatch: api.Authed(authDecoder, log, boardapi.NewPatchHandler(
+			validator.ID,
+			validator.BoardName,
+			validator.BoardDesc,
+			boardapi.ValidateColumns,
 			teamtbl.NewBoardUpdater(test.DB()),
 			log,
-		),
+		)),
 	})
 
 	t.Run("POST", func(t *testing.T) {
@@ -0,0 +1,100 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/cache"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/validator"
+	"github.com/kxplxn/goteam/test"
+)
+
+// TestTeamCache tests that the cached team is invalidated on sprint writes, so
+// that the board and team writes that follow them are not served a stale team
+// and do not conflict with the sprint write.
+func TestTeamCache(t *testing.T) {
+	ctx := context.Background()
+	log := log.New()
+	teamID := "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc"
+	boardID := "ca47fbec-269e-4ef4-a74a-bcfbcd599fd5"
+
+	teamCache := cache.NewRetriever(
+		teamtbl.NewRetriever(test.DB()), time.Minute, 10, teamtbl.Team.Clone,
+	)
+	teamUpdater := cache.NewUpdater(
+		teamtbl.NewUpdater(test.DB()),
+		teamCache,
+		func(t teamtbl.Team) string { return t.ID },
+	)
+	sprintInserter := cache.NewInserterDualKey(
+		teamtbl.NewSprintInserter(test.DB()), teamCache,
+	)
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPatch: api.Authed(
+			cookie.NewAuthDecoder(
+				cookie.NewKeys(test.JWTKey), clock.System{},
+			),
+			log,
+			boardapi.NewPatchHandler(
+				validator.ID,
+				validator.BoardName,
+				validator.BoardDesc,
+				boardapi.ValidateColumns,
+				cache.NewUpdaterDualKey(
+					teamtbl.NewBoardUpdater(test.DB()), teamCache,
+				),
+				log,
+			),
+		),
+	})
+
+	// cache the team before the sprint is written
+	_, err := teamCache.Retrieve(ctx, teamID)
+	assert.Nil(t.Fatal, err)
+
+	err = sprintInserter.Insert(ctx, teamID, teamtbl.Sprint{
+		ID:        "5e0a1a45-b3c7-4bcd-9d3e-1b1a4f6c7a02",
+		BoardID:   boardID,
+		Name:      "Sprint 1",
+		StartDate: "2024-01-01",
+		EndDate:   "2024-01-14",
+	})
+	assert.Nil(t.Fatal, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(
+		http.MethodPatch, "/team/board", strings.NewReader(`{
+            "id": "`+boardID+`",
+            "name": "Team 4 Board 1 Renamed"
+        }`),
+	)
+	test.AddAuthCookie(test.T4AdminToken)(r)
+
+	sut.ServeHTTP(w, r)
+
+	assert.Equal(t.Error, w.Result().StatusCode, http.StatusOK)
+
+	// the cached team must reflect both writes and hold the latest version
+	team, err := teamCache.Retrieve(ctx, teamID)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(team.Sprints), 1)
+	board, ok := team.Board(boardID)
+	assert.True(t.Fatal, ok)
+	assert.Equal(t.Error, board.Name, "Team 4 Board 1 Renamed")
+
+	err = teamUpdater.Update(ctx, team)
+	assert.Nil(t.Error, err)
+}
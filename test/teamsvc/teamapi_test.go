@@ -16,22 +16,35 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/kxplxn/goteam/internal/teamsvc/teamapi"
+	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/memdb"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTeamAPI(t *testing.T) {
-	handler := teamapi.NewGetHandler(
-		cookie.NewAuthDecoder(test.JWTKey),
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewKeys(test.JWTKey), clock.System{},
+	)
+	log := log.New()
+	handler := api.Authed(authDecoder, log, teamapi.NewGetHandler(
 		teamtbl.NewRetriever(test.DB()),
 		teamtbl.NewInserter(test.DB()),
 		teamtbl.NewUpdater(test.DB()),
-		cookie.NewInviteEncoder(test.JWTKey, 1*time.Hour),
-		log.New(),
-	)
+		memdb.NewUserRetriever(memdb.NewStore()),
+		cookie.NewInviteEncoder(
+			cookie.NewKeys(test.JWTKey),
+			1*time.Hour,
+			cookie.DefaultConfig(),
+			clock.System{},
+		),
+		clock.System{},
+		log,
+	))
 
 	t.Run("GET", func(t *testing.T) {
 		for _, c := range []struct {
@@ -119,7 +132,7 @@ func TestTeamAPI(t *testing.T) {
 					wantResp := teamapi.GetResp{
 						ID:      "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
 						Members: []string{"team1Admin", "team1Member"},
-						Boards: []teamtbl.Board{
+						Boards: []teamapi.GetBoard{
 							{
 								ID:      "91536664-9749-4dbb-a470-6e52aa353ae4",
 								Name:    "Team 1 Board 1",
@@ -197,7 +210,7 @@ func TestTeamAPI(t *testing.T) {
 					wantResp := teamapi.GetResp{
 						ID:      "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
 						Members: []string{"team1Admin", "team1Member"},
-						Boards: []teamtbl.Board{
+						Boards: []teamapi.GetBoard{
 							{
 								ID:      "91536664-9749-4dbb-a470-6e52aa353ae4",
 								Name:    "Team 1 Board 1",
@@ -263,7 +276,7 @@ func TestTeamAPI(t *testing.T) {
 				c.authFunc(r)
 				w := httptest.NewRecorder()
 
-				handler.Handle(w, r, "")
+				handler.Handle(w, r, cookie.Auth{})
 
 				resp := w.Result()
 				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
@@ -7,24 +7,33 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
-	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
 	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pwdhash"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestLoginAPI(t *testing.T) {
 	sut := loginapi.NewPostHandler(
 		loginapi.NewValidator(),
+		captcha.Disabled{},
 		usertbl.NewRetriever(test.DB()),
-		loginapi.NewPasswordComparator(),
-		cookie.NewAuthEncoder(test.JWTKey, 1*time.Hour),
+		usertbl.NewRetrieverByEmail(test.DB()),
+		pwdhash.NewHasher(pwdhash.DefaultParams()),
+		pwdhash.NewHasher(pwdhash.DefaultParams()),
+		cookie.NewAuthEncoder(
+			cookie.NewKeys(test.JWTKey), cookie.DefaultConfig(), clock.System{},
+		),
+		usertbl.NewUpdater(test.DB()),
+		clock.System{},
 		log.New(),
 	)
 
@@ -118,7 +127,7 @@ func TestLoginAPI(t *testing.T) {
                 }`),
 			)
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, cookie.Auth{})
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
@@ -7,16 +7,22 @@ import (
 	"log"
 	"testing"
 
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-
 	"github.com/kxplxn/goteam/test"
+	"github.com/kxplxn/goteam/test/fixture"
 )
 
 // tableName is the name of the user table used in the integration tests.
 var tableName = "goteam-test-user"
 
-// TestMain sets up the test table in DynamoDB and runs the tests.
+// TestMain starts DynamoDB, sets up the test table in it, and runs the tests.
 func TestMain(m *testing.M) {
+	tearDownDB, err := test.StartDB()
+	defer tearDownDB()
+	if err != nil {
+		log.Println("start db failed:", err)
+		return
+	}
+
 	fmt.Println("setting up user table")
 	tearDownTables, err := test.SetUpTestTable(
 		"USER_TABLE_NAME", tableName, writeReqs, "Username", "",
@@ -31,103 +37,20 @@ func TestMain(m *testing.M) {
 }
 
 // writeReqs are the requests sent to the test table to initialise it for tests.
-var writeReqs = []types.WriteRequest{
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team1Admin"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: true,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: false,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: true,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team2Member"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: false,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team3Admin"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: true,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "74c80ae5-64f3-4298-a8ff-48f8f920c7d4",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team4Admin"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: true,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
-		},
-	}}},
-	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
-		"Password": &types.AttributeValueMemberB{
-			Value: []byte(
-				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
-			),
-		},
-		"IsAdmin": &types.AttributeValueMemberBOOL{
-			Value: false,
-		},
-		"TeamID": &types.AttributeValueMemberS{
-			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
-		},
-	}}},
-}
+var writeReqs = fixture.MustWriteReqs(
+	fixture.User("team1Admin").Admin().Team(team1ID).Build(),
+	fixture.User("team1Member").Team(team1ID).Build(),
+	fixture.User("team2Admin").Admin().Team(team2ID).Build(),
+	fixture.User("team2Member").Team(team2ID).Build(),
+	fixture.User("team3Admin").Admin().Team(team3ID).Build(),
+	fixture.User("team4Admin").Admin().Team(team4ID).Build(),
+	fixture.User("team4Member").Team(team4ID).Build(),
+)
+
+// IDs of the teams the users in the test table are members of.
+const (
+	team1ID = "afeadc4a-68b0-4c33-9e83-4648d20ff26a"
+	team2ID = "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b"
+	team3ID = "74c80ae5-64f3-4298-a8ff-48f8f920c7d4"
+	team4ID = "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc"
+)
@@ -17,11 +17,16 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/kxplxn/goteam/internal/usersvc/captcha"
 	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/clock"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pwdhash"
+	"github.com/kxplxn/goteam/pkg/quota"
 	"github.com/kxplxn/goteam/test"
 )
 
@@ -29,12 +34,21 @@ func TestRegisterAPI(t *testing.T) {
 	sut := registerapi.NewPostHandler(
 		registerapi.NewUserValidator(
 			registerapi.NewUsernameValidator(),
-			registerapi.NewPasswordValidator(),
+			registerapi.NewPasswordValidator(registerapi.DefaultMinPwdScore),
 		),
-		cookie.NewInviteDecoder(test.JWTKey),
-		registerapi.NewPasswordHasher(),
+		captcha.Disabled{},
+		cookie.NewInviteDecoder(cookie.NewKeys(test.JWTKey), clock.System{}),
+		teamtbl.NewRetriever(test.DB()),
+		teamtbl.NewUpdater(test.DB()),
+		quota.Default(),
+		pwdhash.NewHasher(pwdhash.DefaultParams()),
+		usertbl.NewRetriever(test.DB()),
 		usertbl.NewInserter(test.DB()),
-		cookie.NewAuthEncoder(test.JWTKey, 1*time.Hour),
+		cookie.NewAuthEncoder(
+			cookie.NewKeys(test.JWTKey), cookie.DefaultConfig(), clock.System{},
+		),
+		usertbl.NewUpdater(test.DB()),
+		clock.System{},
 		log.New(),
 	)
 
@@ -138,7 +152,7 @@ func TestRegisterAPI(t *testing.T) {
 		{
 			name:           "UsnTaken",
 			username:       "team1Member",
-			password:       "Myp4ssw0rd!",
+			password:       "Gr8!Koala-Mug",
 			inviteToken:    "",
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc: assertOnValidationErrs(
@@ -148,7 +162,7 @@ func TestRegisterAPI(t *testing.T) {
 		{
 			name:           "InviteInvalid",
 			username:       "bob321",
-			password:       "Myp4ssw0rd!",
+			password:       "Gr8!Koala-Mug",
 			inviteToken:    "10249812049182",
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     assertOnResErr("Invalid invite token."),
@@ -156,7 +170,7 @@ func TestRegisterAPI(t *testing.T) {
 		{
 			name:           "OK",
 			username:       "bob321",
-			password:       "Myp4ssw0rd!",
+			password:       "Gr8!Koala-Mug",
 			inviteToken:    "",
 			wantStatusCode: http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ string) {
@@ -180,7 +194,7 @@ func TestRegisterAPI(t *testing.T) {
 					t.Fatal(err)
 				}
 				if err = bcrypt.CompareHashAndPassword(
-					user.Password, []byte("Myp4ssw0rd!"),
+					user.Password, []byte("Gr8!Koala-Mug"),
 				); err != nil {
 					t.Error(err)
 				}
@@ -226,7 +240,7 @@ func TestRegisterAPI(t *testing.T) {
                 }`),
 			)
 
-			sut.Handle(w, r, "")
+			sut.Handle(w, r, cookie.Auth{})
 
 			resp := w.Result()
 			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
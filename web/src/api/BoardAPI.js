@@ -1,16 +1,16 @@
 import axios from 'axios';
 
-const apiUrl = process.env.REACT_APP_TEAM_SERVICE_URL + "/board"
+const apiUrl = process.env.REACT_APP_TEAM_SERVICE_URL + "/boards"
 
 const BoardAPI = {
   post: (boardData) => axios.post(apiUrl, boardData, { withCredentials: true }),
 
   delete: (boardId) => axios.delete(
-    apiUrl + "?id=" + boardId, { withCredentials: true },
+    apiUrl + "/" + boardId, { withCredentials: true },
   ),
 
   patch: (boardId, boardData) => axios.patch(
-    apiUrl + "?id=" + boardId, boardData, { withCredentials: true },
+    apiUrl + "/" + boardId, boardData, { withCredentials: true },
   ),
 };
 
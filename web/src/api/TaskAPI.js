@@ -1,18 +1,20 @@
 import axios from 'axios';
 
-const apiUrl = process.env.REACT_APP_TASK_SERVICE_URL + "/task"
+const serviceUrl = process.env.REACT_APP_TASK_SERVICE_URL
 
 const TaskAPI = {
   post: (task) => axios.post(
-    apiUrl, task, { withCredentials: true },
+    serviceUrl + "/boards/" + task.boardID + "/tasks",
+    task,
+    { withCredentials: true },
   ),
 
   patch: (task) => axios.patch(
-    apiUrl, task, { withCredentials: true },
+    serviceUrl + "/tasks/" + task.id, task, { withCredentials: true },
   ),
 
   delete: (taskId) => axios.delete(
-    apiUrl + "?id=" + taskId, { withCredentials: true },
+    serviceUrl + "/tasks/" + taskId, { withCredentials: true },
   ),
 };
 
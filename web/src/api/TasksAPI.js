@@ -1,13 +1,15 @@
 import axios from 'axios';
 
-const apiUrl = process.env.REACT_APP_TASK_SERVICE_URL + "/tasks"
+const serviceUrl = process.env.REACT_APP_TASK_SERVICE_URL
 
 const TasksAPI = {
   get: (boardID) => axios.get(
-    apiUrl + "?boardID=" + boardID, { withCredentials: true },
+    serviceUrl + "/boards/" + boardID + "/tasks", { withCredentials: true },
   ),
 
-  patch: (data) => axios.patch(apiUrl, data, { withCredentials: true }),
+  patch: (data) => axios.patch(
+    serviceUrl + "/tasks", data, { withCredentials: true },
+  ),
 };
 
 export default TasksAPI;
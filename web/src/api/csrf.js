@@ -0,0 +1,20 @@
+import axios from 'axios';
+
+// The CSRF token is issued in the X-CSRF-Token header of logins and sent back
+// in it on every other request made with it, since its cookie cannot be read
+// from this origin. It is sent in the same header with every mutating request.
+const header = "x-csrf-token";
+let token = "";
+
+axios.interceptors.response.use((res) => {
+  if (res.headers[header]) { token = res.headers[header]; }
+  return res;
+});
+
+axios.interceptors.request.use((config) => {
+  const method = (config.method || "get").toLowerCase();
+  if (token && !["get", "head", "options"].includes(method)) {
+    config.headers = { ...config.headers, "X-CSRF-Token": token };
+  }
+  return config;
+});
@@ -1,6 +1,7 @@
 import React from 'react';
 import ReactDOM from 'react-dom';
 import App from './App';
+import './api/csrf';
 
 document.addEventListener('contextmenu', (e) => e.preventDefault());
 